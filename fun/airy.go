@@ -0,0 +1,65 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// AiryAi returns the Airy function Ai(x)
+func AiryAi(x float64) float64 {
+	ai, _, _, _ := Airy(x)
+	return ai
+}
+
+// AiryBi returns the Airy function Bi(x)
+func AiryBi(x float64) float64 {
+	_, bi, _, _ := Airy(x)
+	return bi
+}
+
+// Airy computes the Airy functions Ai(x) and Bi(x) and their derivatives; i.e. the solutions of
+//
+//   y'' - x y = 0
+//
+//   The functions are computed from the Bessel functions of order 1/3 and 2/3 with z = (2/3) |x|^(3/2):
+//
+//     x > 0:  Ai(x) = (1/π) √(x/3) K⅓(z)      Bi(x) = √(x/3) [2/√3 I⅓(z) + K⅓(z)/π]
+//     x < 0:  Ai(x) = (√|x|/2) [J⅓(z) - Y⅓(z)/√3]      Bi(x) = -(√|x|/2) [J⅓(z)/√3 + Y⅓(z)]
+//
+//   References:
+//   [1] Abramowitz M, Stegun IA (1972) Handbook of Mathematical Functions with Formulas, Graphs,
+//       and Mathematical Tables. U.S. Department of Commerce, NIST
+//   [2] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//       Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func Airy(x float64) (ai, bi, aip, bip float64) {
+	const onovrt = 0.577350269189625764509148780502 // 1/√3
+	absx := math.Abs(x)
+	rootx := math.Sqrt(absx)
+	z := 2.0 * absx * rootx / 3.0
+	switch {
+	case x > 0:
+		i, k, _, _ := ModBesselIK(1.0/3.0, z)
+		ai = onovrt * rootx * k / math.Pi
+		bi = rootx * (k/math.Pi + 2.0*onovrt*i)
+		i, k, _, _ = ModBesselIK(2.0/3.0, z)
+		aip = -x * onovrt * k / math.Pi
+		bip = x * (k/math.Pi + 2.0*onovrt*i)
+	case x < 0:
+		j, y, _, _ := BesselJY(1.0/3.0, z)
+		ai = 0.5 * rootx * (j - onovrt*y)
+		bi = -0.5 * rootx * (y + onovrt*j)
+		j, y, _, _ = BesselJY(2.0/3.0, z)
+		aip = 0.5 * absx * (onovrt*y + j)
+		bip = 0.5 * absx * (onovrt*j - y)
+	case x == 0:
+		ai = 0.355028053887817239260063186004 // 1/(3^(2/3) Γ(2/3))
+		bi = ai / onovrt
+		aip = -0.258819403792806798405183560189 // -1/(3^(1/3) Γ(1/3))
+		bip = -aip / onovrt
+	default:
+		nan := math.NaN()
+		return nan, nan, nan, nan
+	}
+	return
+}
@@ -0,0 +1,490 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/utl"
+)
+
+// constants for Bessel functions of real order
+const (
+	besMaxIt = 10000
+	besEps   = 2.220446049250313e-16                           // machine epsilon
+	besFpMin = 2.2250738585072014e-308 / 2.220446049250313e-16 // smallest normal number / eps
+	besXmin  = 2.0
+)
+
+// BesselJ returns the Bessel function of the first kind Jν(x) of real order ν and x ≥ 0
+//  NOTE: for negative orders: J₋ν = cos(νπ) Jν - sin(νπ) Yν
+func BesselJ(ν, x float64) float64 {
+	j, _, _, _ := BesselJY(ν, x)
+	return j
+}
+
+// BesselY returns the Bessel function of the second kind Yν(x) of real order ν and x ≥ 0
+//  NOTE: for negative orders: Y₋ν = sin(νπ) Jν + cos(νπ) Yν
+func BesselY(ν, x float64) float64 {
+	_, y, _, _ := BesselJY(ν, x)
+	return y
+}
+
+// BesselJY computes the Bessel functions of first and second kinds Jν(x) and Yν(x) of real order ν
+// and their derivatives with respect to x
+//  Special cases:
+//    x < 0 ⇒ NaN
+//    x = 0 ⇒ J0(0) = 1, Jν(0) = 0 (ν > 0), Yν(0) = -Inf and the derivatives are NaN
+//
+//  The functions are computed by Steed's method (continued fractions) for x ≥ 2 and by Temme's
+//  series for x < 2 [1,2].
+//
+//  References:
+//  [1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//      Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+//  [2] Temme NM (1976) On the numerical evaluation of the ordinary Bessel function of the second
+//      kind. Journal of Computational Physics, 21:343-350
+func BesselJY(ν, x float64) (j, y, jp, yp float64) {
+
+	// special cases
+	if x < 0 || math.IsNaN(x) || math.IsNaN(ν) {
+		nan := math.NaN()
+		return nan, nan, nan, nan
+	}
+	if ν < 0 {
+		j, y, jp, yp = BesselJY(-ν, x)
+		s, c := math.Sincos(-ν * math.Pi)
+		return c*j - s*y, s*j + c*y, c*jp - s*yp, s*jp + c*yp
+	}
+	if x == 0 {
+		nan := math.NaN()
+		if ν == 0 {
+			return 1, math.Inf(-1), nan, nan
+		}
+		return 0, math.Inf(-1), nan, nan
+	}
+
+	// number of downward recurrences
+	var nl int
+	if x < besXmin {
+		nl = int(ν + 0.5)
+	} else {
+		nl = utl.Imax(0, int(ν-x+1.5))
+	}
+	xmu := ν - float64(nl)
+	xmu2 := xmu * xmu
+	xi := 1.0 / x
+	xi2 := 2.0 * xi
+	w := xi2 / math.Pi
+
+	// CF1 by modified Lentz's method
+	isign := 1.0
+	h := ν * xi
+	if h < besFpMin {
+		h = besFpMin
+	}
+	b := xi2 * ν
+	d := 0.0
+	c := h
+	var i int
+	for i = 0; i < besMaxIt; i++ {
+		b += xi2
+		d = b - d
+		if math.Abs(d) < besFpMin {
+			d = besFpMin
+		}
+		c = b - 1.0/c
+		if math.Abs(c) < besFpMin {
+			c = besFpMin
+		}
+		d = 1.0 / d
+		del := c * d
+		h = del * h
+		if d < 0 {
+			isign = -isign
+		}
+		if math.Abs(del-1.0) <= besEps {
+			break
+		}
+	}
+	if i >= besMaxIt {
+		chk.Panic("x = %g is too large; try asymptotic expansion\n", x)
+	}
+
+	// downward recurrence
+	rjl := isign * besFpMin
+	rjpl := h * rjl
+	rjl1 := rjl
+	rjp1 := rjpl
+	fact := ν * xi
+	for l := nl - 1; l >= 0; l-- {
+		rjtemp := fact*rjl + rjpl
+		fact -= xi
+		rjpl = fact*rjtemp - rjl
+		rjl = rjtemp
+	}
+	if rjl == 0 {
+		rjl = besEps
+	}
+	f := rjpl / rjl
+
+	// Jμ, Yμ and Yμ+1 with |μ| ≤ 1/2
+	var rjmu, rymu, rymup, ry1 float64
+	if x < besXmin {
+		x2 := 0.5 * x
+		pimu := math.Pi * xmu
+		fact = 1.0
+		if math.Abs(pimu) >= besEps {
+			fact = pimu / math.Sin(pimu)
+		}
+		d = -math.Log(x2)
+		e := xmu * d
+		fact2 := 1.0
+		if math.Abs(e) >= besEps {
+			fact2 = math.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := beschb(xmu)
+		ff := 2.0 / math.Pi * fact * (gam1*math.Cosh(e) + gam2*fact2*d)
+		e = math.Exp(e)
+		p := e / (gampl * math.Pi)
+		q := 1.0 / (e * math.Pi * gammi)
+		pimu2 := 0.5 * pimu
+		fact3 := 1.0
+		if math.Abs(pimu2) >= besEps {
+			fact3 = math.Sin(pimu2) / pimu2
+		}
+		r := math.Pi * pimu2 * fact3 * fact3
+		c = 1.0
+		d = -x2 * x2
+		sum := ff + r*q
+		sum1 := p
+		for i = 1; i <= besMaxIt; i++ {
+			fi := float64(i)
+			ff = (fi*ff + p + q) / (fi*fi - xmu2)
+			c *= d / fi
+			p /= fi - xmu
+			q /= fi + xmu
+			del := c * (ff + r*q)
+			sum += del
+			del1 := c*p - fi*del
+			sum1 += del1
+			if math.Abs(del) < (1.0+math.Abs(sum))*besEps {
+				break
+			}
+		}
+		if i > besMaxIt {
+			chk.Panic("Bessel series failed to converge\n")
+		}
+		rymu = -sum
+		ry1 = -sum1 * xi2
+		rymup = xmu*xi*rymu - ry1
+		rjmu = w / (rymup - f*rymu)
+	} else {
+		a := 0.25 - xmu2
+		p := -0.5 * xi
+		q := 1.0
+		br := 2.0 * x
+		bi := 2.0
+		fact = a * xi / (p*p + q*q)
+		cr := br + q*fact
+		ci := bi + p*fact
+		den := br*br + bi*bi
+		dr := br / den
+		di := -bi / den
+		dlr := cr*dr - ci*di
+		dli := cr*di + ci*dr
+		temp := p*dlr - q*dli
+		q = p*dli + q*dlr
+		p = temp
+		for i = 1; i < besMaxIt; i++ {
+			a += float64(2 * i)
+			bi += 2.0
+			dr = a*dr + br
+			di = a*di + bi
+			if math.Abs(dr)+math.Abs(di) < besFpMin {
+				dr = besFpMin
+			}
+			fact = a / (cr*cr + ci*ci)
+			cr = br + cr*fact
+			ci = bi - ci*fact
+			if math.Abs(cr)+math.Abs(ci) < besFpMin {
+				cr = besFpMin
+			}
+			den = dr*dr + di*di
+			dr /= den
+			di /= -den
+			dlr = cr*dr - ci*di
+			dli = cr*di + ci*dr
+			temp = p*dlr - q*dli
+			q = p*dli + q*dlr
+			p = temp
+			if math.Abs(dlr-1.0)+math.Abs(dli) <= besEps {
+				break
+			}
+		}
+		if i >= besMaxIt {
+			chk.Panic("Bessel continued fraction (CF2) failed to converge\n")
+		}
+		gam := (p - f) / q
+		rjmu = math.Copysign(math.Sqrt(w/((p-f)*gam+q)), rjl)
+		rymu = rjmu * gam
+		rymup = rymu * (p + q/gam)
+		ry1 = xmu*xi*rymu - rymup
+	}
+
+	// scale and upward recurrence for Y
+	fact = rjmu / rjl
+	j = rjl1 * fact
+	jp = rjp1 * fact
+	for i = 1; i <= nl; i++ {
+		rytemp := (xmu+float64(i))*xi2*ry1 - rymu
+		rymu = ry1
+		ry1 = rytemp
+	}
+	y = rymu
+	yp = ν*xi*rymu - ry1
+	return
+}
+
+// ModBesselI returns the modified Bessel function of the first kind Iν(x) of real order ν ≥ 0 and
+// x ≥ 0 (see ModBesselIn for integer orders and negative x)
+func ModBesselI(ν, x float64) float64 {
+	i, _, _, _ := ModBesselIK(ν, x)
+	return i
+}
+
+// ModBesselK returns the modified Bessel function of the second kind Kν(x) of real order ν and
+// x ≥ 0. NOTE: K₋ν = Kν
+func ModBesselK(ν, x float64) float64 {
+	_, k, _, _ := ModBesselIK(math.Abs(ν), x)
+	return k
+}
+
+// ModBesselIK computes the modified Bessel functions Iν(x) and Kν(x) of real order ν ≥ 0 and their
+// derivatives with respect to x
+//  Special cases:
+//    x < 0 or ν < 0 ⇒ NaN
+//    x = 0 ⇒ I0(0) = 1, Iν(0) = 0 (ν > 0), Kν(0) = +Inf and the derivatives are NaN
+//
+//  The functions are computed by Steed's method for x ≥ 2 and by Temme's series for x < 2.
+//  See BesselJY for references
+func ModBesselIK(ν, x float64) (i, k, ip, kp float64) {
+
+	// special cases
+	if x < 0 || ν < 0 || math.IsNaN(x) || math.IsNaN(ν) {
+		nan := math.NaN()
+		return nan, nan, nan, nan
+	}
+	if x == 0 {
+		nan := math.NaN()
+		if ν == 0 {
+			return 1, math.Inf(1), nan, nan
+		}
+		return 0, math.Inf(1), nan, nan
+	}
+
+	// CF1 by modified Lentz's method
+	nl := int(ν + 0.5)
+	xmu := ν - float64(nl)
+	xmu2 := xmu * xmu
+	xi := 1.0 / x
+	xi2 := 2.0 * xi
+	h := ν * xi
+	if h < besFpMin {
+		h = besFpMin
+	}
+	b := xi2 * ν
+	d := 0.0
+	c := h
+	var it int
+	for it = 0; it < besMaxIt; it++ {
+		b += xi2
+		d = 1.0 / (b + d)
+		c = b + 1.0/c
+		del := c * d
+		h = del * h
+		if math.Abs(del-1.0) <= besEps {
+			break
+		}
+	}
+	if it >= besMaxIt {
+		chk.Panic("x = %g is too large; try asymptotic expansion\n", x)
+	}
+
+	// downward recurrence
+	ril := besFpMin
+	ripl := h * ril
+	ril1 := ril
+	rip1 := ripl
+	fact := ν * xi
+	for l := nl - 1; l >= 0; l-- {
+		ritemp := fact*ril + ripl
+		fact -= xi
+		ripl = fact*ritemp + ril
+		ril = ritemp
+	}
+	f := ripl / ril
+
+	// Kμ and Kμ+1 with |μ| ≤ 1/2
+	var rkmu, rk1 float64
+	if x < besXmin {
+		x2 := 0.5 * x
+		pimu := math.Pi * xmu
+		fact = 1.0
+		if math.Abs(pimu) >= besEps {
+			fact = pimu / math.Sin(pimu)
+		}
+		d = -math.Log(x2)
+		e := xmu * d
+		fact2 := 1.0
+		if math.Abs(e) >= besEps {
+			fact2 = math.Sinh(e) / e
+		}
+		gam1, gam2, gampl, gammi := beschb(xmu)
+		ff := fact * (gam1*math.Cosh(e) + gam2*fact2*d)
+		sum := ff
+		e = math.Exp(e)
+		p := 0.5 * e / gampl
+		q := 0.5 / (e * gammi)
+		c = 1.0
+		d = x2 * x2
+		sum1 := p
+		for it = 1; it <= besMaxIt; it++ {
+			fi := float64(it)
+			ff = (fi*ff + p + q) / (fi*fi - xmu2)
+			c *= d / fi
+			p /= fi - xmu
+			q /= fi + xmu
+			del := c * ff
+			sum += del
+			del1 := c * (p - fi*ff)
+			sum1 += del1
+			if math.Abs(del) < math.Abs(sum)*besEps {
+				break
+			}
+		}
+		if it > besMaxIt {
+			chk.Panic("modified Bessel series failed to converge\n")
+		}
+		rkmu = sum
+		rk1 = sum1 * xi2
+	} else {
+		b = 2.0 * (1.0 + x)
+		d = 1.0 / b
+		h = d
+		delh := d
+		q1 := 0.0
+		q2 := 1.0
+		a1 := 0.25 - xmu2
+		q := a1
+		c = a1
+		a := -a1
+		s := 1.0 + q*delh
+		for it = 1; it < besMaxIt; it++ {
+			fi := float64(it)
+			a -= 2 * fi
+			c = -a * c / (fi + 1.0)
+			qnew := (q1 - b*q2) / a
+			q1 = q2
+			q2 = qnew
+			q += c * qnew
+			b += 2.0
+			d = 1.0 / (b + a*d)
+			delh = (b*d - 1.0) * delh
+			h += delh
+			dels := q * delh
+			s += dels
+			if math.Abs(dels/s) <= besEps {
+				break
+			}
+		}
+		if it >= besMaxIt {
+			chk.Panic("modified Bessel continued fraction (CF2) failed to converge\n")
+		}
+		h = a1 * h
+		rkmu = math.Sqrt(math.Pi/(2.0*x)) * math.Exp(-x) / s
+		rk1 = rkmu * (xmu + x + 0.5 - h) * xi
+	}
+
+	// scale and upward recurrence for K
+	rkmup := xmu*xi*rkmu - rk1
+	rimu := xi / (f*rkmu - rkmup)
+	i = (rimu * ril1) / ril
+	ip = (rimu * rip1) / ril
+	for it = 1; it <= nl; it++ {
+		rktemp := (xmu+float64(it))*xi2*rk1 + rkmu
+		rkmu = rk1
+		rk1 = rktemp
+	}
+	k = rkmu
+	kp = ν*xi*rkmu - rk1
+	return
+}
+
+// SphBesselJ returns the spherical Bessel function of the first kind jn(x) = √(π/(2x)) Jₙ₊½(x)
+func SphBesselJ(n int, x float64) float64 {
+	if n < 0 {
+		chk.Panic("order of spherical Bessel function must be non-negative. n = %d is invalid\n", n)
+	}
+	if x == 0 {
+		if n == 0 {
+			return 1
+		}
+		return 0
+	}
+	if n == 0 && math.Abs(x) > 1e-3 {
+		return math.Sin(x) / x
+	}
+	if x < 0 {
+		return NegOnePowN(n) * SphBesselJ(n, -x)
+	}
+	return math.Sqrt(math.Pi/(2.0*x)) * BesselJ(float64(n)+0.5, x)
+}
+
+// SphBesselY returns the spherical Bessel function of the second kind yn(x) = √(π/(2x)) Yₙ₊½(x)
+func SphBesselY(n int, x float64) float64 {
+	if n < 0 {
+		chk.Panic("order of spherical Bessel function must be non-negative. n = %d is invalid\n", n)
+	}
+	if x == 0 {
+		return math.Inf(-1)
+	}
+	if x < 0 {
+		return NegOnePowN(n+1) * SphBesselY(n, -x)
+	}
+	return math.Sqrt(math.Pi/(2.0*x)) * BesselY(float64(n)+0.5, x)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// beschb evaluates Γ₁ and Γ₂ by Chebyshev expansion for |μ| ≤ 1/2. Also returns 1/Γ(1+μ) and
+// 1/Γ(1-μ)
+func beschb(μ float64) (gam1, gam2, gampl, gammi float64) {
+	xx := 8.0*μ*μ - 1.0
+	gam1 = chebev(besC1, xx)
+	gam2 = chebev(besC2, xx)
+	gampl = gam2 - μ*gam1
+	gammi = gam2 + μ*gam1
+	return
+}
+
+// chebev evaluates a Chebyshev series Σ' c[k] Tₖ(x) (first term halved) for x in [-1,1]
+func chebev(c []float64, x float64) float64 {
+	d, dd := 0.0, 0.0
+	y2 := 2.0 * x
+	for j := len(c) - 1; j > 0; j-- {
+		sv := d
+		d = y2*d - dd + c[j]
+		dd = sv
+	}
+	return x*d - dd + 0.5*c[0]
+}
+
+var besC1 = []float64{-1.142022680371168e0, 6.5165112670737e-3, 3.087090173086e-4,
+	-3.4706269649e-6, 6.9437664e-9, 3.67795e-11, -1.356e-13}
+
+var besC2 = []float64{1.843740587300905e0, -7.68528408447867e-2, 1.2719271366546e-3,
+	-4.9717367042e-6, -3.31261198e-8, 2.423096e-10, -1.702e-13, -1.49e-15}
@@ -0,0 +1,495 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/fft"
+	"github.com/cpmech/gosl/la"
+)
+
+// Chebfun implements adaptive (piecewise) Chebyshev representations of functions; i.e. functions
+// are approximated to machine precision by Chebyshev series on each piece [Ends[i], Ends[i+1]]
+//
+//            N-1
+//     f(x) =  Σ  Coefs[i][k] ⋅ T_k(t)     with  t = (2 x - Ends[i] - Ends[i+1]) / (Ends[i+1] - Ends[i])
+//            k=0
+//
+//  The function is sampled at the Chebyshev points of the first kind (Gauss-Chebyshev) with
+//  increasing number of points (17, 33, 65, ...) until the coefficients decay to the machine
+//  precision level. The number of coefficients is then chosen by the "standard chop" algorithm [2].
+//  Since the endpoints are never sampled, the pieces adjacent to discontinuities converge.
+//
+//  If splitting is enabled, pieces that cannot be resolved with 129 points are recursively bisected
+//  and adjacent pieces that can be represented together are merged afterwards. Thus,
+//  discontinuities in the function or its derivatives become breakpoints (to within machine
+//  precision).
+//
+//  References:
+//  [1] Trefethen LN (2013) Approximation Theory and Approximation Practice. SIAM. 305p
+//  [2] Aurentz JL, Trefethen LN (2017) Chopping a Chebyshev series, ACM Transactions on
+//      Mathematical Software, 43(4):33
+//  [3] Driscoll TA, Hale N, Trefethen LN (2014) Chebfun Guide. Pafnuty Publications, Oxford
+type Chebfun struct {
+	Ends  la.Vector   // breakpoints: Ends[0] < Ends[1] < ... < Ends[npieces]
+	Coefs []la.Vector // Chebyshev coefficients of each piece
+	split bool        // splitting is enabled (also for results of operations)
+}
+
+// constants for Chebfun
+const (
+	cfMinN     = 17          // initial number of points
+	cfMaxN     = 65537       // maximum number of points without splitting
+	cfMaxNsplt = 129         // maximum number of points of each piece when splitting
+	cfMaxNroot = 50          // maximum degree for the colleague matrix; larger pieces are subdivided
+	cfTol      = 0x1p-52     // tolerance for chopping
+	cfMinLen   = 1e-14       // minimum length of pieces (relative to the domain length)
+	cfRootSplt = -0.00484983 // point for subdividing the interval in Roots (arbitrary; not 0)
+)
+
+// NewChebfun computes a Chebfun representation of f
+//
+//  Input:
+//   f     -- function
+//   ends  -- breakpoints; e.g. {a, b}. Interior breakpoints are used as initial pieces
+//   split -- enable automatic splitting; e.g. for functions with discontinuities
+//
+//  NOTE: without splitting, the function must be resolved with less than 65537 points in each
+//        piece; otherwise a panic occurs.
+func NewChebfun(f Ss, ends []float64, split bool) (o *Chebfun) {
+	if len(ends) < 2 {
+		chk.Panic("at least two breakpoints are required\n")
+	}
+	for i := 1; i < len(ends); i++ {
+		if ends[i] <= ends[i-1] {
+			chk.Panic("breakpoints must be strictly increasing. ends[%d]=%g ≤ ends[%d]=%g\n", i, ends[i], i-1, ends[i-1])
+		}
+	}
+	o = &Chebfun{split: split}
+	minLen := cfMinLen * (ends[len(ends)-1] - ends[0])
+	vscale := 0.0
+	for i := 0; i < len(ends)-1; i++ {
+		for _, v := range chebSample(f, ends[i], ends[i+1], cfMaxNsplt) {
+			vscale = math.Max(vscale, math.Abs(v))
+		}
+	}
+	o.Ends = la.Vector{ends[0]}
+	for i := 0; i < len(ends)-1; i++ {
+		if split {
+			o.splitPiece(f, ends[i], ends[i+1], minLen, vscale)
+		} else {
+			c, ok := chebCoefsAdapt(f, ends[i], ends[i+1], cfMaxN, vscale)
+			if !ok {
+				chk.Panic("function could not be resolved in [%g, %g] with %d points. Use splitting\n", ends[i], ends[i+1], cfMaxN)
+			}
+			o.Ends = append(o.Ends, ends[i+1])
+			o.Coefs = append(o.Coefs, c)
+		}
+	}
+	if split {
+		o.merge(f, vscale)
+	}
+	return
+}
+
+// NumPieces returns the number of pieces
+func (o *Chebfun) NumPieces() int {
+	return len(o.Coefs)
+}
+
+// F evaluates the function at x (Clenshaw's algorithm)
+//  NOTE: x outside [Ends[0], Ends[npieces]] is extrapolated using the first or last pieces
+func (o *Chebfun) F(x float64) float64 {
+	i := sort.SearchFloat64s(o.Ends[1:len(o.Ends)-1], x)
+	if i < len(o.Coefs)-1 && x == o.Ends[i+1] {
+		i++ // value from the right at breakpoints
+	}
+	return chebClenshaw(o.Coefs[i], o.mapToRef(i, x))
+}
+
+// Add returns the Chebfun of f + g
+//  NOTE: f and g must have the same domain
+func (o *Chebfun) Add(g *Chebfun) *Chebfun {
+	return o.binary(g, func(x float64) float64 { return o.F(x) + g.F(x) })
+}
+
+// Mul returns the Chebfun of f ⋅ g
+//  NOTE: f and g must have the same domain
+func (o *Chebfun) Mul(g *Chebfun) *Chebfun {
+	return o.binary(g, func(x float64) float64 { return o.F(x) * g.F(x) })
+}
+
+// Compose returns the Chebfun of g(f(x))
+//  NOTE: to compose with another Chebfun h, use o.Compose(h.F); the range of f must be contained
+//        in the domain of h
+func (o *Chebfun) Compose(g Ss) *Chebfun {
+	return NewChebfun(func(x float64) float64 { return g(o.F(x)) }, o.Ends, o.split)
+}
+
+// Diff returns the Chebfun of the derivative df/dx (computed piecewise)
+func (o *Chebfun) Diff() (d *Chebfun) {
+	d = &Chebfun{Ends: o.Ends.GetCopy(), Coefs: make([]la.Vector, len(o.Coefs)), split: o.split}
+	for i, c := range o.Coefs {
+		d.Coefs[i] = chebDiffCoefs(c, 2/(o.Ends[i+1]-o.Ends[i]))
+	}
+	return
+}
+
+// Integ computes the definite integral of f over the whole domain (Clenshaw-Curtis quadrature)
+//   1              ⎧ 2 / (1 - k²)   k even
+//  ∫  T_k(t) dt =  ⎨
+//  -1              ⎩ 0              k odd
+func (o *Chebfun) Integ() (res float64) {
+	for i, c := range o.Coefs {
+		sum := 0.0
+		for k := 0; k < len(c); k += 2 {
+			sum += c[k] * 2 / float64(1-k*k)
+		}
+		res += sum * (o.Ends[i+1] - o.Ends[i]) / 2
+	}
+	return
+}
+
+// Roots computes all real roots of f in the domain (sorted)
+//
+//  The roots of each piece are the eigenvalues of the colleague matrix [1] (with la.EigenVal),
+//  keeping only the real ones inside [-1, 1]. Pieces with more than 50 coefficients are
+//  subdivided recursively.
+//  NOTE: with splitting, a jump through zero is also reported as a root
+func (o *Chebfun) Roots() (roots []float64) {
+	h := o.Ends[len(o.Ends)-1] - o.Ends[0]
+	for i, c := range o.Coefs {
+		for _, t := range chebRoots(c, -1, 1) {
+			x := o.Ends[i] + (o.Ends[i+1]-o.Ends[i])*(t+1)/2
+			if n := len(roots); n > 0 && math.Abs(x-roots[n-1]) < 1e-14*h {
+				continue // repeated root at breakpoint
+			}
+			roots = append(roots, x)
+		}
+	}
+	return
+}
+
+// Max computes the global maximum of f in the domain
+func (o *Chebfun) Max() (xmax, fmax float64) {
+	return o.extremum(1)
+}
+
+// Min computes the global minimum of f in the domain
+func (o *Chebfun) Min() (xmin, fmin float64) {
+	xmin, fmin = o.extremum(-1)
+	return xmin, -fmin
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// mapToRef maps x into the reference interval [-1, 1] of piece i
+func (o *Chebfun) mapToRef(i int, x float64) float64 {
+	return (2*x - o.Ends[i] - o.Ends[i+1]) / (o.Ends[i+1] - o.Ends[i])
+}
+
+// splitPiece resolves f in [a, b] with recursive bisection
+func (o *Chebfun) splitPiece(f Ss, a, b, minLen, vscale float64) {
+	c, ok := chebCoefsAdapt(f, a, b, cfMaxNsplt, vscale)
+	if ok || b-a < minLen {
+		o.Ends = append(o.Ends, b)
+		o.Coefs = append(o.Coefs, c)
+		return
+	}
+	m := (a + b) / 2
+	o.splitPiece(f, a, m, minLen, vscale)
+	o.splitPiece(f, m, b, minLen, vscale)
+}
+
+// merge merges adjacent pieces if they can be represented together. The merged piece must also
+// reproduce the original pieces; otherwise, features missed by the coarse sampling (e.g. a kink
+// close to the end of the merged interval) would be lost
+func (o *Chebfun) merge(f Ss, vscale float64) {
+	for i := 0; i < len(o.Coefs)-1; {
+		c, ok := chebCoefsAdapt(f, o.Ends[i], o.Ends[i+2], cfMaxNsplt, vscale)
+		if !ok || !o.reproduces(c, i, 100*cfTol*vscale) {
+			i++
+			continue
+		}
+		o.Coefs[i] = c
+		o.Coefs = append(o.Coefs[:i+1], o.Coefs[i+2:]...)
+		o.Ends = append(o.Ends[:i+1], o.Ends[i+2:]...)
+	}
+}
+
+// reproduces checks whether the series c over [Ends[i], Ends[i+2]] reproduces the pieces i and i+1
+// at their Chebyshev points (within tol)
+func (o *Chebfun) reproduces(c la.Vector, i int, tol float64) bool {
+	a, b := o.Ends[i], o.Ends[i+2]
+	for k := i; k <= i+1; k++ {
+		ck := o.Coefs[k]
+		l, r := o.Ends[k], o.Ends[k+1]
+		n := 2*len(ck) + 1
+		for j := 0; j < n; j++ {
+			t := math.Cos(math.Pi * (float64(j) + 0.5) / float64(n))
+			x := (l+r)/2 + (r-l)/2*t
+			if math.Abs(chebClenshaw(c, (2*x-a-b)/(b-a))-chebClenshaw(ck, t)) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// binary builds the Chebfun of a binary operation using the union of breakpoints
+func (o *Chebfun) binary(g *Chebfun, op Ss) *Chebfun {
+	n, m := len(o.Ends), len(g.Ends)
+	if o.Ends[0] != g.Ends[0] || o.Ends[n-1] != g.Ends[m-1] {
+		chk.Panic("domains must be equal. [%g, %g] != [%g, %g]\n", o.Ends[0], o.Ends[n-1], g.Ends[0], g.Ends[m-1])
+	}
+	ends := append(o.Ends.GetCopy(), g.Ends[1:m-1]...)
+	sort.Float64s(ends)
+	var union []float64
+	for i, x := range ends {
+		if i == 0 || x > union[len(union)-1] {
+			union = append(union, x)
+		}
+	}
+	return NewChebfun(op, union, o.split || g.split)
+}
+
+// extremum computes the maximum of sgn⋅f
+func (o *Chebfun) extremum(sgn float64) (xopt, fopt float64) {
+	fopt = math.Inf(-1)
+	for i, c := range o.Coefs {
+		cands := []float64{-1, 1}
+		if len(c) > 2 {
+			cands = append(cands, chebRoots(chebDiffCoefs(c, 1), -1, 1)...)
+		}
+		for _, t := range cands {
+			if val := sgn * chebClenshaw(c, t); val > fopt {
+				xopt, fopt = o.Ends[i]+(o.Ends[i+1]-o.Ends[i])*(t+1)/2, val
+			}
+		}
+	}
+	return
+}
+
+// chebCoefsAdapt computes the Chebyshev coefficients of f in [a, b] with increasing number of
+// points until convergence (ok=true) or until nmax is reached (ok=false)
+//  NOTE: the tolerance is relaxed if the local values are small compared to the global scale vscale
+func chebCoefsAdapt(f Ss, a, b float64, nmax int, vscale float64) (c la.Vector, ok bool) {
+	for n := cfMinN; n <= nmax; n = 2*n - 1 {
+		vals := chebSample(f, a, b, n)
+		vloc := 0.0
+		for _, v := range vals {
+			vloc = math.Max(vloc, math.Abs(v))
+		}
+		tol := cfTol
+		if vloc > 0 && vscale > vloc {
+			tol = math.Min(cfTol*vscale/vloc, 1e-3)
+		}
+		c = chebCoefs(vals)
+		if cut := chebChop(c, tol); cut < n {
+			return c[:cut], true
+		}
+	}
+	return c, false
+}
+
+// chebSample evaluates f at the n Chebyshev points of the first kind mapped into [a, b]
+func chebSample(f Ss, a, b float64, n int) (vals []float64) {
+	vals = make([]float64, n)
+	for j := 0; j < n; j++ {
+		t := math.Cos(math.Pi * (float64(j) + 0.5) / float64(n))
+		vals[j] = f((a+b)/2 + (b-a)/2*t)
+	}
+	return
+}
+
+// chebCoefs computes the Chebyshev coefficients from the values at first-kind Chebyshev points
+// t_j = cos(π (j+½) / n), j = 0...n-1, using the discrete cosine transform (DCT-II)
+func chebCoefs(vals []float64) (c la.Vector) {
+	n := len(vals)
+	c = la.NewVector(n)
+	copy(c, vals)
+	fft.NewPlanR2r(c, "dct2").Execute()
+	for k := 0; k < n; k++ {
+		c[k] /= float64(n)
+	}
+	c[0] /= 2
+	return
+}
+
+// chebChop returns the number of coefficients to keep according to the "standard chop"
+// algorithm [2]. The series is resolved if the result is smaller than len(c)
+func chebChop(c la.Vector, tol float64) (cutoff int) {
+
+	// step 1: monotonically non-increasing normalised envelope
+	n := len(c)
+	if n < cfMinN {
+		return n
+	}
+	env := make([]float64, n)
+	env[n-1] = math.Abs(c[n-1])
+	for j := n - 2; j >= 0; j-- {
+		env[j] = math.Max(math.Abs(c[j]), env[j+1])
+	}
+	if env[0] == 0 {
+		return 1
+	}
+	for j := n - 1; j >= 0; j-- {
+		env[j] /= env[0]
+	}
+
+	// step 2: plateau point (indices are 1-based as in [2])
+	var plateau, j2 int
+	for j := 2; j <= n; j++ {
+		j2 = int(math.Round(1.25*float64(j) + 5))
+		if j2 > n {
+			return n // no plateau: not resolved
+		}
+		e1, e2 := env[j-1], env[j2-1]
+		r := 3 * (1 - math.Log(e1)/math.Log(tol))
+		if e1 == 0 || e2/e1 > r {
+			plateau = j - 1
+			break
+		}
+	}
+
+	// step 3: cutoff
+	if env[plateau-1] == 0 {
+		return plateau
+	}
+	tol76 := math.Pow(tol, 7.0/6.0)
+	j3 := 0
+	for _, e := range env {
+		if e >= tol76 {
+			j3++
+		}
+	}
+	if j3 < j2 {
+		j2 = j3 + 1
+		env[j2-1] = tol76
+	}
+	dmin, cmin := 0, math.Inf(1)
+	for i := 0; i < j2; i++ {
+		cc := math.Log10(env[i])
+		if j2 > 1 {
+			cc += float64(i) / float64(j2-1) * (-1.0 / 3.0) * math.Log10(tol)
+		}
+		if cc < cmin {
+			dmin, cmin = i+1, cc
+		}
+	}
+	if dmin-1 > 1 {
+		return dmin - 1
+	}
+	return 1
+}
+
+// chebClenshaw evaluates Σ c[k] T_k(t) with Clenshaw's algorithm
+func chebClenshaw(c la.Vector, t float64) float64 {
+	var b0, b1, b2 float64
+	for k := len(c) - 1; k > 0; k-- {
+		b0 = c[k] + 2*t*b1 - b2
+		b2, b1 = b1, b0
+	}
+	return c[0] + t*b1 - b2
+}
+
+// chebDiffCoefs computes the coefficients of the derivative of Σ c[k] T_k(t) multiplied by scale
+//  d[k-1] = d[k+1] + 2 k c[k]
+func chebDiffCoefs(c la.Vector, scale float64) (d la.Vector) {
+	n := len(c)
+	if n < 2 {
+		return la.Vector{0}
+	}
+	d = la.NewVector(n + 1)
+	for k := n - 1; k > 0; k-- {
+		d[k-1] = d[k+1] + 2*float64(k)*c[k]
+	}
+	d[0] /= 2
+	d = d[:n-1]
+	for k := range d {
+		d[k] *= scale
+	}
+	return
+}
+
+// chebRoots computes the real roots of Σ c[k] T_k(t) in [-1, 1] mapped into [a, b]
+func chebRoots(c la.Vector, a, b float64) (roots []float64) {
+
+	// trim negligible trailing coefficients
+	cmax := 0.0
+	for _, v := range c {
+		cmax = math.Max(cmax, math.Abs(v))
+	}
+	if cmax == 0 {
+		return
+	}
+	n := len(c) - 1
+	for n > 0 && math.Abs(c[n]) < cfTol*cmax {
+		n--
+	}
+
+	// subdivide large pieces
+	if n > cfMaxNroot {
+		s := cfRootSplt
+		left := chebRestrict(c[:n+1], -1, s)
+		right := chebRestrict(c[:n+1], s, 1)
+		m := a + (b-a)*(s+1)/2
+		roots = chebRoots(left, a, m)
+		for _, x := range chebRoots(right, m, b) {
+			if k := len(roots); k > 0 && math.Abs(x-roots[k-1]) < 1e-14*(b-a) {
+				continue
+			}
+			roots = append(roots, x)
+		}
+		return
+	}
+
+	// eigenvalues of the colleague matrix
+	var ts []float64
+	switch n {
+	case 0:
+		return
+	case 1:
+		ts = []float64{-c[0] / c[1]}
+	default:
+		A := la.NewMatrix(n, n)
+		A.Set(0, 1, 1)
+		for i := 1; i < n; i++ {
+			A.Set(i, i-1, 0.5)
+			if i < n-1 {
+				A.Set(i, i+1, 0.5)
+			}
+		}
+		for j := 0; j < n; j++ {
+			A.Add(n-1, j, -c[j]/(2*c[n]))
+		}
+		λ := make([]complex128, n)
+		la.EigenVal(λ, A, false)
+		for _, l := range λ {
+			if math.Abs(imag(l)) < 1e-8 {
+				ts = append(ts, real(l))
+			}
+		}
+	}
+
+	// select, map and sort
+	for _, t := range ts {
+		if t >= -1-1e-10 && t <= 1+1e-10 {
+			t = math.Max(-1, math.Min(1, t))
+			roots = append(roots, a+(b-a)*(t+1)/2)
+		}
+	}
+	sort.Float64s(roots)
+	return
+}
+
+// chebRestrict computes the coefficients of Σ c[k] T_k(t) restricted to [l, r] ⊂ [-1, 1]
+func chebRestrict(c la.Vector, l, r float64) (d la.Vector) {
+	d = chebCoefs(chebSample(func(t float64) float64 { return chebClenshaw(c, t) }, l, r, len(c)))
+	return d[:chebChop(d, cfTol)]
+}
@@ -0,0 +1,236 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
+
+// ChebyTensor implements N-D tensor-product Chebyshev interpolation over a box [xmin, xmax]
+//
+//                     N0    N1         N(n-1)
+//  I{f}(x) = Σ Σ ... Σ   C[k0,k1,...] ⋅ T_k0(ξ0) ⋅ T_k1(ξ1) ⋅ ... ⋅ T_k(n-1)(ξ(n-1))
+//                    k0=0  k1=0        k(n-1)=0
+//
+//  where T_k are the Chebyshev polynomials and ξk = 2⋅(xk - xmin[k]) / (xmax[k] - xmin[k]) - 1.
+//  The coefficients C are computed from the values at the tensor grid of Chebyshev-Gauss or
+//  Chebyshev-Gauss-Lobatto points by applying the 1D transform of ChebyInterp (see CalcCoefI)
+//  along each direction. The nodes and coefficients are numbered with the first index running
+//  fastest (see TensorInterp).
+//
+//  Because the coefficients of smooth functions decay quickly, the magnitude of the last
+//  coefficients along each direction provides an estimate of the interpolation error without
+//  extra function evaluations (see ErrorEstimate).
+//
+//  NOTE: I and Grad use internal workspaces; thus, they must not be called concurrently
+type ChebyTensor struct {
+	Ndim int            // space dimension
+	Xmin la.Vector      // [ndim] min coordinates of box
+	Xmax la.Vector      // [ndim] max coordinates of box
+	Npts []int          // [ndim] number of points along each direction = degree + 1
+	Cis  []*ChebyInterp // [ndim] 1D interpolators
+	U    la.Vector      // [Size] function evaluated @ nodes: f(x_I)
+	Coef la.Vector      // [Size] coefficients C[k0,k1,...]
+
+	// workspace
+	mats []*la.Matrix // [ndim] 1D transforms: physical to coefficients
+	t    [][]float64  // [ndim][npts] Chebyshev polynomials @ x
+	dt   [][]float64  // [ndim][npts] derivatives of Chebyshev polynomials @ x (w.r.t ξ)
+	vecs [][]float64  // [ndim] pointers to t or dt
+	work []float64    // workspace for contractions and transforms
+}
+
+// NewChebyTensor returns a new tensor-product Chebyshev interpolator
+//  xmin, xmax -- [ndim] limits of box
+//  degrees    -- [ndim] degrees of 1D interpolators (≥ 1)
+//  gauss      -- use Chebyshev-Gauss (roots) instead of Chebyshev-Gauss-Lobatto points
+func NewChebyTensor(xmin, xmax []float64, degrees []int, gauss bool) (o *ChebyTensor) {
+
+	// check
+	ndim := len(xmin)
+	if ndim < 1 || len(xmax) != ndim || len(degrees) != ndim {
+		chk.Panic("xmin, xmax and degrees must have the same length ≥ 1\n")
+	}
+
+	// allocate
+	o = new(ChebyTensor)
+	o.Ndim = ndim
+	o.Xmin = la.NewVector(ndim)
+	o.Xmax = la.NewVector(ndim)
+	o.Npts = make([]int, ndim)
+	o.Cis = make([]*ChebyInterp, ndim)
+	o.mats = make([]*la.Matrix, ndim)
+	o.t = make([][]float64, ndim)
+	o.dt = make([][]float64, ndim)
+	o.vecs = make([][]float64, ndim)
+	size := 1
+	for k := 0; k < ndim; k++ {
+		if xmax[k] <= xmin[k] {
+			chk.Panic("xmax must be greater than xmin. xmin[%d] = %g and xmax[%d] = %g are invalid\n", k, xmin[k], k, xmax[k])
+		}
+		if degrees[k] < 1 {
+			chk.Panic("degrees must be at least 1. degrees[%d] = %d is invalid\n", k, degrees[k])
+		}
+		o.Xmin[k], o.Xmax[k] = xmin[k], xmax[k]
+		o.Npts[k] = degrees[k] + 1
+		size *= o.Npts[k]
+
+		// 1D transform: M_kj = T_k(x_j) ⋅ wb_j / γ_k
+		ci := NewChebyInterp(degrees[k], gauss)
+		n := o.Npts[k]
+		o.Cis[k] = ci
+		o.mats[k] = la.NewMatrix(n, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				o.mats[k].Set(i, j, ChebyshevT(i, ci.X[j])*ci.Wb[j]/ci.Gamma[i])
+			}
+		}
+		o.t[k] = make([]float64, n)
+		o.dt[k] = make([]float64, n)
+	}
+	o.U = la.NewVector(size)
+	o.Coef = la.NewVector(size)
+	o.work = make([]float64, size)
+	return
+}
+
+// Size returns the total number of nodes
+func (o *ChebyTensor) Size() int {
+	return len(o.U)
+}
+
+// Node returns the coordinates of node I
+func (o *ChebyTensor) Node(I int) (x la.Vector) {
+	x = la.NewVector(o.Ndim)
+	for k := 0; k < o.Ndim; k++ {
+		i := I % o.Npts[k]
+		I /= o.Npts[k]
+		x[k] = o.Xmin[k] + (o.Cis[k].X[i]+1.0)*(o.Xmax[k]-o.Xmin[k])/2.0
+	}
+	return
+}
+
+// CalcCoef computes f(x_I) @ all nodes and the coefficients of the interpolant
+func (o *ChebyTensor) CalcCoef(f Sv) {
+	for I := 0; I < len(o.U); I++ {
+		o.U[I] = f(o.Node(I))
+	}
+	o.SetU(o.U)
+}
+
+// SetU sets the values at the nodes (e.g. computed externally) and computes the coefficients
+func (o *ChebyTensor) SetU(U la.Vector) {
+	if len(U) != len(o.U) {
+		chk.Panic("len(U) must be equal to the number of nodes = %d. %d is invalid\n", len(o.U), len(U))
+	}
+	copy(o.U, U)
+	copy(o.Coef, U)
+	stride := 1
+	for k := 0; k < o.Ndim; k++ {
+		n := o.Npts[k]
+		nouter := len(o.U) / (stride * n)
+		for b := 0; b < nouter; b++ {
+			for a := 0; a < stride; a++ {
+				off := a + stride*n*b
+				for i := 0; i < n; i++ {
+					sum := 0.0
+					for j := 0; j < n; j++ {
+						sum += o.mats[k].Get(i, j) * o.Coef[off+stride*j]
+					}
+					o.work[off+stride*i] = sum
+				}
+			}
+		}
+		copy(o.Coef, o.work)
+		stride *= n
+	}
+}
+
+// I computes the interpolation I{f}(x) @ x
+//  NOTE: the coefficients must be computed with CalcCoef or SetU first
+func (o *ChebyTensor) I(x la.Vector) float64 {
+	for k := 0; k < o.Ndim; k++ {
+		o.polys(k, x[k], false)
+		o.vecs[k] = o.t[k]
+	}
+	return tensorContract(o.Coef, o.Npts, o.vecs, o.work)
+}
+
+// Grad computes the gradient of the interpolant @ x and returns the interpolation I{f}(x)
+//  Output:
+//    g -- [ndim] gradient dI{f}/dx @ x
+//  NOTE: the coefficients must be computed with CalcCoef or SetU first
+func (o *ChebyTensor) Grad(g, x la.Vector) (res float64) {
+	for k := 0; k < o.Ndim; k++ {
+		o.polys(k, x[k], true)
+		o.vecs[k] = o.t[k]
+	}
+	res = tensorContract(o.Coef, o.Npts, o.vecs, o.work)
+	for k := 0; k < o.Ndim; k++ {
+		o.vecs[k] = o.dt[k]
+		g[k] = tensorContract(o.Coef, o.Npts, o.vecs, o.work) * 2.0 / (o.Xmax[k] - o.Xmin[k])
+		o.vecs[k] = o.t[k]
+	}
+	return
+}
+
+// ErrorEstimate estimates the interpolation error from the magnitude of the last two coefficients
+// along each direction
+//
+//                     Nk
+//  errDims[k] =  Σ    Σ      |C[k0,...,kk,...]|
+//              others kk=Nk-1
+//
+//  Output:
+//    err     -- total estimate: Σ errDims
+//    errDims -- [ndim] estimates along each direction; useful to select the degrees
+//
+//  NOTE: the coefficients must be computed with CalcCoef or SetU first
+func (o *ChebyTensor) ErrorEstimate() (err float64, errDims la.Vector) {
+	errDims = la.NewVector(o.Ndim)
+	for I, c := range o.Coef {
+		J := I
+		for k := 0; k < o.Ndim; k++ {
+			i := J % o.Npts[k]
+			J /= o.Npts[k]
+			if i >= o.Npts[k]-2 {
+				errDims[k] += math.Abs(c)
+			}
+		}
+	}
+	return errDims.Accum(), errDims
+}
+
+// EstimateMaxErr estimates the maximum error using a tensor grid of stations
+//
+//   maxerr = max(|f - I{f}|)
+//
+//  nsta -- number of stations along each direction [use ≤ 1 for default = 11]
+//
+//  NOTE: the coefficients must be computed with CalcCoef or SetU first
+func (o *ChebyTensor) EstimateMaxErr(f Sv, nsta int) (maxerr float64, xloc la.Vector) {
+	return tensorMaxErr(o.Xmin, o.Xmax, nsta, f, o.I)
+}
+
+// polys computes the Chebyshev polynomials (and derivatives) along direction k @ xk by recurrence
+//
+//  T_{n+1} = 2 ξ T_n - T_{n-1}      T'_{n+1} = 2 T_n + 2 ξ T'_n - T'_{n-1}
+func (o *ChebyTensor) polys(k int, xk float64, withDerivs bool) {
+	ξ := 2.0*(xk-o.Xmin[k])/(o.Xmax[k]-o.Xmin[k]) - 1.0
+	t, dt := o.t[k], o.dt[k]
+	t[0], t[1] = 1, ξ
+	for n := 1; n < len(t)-1; n++ {
+		t[n+1] = 2.0*ξ*t[n] - t[n-1]
+	}
+	if withDerivs {
+		dt[0], dt[1] = 0, 1
+		for n := 1; n < len(t)-1; n++ {
+			dt[n+1] = 2.0*t[n] + 2.0*ξ*dt[n] - dt[n-1]
+		}
+	}
+}
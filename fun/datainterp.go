@@ -15,7 +15,10 @@ import (
 type DataInterp struct {
 
 	// configuration data
-	DisableHunt bool // do not use hunt code at all
+	DisableHunt bool    // do not use hunt code at all
+	Extrap      string  // extrapolation outside the data range: "" (extend end pieces), "const", "lin" or "panic"
+	DyLeft      float64 // "spline-clamped" only: dy/dx at the first point (call Reset after changing)
+	DyRight     float64 // "spline-clamped" only: dy/dx at the last point (call Reset after changing)
 
 	// output data
 	Dy float64 // error estimate
@@ -33,6 +36,10 @@ type DataInterp struct {
 	useHunt bool // use hunt code instead of locate
 	ascnd   bool // ascending order of x-values
 
+	// piecewise cubic Hermite data (spline, pchip and akima)
+	dd  []float64 // dy/dx at each data point
+	cum []float64 // cumulative integral from xx[0] to each data point
+
 	// implementation
 	interp func(j int, x float64) float64
 }
@@ -40,10 +47,15 @@ type DataInterp struct {
 // NewDataInterp creates new interpolator for data point sets xx and yy (with same lengths)
 //
 //     Type -- type of interpolator
-//        "lin"  : linear
-//        "poly" : polynomial
+//        "lin"            : linear
+//        "poly"           : polynomial
+//        "spline"         : natural cubic spline (zero second derivatives at the ends)
+//        "spline-clamped" : cubic spline with given first derivatives DyLeft and DyRight at the ends
+//        "spline-nak"     : cubic spline with not-a-knot end conditions
+//        "pchip"          : monotone piecewise cubic Hermite (Fritsch-Carlson)
+//        "akima"          : Akima piecewise cubic Hermite (less wiggly than splines)
 //
-//     p  -- order of interpolator
+//     p  -- order of interpolator ("poly" only)
 //     xx -- x-data
 //     yy -- y-data
 func NewDataInterp(Type string, p int, xx, yy []float64) (o *DataInterp) {
@@ -56,6 +68,9 @@ func NewDataInterp(Type string, p int, xx, yy []float64) (o *DataInterp) {
 	case "poly":
 		o.m = p + 1
 		o.interp = o.polyInterp
+	case "spline", "spline-clamped", "spline-nak", "pchip", "akima":
+		o.m = 2
+		o.interp = o.hermInterp
 	default:
 		chk.Panic("cannot find interpolator type == %q\n", Type)
 	}
@@ -80,18 +95,111 @@ func (o *DataInterp) Reset(xx, yy []float64) {
 	o.djHunt = utl.Imin(1, int(math.Pow(float64(o.n), 0.25)))
 	o.useHunt = false
 	o.ascnd = o.xx[o.n-1] >= o.xx[0]
+	switch o.itype {
+	case "spline", "spline-clamped", "spline-nak", "pchip", "akima":
+		o.calcSlopes()
+	}
+	if o.itype != "poly" {
+		o.calcCumulative()
+	}
 	return
 }
 
 // P computes P(x); i.e. performs the interpolation
 func (o *DataInterp) P(x float64) float64 {
-	var jlo int
+	if iend, out := o.outside(x); out {
+		y, _ := o.extrapolate(iend, x)
+		return y
+	}
+	return o.interp(o.find(x), x)
+}
+
+// G computes the first derivative dP/dx
+//   NOTE: not available with "poly"
+func (o *DataInterp) G(x float64) float64 {
+	if iend, out := o.outside(x); out {
+		_, dydx := o.extrapolate(iend, x)
+		return dydx
+	}
+	return o.pieceDeriv(o.find(x), x)
+}
+
+// H computes the second derivative d²P/dx²
+//   NOTE: not available with "poly"; H is zero with "lin" and discontinuous at the data points
+//         with "pchip" and "akima"
+func (o *DataInterp) H(x float64) float64 {
+	if _, out := o.outside(x); out {
+		return 0
+	}
+	return o.pieceDeriv2(o.find(x), x)
+}
+
+// Integ computes the integral of P(x) from a to b
+//   NOTE: not available with "poly"
+func (o *DataInterp) Integ(a, b float64) float64 {
+	return o.antiderivative(b) - o.antiderivative(a)
+}
+
+// find returns the index of the first point of the subrange used to interpolate at x
+func (o *DataInterp) find(x float64) int {
 	if o.useHunt && !o.DisableHunt {
-		jlo = o.hunt(x)
-	} else {
-		jlo = o.locate(x)
+		return o.hunt(x)
+	}
+	return o.locate(x)
+}
+
+// outside checks whether x is outside the data range and an extrapolation policy is set.
+// It returns the index of the nearest end point
+func (o *DataInterp) outside(x float64) (iend int, out bool) {
+	if o.Extrap == "" {
+		return
+	}
+	first, last := 0, o.n-1
+	if !o.ascnd {
+		first, last = last, first
+	}
+	if x < o.xx[first] {
+		return first, true
+	}
+	if x > o.xx[last] {
+		return last, true
 	}
-	return o.interp(jlo, x)
+	return
+}
+
+// extrapolate computes P(x) and dP/dx outside the data range, near point iend
+func (o *DataInterp) extrapolate(iend int, x float64) (y, dydx float64) {
+	switch o.Extrap {
+	case "const":
+		return o.yy[iend], 0
+	case "lin":
+		dydx = o.pieceDeriv(o.locate(o.xx[iend]), o.xx[iend])
+		return o.yy[iend] + dydx*(x-o.xx[iend]), dydx
+	case "panic":
+		chk.Panic("x = %g is outside the data range [%g, %g]\n", x, o.xx[0], o.xx[o.n-1])
+	}
+	chk.Panic("cannot find extrapolation policy == %q\n", o.Extrap)
+	return
+}
+
+// antiderivative computes the integral of P from xx[0] to x
+func (o *DataInterp) antiderivative(x float64) float64 {
+	if o.cum == nil {
+		chk.Panic("integration is not available with %q interpolator\n", o.itype)
+	}
+	if iend, out := o.outside(x); out {
+		switch o.Extrap {
+		case "const":
+			return o.cum[iend] + o.yy[iend]*(x-o.xx[iend])
+		case "lin":
+			_, dydx := o.extrapolate(iend, x)
+			dx := x - o.xx[iend]
+			return o.cum[iend] + o.yy[iend]*dx + dydx*dx*dx/2.0
+		}
+		o.extrapolate(iend, x) // panic
+	}
+	j := o.locate(x)
+	return o.cum[j] + o.pieceInteg(j, x)
 }
 
 // locate returns a value j such that x is (insofar as possible) centered in the subrange
@@ -196,6 +304,200 @@ func (o *DataInterp) linInterp(j int, x float64) float64 {
 	return o.yy[j] + (o.yy[j+1]-o.yy[j])*(x-o.xx[j])/(o.xx[j+1]-o.xx[j])
 }
 
+// hermInterp implements the piecewise cubic Hermite interpolator
+//
+//   P(x) = h00(t)⋅y[j] + h10(t)⋅Δx⋅d[j] + h01(t)⋅y[j+1] + h11(t)⋅Δx⋅d[j+1]     t = (x - x[j]) / Δx
+//
+//   where d are the derivatives dy/dx at the data points
+func (o *DataInterp) hermInterp(j int, x float64) float64 {
+	h := o.xx[j+1] - o.xx[j]
+	t := (x - o.xx[j]) / h
+	t2, t3 := t*t, t*t*t
+	return (2*t3-3*t2+1)*o.yy[j] + (t3-2*t2+t)*h*o.dd[j] + (-2*t3+3*t2)*o.yy[j+1] + (t3-t2)*h*o.dd[j+1]
+}
+
+// pieceDeriv computes dP/dx using the piece starting at j
+func (o *DataInterp) pieceDeriv(j int, x float64) float64 {
+	h := o.xx[j+1] - o.xx[j]
+	switch o.itype {
+	case "poly":
+		chk.Panic("derivatives are not available with %q interpolator\n", o.itype)
+	case "lin":
+		if h == 0 {
+			return 0
+		}
+		return (o.yy[j+1] - o.yy[j]) / h
+	}
+	t := (x - o.xx[j]) / h
+	t2 := t * t
+	return (6*t2-6*t)*o.yy[j]/h + (3*t2-4*t+1)*o.dd[j] + (-6*t2+6*t)*o.yy[j+1]/h + (3*t2-2*t)*o.dd[j+1]
+}
+
+// pieceDeriv2 computes d²P/dx² using the piece starting at j
+func (o *DataInterp) pieceDeriv2(j int, x float64) float64 {
+	switch o.itype {
+	case "poly":
+		chk.Panic("derivatives are not available with %q interpolator\n", o.itype)
+	case "lin":
+		return 0
+	}
+	h := o.xx[j+1] - o.xx[j]
+	t := (x - o.xx[j]) / h
+	return ((12*t-6)*(o.yy[j]-o.yy[j+1])/h + (6*t-4)*o.dd[j] + (6*t-2)*o.dd[j+1]) / h
+}
+
+// pieceInteg computes the integral of P from x[j] to x using the piece starting at j
+func (o *DataInterp) pieceInteg(j int, x float64) float64 {
+	h := o.xx[j+1] - o.xx[j]
+	if o.itype == "lin" {
+		if h == 0 {
+			return 0
+		}
+		dx := x - o.xx[j]
+		return o.yy[j]*dx + (o.yy[j+1]-o.yy[j])/h*dx*dx/2.0
+	}
+	t := (x - o.xx[j]) / h
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+	return h * ((t4/2-t3+t)*o.yy[j] + (t4/4-2*t3/3+t2/2)*h*o.dd[j] + (-t4/2+t3)*o.yy[j+1] + (t4/4-t3/3)*h*o.dd[j+1])
+}
+
+// calcCumulative computes the cumulative integrals at the data points
+func (o *DataInterp) calcCumulative() {
+	o.cum = make([]float64, o.n)
+	for j := 0; j < o.n-1; j++ {
+		o.cum[j+1] = o.cum[j] + o.pieceInteg(j, o.xx[j+1])
+	}
+}
+
+// calcSlopes computes the derivatives dy/dx at the data points of the piecewise cubic Hermite interpolators
+//
+//   References:
+//   [1] de Boor C (2001) A Practical Guide to Splines. Revised Edition, Springer
+//   [2] Fritsch FN and Carlson RE (1980) Monotone piecewise cubic interpolation. SIAM Journal on
+//       Numerical Analysis, 17(2):238-246
+//   [3] Akima H (1970) A new method of interpolation and smooth curve fitting based on local
+//       procedures. Journal of the ACM, 17(4):589-602
+func (o *DataInterp) calcSlopes() {
+
+	// check
+	n := o.n
+	for i := 1; i < n; i++ {
+		if o.xx[i] <= o.xx[i-1] {
+			chk.Panic("x-data must be strictly increasing when using %q interpolator\n", o.itype)
+		}
+	}
+
+	// intervals and divided differences
+	h := make([]float64, n-1)
+	δ := make([]float64, n-1)
+	for i := 0; i < n-1; i++ {
+		h[i] = o.xx[i+1] - o.xx[i]
+		δ[i] = (o.yy[i+1] - o.yy[i]) / h[i]
+	}
+	o.dd = make([]float64, n)
+	d := o.dd
+	if n == 2 && o.itype != "spline-clamped" {
+		d[0], d[1] = δ[0], δ[0]
+		return
+	}
+
+	switch o.itype {
+
+	// monotone piecewise cubic Hermite [2]
+	case "pchip":
+		for i := 1; i < n-1; i++ {
+			if δ[i-1]*δ[i] > 0 {
+				w1, w2 := 2*h[i]+h[i-1], h[i]+2*h[i-1]
+				d[i] = (w1 + w2) / (w1/δ[i-1] + w2/δ[i])
+			}
+		}
+		d[0] = pchipEnd(h[0], h[1], δ[0], δ[1])
+		d[n-1] = pchipEnd(h[n-2], h[n-3], δ[n-2], δ[n-3])
+
+	// Akima [3]
+	case "akima":
+		m := make([]float64, n+3) // m[i+2] = δ[i]
+		for i := 0; i < n-1; i++ {
+			m[i+2] = δ[i]
+		}
+		m[1] = 2*m[2] - m[3]
+		m[0] = 2*m[1] - m[2]
+		m[n+1] = 2*m[n] - m[n-1]
+		m[n+2] = 2*m[n+1] - m[n]
+		for i := 0; i < n; i++ {
+			w1, w2 := math.Abs(m[i+3]-m[i+2]), math.Abs(m[i+1]-m[i])
+			if w1+w2 == 0 {
+				d[i] = (m[i+1] + m[i+2]) / 2.0
+			} else {
+				d[i] = (w1*m[i+1] + w2*m[i+2]) / (w1 + w2)
+			}
+		}
+
+	// cubic splines: tridiagonal system for the slopes [1]
+	default:
+		if o.itype == "spline-nak" && n == 3 {
+			// not-a-knot with 3 points: parabola through the points
+			d[0] = δ[0] - h[0]*(δ[1]-δ[0])/(h[0]+h[1])
+			d[1] = δ[0] + h[0]*(δ[1]-δ[0])/(h[0]+h[1])
+			d[2] = δ[1] + h[1]*(δ[1]-δ[0])/(h[0]+h[1])
+			return
+		}
+		lo := make([]float64, n) // sub-diagonal
+		di := make([]float64, n) // diagonal
+		up := make([]float64, n) // super-diagonal
+		for i := 1; i < n-1; i++ {
+			lo[i], di[i], up[i] = h[i], 2*(h[i-1]+h[i]), h[i-1]
+			d[i] = 3 * (h[i]*δ[i-1] + h[i-1]*δ[i])
+		}
+		switch o.itype {
+		case "spline":
+			di[0], up[0], d[0] = 2, 1, 3*δ[0]
+			lo[n-1], di[n-1], d[n-1] = 1, 2, 3*δ[n-2]
+		case "spline-clamped":
+			di[0], up[0], d[0] = 1, 0, o.DyLeft
+			lo[n-1], di[n-1], d[n-1] = 0, 1, o.DyRight
+		case "spline-nak":
+			s := h[0] + h[1]
+			di[0], up[0] = h[1], s
+			d[0] = ((h[0]+2*s)*h[1]*δ[0] + h[0]*h[0]*δ[1]) / s
+			s = h[n-2] + h[n-3]
+			lo[n-1], di[n-1] = s, h[n-3]
+			d[n-1] = (h[n-2]*h[n-2]*δ[n-3] + (2*s+h[n-2])*h[n-3]*δ[n-2]) / s
+		}
+		solveTridiag(lo, di, up, d)
+	}
+}
+
+// pchipEnd computes the shape-preserving derivative at an end point using a three-point formula [2]
+//   h0, δ0 -- interval and divided difference next to the end point
+//   h1, δ1 -- interval and divided difference of the following segment
+func pchipEnd(h0, h1, δ0, δ1 float64) (d float64) {
+	d = ((2*h0+h1)*δ0 - h0*δ1) / (h0 + h1)
+	if d*δ0 <= 0 {
+		return 0
+	}
+	if δ0*δ1 <= 0 && math.Abs(d) > math.Abs(3*δ0) {
+		return 3 * δ0
+	}
+	return
+}
+
+// solveTridiag solves a tridiagonal system by the Thomas algorithm
+//   lo, di, up -- sub-, main and super-diagonals (modified)
+//   x -- right-hand side (input) and solution (output)
+func solveTridiag(lo, di, up, x []float64) {
+	n := len(di)
+	for i := 1; i < n; i++ {
+		w := lo[i] / di[i-1]
+		di[i] -= w * up[i-1]
+		x[i] -= w * x[i-1]
+	}
+	x[n-1] /= di[n-1]
+	for i := n - 2; i >= 0; i-- {
+		x[i] = (x[i] - up[i]*x[i+1]) / di[i]
+	}
+}
+
 // polyInterp performs a polynomial interpolation. This routine returns an interpolated value y, and
 // stores an error estimate dy. The returned value is obtained by m-point polynomial interpolation
 // on the subrange xx[jl..jl+m-1].
@@ -0,0 +1,722 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbf
+
+import (
+	"math"
+	"strconv"
+	"unicode"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+// kinds of nodes in expression trees
+const (
+	exprNum = iota // number
+	exprT          // time variable t
+	exprX          // coordinate x[i]
+	exprPrm        // parameter
+	exprAdd        // a + b
+	exprSub        // a - b
+	exprMul        // a * b
+	exprDiv        // a / b
+	exprPow        // a ^ b
+	exprNeg        // -a
+	exprFcn        // f(a)
+)
+
+// exprFcns holds the functions of one argument available in expressions
+var exprFcns = map[string]func(float64) float64{
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+	"asin":  math.Asin,
+	"acos":  math.Acos,
+	"atan":  math.Atan,
+	"sinh":  math.Sinh,
+	"cosh":  math.Cosh,
+	"tanh":  math.Tanh,
+	"exp":   math.Exp,
+	"log":   math.Log,
+	"sqrt":  math.Sqrt,
+	"abs":   math.Abs,
+	"sign":  exprSign,
+	"heav":  exprHeav,
+	"delta": func(float64) float64 { return 0 },
+}
+
+// exprSign returns the sign of x: -1, 0 or 1
+func exprSign(x float64) float64 {
+	if x < 0 {
+		return -1
+	}
+	if x > 0 {
+		return 1
+	}
+	return 0
+}
+
+// exprHeav returns the Heaviside function of x: 0 if x < 0, ½ if x = 0, 1 if x > 0
+func exprHeav(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	if x > 0 {
+		return 1
+	}
+	return 0.5
+}
+
+// exprNode defines a node of an expression tree
+type exprNode struct {
+	kind int       // kind of node
+	val  float64   // value of number
+	idx  int       // index of coordinate or parameter
+	name string    // name of parameter or function
+	a, b *exprNode // arguments
+}
+
+// exprParse parses an expression string into a tree
+//  prms -- names of parameters; the index in this list identifies the parameter
+//
+//  Grammar (^ is right-associative and binds tighter than unary minus; ** is a synonym of ^):
+//    expr    := term { ("+" | "-") term }
+//    term    := unary { ("*" | "/") unary }
+//    unary   := ("-" | "+") unary | power
+//    power   := primary [ "^" unary ]
+//    primary := number | "t" | "x" "[" integer "]" | name | fcn "(" expr ")" | "(" expr ")"
+//
+//  The names t and x are reserved. The constant "pi" is available unless a parameter with this
+//  name is given
+func exprParse(str string, prms []string) (node *exprNode) {
+	p := &exprParser{str: str, src: []rune(str), prms: prms}
+	p.next()
+	node = p.expr()
+	if p.tok != "" {
+		p.fail("unexpected %q", p.tok)
+	}
+	return
+}
+
+// exprParser implements a recursive descent parser for expressions
+type exprParser struct {
+	str  string   // expression
+	src  []rune   // expression as runes
+	prms []string // names of parameters
+	pos  int      // position of next rune in src
+	tok  string   // current token; "" means end of input
+	tpos int      // position of current token
+	num  bool     // current token is a number
+}
+
+// fail panics with a message indicating the position of the current token
+func (o *exprParser) fail(msg string, args ...interface{}) {
+	chk.Panic("expr: %s at position %d of %q\n", io.Sf(msg, args...), o.tpos, o.str)
+}
+
+// next reads the next token
+func (o *exprParser) next() {
+	for o.pos < len(o.src) && unicode.IsSpace(o.src[o.pos]) {
+		o.pos++
+	}
+	o.tpos, o.num = o.pos, false
+	if o.pos == len(o.src) {
+		o.tok = ""
+		return
+	}
+	start := o.pos
+	r := o.src[o.pos]
+	switch {
+	case unicode.IsDigit(r) || r == '.':
+		o.num = true
+		for o.pos < len(o.src) && (unicode.IsDigit(o.src[o.pos]) || o.src[o.pos] == '.') {
+			o.pos++
+		}
+		if o.pos < len(o.src) && (o.src[o.pos] == 'e' || o.src[o.pos] == 'E') {
+			k := o.pos + 1
+			if k < len(o.src) && (o.src[k] == '+' || o.src[k] == '-') {
+				k++
+			}
+			if k < len(o.src) && unicode.IsDigit(o.src[k]) {
+				o.pos = k
+				for o.pos < len(o.src) && unicode.IsDigit(o.src[o.pos]) {
+					o.pos++
+				}
+			}
+		}
+	case unicode.IsLetter(r) || r == '_':
+		for o.pos < len(o.src) && (unicode.IsLetter(o.src[o.pos]) || unicode.IsDigit(o.src[o.pos]) || o.src[o.pos] == '_') {
+			o.pos++
+		}
+	case r == '*' && o.pos+1 < len(o.src) && o.src[o.pos+1] == '*':
+		o.pos += 2
+	default:
+		o.pos++
+	}
+	o.tok = string(o.src[start:o.pos])
+	if o.tok == "**" {
+		o.tok = "^"
+	}
+}
+
+// expect checks the current token and reads the next one
+func (o *exprParser) expect(tok string) {
+	if o.tok != tok {
+		if o.tok == "" {
+			o.fail("expected %q but found end of expression", tok)
+		}
+		o.fail("expected %q but found %q", tok, o.tok)
+	}
+	o.next()
+}
+
+// expr parses a sum of terms
+func (o *exprParser) expr() (node *exprNode) {
+	node = o.term()
+	for o.tok == "+" || o.tok == "-" {
+		op := o.tok
+		o.next()
+		if op == "+" {
+			node = &exprNode{kind: exprAdd, a: node, b: o.term()}
+		} else {
+			node = &exprNode{kind: exprSub, a: node, b: o.term()}
+		}
+	}
+	return
+}
+
+// term parses a product of factors
+func (o *exprParser) term() (node *exprNode) {
+	node = o.unary()
+	for o.tok == "*" || o.tok == "/" {
+		op := o.tok
+		o.next()
+		if op == "*" {
+			node = &exprNode{kind: exprMul, a: node, b: o.unary()}
+		} else {
+			node = &exprNode{kind: exprDiv, a: node, b: o.unary()}
+		}
+	}
+	return
+}
+
+// unary parses signed factors
+func (o *exprParser) unary() *exprNode {
+	switch o.tok {
+	case "-":
+		o.next()
+		return &exprNode{kind: exprNeg, a: o.unary()}
+	case "+":
+		o.next()
+		return o.unary()
+	}
+	return o.power()
+}
+
+// power parses powers
+func (o *exprParser) power() (node *exprNode) {
+	node = o.primary()
+	if o.tok == "^" {
+		o.next()
+		node = &exprNode{kind: exprPow, a: node, b: o.unary()}
+	}
+	return
+}
+
+// primary parses numbers, variables, parameters, function calls and parenthesised expressions
+func (o *exprParser) primary() (node *exprNode) {
+	tok := o.tok
+	switch {
+	case tok == "":
+		o.fail("unexpected end of expression")
+	case o.num:
+		v, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			o.fail("invalid number %q", tok)
+		}
+		o.next()
+		return &exprNode{kind: exprNum, val: v}
+	case tok == "(":
+		o.next()
+		node = o.expr()
+		o.expect(")")
+		return
+	case unicode.IsLetter([]rune(tok)[0]) || tok[0] == '_':
+		o.next()
+		if o.tok == "(" {
+			if _, ok := exprFcns[tok]; !ok {
+				o.fail("cannot find function named %q", tok)
+			}
+			o.next()
+			node = &exprNode{kind: exprFcn, name: tok, a: o.expr()}
+			o.expect(")")
+			return
+		}
+		switch tok {
+		case "t":
+			return &exprNode{kind: exprT}
+		case "x":
+			o.expect("[")
+			if !o.num {
+				o.fail("index of x must be an integer")
+			}
+			i, err := strconv.Atoi(o.tok)
+			if err != nil || i < 0 {
+				o.fail("index of x must be a non-negative integer. %q is invalid", o.tok)
+			}
+			o.next()
+			o.expect("]")
+			return &exprNode{kind: exprX, idx: i}
+		}
+		for i, name := range o.prms {
+			if name == tok {
+				return &exprNode{kind: exprPrm, idx: i, name: tok}
+			}
+		}
+		if tok == "pi" {
+			return &exprNode{kind: exprNum, val: math.Pi}
+		}
+		o.fail("cannot find parameter named %q", tok)
+	}
+	o.fail("unexpected %q", tok)
+	return
+}
+
+// constructors with simplification ////////////////////////////////////////////////////////////////
+
+// isNum tells whether node is the number v
+func (o *exprNode) isNum(v float64) bool {
+	return o.kind == exprNum && o.val == v
+}
+
+// exprN returns a number node
+func exprN(v float64) *exprNode {
+	return &exprNode{kind: exprNum, val: v}
+}
+
+// exprAddS returns a + b simplified
+func exprAddS(a, b *exprNode) *exprNode {
+	switch {
+	case a.kind == exprNum && b.kind == exprNum:
+		return exprN(a.val + b.val)
+	case a.isNum(0):
+		return b
+	case b.isNum(0):
+		return a
+	case b.kind == exprNeg:
+		return exprSubS(a, b.a)
+	case a.kind == exprNeg:
+		return exprSubS(b, a.a)
+	}
+	return &exprNode{kind: exprAdd, a: a, b: b}
+}
+
+// exprSubS returns a - b simplified
+func exprSubS(a, b *exprNode) *exprNode {
+	switch {
+	case a.kind == exprNum && b.kind == exprNum:
+		return exprN(a.val - b.val)
+	case b.isNum(0):
+		return a
+	case a.isNum(0):
+		return exprNegS(b)
+	case b.kind == exprNeg:
+		return exprAddS(a, b.a)
+	}
+	return &exprNode{kind: exprSub, a: a, b: b}
+}
+
+// exprMulS returns a * b simplified
+func exprMulS(a, b *exprNode) *exprNode {
+	switch {
+	case a.kind == exprNum && b.kind == exprNum:
+		return exprN(a.val * b.val)
+	case a.isNum(0) || b.isNum(0):
+		return exprN(0)
+	case a.isNum(1):
+		return b
+	case b.isNum(1):
+		return a
+	case a.isNum(-1):
+		return exprNegS(b)
+	case b.isNum(-1):
+		return exprNegS(a)
+	case a.kind == exprNeg:
+		return exprNegS(exprMulS(a.a, b))
+	case b.kind == exprNeg:
+		return exprNegS(exprMulS(a, b.a))
+	case b.kind == exprNum:
+		return exprMulS(b, a)
+	case a.kind == exprNum && b.kind == exprMul && b.a.kind == exprNum:
+		return exprMulS(exprN(a.val*b.a.val), b.b)
+	}
+	return &exprNode{kind: exprMul, a: a, b: b}
+}
+
+// exprDivS returns a / b simplified
+func exprDivS(a, b *exprNode) *exprNode {
+	switch {
+	case a.kind == exprNum && b.kind == exprNum && b.val != 0:
+		return exprN(a.val / b.val)
+	case a.isNum(0):
+		return exprN(0)
+	case b.isNum(1):
+		return a
+	case a.kind == exprNeg:
+		return exprNegS(exprDivS(a.a, b))
+	case b.kind == exprNeg:
+		return exprNegS(exprDivS(a, b.a))
+	}
+	return &exprNode{kind: exprDiv, a: a, b: b}
+}
+
+// exprPowS returns a ^ b simplified
+func exprPowS(a, b *exprNode) *exprNode {
+	switch {
+	case a.kind == exprNum && b.kind == exprNum:
+		return exprN(math.Pow(a.val, b.val))
+	case b.isNum(0):
+		return exprN(1)
+	case b.isNum(1):
+		return a
+	}
+	return &exprNode{kind: exprPow, a: a, b: b}
+}
+
+// exprNegS returns -a simplified
+func exprNegS(a *exprNode) *exprNode {
+	switch a.kind {
+	case exprNum:
+		return exprN(-a.val)
+	case exprNeg:
+		return a.a
+	}
+	return &exprNode{kind: exprNeg, a: a}
+}
+
+// exprFcnS returns f(a) simplified
+func exprFcnS(name string, a *exprNode) *exprNode {
+	if a.kind == exprNum {
+		return exprN(exprFcns[name](a.val))
+	}
+	return &exprNode{kind: exprFcn, name: name, a: a}
+}
+
+// simplify returns a simplified copy of the tree
+func (o *exprNode) simplify() *exprNode {
+	switch o.kind {
+	case exprAdd:
+		return exprAddS(o.a.simplify(), o.b.simplify())
+	case exprSub:
+		return exprSubS(o.a.simplify(), o.b.simplify())
+	case exprMul:
+		return exprMulS(o.a.simplify(), o.b.simplify())
+	case exprDiv:
+		return exprDivS(o.a.simplify(), o.b.simplify())
+	case exprPow:
+		return exprPowS(o.a.simplify(), o.b.simplify())
+	case exprNeg:
+		return exprNegS(o.a.simplify())
+	case exprFcn:
+		return exprFcnS(o.name, o.a.simplify())
+	}
+	return o
+}
+
+// differentiation /////////////////////////////////////////////////////////////////////////////////
+
+// diff returns the derivative of the tree w.r.t t (if idx < 0) or w.r.t x[idx]
+//  NOTE: the derivatives of abs, sign and heav are taken as sign, 0 (delta) and 0 (delta),
+//        respectively; i.e. they are valid away from the discontinuities only
+func (o *exprNode) diff(idx int) *exprNode {
+	switch o.kind {
+	case exprNum, exprPrm:
+		return exprN(0)
+	case exprT:
+		if idx < 0 {
+			return exprN(1)
+		}
+		return exprN(0)
+	case exprX:
+		if idx == o.idx {
+			return exprN(1)
+		}
+		return exprN(0)
+	case exprAdd:
+		return exprAddS(o.a.diff(idx), o.b.diff(idx))
+	case exprSub:
+		return exprSubS(o.a.diff(idx), o.b.diff(idx))
+	case exprNeg:
+		return exprNegS(o.a.diff(idx))
+	case exprMul:
+		return exprAddS(exprMulS(o.a.diff(idx), o.b), exprMulS(o.a, o.b.diff(idx)))
+	case exprDiv: // (a/b)' = a'/b - a b'/b²
+		da, db := o.a.diff(idx), o.b.diff(idx)
+		return exprSubS(exprDivS(da, o.b), exprDivS(exprMulS(o.a, db), exprPowS(o.b, exprN(2))))
+	case exprPow:
+		da, db := o.a.diff(idx), o.b.diff(idx)
+		if db.isNum(0) { // (aᵇ)' = b a^(b-1) a'
+			return exprMulS(exprMulS(o.b, exprPowS(o.a, exprSubS(o.b, exprN(1)))), da)
+		} // (aᵇ)' = aᵇ (b' log(a) + b a'/a)
+		return exprMulS(o, exprAddS(exprMulS(db, exprFcnS("log", o.a)), exprDivS(exprMulS(o.b, da), o.a)))
+	case exprFcn:
+		da := o.a.diff(idx)
+		if da.isNum(0) {
+			return da
+		}
+		a := o.a
+		var df *exprNode
+		switch o.name {
+		case "sin":
+			df = exprFcnS("cos", a)
+		case "cos":
+			df = exprNegS(exprFcnS("sin", a))
+		case "tan":
+			df = exprAddS(exprN(1), exprPowS(o, exprN(2)))
+		case "asin":
+			df = exprDivS(exprN(1), exprFcnS("sqrt", exprSubS(exprN(1), exprPowS(a, exprN(2)))))
+		case "acos":
+			df = exprNegS(exprDivS(exprN(1), exprFcnS("sqrt", exprSubS(exprN(1), exprPowS(a, exprN(2))))))
+		case "atan":
+			df = exprDivS(exprN(1), exprAddS(exprN(1), exprPowS(a, exprN(2))))
+		case "sinh":
+			df = exprFcnS("cosh", a)
+		case "cosh":
+			df = exprFcnS("sinh", a)
+		case "tanh":
+			df = exprSubS(exprN(1), exprPowS(o, exprN(2)))
+		case "exp":
+			df = o
+		case "log":
+			return exprDivS(da, a)
+		case "sqrt":
+			return exprDivS(da, exprMulS(exprN(2), o))
+		case "abs":
+			df = exprFcnS("sign", a)
+		case "sign", "heav":
+			df = exprFcnS("delta", a)
+		case "delta":
+			df = exprN(0)
+		}
+		return exprMulS(df, da)
+	}
+	chk.Panic("expr: cannot differentiate node of kind %d\n", o.kind)
+	return nil
+}
+
+// maxX returns the maximum index of x in the tree or -1 if x is not present
+func (o *exprNode) maxX() (res int) {
+	res = -1
+	if o.kind == exprX {
+		res = o.idx
+	}
+	if o.a != nil {
+		if i := o.a.maxX(); i > res {
+			res = i
+		}
+	}
+	if o.b != nil {
+		if i := o.b.maxX(); i > res {
+			res = i
+		}
+	}
+	return
+}
+
+// String returns the expression corresponding to the tree
+func (o *exprNode) String() string {
+	return o.str(0)
+}
+
+// str returns the expression with parentheses if the precedence of the node is lower than prec
+//  precedences: 1 = {+, -}, 2 = {*, /}, 3 = unary minus, 4 = ^, 5 = primary
+func (o *exprNode) str(prec int) (l string) {
+	var p int
+	switch o.kind {
+	case exprNum:
+		if o.val < 0 {
+			p, l = 3, strconv.FormatFloat(o.val, 'g', -1, 64)
+		} else {
+			p, l = 5, strconv.FormatFloat(o.val, 'g', -1, 64)
+		}
+	case exprT:
+		p, l = 5, "t"
+	case exprX:
+		p, l = 5, io.Sf("x[%d]", o.idx)
+	case exprPrm:
+		p, l = 5, o.name
+	case exprAdd: // associative: a+(b+c) is printed as a+b+c
+		if o.b.kind == exprAdd {
+			p, l = 1, o.a.str(1)+"+"+o.b.str(1)
+		} else {
+			p, l = 1, o.a.str(1)+"+"+o.b.str(2)
+		}
+	case exprSub:
+		p, l = 1, o.a.str(1)+"-"+o.b.str(2)
+	case exprMul: // associative: a*(b*c) is printed as a*b*c
+		if o.b.kind == exprMul {
+			p, l = 2, o.a.str(2)+"*"+o.b.str(2)
+		} else {
+			p, l = 2, o.a.str(2)+"*"+o.b.str(3)
+		}
+	case exprDiv:
+		p, l = 2, o.a.str(2)+"/"+o.b.str(3)
+	case exprNeg: // -(a*b) and -(a/b) are printed as -a*b and -a/b
+		if o.a.kind == exprMul || o.a.kind == exprDiv {
+			p, l = 2, "-"+o.a.str(2)
+		} else {
+			p, l = 3, "-"+o.a.str(3)
+		}
+	case exprPow:
+		p, l = 4, o.a.str(5)+"^"+o.b.str(3)
+	case exprFcn:
+		p, l = 5, o.name+"("+o.a.str(0)+")"
+	}
+	if p < prec {
+		return "(" + l + ")"
+	}
+	return
+}
+
+// bytecode ////////////////////////////////////////////////////////////////////////////////////////
+
+// operation codes of the stack machine
+const (
+	opNum  = iota // push number
+	opT           // push t
+	opX           // push x[i]
+	opPrm         // push parameter
+	opAdd         // pop b, a; push a + b
+	opSub         // pop b, a; push a - b
+	opMul         // pop b, a; push a * b
+	opDiv         // pop b, a; push a / b
+	opPow         // pop b, a; push a ^ b
+	opPowi        // pop a; push a ^ i (integer exponent)
+	opNeg         // pop a; push -a
+	opFcn         // pop a; push f(a)
+)
+
+// exprInstr holds a bytecode instruction
+type exprInstr struct {
+	op  int                   // operation code
+	idx int                   // index of x or parameter; integer exponent
+	val float64               // number
+	fcn func(float64) float64 // function
+}
+
+// exprCode holds the bytecode of an expression for a stack machine
+type exprCode struct {
+	code  []exprInstr // instructions
+	depth int         // maximum stack depth
+}
+
+// exprCompile compiles the tree into bytecode
+func exprCompile(node *exprNode) (o *exprCode) {
+	o = new(exprCode)
+	o.emit(node, 0)
+	return
+}
+
+// emit generates the instructions of node in post-order; sp is the stack size before the node
+func (o *exprCode) emit(node *exprNode, sp int) {
+	if sp+1 > o.depth {
+		o.depth = sp + 1
+	}
+	switch node.kind {
+	case exprNum:
+		o.code = append(o.code, exprInstr{op: opNum, val: node.val})
+	case exprT:
+		o.code = append(o.code, exprInstr{op: opT})
+	case exprX:
+		o.code = append(o.code, exprInstr{op: opX, idx: node.idx})
+	case exprPrm:
+		o.code = append(o.code, exprInstr{op: opPrm, idx: node.idx})
+	case exprNeg:
+		o.emit(node.a, sp)
+		o.code = append(o.code, exprInstr{op: opNeg})
+	case exprFcn:
+		o.emit(node.a, sp)
+		o.code = append(o.code, exprInstr{op: opFcn, fcn: exprFcns[node.name]})
+	case exprPow:
+		o.emit(node.a, sp)
+		if b := node.b; b.kind == exprNum && b.val == math.Trunc(b.val) && math.Abs(b.val) <= 64 {
+			o.code = append(o.code, exprInstr{op: opPowi, idx: int(b.val)})
+			return
+		}
+		o.emit(node.b, sp+1)
+		o.code = append(o.code, exprInstr{op: opPow})
+	default:
+		o.emit(node.a, sp)
+		o.emit(node.b, sp+1)
+		o.code = append(o.code, exprInstr{op: opAdd + node.kind - exprAdd})
+	}
+}
+
+// run evaluates the bytecode
+func (o *exprCode) run(t float64, x, prms []float64) float64 {
+	var buf [16]float64
+	var s []float64
+	if o.depth <= len(buf) {
+		s = buf[:]
+	} else {
+		s = make([]float64, o.depth)
+	}
+	n := 0
+	for _, c := range o.code {
+		switch c.op {
+		case opNum:
+			s[n] = c.val
+			n++
+		case opT:
+			s[n] = t
+			n++
+		case opX:
+			s[n] = x[c.idx]
+			n++
+		case opPrm:
+			s[n] = prms[c.idx]
+			n++
+		case opAdd:
+			n--
+			s[n-1] += s[n]
+		case opSub:
+			n--
+			s[n-1] -= s[n]
+		case opMul:
+			n--
+			s[n-1] *= s[n]
+		case opDiv:
+			n--
+			s[n-1] /= s[n]
+		case opPow:
+			n--
+			s[n-1] = math.Pow(s[n-1], s[n])
+		case opPowi:
+			s[n-1] = exprPowi(s[n-1], c.idx)
+		case opNeg:
+			s[n-1] = -s[n-1]
+		case opFcn:
+			s[n-1] = c.fcn(s[n-1])
+		}
+	}
+	return s[0]
+}
+
+// exprPowi computes xⁿ for integer n by repeated squaring
+func exprPowi(x float64, n int) (res float64) {
+	if n < 0 {
+		return 1.0 / exprPowi(x, -n)
+	}
+	res = 1
+	for n > 0 {
+		if n&1 == 1 {
+			res *= x
+		}
+		x *= x
+		n >>= 1
+	}
+	return
+}
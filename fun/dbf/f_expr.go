@@ -0,0 +1,101 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbf
+
+import "github.com/cpmech/gosl/chk"
+
+// Expr implements y = F(t, x) given by a mathematical expression; e.g. "A*sin(w*t)*exp(-x[0])"
+//  The expression is given in the Extra field of the parameter named "expr"; all other parameters
+//  may be referenced by name in the expression. Since the parameters are connected, changing
+//  their values with P.Set updates the function.
+//
+//  Available:
+//    variables  -- t and x[0], x[1], x[2], ...
+//    operators  -- + - * / ^ (or **) and parentheses
+//    functions  -- sin, cos, tan, asin, acos, atan, sinh, cosh, tanh, exp, log, sqrt, abs, sign,
+//                  heav (Heaviside) and delta (taken as zero; derivative of sign and heav)
+//    constants  -- pi
+//
+//  G, H and Grad are obtained by symbolic differentiation of the expression. All expressions are
+//  simplified and compiled into bytecode evaluated by a small stack machine.
+//
+//  Example:
+//    o := dbf.New("expr", []*dbf.P{
+//        {N: "expr", Extra: "A*sin(w*t)*exp(-x[0])"},
+//        {N: "A", V: 2},
+//        {N: "w", V: 3},
+//    })
+type Expr struct {
+	Str  string      // expression
+	prms []float64   // values of parameters
+	f    *exprCode   // F(t, x)
+	g    *exprCode   // G(t, x)
+	h    *exprCode   // H(t, x)
+	grad []*exprCode // Grad(t, x); len(grad) = 1 + max index of x in expression
+}
+
+// set allocators database
+func init() {
+	allocators["expr"] = func() T { return new(Expr) }
+}
+
+// Init initialises the function
+func (o *Expr) Init(prms Params) {
+
+	// expression and parameters
+	p := prms.Find("expr")
+	if p == nil {
+		chk.Panic("expr function requires a parameter named \"expr\" with the expression in Extra\n")
+	}
+	o.Str = p.Extra
+	var names []string
+	for _, q := range prms {
+		if q.N != "expr" {
+			names = append(names, q.N)
+		}
+	}
+	o.prms = make([]float64, len(names))
+	for i, name := range names {
+		prms.Connect(&o.prms[i], name, "expr function")
+	}
+
+	// parse and differentiate
+	f := exprParse(o.Str, names).simplify()
+	g := f.diff(-1)
+	h := g.diff(-1)
+	o.f = exprCompile(f)
+	o.g = exprCompile(g)
+	o.h = exprCompile(h)
+	o.grad = make([]*exprCode, f.maxX()+1)
+	for i := range o.grad {
+		o.grad[i] = exprCompile(f.diff(i))
+	}
+}
+
+// F returns y = F(t, x)
+func (o *Expr) F(t float64, x []float64) float64 {
+	return o.f.run(t, x, o.prms)
+}
+
+// G returns ∂y/∂t_cteX = G(t, x)
+func (o *Expr) G(t float64, x []float64) float64 {
+	return o.g.run(t, x, o.prms)
+}
+
+// H returns ∂²y/∂t²_cteX = H(t, x)
+func (o *Expr) H(t float64, x []float64) float64 {
+	return o.h.run(t, x, o.prms)
+}
+
+// Grad returns ∇F = ∂y/∂x = Grad(t, x)
+func (o *Expr) Grad(v []float64, t float64, x []float64) {
+	for i := 0; i < len(v); i++ {
+		if i < len(o.grad) {
+			v[i] = o.grad[i].run(t, x, o.prms)
+		} else {
+			v[i] = 0
+		}
+	}
+}
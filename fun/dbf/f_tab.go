@@ -0,0 +1,219 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/utl"
+)
+
+// Tab implements a smooth function y(t) interpolating tabulated data; e.g. measured earthquake or
+// load records
+//
+//  y(t) = yshift + yscale ⋅ p(τ)     with     τ = (t - tshift) / tscale
+//
+//  where p is the interpolant of the data points (tᵢ, yᵢ). With splines, G and H are continuous.
+//  Outside the data range, the first and last values are held constant (G = H = 0), unless the
+//  function is periodic.
+//
+//  Parameters:
+//    type     -- [Extra] interpolator: "lin", "spline" (natural; default), "spline-nak", "pchip"
+//                or "akima". See fun.DataInterp
+//    file     -- [Extra] CSV or whitespace separated file with the data. Empty lines, comments
+//                (starting with #, % or //) and header lines (not starting with a number) are
+//                skipped. Columns may be separated by spaces, tabs, commas or semicolons
+//    tcol     -- [V] column with t-values in file (default 0)
+//    ycol     -- [V] column with y-values in file (default 1; or 0 if dt is given)
+//    t, y     -- [Extra] t- and y-values given as strings; e.g. "0 0.1 0.2" (instead of file)
+//    dt       -- [V] constant time step: tᵢ = i⋅dt (then, t-values are not required)
+//    periodic -- [V] flag: if V > 0, the data is repeated with period = tmax - tmin
+//    period   -- [V] repeat with the given period ≥ tmax - tmin (constant values in the gap)
+//    tshift   -- [V] time shift (delay) (default 0)
+//    tscale   -- [V] time scale; e.g. to stretch records (default 1)
+//    yshift   -- [V] y shift (default 0)
+//    yscale   -- [V] y scale; e.g. to convert units (default 1)
+//
+//  NOTE: for periodic functions, the derivatives are continuous across periods only if the data
+//        is itself periodic and the interpolator is local ("lin", "pchip" or "akima") or if the
+//        end slopes happen to match
+//
+//  NOTE: the interpolator keeps track of the last interval; thus F, G and H must not be called
+//        concurrently
+type Tab struct {
+
+	// parameters
+	Tshift float64 // time shift
+	Tscale float64 // time scale
+	Yshift float64 // y shift
+	Yscale float64 // y scale
+	Period float64 // period (≤ 0 means not periodic)
+
+	// derived
+	itp  *fun.DataInterp // interpolator
+	tmin float64         // first t of data
+	tmax float64         // last t of data
+}
+
+// set allocators database
+func init() {
+	allocators["tab"] = func() T { return new(Tab) }
+}
+
+// Init initialises the function
+func (o *Tab) Init(prms Params) {
+
+	// modifiers
+	o.Tscale, o.Yscale = 1, 1
+	var periodic float64
+	e := prms.ConnectSetOpt(
+		[]*float64{&o.Tshift, &o.Tscale, &o.Yshift, &o.Yscale, &o.Period, &periodic},
+		[]string{"tshift", "tscale", "yshift", "yscale", "period", "periodic"},
+		[]bool{true, true, true, true, true, true},
+		"tab function",
+	)
+	if e != "" {
+		chk.Panic("%v\n", e)
+	}
+	if o.Tscale == 0 {
+		chk.Panic("tab: tscale must not be zero\n")
+	}
+
+	// interpolator type
+	itype := "spline"
+	if p := prms.Find("type"); p != nil {
+		itype = p.Extra
+	}
+	switch itype {
+	case "lin", "spline", "spline-nak", "pchip", "akima":
+	default:
+		chk.Panic("tab: interpolator type %q is invalid\n", itype)
+	}
+
+	// data
+	var T, Y []float64
+	pdt := prms.Find("dt")
+	if p := prms.Find("file"); p != nil {
+		tcol, ycol := 0, 1
+		if pdt != nil {
+			ycol = 0
+		}
+		if q := prms.Find("tcol"); q != nil {
+			tcol = int(q.V)
+		}
+		if q := prms.Find("ycol"); q != nil {
+			ycol = int(q.V)
+		}
+		rows := readTabFile(p.Extra)
+		for i, row := range rows {
+			if ycol >= len(row) || (pdt == nil && tcol >= len(row)) {
+				chk.Panic("tab: row %d of file %q has %d columns only\n", i, p.Extra, len(row))
+			}
+			if pdt == nil {
+				T = append(T, row[tcol])
+			}
+			Y = append(Y, row[ycol])
+		}
+	} else {
+		if p := prms.Find("t"); p != nil {
+			T = utl.FromString(p.Extra)
+		}
+		if p := prms.Find("y"); p != nil {
+			Y = utl.FromString(p.Extra)
+		}
+	}
+	if pdt != nil {
+		if pdt.V <= 0 {
+			chk.Panic("tab: dt must be positive. dt = %g is invalid\n", pdt.V)
+		}
+		T = make([]float64, len(Y))
+		for i := range T {
+			T[i] = float64(i) * pdt.V
+		}
+	}
+	if len(T) != len(Y) {
+		chk.Panic("tab: number of t-values must be equal to the number of y-values. %d != %d\n", len(T), len(Y))
+	}
+	if len(T) < 2 {
+		chk.Panic("tab: at least 2 data points are required. %d is invalid\n", len(T))
+	}
+
+	// interpolator
+	o.itp = fun.NewDataInterp(itype, 0, T, Y)
+	o.itp.Extrap = "const"
+	o.tmin, o.tmax = T[0], T[len(T)-1]
+	if periodic > 0 {
+		o.Period = o.tmax - o.tmin
+	}
+	if o.Period > 0 && o.Period < o.tmax-o.tmin {
+		chk.Panic("tab: period must be greater than or equal to tmax - tmin = %g. %g is invalid\n", o.tmax-o.tmin, o.Period)
+	}
+}
+
+// F returns y = F(t, x)
+func (o *Tab) F(t float64, x []float64) float64 {
+	return o.Yshift + o.Yscale*o.itp.P(o.tau(t))
+}
+
+// G returns ∂y/∂t_cteX = G(t, x)
+func (o *Tab) G(t float64, x []float64) float64 {
+	return o.Yscale * o.itp.G(o.tau(t)) / o.Tscale
+}
+
+// H returns ∂²y/∂t²_cteX = H(t, x)
+func (o *Tab) H(t float64, x []float64) float64 {
+	return o.Yscale * o.itp.H(o.tau(t)) / (o.Tscale * o.Tscale)
+}
+
+// Grad returns ∇F = ∂y/∂x = Grad(t, x)
+func (o *Tab) Grad(v []float64, t float64, x []float64) {
+	setvzero(v)
+	return
+}
+
+// tau computes the shifted and scaled time, reduced to the first period if periodic
+func (o *Tab) tau(t float64) (τ float64) {
+	τ = (t - o.Tshift) / o.Tscale
+	if o.Period > 0 {
+		s := τ - o.tmin
+		τ = o.tmin + s - o.Period*math.Floor(s/o.Period)
+	}
+	return
+}
+
+// readTabFile reads a table of numbers from a CSV or whitespace separated file
+func readTabFile(fn string) (rows [][]float64) {
+	io.ReadLines(fn, func(idx int, line string) (stop bool) {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == '%' || strings.HasPrefix(line, "//") {
+			return
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ';' || r == ' ' || r == '\t'
+		})
+		if len(fields) == 0 {
+			return
+		}
+		if _, err := strconv.ParseFloat(fields[0], 64); err != nil { // header
+			return
+		}
+		row := make([]float64, len(fields))
+		for i, s := range fields {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				chk.Panic("tab: cannot parse %q in line %d of file %q\n", s, idx+1, fn)
+			}
+			row[i] = v
+		}
+		rows = append(rows, row)
+		return
+	})
+	return
+}
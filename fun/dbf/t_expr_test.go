@@ -0,0 +1,130 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func Test_expr01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("expr01. parser and simplification")
+
+	prms := []string{"A", "w", "λ"}
+	for _, c := range [][]string{
+		{"1 + 2*3", "7"},
+		{"2^3^2", "512"},
+		{"-2^2", "-4"},
+		{"2**-1", "0.5"},
+		{"1.5e-1*A + 0*w", "0.15*A"},
+		{"A*sin(w*t)*exp(-x[0])", "A*sin(w*t)*exp(-x[0])"},
+		{"(A - λ) - (w - t)", "A-λ-(w-t)"},
+		{"A/(w*t)", "A/(w*t)"},
+		{"(-A)^2 + x[1]^λ", "(-A)^2+x[1]^λ"},
+		{"cos(pi) * 1 * t", "-t"},
+	} {
+		res := exprParse(c[0], prms).simplify().String()
+		io.Pforan("%-24s => %s\n", c[0], res)
+		chk.String(tst, res, c[1])
+	}
+
+	for _, c := range [][]string{
+		{"A*sin(w*t)", "A*cos(w*t)*w", "-A*sin(w*t)*w*w"},
+		{"x[0]*t^3", "x[0]*3*t^2", "x[0]*6*t"},
+		{"exp(-λ*t)", "-exp(-λ*t)*λ", "exp(-λ*t)*λ*λ"},
+	} {
+		f := exprParse(c[0], prms).simplify()
+		g := f.diff(-1)
+		h := g.diff(-1)
+		io.Pforan("%-12s => g = %-16s h = %s\n", c[0], g, h)
+		chk.String(tst, g.String(), c[1])
+		chk.String(tst, h.String(), c[2])
+	}
+
+	for _, str := range []string{"", "1 +", "2*(t", "foo(t)", "b*t", "x[a]", "x[1", "1 2", "sin t"} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					tst.Errorf("expression %q should have failed\n", str)
+				}
+			}()
+			exprParse(str, prms)
+		}()
+	}
+}
+
+func Test_expr02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("expr02. time function")
+
+	A, w := 2.0, 3.0
+	o := New("expr", []*P{
+		{N: "expr", Extra: "A*sin(w*t)*exp(-x[0]) + t^2/2"},
+		{N: "A", V: A},
+		{N: "w", V: w},
+	})
+
+	x := []float64{0.5, 0}
+	t := 0.7
+	chk.Float64(tst, "F", 1e-15, o.F(t, x), A*math.Sin(w*t)*math.Exp(-x[0])+t*t/2)
+	chk.Float64(tst, "G", 1e-15, o.G(t, x), A*w*math.Cos(w*t)*math.Exp(-x[0])+t)
+	chk.Float64(tst, "H", 1e-14, o.H(t, x), -A*w*w*math.Sin(w*t)*math.Exp(-x[0])+1)
+
+	sktol := 1e-10
+	dtol := 1e-8
+	dtol2 := 1e-8
+	ver := chk.Verbose
+	CheckDerivT(tst, o, 0.0, 3.0, x, 11, nil, sktol, dtol, dtol2, ver)
+
+	// connected parameters
+	prms := Params{{N: "expr", Extra: "a*t"}, {N: "a", V: 1}}
+	o2 := New("expr", prms)
+	chk.Float64(tst, "a*t", 1e-15, o2.F(2, nil), 2)
+	prms[1].Set(3)
+	chk.Float64(tst, "a*t", 1e-15, o2.F(2, nil), 6)
+	chk.Float64(tst, "d(a*t)/dt", 1e-15, o2.G(2, nil), 3)
+}
+
+func Test_expr03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("expr03. space function")
+
+	o := New("expr", []*P{
+		{N: "expr", Extra: "sqrt((x[0]-xc)^2 + (x[1]-yc)^2) - r + a*log(1 + x[0]^2)*tanh(x[1]/b) + atan(x[0]*x[1])"},
+		{N: "xc", V: 0.5},
+		{N: "yc", V: 0.5},
+		{N: "r", V: 0.5},
+		{N: "a", V: 0.3},
+		{N: "b", V: 2},
+	})
+
+	tcte := 0.0
+	xmin := []float64{-1, -1}
+	xmax := []float64{2, 2}
+	np := 4
+	sktol := 1e-10
+	dtol := 1e-8
+	ver := chk.Verbose
+	CheckDerivX(tst, o, tcte, xmin, xmax, np, nil, sktol, dtol, ver)
+
+	// 3D gradient with x[2] absent
+	v := []float64{-1, -1, -1}
+	o.Grad(v, 0, []float64{1, 1, 1})
+	chk.Float64(tst, "v[2]", 1e-15, v[2], 0)
+
+	// time and space with general power
+	o = New("expr", []*P{
+		{N: "expr", Extra: "(1 + t^2)^(x[0]/2) * cos(pi*x[1]) / (2 + sin(t))"},
+	})
+	CheckDerivT(tst, o, 0.0, 2.0, []float64{0.3, 0.2}, 7, nil, sktol, dtol, 1e-7, ver)
+	CheckDerivX(tst, o, 0.5, []float64{0.1, 0.1}, []float64{1, 1}, np, nil, sktol, dtol, ver)
+}
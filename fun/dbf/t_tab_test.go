@@ -0,0 +1,133 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbf
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/plt"
+)
+
+func Test_tab01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("tab01. tabulated data: spline and pchip")
+
+	T := []float64{0, 0.5, 1.2, 2, 2.5, 3.5, 4}
+	Y := []float64{0, 0.8, 1.0, 0.2, -0.5, -0.1, 0.3}
+	for _, itype := range []string{"spline", "spline-nak", "pchip", "akima"} {
+		o := New("tab", []*P{
+			{N: "type", Extra: itype},
+			{N: "t", Extra: "0 0.5 1.2 2 2.5 3.5 4"},
+			{N: "y", Extra: "0 0.8 1.0 0.2 -0.5 -0.1 0.3"},
+		})
+		for i, t := range T {
+			chk.Float64(tst, itype+": F(ti)", 1e-15, o.F(t, nil), Y[i])
+		}
+		chk.Float64(tst, itype+": F(before)", 1e-15, o.F(-1, nil), Y[0])
+		chk.Float64(tst, itype+": F(after)", 1e-15, o.F(5, nil), Y[len(Y)-1])
+		chk.Float64(tst, itype+": G(after)", 1e-15, o.G(5, nil), 0)
+		chk.Float64(tst, itype+": H(after)", 1e-15, o.H(5, nil), 0)
+
+		// H is discontinuous at the data points with pchip and akima
+		tskip := []float64{T[0], T[len(T)-1]}
+		if itype == "pchip" || itype == "akima" {
+			tskip = T
+		}
+		CheckDerivT(tst, o, -0.4, 4.4, nil, 25, tskip, 1e-10, 1e-8, 1e-5, chk.Verbose)
+		if chk.Verbose {
+			plt.Reset(false, nil)
+			PlotT(o, "/tmp/gosl/fun", "tab01-"+itype, -0.5, 4.5, nil, 201)
+		}
+	}
+}
+
+func Test_tab02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("tab02. tabulated data: file and modifiers")
+
+	var buf bytes.Buffer
+	io.Ff(&buf, `# load record
+time,load,other
+0.0, 1.0, 9
+1.0, 3.0, 9
+
+2.0, 2.0, 9
+3.0, 4.0, 9
+`)
+	io.WriteFileD("/tmp/gosl/fun", "tab02.csv", &buf)
+	ts, sc, ys, yc := 0.5, 2.0, 10.0, 3.0
+	o := New("tab", []*P{
+		{N: "file", Extra: "/tmp/gosl/fun/tab02.csv"},
+		{N: "tshift", V: ts},
+		{N: "tscale", V: sc},
+		{N: "yshift", V: ys},
+		{N: "yscale", V: yc},
+	})
+	p := fun.NewDataInterp("spline", 0, []float64{0, 1, 2, 3}, []float64{1, 3, 2, 4})
+	for _, t := range []float64{0.5, 1.3, 2.5, 4.9, 6.5} {
+		τ := (t - ts) / sc
+		chk.Float64(tst, io.Sf("F(%g)", t), 1e-15, o.F(t, nil), ys+yc*p.P(τ))
+		chk.Float64(tst, io.Sf("G(%g)", t), 1e-15, o.G(t, nil), yc*p.G(τ)/sc)
+		chk.Float64(tst, io.Sf("H(%g)", t), 1e-15, o.H(t, nil), yc*p.H(τ)/(sc*sc))
+	}
+
+	// constant time step and second column
+	buf.Reset()
+	io.Ff(&buf, "1 0.0\n2 0.5\n3 1.0\n4 0.5\n5 0.0\n")
+	io.WriteFileD("/tmp/gosl/fun", "tab02.dat", &buf)
+	o = New("tab", []*P{
+		{N: "type", Extra: "lin"},
+		{N: "file", Extra: "/tmp/gosl/fun/tab02.dat"},
+		{N: "dt", V: 0.1},
+		{N: "ycol", V: 1},
+	})
+	chk.Float64(tst, "F(0.25)", 1e-15, o.F(0.25, nil), 0.75)
+	chk.Float64(tst, "G(0.25)", 1e-14, o.G(0.25, nil), -5)
+}
+
+func Test_tab03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("tab03. tabulated data: periodic")
+
+	// one period of sin(2πt) sampled with akima
+	n := 41
+	Y := ""
+	for i := 0; i < n; i++ {
+		Y += io.Sf(" %g", math.Sin(2*math.Pi*float64(i)/float64(n-1)))
+	}
+	o := New("tab", []*P{
+		{N: "type", Extra: "akima"},
+		{N: "y", Extra: Y},
+		{N: "dt", V: 1.0 / float64(n-1)},
+		{N: "periodic", V: 1},
+	})
+	for _, t := range []float64{0.13, 0.5, 0.77} {
+		for _, k := range []float64{-2, 1, 3} {
+			chk.Float64(tst, io.Sf("F(%g)", t+k), 1e-14, o.F(t+k, nil), o.F(t, nil))
+			chk.Float64(tst, io.Sf("G(%g)", t+k), 1e-12, o.G(t+k, nil), o.G(t, nil))
+		}
+		chk.Float64(tst, io.Sf("F(%g) ≈ sin", t), 1e-4, o.F(t, nil), math.Sin(2*math.Pi*t))
+	}
+	chk.Float64(tst, "G continuity", 1e-6, o.G(1-1e-9, nil), o.G(1+1e-9, nil))
+
+	// period with gap
+	o = New("tab", []*P{
+		{N: "type", Extra: "lin"},
+		{N: "t", Extra: "0 1"},
+		{N: "y", Extra: "0 1"},
+		{N: "period", V: 3},
+	})
+	chk.Float64(tst, "F(3.5)", 1e-15, o.F(3.5, nil), 0.5)
+	chk.Float64(tst, "F(5.0)", 1e-15, o.F(5.0, nil), 1.0)
+	chk.Float64(tst, "F(-0.5)", 1e-15, o.F(-0.5, nil), 1.0)
+}
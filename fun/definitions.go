@@ -122,3 +122,19 @@ type Tt func(f, t *la.Triplet)
 //   Returns:
 //     scalar
 type Svs func(v la.Vector, s float64) float64
+
+// Vvs defines a vector function f(v,s) of a vector and a scalar
+//   Input:
+//     v -- input vector
+//     s -- the scalar
+//   Output:
+//     f -- output vector
+type Vvs func(f, v la.Vector, s float64)
+
+// Mvs defines a matrix function f(v,s) of a vector and a scalar
+//   Input:
+//     v -- input vector
+//     s -- the scalar
+//   Output:
+//     f -- output matrix
+type Mvs func(f *la.Matrix, v la.Vector, s float64)
@@ -7,7 +7,7 @@ package fun
 import (
 	"math"
 
-	"github.com/cpmech/gosl/fun/fftw"
+	"github.com/cpmech/gosl/fun/fft"
 )
 
 // Dft1d computes the discrete Fourier transform (DFT) in 1D.
@@ -25,10 +25,11 @@ import (
 //
 //   NOTE: (1) the inverse operation does not divide by N
 //         (2) ideally, N=len(data) is an integer power of 2.
-//         (3) using FFTW: http://fftw.org/fftw3_doc/What-FFTW-Really-Computes.html
+//         (3) using the pure-Go fun/fft package (no cgo); the definitions are the same as FFTW:
+//             http://fftw.org/fftw3_doc/What-FFTW-Really-Computes.html
 //
 func Dft1d(data []complex128, inverse bool) {
-	plan := fftw.NewPlan1d(data, inverse, false)
+	plan := fft.NewPlan1d(data, inverse, false)
 	defer plan.Free()
 	plan.Execute()
 	return
@@ -0,0 +1,51 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// Erfcx computes the scaled complementary error function
+//
+//   erfcx(x) = exp(x²) ⋅ erfc(x)
+//
+//   which does not underflow for large positive x; e.g. erfcx(x) ≈ 1/(x√π) for x → ∞.
+//   The product exp(x²) erfc(x) is evaluated with x² split into exact high and low parts to
+//   avoid the amplification of rounding errors for moderate x; the asymptotic expansion is
+//   used for x ≥ 26. For x < 0, erfcx(x) = 2 exp(x²) - erfcx(-x) (overflows for x < -26.6)
+//
+//   NOTE: the inverse error functions are available in the standard library: math.Erfinv and
+//         math.Erfcinv
+func Erfcx(x float64) float64 {
+	if math.IsNaN(x) {
+		return x
+	}
+	if x < 0 {
+		return 2.0*expx2(x) - Erfcx(-x)
+	}
+	if x < 26 {
+		return expx2(x) * math.Erfc(x)
+	}
+	if math.IsInf(x, 1) {
+		return 0
+	}
+	// asymptotic expansion: 1/(x√π) Σ (-1)ᵏ (2k-1)!! / (2x²)ᵏ
+	z := 1.0 / (2.0 * x * x)
+	sum, term := 1.0, 1.0
+	for k := 1; k < 20; k++ {
+		term *= -float64(2*k-1) * z
+		sum += term
+		if math.Abs(term) < 1e-17 {
+			break
+		}
+	}
+	return sum / (x * math.SqrtPi)
+}
+
+// expx2 computes exp(x²) accurately by splitting x² = hi + lo
+func expx2(x float64) float64 {
+	hi := x * x
+	lo := math.FMA(x, x, -hi)
+	return math.Exp(hi) * (1.0 + lo)
+}
@@ -0,0 +1,135 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// EulerGamma is the Euler-Mascheroni constant γ
+const EulerGamma = 0.577215664901532860606512090082
+
+// ExpIntE computes the exponential integral Eₙ(x) for n ≥ 0 and x ≥ 0
+//
+//             ∞  exp(-x t)
+//   Eₙ(x) =  ∫  ───────── dt        E₀(x) = exp(-x)/x
+//            1      tⁿ
+//
+//   The series expansion is used for x ≤ 1 and the continued fraction (modified Lentz's method)
+//   otherwise [1]. Eₙ(0) = 1/(n-1) for n > 1 and +Inf for n = 0 or 1.
+//   See GammaP for references
+func ExpIntE(n int, x float64) float64 {
+	if n < 0 || x < 0 {
+		chk.Panic("n and x must be non-negative. n=%d and x=%g are invalid\n", n, x)
+	}
+	nm1 := n - 1
+	if n == 0 {
+		return math.Exp(-x) / x
+	}
+	if x == 0 {
+		if n == 1 {
+			return math.Inf(1)
+		}
+		return 1.0 / float64(nm1)
+	}
+	if x > 1.0 {
+		b := x + float64(n)
+		c := 1.0 / spFpMin
+		d := 1.0 / b
+		h := d
+		for i := 1; i <= spMaxIt; i++ {
+			a := -float64(i) * float64(nm1+i)
+			b += 2.0
+			d = 1.0 / (a*d + b)
+			c = b + a/c
+			del := c * d
+			h *= del
+			if math.Abs(del-1.0) <= spEps {
+				return h * math.Exp(-x)
+			}
+		}
+		chk.Panic("continued fraction of exponential integral failed to converge. n=%d, x=%g\n", n, x)
+	}
+	var ans float64
+	if nm1 != 0 {
+		ans = 1.0 / float64(nm1)
+	} else {
+		ans = -math.Log(x) - EulerGamma
+	}
+	fact := 1.0
+	for i := 1; i <= spMaxIt; i++ {
+		fact *= -x / float64(i)
+		var del float64
+		if i != nm1 {
+			del = -fact / float64(i-nm1)
+		} else {
+			ψ := -EulerGamma
+			for ii := 1; ii <= nm1; ii++ {
+				ψ += 1.0 / float64(ii)
+			}
+			del = fact * (-math.Log(x) + ψ)
+		}
+		ans += del
+		if math.Abs(del) < math.Abs(ans)*spEps {
+			return ans
+		}
+	}
+	chk.Panic("series of exponential integral failed to converge. n=%d, x=%g\n", n, x)
+	return 0
+}
+
+// ExpIntE1 computes the exponential integral E₁(x) = ∫ₓ^∞ exp(-t)/t dt for x > 0
+func ExpIntE1(x float64) float64 {
+	return ExpIntE(1, x)
+}
+
+// ExpIntEi computes the exponential integral Ei(x) (Cauchy principal value)
+//
+//               x  exp(t)
+//   Ei(x) = PV ∫  ────── dt        Ei(x) = -E₁(-x) for x < 0
+//             -∞    t
+//
+//   The power series is used for x ≤ ln(1/ε) and the asymptotic expansion otherwise [1].
+//   Ei(0) = -Inf. See GammaP for references
+func ExpIntEi(x float64) float64 {
+	if x < 0 {
+		return -ExpIntE1(-x)
+	}
+	if x == 0 {
+		return math.Inf(-1)
+	}
+	if x < spFpMin {
+		return math.Log(x) + EulerGamma
+	}
+	if x <= -math.Log(spEps) {
+		sum, fact := 0.0, 1.0
+		for k := 1; k <= spMaxIt; k++ {
+			fact *= x / float64(k)
+			term := fact / float64(k)
+			sum += term
+			if term < spEps*sum {
+				break
+			}
+		}
+		return sum + math.Log(x) + EulerGamma
+	}
+	sum, term := 0.0, 1.0
+	for k := 1; k <= spMaxIt; k++ {
+		prev := term
+		term *= float64(k) / x
+		if term < spEps {
+			break
+		}
+		if term < prev {
+			sum += term
+		} else {
+			sum -= prev
+			break
+		}
+	}
+	return math.Exp(x) * (1.0 + sum) / x
+}
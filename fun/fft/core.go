@@ -0,0 +1,240 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fft implements Fast Fourier Transforms in pure Go (no cgo). The API is compatible with
+// the fftw package (plans to compute 1D and 2D transforms), with the addition of 3D transforms,
+// real-to-complex and complex-to-real transforms, and discrete cosine and sine transforms
+package fft
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// maxRadix is the largest prime factor handled by the mixed-radix algorithm. Lengths with larger
+// prime factors are computed by Bluestein's algorithm
+const maxRadix = 13
+
+// core implements the complex FFT of length n (out-of-place)
+//  Lengths with prime factors up to maxRadix are computed by a recursive mixed-radix
+//  decimation-in-time Cooley-Tukey algorithm (with special butterflies for radix 2 and 4).
+//  Other lengths are computed by Bluestein's algorithm that converts the DFT into a circular
+//  convolution of power-of-two length:
+//
+//                   -i π k² / n    N-1                  +i π (k-j)² / n
+//    X[k] = w[k] ⋅ Σ  (x[j] ⋅ w[j]) ⋅ c[k-j]     w[k] = e                  c = conj(w)
+//                  j=0
+//
+//  References:
+//  [1] Cooley JW and Tukey JW (1965) An algorithm for the machine calculation of complex Fourier
+//      series. Mathematics of Computation, 19:297-301
+//  [2] Bluestein L (1970) A linear filtering approach to the computation of discrete Fourier
+//      transform. IEEE Transactions on Audio and Electroacoustics, 18(4):451-455
+type core struct {
+	n       int          // length
+	inverse bool         // inverse transform (positive exponent)
+	factors []int        // pairs {p, m} of radix p and remaining length m
+	tw      []complex128 // twiddle factors: exp(∓i 2 π k / n)
+	scratch []complex128 // workspace for generic butterflies
+
+	// Bluestein
+	blue *core        // FFT of power-of-two length (nil if not used)
+	binv *core        // inverse FFT of power-of-two length
+	w    []complex128 // [n] chirp: exp(∓i π k² / n)
+	bhat []complex128 // [m] FFT of conjugated chirp
+	a    []complex128 // [m] workspace
+	b    []complex128 // [m] workspace
+}
+
+// newCore returns a new FFT core
+func newCore(n int, inverse bool) (o *core) {
+	if n < 1 {
+		chk.Panic("length of transform must be positive. n = %d is invalid\n", n)
+	}
+	o = new(core)
+	o.n = n
+	o.inverse = inverse
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+
+	// factorise
+	rem := n
+	for _, p := range []int{4, 2, 3, 5} {
+		for rem%p == 0 && rem > 1 {
+			rem /= p
+			o.factors = append(o.factors, p, rem)
+		}
+	}
+	for p := 7; rem > 1 && p <= maxRadix; p += 2 {
+		for rem%p == 0 {
+			rem /= p
+			o.factors = append(o.factors, p, rem)
+		}
+	}
+	if n == 1 {
+		o.factors = []int{1, 1}
+	}
+
+	// Bluestein
+	if rem > 1 {
+		o.factors = nil
+		m := 1
+		for m < 2*n-1 {
+			m *= 2
+		}
+		o.blue = newCore(m, false)
+		o.binv = newCore(m, true)
+		o.w = make([]complex128, n)
+		o.a = make([]complex128, m)
+		o.b = make([]complex128, m)
+		o.bhat = make([]complex128, m)
+		b := make([]complex128, m)
+		for k := 0; k < n; k++ {
+			k2 := (k * k) % (2 * n) // avoid loss of precision for large k
+			o.w[k] = expi(sign * math.Pi * float64(k2) / float64(n))
+			b[k] = conj(o.w[k])
+			if k > 0 {
+				b[m-k] = b[k]
+			}
+		}
+		o.blue.transform(o.bhat, b)
+		return
+	}
+
+	// twiddle factors
+	o.tw = make([]complex128, n)
+	for k := 0; k < n; k++ {
+		o.tw[k] = expi(sign * 2.0 * math.Pi * float64(k) / float64(n))
+	}
+	pmax := 0
+	for i := 0; i < len(o.factors); i += 2 {
+		if o.factors[i] > pmax {
+			pmax = o.factors[i]
+		}
+	}
+	o.scratch = make([]complex128, pmax)
+	return
+}
+
+// transform computes the (non-normalised) transform of in and stores the results in out
+//  NOTE: out and in must not overlap
+func (o *core) transform(out, in []complex128) {
+	if o.blue != nil {
+		o.bluestein(out, in)
+		return
+	}
+	o.work(out, in, 0, 1, o.factors)
+}
+
+// work performs the recursive decimation in time
+func (o *core) work(out, in []complex128, off, fstride int, factors []int) {
+	p, m := factors[0], factors[1]
+	if m == 1 {
+		for j := 0; j < p; j++ {
+			out[j] = in[off+j*fstride]
+		}
+	} else {
+		for q := 0; q < p; q++ {
+			o.work(out[q*m:(q+1)*m], in, off+q*fstride, fstride*p, factors[2:])
+		}
+	}
+	switch p {
+	case 1:
+	case 2:
+		o.butterfly2(out, fstride, m)
+	case 4:
+		o.butterfly4(out, fstride, m)
+	default:
+		o.butterflyGen(out, fstride, m, p)
+	}
+}
+
+// butterfly2 combines two sub-transforms of length m
+func (o *core) butterfly2(out []complex128, fstride, m int) {
+	for u := 0; u < m; u++ {
+		t := out[u+m] * o.tw[u*fstride]
+		out[u+m] = out[u] - t
+		out[u] += t
+	}
+}
+
+// butterfly4 combines four sub-transforms of length m
+func (o *core) butterfly4(out []complex128, fstride, m int) {
+	for u := 0; u < m; u++ {
+		a0 := out[u]
+		a1 := out[u+m] * o.tw[u*fstride]
+		a2 := out[u+2*m] * o.tw[2*u*fstride]
+		a3 := out[u+3*m] * o.tw[3*u*fstride]
+		s02, d02 := a0+a2, a0-a2
+		s13, d13 := a1+a3, a1-a3
+		if o.inverse {
+			d13 = complex(-imag(d13), real(d13)) // +i⋅d13
+		} else {
+			d13 = complex(imag(d13), -real(d13)) // -i⋅d13
+		}
+		out[u] = s02 + s13
+		out[u+m] = d02 + d13
+		out[u+2*m] = s02 - s13
+		out[u+3*m] = d02 - d13
+	}
+}
+
+// butterflyGen combines p sub-transforms of length m (generic radix)
+func (o *core) butterflyGen(out []complex128, fstride, m, p int) {
+	for u := 0; u < m; u++ {
+		for q := 0; q < p; q++ {
+			o.scratch[q] = out[u+q*m]
+		}
+		for q1 := 0; q1 < p; q1++ {
+			k := u + q1*m
+			sum := o.scratch[0]
+			step := (fstride * k) % o.n
+			idx := 0
+			for q := 1; q < p; q++ {
+				idx += step
+				if idx >= o.n {
+					idx -= o.n
+				}
+				sum += o.scratch[q] * o.tw[idx]
+			}
+			out[k] = sum
+		}
+	}
+}
+
+// bluestein computes the transform by Bluestein's algorithm
+func (o *core) bluestein(out, in []complex128) {
+	m := len(o.a)
+	for k := 0; k < o.n; k++ {
+		o.a[k] = in[k] * o.w[k]
+	}
+	for k := o.n; k < m; k++ {
+		o.a[k] = 0
+	}
+	o.blue.transform(o.b, o.a)
+	for k := 0; k < m; k++ {
+		o.b[k] *= o.bhat[k]
+	}
+	o.binv.transform(o.a, o.b)
+	s := 1.0 / float64(m)
+	for k := 0; k < o.n; k++ {
+		out[k] = o.a[k] * o.w[k] * complex(s, 0)
+	}
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// expi computes exp(i⋅x)
+func expi(x float64) complex128 {
+	s, c := math.Sincos(x)
+	return complex(c, s)
+}
+
+// conj returns the complex conjugate
+func conj(z complex128) complex128 {
+	return complex(real(z), -imag(z))
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+// Plan1d implements a "plan" to compute direct or inverse 1D FTs (see fftw.Plan1d)
+//  Computes:
+//                     N-1         -i 2 π j k / N                 __
+//    forward:  X[k] =  Σ  x[j] ⋅ e                     with i = √-1
+//                     j=0
+//
+//                     N-1         +i 2 π j k / N
+//    inverse:  Y[k] =  Σ  y[j] ⋅ e                     thus x[k] = Y[k] / N
+//                     j=0
+//
+//  NOTE: any length N ≥ 1 is accepted. The plan can be reused as many times as needed; e.g. by
+//        changing the values in data and calling Execute again
+type Plan1d struct {
+	data []complex128 // input and output
+	core *core        // FFT core
+	work []complex128 // workspace
+}
+
+// NewPlan1d allocates a new "plan" to compute 1D Fourier Transforms
+//  data    -- [modified] data is a complex array of length N.
+//  inverse -- will perform inverse transform; otherwise will perform direct
+//             Note: both transforms are non-normalised;
+//             i.e. the user will have to multiply by (1/n) if computing inverse transforms
+//  measure -- not used; kept for compatibility with fftw.NewPlan1d
+//
+//  NOTE: data will be overwritten by Execute
+func NewPlan1d(data []complex128, inverse, measure bool) (o *Plan1d) {
+	o = new(Plan1d)
+	o.data = data
+	o.core = newCore(len(data), inverse)
+	o.work = make([]complex128, len(data))
+	return
+}
+
+// Free frees internal data (nothing to be done; kept for compatibility with fftw.Plan1d)
+func (o *Plan1d) Free() {
+}
+
+// Execute performs the Fourier transform
+func (o *Plan1d) Execute() {
+	o.core.transform(o.work, o.data)
+	copy(o.data, o.work)
+}
@@ -0,0 +1,164 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import "github.com/cpmech/gosl/chk"
+
+// Plan2d implements a "plan" to compute direct or inverse 2D FTs (see fftw.Plan2d)
+//  Computes:
+//                     N1-1 N0-1             -i 2 π k1 l1 / N1    -i 2 π k0 l0 / N0
+//          X[l0,l1] =   Σ    Σ  x[k0,k1] ⋅ e                  ⋅ e
+//                     k1=0 k0=0
+//
+//  The data is a ROW-MAJOR matrix; i.e. x[i,j] = data[N1⋅i + j]
+type Plan2d struct {
+	n0   int          // length along first dimension
+	n1   int          // length along second dimension
+	data []complex128 // input (row-major matrix)
+	nd   ndPlan       // N-D transform
+}
+
+// NewPlan2d allocates a new "plan" to compute 2D Fourier Transforms
+//  N0, N1  -- dimensions
+//  data    -- [modified] data is a complex array of length N0*N1 (row-major matrix)
+//  inverse -- will perform inverse transform; otherwise will perform direct
+//             Note: both transforms are non-normalised;
+//             i.e. the user will have to multiply by (1/n) if computing inverse transforms
+//  measure -- not used; kept for compatibility with fftw.NewPlan2d
+func NewPlan2d(N0, N1 int, data []complex128, inverse, measure bool) (o *Plan2d) {
+	o = new(Plan2d)
+	o.n0 = N0
+	o.n1 = N1
+	o.data = data
+	o.nd.init([]int{N0, N1}, data, inverse)
+	return
+}
+
+// Free frees internal data (nothing to be done; kept for compatibility with fftw.Plan2d)
+func (o *Plan2d) Free() {
+}
+
+// Set sets data value located at "i,j". NOTE: this method does not check for out-of-range indices
+func (o *Plan2d) Set(i, j int, v complex128) {
+	o.data[o.n1*i+j] = v
+}
+
+// Get gets data value located at "i,j". NOTE: this method does not check for out-of-range indices
+func (o *Plan2d) Get(i, j int) (v complex128) {
+	return o.data[o.n1*i+j]
+}
+
+// Execute performs the Fourier transform
+func (o *Plan2d) Execute() {
+	o.nd.execute()
+}
+
+// GetSlice gets the output array as a nested slice
+func (o *Plan2d) GetSlice() (out [][]complex128) {
+	out = make([][]complex128, o.n0)
+	for i := 0; i < o.n0; i++ {
+		out[i] = make([]complex128, o.n1)
+		for j := 0; j < o.n1; j++ {
+			out[i][j] = o.Get(i, j)
+		}
+	}
+	return
+}
+
+// Plan3d implements a "plan" to compute direct or inverse 3D FTs
+//  Computes:
+//                     N2-1 N1-1 N0-1
+//    X[l0,l1,l2] =     Σ    Σ    Σ   x[k0,k1,k2] ⋅ exp(∓i 2 π (k0 l0 / N0 + k1 l1 / N1 + k2 l2 / N2))
+//                     k2=0 k1=0 k0=0
+//
+//  The data is ROW-MAJOR; i.e. x[i,j,k] = data[N2⋅(N1⋅i + j) + k]
+type Plan3d struct {
+	n0   int          // length along first dimension
+	n1   int          // length along second dimension
+	n2   int          // length along third dimension
+	data []complex128 // input (row-major)
+	nd   ndPlan       // N-D transform
+}
+
+// NewPlan3d allocates a new "plan" to compute 3D Fourier Transforms
+//  N0, N1, N2 -- dimensions
+//  data       -- [modified] data is a complex array of length N0*N1*N2 (row-major)
+//  inverse    -- will perform inverse transform (non-normalised); otherwise will perform direct
+func NewPlan3d(N0, N1, N2 int, data []complex128, inverse bool) (o *Plan3d) {
+	o = new(Plan3d)
+	o.n0 = N0
+	o.n1 = N1
+	o.n2 = N2
+	o.data = data
+	o.nd.init([]int{N0, N1, N2}, data, inverse)
+	return
+}
+
+// Set sets data value located at "i,j,k". NOTE: this method does not check for out-of-range indices
+func (o *Plan3d) Set(i, j, k int, v complex128) {
+	o.data[o.n2*(o.n1*i+j)+k] = v
+}
+
+// Get gets data value located at "i,j,k". NOTE: this method does not check for out-of-range indices
+func (o *Plan3d) Get(i, j, k int) (v complex128) {
+	return o.data[o.n2*(o.n1*i+j)+k]
+}
+
+// Execute performs the Fourier transform
+func (o *Plan3d) Execute() {
+	o.nd.execute()
+}
+
+// ndPlan computes N-D transforms of row-major data by 1D transforms along each dimension
+type ndPlan struct {
+	shape []int        // dimensions
+	data  []complex128 // input and output
+	cores []*core      // [ndim] 1D transforms
+	in    []complex128 // workspace: gathered line
+	out   []complex128 // workspace: transformed line
+}
+
+// init initialises ndPlan
+func (o *ndPlan) init(shape []int, data []complex128, inverse bool) {
+	size, nmax := 1, 0
+	for _, n := range shape {
+		size *= n
+		if n > nmax {
+			nmax = n
+		}
+	}
+	if len(data) != size {
+		chk.Panic("length of data must be equal to the product of dimensions = %d. %d is invalid\n", size, len(data))
+	}
+	o.shape = shape
+	o.data = data
+	o.cores = make([]*core, len(shape))
+	for d, n := range shape {
+		o.cores[d] = newCore(n, inverse)
+	}
+	o.in = make([]complex128, nmax)
+	o.out = make([]complex128, nmax)
+}
+
+// execute performs the transform
+func (o *ndPlan) execute() {
+	stride := len(o.data)
+	for d, n := range o.shape {
+		stride /= n // distance between consecutive entries along dimension d
+		nouter := len(o.data) / (stride * n)
+		for b := 0; b < nouter; b++ {
+			for a := 0; a < stride; a++ {
+				off := a + stride*n*b
+				for j := 0; j < n; j++ {
+					o.in[j] = o.data[off+stride*j]
+				}
+				o.cores[d].transform(o.out[:n], o.in[:n])
+				for j := 0; j < n; j++ {
+					o.data[off+stride*j] = o.out[j]
+				}
+			}
+		}
+	}
+}
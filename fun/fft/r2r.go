@@ -0,0 +1,182 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// PlanR2r implements a "plan" to compute real-to-real transforms; i.e. discrete cosine (DCT) and
+// sine (DST) transforms of types I to IV. The definitions are the same as FFTW's REDFTxx and
+// RODFTxx (non-normalised) transforms:
+//  "dct1" (REDFT00): Y[k] = x[0] + (-1)ᵏ x[N-1] + 2 Σ_{j=1}^{N-2} x[j] cos(π j k / (N-1))
+//  "dct2" (REDFT10): Y[k] = 2 Σ_{j=0}^{N-1} x[j] cos(π (j+½) k / N)
+//  "dct3" (REDFT01): Y[k] = x[0] + 2 Σ_{j=1}^{N-1} x[j] cos(π j (k+½) / N)
+//  "dct4" (REDFT11): Y[k] = 2 Σ_{j=0}^{N-1} x[j] cos(π (j+½) (k+½) / N)
+//  "dst1" (RODFT00): Y[k] = 2 Σ_{j=0}^{N-1} x[j] sin(π (j+1) (k+1) / (N+1))
+//  "dst2" (RODFT10): Y[k] = 2 Σ_{j=0}^{N-1} x[j] sin(π (j+½) (k+1) / N)
+//  "dst3" (RODFT01): Y[k] = (-1)ᵏ x[N-1] + 2 Σ_{j=0}^{N-2} x[j] sin(π (j+1) (k+½) / N)
+//  "dst4" (RODFT11): Y[k] = 2 Σ_{j=0}^{N-1} x[j] sin(π (j+½) (k+½) / N)
+//
+//  The inverses are: dct1 ⇒ dct1 / (2(N-1)); dct2 ⇒ dct3 / (2N); dct3 ⇒ dct2 / (2N);
+//  dct4 ⇒ dct4 / (2N); dst1 ⇒ dst1 / (2(N+1)); dst2 ⇒ dst3 / (2N); dst3 ⇒ dst2 / (2N);
+//  and dst4 ⇒ dst4 / (2N).
+//
+//  The transforms are computed by complex FFTs of the extended sequences (of length 2N or so).
+type PlanR2r struct {
+	kind  string       // kind of transform
+	data  []float64    // input and output
+	core  *core        // complex FFT of extended sequence
+	in    []complex128 // workspace
+	out   []complex128 // workspace
+	pre   []complex128 // phase factors applied before the FFT
+	post  []complex128 // phase factors applied after the FFT
+	input []float64    // workspace: modified input for DSTs
+}
+
+// NewPlanR2r allocates a new "plan" to compute real-to-real transforms
+//  data -- [modified] input and output data
+//  kind -- "dct1", "dct2", "dct3", "dct4", "dst1", "dst2", "dst3" or "dst4"
+func NewPlanR2r(data []float64, kind string) (o *PlanR2r) {
+
+	// check
+	n := len(data)
+	if n < 1 || (kind == "dct1" && n < 2) {
+		chk.Panic("length of data is too small for %q transform. N = %d is invalid\n", kind, n)
+	}
+
+	// allocate
+	o = new(PlanR2r)
+	o.kind = kind
+	o.data = data
+	o.input = make([]float64, n)
+	var m int
+	inverse := false
+	switch kind {
+	case "dct1":
+		m = 2 * (n - 1)
+	case "dst1":
+		m = 2 * (n + 1)
+	case "dct2", "dst2":
+		m = 2 * n
+		o.post = make([]complex128, n)
+		for k := 0; k < n; k++ {
+			o.post[k] = expi(-math.Pi * float64(k) / float64(2*n))
+		}
+	case "dct3", "dst3":
+		m = 2 * n
+		inverse = true
+		o.pre = make([]complex128, n)
+		for j := 0; j < n; j++ {
+			o.pre[j] = expi(math.Pi * float64(j) / float64(2*n))
+			if j > 0 {
+				o.pre[j] *= 2
+			}
+		}
+	case "dct4", "dst4":
+		m = 2 * n
+		o.pre = make([]complex128, n)
+		o.post = make([]complex128, n)
+		for j := 0; j < n; j++ {
+			o.pre[j] = expi(-math.Pi * float64(j) / float64(2*n))
+			o.post[j] = 2 * expi(-math.Pi*float64(2*j+1)/float64(4*n))
+		}
+	default:
+		chk.Panic("cannot find real-to-real transform named %q\n", kind)
+	}
+	o.core = newCore(m, inverse)
+	o.in = make([]complex128, m)
+	o.out = make([]complex128, m)
+	return
+}
+
+// Execute performs the transform
+func (o *PlanR2r) Execute() {
+	n := len(o.data)
+	x, y := o.input, o.data
+
+	// DSTs from DCTs (types II to IV)
+	switch o.kind {
+	case "dst2":
+		for j := 0; j < n; j++ {
+			x[j] = o.data[j] * float64(1-2*(j%2)) // (-1)ʲ x[j]
+		}
+	case "dst3", "dst4":
+		for j := 0; j < n; j++ {
+			x[j] = o.data[n-1-j]
+		}
+	default:
+		copy(x, o.data)
+	}
+
+	// extended sequences
+	for j := range o.in {
+		o.in[j] = 0
+	}
+	switch o.kind {
+	case "dct1":
+		for j := 0; j < n; j++ {
+			o.in[j] = complex(x[j], 0)
+		}
+		for j := 1; j < n-1; j++ {
+			o.in[2*(n-1)-j] = complex(x[j], 0)
+		}
+	case "dst1":
+		for j := 0; j < n; j++ {
+			o.in[j+1] = complex(x[j], 0)
+			o.in[2*(n+1)-1-j] = complex(-x[j], 0)
+		}
+	case "dct2", "dst2":
+		for j := 0; j < n; j++ {
+			o.in[j] = complex(x[j], 0)
+			o.in[2*n-1-j] = complex(x[j], 0)
+		}
+	default: // types III and IV
+		for j := 0; j < n; j++ {
+			o.in[j] = complex(x[j], 0) * o.pre[j]
+		}
+	}
+
+	// transform
+	o.core.transform(o.out, o.in)
+
+	// results
+	switch o.kind {
+	case "dct1":
+		for k := 0; k < n; k++ {
+			y[k] = real(o.out[k])
+		}
+	case "dst1":
+		for k := 0; k < n; k++ {
+			y[k] = -imag(o.out[k+1])
+		}
+	case "dct2":
+		for k := 0; k < n; k++ {
+			y[k] = real(o.post[k] * o.out[k])
+		}
+	case "dst2":
+		for k := 0; k < n; k++ {
+			y[n-1-k] = real(o.post[k] * o.out[k])
+		}
+	case "dct3":
+		for k := 0; k < n; k++ {
+			y[k] = real(o.out[k])
+		}
+	case "dst3":
+		for k := 0; k < n; k++ {
+			y[k] = float64(1-2*(k%2)) * real(o.out[k])
+		}
+	case "dct4":
+		for k := 0; k < n; k++ {
+			y[k] = real(o.post[k] * o.out[k])
+		}
+	case "dst4":
+		for k := 0; k < n; k++ {
+			y[k] = float64(1-2*(k%2)) * real(o.post[k]*o.out[k])
+		}
+	}
+}
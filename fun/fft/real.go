@@ -0,0 +1,161 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// PlanR2c implements a "plan" to compute the FT of real data
+//  Computes:
+//                 N-1         -i 2 π j k / N
+//        X[k] =    Σ  x[j] ⋅ e                     for k = 0 ... N/2
+//                 j=0
+//
+//  The other coefficients follow from the Hermitian symmetry: X[N-k] = conj(X[k]).
+//  If N is even, the transform is computed by a complex FFT of length N/2.
+type PlanR2c struct {
+	x    []float64    // [N] input
+	X    []complex128 // [N/2+1] output
+	core *core        // complex FFT of length N/2 (even N) or N (odd N)
+	in   []complex128 // workspace
+	out  []complex128 // workspace
+	tw   []complex128 // [N/2+1] twiddle factors exp(-i 2 π k / N) (even N)
+}
+
+// NewPlanR2c allocates a new "plan" to compute the FT of real data
+//  x -- [N] input
+//  X -- [N/2+1] output
+func NewPlanR2c(x []float64, X []complex128) (o *PlanR2c) {
+	n := len(x)
+	if n < 1 || len(X) != n/2+1 {
+		chk.Panic("lengths of x and X must be N ≥ 1 and N/2+1. %d and %d are invalid\n", n, len(X))
+	}
+	o = new(PlanR2c)
+	o.x = x
+	o.X = X
+	m := n
+	if n%2 == 0 {
+		m = n / 2
+		o.tw = twiddles(n, n/2+1, -1)
+	}
+	o.core = newCore(m, false)
+	o.in = make([]complex128, m)
+	o.out = make([]complex128, m)
+	return
+}
+
+// Execute performs the Fourier transform
+func (o *PlanR2c) Execute() {
+	n := len(o.x)
+
+	// odd N
+	if o.tw == nil {
+		for j := 0; j < n; j++ {
+			o.in[j] = complex(o.x[j], 0)
+		}
+		o.core.transform(o.out, o.in)
+		copy(o.X, o.out[:n/2+1])
+		return
+	}
+
+	// even N: z = x_even + i x_odd
+	h := n / 2
+	for j := 0; j < h; j++ {
+		o.in[j] = complex(o.x[2*j], o.x[2*j+1])
+	}
+	o.core.transform(o.out, o.in)
+	for k := 0; k <= h; k++ {
+		zk, zc := o.out[k%h], conj(o.out[(h-k)%h])
+		e := (zk + zc) / 2
+		d := (zk - zc) / 2
+		od := complex(imag(d), -real(d)) // (zk - zc) / (2i)
+		o.X[k] = e + o.tw[k]*od
+	}
+}
+
+// PlanC2r implements a "plan" to compute the inverse FT of Hermitian data resulting in real data
+//  Computes:
+//                 N-1         +i 2 π j k / N
+//        x[j] =    Σ  X[k] ⋅ e                     with X[N-k] = conj(X[k])
+//                 k=0
+//
+//  NOTE: the transform is non-normalised; i.e. the user will have to multiply by (1/N) to
+//        recover the data given to PlanR2c
+type PlanC2r struct {
+	X    []complex128 // [N/2+1] input
+	x    []float64    // [N] output
+	core *core        // complex inverse FFT of length N/2 (even N) or N (odd N)
+	in   []complex128 // workspace
+	out  []complex128 // workspace
+	tw   []complex128 // [N/2+1] twiddle factors exp(+i 2 π k / N) (even N)
+}
+
+// NewPlanC2r allocates a new "plan" to compute the inverse FT of Hermitian data
+//  X -- [N/2+1] input
+//  x -- [N] output
+func NewPlanC2r(X []complex128, x []float64) (o *PlanC2r) {
+	n := len(x)
+	if n < 1 || len(X) != n/2+1 {
+		chk.Panic("lengths of X and x must be N/2+1 and N ≥ 1. %d and %d are invalid\n", len(X), n)
+	}
+	o = new(PlanC2r)
+	o.X = X
+	o.x = x
+	m := n
+	if n%2 == 0 {
+		m = n / 2
+		o.tw = twiddles(n, n/2+1, +1)
+	}
+	o.core = newCore(m, true)
+	o.in = make([]complex128, m)
+	o.out = make([]complex128, m)
+	return
+}
+
+// Execute performs the inverse Fourier transform
+func (o *PlanC2r) Execute() {
+	n := len(o.x)
+
+	// odd N
+	if o.tw == nil {
+		for k := 0; k <= n/2; k++ {
+			o.in[k] = o.X[k]
+			if k > 0 {
+				o.in[n-k] = conj(o.X[k])
+			}
+		}
+		o.core.transform(o.out, o.in)
+		for j := 0; j < n; j++ {
+			o.x[j] = real(o.out[j])
+		}
+		return
+	}
+
+	// even N: Z = E + i O
+	h := n / 2
+	for k := 0; k < h; k++ {
+		xk, xc := o.X[k], conj(o.X[h-k])
+		e := (xk + xc) / 2
+		od := (xk - xc) * o.tw[k] / 2
+		o.in[k] = e + complex(-imag(od), real(od)) // e + i od
+	}
+	o.core.transform(o.out, o.in)
+	for j := 0; j < h; j++ {
+		o.x[2*j] = 2 * real(o.out[j])
+		o.x[2*j+1] = 2 * imag(o.out[j])
+	}
+}
+
+// twiddles returns exp(sign⋅i 2 π k / n) for k = 0 ... m-1
+func twiddles(n, m int, sign float64) (tw []complex128) {
+	tw = make([]complex128, m)
+	for k := 0; k < m; k++ {
+		tw[k] = expi(sign * 2.0 * math.Pi * float64(k) / float64(n))
+	}
+	return
+}
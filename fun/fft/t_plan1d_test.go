@@ -0,0 +1,93 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestPlan1d01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Plan1d01. small example")
+
+	// data
+	x := []complex128{1 + 2i, 3 + 4i, 5 + 6i, 7 + 8i}
+	Xref := dft1d(x, false)
+
+	// transform
+	plan := NewPlan1d(x, false, false)
+	defer plan.Free()
+	plan.Execute()
+	io.Pf("X = %v\n", x)
+	chk.ArrayC(tst, "X", 1e-14, x, Xref)
+}
+
+func TestPlan1d02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Plan1d02. mixed-radix and Bluestein lengths")
+
+	// lengths: powers of 2, mixed radix, radix 7, 11 and 13, and primes (Bluestein)
+	for _, N := range []int{1, 2, 3, 4, 5, 6, 8, 12, 16, 30, 64, 105, 7 * 11 * 13, 17, 97, 2 * 97, 1009} {
+		x := make([]complex128, N)
+		for i := 0; i < N; i++ {
+			x[i] = complex(math.Sin(float64(i)+0.3), math.Cos(float64(i*i)/7.0))
+		}
+		for _, inverse := range []bool{false, true} {
+			Xref := dft1d(x, inverse)
+			X := make([]complex128, N)
+			copy(X, x)
+			plan := NewPlan1d(X, inverse, false)
+			plan.Execute()
+			io.Pf("N = %4d inverse = %5v\n", N, inverse)
+			chk.ArrayC(tst, io.Sf("X(N=%d)", N), 1e-11*float64(N), X, Xref)
+		}
+	}
+}
+
+func TestPlan1d03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Plan1d03. round trip")
+
+	N := 360
+	x := make([]complex128, N)
+	for i := 0; i < N; i++ {
+		x[i] = complex(float64(i%7), float64(i%5)-2)
+	}
+	X := make([]complex128, N)
+	copy(X, x)
+	forward := NewPlan1d(X, false, false)
+	inverse := NewPlan1d(X, true, false)
+	forward.Execute()
+	inverse.Execute()
+	for i := 0; i < N; i++ {
+		X[i] /= complex(float64(N), 0)
+	}
+	chk.ArrayC(tst, "x", 1e-13, X, x)
+}
+
+// solution ////////////////////////////////////////////////////////////////////////////////////////
+
+// dft1d computes the discrete Fourier Transform of x (very slow: for testing only)
+func dft1d(x []complex128, inverse bool) (X []complex128) {
+	N := len(x)
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+	X = make([]complex128, N)
+	for k := 0; k < N; k++ {
+		for j := 0; j < N; j++ {
+			X[k] += x[j] * expi(sign*2.0*math.Pi*float64((j*k)%N)/float64(N))
+		}
+	}
+	return
+}
@@ -0,0 +1,99 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestPlan2d01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Plan2d01. 2D transform")
+
+	// data
+	N0, N1 := 6, 5
+	x := make([]complex128, N0*N1)
+	plan := NewPlan2d(N0, N1, x, false, false)
+	defer plan.Free()
+	for i := 0; i < N0; i++ {
+		for j := 0; j < N1; j++ {
+			plan.Set(i, j, complex(float64(i+2*j), math.Sin(float64(i*j))))
+		}
+	}
+
+	// reference
+	Xref := make([]complex128, N0*N1)
+	for l0 := 0; l0 < N0; l0++ {
+		for l1 := 0; l1 < N1; l1++ {
+			for k0 := 0; k0 < N0; k0++ {
+				for k1 := 0; k1 < N1; k1++ {
+					a := 2.0 * math.Pi * (float64(k0*l0)/float64(N0) + float64(k1*l1)/float64(N1))
+					Xref[l0*N1+l1] += x[k0*N1+k1] * expi(-a)
+				}
+			}
+		}
+	}
+
+	// transform
+	plan.Execute()
+	X := plan.GetSlice()
+	io.Pf("X[0] = %v\n", X[0])
+	chk.ArrayC(tst, "X", 1e-12, x, Xref)
+}
+
+func TestPlan3d01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Plan3d01. 3D transform and round trip")
+
+	// data
+	N0, N1, N2 := 4, 3, 7
+	x := make([]complex128, N0*N1*N2)
+	plan := NewPlan3d(N0, N1, N2, x, false)
+	for i := 0; i < N0; i++ {
+		for j := 0; j < N1; j++ {
+			for k := 0; k < N2; k++ {
+				plan.Set(i, j, k, complex(float64(i-j+k*k), float64(i*j-k)))
+			}
+		}
+	}
+	x0 := make([]complex128, len(x))
+	copy(x0, x)
+
+	// reference
+	Xref := make([]complex128, len(x))
+	for l0 := 0; l0 < N0; l0++ {
+		for l1 := 0; l1 < N1; l1++ {
+			for l2 := 0; l2 < N2; l2++ {
+				for k0 := 0; k0 < N0; k0++ {
+					for k1 := 0; k1 < N1; k1++ {
+						for k2 := 0; k2 < N2; k2++ {
+							a := 2.0 * math.Pi * (float64(k0*l0)/float64(N0) + float64(k1*l1)/float64(N1) + float64(k2*l2)/float64(N2))
+							Xref[(l0*N1+l1)*N2+l2] += x[(k0*N1+k1)*N2+k2] * expi(-a)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// transform
+	plan.Execute()
+	chk.ArrayC(tst, "X", 1e-11, x, Xref)
+	chk.Complex128(tst, "X(1,2,3)", 1e-12, plan.Get(1, 2, 3), Xref[(1*N1+2)*N2+3])
+
+	// inverse
+	inv := NewPlan3d(N0, N1, N2, x, true)
+	inv.Execute()
+	for i := range x {
+		x[i] /= complex(float64(len(x)), 0)
+	}
+	chk.ArrayC(tst, "x", 1e-13, x, x0)
+}
@@ -0,0 +1,113 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestR2r01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("R2r01. DCT and DST types I to IV")
+
+	for _, kind := range []string{"dct1", "dct2", "dct3", "dct4", "dst1", "dst2", "dst3", "dst4"} {
+		for _, N := range []int{2, 3, 5, 8, 13} {
+			x := make([]float64, N)
+			for j := 0; j < N; j++ {
+				x[j] = math.Sin(float64(j)+0.5) + float64(j)/3.0
+			}
+			Yref := r2rDirect(x, kind)
+			Y := make([]float64, N)
+			copy(Y, x)
+			plan := NewPlanR2r(Y, kind)
+			plan.Execute()
+			io.Pf("%s N = %2d\n", kind, N)
+			chk.Array(tst, io.Sf("%s(N=%d)", kind, N), 1e-12, Y, Yref)
+		}
+	}
+}
+
+func TestR2r02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("R2r02. inverses")
+
+	N := 12
+	x := make([]float64, N)
+	for j := 0; j < N; j++ {
+		x[j] = float64(j*j%7) - 2.5
+	}
+	pairs := [][]string{{"dct1", "dct1"}, {"dct2", "dct3"}, {"dct3", "dct2"}, {"dct4", "dct4"},
+		{"dst1", "dst1"}, {"dst2", "dst3"}, {"dst3", "dst2"}, {"dst4", "dst4"}}
+	for _, pair := range pairs {
+		y := make([]float64, N)
+		copy(y, x)
+		NewPlanR2r(y, pair[0]).Execute()
+		NewPlanR2r(y, pair[1]).Execute()
+		s := 2.0 * float64(N)
+		switch pair[0] {
+		case "dct1":
+			s = 2.0 * float64(N-1)
+		case "dst1":
+			s = 2.0 * float64(N+1)
+		}
+		for j := 0; j < N; j++ {
+			y[j] /= s
+		}
+		chk.Array(tst, pair[0]+" ⇒ "+pair[1], 1e-13, y, x)
+	}
+}
+
+// solution ////////////////////////////////////////////////////////////////////////////////////////
+
+// r2rDirect computes the real-to-real transforms by the definitions (slow: for testing only)
+func r2rDirect(x []float64, kind string) (Y []float64) {
+	N := len(x)
+	n := float64(N)
+	Y = make([]float64, N)
+	for k := 0; k < N; k++ {
+		K := float64(k)
+		sgn := float64(1 - 2*(k%2))
+		switch kind {
+		case "dct1":
+			Y[k] = x[0] + sgn*x[N-1]
+			for j := 1; j < N-1; j++ {
+				Y[k] += 2 * x[j] * math.Cos(math.Pi*float64(j)*K/(n-1))
+			}
+		case "dct3":
+			Y[k] = x[0]
+			for j := 1; j < N; j++ {
+				Y[k] += 2 * x[j] * math.Cos(math.Pi*float64(j)*(K+0.5)/n)
+			}
+		case "dst3":
+			Y[k] = sgn * x[N-1]
+			for j := 0; j < N-1; j++ {
+				Y[k] += 2 * x[j] * math.Sin(math.Pi*float64(j+1)*(K+0.5)/n)
+			}
+		default:
+			for j := 0; j < N; j++ {
+				J := float64(j)
+				switch kind {
+				case "dct2":
+					Y[k] += 2 * x[j] * math.Cos(math.Pi*(J+0.5)*K/n)
+				case "dct4":
+					Y[k] += 2 * x[j] * math.Cos(math.Pi*(J+0.5)*(K+0.5)/n)
+				case "dst1":
+					Y[k] += 2 * x[j] * math.Sin(math.Pi*(J+1)*(K+1)/(n+1))
+				case "dst2":
+					Y[k] += 2 * x[j] * math.Sin(math.Pi*(J+0.5)*(K+1)/n)
+				case "dst4":
+					Y[k] += 2 * x[j] * math.Sin(math.Pi*(J+0.5)*(K+0.5)/n)
+				}
+			}
+		}
+	}
+	return
+}
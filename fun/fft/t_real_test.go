@@ -0,0 +1,47 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fft
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestReal01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Real01. real-to-complex and complex-to-real")
+
+	for _, N := range []int{1, 2, 3, 4, 7, 10, 16, 34, 45} {
+
+		// data
+		x := make([]float64, N)
+		xc := make([]complex128, N)
+		for i := 0; i < N; i++ {
+			x[i] = math.Cos(float64(i)*1.3) + float64(i%3)
+			xc[i] = complex(x[i], 0)
+		}
+		Xref := dft1d(xc, false)
+
+		// forward
+		X := make([]complex128, N/2+1)
+		r2c := NewPlanR2c(x, X)
+		r2c.Execute()
+		io.Pf("N = %2d\n", N)
+		chk.ArrayC(tst, io.Sf("X(N=%d)", N), 1e-12, X, Xref[:N/2+1])
+
+		// inverse
+		y := make([]float64, N)
+		c2r := NewPlanC2r(X, y)
+		c2r.Execute()
+		for i := 0; i < N; i++ {
+			y[i] /= float64(N)
+		}
+		chk.Array(tst, io.Sf("x(N=%d)", N), 1e-13, y, x)
+	}
+}
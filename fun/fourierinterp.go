@@ -9,7 +9,7 @@ import (
 	"math/cmplx"
 
 	"github.com/cpmech/gosl/chk"
-	"github.com/cpmech/gosl/fun/fftw"
+	"github.com/cpmech/gosl/fun/fft"
 	"github.com/cpmech/gosl/io"
 	"github.com/cpmech/gosl/la"
 	"github.com/cpmech/gosl/plt"
@@ -66,11 +66,11 @@ type FourierInterp struct {
 	Du1Hat la.VectorC // spectral coefficient corresponding to 1st derivative
 	Du2Hat la.VectorC // spectral coefficient corresponding to 1st derivative
 
-	// FFTW
-	planA   *fftw.Plan1d // "plan" to compute the A coefficients
-	planDu  *fftw.Plan1d // "plan" to compute the p-derivative (inverse transform)
-	planDu1 *fftw.Plan1d // "plan" to compute the 1st derivative (inverse transform)
-	planDu2 *fftw.Plan1d // "plan" to compute the 2nd derivative (inverse transform)
+	// FFT
+	planA   *fft.Plan1d // "plan" to compute the A coefficients
+	planDu  *fft.Plan1d // "plan" to compute the p-derivative (inverse transform)
+	planDu1 *fft.Plan1d // "plan" to compute the 1st derivative (inverse transform)
+	planDu2 *fft.Plan1d // "plan" to compute the 2nd derivative (inverse transform)
 
 	// workspace
 	workAli la.VectorC // values of f(x) at 3⋅N/2-1 grid points (nodes) X[j] to reduce aliasing error
@@ -132,10 +132,10 @@ func NewFourierInterp(N int, smoothing string) (o *FourierInterp) {
 	o.DuHat = la.NewVectorC(o.N)
 	o.Du1Hat = la.NewVectorC(o.N)
 	o.Du2Hat = la.NewVectorC(o.N)
-	o.planA = fftw.NewPlan1d(o.A, false, false)
-	o.planDu = fftw.NewPlan1d(o.DuHat, true, false)
-	o.planDu1 = fftw.NewPlan1d(o.Du1Hat, true, false)
-	o.planDu2 = fftw.NewPlan1d(o.Du2Hat, true, false)
+	o.planA = fft.NewPlan1d(o.A, false, false)
+	o.planDu = fft.NewPlan1d(o.DuHat, true, false)
+	o.planDu1 = fft.NewPlan1d(o.Du1Hat, true, false)
+	o.planDu2 = fft.NewPlan1d(o.Du2Hat, true, false)
 	return
 }
 
@@ -0,0 +1,214 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// maximum number of iterations and tolerance for series and continued fractions
+const (
+	spMaxIt = 100000
+	spEps   = 2.220446049250313e-16
+	spFpMin = 2.2250738585072014e-308 / 2.220446049250313e-16
+)
+
+// GammaP computes the regularised lower incomplete gamma function P(a,x) for a > 0 and x ≥ 0
+//
+//                 1      x
+//   P(a,x) = ─────────  ∫  exp(-t) t^(a-1) dt       with  P(a,∞) = 1
+//              Γ(a)    0
+//
+//   The series expansion is used for x < a+1 and the continued fraction of Q = 1 - P otherwise [1]
+//
+//   References:
+//   [1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//       Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func GammaP(a, x float64) float64 {
+	checkGammaInc(a, x)
+	if x == 0 {
+		return 0
+	}
+	if x < a+1.0 {
+		return gammaSeries(a, x)
+	}
+	return 1.0 - gammaContFrac(a, x)
+}
+
+// GammaQ computes the regularised upper incomplete gamma function Q(a,x) = 1 - P(a,x)
+//
+//                 1      ∞
+//   Q(a,x) = ─────────  ∫  exp(-t) t^(a-1) dt
+//              Γ(a)    x
+//
+func GammaQ(a, x float64) float64 {
+	checkGammaInc(a, x)
+	if x == 0 {
+		return 1
+	}
+	if x < a+1.0 {
+		return 1.0 - gammaSeries(a, x)
+	}
+	return gammaContFrac(a, x)
+}
+
+// Digamma computes the digamma function ψ(x) = d(ln Γ(x))/dx
+//
+//   The recurrence ψ(x) = ψ(x+1) - 1/x is used to shift x above 10 where the asymptotic expansion
+//   is applied. For x < 0, the reflection formula ψ(1-x) - ψ(x) = π cot(πx) is used.
+//   ψ(x) = NaN for x = 0, -1, -2, ...
+func Digamma(x float64) (res float64) {
+	if math.IsNaN(x) || (x <= 0 && x == math.Floor(x)) {
+		return math.NaN()
+	}
+	if x < 0 {
+		return Digamma(1.0-x) - math.Pi/math.Tan(math.Pi*x)
+	}
+	for x < 10 {
+		res -= 1.0 / x
+		x++
+	}
+	// asymptotic expansion: ln(x) - 1/(2x) - Σ B₂ₖ/(2k x^(2k))
+	z := 1.0 / (x * x)
+	res += math.Log(x) - 0.5/x - z*(1.0/12-z*(1.0/120-z*(1.0/252-z*(1.0/240-z*(1.0/132-z*(691.0/32760-z/12))))))
+	return
+}
+
+// BetaInc computes the regularised incomplete beta function Iₓ(a,b) for a > 0, b > 0 and 0 ≤ x ≤ 1
+//
+//               1     x
+//   Iₓ(a,b) = ──────  ∫  t^(a-1) (1-t)^(b-1) dt
+//             B(a,b) 0
+//
+//   The continued fraction is evaluated by the modified Lentz's method using the symmetry
+//   Iₓ(a,b) = 1 - I₁₋ₓ(b,a) to ensure fast convergence [1]. See GammaP for references
+func BetaInc(a, b, x float64) float64 {
+	if a <= 0 || b <= 0 {
+		chk.Panic("a and b must be positive. a=%g and b=%g are invalid\n", a, b)
+	}
+	if x < 0 || x > 1 {
+		chk.Panic("x must be in [0,1]. x=%g is invalid\n", x)
+	}
+	if x == 0 || x == 1 {
+		return x
+	}
+	lbt := lgam(a+b) - lgam(a) - lgam(b) + a*math.Log(x) + b*math.Log1p(-x)
+	bt := math.Exp(lbt)
+	if x < (a+1.0)/(a+b+2.0) {
+		return bt * betaContFrac(a, b, x) / a
+	}
+	return 1.0 - bt*betaContFrac(b, a, 1.0-x)/b
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// checkGammaInc checks the arguments of incomplete gamma functions
+func checkGammaInc(a, x float64) {
+	if a <= 0 {
+		chk.Panic("a must be positive. a=%g is invalid\n", a)
+	}
+	if x < 0 {
+		chk.Panic("x must be non-negative. x=%g is invalid\n", x)
+	}
+}
+
+// lgam returns ln|Γ(x)|
+func lgam(x float64) float64 {
+	res, _ := math.Lgamma(x)
+	return res
+}
+
+// gammaSeries computes P(a,x) by its series expansion
+func gammaSeries(a, x float64) float64 {
+	ap := a
+	del := 1.0 / a
+	sum := del
+	for i := 0; i < spMaxIt; i++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*spEps {
+			return sum * math.Exp(-x+a*math.Log(x)-lgam(a))
+		}
+	}
+	chk.Panic("series of incomplete gamma function failed to converge. a=%g, x=%g\n", a, x)
+	return 0
+}
+
+// gammaContFrac computes Q(a,x) by its continued fraction (modified Lentz's method)
+func gammaContFrac(a, x float64) float64 {
+	b := x + 1.0 - a
+	c := 1.0 / spFpMin
+	d := 1.0 / b
+	h := d
+	for i := 1; i < spMaxIt; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2.0
+		d = an*d + b
+		if math.Abs(d) < spFpMin {
+			d = spFpMin
+		}
+		c = b + an/c
+		if math.Abs(c) < spFpMin {
+			c = spFpMin
+		}
+		d = 1.0 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1.0) <= spEps {
+			return math.Exp(-x+a*math.Log(x)-lgam(a)) * h
+		}
+	}
+	chk.Panic("continued fraction of incomplete gamma function failed to converge. a=%g, x=%g\n", a, x)
+	return 0
+}
+
+// betaContFrac evaluates the continued fraction of the incomplete beta function
+func betaContFrac(a, b, x float64) float64 {
+	qab := a + b
+	qap := a + 1.0
+	qam := a - 1.0
+	c := 1.0
+	d := 1.0 - qab*x/qap
+	if math.Abs(d) < spFpMin {
+		d = spFpMin
+	}
+	d = 1.0 / d
+	h := d
+	for m := 1; m < spMaxIt; m++ {
+		fm := float64(m)
+		m2 := 2.0 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1.0 + aa*d
+		if math.Abs(d) < spFpMin {
+			d = spFpMin
+		}
+		c = 1.0 + aa/c
+		if math.Abs(c) < spFpMin {
+			c = spFpMin
+		}
+		d = 1.0 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1.0 + aa*d
+		if math.Abs(d) < spFpMin {
+			d = spFpMin
+		}
+		c = 1.0 + aa/c
+		if math.Abs(c) < spFpMin {
+			c = spFpMin
+		}
+		d = 1.0 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1.0) <= spEps {
+			return h
+		}
+	}
+	chk.Panic("continued fraction of incomplete beta function failed to converge. a=%g, b=%g, x=%g\n", a, b, x)
+	return 0
+}
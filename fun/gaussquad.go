@@ -0,0 +1,308 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// Recurrence returns the coefficients of the three-term recurrence relation of the monic
+// orthogonal polynomials
+//  p(k+1, x) = (x - a[k]) ⋅ p(k, x) - b[k] ⋅ p(k-1, x)     k = 0...n-1
+//
+//  with p(-1, x) = 0, p(0, x) = 1 and b[0] = μ0 = ∫ w(x) dx (integral of the weight function)
+//
+//  NOTE: the recurrence coefficients are not limited by the max degree N
+func (o *GeneralOrthoPoly) Recurrence(n int) (a, b []float64) {
+	a = make([]float64, n)
+	b = make([]float64, n)
+	for k := 0; k < n; k++ {
+		a[k], b[k] = o.poly.rec(k)
+	}
+	return
+}
+
+// GaussXW computes the nodes and weights of the n-point Gauss quadrature rule associated with
+// the weight function of this polynomial
+//         ∫ f(x) w(x) dx  ≈  Σ w[j] f(x[j])      (exact if f is a polynomial of degree ≤ 2n-1)
+//
+//  Example: "L" → Gauss-Legendre, "H" → Gauss-Hermite, "La" → Gauss-Laguerre, "J" → Gauss-Jacobi
+func (o *GeneralOrthoPoly) GaussXW(n int) (x, w []float64) {
+	return GolubWelsch(o.Recurrence(n))
+}
+
+// GaussRadauXW computes the nodes and weights of the n-point Gauss-Radau quadrature rule with one
+// node fixed at x0 (usually one end of the interval); e.g. x0 = -1 for Legendre
+//  The rule is exact if f is a polynomial of degree ≤ 2n-2
+func (o *GeneralOrthoPoly) GaussRadauXW(n int, x0 float64) (x, w []float64) {
+	if n < 1 {
+		chk.Panic("number of points must be at least 1. n = %d is invalid", n)
+	}
+	a, b := o.Recurrence(n)
+
+	// modify the last diagonal entry such that x0 is a zero of p(n, x) [1]
+	p0, p1 := 0.0, 1.0
+	for k := 0; k < n-1; k++ {
+		pm1 := p0
+		p0 = p1
+		p1 = (x0-a[k])*p0 - b[k]*pm1
+	}
+	a[n-1] = x0 - b[n-1]*p0/p1
+	return GolubWelsch(a, b)
+}
+
+// GaussLobattoXW computes the nodes and weights of the n-point Gauss-Lobatto quadrature rule with two
+// nodes fixed at xl and xr (usually the ends of the interval); e.g. xl = -1 and xr = 1 for Legendre
+//  The rule is exact if f is a polynomial of degree ≤ 2n-3
+func (o *GeneralOrthoPoly) GaussLobattoXW(n int, xl, xr float64) (x, w []float64) {
+	if n < 2 {
+		chk.Panic("number of points must be at least 2. n = %d is invalid", n)
+	}
+	a, b := o.Recurrence(n)
+
+	// modify the last diagonal and off-diagonal entries such that xl and xr are zeros of p(n, x) [1]
+	p0l, p0r, p1l, p1r := 0.0, 0.0, 1.0, 1.0
+	for k := 0; k < n-1; k++ {
+		pm1l, pm1r := p0l, p0r
+		p0l, p0r = p1l, p1r
+		p1l = (xl-a[k])*p0l - b[k]*pm1l
+		p1r = (xr-a[k])*p0r - b[k]*pm1r
+	}
+	det := p1l*p0r - p1r*p0l
+	a[n-1] = (xl*p1l*p0r - xr*p1r*p0l) / det
+	b[n-1] = (xr - xl) * p1l * p1r / det
+	return GolubWelsch(a, b)
+}
+
+// GaussKronrodXW computes the nodes and weights of the (2n+1)-point Gauss-Kronrod quadrature rule
+// extending the n-point Gauss rule by Laurie's algorithm [2]
+//  Output:
+//    x  -- [2n+1] nodes, including the n Gauss nodes x[1], x[3], ..., x[2n-1]
+//    wk -- [2n+1] weights of the Kronrod rule (exact for polynomials of degree ≤ 3n+1)
+//    wg -- [2n+1] weights of the embedded Gauss rule; zero at the Kronrod-only nodes
+//
+//  The difference between the two rules can be used to estimate the error. The Kronrod extension
+//  with real nodes and positive weights does not exist for all weight functions (e.g. for
+//  Hermite and Laguerre with large n); in this case, a panic is raised.
+func (o *GeneralOrthoPoly) GaussKronrodXW(n int) (x, wk, wg []float64) {
+	if n < 1 {
+		chk.Panic("number of Gauss points must be at least 1. n = %d is invalid", n)
+	}
+	a0, b0 := o.Recurrence((3*n+1)/2 + 2)
+	a, b := kronrodRecurrence(n, a0, b0)
+	for k := 1; k < 2*n+1; k++ {
+		if !(b[k] > 0) {
+			chk.Panic("Gauss-Kronrod rule with real nodes and positive weights does not exist for n = %d", n)
+		}
+	}
+	x, wk = GolubWelsch(a, b)
+	xg, wgauss := GolubWelsch(a0[:n], b0[:n])
+	wg = make([]float64, 2*n+1)
+	for j := 0; j < n; j++ {
+		if math.Abs(x[2*j+1]-xg[j]) > 1e-8*(1+math.Abs(xg[j])) {
+			chk.Panic("Gauss nodes are not interlaced with Kronrod nodes: %g != %g", x[2*j+1], xg[j])
+		}
+		x[2*j+1] = xg[j]
+		wg[2*j+1] = wgauss[j]
+	}
+	return
+}
+
+// GolubWelsch computes the nodes and weights of the Gauss quadrature rule defined by the
+// coefficients of the three-term recurrence relation of monic orthogonal polynomials
+//  p(k+1, x) = (x - a[k]) ⋅ p(k, x) - b[k] ⋅ p(k-1, x)     with  b[0] = μ0 = ∫ w(x) dx
+//
+//  The nodes are the eigenvalues of the symmetric tridiagonal (Jacobi) matrix with diagonal a and
+//  off-diagonal √b[1...n-1]; the weights are w[j] = μ0 ⋅ v[0,j]², where v[:,j] is the j-th
+//  normalised eigenvector [3]. The nodes are sorted in increasing order.
+//
+//  References:
+//  [1] Gautschi W (2004) Orthogonal Polynomials: Computation and Approximation. Oxford University Press
+//  [2] Laurie DP (1997) Calculation of Gauss-Kronrod quadrature rules. Mathematics of Computation,
+//      66(219):1133-1145
+//  [3] Golub GH and Welsch JH (1969) Calculation of Gauss quadrature rules. Mathematics of
+//      Computation, 23(106):221-230
+func GolubWelsch(a, b []float64) (x, w []float64) {
+
+	// check
+	n := len(a)
+	if n < 1 || len(b) < n {
+		chk.Panic("at least one recurrence coefficient is required and len(b) must be ≥ len(a). len(a) = %d, len(b) = %d", len(a), len(b))
+	}
+
+	// Jacobi matrix
+	d := make([]float64, n)
+	e := make([]float64, n)
+	z := make([]float64, n)
+	for k := 0; k < n; k++ {
+		d[k] = a[k]
+		if k < n-1 {
+			if b[k+1] < 0 {
+				chk.Panic("recurrence coefficient b[%d] = %g must not be negative", k+1, b[k+1])
+			}
+			e[k] = math.Sqrt(b[k+1])
+		}
+	}
+	z[0] = 1
+
+	// eigenvalues and first components of eigenvectors
+	tridiagQL(d, e, z)
+
+	// sort
+	idx := make([]int, n)
+	for i := 0; i < n; i++ {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return d[idx[i]] < d[idx[j]] })
+	x = make([]float64, n)
+	w = make([]float64, n)
+	for i, k := range idx {
+		x[i] = d[k]
+		w[i] = b[0] * z[k] * z[k]
+	}
+	return
+}
+
+// tridiagQL computes the eigenvalues of a symmetric tridiagonal matrix by the QL algorithm with
+// implicit shifts. The rotations are also applied to z; thus, if z is the first row of the
+// identity matrix, z becomes the first row of the matrix of eigenvectors
+//  Input:
+//    d -- [n] diagonal; replaced by the eigenvalues
+//    e -- [n] off-diagonal: e[i] couples i and i+1; e[n-1] is not used. e is destroyed
+//    z -- [n] row vector to be rotated
+func tridiagQL(d, e, z []float64) {
+	n := len(d)
+	if n > 0 {
+		e[n-1] = 0
+	}
+	for l := 0; l < n; l++ {
+		for it := 0; ; it++ {
+
+			// find small off-diagonal element
+			m := l
+			for ; m < n-1; m++ {
+				dd := math.Abs(d[m]) + math.Abs(d[m+1])
+				if math.Abs(e[m])+dd == dd {
+					break
+				}
+			}
+			if m == l {
+				break
+			}
+			if it == 60 {
+				chk.Panic("QL algorithm did not converge")
+			}
+
+			// implicit shift
+			g := (d[l+1] - d[l]) / (2.0 * e[l])
+			r := math.Hypot(g, 1.0)
+			g = d[m] - d[l] + e[l]/(g+math.Copysign(r, g))
+			s, c, p := 1.0, 1.0, 0.0
+			i := m - 1
+			underflow := false
+			for ; i >= l; i-- {
+				f := s * e[i]
+				bb := c * e[i]
+				r = math.Hypot(f, g)
+				e[i+1] = r
+				if r == 0 {
+					d[i+1] -= p
+					e[m] = 0
+					underflow = true
+					break
+				}
+				s = f / r
+				c = g / r
+				g = d[i+1] - p
+				r = (d[i]-g)*s + 2.0*c*bb
+				p = s * r
+				d[i+1] = g + p
+				g = c*r - bb
+				f = z[i+1]
+				z[i+1] = s*z[i] + c*f
+				z[i] = c*z[i] - s*f
+			}
+			if underflow {
+				continue
+			}
+			d[l] -= p
+			e[l] = g
+			e[m] = 0
+		}
+	}
+}
+
+// kronrodRecurrence computes the recurrence coefficients of the (2n+1) Jacobi-Kronrod matrix from
+// the coefficients a0 and b0 of the original polynomials by Laurie's algorithm [2]
+//  Note: len(a0), len(b0) ≥ ⌊3n/2⌋ + 2
+func kronrodRecurrence(n int, a0, b0 []float64) (a, b []float64) {
+
+	// a and b use 1-based indices as in Laurie's paper; i.e. a[1] = a0[0]
+	a = make([]float64, 2*n+2)
+	b = make([]float64, 2*n+2)
+	for k := 0; k <= (3*n)/2; k++ {
+		a[k+1] = a0[k]
+	}
+	for k := 0; k <= (3*n+1)/2; k++ {
+		b[k+1] = b0[k]
+	}
+	ns := n/2 + 3
+	s := make([]float64, ns)
+	t := make([]float64, ns)
+	tmp := make([]float64, ns)
+	t[2] = b[n+2]
+
+	// eastern part
+	for m := 0; m <= n-2; m++ {
+		ks := []int{}
+		for k := (m + 1) / 2; k >= 0; k-- {
+			ks = append(ks, k)
+		}
+		for i, k := range ks {
+			l := m - k
+			tmp[i] = (a[k+n+2]-a[l+1])*t[k+2] + b[k+n+2]*s[k+1] - b[l+1]*s[k+2]
+		}
+		cum := 0.0
+		for i, k := range ks {
+			cum += tmp[i]
+			s[k+2] = cum
+		}
+		s, t = t, s
+	}
+	for j := n / 2; j >= 0; j-- {
+		s[j+2] = s[j+1]
+	}
+
+	// western part
+	for m := n - 1; m <= 2*n-3; m++ {
+		ks := []int{}
+		for k := m + 1 - n; k <= (m-1)/2; k++ {
+			ks = append(ks, k)
+		}
+		j := 0
+		for i, k := range ks {
+			l := m - k
+			j = n - 1 - l
+			tmp[i] = -(a[k+n+2]-a[l+1])*t[j+2] - b[k+n+2]*s[j+2] + b[l+1]*s[j+3]
+		}
+		cum := 0.0
+		for i, k := range ks {
+			cum += tmp[i]
+			s[n-1-(m-k)+2] = cum
+		}
+		k := (m + 1) / 2
+		if m%2 == 0 {
+			a[k+n+2] = a[k+1] + (s[j+2]-b[k+n+2]*s[j+3])/t[j+3]
+		} else {
+			b[k+n+2] = s[j+2] / s[j+3]
+		}
+		s, t = t, s
+	}
+	a[2*n+1] = a[n] - b[2*n+1]*s[2]/t[2]
+	return a[1:], b[1:]
+}
@@ -43,12 +43,13 @@ type GeneralOrthoPoly struct {
 //     "H" or "her"    : Hermite
 //     "T" or "cheby1" : Chebyshev first kind
 //     "U" or "cheby2" : Chebyshev second kind
+//     "La"            : generalized Laguerre
 //
 //   N -- is the (max) degree of the polynomial.
 //        Lower order can later be quickly obtained after this
 //        polynomial with max(N) is created
 //
-//   alpha -- Jacobi only: α coefficient
+//   alpha -- Jacobi and Laguerre only: α coefficient
 //
 //   beta -- Jacobi only: β coefficient
 //
@@ -111,11 +112,18 @@ func (o *GeneralOrthoPoly) P(n int, x float64) (res float64) {
 //                          /
 //                          ————
 //                          m = 0
+//
+//   The coefficients of the three-term recurrence relation of the monic polynomials
+//
+//        p(k+1, x) = (x - a(k)) ⋅ p(k, x) - b(k) ⋅ p(k-1, x)
+//
+//   are given by rec(k), where b(0) = μ0 = ∫ w(x) dx is the integral of the weight function
 type oPoly interface {
 	M(n int) int
 	d(n int) float64
 	c(n, m int) float64
 	g(n, m int, x float64) float64
+	rec(k int) (a, b float64)
 }
 
 // oPolyMaker defines a function that makes new oPolys
@@ -159,6 +167,24 @@ func (o *opJacobi) g(n, m int, x float64) float64 {
 	return math.Pow(x-1, float64(n-m)) * math.Pow(x+1, float64(m))
 }
 
+func (o *opJacobi) rec(k int) (a, b float64) {
+	α, β := o.alpha, o.beta
+	s := 2*float64(k) + α + β
+	switch k {
+	case 0:
+		a = (β - α) / (α + β + 2)
+		b = math.Pow(2, α+β+1) * Beta(α+1, β+1)
+	case 1:
+		a = (β*β - α*α) / (s * (s + 2))
+		b = 4 * (1 + α) * (1 + β) / ((2 + α + β) * (2 + α + β) * (3 + α + β))
+	default:
+		n := float64(k)
+		a = (β*β - α*α) / (s * (s + 2))
+		b = 4 * n * (n + α) * (n + β) * (n + α + β) / (s * s * (s + 1) * (s - 1))
+	}
+	return
+}
+
 func newJacobi(alpha, beta float64) oPoly {
 	o := new(opJacobi)
 	o.alpha = alpha
@@ -189,6 +215,14 @@ func (o *opLegendre) g(n, m int, x float64) float64 {
 	return math.Pow(x, float64(n-2*m))
 }
 
+func (o *opLegendre) rec(k int) (a, b float64) {
+	if k == 0 {
+		return 0, 2
+	}
+	n := float64(k)
+	return 0, n * n / (4*n*n - 1)
+}
+
 func newLegendre(alpha, beta float64) oPoly {
 	return new(opLegendre)
 }
@@ -215,6 +249,13 @@ func (o *opHermite) g(n, m int, x float64) float64 {
 	return math.Pow(2*x, float64(n-2*m))
 }
 
+func (o *opHermite) rec(k int) (a, b float64) {
+	if k == 0 {
+		return 0, math.Sqrt(math.Pi)
+	}
+	return 0, float64(k) / 2.0
+}
+
 func newHermite(alpha, beta float64) oPoly {
 	return new(opHermite)
 }
@@ -242,6 +283,16 @@ func (o *opChebyshev1) g(n, m int, x float64) float64 {
 	return math.Pow(2*x, float64(n-2*m))
 }
 
+func (o *opChebyshev1) rec(k int) (a, b float64) {
+	switch k {
+	case 0:
+		return 0, math.Pi
+	case 1:
+		return 0, 0.5
+	}
+	return 0, 0.25
+}
+
 func newChebyshev1(alpha, beta float64) oPoly {
 	return new(opChebyshev1)
 }
@@ -269,10 +320,56 @@ func (o *opChebyshev2) g(n, m int, x float64) float64 {
 	return math.Pow(2*x, float64(n-2*m))
 }
 
+func (o *opChebyshev2) rec(k int) (a, b float64) {
+	if k == 0 {
+		return 0, math.Pi / 2.0
+	}
+	return 0, 0.25
+}
+
 func newChebyshev2(alpha, beta float64) oPoly {
 	return new(opChebyshev2)
 }
 
+// Laguerre //////////////////////////////////////////////////////////////////////////////////////////
+
+type opLaguerre struct {
+	alpha float64
+}
+
+func (o *opLaguerre) M(n int) int {
+	return n
+}
+
+func (o *opLaguerre) d(n int) float64 {
+	return 1.0
+}
+
+func (o *opLaguerre) c(n, m int) float64 {
+	r := Rbinomial(float64(n)+o.alpha, float64(n-m))
+	s := Factorial22(m)
+	return math.Pow(-1, float64(m)) * r / s
+}
+
+func (o *opLaguerre) g(n, m int, x float64) float64 {
+	return math.Pow(x, float64(m))
+}
+
+func (o *opLaguerre) rec(k int) (a, b float64) {
+	n := float64(k)
+	a = 2*n + o.alpha + 1
+	if k == 0 {
+		return a, math.Gamma(o.alpha + 1)
+	}
+	return a, n * (n + o.alpha)
+}
+
+func newLaguerre(alpha, beta float64) oPoly {
+	o := new(opLaguerre)
+	o.alpha = alpha
+	return o
+}
+
 // add polynomials to database /////////////////////////////////////////////////////////////////////
 
 func init() {
@@ -281,4 +378,5 @@ func init() {
 	oPolyDB["H"] = newHermite
 	oPolyDB["T"] = newChebyshev1
 	oPolyDB["U"] = newChebyshev2
+	oPolyDB["La"] = newLaguerre
 }
@@ -0,0 +1,191 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// Hyp1f1 computes the confluent hypergeometric function of the first kind (Kummer's function)
+//
+//                      ∞   (a)ₖ  xᵏ
+//   ₁F₁(a; b; x) = M = Σ  ───── ───      where (a)ₖ = a (a+1) ... (a+k-1)
+//                     k=0  (b)ₖ  k!
+//
+//   The series is summed directly for x ≥ 0 (or if a is a non-positive integer; i.e. M is a
+//   polynomial). For x < 0, Kummer's transformation M(a; b; x) = exp(x) M(b-a; b; -x) is applied
+//   to avoid cancellation. b must not be a non-positive integer.
+//   NOTE: the number of terms increases with |x|; M overflows for x ≳ 700
+//
+//   References:
+//   [1] Abramowitz M, Stegun IA (1972) Handbook of Mathematical Functions with Formulas, Graphs,
+//       and Mathematical Tables. U.S. Department of Commerce, NIST
+func Hyp1f1(a, b, x float64) float64 {
+	if b <= 0 && b == math.Floor(b) {
+		chk.Panic("b must not be a non-positive integer. b=%g is invalid\n", b)
+	}
+	if a == b {
+		return math.Exp(x)
+	}
+	if x < 0 && !(a <= 0 && a == math.Floor(a)) {
+		return math.Exp(x) * Hyp1f1(b-a, b, -x)
+	}
+	return hypSeries(func(k float64) float64 { return (a + k) * x / ((b + k) * (k + 1)) })
+}
+
+// Hyp2f1 computes the Gauss hypergeometric function
+//
+//                      ∞   (a)ₖ (b)ₖ  xᵏ
+//   ₂F₁(a, b; c; x) =  Σ  ─────────── ───       for x ≤ 1
+//                     k=0    (c)ₖ      k!
+//
+//   The series is summed directly for 0 ≤ x ≤ 0.5 (or if a or b is a non-positive integer; i.e.
+//   ₂F₁ is a polynomial). For 0.5 < x < 1, the connection formulae with 1-x are used; i.e.
+//   15.3.6 of [1] or, if c-a-b is an integer, the logarithmic cases 15.3.10 and 15.3.11 of [1].
+//   For x < 0, Pfaff's transformation ₂F₁(a, b; c; x) = (1-x)^(-a) ₂F₁(a, c-b; c; x/(x-1)) maps
+//   the argument into [0,1); thus x < -1 corresponds to the 1/(1-x) transformation. At x = 1,
+//   Gauss' theorem ₂F₁ = Γ(c) Γ(c-a-b) / (Γ(c-a) Γ(c-b)) is used if c-a-b > 0 (+Inf otherwise).
+//   Returns NaN for x > 1. c must not be a non-positive integer.
+//
+//   (see Hyp1f1 for references)
+func Hyp2f1(a, b, c, x float64) float64 {
+	if isNonPosInt(c) {
+		chk.Panic("c must not be a non-positive integer. c=%g is invalid\n", c)
+	}
+	switch {
+	case math.IsNaN(x) || x > 1:
+		return math.NaN()
+	case x == 1:
+		if c-a-b <= 0 {
+			return math.Inf(1)
+		}
+		return gammaRatio([]float64{c, c - a - b}, []float64{c - a, c - b})
+	case x < 0:
+		return math.Pow(1-x, -a) * hyp2f1(a, c-b, c, x/(x-1), 1/(1-x))
+	}
+	return hyp2f1(a, b, c, x, 1-x)
+}
+
+// hyp2f1 computes ₂F₁(a, b; c; x) for 0 ≤ x < 1 with y = 1-x given separately to avoid cancellation
+func hyp2f1(a, b, c, x, y float64) float64 {
+	if x <= 0.5 || isNonPosInt(a) || isNonPosInt(b) {
+		return hyp2f1Series(a, b, c, x)
+	}
+	return hyp2f1Connect(a, b, c, x, y)
+}
+
+// hyp2f1Series sums the series of ₂F₁(a, b; c; x)
+func hyp2f1Series(a, b, c, x float64) float64 {
+	return hypSeries(func(k float64) float64 { return (a + k) * (b + k) * x / ((c + k) * (k + 1)) })
+}
+
+// hyp2f1Connect computes ₂F₁(a, b; c; x) for 0.5 < x < 1 using the connection formulae with y = 1-x
+//
+//   m = c-a-b not an integer (15.3.6 of [1]):
+//
+//               Γ(c) Γ(m)                                     Γ(c) Γ(-m)
+//     ₂F₁ = ───────────── ₂F₁(a, b; 1-m; y) + yᵐ ────────── ₂F₁(c-a, c-b; 1+m; y)
+//           Γ(c-a) Γ(c-b)                                   Γ(a) Γ(b)
+//
+//   m = n ≥ 0 an integer (15.3.10 and 15.3.11 of [1]):
+//
+//             Γ(n) Γ(c)    n-1 (a)ₖ (b)ₖ                    Γ(c)     ∞  (a+n)ₖ (b+n)ₖ
+//     ₂F₁ = ──────────────  Σ  ────────── yᵏ - (-y)ⁿ ───────── Σ  ─────────────── yᵏ hₖ
+//           Γ(a+n) Γ(b+n)  k=0 k! (1-n)ₖ                Γ(a) Γ(b) k=0  k! (k+n)!
+//
+//     with hₖ = ln y - ψ(k+1) - ψ(k+n+1) + ψ(a+k+n) + ψ(b+k+n)
+//
+//   m < 0 an integer: Euler's transformation ₂F₁(a, b; c; x) = yᵐ ₂F₁(c-a, c-b; c; x) is applied
+func hyp2f1Connect(a, b, c, x, y float64) float64 {
+
+	// polynomial after Euler's transformation
+	m := c - a - b
+	if isNonPosInt(c-a) || isNonPosInt(c-b) {
+		return math.Pow(y, m) * hyp2f1Series(c-a, c-b, c, x)
+	}
+
+	// non-integer c-a-b
+	if m != math.Floor(m) {
+		t1 := gammaRatio([]float64{c, m}, []float64{c - a, c - b}) * hyp2f1Series(a, b, 1-m, y)
+		t2 := gammaRatio([]float64{c, -m}, []float64{a, b}) * hyp2f1Series(c-a, c-b, 1+m, y)
+		return t1 + math.Pow(y, m)*t2
+	}
+	if m < 0 {
+		return math.Pow(y, m) * hyp2f1Connect(c-a, c-b, c, x, y)
+	}
+
+	// finite sum
+	n := int(m)
+	fin, term := 0.0, 1.0
+	for k := 0; k < n; k++ {
+		fin += term
+		fk := float64(k)
+		term *= (a + fk) * (b + fk) * y / ((fk + 1) * (fk + 1 - m))
+	}
+	if n > 0 {
+		fin *= gammaRatio([]float64{m, c}, []float64{a + m, b + m})
+	}
+
+	// logarithmic series
+	lny := math.Log(y)
+	sum, p := 0.0, 1.0/math.Gamma(m+1)
+	for k := 0; k < spMaxIt; k++ {
+		fk := float64(k)
+		h := lny - Digamma(fk+1) - Digamma(fk+m+1) + Digamma(a+fk+m) + Digamma(b+fk+m)
+		t := p * h
+		sum += t
+		if k > 0 && (t == 0 || math.Abs(t) <= spEps*math.Abs(sum)) {
+			return fin - math.Pow(-y, m)*gammaRatio([]float64{c}, []float64{a, b})*sum
+		}
+		p *= (a + m + fk) * (b + m + fk) * y / ((fk + 1) * (fk + m + 1))
+	}
+	chk.Panic("hypergeometric series failed to converge\n")
+	return 0
+}
+
+// hypSeries sums Σ tₖ with t₀ = 1 and tₖ₊₁ = tₖ ⋅ ratio(k)
+func hypSeries(ratio func(k float64) float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 0; k < spMaxIt; k++ {
+		r := ratio(float64(k))
+		term *= r
+		sum += term
+		if term == 0 || (math.Abs(term) <= spEps*math.Abs(sum) && math.Abs(r) < 1) {
+			return sum
+		}
+	}
+	chk.Panic("hypergeometric series failed to converge\n")
+	return 0
+}
+
+// gammaRatio computes Π Γ(num[i]) / Π Γ(den[j]); the result is zero if any den[j] is a pole
+func gammaRatio(num, den []float64) float64 {
+	lnr, sgn := 0.0, 1.0
+	for _, x := range num {
+		lnr += lgam(x)
+		sgn *= gammaSign(x)
+	}
+	for _, x := range den {
+		if isNonPosInt(x) {
+			return 0
+		}
+		lnr -= lgam(x)
+		sgn *= gammaSign(x)
+	}
+	return sgn * math.Exp(lnr)
+}
+
+// isNonPosInt returns whether x is a non-positive integer (0, -1, -2, ...)
+func isNonPosInt(x float64) bool {
+	return x <= 0 && x == math.Floor(x)
+}
+
+// gammaSign returns the sign of Γ(x); i.e. 1 for poles
+func gammaSign(x float64) float64 {
+	_, s := math.Lgamma(x)
+	return float64(s)
+}
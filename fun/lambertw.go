@@ -0,0 +1,83 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import "math"
+
+// LambertW0 computes the principal branch W₀(x) of the Lambert W function; i.e. the solution
+// w ≥ -1 of
+//
+//   w exp(w) = x       for x ≥ -1/e
+//
+//   The initial guess is given by the series about the branch point x = -1/e or Winitzki's
+//   approximation, followed by Halley's iterations [1]. Returns NaN for x < -1/e.
+//
+//   References:
+//   [1] Corless RM, Gonnet GH, Hare DEG, Jeffrey DJ, Knuth DE (1996) On the Lambert W function.
+//       Advances in Computational Mathematics, 5:329-359
+func LambertW0(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || x < -1.0/math.E-1e-16:
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return x
+	case x == 0:
+		return 0
+	}
+	p := math.Sqrt(math.Max(0, 2.0*(math.E*x+1.0)))
+	if p < 1e-3 {
+		return lambertBranchSeries(p)
+	}
+	var w float64
+	if x < -0.25 {
+		w = lambertBranchSeries(p)
+	} else {
+		l := math.Log1p(x)
+		w = l * (1.0 - math.Log1p(l)/(2.0+l))
+	}
+	return lambertHalley(w, x)
+}
+
+// LambertWm1 computes the lower branch W₋₁(x) of the Lambert W function; i.e. the solution w ≤ -1
+// of w exp(w) = x for -1/e ≤ x < 0. Returns NaN for x outside this range. See LambertW0
+func LambertWm1(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || x < -1.0/math.E-1e-16 || x >= 0:
+		return math.NaN()
+	}
+	p := math.Sqrt(math.Max(0, 2.0*(math.E*x+1.0)))
+	if p < 1e-3 {
+		return lambertBranchSeries(-p)
+	}
+	var w float64
+	if x < -0.25 {
+		w = lambertBranchSeries(-p)
+	} else {
+		l1 := math.Log(-x)
+		l2 := math.Log(-l1)
+		w = l1 - l2 + l2/l1
+	}
+	return lambertHalley(w, x)
+}
+
+// lambertBranchSeries evaluates the series of W about the branch point with p = ±√(2(ex+1))
+func lambertBranchSeries(p float64) float64 {
+	return -1 + p*(1+p*(-1.0/3+p*(11.0/72+p*(-43.0/540+p*(769.0/17280+p*(-221.0/8505))))))
+}
+
+// lambertHalley performs Halley's iterations to solve w exp(w) = x
+func lambertHalley(w, x float64) float64 {
+	for it := 0; it < 100; it++ {
+		ew := math.Exp(w)
+		f := w*ew - x
+		wp1 := w + 1.0
+		δ := f / (ew*wp1 - (w+2.0)*f/(2.0*wp1))
+		w -= δ
+		if math.Abs(δ) <= 1e-15*(1.0+math.Abs(w)) {
+			break
+		}
+	}
+	return w
+}
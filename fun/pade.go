@@ -0,0 +1,142 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
+
+// Pade implements the Padé approximant of type [L/M] computed from Taylor coefficients
+//             P(x)     p₀ + p₁ x + ... + pL xᴸ
+//    R(x) = ——————— = —————————————————————————
+//             Q(x)     1  + q₁ x + ... + qM xᴹ
+//
+//  such that f(x) - R(x) = O(xᴸ⁺ᴹ⁺¹) where f(x) = c₀ + c₁ x + c₂ x² + ...
+//
+//  The denominator coefficients are obtained from the linear system
+//
+//     M
+//     Σ  qⱼ c[L+k-j] = -c[L+k]     k = 1...M     (c[i] = 0 if i < 0)
+//    j=1
+//
+//  and the numerator from pᵢ = Σ_{j=0}^{min(i,M)} qⱼ c[i-j] with q₀ = 1.
+//
+//  NOTE: the system is singular for degenerate cases; e.g. if f is itself a rational function of
+//        lower type. Then, smaller L or M must be chosen.
+//
+//  References:
+//    [1] Baker GA, Graves-Morris P (1996) Padé Approximants, 2nd Edition. Cambridge University Press
+//    [2] Press WH, Teukolsky SA, Vetterling WT, Flannery BP (2007) Numerical Recipes: The Art of
+//        Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+type Pade struct {
+	L int       // degree of numerator
+	M int       // degree of denominator
+	P la.Vector // numerator coefficients [L+1]
+	Q la.Vector // denominator coefficients [M+1]; Q[0] = 1
+}
+
+// NewPade computes the [L/M] Padé approximant
+//  c -- Taylor coefficients about x = 0: len(c) ≥ L+M+1; e.g. cᵢ = f⁽ⁱ⁾(0) / i!
+func NewPade(c la.Vector, L, M int) (o *Pade) {
+	if L < 0 || M < 0 {
+		chk.Panic("degrees must be non-negative. L=%d and M=%d are invalid\n", L, M)
+	}
+	if len(c) < L+M+1 {
+		chk.Panic("at least L+M+1=%d Taylor coefficients are required. len(c)=%d is invalid\n", L+M+1, len(c))
+	}
+	coef := func(i int) float64 {
+		if i < 0 {
+			return 0
+		}
+		return c[i]
+	}
+	o = &Pade{L: L, M: M, P: la.NewVector(L + 1), Q: la.NewVector(M + 1)}
+	o.Q[0] = 1
+	if M > 0 {
+		A := la.NewMatrix(M, M)
+		b := la.NewVector(M)
+		for k := 1; k <= M; k++ {
+			for j := 1; j <= M; j++ {
+				A.Set(k-1, j-1, coef(L+k-j))
+			}
+			b[k-1] = -c[L+k]
+		}
+		la.DenSolve(o.Q[1:], A, b, false)
+	}
+	for i := 0; i <= L; i++ {
+		for j := 0; j <= i && j <= M; j++ {
+			o.P[i] += o.Q[j] * c[i-j]
+		}
+	}
+	return
+}
+
+// F evaluates the Padé approximant R(x)
+func (o *Pade) F(x float64) float64 {
+	return polyEval(o.P, x) / polyEval(o.Q, x)
+}
+
+// Fc evaluates the Padé approximant R(z) at a complex point
+func (o *Pade) Fc(z complex128) complex128 {
+	return polyEvalC(o.P, z) / polyEvalC(o.Q, z)
+}
+
+// Poles computes the poles of R; i.e. the roots of Q, and the corresponding residues P(p)/Q'(p)
+func (o *Pade) Poles() (poles, residues []complex128) {
+	poles = polyRoots(o.Q)
+	residues = make([]complex128, len(poles))
+	dQ := la.NewVector(o.M)
+	for i := 1; i <= o.M; i++ {
+		dQ[i-1] = float64(i) * o.Q[i]
+	}
+	for k, p := range poles {
+		residues[k] = polyEvalC(o.P, p) / polyEvalC(dQ, p)
+	}
+	return
+}
+
+// Zeros computes the zeros of R; i.e. the roots of P
+func (o *Pade) Zeros() []complex128 {
+	return polyRoots(o.P)
+}
+
+// polyEval evaluates the polynomial Σ a[i] xⁱ by Horner's rule
+func polyEval(a la.Vector, x float64) (res float64) {
+	for i := len(a) - 1; i >= 0; i-- {
+		res = res*x + a[i]
+	}
+	return
+}
+
+// polyEvalC evaluates the polynomial Σ a[i] zⁱ by Horner's rule at a complex point
+func polyEvalC(a la.Vector, z complex128) (res complex128) {
+	for i := len(a) - 1; i >= 0; i-- {
+		res = res*z + complex(a[i], 0)
+	}
+	return
+}
+
+// polyRoots computes the roots of the polynomial Σ a[i] xⁱ as the eigenvalues of the companion
+// matrix. Vanishing leading coefficients are ignored
+func polyRoots(a la.Vector) (roots []complex128) {
+	n := len(a) - 1
+	for n > 0 && a[n] == 0 {
+		n--
+	}
+	if n < 1 {
+		return
+	}
+	C := la.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		C.Set(0, i, -a[n-1-i]/a[n])
+		if i > 0 {
+			C.Set(i, i-1, 1)
+		}
+	}
+	roots = make([]complex128, n)
+	la.EigenVal(roots, C, false)
+	return
+}
@@ -0,0 +1,453 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/utl"
+)
+
+// BaryRational implements rational functions in barycentric form
+//             m   W[j] ⋅ F[j]
+//             Σ   ———————————
+//            j=0    x - Z[j]          n(x)
+//    r(x) = ——————————————————   =   ——————
+//              m     W[j]             d(x)
+//              Σ   ————————
+//             j=0  x - Z[j]
+//
+//  where Z are the support points, F the function values at the support points and W the
+//  barycentric weights. If all weights are non-zero, r(Z[j]) = F[j]. Different choices of weights
+//  lead to different rational functions; e.g. Floater-Hormann interpolants or AAA approximations.
+//  For Berrut-Trefethen weights of Lagrange polynomials, see LagrangeInterp.
+//
+//  References:
+//    [1] Berrut JP, Trefethen LN (2004) Barycentric Lagrange Interpolation,
+//        SIAM Review Vol. 46, No. 3, pp. 501-517
+//    [2] Floater MS, Hormann K (2007) Barycentric rational interpolation with no poles and high
+//        rates of approximation, Numerische Mathematik, 107:315-331
+//    [3] Nakatsukasa Y, Sète O, Trefethen LN (2018) The AAA algorithm for rational approximation,
+//        SIAM Journal on Scientific Computing, 40(3):A1494-A1522
+type BaryRational struct {
+	Z la.Vector // support points
+	F la.Vector // function values at support points
+	W la.Vector // barycentric weights
+}
+
+// NewFloaterHormann returns the Floater-Hormann rational interpolant of the data (x, y)
+//  The interpolant blends all polynomial interpolants of degree d over d+1 consecutive points and
+//  has no real poles. The approximation order is O(hᵈ⁺¹) for smooth functions. With d = n-1,
+//  where n = len(x), the polynomial interpolant is recovered.
+//
+//    d -- blending degree: 0 ≤ d < len(x)
+//    x -- data points (strictly increasing)
+//    y -- values y = f(x) at data points
+func NewFloaterHormann(d int, x, y la.Vector) (o *BaryRational) {
+	n := len(x)
+	if len(y) != n {
+		chk.Panic("x and y must have the same length. %d != %d\n", n, len(y))
+	}
+	if d < 0 || d >= n {
+		chk.Panic("blending degree must satisfy 0 ≤ d < %d. d=%d is invalid\n", n, d)
+	}
+	for k := 1; k < n; k++ {
+		if x[k] <= x[k-1] {
+			chk.Panic("data points must be strictly increasing. x[%d]=%g ≤ x[%d]=%g\n", k, x[k], k-1, x[k-1])
+		}
+	}
+	o = new(BaryRational)
+	o.Z = x.GetCopy()
+	o.F = y.GetCopy()
+	o.W = la.NewVector(n)
+	for k := 0; k < n; k++ {
+		sum := 0.0
+		for i := utl.Imax(0, k-d); i <= utl.Imin(k, n-1-d); i++ {
+			prod := 1.0
+			for j := i; j <= i+d; j++ {
+				if j != k {
+					prod /= math.Abs(x[k] - x[j])
+				}
+			}
+			sum += prod
+		}
+		o.W[k] = NegOnePowN(k-d) * sum
+	}
+	return
+}
+
+// NewAAA computes the AAA (adaptive Antoulas-Anderson) rational approximation of f
+//  Support points are chosen greedily from the sample points x where the error is largest; the
+//  weights minimise the linearised residual in the least-squares sense (via SVD). Afterwards,
+//  spurious poles with tiny residues (Froissart doublets) are removed by deleting the nearest
+//  support points and recomputing the weights.
+//
+//    x    -- sample points (distinct)
+//    f    -- function
+//    tol  -- relative tolerance; e.g. 1e-13. The iterations stop when max|f(x)-r(x)| ≤ tol⋅max|f(x)|
+//    mmax -- maximum number of support points; e.g. 100. The degree of r is at most mmax-1
+func NewAAA(x la.Vector, f Ss, tol float64, mmax int) (o *BaryRational) {
+
+	// sample function
+	nx := len(x)
+	if nx < 2 {
+		chk.Panic("at least 2 sample points are required\n")
+	}
+	fx := la.NewVector(nx)
+	fmax, fmean := 0.0, 0.0
+	for i := 0; i < nx; i++ {
+		fx[i] = f(x[i])
+		fmax = math.Max(fmax, math.Abs(fx[i]))
+		fmean += fx[i] / float64(nx)
+	}
+	if mmax > nx/2 {
+		mmax = nx / 2
+	}
+
+	// current approximation at sample points
+	r := la.NewVector(nx)
+	r.Fill(fmean)
+
+	// greedy iterations
+	o = new(BaryRational)
+	free := make([]bool, nx) // sample point is not a support point
+	for i := 0; i < nx; i++ {
+		free[i] = true
+	}
+	var sup []int
+	for m := 1; m <= mmax; m++ {
+
+		// new support point: point with largest error
+		jmax, emax := -1, -1.0
+		for i := 0; i < nx; i++ {
+			if free[i] && math.Abs(fx[i]-r[i]) > emax {
+				jmax, emax = i, math.Abs(fx[i]-r[i])
+			}
+		}
+		free[jmax] = false
+		sup = append(sup, jmax)
+
+		// weights and approximation
+		o.setAAA(x, fx, free, sup)
+		emax = 0
+		for i := 0; i < nx; i++ {
+			if free[i] {
+				r[i] = o.I(x[i])
+			} else {
+				r[i] = fx[i]
+			}
+			emax = math.Max(emax, math.Abs(fx[i]-r[i]))
+		}
+		if emax <= tol*fmax {
+			break
+		}
+	}
+
+	// remove Froissart doublets
+	poles, res := o.Poles()
+	newsup := make([]bool, nx)
+	for _, j := range sup {
+		newsup[j] = true
+	}
+	nrm := 0
+	for k, p := range poles {
+		if cmplx.Abs(res[k]) < 1e-13*fmax {
+			jmin, dmin := -1, math.Inf(1)
+			for _, j := range sup {
+				if d := cmplx.Abs(p - complex(x[j], 0)); newsup[j] && d < dmin {
+					jmin, dmin = j, d
+				}
+			}
+			if jmin >= 0 {
+				newsup[jmin] = false
+				free[jmin] = true
+				nrm++
+			}
+		}
+	}
+	if nrm > 0 {
+		var kept []int
+		for _, j := range sup {
+			if newsup[j] {
+				kept = append(kept, j)
+			}
+		}
+		o.setAAA(x, fx, free, kept)
+	}
+	return
+}
+
+// setAAA sets the support points and computes the AAA weights by solving the least-squares
+// problem min ‖A⋅w‖ with ‖w‖=1 where A is the Loewner matrix Aᵢⱼ = (f(xᵢ) - F[j]) / (xᵢ - Z[j])
+// for all sample points xᵢ that are not support points
+func (o *BaryRational) setAAA(x, fx la.Vector, free []bool, sup []int) {
+	m := len(sup)
+	o.Z = la.NewVector(m)
+	o.F = la.NewVector(m)
+	o.W = la.NewVector(m)
+	for j, k := range sup {
+		o.Z[j] = x[k]
+		o.F[j] = fx[k]
+	}
+	if m == 1 {
+		o.W[0] = 1
+		return
+	}
+	var rows []int
+	for i, isfree := range free {
+		if isfree {
+			rows = append(rows, i)
+		}
+	}
+	A := la.NewMatrix(len(rows), m)
+	for i, k := range rows {
+		for j := 0; j < m; j++ {
+			A.Set(i, j, (fx[k]-o.F[j])/(x[k]-o.Z[j]))
+		}
+	}
+	s := make([]float64, m)
+	u := la.NewMatrix(A.M, A.M)
+	vt := la.NewMatrix(m, m)
+	la.MatSvd(s, u, vt, A, false)
+	for j := 0; j < m; j++ {
+		o.W[j] = vt.Get(m-1, j) // right singular vector of the smallest singular value
+	}
+}
+
+// I evaluates the rational function r(x)
+func (o *BaryRational) I(x float64) float64 {
+	var num, den float64
+	for j, z := range o.Z {
+		if o.W[j] == 0 {
+			continue
+		}
+		dx := x - z
+		if dx == 0 {
+			return o.F[j]
+		}
+		num += o.W[j] * o.F[j] / dx
+		den += o.W[j] / dx
+	}
+	return num / den
+}
+
+// Ic evaluates the rational function r(z) at a complex point
+func (o *BaryRational) Ic(z complex128) complex128 {
+	var num, den complex128
+	for j, zj := range o.Z {
+		if o.W[j] == 0 {
+			continue
+		}
+		dz := z - complex(zj, 0)
+		if dz == 0 {
+			return complex(o.F[j], 0)
+		}
+		num += complex(o.W[j]*o.F[j], 0) / dz
+		den += complex(o.W[j], 0) / dz
+	}
+	return num / den
+}
+
+// Poles computes the poles p of the rational function and the corresponding residues
+//  The poles are the zeros of the denominator d(x); i.e. the finite eigenvalues of the arrowhead
+//  pencil (E, B) [3] which are computed here by deflating the pencil into a standard eigenvalue
+//  problem of size m-1. The residues are computed by n(p) / d'(p).
+//
+//  NOTE: support points with zero weight are poles that cancel out and are not included
+func (o *BaryRational) Poles() (poles, residues []complex128) {
+	poles = baryRoots(o.Z, o.W)
+	residues = make([]complex128, len(poles))
+	for k, p := range poles {
+		var num, dden complex128
+		for j, zj := range o.Z {
+			dz := p - complex(zj, 0)
+			num += complex(o.W[j]*o.F[j], 0) / dz
+			dden -= complex(o.W[j], 0) / (dz * dz)
+		}
+		residues[k] = num / dden
+	}
+	return
+}
+
+// Zeros computes the zeros of the rational function; i.e. the zeros of the numerator n(x)
+func (o *BaryRational) Zeros() []complex128 {
+	wf := la.NewVector(len(o.W))
+	for j := range o.W {
+		wf[j] = o.W[j] * o.F[j]
+	}
+	return baryRoots(o.Z, wf)
+}
+
+// baryRoots computes the finite roots of Σ w[j] / (x - z[j])
+//  The roots λ are the finite eigenvalues of
+//
+//           ┌              ┐         ┌         ┐
+//           │ 0   w₀ ... wₘ│         │ 0       │
+//    E⋅v =  │ 1   z₀       │ v = λ ⋅ │   1     │ ⋅ v = λ ⋅ B ⋅ v
+//           │ ⋮      ⋱     │         │     ⋱   │
+//           │ 1         zₘ │         │       1 │
+//           └              ┘         └         ┘
+//
+//  The pencil has r+1 infinite eigenvalues, where r-1 is the number of leading vanishing moments
+//  Σ w[j] z[j]ᵏ = 0 (k < r-1); e.g. r = d+1 for Floater-Hormann weights. These are deflated
+//  exactly by writing v = (v₀, Q⋅y), where the columns of Q span {w, D⋅w, ..., Dʳ⁻¹⋅w}⊥ and
+//  D = diag(z). With the columns of L spanning {1, D⋅1, ..., Dʳ⁻¹⋅1}⊥, the problem becomes the
+//  standard eigenvalue problem (Lᵀ⋅Q)⁻¹ ⋅ Lᵀ⋅D⋅Q ⋅ y = λ ⋅ y of size m-r. The eigenvalues are then
+//  polished by a few Newton iterations.
+func baryRoots(z, w la.Vector) (roots []complex128) {
+
+	// skip zero weights
+	var zz, ww []float64
+	for j := range w {
+		if w[j] != 0 {
+			zz = append(zz, z[j])
+			ww = append(ww, w[j])
+		}
+	}
+	m := len(zz)
+	if m < 2 {
+		return
+	}
+
+	// scale points into [-1, 1]
+	zmin, zmax := utl.MinMax(zz)
+	c, s := (zmin+zmax)/2, (zmax-zmin)/2
+	for j := range zz {
+		zz[j] = (zz[j] - c) / s
+	}
+
+	// number of vanishing moments
+	r := 1
+	for ; r < m; r++ {
+		var mom, nrm float64
+		for j := range zz {
+			t := ww[j] * math.Pow(zz[j], float64(r-1))
+			mom += t
+			nrm += math.Abs(t)
+		}
+		if math.Abs(mom) > 1e-10*nrm {
+			break
+		}
+	}
+	if r >= m {
+		return
+	}
+
+	// bases
+	one := make([]float64, m)
+	for i := range one {
+		one[i] = 1
+	}
+	Q := krylovComplement(zz, ww, r)
+	L := krylovComplement(zz, one, r)
+
+	// reduced matrices
+	n := m - r
+	A := la.NewMatrix(n, n)
+	B := la.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			var a, b float64
+			for k := 0; k < m; k++ {
+				a += L.Get(k, i) * zz[k] * Q.Get(k, j)
+				b += L.Get(k, i) * Q.Get(k, j)
+			}
+			A.Set(i, j, a)
+			B.Set(i, j, b)
+		}
+	}
+
+	// eigenvalues of B⁻¹⋅A
+	Bi := la.NewMatrix(n, n)
+	la.MatInv(Bi, B, false)
+	K := la.NewMatrix(n, n)
+	la.MatMatMul(K, 1, Bi, A)
+	roots = make([]complex128, n)
+	la.EigenVal(roots, K, false)
+
+	// polish roots with Newton's method (accepting only steps that reduce |Σ w/(x-z)|)
+	eval := func(x complex128) (f, df complex128) {
+		for j := range zz {
+			t := x - complex(zz[j], 0)
+			f += complex(ww[j], 0) / t
+			df -= complex(ww[j], 0) / (t * t)
+		}
+		return
+	}
+	for i := range roots {
+		f, df := eval(roots[i])
+		for it := 0; it < 5 && f != 0; it++ {
+			x := roots[i] - f/df
+			fnew, dfnew := eval(x)
+			if cmplx.Abs(fnew) >= cmplx.Abs(f) {
+				break
+			}
+			roots[i], f, df = x, fnew, dfnew
+		}
+		roots[i] = complex(c, 0) + complex(s, 0)*roots[i]
+	}
+	return
+}
+
+// krylovComplement returns an m×(m-r) matrix whose columns are an orthonormal basis of the
+// subspace orthogonal to the Krylov subspace {v, D⋅v, ..., Dʳ⁻¹⋅v}, where D = diag(z)
+func krylovComplement(z, v []float64, r int) (C *la.Matrix) {
+
+	// orthonormal basis of the Krylov subspace (Arnoldi with re-orthogonalisation)
+	m := len(z)
+	V := make([]la.Vector, r)
+	q := la.Vector(v).GetCopy()
+	for k := 0; k < r; k++ {
+		if k > 0 {
+			for i := range q {
+				q[i] = z[i] * V[k-1][i]
+			}
+		}
+		for pass := 0; pass < 2; pass++ {
+			for l := 0; l < k; l++ {
+				la.VecAdd(q, -la.VecDot(q, V[l]), V[l], 1, q)
+			}
+		}
+		V[k] = la.NewVector(m)
+		la.VecAdd(V[k], 0, q, 1/q.Norm(), q)
+	}
+
+	// Householder reflectors of the QR factorisation of V
+	U := make([]la.Vector, r)
+	for k := 0; k < r; k++ {
+		u := la.NewVector(m)
+		copy(u[k:], V[k][k:])
+		nrm := u.Norm()
+		if u[k] >= 0 {
+			u[k] += nrm
+		} else {
+			u[k] -= nrm
+		}
+		U[k] = la.NewVector(m)
+		la.VecAdd(U[k], 0, u, 1/u.Norm(), u)
+		u = U[k]
+		for j := k + 1; j < r; j++ {
+			la.VecAdd(V[j], -2*la.VecDot(u, V[j]), u, 1, V[j])
+		}
+	}
+
+	// last m-r columns of H₀⋅H₁⋯Hᵣ₋₁
+	C = la.NewMatrix(m, m-r)
+	e := la.NewVector(m)
+	for j := r; j < m; j++ {
+		e.Fill(0)
+		e[j] = 1
+		for k := r - 1; k >= 0; k-- {
+			la.VecAdd(e, -2*la.VecDot(U[k], e), U[k], 1, e)
+		}
+		for i := 0; i < m; i++ {
+			C.Set(i, j-r, e[i])
+		}
+	}
+	return
+}
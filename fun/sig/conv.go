@@ -0,0 +1,80 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/fft"
+	"github.com/cpmech/gosl/la"
+)
+
+// Convolve computes the (full) linear convolution of a and b using FFTs
+//         min(k,na-1)
+//  c[k] =     Σ      a[j] ⋅ b[k-j]      for k = 0 ... na+nb-2
+//        j=max(0,k-nb+1)
+func Convolve(a, b []float64) (c la.Vector) {
+	return fftConv(a, b, false)
+}
+
+// Correlate computes the (full) cross-correlation of a and b using FFTs
+//  c[k] = Σ a[j+l] ⋅ b[j]      with lag l = k - (nb-1)   for k = 0 ... na+nb-2
+//         j
+//
+//  NOTE: the auto-correlation is obtained with Correlate(x, x); the zero lag is at k = len(x)-1
+func Correlate(a, b []float64) (c la.Vector) {
+	return fftConv(a, b, true)
+}
+
+// fftConv computes the convolution of a and b (or reversed b) by FFTs
+func fftConv(a, b []float64, reverseB bool) (c la.Vector) {
+	na, nb := len(a), len(b)
+	if na < 1 || nb < 1 {
+		chk.Panic("input arrays must not be empty. na = %d and nb = %d are invalid\n", na, nb)
+	}
+	n := na + nb - 1
+	m := nextFastLen(n)
+	x := make([]float64, m)
+	X := make([]complex128, m/2+1)
+	Y := make([]complex128, m/2+1)
+	r2c := fft.NewPlanR2c(x, X)
+	copy(x, a)
+	r2c.Execute()
+	copy(Y, X)
+	for i := range x {
+		x[i] = 0
+	}
+	for j := 0; j < nb; j++ {
+		if reverseB {
+			x[j] = b[nb-1-j]
+		} else {
+			x[j] = b[j]
+		}
+	}
+	r2c.Execute()
+	for k := range X {
+		X[k] *= Y[k]
+	}
+	fft.NewPlanC2r(X, x).Execute()
+	c = la.NewVector(n)
+	for k := 0; k < n; k++ {
+		c[k] = x[k] / float64(m)
+	}
+	return
+}
+
+// nextFastLen returns the smallest integer ≥ n with prime factors 2, 3 and 5 only
+func nextFastLen(n int) int {
+	for m := n; ; m++ {
+		r := m
+		for _, p := range []int{2, 3, 5} {
+			for r%p == 0 {
+				r /= p
+			}
+		}
+		if r == 1 {
+			return m
+		}
+	}
+}
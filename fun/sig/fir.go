@@ -0,0 +1,116 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/la"
+)
+
+// FirWin designs a linear-phase FIR filter by the windowed sinc method
+//
+//   h[j] = w[j] ⋅ hideal[j - (ntaps-1)/2]    with   hideal[m] = 2ν sin(2πνm)/(2πνm)  (lowpass)
+//
+//  Input:
+//   kind  -- "lowpass", "highpass", "bandpass" or "bandstop"
+//   ntaps -- number of coefficients (filter order + 1). Must be odd for "highpass" and "bandstop"
+//   f1    -- cutoff frequency (lowpass and highpass) or lower cutoff frequency (band filters)
+//   f2    -- upper cutoff frequency (band filters only; ignored otherwise)
+//   fs    -- sampling frequency
+//   w     -- [ntaps] window; e.g. from Window or Kaiser. nil ⇒ Hamming window
+//  Output:
+//   h -- [ntaps] filter coefficients, scaled to have unit gain at the centre of the pass band
+//        (zero frequency, Nyquist frequency or (f1+f2)/2)
+func FirWin(kind string, ntaps int, f1, f2, fs float64, w la.Vector) (h la.Vector) {
+
+	// check
+	if ntaps < 1 {
+		chk.Panic("number of taps must be positive. ntaps = %d is invalid\n", ntaps)
+	}
+	nyq := fs / 2.0
+	if f1 <= 0 || f1 >= nyq {
+		chk.Panic("cutoff frequency must be in (0, fs/2). f1 = %g is invalid\n", f1)
+	}
+	band := kind == "bandpass" || kind == "bandstop"
+	if band && (f2 <= f1 || f2 >= nyq) {
+		chk.Panic("upper cutoff frequency must be in (f1, fs/2). f2 = %g is invalid\n", f2)
+	}
+	if (kind == "highpass" || kind == "bandstop") && ntaps%2 == 0 {
+		chk.Panic("number of taps must be odd for %q filters. ntaps = %d is invalid\n", kind, ntaps)
+	}
+	if w == nil {
+		w = Window("hamming", ntaps, false)
+	}
+	if len(w) != ntaps {
+		chk.Panic("length of window must be equal to ntaps = %d. %d is invalid\n", ntaps, len(w))
+	}
+
+	// ideal filter
+	ν1, ν2 := f1/fs, f2/fs
+	α := float64(ntaps-1) / 2.0
+	h = la.NewVector(ntaps)
+	lp := func(ν, m float64) float64 { return 2.0 * ν * fun.Sinc(2.0*math.Pi*ν*m) }
+	delta := func(m float64) float64 {
+		if m == 0 {
+			return 1
+		}
+		return 0
+	}
+	var νc float64 // frequency (normalised) at which the gain is unity
+	for j := 0; j < ntaps; j++ {
+		m := float64(j) - α
+		switch kind {
+		case "lowpass":
+			h[j] = lp(ν1, m)
+		case "highpass":
+			h[j] = delta(m) - lp(ν1, m)
+			νc = 0.5
+		case "bandpass":
+			h[j] = lp(ν2, m) - lp(ν1, m)
+			νc = (ν1 + ν2) / 2.0
+		case "bandstop":
+			h[j] = delta(m) - lp(ν2, m) + lp(ν1, m)
+		default:
+			chk.Panic("cannot find FIR filter kind %q\n", kind)
+		}
+		h[j] *= w[j]
+	}
+
+	// scale
+	s := 0.0
+	for j := 0; j < ntaps; j++ {
+		s += h[j] * math.Cos(2.0*math.Pi*νc*(float64(j)-α))
+	}
+	for j := 0; j < ntaps; j++ {
+		h[j] /= s
+	}
+	return
+}
+
+// FreqResp computes the frequency response of the filter with coefficients b and a
+//          Σ b[k] zᵏ
+//  H(f) = ───────────      with  z = exp(-i 2π f / fs)
+//          Σ a[k] zᵏ
+//
+//  NOTE: use a = nil for FIR filters
+func FreqResp(b, a []float64, f, fs float64) complex128 {
+	z := complex(math.Cos(2.0*math.Pi*f/fs), -math.Sin(2.0*math.Pi*f/fs))
+	num := polyvalz(b, z)
+	if a == nil {
+		return num
+	}
+	return num / polyvalz(a, z)
+}
+
+// polyvalz evaluates Σ c[k] zᵏ
+func polyvalz(c []float64, z complex128) (res complex128) {
+	for k := len(c) - 1; k >= 0; k-- {
+		res = res*z + complex(c[k], 0)
+	}
+	return
+}
@@ -0,0 +1,429 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
+
+// Iir implements a digital IIR filter as a cascade of second-order sections (biquads)
+//
+//          nsec-1   b0 + b1 z⁻¹ + b2 z⁻²
+//   H(z) =   Π     ──────────────────────
+//           s=0     1  + a1 z⁻¹ + a2 z⁻²
+//
+//  The filters are designed from analog prototypes (Butterworth or Chebyshev) transformed to the
+//  desired band and mapped to the digital domain by the bilinear transform with pre-warping of the
+//  cutoff frequencies. The second-order sections are much less sensitive to round-off errors
+//  than the transfer function written as a ratio of two polynomials.
+//
+//   Reference:
+//   [1] Oppenheim AV and Schafer RW (2010) Discrete-Time Signal Processing. 3rd Edition. Pearson
+type Iir struct {
+	Fs  float64     // sampling frequency
+	Sos [][]float64 // [nsec][6] second-order sections: {b0, b1, b2, 1, a1, a2}
+}
+
+// NewButter designs a Butterworth filter (maximally flat pass band)
+//
+//  Input:
+//   order -- order of the analog prototype (the order of band filters is 2⋅order)
+//   kind  -- "lowpass", "highpass", "bandpass" or "bandstop"
+//   f1    -- cutoff frequency (-3 dB) or lower cutoff frequency (band filters)
+//   f2    -- upper cutoff frequency (band filters only; ignored otherwise)
+//   fs    -- sampling frequency
+func NewButter(order int, kind string, f1, f2, fs float64) (o *Iir) {
+	checkOrder(order)
+	p := make([]complex128, order)
+	for k := 0; k < order; k++ {
+		p[k] = cmplx.Exp(complex(0, math.Pi*float64(2*k+order+1)/float64(2*order)))
+	}
+	return newIir(nil, p, 1, kind, f1, f2, fs)
+}
+
+// NewCheby1 designs a Chebyshev type I filter (equiripple pass band)
+//
+//  Input:
+//   rp -- maximum ripple in the pass band [dB]. The gain at the cutoff frequencies is -rp dB
+//   (see NewButter for the other arguments)
+func NewCheby1(order int, rp float64, kind string, f1, f2, fs float64) (o *Iir) {
+	checkOrder(order)
+	if rp <= 0 {
+		chk.Panic("pass band ripple must be positive. rp = %g is invalid\n", rp)
+	}
+	ε := math.Sqrt(math.Pow(10, rp/10) - 1)
+	μ := math.Asinh(1/ε) / float64(order)
+	p := make([]complex128, order)
+	k := complex(1, 0)
+	for i := 0; i < order; i++ {
+		θ := math.Pi * float64(2*i+1) / float64(2*order)
+		p[i] = complex(-math.Sinh(μ)*math.Sin(θ), math.Cosh(μ)*math.Cos(θ))
+		k *= -p[i]
+	}
+	gain := real(k)
+	if order%2 == 0 {
+		gain /= math.Sqrt(1 + ε*ε)
+	}
+	return newIir(nil, p, gain, kind, f1, f2, fs)
+}
+
+// NewCheby2 designs a Chebyshev type II filter (equiripple stop band)
+//
+//  Input:
+//   rs -- minimum attenuation in the stop band [dB]. The gain at the cutoff frequencies is -rs dB
+//         (i.e. f1 and f2 are the edges of the stop band)
+//   (see NewButter for the other arguments)
+func NewCheby2(order int, rs float64, kind string, f1, f2, fs float64) (o *Iir) {
+	checkOrder(order)
+	if rs <= 0 {
+		chk.Panic("stop band attenuation must be positive. rs = %g is invalid\n", rs)
+	}
+	ε := 1.0 / math.Sqrt(math.Pow(10, rs/10)-1)
+	μ := math.Asinh(1/ε) / float64(order)
+	var z []complex128
+	p := make([]complex128, order)
+	k := complex(1, 0)
+	for i := 0; i < order; i++ {
+		θ := math.Pi * float64(2*i+1) / float64(2*order)
+		if 2*i+1 != order {
+			z = append(z, complex(0, 1/math.Cos(θ)))
+			k /= -z[len(z)-1]
+		}
+		p[i] = 1 / complex(-math.Sinh(μ)*math.Sin(θ), math.Cosh(μ)*math.Cos(θ))
+		k *= -p[i]
+	}
+	return newIir(z, p, real(k), kind, f1, f2, fs)
+}
+
+// Filter applies the filter to x (causal filtering with zero initial conditions)
+func (o *Iir) Filter(x []float64) (y la.Vector) {
+	zi := make([][]float64, len(o.Sos))
+	for s := range zi {
+		zi[s] = make([]float64, 2)
+	}
+	y = la.NewVector(len(x))
+	copy(y, x)
+	o.run(y, zi)
+	return
+}
+
+// FiltFilt applies the filter forwards and backwards resulting in zero phase distortion. The
+// magnitude response is squared and the order is doubled. The ends of the signal are extended by
+// odd reflection and the initial states are set for the steady-state step response to reduce
+// transients.
+func (o *Iir) FiltFilt(x []float64) (y la.Vector) {
+
+	// extension
+	n := len(x)
+	if n < 2 {
+		chk.Panic("FiltFilt requires at least 2 points. n = %d is invalid\n", n)
+	}
+	npad := 3 * (2*len(o.Sos) + 1)
+	for _, s := range o.Sos {
+		if s[2] == 0 && s[5] == 0 {
+			npad -= 3
+		}
+	}
+	if npad > n-1 {
+		npad = n - 1
+	}
+	ext := make([]float64, n+2*npad)
+	for i := 0; i < npad; i++ {
+		ext[i] = 2*x[0] - x[npad-i]
+		ext[n+npad+i] = 2*x[n-1] - x[n-2-i]
+	}
+	copy(ext[npad:], x)
+
+	// forward
+	zi0 := o.stepStates()
+	zi := make([][]float64, len(o.Sos))
+	for s := range zi {
+		zi[s] = make([]float64, 2)
+	}
+	setStates := func(v float64) {
+		for s := range zi {
+			zi[s][0], zi[s][1] = zi0[s][0]*v, zi0[s][1]*v
+		}
+	}
+	setStates(ext[0])
+	o.run(ext, zi)
+
+	// backward
+	reverse(ext)
+	setStates(ext[0])
+	o.run(ext, zi)
+	reverse(ext)
+	y = la.NewVector(n)
+	copy(y, ext[npad:npad+n])
+	return
+}
+
+// H computes the frequency response H(exp(i 2π f / fs)) at frequency f
+func (o *Iir) H(f float64) (res complex128) {
+	res = 1
+	for _, s := range o.Sos {
+		res *= FreqResp(s[:3], s[3:], f, o.Fs)
+	}
+	return
+}
+
+// run filters data in place using the transposed direct form II and the states zi (modified)
+func (o *Iir) run(data []float64, zi [][]float64) {
+	for s, c := range o.Sos {
+		z := zi[s]
+		for i, u := range data {
+			v := c[0]*u + z[0]
+			z[0] = c[1]*u - c[4]*v + z[1]
+			z[1] = c[2]*u - c[5]*v
+			data[i] = v
+		}
+	}
+}
+
+// stepStates computes the states of each section for the steady-state response to a unit step
+func (o *Iir) stepStates() (zi [][]float64) {
+	zi = make([][]float64, len(o.Sos))
+	u := 1.0 // input of current section
+	for s, c := range o.Sos {
+		y := u * (c[0] + c[1] + c[2]) / (1 + c[4] + c[5])
+		z1 := c[2]*u - c[5]*y
+		z0 := c[1]*u - c[4]*y + z1
+		zi[s] = []float64{z0, z1}
+		u = y
+	}
+	return
+}
+
+// Lfilter filters x with the rational transfer function b(z⁻¹)/a(z⁻¹) (transposed direct form II
+// with zero initial conditions). Use a = nil or a = {1} for FIR filters; e.g. from FirWin
+//  a[0]⋅y[n] = Σ b[k]⋅x[n-k] - Σ_{k≥1} a[k]⋅y[n-k]
+func Lfilter(b, a, x []float64) (y la.Vector) {
+	if a == nil {
+		a = []float64{1}
+	}
+	if a[0] == 0 {
+		chk.Panic("a[0] must not be zero\n")
+	}
+	m := len(b)
+	if len(a) > m {
+		m = len(a)
+	}
+	bb := make([]float64, m)
+	aa := make([]float64, m)
+	for k := 0; k < len(b); k++ {
+		bb[k] = b[k] / a[0]
+	}
+	for k := 0; k < len(a); k++ {
+		aa[k] = a[k] / a[0]
+	}
+	z := make([]float64, m)
+	y = la.NewVector(len(x))
+	for i, u := range x {
+		v := bb[0]*u + z[0]
+		for k := 1; k < m; k++ {
+			z[k-1] = bb[k]*u - aa[k]*v + z[k]
+		}
+		y[i] = v
+	}
+	return
+}
+
+// design ///////////////////////////////////////////////////////////////////////////////////////////
+
+// newIir designs a digital filter from the zeros, poles and gain of the analog lowpass prototype
+// with unit cutoff frequency
+func newIir(z, p []complex128, k float64, kind string, f1, f2, fs float64) (o *Iir) {
+
+	// check
+	nyq := fs / 2.0
+	if f1 <= 0 || f1 >= nyq {
+		chk.Panic("cutoff frequency must be in (0, fs/2). f1 = %g is invalid\n", f1)
+	}
+	if (kind == "bandpass" || kind == "bandstop") && (f2 <= f1 || f2 >= nyq) {
+		chk.Panic("upper cutoff frequency must be in (f1, fs/2). f2 = %g is invalid\n", f2)
+	}
+
+	// pre-warped frequencies
+	ω1 := 2.0 * fs * math.Tan(math.Pi*f1/fs)
+	ω2 := 2.0 * fs * math.Tan(math.Pi*f2/fs)
+	ω0 := complex(math.Sqrt(ω1*ω2), 0)
+	bw := complex((ω2-ω1)/2.0, 0)
+	degree := len(p) - len(z)
+
+	// transform analog prototype
+	K := complex(k, 0)
+	switch kind {
+	case "lowpass":
+		w := complex(ω1, 0)
+		z, p = scaleRoots(w, z), scaleRoots(w, p)
+		K *= cmplx.Pow(w, complex(float64(degree), 0))
+	case "highpass":
+		w := complex(ω1, 0)
+		K *= prodNeg(z) / prodNeg(p)
+		z, p = invRoots(w, z), invRoots(w, p)
+		z = append(z, make([]complex128, degree)...)
+	case "bandpass":
+		z, p = bandRoots(ω0, scaleRoots(bw, z)), bandRoots(ω0, scaleRoots(bw, p))
+		z = append(z, make([]complex128, degree)...)
+		K *= cmplx.Pow(2*bw, complex(float64(degree), 0))
+	case "bandstop":
+		K *= prodNeg(z) / prodNeg(p)
+		z, p = bandRoots(ω0, invRoots(bw, z)), bandRoots(ω0, invRoots(bw, p))
+		for i := 0; i < degree; i++ {
+			z = append(z, complex(0, real(ω0)), complex(0, -real(ω0)))
+		}
+	default:
+		chk.Panic("cannot find IIR filter kind %q\n", kind)
+	}
+
+	// bilinear transform
+	fs2 := complex(2.0*fs, 0)
+	num, den := complex(1, 0), complex(1, 0)
+	for i := range z {
+		num *= fs2 - z[i]
+		z[i] = (fs2 + z[i]) / (fs2 - z[i])
+	}
+	for i := range p {
+		den *= fs2 - p[i]
+		p[i] = (fs2 + p[i]) / (fs2 - p[i])
+	}
+	for len(z) < len(p) {
+		z = append(z, -1)
+	}
+	K *= num / den
+
+	// second-order sections
+	//   the poles closest to the unit circle are matched with the nearest zeros first and are
+	//   placed in the last sections
+	pg := groupRoots(p, false)
+	zg := groupRoots(z, true)
+	sort.SliceStable(pg, func(i, j int) bool { return maxAbs(pg[i]) > maxAbs(pg[j]) })
+	o = new(Iir)
+	o.Fs = fs
+	o.Sos = make([][]float64, len(pg))
+	used := make([]bool, len(zg))
+	for s := range pg {
+		best, dmin := -1, 0.0
+		for i := range zg {
+			if used[i] {
+				continue
+			}
+			d := cmplx.Abs(zg[i][0] - pg[s][0])
+			if len(zg[i]) != len(pg[s]) {
+				d += 10 // penalty: distances between digital roots are at most 2
+			}
+			if best < 0 || d < dmin {
+				best, dmin = i, d
+			}
+		}
+		used[best] = true
+		b, a := quadratic(zg[best]), quadratic(pg[s])
+		o.Sos[len(pg)-1-s] = []float64{b[0], b[1], b[2], 1, a[1], a[2]}
+	}
+	for j := 0; j < 3; j++ {
+		o.Sos[0][j] *= real(K)
+	}
+	return
+}
+
+// checkOrder checks the order of filters
+func checkOrder(order int) {
+	if order < 1 {
+		chk.Panic("order of filter must be positive. order = %d is invalid\n", order)
+	}
+}
+
+// scaleRoots returns w⋅r
+func scaleRoots(w complex128, r []complex128) (res []complex128) {
+	res = make([]complex128, len(r))
+	for i := range r {
+		res[i] = w * r[i]
+	}
+	return
+}
+
+// invRoots returns w/r
+func invRoots(w complex128, r []complex128) (res []complex128) {
+	res = make([]complex128, len(r))
+	for i := range r {
+		res[i] = w / r[i]
+	}
+	return
+}
+
+// bandRoots returns r ± √(r² - ω0²)
+func bandRoots(ω0 complex128, r []complex128) (res []complex128) {
+	res = make([]complex128, 0, 2*len(r))
+	for i := range r {
+		s := cmplx.Sqrt(r[i]*r[i] - ω0*ω0)
+		res = append(res, r[i]+s, r[i]-s)
+	}
+	return
+}
+
+// prodNeg returns Π(-r)
+func prodNeg(r []complex128) (res complex128) {
+	res = 1
+	for i := range r {
+		res *= -r[i]
+	}
+	return
+}
+
+// groupRoots groups conjugate pairs and real roots into sets of one or two roots. Real zeros are
+// paired as largest with smallest (e.g. {1, -1} for band pass filters)
+func groupRoots(r []complex128, zeros bool) (groups [][]complex128) {
+	var reals []float64
+	for _, v := range r {
+		switch {
+		case math.Abs(imag(v)) <= 1e-10*math.Max(1, cmplx.Abs(v)):
+			reals = append(reals, real(v))
+		case imag(v) > 0:
+			groups = append(groups, []complex128{v, cmplx.Conj(v)})
+		}
+	}
+	sort.Float64s(reals)
+	for len(reals) > 1 {
+		if zeros {
+			groups = append(groups, []complex128{complex(reals[0], 0), complex(reals[len(reals)-1], 0)})
+			reals = reals[1 : len(reals)-1]
+		} else {
+			groups = append(groups, []complex128{complex(reals[0], 0), complex(reals[1], 0)})
+			reals = reals[2:]
+		}
+	}
+	if len(reals) == 1 {
+		groups = append(groups, []complex128{complex(reals[0], 0)})
+	}
+	return
+}
+
+// quadratic returns the coefficients of (1 - r0 z⁻¹)(1 - r1 z⁻¹)
+func quadratic(r []complex128) (c []float64) {
+	if len(r) == 1 {
+		return []float64{1, -real(r[0]), 0}
+	}
+	return []float64{1, -real(r[0] + r[1]), real(r[0] * r[1])}
+}
+
+// maxAbs returns the largest magnitude of roots
+func maxAbs(r []complex128) (res float64) {
+	for _, v := range r {
+		res = math.Max(res, cmplx.Abs(v))
+	}
+	return
+}
+
+// reverse reverses x in place
+func reverse(x []float64) {
+	for i, j := 0, len(x)-1; i < j; i, j = i+1, j-1 {
+		x[i], x[j] = x[j], x[i]
+	}
+}
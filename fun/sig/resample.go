@@ -0,0 +1,112 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/fft"
+	"github.com/cpmech/gosl/la"
+)
+
+// Resample resamples x to num points using the Fourier method; i.e. by truncating or zero-padding
+// the spectrum. The signal is assumed to be periodic; thus, this method is best suited for
+// periodic or windowed signals. The new sampling interval is len(x)/num times the original one.
+func Resample(x []float64, num int) (y la.Vector) {
+
+	// check
+	n := len(x)
+	if n < 1 || num < 1 {
+		chk.Panic("number of points must be positive. len(x) = %d and num = %d are invalid\n", n, num)
+	}
+
+	// spectrum
+	xx := make([]float64, n)
+	copy(xx, x)
+	X := make([]complex128, n/2+1)
+	fft.NewPlanR2c(xx, X).Execute()
+
+	// truncate or pad
+	Y := make([]complex128, num/2+1)
+	m := len(X)
+	if len(Y) < m {
+		m = len(Y)
+	}
+	copy(Y, X[:m])
+
+	// Nyquist component is split (upsampling) or merged (downsampling) for even lengths
+	if num < n && num%2 == 0 {
+		Y[num/2] = complex(real(Y[num/2]), 0) * 2 // X[k] + X[n-k] = 2 Re X[k] for real signals
+	}
+	if num > n && n%2 == 0 {
+		Y[n/2] /= 2
+	}
+
+	// inverse
+	y = la.NewVector(num)
+	fft.NewPlanC2r(Y, y).Execute()
+	for i := 0; i < num; i++ {
+		y[i] /= float64(n)
+	}
+	return
+}
+
+// ResamplePoly resamples x by the rational factor up/down using a polyphase FIR filter; i.e.
+// upsampling by zero insertion, lowpass filtering and downsampling. The anti-aliasing filter is
+// designed by FirWin with a Kaiser window (β = 5) and 20⋅max(up,down)+1 taps. The filter delay is
+// compensated; thus y[i] corresponds to the time i⋅down/up (in units of the original sampling
+// interval). The signal is assumed to be zero outside its range.
+func ResamplePoly(x []float64, up, down int) (y la.Vector) {
+
+	// check
+	n := len(x)
+	if n < 1 || up < 1 || down < 1 {
+		chk.Panic("len(x), up and down must be positive. %d, %d and %d are invalid\n", n, up, down)
+	}
+	g := gcd(up, down)
+	up, down = up/g, down/g
+	nout := (n*up + down - 1) / down
+	if up == 1 && down == 1 {
+		y = la.NewVector(n)
+		copy(y, x)
+		return
+	}
+
+	// filter
+	mx := up
+	if down > mx {
+		mx = down
+	}
+	half := 10 * mx
+	ntaps := 2*half + 1
+	h := FirWin("lowpass", ntaps, 0.5/float64(mx), 0, 1, Kaiser(ntaps, 5, false))
+	for i := range h {
+		h[i] *= float64(up)
+	}
+
+	// polyphase filtering: y[i] = Σ_k h[k] ⋅ xu[i⋅down + half - k] with xu[m] = x[m/up] if m%up == 0
+	y = la.NewVector(nout)
+	for i := 0; i < nout; i++ {
+		c := i*down + half
+		k0 := c % up // first tap hitting a non-zero sample
+		for k := k0; k < ntaps; k += up {
+			m := (c - k) / up
+			if m < 0 {
+				break
+			}
+			if m < n {
+				y[i] += h[k] * x[m]
+			}
+		}
+	}
+	return
+}
+
+// gcd returns the greatest common divisor
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,121 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math/cmplx"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/fft"
+	"github.com/cpmech/gosl/la"
+)
+
+// Welch estimates the (one-sided) power spectral density (PSD) by Welch's method; i.e. by
+// averaging the modified periodograms of overlapping segments
+//
+//              1      │ N-1                    -i 2π j k / N │²
+//   P[k] = ────────── │  Σ  w[j] ⋅ (x[j] - x̄) ⋅ e              │     (averaged over segments)
+//          fs Σ w[j]² │ j=0                                   │
+//
+//  The values at 0 < f < fs/2 are doubled; thus Σ P[k] ⋅ fs/N ≈ mean square value of x.
+//
+//  Input:
+//   x        -- signal
+//   fs       -- sampling frequency
+//   nperseg  -- length N of each segment (≤ len(x))
+//   noverlap -- number of points to overlap between segments (< nperseg); e.g. nperseg/2
+//   w        -- [nperseg] window; e.g. from Window("hann", nperseg, true). nil ⇒ periodic Hann
+//  Output:
+//   f   -- [N/2+1] frequencies
+//   psd -- [N/2+1] power spectral density
+//
+//   Reference:
+//   [1] Welch PD (1967) The use of fast Fourier transform for the estimation of power spectra: a
+//       method based on time averaging over short, modified periodograms. IEEE Transactions on
+//       Audio and Electroacoustics, 15(2):70-73
+func Welch(x []float64, fs float64, nperseg, noverlap int, w la.Vector) (f, psd la.Vector) {
+	f, _, S := Spectrogram(x, fs, nperseg, noverlap, w)
+	psd = la.NewVector(len(f))
+	for k := 0; k < S.M; k++ {
+		for j := 0; j < S.N; j++ {
+			psd[k] += S.Get(k, j)
+		}
+		psd[k] /= float64(S.N)
+	}
+	return
+}
+
+// Spectrogram computes the (one-sided) power spectral density of overlapping segments of x
+//
+//  Output:
+//   f -- [N/2+1] frequencies
+//   t -- [nseg] times corresponding to the centres of segments (t = 0 at x[0])
+//   S -- [N/2+1][nseg] power spectral density (same scaling as Welch)
+//
+//   (see Welch for the input arguments)
+func Spectrogram(x []float64, fs float64, nperseg, noverlap int, w la.Vector) (f, t la.Vector, S *la.Matrix) {
+
+	// check
+	n := len(x)
+	if nperseg < 1 || nperseg > n {
+		chk.Panic("length of segments must be in [1, len(x)=%d]. nperseg = %d is invalid\n", n, nperseg)
+	}
+	if noverlap < 0 || noverlap >= nperseg {
+		chk.Panic("overlap must be in [0, nperseg). noverlap = %d is invalid\n", noverlap)
+	}
+	if w == nil {
+		w = Window("hann", nperseg, true)
+	}
+	if len(w) != nperseg {
+		chk.Panic("length of window must be equal to nperseg = %d. %d is invalid\n", nperseg, len(w))
+	}
+
+	// frequencies and times
+	step := nperseg - noverlap
+	nseg := (n - noverlap) / step
+	nf := nperseg/2 + 1
+	f = la.NewVector(nf)
+	for k := 0; k < nf; k++ {
+		f[k] = float64(k) * fs / float64(nperseg)
+	}
+	t = la.NewVector(nseg)
+	for j := 0; j < nseg; j++ {
+		t[j] = (float64(j*step) + float64(nperseg)/2.0) / fs
+	}
+
+	// scaling
+	sw2 := 0.0
+	for _, v := range w {
+		sw2 += v * v
+	}
+	scale := 1.0 / (fs * sw2)
+
+	// segments
+	seg := make([]float64, nperseg)
+	X := make([]complex128, nf)
+	plan := fft.NewPlanR2c(seg, X)
+	S = la.NewMatrix(nf, nseg)
+	for j := 0; j < nseg; j++ {
+		s := x[j*step : j*step+nperseg]
+		mean := 0.0
+		for _, v := range s {
+			mean += v
+		}
+		mean /= float64(nperseg)
+		for i, v := range s {
+			seg[i] = w[i] * (v - mean)
+		}
+		plan.Execute()
+		for k := 0; k < nf; k++ {
+			a := cmplx.Abs(X[k])
+			p := a * a * scale
+			if k > 0 && !(nperseg%2 == 0 && k == nf-1) {
+				p *= 2
+			}
+			S.Set(k, j, p)
+		}
+	}
+	return
+}
@@ -0,0 +1,61 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestConv01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Conv01. convolution and correlation")
+
+	c := Convolve([]float64{1, 2, 3}, []float64{0, 1, 0.5})
+	io.Pforan("c = %v\n", c)
+	chk.Array(tst, "c", 1e-15, c, []float64{0, 1, 2.5, 4, 1.5})
+
+	// compare with direct sums
+	na, nb := 37, 11
+	a := make([]float64, na)
+	b := make([]float64, nb)
+	for i := range a {
+		a[i] = math.Sin(float64(i))
+	}
+	for i := range b {
+		b[i] = math.Cos(float64(i*i)) + 0.1
+	}
+	conv := make([]float64, na+nb-1)
+	corr := make([]float64, na+nb-1)
+	for k := 0; k < na+nb-1; k++ {
+		for j := 0; j < na; j++ {
+			if k-j >= 0 && k-j < nb {
+				conv[k] += a[j] * b[k-j]
+			}
+			l := k - (nb - 1)
+			if j-l >= 0 && j-l < nb {
+				corr[k] += a[j] * b[j-l]
+			}
+		}
+	}
+	chk.Array(tst, "Convolve", 1e-13, Convolve(a, b), conv)
+	chk.Array(tst, "Correlate", 1e-13, Correlate(a, b), corr)
+
+	// autocorrelation
+	r := Correlate(a, a)
+	sum := 0.0
+	for _, v := range a {
+		sum += v * v
+	}
+	chk.Float64(tst, "r(0)", 1e-13, r[na-1], sum)
+	chk.Float64(tst, "r(-1) - r(1)", 1e-13, r[na-2]-r[na], 0)
+
+	// fast lengths
+	chk.Ints(tst, "nextFastLen", []int{nextFastLen(1), nextFastLen(7), nextFastLen(17), nextFastLen(97)}, []int{1, 8, 18, 100})
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestFir01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Fir01. windowed sinc design")
+
+	fs := 1000.0
+	ntaps := 61
+	check := func(kind string, h []float64, pass, stop []float64) {
+		for j := 0; j < ntaps; j++ {
+			chk.Float64(tst, kind+": symmetry", 1e-15, h[j], h[ntaps-1-j])
+		}
+		for _, f := range pass {
+			g := cmplx.Abs(FreqResp(h, nil, f, fs))
+			io.Pf("%s: |H(%g)| = %g\n", kind, f, g)
+			chk.Float64(tst, kind+": pass band", 5e-3, g, 1)
+		}
+		for _, f := range stop {
+			g := cmplx.Abs(FreqResp(h, nil, f, fs))
+			io.Pf("%s: |H(%g)| = %g\n", kind, f, g)
+			if g > 5e-3 {
+				tst.Errorf("%s: gain in stop band is too large: |H(%g)| = %g\n", kind, f, g)
+			}
+		}
+	}
+
+	h := FirWin("lowpass", ntaps, 100, 0, fs, nil)
+	chk.Float64(tst, "lowpass: H(0)", 1e-14, real(FreqResp(h, nil, 0, fs)), 1)
+	check("lowpass", h, []float64{0, 30}, []float64{200, 350, 500})
+
+	h = FirWin("highpass", ntaps, 300, 0, fs, nil)
+	chk.Float64(tst, "highpass: H(fs/2)", 1e-14, cmplx.Abs(FreqResp(h, nil, 500, fs)), 1)
+	check("highpass", h, []float64{400, 500}, []float64{0, 100, 200})
+
+	h = FirWin("bandpass", ntaps, 150, 350, fs, nil)
+	chk.Float64(tst, "bandpass: |H(fc)|", 1e-14, cmplx.Abs(FreqResp(h, nil, 250, fs)), 1)
+	check("bandpass", h, []float64{250}, []float64{0, 50, 450, 500})
+
+	h = FirWin("bandstop", ntaps, 150, 350, fs, Kaiser(ntaps, KaiserBeta(60), false))
+	check("bandstop", h, []float64{0, 20, 480, 500}, []float64{250})
+}
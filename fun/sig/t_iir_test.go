@@ -0,0 +1,156 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestIir01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Iir01. Butterworth filters")
+
+	// reference coefficients (2nd order lowpass with cutoff = 0.2 × Nyquist)
+	o := NewButter(2, "lowpass", 0.2, 0, 2)
+	io.Pforan("sos = %v\n", o.Sos)
+	chk.Array(tst, "sos", 1e-7, o.Sos[0], []float64{0.06745527, 0.13491055, 0.06745527, 1, -1.1429805, 0.4128016})
+
+	// gains
+	fs := 1000.0
+	s2 := 1.0 / math.Sqrt2
+	o = NewButter(5, "lowpass", 100, 0, fs)
+	chk.Int(tst, "nsec", len(o.Sos), 3)
+	chk.Float64(tst, "lowpass: |H(0)|", 1e-14, cmplx.Abs(o.H(0)), 1)
+	chk.Float64(tst, "lowpass: |H(fc)|", 1e-14, cmplx.Abs(o.H(100)), s2)
+	chk.Float64(tst, "lowpass: |H(fs/2)|", 1e-14, cmplx.Abs(o.H(500)), 0)
+
+	o = NewButter(4, "highpass", 100, 0, fs)
+	chk.Float64(tst, "highpass: |H(fs/2)|", 1e-14, cmplx.Abs(o.H(500)), 1)
+	chk.Float64(tst, "highpass: |H(fc)|", 1e-14, cmplx.Abs(o.H(100)), s2)
+	chk.Float64(tst, "highpass: |H(0)|", 1e-14, cmplx.Abs(o.H(0)), 0)
+
+	f0 := centre(fs, 100, 200)
+	o = NewButter(3, "bandpass", 100, 200, fs)
+	chk.Int(tst, "nsec", len(o.Sos), 3)
+	chk.Float64(tst, "bandpass: |H(f1)|", 1e-13, cmplx.Abs(o.H(100)), s2)
+	chk.Float64(tst, "bandpass: |H(f2)|", 1e-13, cmplx.Abs(o.H(200)), s2)
+	chk.Float64(tst, "bandpass: |H(f0)|", 1e-13, cmplx.Abs(o.H(f0)), 1)
+	chk.Float64(tst, "bandpass: |H(0)|", 1e-13, cmplx.Abs(o.H(0)), 0)
+
+	o = NewButter(3, "bandstop", 100, 200, fs)
+	chk.Float64(tst, "bandstop: |H(f1)|", 1e-13, cmplx.Abs(o.H(100)), s2)
+	chk.Float64(tst, "bandstop: |H(f2)|", 1e-13, cmplx.Abs(o.H(200)), s2)
+	chk.Float64(tst, "bandstop: |H(f0)|", 1e-13, cmplx.Abs(o.H(f0)), 0)
+	chk.Float64(tst, "bandstop: |H(0)|", 1e-13, cmplx.Abs(o.H(0)), 1)
+}
+
+func TestIir02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Iir02. Chebyshev filters")
+
+	fs := 1000.0
+	rp, rs := 1.0, 40.0
+	gp, gs := math.Pow(10, -rp/20), math.Pow(10, -rs/20)
+
+	o := NewCheby1(4, rp, "lowpass", 100, 0, fs)
+	chk.Float64(tst, "cheby1(even): |H(0)|", 1e-13, cmplx.Abs(o.H(0)), gp)
+	chk.Float64(tst, "cheby1(even): |H(fc)|", 1e-13, cmplx.Abs(o.H(100)), gp)
+	o = NewCheby1(5, rp, "lowpass", 100, 0, fs)
+	chk.Float64(tst, "cheby1(odd): |H(0)|", 1e-13, cmplx.Abs(o.H(0)), 1)
+	chk.Float64(tst, "cheby1(odd): |H(fc)|", 1e-13, cmplx.Abs(o.H(100)), gp)
+	for _, f := range []float64{10, 30, 50, 70, 90} {
+		if g := cmplx.Abs(o.H(f)); g < gp-1e-13 || g > 1+1e-13 {
+			tst.Errorf("cheby1: gain in pass band is out of range: |H(%g)| = %g\n", f, g)
+		}
+	}
+
+	o = NewCheby1(3, rp, "bandpass", 100, 200, fs)
+	chk.Float64(tst, "cheby1 bandpass: |H(f1)|", 1e-13, cmplx.Abs(o.H(100)), gp)
+	chk.Float64(tst, "cheby1 bandpass: |H(f0)|", 1e-13, cmplx.Abs(o.H(centre(fs, 100, 200))), 1)
+
+	o = NewCheby2(4, rs, "lowpass", 100, 0, fs)
+	chk.Float64(tst, "cheby2: |H(0)|", 1e-13, cmplx.Abs(o.H(0)), 1)
+	chk.Float64(tst, "cheby2: |H(fc)|", 1e-13, cmplx.Abs(o.H(100)), gs)
+	for _, f := range []float64{120, 200, 300, 400, 500} {
+		if g := cmplx.Abs(o.H(f)); g > gs+1e-13 {
+			tst.Errorf("cheby2: gain in stop band is too large: |H(%g)| = %g\n", f, g)
+		}
+	}
+
+	o = NewCheby2(5, rs, "highpass", 100, 0, fs)
+	chk.Float64(tst, "cheby2 highpass: |H(fs/2)|", 1e-13, cmplx.Abs(o.H(500)), 1)
+	chk.Float64(tst, "cheby2 highpass: |H(fc)|", 1e-13, cmplx.Abs(o.H(100)), gs)
+
+	o = NewCheby2(4, rs, "bandstop", 100, 200, fs)
+	chk.Float64(tst, "cheby2 bandstop: |H(0)|", 1e-13, cmplx.Abs(o.H(0)), 1)
+	chk.Float64(tst, "cheby2 bandstop: |H(f1)|", 1e-13, cmplx.Abs(o.H(100)), gs)
+	chk.Float64(tst, "cheby2 bandstop: |H(f2)|", 1e-13, cmplx.Abs(o.H(200)), gs)
+}
+
+func TestIir03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Iir03. filtering and zero-phase filtering")
+
+	// signal
+	fs := 1000.0
+	n := 1000
+	x := make([]float64, n)
+	xslow := make([]float64, n)
+	for i := 0; i < n; i++ {
+		t := float64(i) / fs
+		xslow[i] = math.Sin(2*math.Pi*5*t) + 0.5
+		x[i] = xslow[i] + 0.5*math.Sin(2*math.Pi*150*t)
+	}
+
+	// Filter versus Lfilter with the expanded transfer function
+	o := NewButter(4, "lowpass", 30, 0, fs)
+	b, a := []float64{1}, []float64{1}
+	for _, s := range o.Sos {
+		b, a = polymul(b, s[:3]), polymul(a, s[3:])
+	}
+	y := o.Filter(x)
+	chk.Array(tst, "Filter", 1e-11, y, Lfilter(b, a, x))
+
+	// FIR filtering
+	h := FirWin("lowpass", 31, 30, 0, fs, nil)
+	chk.Array(tst, "Lfilter(FIR)", 1e-13, Lfilter(h, nil, x), Convolve(h, x)[:n])
+
+	// zero-phase (the ends are affected by the odd extension of the noisy signal)
+	y = o.FiltFilt(x)
+	err := 0.0
+	for i := 50; i < n-50; i++ {
+		err = math.Max(err, math.Abs(y[i]-xslow[i]))
+	}
+	io.Pforan("max error (filtfilt) = %v\n", err)
+	if err > 1e-2 {
+		tst.Errorf("FiltFilt failed: error = %g is too large\n", err)
+	}
+}
+
+// centre returns the digital centre frequency of band filters (geometric mean of warped frequencies)
+func centre(fs, f1, f2 float64) float64 {
+	ω1 := 2 * fs * math.Tan(math.Pi*f1/fs)
+	ω2 := 2 * fs * math.Tan(math.Pi*f2/fs)
+	return fs / math.Pi * math.Atan(math.Sqrt(ω1*ω2)/(2*fs))
+}
+
+// polymul multiplies polynomials
+func polymul(p, q []float64) (r []float64) {
+	r = make([]float64, len(p)+len(q)-1)
+	for i := range p {
+		for j := range q {
+			r[i+j] += p[i] * q[j]
+		}
+	}
+	return
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestResample01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Resample01. Fourier method")
+
+	// periodic signal
+	f := func(t float64) float64 { return math.Sin(2*math.Pi*3*t) + 0.5*math.Cos(2*math.Pi*7*t) + 0.2 }
+	n := 40
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = f(float64(i) / float64(n))
+	}
+
+	// up and down
+	for _, num := range []int{100, 41, 25, 20, 40} {
+		y := Resample(x, num)
+		yref := make([]float64, num)
+		for i := 0; i < num; i++ {
+			yref[i] = f(float64(i) / float64(num))
+		}
+		io.Pf("num = %d\n", num)
+		chk.Array(tst, io.Sf("y(num=%d)", num), 1e-13, y, yref)
+	}
+
+	// Nyquist component
+	x = []float64{1, -1, 1, -1}
+	chk.Array(tst, "nyquist", 1e-15, Resample(x, 8), []float64{1, 0, -1, 0, 1, 0, -1, 0})
+}
+
+func TestResample02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Resample02. polyphase")
+
+	f := func(t float64) float64 { return math.Sin(2*math.Pi*0.02*t) + 0.3*math.Cos(2*math.Pi*0.05*t) }
+	n := 300
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = f(float64(i))
+	}
+	for _, ud := range [][]int{{3, 2}, {2, 3}, {4, 1}, {1, 2}} {
+		up, down := ud[0], ud[1]
+		y := ResamplePoly(x, up, down)
+		chk.Int(tst, "len(y)", len(y), (n*up+down-1)/down)
+		err := 0.0
+		margin := 25 * up
+		for i := margin; i < len(y)-margin; i++ {
+			err = math.Max(err, math.Abs(y[i]-f(float64(i*down)/float64(up))))
+		}
+		io.Pf("up = %d down = %d error = %v\n", up, down, err)
+		if err > 5e-3 {
+			tst.Errorf("ResamplePoly(%d,%d) failed: error = %g is too large\n", up, down, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestSpectral01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral01. Welch PSD")
+
+	// signal: A sin(2π f0 t + φ) + offset
+	fs, A, f0 := 1000.0, 2.0, 62.5
+	n := 4096
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = A*math.Sin(2*math.Pi*f0*float64(i)/fs+0.3) + 1.5
+	}
+
+	// PSD
+	nperseg := 256
+	f, psd := Welch(x, fs, nperseg, nperseg/2, nil)
+	chk.Int(tst, "len(f)", len(f), nperseg/2+1)
+	chk.Float64(tst, "df", 1e-15, f[1]-f[0], fs/float64(nperseg))
+
+	// peak and power
+	kmax, power := 0, 0.0
+	for k := range psd {
+		if psd[k] > psd[kmax] {
+			kmax = k
+		}
+		power += psd[k] * fs / float64(nperseg)
+	}
+	io.Pforan("peak @ %g Hz; power = %v\n", f[kmax], power)
+	chk.Float64(tst, "f(peak)", 1e-15, f[kmax], f0)
+	chk.Float64(tst, "power", 1e-10, power, A*A/2)
+}
+
+func TestSpectral02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral02. spectrogram")
+
+	// signal: frequency changes at the middle
+	fs := 1000.0
+	n := 2048
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		f := 62.5
+		if i >= n/2 {
+			f = 187.5
+		}
+		x[i] = math.Sin(2 * math.Pi * f * float64(i) / fs)
+	}
+
+	// spectrogram without overlap
+	nperseg := 128
+	f, t, S := Spectrogram(x, fs, nperseg, 0, nil)
+	chk.Int(tst, "nseg", len(t), n/nperseg)
+	chk.Int(tst, "nfreq", S.M, len(f))
+	chk.Float64(tst, "t[0]", 1e-15, t[0], 64/fs)
+	for j := 0; j < S.N; j++ {
+		kmax := 0
+		for k := 0; k < S.M; k++ {
+			if S.Get(k, j) > S.Get(kmax, j) {
+				kmax = k
+			}
+		}
+		fref := 62.5
+		if t[j] > float64(n/2)/fs {
+			fref = 187.5
+		}
+		chk.Float64(tst, io.Sf("f(peak) @ t=%.3f", t[j]), 1e-15, f[kmax], fref)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sig
+
+import (
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestWindows01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Windows01. cosine windows")
+
+	w := Window("hann", 5, false)
+	io.Pforan("hann = %v\n", w)
+	chk.Array(tst, "hann", 1e-15, w, []float64{0, 0.5, 1, 0.5, 0})
+
+	w = Window("hann", 4, true)
+	chk.Array(tst, "hann (periodic)", 1e-15, w, []float64{0, 0.5, 1, 0.5})
+
+	w = Window("hamming", 3, false)
+	chk.Array(tst, "hamming", 1e-15, w, []float64{0.08, 1, 0.08})
+
+	w = Window("blackman", 3, false)
+	chk.Array(tst, "blackman", 1e-15, w, []float64{0, 1, 0})
+
+	w = Window("bartlett", 5, false)
+	chk.Array(tst, "bartlett", 1e-15, w, []float64{0, 0.5, 1, 0.5, 0})
+
+	w = Window("rect", 3, false)
+	chk.Array(tst, "rect", 1e-15, w, []float64{1, 1, 1})
+
+	w = Window("flattop", 3, false)
+	chk.Array(tst, "flattop", 1e-15, w, []float64{-0.000421051, 1.000000003, -0.000421051})
+}
+
+func TestWindows02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Windows02. Kaiser and Tukey windows")
+
+	w := Kaiser(5, 0, false)
+	chk.Array(tst, "kaiser(β=0)", 1e-15, w, []float64{1, 1, 1, 1, 1})
+
+	β := 8.6
+	w = Kaiser(7, β, false)
+	io.Pforan("kaiser = %v\n", w)
+	chk.Float64(tst, "w[0]", 1e-14, w[0], 1.0/fun.ModBesselI0(β))
+	chk.Float64(tst, "w[3]", 1e-15, w[3], 1)
+	chk.Float64(tst, "w[1]-w[5]", 1e-15, w[1]-w[5], 0)
+
+	chk.Float64(tst, "β(60dB)", 1e-15, KaiserBeta(60), 0.1102*(60-8.7))
+	chk.Float64(tst, "β(10dB)", 1e-15, KaiserBeta(10), 0)
+
+	w = Tukey(9, 1, false)
+	chk.Array(tst, "tukey(α=1)", 1e-15, w, Window("hann", 9, false))
+	w = Tukey(9, 0, false)
+	chk.Array(tst, "tukey(α=0)", 1e-15, w, Window("rect", 9, false))
+	w = Tukey(9, 0.5, false)
+	io.Pforan("tukey = %v\n", w)
+	chk.Array(tst, "tukey(α=0.5)", 1e-15, w, []float64{0, 0.5, 1, 1, 1, 1, 1, 0.5, 0})
+}
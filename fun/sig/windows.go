@@ -0,0 +1,126 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sig implements signal processing tools such as window functions, FIR and IIR filters,
+// convolution, spectral estimation and resampling. The transforms are computed by fun/fft
+package sig
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/la"
+)
+
+// Window computes a window function with N points
+//  kind -- "rect", "hann", "hamming", "blackman", "bartlett" or "flattop"
+//  periodic -- computes the periodic (DFT-even) version, suitable for spectral analysis, instead
+//              of the symmetric version, suitable for filter design. The periodic window of
+//              length N equals the first N points of the symmetric window of length N+1
+//
+//  The windows are (with M = N-1 or M = N if periodic):
+//
+//    hann:     w[j] = 0.5 - 0.5 cos(2πj/M)
+//    hamming:  w[j] = 0.54 - 0.46 cos(2πj/M)
+//    blackman: w[j] = 0.42 - 0.5 cos(2πj/M) + 0.08 cos(4πj/M)
+//    bartlett: w[j] = 1 - |2j/M - 1|
+//    flattop:  w[j] = Σ_k (-1)ᵏ aₖ cos(2πkj/M) with a = {0.21557895, 0.41663158, 0.277263158,
+//                     0.083578947, 0.006947368}
+func Window(kind string, N int, periodic bool) (w la.Vector) {
+	var cosines []float64
+	switch kind {
+	case "rect":
+		w = la.NewVector(N)
+		w.Fill(1)
+		return
+	case "hann":
+		cosines = []float64{0.5, 0.5}
+	case "hamming":
+		cosines = []float64{0.54, 0.46}
+	case "blackman":
+		cosines = []float64{0.42, 0.5, 0.08}
+	case "flattop":
+		cosines = []float64{0.21557895, 0.41663158, 0.277263158, 0.083578947, 0.006947368}
+	case "bartlett":
+		w = la.NewVector(N)
+		M := winM(N, periodic)
+		for j := 0; j < N; j++ {
+			w[j] = 1.0 - math.Abs(2.0*float64(j)/M-1.0)
+		}
+		return
+	default:
+		chk.Panic("cannot find window named %q\n", kind)
+	}
+	w = la.NewVector(N)
+	M := winM(N, periodic)
+	for j := 0; j < N; j++ {
+		for k, a := range cosines {
+			w[j] += fun.NegOnePowN(k) * a * math.Cos(2.0*math.Pi*float64(k*j)/M)
+		}
+	}
+	return
+}
+
+// Kaiser computes the Kaiser window with N points and shape parameter β
+//         I₀(β √(1 - (2j/M - 1)²))
+//  w[j] = ────────────────────────       with M = N-1 or M = N if periodic
+//                  I₀(β)
+func Kaiser(N int, β float64, periodic bool) (w la.Vector) {
+	w = la.NewVector(N)
+	M := winM(N, periodic)
+	den := fun.ModBesselI0(β)
+	for j := 0; j < N; j++ {
+		r := 2.0*float64(j)/M - 1.0
+		w[j] = fun.ModBesselI0(β*math.Sqrt(math.Max(0, 1.0-r*r))) / den
+	}
+	return
+}
+
+// KaiserBeta returns the shape parameter β of the Kaiser window for a stop band attenuation of
+// atten decibels (Kaiser's empirical formula)
+func KaiserBeta(atten float64) float64 {
+	switch {
+	case atten > 50:
+		return 0.1102 * (atten - 8.7)
+	case atten > 21:
+		return 0.5842*math.Pow(atten-21, 0.4) + 0.07886*(atten-21)
+	}
+	return 0
+}
+
+// Tukey computes the Tukey (tapered cosine) window with N points. The fraction α of the window is
+// inside the cosine tapered region; α = 0 gives the rectangular window and α = 1 the Hann window
+func Tukey(N int, α float64, periodic bool) (w la.Vector) {
+	w = la.NewVector(N)
+	M := winM(N, periodic)
+	for j := 0; j < N; j++ {
+		x := float64(j) / M
+		switch {
+		case α <= 0:
+			w[j] = 1
+		case x < α/2:
+			w[j] = 0.5 * (1.0 - math.Cos(2.0*math.Pi*x/α))
+		case x > 1-α/2:
+			w[j] = 0.5 * (1.0 - math.Cos(2.0*math.Pi*(1-x)/α))
+		default:
+			w[j] = 1
+		}
+	}
+	return
+}
+
+// winM returns the denominator of windows: N-1 (symmetric) or N (periodic)
+func winM(N int, periodic bool) float64 {
+	if N < 1 {
+		chk.Panic("number of points of window must be positive. N = %d is invalid\n", N)
+	}
+	if periodic {
+		return float64(N)
+	}
+	if N == 1 {
+		return 1
+	}
+	return float64(N - 1)
+}
@@ -0,0 +1,50 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestAiry01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Airy01. Ai, Bi and derivatives")
+
+	// reference values: {x, Ai, Bi, Ai', Bi'} computed by the power series with 80 digits
+	ref := [][]float64{
+		{-5, 3.50761009024114334e-01, -1.38369134901600577e-01, 3.27192818554443154e-01, 7.78411773001899232e-01},
+		{-2, 2.27407428201685580e-01, -4.12302587956398514e-01, 6.18259020741691034e-01, 2.78795166921169513e-01},
+		{-1, 5.35560883292352075e-01, 1.03997389496944606e-01, -1.01605671166452097e-02, 5.92375626422792401e-01},
+		{-0.5, 4.75728091610539583e-01, 3.80352659751053868e-01, -2.04081670339547383e-01, 5.05933713623847203e-01},
+		{0, 3.55028053887817239e-01, 6.14926627446000736e-01, -2.58819403792806798e-01, 4.48288357353826357e-01},
+		{0.5, 2.31693606480833481e-01, 8.54277043103155442e-01, -2.24910532664683888e-01, 5.44572564140592297e-01},
+		{1, 1.35292416312881414e-01, 1.20742359495287133e+00, -1.59147441296793202e-01, 9.32435933392775640e-01},
+		{2, 3.49241304232743785e-02, 3.29809499997821476e+00, -5.30903844336536312e-02, 4.10068204993288976e+00},
+		{3, 6.59113935746071921e-03, 1.40373289637302321e+01, -1.19129767059513187e-02, 2.29222149663821710e+01},
+	}
+	for _, r := range ref {
+		x := r[0]
+		ai, bi, aip, bip := Airy(x)
+		io.Pf("x = %5.2f  Ai = %23.15e  Bi = %23.15e\n", x, ai, bi)
+		chk.Float64(tst, io.Sf("Ai(%g)", x), 1e-15+1e-14*math.Abs(r[1]), ai, r[1])
+		chk.Float64(tst, io.Sf("Bi(%g)", x), 1e-15+1e-14*math.Abs(r[2]), bi, r[2])
+		chk.Float64(tst, io.Sf("Ai'(%g)", x), 1e-15+1e-14*math.Abs(r[3]), aip, r[3])
+		chk.Float64(tst, io.Sf("Bi'(%g)", x), 1e-15+1e-14*math.Abs(r[4]), bip, r[4])
+		chk.Float64(tst, io.Sf("AiryAi(%g)", x), 1e-17, AiryAi(x), ai)
+		chk.Float64(tst, io.Sf("AiryBi(%g)", x), 1e-17, AiryBi(x), bi)
+	}
+
+	// Wronskian: Ai Bi' - Ai' Bi = 1/π
+	io.Pl()
+	for _, x := range []float64{-30, -8, -1.5, 0.2, 4, 10} {
+		ai, bi, aip, bip := Airy(x)
+		chk.Float64(tst, io.Sf("W(%g)", x), 1e-13, (ai*bip-aip*bi)*math.Pi, 1)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestBesselNu01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselNu01. Jν and Yν: integer orders and closed forms")
+
+	xx := []float64{0.01, 0.3, 1, 1.9, 2, 2.5, 5, 10, 30, 100}
+
+	// integer orders versus standard library
+	for _, n := range []int{0, 1, 2, 5, 10} {
+		for _, x := range xx {
+			j, y := BesselJ(float64(n), x), BesselY(float64(n), x)
+			chk.Float64(tst, io.Sf("J%d(%g)", n, x), 1e-14*(1+math.Abs(math.Jn(n, x))), j, math.Jn(n, x))
+			chk.Float64(tst, io.Sf("Y%d(%g)", n, x), 1e-14*(1+math.Abs(math.Yn(n, x))), y, math.Yn(n, x))
+		}
+	}
+
+	// half-integer orders
+	io.Pl()
+	for _, x := range xx {
+		s := math.Sqrt(2.0 / (math.Pi * x))
+		sn, cs := math.Sincos(x)
+		chk.Float64(tst, io.Sf("J½(%g)", x), 1e-13*s, BesselJ(0.5, x), s*sn)
+		chk.Float64(tst, io.Sf("Y½(%g)", x), 1e-13*s, BesselY(0.5, x), -s*cs)
+		chk.Float64(tst, io.Sf("J₋½(%g)", x), 1e-13*s, BesselJ(-0.5, x), s*cs)
+		if x > 0.2 {
+			chk.Float64(tst, io.Sf("J2.5(%g)", x), 1e-13*s, BesselJ(2.5, x), s*((3/(x*x)-1)*sn-3*cs/x))
+		}
+	}
+
+	// special values
+	chk.Float64(tst, "J0(0)", 1e-15, BesselJ(0, 0), 1)
+	chk.Float64(tst, "J1.5(0)", 1e-15, BesselJ(1.5, 0), 0)
+	if !math.IsInf(BesselY(0.3, 0), -1) || !math.IsNaN(BesselJ(0.3, -1)) {
+		tst.Errorf("special cases failed\n")
+	}
+}
+
+func TestBesselNu02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselNu02. real orders: Wronskians and recurrences")
+
+	for _, ν := range []float64{0.3, 1.7, 4.25, 10.2} {
+		for _, x := range []float64{0.05, 0.7, 1.99, 2.01, 6, 25} {
+			j, y, jp, yp := BesselJY(ν, x)
+			i, k, ip, kp := ModBesselIK(ν, x)
+			chk.Float64(tst, io.Sf("W[J,Y](ν=%g,x=%g)", ν, x), 1e-13, (j*yp-jp*y)*math.Pi*x/2, 1)
+			chk.Float64(tst, io.Sf("W[I,K](ν=%g,x=%g)", ν, x), 1e-13, (i*kp-ip*k)*x, -1)
+
+			// Jν₋₁ + Jν₊₁ = (2ν/x) Jν  and  Iν₋₁ - Iν₊₁ = (2ν/x) Iν
+			jm, jpl := BesselJ(ν-1, x), BesselJ(ν+1, x)
+			chk.Float64(tst, io.Sf("rec J(ν=%g,x=%g)", ν, x), 1e-13*(math.Abs(jm)+math.Abs(jpl)), jm+jpl, 2*ν/x*j)
+			im, ipl := ModBesselI(math.Abs(ν-1), x), ModBesselI(ν+1, x)
+			if ν < 1 { // I₋μ = Iμ + (2/π) sin(μπ) Kμ
+				im += 2 / math.Pi * math.Sin((1-ν)*math.Pi) * ModBesselK(1-ν, x)
+			}
+			chk.Float64(tst, io.Sf("rec I(ν=%g,x=%g)", ν, x), 1e-13*(math.Abs(im)+math.Abs(ipl)), im-ipl, 2*ν/x*i)
+		}
+	}
+}
+
+func TestBesselNu03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BesselNu03. modified Bessel functions and spherical Bessel functions")
+
+	// integer orders versus ModBesselIn and ModBesselKn
+	for _, n := range []int{0, 1, 2, 5} {
+		for _, x := range []float64{0.5, 2, 20} {
+			chk.Float64(tst, io.Sf("I%d(%g)", n, x), 1e-14*ModBesselIn(n, x), ModBesselI(float64(n), x), ModBesselIn(n, x))
+			chk.Float64(tst, io.Sf("K%d(%g)", n, x), 1e-14*ModBesselKn(n, x), ModBesselK(float64(n), x), ModBesselKn(n, x))
+		}
+	}
+
+	// half-integer orders
+	io.Pl()
+	for _, x := range []float64{0.01, 0.3, 1, 3, 10, 50} {
+		i, k := ModBesselI(0.5, x), ModBesselK(0.5, x)
+		iref := math.Sqrt(2/(math.Pi*x)) * math.Sinh(x)
+		kref := math.Sqrt(math.Pi/(2*x)) * math.Exp(-x)
+		chk.Float64(tst, io.Sf("I½(%g)", x), 1e-14*iref, i, iref)
+		chk.Float64(tst, io.Sf("K½(%g)", x), 1e-14*kref, k, kref)
+	}
+
+	// spherical Bessel functions
+	io.Pl()
+	for _, x := range []float64{0.1, 1, 2.5, 7, 20} {
+		sn, cs := math.Sincos(x)
+		chk.Float64(tst, io.Sf("j0(%g)", x), 1e-15, SphBesselJ(0, x), sn/x)
+		if x >= 1 { // the closed forms suffer from cancellation for small x
+			chk.Float64(tst, io.Sf("j1(%g)", x), 1e-15, SphBesselJ(1, x), sn/(x*x)-cs/x)
+			chk.Float64(tst, io.Sf("j2(%g)", x), 1e-14, SphBesselJ(2, x), (3/(x*x)-1)*sn/x-3*cs/(x*x))
+		}
+		chk.Float64(tst, io.Sf("y0(%g)", x), 1e-14, SphBesselY(0, x), -cs/x)
+		chk.Float64(tst, io.Sf("y1(%g)", x), 1e-13, SphBesselY(1, x), -cs/(x*x)-sn/x)
+		chk.Float64(tst, io.Sf("j1(-%g)", x), 1e-15, SphBesselJ(1, -x), -SphBesselJ(1, x))
+	}
+	chk.Float64(tst, "j0(0)", 1e-15, SphBesselJ(0, 0), 1)
+	chk.Float64(tst, "j3(0)", 1e-15, SphBesselJ(3, 0), 0)
+}
@@ -0,0 +1,161 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/utl"
+)
+
+// checkChebfun checks the Chebfun against f at many points
+func checkChebfun(tst *testing.T, msg string, o *Chebfun, f Ss, tol float64) {
+	emax := 0.0
+	for _, x := range utl.LinSpace(o.Ends[0], o.Ends[len(o.Ends)-1], 1001) {
+		emax = math.Max(emax, math.Abs(o.F(x)-f(x)))
+	}
+	io.Pforan("%s: npieces = %d  ncoefs[0] = %d  max error = %.3e\n", msg, o.NumPieces(), len(o.Coefs[0]), emax)
+	if emax > tol {
+		tst.Errorf("%s: error %g is greater than %g\n", msg, emax, tol)
+	}
+}
+
+func TestChebfun01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Chebfun01. construction and arithmetic")
+
+	// simple functions
+	one := NewChebfun(func(x float64) float64 { return 1 }, []float64{0, 1}, false)
+	chk.Int(tst, "len(one)", len(one.Coefs[0]), 1)
+	cubic := NewChebfun(func(x float64) float64 { return x*x*x - x }, []float64{-1, 1}, false)
+	chk.Int(tst, "len(cubic)", len(cubic.Coefs[0]), 4)
+	chk.Array(tst, "cubic", 1e-15, cubic.Coefs[0], []float64{0, -0.25, 0, 0.25})
+
+	// smooth functions
+	f := func(x float64) float64 { return math.Exp(x) * math.Sin(5*x) }
+	g := func(x float64) float64 { return 1 / (1 + 25*x*x) }
+	F := NewChebfun(f, []float64{-1, 2}, false)
+	G := NewChebfun(g, []float64{-1, 0.5, 2}, false)
+	checkChebfun(tst, "exp⋅sin", F, f, 5e-14)
+	checkChebfun(tst, "Runge", G, g, 1e-15)
+	if len(F.Coefs[0]) > 60 {
+		tst.Errorf("too many coefficients: %d\n", len(F.Coefs[0]))
+	}
+
+	// arithmetic
+	io.Pl()
+	checkChebfun(tst, "f+g", F.Add(G), func(x float64) float64 { return f(x) + g(x) }, 5e-14)
+	checkChebfun(tst, "f⋅g", F.Mul(G), func(x float64) float64 { return f(x) * g(x) }, 1e-14)
+	checkChebfun(tst, "cos(f)", F.Compose(math.Cos), func(x float64) float64 { return math.Cos(f(x)) }, 5e-14)
+	checkChebfun(tst, "g(g)", G.Compose(G.F), func(x float64) float64 { return g(g(x)) }, 1e-14)
+	chk.Int(tst, "f+g: npieces", F.Add(G).NumPieces(), 2)
+
+	// derivative
+	df := func(x float64) float64 { return math.Exp(x) * (math.Sin(5*x) + 5*math.Cos(5*x)) }
+	checkChebfun(tst, "df/dx", F.Diff(), df, 1e-11)
+}
+
+func TestChebfun02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Chebfun02. integration, roots and extrema")
+
+	// integrals
+	F := NewChebfun(math.Exp, []float64{-1, 1}, false)
+	chk.Float64(tst, "∫exp", 1e-15, F.Integ(), math.E-1/math.E)
+	F = NewChebfun(math.Sin, []float64{0, math.Pi}, false)
+	chk.Float64(tst, "∫sin", 1e-15, F.Integ(), 2)
+	F = NewChebfun(func(x float64) float64 { return 1 / (1 + x*x) }, []float64{-3, 0, 5}, false)
+	chk.Float64(tst, "∫1/(1+x²)", 1e-14, F.Integ(), math.Atan(5)+math.Atan(3))
+
+	// roots
+	io.Pl()
+	F = NewChebfun(func(x float64) float64 { return math.Sin(math.Pi * x) }, []float64{-3.3, 4.7}, false)
+	roots := F.Roots()
+	chk.Array(tst, "roots of sin(πx)", 1e-13, roots, []float64{-3, -2, -1, 0, 1, 2, 3, 4})
+
+	// many roots (subdivision)
+	F = NewChebfun(func(x float64) float64 { return math.Sin(50 * x) }, []float64{0, 10}, false)
+	roots = F.Roots()
+	io.Pforan("sin(50x): ncoefs = %d  nroots = %d\n", len(F.Coefs[0]), len(roots))
+	chk.Int(tst, "number of roots of sin(50x)", len(roots), 160)
+	for k, r := range roots {
+		chk.Float64(tst, io.Sf("root %d", k), 1e-13, r, float64(k)*math.Pi/50)
+	}
+
+	// roots of J0
+	F = NewChebfun(math.J0, []float64{0, 20}, false)
+	chk.Array(tst, "roots of J0", 1e-13, F.Roots(), []float64{2.404825557695773, 5.520078110286311,
+		8.653727912911013, 11.79153443901428, 14.93091770848779, 18.07106396791092})
+
+	// extrema
+	io.Pl()
+	f := func(x float64) float64 { return math.Sin(x) + math.Sin(10*x/3) }
+	F = NewChebfun(f, []float64{2.7, 7.5}, false)
+	xmin, fmin := F.Min()
+	chk.Float64(tst, "xmin", 1e-6, xmin, 5.145735)
+	chk.Float64(tst, "fmin", 1e-6, fmin, -1.899599)
+	chk.Float64(tst, "fmin=f(xmin)", 1e-14, fmin, f(xmin))
+	xmax, fmax := F.Max()
+	io.Pforan("xmax = %v  fmax = %v\n", xmax, fmax)
+	for _, x := range utl.LinSpace(2.7, 7.5, 1001) {
+		if f(x) > fmax+1e-14 || f(x) < fmin-1e-14 {
+			tst.Errorf("extrema are incorrect\n")
+			return
+		}
+	}
+	F = NewChebfun(func(x float64) float64 { return x * (1 - x) }, []float64{0, 1}, false)
+	xmax, fmax = F.Max()
+	chk.Float64(tst, "xmax", 1e-15, xmax, 0.5)
+	chk.Float64(tst, "fmax", 1e-15, fmax, 0.25)
+	xmin, fmin = F.Min()
+	chk.Float64(tst, "fmin", 1e-15, fmin, 0)
+}
+
+func TestChebfun03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Chebfun03. splitting")
+
+	// kink
+	c := 1.0 / 3.0
+	f := func(x float64) float64 { return math.Abs(x - c) }
+	F := NewChebfun(f, []float64{-1, 1}, true)
+	checkChebfun(tst, "|x-c|", F, f, 1e-14)
+	io.Pforan("ends = %v\n", F.Ends)
+	if F.NumPieces() > 4 {
+		tst.Errorf("too many pieces\n")
+	}
+	chk.Float64(tst, "∫|x-c|", 1e-14, F.Integ(), ((1+c)*(1+c)+(1-c)*(1-c))/2)
+	xmin, fmin := F.Min()
+	chk.Float64(tst, "xmin", 1e-13, xmin, c)
+	chk.Float64(tst, "fmin", 1e-12, fmin, 0)
+
+	// jump
+	io.Pl()
+	g := func(x float64) float64 {
+		if x < c {
+			return math.Exp(x)
+		}
+		return -math.Cos(x)
+	}
+	G := NewChebfun(g, []float64{-1, 2}, true)
+	io.Pforan("ends = %v\n", G.Ends)
+	for _, x := range utl.LinSpace(-1, 2, 1001) {
+		if math.Abs(x-c) > 1e-12 {
+			chk.Float64(tst, io.Sf("g(%g)", x), 1e-14, G.F(x), g(x))
+		}
+	}
+	chk.Float64(tst, "∫g", 1e-14, G.Integ(), math.Exp(c)-math.Exp(-1)-math.Sin(2)+math.Sin(c))
+	chk.Array(tst, "roots of g (jump and π/2)", 1e-10, G.Roots(), []float64{c, math.Pi / 2})
+
+	// arithmetic with splitting
+	H := F.Mul(NewChebfun(math.Cos, []float64{-1, 1}, false))
+	checkChebfun(tst, "|x-c|⋅cos(x)", H, func(x float64) float64 { return f(x) * math.Cos(x) }, 1e-14)
+}
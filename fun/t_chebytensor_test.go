@@ -0,0 +1,73 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestChebyTensor01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ChebyTensor01. coefficients and exactness")
+
+	// f = T2(ξ0) ⋅ T1(ξ1) + 3 in [0,2]×[-1,1]
+	f := func(x la.Vector) float64 {
+		ξ := x[0] - 1
+		return (2*ξ*ξ-1)*x[1] + 3
+	}
+	for _, gauss := range []bool{false, true} {
+		o := NewChebyTensor([]float64{0, -1}, []float64{2, 1}, []int{4, 3}, gauss)
+		o.CalcCoef(f)
+		cor := make([]float64, o.Size())
+		cor[0] = 3
+		cor[2+5*1] = 1
+		chk.Array(tst, io.Sf("gauss=%v: coef", gauss), 1e-14, o.Coef, cor)
+		g := la.NewVector(2)
+		x := la.Vector{0.3, 0.6}
+		chk.Float64(tst, "I(x)", 1e-14, o.Grad(g, x), f(x))
+		ξ := x[0] - 1
+		chk.Array(tst, "dIdx(x)", 1e-14, g, []float64{4 * ξ * x[1], 2*ξ*ξ - 1})
+		err, _ := o.ErrorEstimate()
+		chk.Float64(tst, "error estimate", 1e-14, err, 0)
+	}
+}
+
+func TestChebyTensor02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ChebyTensor02. convergence and error estimate")
+
+	f := func(x la.Vector) float64 { return 1.0 / (1.0 + x[0]*x[0] + 4*x[1]*x[1]) }
+	for _, N := range []int{8, 16, 32} {
+		o := NewChebyTensor([]float64{-1, -1}, []float64{1, 1}, []int{N, N}, false)
+		o.CalcCoef(f)
+		est, dims := o.ErrorEstimate()
+		maxerr, _ := o.EstimateMaxErr(f, 21)
+		io.Pf("N = %2d  maxerr = %.3e  estimate = %.3e  dims = %.3e\n", N, maxerr, est, dims)
+		if est < maxerr/10 || est > maxerr*1e3 {
+			tst.Errorf("estimate is not reasonable\n")
+		}
+		if dims[1] < dims[0] {
+			tst.Errorf("error along y must be larger\n")
+		}
+	}
+
+	// compare with tensor Lagrange on the same points
+	o := NewChebyTensor([]float64{-1, -1}, []float64{1, 1}, []int{10, 7}, false)
+	l := NewTensorInterp([]float64{-1, -1}, []float64{1, 1}, []int{10, 7}, []string{"cgl", "cgl"})
+	o.CalcCoef(f)
+	l.CalcU(f)
+	gc, gl := la.NewVector(2), la.NewVector(2)
+	for _, x := range []la.Vector{{0.1, 0.2}, {-0.7, 0.9}, {1, -1}} {
+		chk.Float64(tst, "Ic = Il", 1e-14, o.Grad(gc, x), l.Grad(gl, x))
+		chk.Array(tst, "dIc = dIl", 1e-12, gc, gl)
+		io.Pf("x = %v  I = %v  f = %v\n", x, o.I(x), f(x))
+	}
+}
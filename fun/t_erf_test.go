@@ -0,0 +1,34 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestErf01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Erf01. scaled complementary error function")
+
+	// small arguments
+	for _, x := range []float64{-3, -1, -0.2, 0, 0.2, 1} {
+		ref := math.Exp(x*x) * math.Erfc(x)
+		chk.Float64(tst, io.Sf("erfcx(%g)", x), 1e-15*ref, Erfcx(x), ref)
+	}
+
+	// reference values computed by the continued fraction with 100 digits
+	xx := []float64{2, 5, 10, 26, 30, 100, 1e4}
+	ref := []float64{2.55395676310505748e-01, 1.10704637733068628e-01, 5.61409927438225875e-02,
+		2.16835848505629071e-02, 1.87958888614167506e-02, 5.64161378298943302e-03, 5.64189580726808426e-05}
+	for i, x := range xx {
+		chk.Float64(tst, io.Sf("erfcx(%g)", x), 1e-15*ref[i], Erfcx(x), ref[i])
+	}
+	chk.Float64(tst, "erfcx(∞)", 1e-15, Erfcx(math.Inf(1)), 0)
+}
@@ -0,0 +1,50 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestExpInt01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ExpInt01. exponential integrals")
+
+	// reference values computed by the power series with 100 digits
+	xx := []float64{0.01, 0.5, 1, 2, 5, 10, 30}
+	e1 := []float64{4.03792957653811424e+00, 5.59773594776160843e-01, 2.19383934395520286e-01,
+		4.89005107080611179e-02, 1.14829559127532571e-03, 4.15696892968532464e-06, 3.02155201068881243e-15}
+	for i, x := range xx {
+		chk.Float64(tst, io.Sf("E1(%g)", x), 1e-14*e1[i], ExpIntE1(x), e1[i])
+		chk.Float64(tst, io.Sf("Ei(-%g)", x), 1e-14*e1[i], ExpIntEi(-x), -e1[i])
+
+		// recurrence: n Eₙ₊₁(x) = exp(-x) - x Eₙ(x)
+		for n := 1; n < 6; n++ {
+			en, en1 := ExpIntE(n, x), ExpIntE(n+1, x)
+			chk.Float64(tst, io.Sf("E%d(%g)", n+1, x), 1e-14*float64(n)*en1, float64(n)*en1, math.Exp(-x)-x*en)
+		}
+	}
+	chk.Float64(tst, "E0(2)", 1e-15, ExpIntE(0, 2), math.Exp(-2)/2)
+	chk.Float64(tst, "E3(0)", 1e-15, ExpIntE(3, 0), 0.5)
+
+	// Ei
+	io.Pl()
+	xx = []float64{0.01, 0.3725, 0.5, 1, 2, 10, 40, 100}
+	ei := []float64{-4.01792946542666929e+00, -2.88741831887373134e-05, 4.54219904863173596e-01,
+		1.89511781635593679e+00, 4.95423435600188977e+00, 2.49222897624187772e+03, 6.03971826361124200e+15,
+		2.71555274485387984e+41}
+	for i, x := range xx {
+		tol := 1e-15 * math.Abs(ei[i])
+		if x == 0.3725 { // close to the root of Ei
+			tol = 1e-15
+		}
+		chk.Float64(tst, io.Sf("Ei(%g)", x), tol, ExpIntEi(x), ei[i])
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestGammaInc01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GammaInc01. incomplete gamma functions")
+
+	for _, x := range []float64{0, 0.1, 0.5, 1, 2.5, 7, 20, 60} {
+
+		// P(1,x) = 1 - exp(-x)
+		chk.Float64(tst, io.Sf("P(1,%g)", x), 1e-15, GammaP(1, x), -math.Expm1(-x))
+
+		// P(½,x) = erf(√x)
+		chk.Float64(tst, io.Sf("P(½,%g)", x), 1e-15, GammaP(0.5, x), math.Erf(math.Sqrt(x)))
+		chk.Float64(tst, io.Sf("Q(½,%g)", x), 1e-14*math.Erfc(math.Sqrt(x)), GammaQ(0.5, x), math.Erfc(math.Sqrt(x)))
+
+		// Q(n,x) = exp(-x) Σ_{k<n} xᵏ/k!
+		for _, n := range []int{2, 5, 12} {
+			sum, term := 0.0, 1.0
+			for k := 0; k < n; k++ {
+				sum += term
+				term *= x / float64(k+1)
+			}
+			q := math.Exp(-x) * sum
+			chk.Float64(tst, io.Sf("Q(%d,%g)", n, x), 1e-14*q, GammaQ(float64(n), x), q)
+			chk.Float64(tst, io.Sf("P+Q(%d,%g)", n, x), 1e-15, GammaP(float64(n), x)+GammaQ(float64(n), x), 1)
+		}
+	}
+}
+
+func TestGammaInc02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GammaInc02. digamma and incomplete beta functions")
+
+	// digamma
+	γ := EulerGamma
+	chk.Float64(tst, "ψ(1)", 1e-15, Digamma(1), -γ)
+	chk.Float64(tst, "ψ(½)", 1e-15, Digamma(0.5), -γ-2*math.Ln2)
+	chk.Float64(tst, "ψ(-½)", 1e-14, Digamma(-0.5), -γ-2*math.Ln2+2)
+	h := 0.0
+	for n := 1; n < 30; n++ {
+		chk.Float64(tst, io.Sf("ψ(%d)", n), 1e-14, Digamma(float64(n)), -γ+h)
+		h += 1 / float64(n)
+	}
+	if !math.IsNaN(Digamma(-2)) {
+		tst.Errorf("ψ(-2) should be NaN\n")
+	}
+
+	// incomplete beta: integer parameters ⇒ binomial sums
+	io.Pl()
+	for _, x := range []float64{0, 0.05, 0.3, 0.5, 0.77, 0.99, 1} {
+		chk.Float64(tst, io.Sf("I(%g; 2.5, 1)", x), 1e-15, BetaInc(2.5, 1, x), math.Pow(x, 2.5))
+		chk.Float64(tst, io.Sf("I(%g; 1, 3.5)", x), 1e-15, BetaInc(1, 3.5, x), 1-math.Pow(1-x, 3.5))
+		for _, ab := range [][]int{{2, 3}, {5, 4}, {10, 7}} {
+			a, b := ab[0], ab[1]
+			n := a + b - 1
+			ref := 0.0
+			for j := a; j <= n; j++ {
+				ref += Binomial(n, j) * math.Pow(x, float64(j)) * math.Pow(1-x, float64(n-j))
+			}
+			chk.Float64(tst, io.Sf("I(%g; %d, %d)", x, a, b), 1e-14, BetaInc(float64(a), float64(b), x), ref)
+		}
+	}
+	chk.Float64(tst, "I(½; 7.3, 7.3)", 1e-15, BetaInc(7.3, 7.3, 0.5), 0.5)
+}
@@ -0,0 +1,183 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+// quadSum computes Σ w[j] f(x[j])
+func quadSum(x, w []float64, f func(x float64) float64) (res float64) {
+	for j := range x {
+		res += w[j] * f(x[j])
+	}
+	return
+}
+
+func TestGaussQuad01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussQuad01. Gauss-Legendre and Gauss-Chebyshev")
+
+	// Legendre
+	op := NewGeneralOrthoPoly("L", 5, 0, 0)
+	x, w := op.GaussXW(5)
+	io.Pforan("x = %v\n", x)
+	io.Pforan("w = %v\n", w)
+	chk.Array(tst, "x", 1e-15, x, []float64{-0.906179845938664, -0.5384693101056831, 0, 0.5384693101056831, 0.906179845938664})
+	chk.Array(tst, "w", 1e-14, w, []float64{0.2369268850561891, 0.4786286704993665, 0.5688888888888889, 0.4786286704993665, 0.2369268850561891})
+	for _, xx := range x {
+		chk.Float64(tst, "P5(x)", 1e-14, op.P(5, xx), 0)
+	}
+	for k := 0; k < 10; k++ {
+		ana := 0.0
+		if k%2 == 0 {
+			ana = 2.0 / float64(k+1)
+		}
+		num := quadSum(x, w, func(x float64) float64 { return math.Pow(x, float64(k)) })
+		chk.Float64(tst, io.Sf("∫x^%d", k), 1e-15, num, ana)
+	}
+
+	// Chebyshev first and second kinds
+	n := 6
+	x, w = NewGeneralOrthoPoly("T", n, 0, 0).GaussXW(n)
+	for j := 0; j < n; j++ {
+		chk.Float64(tst, "T: x", 1e-15, x[j], -math.Cos(float64(2*j+1)*math.Pi/float64(2*n)))
+		chk.Float64(tst, "T: w", 1e-15, w[j], math.Pi/float64(n))
+	}
+	x, w = NewGeneralOrthoPoly("U", n, 0, 0).GaussXW(n)
+	for j := 0; j < n; j++ {
+		θ := float64(j+1) * math.Pi / float64(n+1)
+		chk.Float64(tst, "U: x", 1e-15, x[j], -math.Cos(θ))
+		chk.Float64(tst, "U: w", 1e-15, w[j], math.Pi/float64(n+1)*math.Pow(math.Sin(θ), 2))
+	}
+}
+
+func TestGaussQuad02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussQuad02. Gauss-Hermite, Gauss-Laguerre and Gauss-Jacobi")
+
+	// Hermite: ∫ x^(2k) exp(-x²) dx = Γ(k+½)
+	n := 10
+	op := NewGeneralOrthoPoly("H", n, 0, 0)
+	x, w := op.GaussXW(n)
+	for _, xx := range x {
+		chk.Float64(tst, "H10(x)", 1e-6, op.P(n, xx)/Factorial22(n), 0)
+	}
+	for k := 0; k < n; k++ {
+		num := quadSum(x, w, func(x float64) float64 { return math.Pow(x, float64(2*k)) })
+		chk.Float64(tst, io.Sf("H: ∫x^%d", 2*k), 1e-13*math.Gamma(float64(k)+0.5), num, math.Gamma(float64(k)+0.5))
+	}
+
+	// Laguerre: ∫ x^k x^α exp(-x) dx = Γ(k+α+1)
+	for _, α := range []float64{0, 0.5} {
+		op = NewGeneralOrthoPoly("La", n, α, 0)
+		x, w = op.GaussXW(n)
+		for _, xx := range x {
+			chk.Float64(tst, "La10(x)", 1e-8, op.P(n, xx), 0)
+		}
+		for k := 0; k < 2*n; k++ {
+			ana := math.Gamma(float64(k) + α + 1)
+			num := quadSum(x, w, func(x float64) float64 { return math.Pow(x, float64(k)) })
+			chk.Float64(tst, io.Sf("La(α=%g): ∫x^%d", α, k), 1e-13*ana, num, ana)
+		}
+	}
+
+	// Jacobi: ∫ (1-x)^α (1+x)^β dx = 2^(α+β+1) B(α+1,β+1) and nodes are the zeros of P(n,x)
+	n = 7
+	α, β := 1.5, -0.5
+	op = NewGeneralOrthoPoly("J", n, α, β)
+	x, w = op.GaussXW(n)
+	for _, xx := range x {
+		chk.Float64(tst, "J7(x)", 1e-13, op.P(n, xx), 0)
+	}
+	chk.Float64(tst, "J: ∫1", 1e-14, quadSum(x, w, func(x float64) float64 { return 1 }), math.Pow(2, α+β+1)*Beta(α+1, β+1))
+	chk.Float64(tst, "J: ∫(1+x)", 1e-14, quadSum(x, w, func(x float64) float64 { return 1 + x }), math.Pow(2, α+β+2)*Beta(α+1, β+2))
+}
+
+func TestGaussQuad03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussQuad03. Gauss-Lobatto and Gauss-Radau")
+
+	op := NewGeneralOrthoPoly("L", 5, 0, 0)
+
+	// Lobatto
+	x, w := op.GaussLobattoXW(5, -1, 1)
+	io.Pforan("x = %v\n", x)
+	io.Pforan("w = %v\n", w)
+	s := math.Sqrt(3.0 / 7.0)
+	chk.Array(tst, "x", 1e-15, x, []float64{-1, -s, 0, s, 1})
+	chk.Array(tst, "w", 1e-14, w, []float64{0.1, 49.0 / 90.0, 32.0 / 45.0, 49.0 / 90.0, 0.1})
+
+	// Radau
+	x, w = op.GaussRadauXW(3, -1)
+	io.Pforan("x = %v\n", x)
+	io.Pforan("w = %v\n", w)
+	s = math.Sqrt(6.0)
+	chk.Array(tst, "x", 1e-15, x, []float64{-1, (1 - s) / 5, (1 + s) / 5})
+	chk.Array(tst, "w", 1e-14, w, []float64{2.0 / 9.0, (16 + s) / 18, (16 - s) / 18})
+
+	// Radau on the right with Laguerre-like exactness test
+	x, w = op.GaussRadauXW(4, 1)
+	chk.Float64(tst, "x[3]", 1e-15, x[3], 1)
+	for k := 0; k <= 6; k++ {
+		ana := 0.0
+		if k%2 == 0 {
+			ana = 2.0 / float64(k+1)
+		}
+		num := quadSum(x, w, func(x float64) float64 { return math.Pow(x, float64(k)) })
+		chk.Float64(tst, io.Sf("Radau: ∫x^%d", k), 1e-15, num, ana)
+	}
+}
+
+func TestGaussQuad04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussQuad04. Gauss-Kronrod")
+
+	// 7-15 Gauss-Kronrod rule used by QUADPACK (qk15)
+	xgk := []float64{0.991455371120812639206854697526329, 0.949107912342758524526189684047851, 0.864864423359769072789712788640926, 0.741531185599394439863864773280788, 0.586087235467691130294144845693013, 0.405845151377397166906606412076961, 0.207784955007898467600689403773245, 0}
+	wgk := []float64{0.022935322010529224963732008058970, 0.063092092629978553290700663189204, 0.104790010322250183839876322541518, 0.140653259715525918745189590510238, 0.169004726639267902826583426598550, 0.190350578064785409913256402421014, 0.204432940075298892414161999234649, 0.209482141084727828012999174891714}
+	wg := []float64{0.129484966168869693270611432679082, 0.279705391489276667901467771423780, 0.381830050505118944950369775488975, 0.417959183673469387755102040816327}
+
+	op := NewGeneralOrthoPoly("L", 7, 0, 0)
+	x, wK, wG := op.GaussKronrodXW(7)
+	io.Pforan("x  = %v\n", x)
+	io.Pforan("wK = %v\n", wK)
+	io.Pforan("wG = %v\n", wG)
+	for j := 0; j < 8; j++ {
+		chk.Float64(tst, "x", 1e-15, x[j], -xgk[j])
+		chk.Float64(tst, "x", 1e-15, x[14-j], xgk[j])
+		chk.Float64(tst, "wK", 1e-15, wK[j], wgk[j])
+		chk.Float64(tst, "wK", 1e-15, wK[14-j], wgk[j])
+		g := 0.0
+		if j%2 == 1 {
+			g = wg[j/2]
+		}
+		chk.Float64(tst, "wG", 1e-15, wG[j], g)
+		chk.Float64(tst, "wG", 1e-15, wG[14-j], g)
+	}
+
+	// Kronrod rules for other weight functions: exactness up to degree 3n+1
+	for _, kind := range []string{"J", "T", "U"} {
+		n := 4
+		op = NewGeneralOrthoPoly(kind, n, 0.5, 0.5)
+		x, wK, wG = op.GaussKronrodXW(n)
+		xg, wg := op.GaussXW(3*n + 2)
+		for k := 0; k <= 3*n+1; k++ {
+			f := func(x float64) float64 { return math.Pow(x, float64(k)) }
+			chk.Float64(tst, io.Sf("%s: ∫x^%d", kind, k), 1e-14, quadSum(x, wK, f), quadSum(xg, wg, f))
+			if k < 2*n {
+				chk.Float64(tst, io.Sf("%s: ∫x^%d (Gauss)", kind, k), 1e-14, quadSum(x, wG, f), quadSum(xg, wg, f))
+			}
+		}
+	}
+}
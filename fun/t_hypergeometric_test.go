@@ -0,0 +1,140 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestHyp1f101(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Hyp1f101. confluent hypergeometric function")
+
+	for _, x := range []float64{-30, -5, -1, -0.1, 0.1, 1, 5, 30} {
+
+		// M(1; 2; x) = (exp(x) - 1)/x
+		ref := math.Expm1(x) / x
+		chk.Float64(tst, io.Sf("M(1;2;%g)", x), 1e-14*ref, Hyp1f1(1, 2, x), ref)
+
+		// M(a; a; x) = exp(x)
+		chk.Float64(tst, io.Sf("M(a;a;%g)", x), 1e-15*math.Exp(x), Hyp1f1(2.3, 2.3, x), math.Exp(x))
+
+		// M(½; 3/2; -x²) = √π erf(x) / (2x)
+		if x > 0 && x < 20 { // M overflows for large |x|
+			ref = math.Sqrt(math.Pi) * math.Erf(x) / (2 * x)
+			chk.Float64(tst, io.Sf("M(½;3/2;-%g²)", x), 1e-14*ref, Hyp1f1(0.5, 1.5, -x*x), ref)
+		}
+
+		// Laguerre polynomials: L₃(x) = M(-3; 1; x)
+		ref = (-x*x*x + 9*x*x - 18*x + 6) / 6
+		chk.Float64(tst, io.Sf("L3(%g)", x), 1e-13*(1+math.Abs(ref)), Hyp1f1(-3, 1, x), ref)
+	}
+}
+
+func TestHyp2f101(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Hyp2f101. Gauss hypergeometric function")
+
+	for _, x := range []float64{-20, -3, -0.9, -0.2, 0, 0.3, 0.7, 0.95} {
+
+		// ₂F₁(1, 1; 2; x) = -ln(1-x)/x
+		ref := 1.0
+		if x != 0 {
+			ref = -math.Log1p(-x) / x
+		}
+		chk.Float64(tst, io.Sf("F(1,1;2;%g)", x), 1e-14*ref, Hyp2f1(1, 1, 2, x), ref)
+
+		// ₂F₁(a, b; b; x) = (1-x)^(-a)
+		ref = math.Pow(1-x, -1.7)
+		chk.Float64(tst, io.Sf("F(a,b;b;%g)", x), 1e-14*ref, Hyp2f1(1.7, 0.4, 0.4, x), ref)
+
+		// ₂F₁(½, 1; 3/2; -z²) = atan(z)/z  with z = √|x|
+		if x < 0 {
+			z := math.Sqrt(-x)
+			ref = math.Atan(z) / z
+			chk.Float64(tst, io.Sf("F(½,1;3/2;%g)", x), 1e-14*ref, Hyp2f1(0.5, 1, 1.5, x), ref)
+		}
+
+		// ₂F₁(½, ½; 3/2; z²) = asin(z)/z  with z = √x
+		if x > 0 {
+			z := math.Sqrt(x)
+			ref = math.Asin(z) / z
+			chk.Float64(tst, io.Sf("F(½,½;3/2;%g)", x), 1e-14*ref, Hyp2f1(0.5, 0.5, 1.5, x), ref)
+		}
+
+		// polynomial: ₂F₁(-2, b; c; x) = 1 - 2bx/c + b(b+1)x²/(c(c+1))
+		b, c := 1.3, 2.1
+		ref = 1 - 2*b*x/c + b*(b+1)*x*x/(c*(c+1))
+		chk.Float64(tst, io.Sf("F(-2,b;c;%g)", x), 1e-13*(1+math.Abs(ref)), Hyp2f1(-2, b, c, x), ref)
+	}
+
+	// Gauss' theorem
+	a, b, c := 0.3, 0.7, 2.5
+	ref := math.Gamma(c) * math.Gamma(c-a-b) / (math.Gamma(c-a) * math.Gamma(c-b))
+	chk.Float64(tst, "F(a,b;c;1)", 1e-14, Hyp2f1(a, b, c, 1), ref)
+	if !math.IsNaN(Hyp2f1(a, b, c, 1.5)) {
+		tst.Errorf("F(x > 1) should be NaN\n")
+	}
+}
+
+func TestHyp2f102(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Hyp2f102. Gauss hypergeometric function: connection formulae")
+
+	for _, x := range []float64{-1e5, -50, 0.5, 0.6, 0.9, 0.99999} {
+
+		// ₂F₁(½, ½; 3/2; z²) = asin(z)/z  with z = √x  (c-a-b = ½)
+		// ₂F₁(½, 1; 3/2; -z²) = atan(z)/z  with z = √-x  (c-a-b = 0)
+		if x > 0 {
+			z := math.Sqrt(x)
+			ref := math.Asin(z) / z
+			chk.Float64(tst, io.Sf("F(½,½;3/2;%g)", x), 1e-14*ref, Hyp2f1(0.5, 0.5, 1.5, x), ref)
+		} else {
+			z := math.Sqrt(-x)
+			ref := math.Atan(z) / z
+			chk.Float64(tst, io.Sf("F(½,1;3/2;%g)", x), 1e-14*ref, Hyp2f1(0.5, 1, 1.5, x), ref)
+		}
+
+		// ₂F₁(1, 1; 2; x) = -ln(1-x)/x  (c-a-b = 0)
+		ref := -math.Log1p(-x) / x
+		chk.Float64(tst, io.Sf("F(1,1;2;%g)", x), 1e-14*ref, Hyp2f1(1, 1, 2, x), ref)
+
+		// ₂F₁(1, 1; 3; x) = 2 ((1-x) ln(1-x) + x) / x²  (c-a-b = 1)
+		ref = 2 * ((1-x)*math.Log1p(-x) + x) / (x * x)
+		chk.Float64(tst, io.Sf("F(1,1;3;%g)", x), 1e-13*ref, Hyp2f1(1, 1, 3, x), ref)
+
+		// ₂F₁(2, 2; 3; x) = 2/(1-x) + 2 (ln(1-x) + x) / x²  (c-a-b = -1)
+		if x > -100 { // the formula suffers from cancellation for large |x|
+			ref = 2/(1-x) + 2*(math.Log1p(-x)+x)/(x*x)
+			chk.Float64(tst, io.Sf("F(2,2;3;%g)", x), 1e-13*ref, Hyp2f1(2, 2, 3, x), ref)
+		}
+	}
+
+	// reference values computed by summing the series with 40 digits
+	chk.Float64(tst, "F(0.3,0.7;2.5;0.99999)", 1e-15, Hyp2f1(0.3, 0.7, 2.5, 0.99999), 1.148013274685047286594360657087703819834)
+	chk.Float64(tst, "F(0.3,0.7;2.5;-1e5)", 1e-16, Hyp2f1(0.3, 0.7, 2.5, -1e5), 0.06463635790626841944053694299024491187407)
+
+	// the series and the connection formula agree at x = 0.5
+	for _, p := range [][]float64{{0.3, 0.7, 2.5}, {0.3, 0.7, 3}, {-0.4, 1.3, 0.2}, {1.5, 2.5, 1}} {
+		a, b, c := p[0], p[1], p[2]
+		chk.Float64(tst, io.Sf("F(%g,%g;%g;0.5)", a, b, c), 1e-14, hyp2f1Connect(a, b, c, 0.5, 0.5), hyp2f1Series(a, b, c, 0.5))
+	}
+
+	// Gauss' theorem (limit x → 1) and large negative x: F(a,b;c;x) ~ Γ(c)Γ(b-a)/(Γ(b)Γ(c-a)) (-x)^(-a)
+	a, b, c := 0.3, 0.7, 2.5
+	ref := math.Gamma(c) * math.Gamma(c-a-b) / (math.Gamma(c-a) * math.Gamma(c-b))
+	chk.Float64(tst, "F(a,b;c;1-1e-12)", 1e-10, Hyp2f1(a, b, c, 1-1e-12), ref)
+	x := -1e12
+	ref = math.Gamma(c)*math.Gamma(b-a)/(math.Gamma(b)*math.Gamma(c-a))*math.Pow(-x, -a) +
+		math.Gamma(c)*math.Gamma(a-b)/(math.Gamma(a)*math.Gamma(c-b))*math.Pow(-x, -b)
+	chk.Float64(tst, "F(a,b;c;-1e12)", 1e-12*ref, Hyp2f1(a, b, c, x), ref)
+}
@@ -87,3 +87,183 @@ func TestInterp02(tst *testing.T) {
 		plt.Save("/tmp/gosl/fun", "interp02")
 	}
 }
+
+func TestInterp03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Interp03. cubic splines")
+
+	// cubic function
+	f := func(x float64) float64 { return 1 - 2*x + 0.5*x*x - 0.1*x*x*x }
+	g := func(x float64) float64 { return -2 + x - 0.3*x*x }
+	h := func(x float64) float64 { return 1 - 0.6*x }
+	F := func(x float64) float64 { return x - x*x + x*x*x/6.0 - 0.025*x*x*x*x }
+	xx := []float64{0, 0.5, 1.5, 2, 3.2, 4, 5}
+	yy := utl.GetMapped(xx, f)
+
+	// not-a-knot and clamped splines reproduce cubics exactly
+	nak := NewDataInterp("spline-nak", 0, xx, yy)
+	cla := NewDataInterp("spline-clamped", 0, xx, yy)
+	cla.DyLeft, cla.DyRight = g(xx[0]), g(xx[len(xx)-1])
+	cla.Reset(xx, yy)
+	for _, o := range []*DataInterp{nak, cla} {
+		for _, x := range utl.LinSpace(-0.5, 5.5, 13) {
+			chk.Float64(tst, o.itype+": P(x)", 1e-13, o.P(x), f(x))
+			chk.Float64(tst, o.itype+": G(x)", 1e-13, o.G(x), g(x))
+			chk.Float64(tst, o.itype+": H(x)", 1e-12, o.H(x), h(x))
+		}
+		chk.Float64(tst, o.itype+": Integ", 1e-13, o.Integ(0.2, 4.5), F(4.5)-F(0.2))
+		chk.Float64(tst, o.itype+": Integ", 1e-13, o.Integ(4.5, -0.3), F(-0.3)-F(4.5))
+	}
+
+	// natural spline: interpolation, zero second derivatives at the ends and continuity
+	nat := NewDataInterp("spline", 0, xx, yy)
+	for i, x := range xx {
+		chk.Float64(tst, "natural: P(xi)", 1e-15, nat.P(x), yy[i])
+	}
+	δ := 1e-5
+	for _, x := range []float64{xx[0], xx[len(xx)-1]} {
+		d2 := (nat.P(x+δ) - 2*nat.P(x) + nat.P(x-δ)) / (δ * δ)
+		chk.Float64(tst, "natural: d²P/dx² at end", 1e-4, d2, 0)
+		chk.Float64(tst, "natural: H at end", 1e-13, nat.H(x), 0)
+	}
+	for _, x := range xx[1 : len(xx)-1] {
+		chk.Float64(tst, "natural: G continuity", 1e-8, nat.G(x-1e-10), nat.G(x+1e-10))
+		chk.Float64(tst, "natural: H continuity", 1e-8, nat.H(x-1e-10), nat.H(x+1e-10))
+	}
+	for _, x := range []float64{0.3, 1.7, 4.4} {
+		chk.Float64(tst, "natural: H(x)", 1e-6, nat.H(x), (nat.G(x+δ)-nat.G(x-δ))/(2*δ))
+	}
+
+	// natural spline reproduces straight lines
+	lin := NewDataInterp("spline", 0, xx, utl.GetMapped(xx, func(x float64) float64 { return 3 - 2*x }))
+	for _, x := range utl.LinSpace(0, 5, 11) {
+		chk.Float64(tst, "natural: line", 1e-14, lin.P(x), 3-2*x)
+	}
+
+	if chk.Verbose {
+		X := utl.LinSpace(-0.5, 5.5, 101)
+		plt.Reset(true, &plt.A{WidthPt: 400, Dpi: 150})
+		plt.Plot(xx, yy, &plt.A{C: "k", Ls: "none", M: "o", L: "data", NoClip: true})
+		plt.Plot(X, utl.GetMapped(X, f), &plt.A{C: "k", Ls: "-", L: "f", NoClip: true})
+		plt.Plot(X, utl.GetMapped(X, nat.P), &plt.A{C: "r", Ls: "--", L: "natural", NoClip: true})
+		plt.Gll("x", "y", nil)
+		plt.HideTRborders()
+		plt.Save("/tmp/gosl/fun", "interp03")
+	}
+}
+
+func TestInterp04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Interp04. pchip and akima")
+
+	// monotone data with a flat part
+	xx := []float64{0, 1, 2, 3, 4, 5, 6}
+	yy := []float64{0, 0.1, 0.1, 0.1, 0.8, 1.0, 1.05}
+
+	pchip := NewDataInterp("pchip", 0, xx, yy)
+	akima := NewDataInterp("akima", 0, xx, yy)
+	spline := NewDataInterp("spline", 0, xx, yy)
+	for _, o := range []*DataInterp{pchip, akima, spline} {
+		for i, x := range xx {
+			chk.Float64(tst, o.itype+": P(xi)", 1e-15, o.P(x), yy[i])
+		}
+	}
+
+	// pchip is monotone and both pchip and akima keep the flat part flat
+	X := utl.LinSpace(0, 6, 241)
+	for i := 1; i < len(X); i++ {
+		if pchip.P(X[i]) < pchip.P(X[i-1])-1e-15 {
+			tst.Errorf("pchip must be monotone\n")
+			return
+		}
+	}
+	for _, x := range utl.LinSpace(1, 3, 9) {
+		chk.Float64(tst, "pchip: flat", 1e-15, pchip.P(x), 0.1)
+		chk.Float64(tst, "akima: flat", 1e-15, akima.P(x), 0.1)
+	}
+	overshoot := false
+	for _, x := range X {
+		if x > 1 && x < 3 && spline.P(x) < 0.1-1e-3 {
+			overshoot = true
+		}
+	}
+	if !overshoot {
+		tst.Errorf("spline should undershoot the flat part\n")
+	}
+
+	// derivatives and integrals
+	for _, o := range []*DataInterp{pchip, akima} {
+		h := 1e-6
+		for _, x := range []float64{0.3, 2.5, 3.7, 4.1, 5.9} {
+			chk.Float64(tst, o.itype+": G(x)", 1e-8, o.G(x), (o.P(x+h)-o.P(x-h))/(2*h))
+		}
+		xs := utl.LinSpace(0.5, 5.5, 2001)
+		ys := utl.GetMapped(xs, o.P)
+		trapz := 0.0
+		for i := 1; i < len(xs); i++ {
+			trapz += (xs[i] - xs[i-1]) * (ys[i] + ys[i-1]) / 2
+		}
+		chk.Float64(tst, o.itype+": Integ", 1e-6, o.Integ(0.5, 5.5), trapz)
+	}
+
+	if chk.Verbose {
+		X := utl.LinSpace(-0.5, 6.5, 141)
+		plt.Reset(true, &plt.A{WidthPt: 400, Dpi: 150})
+		plt.Plot(xx, yy, &plt.A{C: "k", Ls: "none", M: "o", L: "data", NoClip: true})
+		plt.Plot(X, utl.GetMapped(X, pchip.P), &plt.A{C: "r", Ls: "-", L: "pchip", NoClip: true})
+		plt.Plot(X, utl.GetMapped(X, akima.P), &plt.A{C: "b", Ls: "--", L: "akima", NoClip: true})
+		plt.Plot(X, utl.GetMapped(X, spline.P), &plt.A{C: "g", Ls: ":", L: "spline", NoClip: true})
+		plt.Gll("x", "y", nil)
+		plt.HideTRborders()
+		plt.Save("/tmp/gosl/fun", "interp04")
+	}
+}
+
+func TestInterp05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Interp05. extrapolation")
+
+	xx := []float64{0, 1, 2, 3}
+	yy := []float64{1, 2, 0, 1}
+
+	o := NewDataInterp("lin", 1, xx, yy)
+	chk.Float64(tst, "lin: default", 1e-15, o.P(-1), 0)
+	chk.Float64(tst, "lin: Integ", 1e-15, o.Integ(0, 3), 1.5+1+0.5)
+	o.Extrap = "const"
+	chk.Float64(tst, "lin: const", 1e-15, o.P(-1), 1)
+	chk.Float64(tst, "lin: const", 1e-15, o.P(5), 1)
+	chk.Float64(tst, "lin: const G", 1e-15, o.G(5), 0)
+	chk.Float64(tst, "lin: const Integ", 1e-15, o.Integ(-1, 4), 1+3+1)
+
+	o = NewDataInterp("spline", 0, xx, yy)
+	o.Extrap = "lin"
+	gl, gr := o.G(0), o.G(3)
+	chk.Float64(tst, "spline: lin", 1e-15, o.P(-2), 1-2*gl)
+	chk.Float64(tst, "spline: lin", 1e-15, o.P(4), 1+gr)
+	chk.Float64(tst, "spline: lin G", 1e-15, o.G(4), gr)
+	chk.Float64(tst, "spline: lin Integ", 1e-14, o.Integ(-2, 0), 2-2*gl)
+	chk.Float64(tst, "spline: lin Integ", 1e-14, o.Integ(3, 4), 1+gr/2)
+
+	o.Extrap = "panic"
+	defer chk.RecoverTstPanicIsOK(tst)
+	o.P(3.1)
+}
+
+func TestInterp06(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Interp06. polynomial interpolation with descending data")
+
+	xx := []float64{3, 2, 1, 0}
+	yy := []float64{9, 4, 1, 0}
+
+	o := NewDataInterp("poly", 1, xx, yy)
+	chk.Float64(tst, "p=1: P(1.5)", 1e-15, o.P(1.5), 2.5)
+	chk.Float64(tst, "p=1: P(2.5)", 1e-15, o.P(2.5), 6.5)
+
+	o = NewDataInterp("poly", 2, xx, yy)
+	chk.Float64(tst, "p=2: P(1.5)", 1e-15, o.P(1.5), 2.25)
+}
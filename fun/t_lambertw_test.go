@@ -0,0 +1,49 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestLambertW01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LambertW01. principal and lower branches")
+
+	// special values
+	chk.Float64(tst, "W0(0)", 1e-15, LambertW0(0), 0)
+	chk.Float64(tst, "W0(1) = Ω", 1e-15, LambertW0(1), 0.56714329040978387300)
+	chk.Float64(tst, "W0(e)", 1e-15, LambertW0(math.E), 1)
+	chk.Float64(tst, "W0(-1/e)", 1e-8, LambertW0(-1/math.E), -1)
+	chk.Float64(tst, "W-1(-1/e)", 1e-8, LambertWm1(-1/math.E), -1)
+	chk.Float64(tst, "W0(-ln2/2)", 1e-15, LambertW0(-math.Ln2/2), -math.Ln2)
+	chk.Float64(tst, "W-1(-ln2/2)", 1e-15, LambertWm1(-math.Ln2/2), -2*math.Ln2)
+	if !math.IsNaN(LambertW0(-1)) || !math.IsNaN(LambertWm1(0.5)) {
+		tst.Errorf("W outside domain should be NaN\n")
+	}
+
+	// w exp(w) = x
+	for _, x := range []float64{-0.36787944, -0.3678, -0.3, -0.1, 1e-10, 0.5, 3, 10, 1e3, 1e10, 1e300} {
+		w := LambertW0(x)
+		io.Pf("W0(%g) = %v\n", x, w)
+		chk.Float64(tst, io.Sf("W0(%g)", x), 1e-14*math.Abs(x)+1e-15, w*math.Exp(w), x)
+		if w < -1 {
+			tst.Errorf("W0 must be ≥ -1\n")
+		}
+	}
+	for _, x := range []float64{-0.36787944, -0.3678, -0.3, -0.1, -1e-3, -1e-100} {
+		w := LambertWm1(x)
+		io.Pf("W-1(%g) = %v\n", x, w)
+		chk.Float64(tst, io.Sf("W-1(%g)", x), 1e-14*math.Abs(x), w*math.Exp(w), x)
+		if w > -1 {
+			tst.Errorf("W-1 must be ≤ -1\n")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestPade01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Pade01. exponential function")
+
+	// Taylor coefficients of exp(x)
+	c := la.NewVector(20)
+	c[0] = 1
+	for i := 1; i < len(c); i++ {
+		c[i] = c[i-1] / float64(i)
+	}
+
+	// [2/2]: (1 + x/2 + x²/12) / (1 - x/2 + x²/12)
+	o := NewPade(c, 2, 2)
+	chk.Array(tst, "P", 1e-15, o.P, []float64{1, 0.5, 1.0 / 12})
+	chk.Array(tst, "Q", 1e-15, o.Q, []float64{1, -0.5, 1.0 / 12})
+
+	// poles: 3 ± i√3
+	poles, res := o.Poles()
+	for k, p := range poles {
+		chk.Complex128(tst, io.Sf("res%d", k), 1e-6, res[k], o.Fc(p+1e-7)*1e-7)
+	}
+	sort.Slice(poles, func(i, j int) bool { return imag(poles[i]) < imag(poles[j]) })
+	chk.Complex128(tst, "p0", 1e-14, poles[0], complex(3, -math.Sqrt(3)))
+	chk.Complex128(tst, "p1", 1e-14, poles[1], complex(3, math.Sqrt(3)))
+	zeros := o.Zeros()
+	for _, z := range zeros {
+		chk.Float64(tst, "|P(z)|", 1e-14, real(o.Fc(z)), 0)
+	}
+
+	// accuracy
+	io.Pl()
+	for _, n := range []int{2, 4, 6, 8} {
+		o = NewPade(c, n, n)
+		err := math.Abs(o.F(1) - math.E)
+		io.Pf("[%d/%d] : error @ x=1 = %.3e\n", n, n, err)
+		if n == 8 && err > 1e-15 {
+			tst.Errorf("error is too large\n")
+		}
+	}
+	o = NewPade(c, 6, 6)
+	for _, x := range []float64{-1, -0.5, 0.25, 0.5} {
+		chk.Float64(tst, io.Sf("exp(%g)", x), 1e-12, o.F(x), math.Exp(x))
+	}
+}
+
+func TestPade02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Pade02. log(1+x) and 1/(1-x)")
+
+	// log(1+x) = x - x²/2 + x³/3 - ...   [1/1] = x / (1 + x/2)
+	c := la.NewVector(12)
+	for i := 1; i < len(c); i++ {
+		c[i] = NegOnePowN(i+1) / float64(i)
+	}
+	o := NewPade(c, 1, 1)
+	chk.Array(tst, "P", 1e-15, o.P, []float64{0, 1})
+	chk.Array(tst, "Q", 1e-15, o.Q, []float64{1, 0.5})
+
+	// Padé converges beyond the radius of convergence of the Taylor series
+	o = NewPade(c, 5, 5)
+	chk.Float64(tst, "log(1+2)", 1e-4, o.F(2), math.Log(3))
+
+	// exact for rational functions: 1/(1-x) = 1 + x + x² + ...
+	c = la.NewVector(5)
+	c.Fill(1)
+	o = NewPade(c, 0, 1)
+	poles, res := o.Poles()
+	chk.Int(tst, "number of poles", len(poles), 1)
+	chk.Complex128(tst, "pole", 1e-15, poles[0], 1)
+	chk.Complex128(tst, "residue", 1e-15, res[0], -1)
+	chk.Float64(tst, "R(3)", 1e-15, o.F(3), -0.5)
+}
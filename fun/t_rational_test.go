@@ -0,0 +1,160 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/utl"
+)
+
+func TestFloaterHormann01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FloaterHormann01. Runge function and polynomial reproduction")
+
+	// polynomials of degree ≤ d are reproduced
+	x := utl.LinSpace(-1, 2, 11)
+	y := make([]float64, len(x))
+	for i, xi := range x {
+		y[i] = xi*xi*xi - 2*xi + 1
+	}
+	o := NewFloaterHormann(3, x, y)
+	for _, xx := range utl.LinSpace(-1, 2, 31) {
+		chk.Float64(tst, io.Sf("cubic(%.2f)", xx), 1e-13, o.I(xx), xx*xx*xx-2*xx+1)
+	}
+
+	// Runge function on equispaced points
+	f := func(x float64) float64 { return 1 / (1 + 25*x*x) }
+	io.Pl()
+	for _, d := range []int{0, 3, 5} {
+		errs := make([]float64, 0)
+		for _, n := range []int{21, 41, 81} {
+			x = utl.LinSpace(-1, 1, n)
+			y = make([]float64, n)
+			for i, xi := range x {
+				y[i] = f(xi)
+			}
+			o = NewFloaterHormann(d, x, y)
+			for i, xi := range x {
+				chk.Float64(tst, "r(xi)", 1e-17, o.I(xi), y[i])
+			}
+			emax := 0.0
+			for _, xx := range utl.LinSpace(-1, 1, 1001) {
+				emax = math.Max(emax, math.Abs(o.I(xx)-f(xx)))
+			}
+			errs = append(errs, emax)
+
+			// no real poles
+			poles, _ := o.Poles()
+			for _, p := range poles {
+				if math.Abs(imag(p)) < 1e-8 {
+					tst.Errorf("Floater-Hormann interpolant should not have real poles. p=%v\n", p)
+				}
+			}
+		}
+		io.Pf("d = %d  errors = %.3e\n", d, errs)
+		for k := 1; k < len(errs); k++ {
+			if errs[k] > errs[k-1] {
+				tst.Errorf("error should decrease with n\n")
+			}
+		}
+		if d == 5 && errs[2] > 1e-5 {
+			tst.Errorf("error is too large: %g\n", errs[2])
+		}
+	}
+}
+
+func TestAAA01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("AAA01. rational function: poles, residues and zeros")
+
+	// f(x) = (x²+1) / ((x-1.2) (x²+0.25))
+	f := func(x float64) float64 { return (x*x + 1) / ((x - 1.2) * (x*x + 0.25)) }
+	o := NewAAA(utl.LinSpace(-1, 1, 200), f, 1e-13, 100)
+	io.Pforan("number of support points = %d\n", len(o.Z))
+	chk.Int(tst, "m", len(o.Z), 4)
+	for _, x := range []float64{-3, -0.77, 0.1, 0.999, 5} {
+		chk.Float64(tst, io.Sf("r(%g)", x), 1e-12*math.Abs(f(x)), o.I(x), f(x))
+	}
+
+	// poles and residues
+	poles, res := o.Poles()
+	chk.Int(tst, "number of poles", len(poles), 3)
+	refPoles := []complex128{1.2, 0.5i, -0.5i}
+	refRes := []complex128{2.44 / 1.69, 0.75 / ((0.5i - 1.2) * 1i), 0.75 / ((-0.5i - 1.2) * (-1i))}
+	for k, p := range refPoles {
+		found := false
+		for i := range poles {
+			if cmplx.Abs(poles[i]-p) < 1e-10 {
+				chk.Complex128(tst, io.Sf("res(%v)", p), 1e-9, res[i], refRes[k])
+				found = true
+			}
+		}
+		if !found {
+			tst.Errorf("pole %v not found in %v\n", p, poles)
+		}
+	}
+
+	// zeros
+	zeros := o.Zeros()
+	sort.Slice(zeros, func(i, j int) bool { return imag(zeros[i]) < imag(zeros[j]) })
+	chk.Int(tst, "number of zeros", len(zeros), 2)
+	chk.Complex128(tst, "-i", 1e-10, zeros[0], -1i)
+	chk.Complex128(tst, "+i", 1e-10, zeros[1], 1i)
+}
+
+func TestAAA02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("AAA02. tan(x) and entire function")
+
+	// tan(x) near its poles ±π/2
+	x := utl.LinSpace(-1.5, 1.5, 1000)
+	o := NewAAA(x, math.Tan, 1e-13, 100)
+	io.Pforan("tan: number of support points = %d\n", len(o.Z))
+	for _, xx := range []float64{-1.4999, -0.3, 0.123, 1.2, 1.4567} {
+		chk.Float64(tst, io.Sf("tan(%g)", xx), 1e-11*(1+math.Abs(math.Tan(xx))), o.I(xx), math.Tan(xx))
+	}
+	poles, res := o.Poles()
+	for _, s := range []float64{-1, 1} {
+		p := complex(s*math.Pi/2, 0)
+		found := false
+		for i := range poles {
+			if cmplx.Abs(poles[i]-p) < 1e-10 {
+				chk.Complex128(tst, io.Sf("res(%v)", p), 1e-8, res[i], -1)
+				found = true
+			}
+		}
+		if !found {
+			tst.Errorf("pole %v not found\n", p)
+		}
+	}
+
+	// entire function: no spurious poles in [-1,1]
+	f := func(x float64) float64 { return math.Exp(x) * math.Sin(5*x) }
+	o = NewAAA(utl.LinSpace(-1, 1, 500), f, 1e-13, 100)
+	io.Pforan("exp⋅sin: number of support points = %d\n", len(o.Z))
+	emax := 0.0
+	for _, xx := range utl.LinSpace(-1, 1, 777) {
+		emax = math.Max(emax, math.Abs(o.I(xx)-f(xx)))
+	}
+	io.Pforan("max error = %v\n", emax)
+	if emax > 1e-12 {
+		tst.Errorf("error is too large: %g\n", emax)
+	}
+	poles, _ = o.Poles()
+	for _, p := range poles {
+		if math.Abs(imag(p)) < 1e-3 && math.Abs(real(p)) <= 1 {
+			tst.Errorf("spurious pole in [-1,1]: %v\n", p)
+		}
+	}
+}
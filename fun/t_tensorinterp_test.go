@@ -0,0 +1,98 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestTensorInterp01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("TensorInterp01. exact for polynomials. 2D and 3D")
+
+	// 2D: degree (3, 2) ⇒ exact for x³y²
+	f := func(x la.Vector) float64 { return 1 + x[0]*x[0]*x[0]*x[1]*x[1] - 2*x[1] }
+	dfdx := func(x la.Vector) []float64 {
+		return []float64{3 * x[0] * x[0] * x[1] * x[1], 2*x[0]*x[0]*x[0]*x[1] - 2}
+	}
+	for _, gtype := range []string{"uni", "cg", "cgl"} {
+		o := NewTensorInterp([]float64{-1, 0}, []float64{2, 3}, []int{3, 2}, []string{gtype, gtype})
+		chk.Int(tst, "size", o.Size(), 12)
+		o.CalcU(f)
+		g := la.NewVector(2)
+		for _, x := range []la.Vector{{0.3, 1.7}, {-1, 0}, {2, 3}, {1.1, 0.2}, o.Node(5)} {
+			chk.Float64(tst, io.Sf("%s: I(%v)", gtype, x), 1e-13, o.I(x), f(x))
+			res := o.Grad(g, x)
+			chk.Float64(tst, io.Sf("%s: I(%v)", gtype, x), 1e-13, res, f(x))
+			chk.Array(tst, io.Sf("%s: dIdx(%v)", gtype, x), 1e-12, g, dfdx(x))
+		}
+	}
+
+	// 3D with constant direction
+	f3 := func(x la.Vector) float64 { return x[0]*x[2] - x[2]*x[2] }
+	o := NewTensorInterp([]float64{0, 0, 0}, []float64{1, 1, 1}, []int{1, 0, 2}, []string{"cgl", "cgl", "cgl"})
+	chk.Int(tst, "size", o.Size(), 6)
+	chk.Array(tst, "node 1", 1e-15, o.Node(1), []float64{1, 0.5, 0})
+	o.CalcU(f3)
+	g := la.NewVector(3)
+	x := la.Vector{0.2, 0.9, 0.7}
+	chk.Float64(tst, "I(x)", 1e-14, o.Grad(g, x), f3(x))
+	chk.Array(tst, "dIdx(x)", 1e-14, g, []float64{0.7, 0, 0.2 - 1.4})
+}
+
+func TestTensorInterp02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("TensorInterp02. convergence and Smolyak sparse grids")
+
+	f := func(x la.Vector) float64 { return math.Exp(x[0]) * math.Sin(x[1]+0.5*x[2]) }
+
+	// tensor product
+	var prev float64
+	for _, N := range []int{4, 8, 12} {
+		o := NewTensorInterp([]float64{-1, -1, -1}, []float64{1, 1, 1}, []int{N, N, N}, []string{"cgl", "cgl", "cgl"})
+		o.CalcU(f)
+		maxerr, xloc := o.EstimateMaxErr(f, 7)
+		io.Pf("tensor  N = %2d  npts = %5d  maxerr = %.3e @ %v\n", N, o.Size(), maxerr, xloc)
+		if N > 4 && maxerr > prev/100 {
+			tst.Errorf("error must decrease quickly\n")
+		}
+		prev = maxerr
+	}
+	chk.Float64(tst, "tensor error", 1e-11, prev, 0)
+
+	// Smolyak
+	sizes := []int{1, 7, 25, 69, 177}
+	for level := 0; level < 5; level++ {
+		o := NewSmolyakInterp([]float64{-1, -1, -1}, []float64{1, 1, 1}, level)
+		chk.Int(tst, io.Sf("smolyak level %d: size", level), o.Size(), sizes[level])
+		o.CalcU(f)
+		for _, x := range o.X {
+			chk.Float64(tst, "interpolatory", 1e-13, o.I(x), f(x))
+		}
+		maxerr, _ := o.EstimateMaxErr(f, 7)
+		io.Pf("smolyak level = %d  npts = %5d  maxerr = %.3e\n", level, o.Size(), maxerr)
+		if level > 1 && maxerr > prev/2 {
+			tst.Errorf("error must decrease\n")
+		}
+		prev = maxerr
+	}
+	chk.Float64(tst, "smolyak error", 1e-3, prev, 0)
+
+	// Smolyak: exact for total degree ≤ level (and gradient)
+	p := func(x la.Vector) float64 { return 1 + x[0]*x[1] - x[2]*x[2] + 3*x[0]*x[1]*x[2] }
+	o := NewSmolyakInterp([]float64{0, 1, -1}, []float64{2, 2, 1}, 3)
+	o.CalcU(p)
+	x := la.Vector{0.3, 1.4, 0.2}
+	g := la.NewVector(3)
+	chk.Float64(tst, "smolyak: p(x)", 1e-13, o.Grad(g, x), p(x))
+	chk.Array(tst, "smolyak: dpdx(x)", 1e-12, g, []float64{x[1] + 3*x[1]*x[2], x[0] + 3*x[0]*x[2], -2*x[2] + 3*x[0]*x[1]})
+}
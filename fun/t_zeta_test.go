@@ -0,0 +1,44 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestZeta01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Zeta01. Riemann zeta function")
+
+	π := math.Pi
+	chk.Float64(tst, "ζ(2)", 1e-15, Zeta(2), π*π/6)
+	chk.Float64(tst, "ζ(4)", 1e-15, Zeta(4), math.Pow(π, 4)/90)
+	chk.Float64(tst, "ζ(6)", 1e-15, Zeta(6), math.Pow(π, 6)/945)
+	chk.Float64(tst, "ζ(3)", 1e-15, Zeta(3), 1.2020569031595942854)
+	chk.Float64(tst, "ζ(½)", 1e-15, Zeta(0.5), -1.4603545088095868129)
+	chk.Float64(tst, "ζ(0)", 1e-15, Zeta(0), -0.5)
+	chk.Float64(tst, "ζ(-1)", 1e-15, Zeta(-1), -1.0/12)
+	chk.Float64(tst, "ζ(-3)", 1e-15, Zeta(-3), 1.0/120)
+	chk.Float64(tst, "ζ(-2)", 1e-15, Zeta(-2), 0)
+	chk.Float64(tst, "ζ(-13)", 1e-15, Zeta(-13), -1.0/12)
+	chk.Float64(tst, "ζ(80)", 1e-15, Zeta(80), 1)
+	if !math.IsInf(Zeta(1), 1) {
+		tst.Errorf("ζ(1) should be +Inf\n")
+	}
+
+	// Euler product check for large s: ζ(s) ≈ 1 + 2⁻ˢ + 3⁻ˢ + 4⁻ˢ + ...
+	for _, s := range []float64{10, 20, 40} {
+		ref := 0.0
+		for k := 1; k < 100; k++ {
+			ref += math.Pow(float64(k), -s)
+		}
+		chk.Float64(tst, io.Sf("ζ(%g)", s), 1e-15, Zeta(s), ref)
+	}
+}
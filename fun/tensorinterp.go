@@ -0,0 +1,422 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fun
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// TensorInterp implements N-D tensor-product Lagrange interpolation over a box [xmin, xmax]
+//
+//                     N0    N1         N(n-1)
+//  I{f}(x) = Σ Σ ... Σ   U[i0,i1,...] ⋅ ℓ_i0(ξ0) ⋅ ℓ_i1(ξ1) ⋅ ... ⋅ ℓ_i(n-1)(ξ(n-1))
+//                    i0=0  i1=0        i(n-1)=0
+//
+//  where ℓ_i are the 1D Lagrange cardinal polynomials (see LagrangeInterp), computed with the
+//  barycentric formula, and ξk = 2⋅(xk - xmin[k]) / (xmax[k] - xmin[k]) - 1 ∈ [-1, 1].
+//
+//  The nodes are numbered with the first dimension running fastest:
+//
+//    I = i0 + N0⋅(i1 + N1⋅(i2 + ...))      with Nk = degree[k] + 1
+//
+//  A degree equal to zero means that the interpolant is constant along that direction with the
+//  single node located at the centre of the box.
+//
+//  The evaluation costs O(Πₖ Nk) operations: the 1D cardinal polynomials are computed first and the
+//  tensor of nodal values is then contracted dimension by dimension.
+//
+//  NOTE: I and Grad use internal workspaces; thus, they must not be called concurrently
+type TensorInterp struct {
+	Ndim int       // space dimension
+	Xmin la.Vector // [ndim] min coordinates of box
+	Xmax la.Vector // [ndim] max coordinates of box
+	Npts []int     // [ndim] number of points along each direction = degree + 1
+	Lis  LagIntSet // [ndim] 1D interpolators (nil for degree 0)
+	U    la.Vector // [Size] function evaluated @ nodes: f(x_I)
+
+	// workspace
+	ell  [][]float64 // [ndim][npts] cardinal polynomials @ x
+	dell [][]float64 // [ndim][npts] derivatives of cardinal polynomials @ x (w.r.t ξ)
+	vecs [][]float64 // [ndim] pointers to ell or dell
+	work []float64   // workspace for contractions
+}
+
+// NewTensorInterp returns a new tensor-product Lagrange interpolator
+//  xmin, xmax -- [ndim] limits of box
+//  degrees    -- [ndim] degrees of 1D interpolators (≥ 0)
+//  gridTypes  -- [ndim] types of 1D grids: "uni", "cg" or "cgl" (see NewLagrangeInterp)
+func NewTensorInterp(xmin, xmax []float64, degrees []int, gridTypes []string) (o *TensorInterp) {
+
+	// check
+	ndim := len(xmin)
+	if ndim < 1 || len(xmax) != ndim || len(degrees) != ndim || len(gridTypes) != ndim {
+		chk.Panic("xmin, xmax, degrees and gridTypes must have the same length ≥ 1\n")
+	}
+
+	// allocate
+	o = new(TensorInterp)
+	o.Ndim = ndim
+	o.Xmin = la.NewVector(ndim)
+	o.Xmax = la.NewVector(ndim)
+	o.Npts = make([]int, ndim)
+	o.Lis = make([]*LagrangeInterp, ndim)
+	o.ell = make([][]float64, ndim)
+	o.dell = make([][]float64, ndim)
+	o.vecs = make([][]float64, ndim)
+	for k := 0; k < ndim; k++ {
+		if xmax[k] <= xmin[k] {
+			chk.Panic("xmax must be greater than xmin. xmin[%d] = %g and xmax[%d] = %g are invalid\n", k, xmin[k], k, xmax[k])
+		}
+		if degrees[k] < 0 {
+			chk.Panic("degrees must be non-negative. degrees[%d] = %d is invalid\n", k, degrees[k])
+		}
+		o.Xmin[k], o.Xmax[k] = xmin[k], xmax[k]
+		o.Npts[k] = degrees[k] + 1
+		if degrees[k] > 0 {
+			o.Lis[k] = NewLagrangeInterp(degrees[k], gridTypes[k])
+			o.Lis[k].CalcD1()
+		}
+		o.ell[k] = make([]float64, o.Npts[k])
+		o.dell[k] = make([]float64, o.Npts[k])
+	}
+	o.U = la.NewVector(o.Size())
+	o.work = make([]float64, o.Size()/o.Npts[0])
+	return
+}
+
+// Size returns the total number of nodes
+func (o *TensorInterp) Size() (size int) {
+	size = 1
+	for _, n := range o.Npts {
+		size *= n
+	}
+	return
+}
+
+// Node returns the coordinates of node I
+func (o *TensorInterp) Node(I int) (x la.Vector) {
+	x = la.NewVector(o.Ndim)
+	for k := 0; k < o.Ndim; k++ {
+		i := I % o.Npts[k]
+		I /= o.Npts[k]
+		ξ := 0.0
+		if o.Lis[k] != nil {
+			ξ = o.Lis[k].X[i]
+		}
+		x[k] = o.Xmin[k] + (ξ+1.0)*(o.Xmax[k]-o.Xmin[k])/2.0
+	}
+	return
+}
+
+// CalcU computes f(x_I); i.e. function f(x) @ all nodes
+func (o *TensorInterp) CalcU(f Sv) {
+	for I := 0; I < len(o.U); I++ {
+		o.U[I] = f(o.Node(I))
+	}
+}
+
+// I computes the interpolation I{f}(x) @ x
+//  NOTE: U must be calculated with CalcU or set first
+func (o *TensorInterp) I(x la.Vector) float64 {
+	for k := 0; k < o.Ndim; k++ {
+		o.cardinal(k, x[k], false)
+		o.vecs[k] = o.ell[k]
+	}
+	return tensorContract(o.U, o.Npts, o.vecs, o.work)
+}
+
+// Grad computes the gradient of the interpolant @ x and returns the interpolation I{f}(x)
+//  Output:
+//    g -- [ndim] gradient dI{f}/dx @ x
+//  NOTE: U must be calculated with CalcU or set first
+func (o *TensorInterp) Grad(g, x la.Vector) (res float64) {
+	for k := 0; k < o.Ndim; k++ {
+		o.cardinal(k, x[k], true)
+		o.vecs[k] = o.ell[k]
+	}
+	res = tensorContract(o.U, o.Npts, o.vecs, o.work)
+	for k := 0; k < o.Ndim; k++ {
+		o.vecs[k] = o.dell[k]
+		g[k] = tensorContract(o.U, o.Npts, o.vecs, o.work) * 2.0 / (o.Xmax[k] - o.Xmin[k])
+		o.vecs[k] = o.ell[k]
+	}
+	return
+}
+
+// EstimateMaxErr estimates the maximum error using a tensor grid of stations
+//
+//   maxerr = max(|f - I{f}|)
+//
+//  Input:
+//    f    -- function
+//    nsta -- number of stations along each direction [use ≤ 1 for default = 11]
+//  Output:
+//    maxerr -- maximum error
+//    xloc   -- location of maximum error
+//
+//  NOTE: U must be calculated with CalcU or set first
+func (o *TensorInterp) EstimateMaxErr(f Sv, nsta int) (maxerr float64, xloc la.Vector) {
+	return tensorMaxErr(o.Xmin, o.Xmax, nsta, f, o.I)
+}
+
+// cardinal computes the 1D cardinal polynomials (and derivatives) along direction k @ xk
+func (o *TensorInterp) cardinal(k int, xk float64, withDerivs bool) {
+	li := o.Lis[k]
+	if li == nil {
+		o.ell[k][0], o.dell[k][0] = 1, 0
+		return
+	}
+	ξ := 2.0*(xk-o.Xmin[k])/(o.Xmax[k]-o.Xmin[k]) - 1.0
+	cardinalPolys(o.ell[k], o.dell[k], li.X, li.Lam, li.D1, ξ, withDerivs)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// cardinalPolys computes the Lagrange cardinal polynomials ℓ_j(ξ) and derivatives dℓ_j/dξ with the
+// barycentric formula and its derivative
+//
+//                  ⎛  Σ λk/(ξ-xk)²      1    ⎞
+//   dℓ_j/dξ = ℓ_j ⋅ ⎜ ———————————— - ——————— ⎟
+//                  ⎝  Σ λk/(ξ-xk)     ξ - xj ⎠
+//
+//  or D1[m][j] if ξ coincides with node m
+func cardinalPolys(ell, dell, X, Lam []float64, D1 *la.Matrix, ξ float64, withDerivs bool) {
+	n := len(X)
+	for m := 0; m < n; m++ {
+		if math.Abs(ξ-X[m]) < 1e-15 {
+			for j := 0; j < n; j++ {
+				ell[j] = 0
+				if withDerivs {
+					dell[j] = D1.Get(m, j)
+				}
+			}
+			ell[m] = 1
+			return
+		}
+	}
+	var s1, s2 float64
+	for j := 0; j < n; j++ {
+		r := Lam[j] / (ξ - X[j])
+		ell[j] = r
+		s1 += r
+		s2 += r / (ξ - X[j])
+	}
+	for j := 0; j < n; j++ {
+		ell[j] /= s1
+		if withDerivs {
+			dell[j] = ell[j] * (s2/s1 - 1.0/(ξ-X[j]))
+		}
+	}
+}
+
+// tensorContract computes Σ T[i0,i1,...] ⋅ v0[i0] ⋅ v1[i1] ⋅ ... with the first index running fastest
+//  work -- workspace with length ≥ len(T) / n[0]
+func tensorContract(T []float64, n []int, vecs [][]float64, work []float64) float64 {
+	src := T
+	size := len(T)
+	for d := 0; d < len(n); d++ {
+		nd := n[d]
+		size /= nd
+		v := vecs[d]
+		for j := 0; j < size; j++ {
+			sum := 0.0
+			for i := 0; i < nd; i++ {
+				sum += src[j*nd+i] * v[i]
+			}
+			work[j] = sum // NOTE: in-place is fine since j ≤ j⋅nd
+		}
+		src = work
+	}
+	return src[0]
+}
+
+// tensorMaxErr estimates the maximum error of an interpolant using a tensor grid of stations
+func tensorMaxErr(xmin, xmax la.Vector, nsta int, f, interp Sv) (maxerr float64, xloc la.Vector) {
+	if nsta < 2 {
+		nsta = 11
+	}
+	ndim := len(xmin)
+	total := 1
+	for k := 0; k < ndim; k++ {
+		total *= nsta
+	}
+	x := la.NewVector(ndim)
+	xloc = la.NewVector(ndim)
+	for I := 0; I < total; I++ {
+		J := I
+		for k := 0; k < ndim; k++ {
+			x[k] = xmin[k] + float64(J%nsta)*(xmax[k]-xmin[k])/float64(nsta-1)
+			J /= nsta
+		}
+		e := math.Abs(f(x) - interp(x))
+		if e > maxerr || I == 0 {
+			maxerr = e
+			copy(xloc, x)
+		}
+	}
+	return
+}
+
+// SmolyakInterp implements Smolyak's sparse-grid interpolation over a box [xmin, xmax]
+//
+//                    w                     ⎛ n - 1 ⎞
+//  A(w,n) =   Σ          (-1)^(w - |l|) ⋅ ⎜       ⎟ ⋅ U(l0) ⊗ U(l1) ⊗ ... ⊗ U(l(n-1))
+//       |l|=max(0,w-n+1)                   ⎝ w-|l| ⎠
+//
+//  where n is the dimension, w is the level, l is a multi-index with lk ≥ 0 and U(l) is the 1D
+//  Lagrange interpolation on the nested Chebyshev-Gauss-Lobatto grid with m(l) points:
+//
+//    m(0) = 1 (centre)    and    m(l) = 2ˡ + 1
+//
+//  Each term is a TensorInterp and the function is evaluated only once at each (unique) node of
+//  the sparse grid. The number of nodes grows much slower with n than for the full tensor grid.
+//
+//  References:
+//    [1] Barthelmann V, Novak E and Ritter K (2000) High dimensional polynomial interpolation on
+//        sparse grids. Advances in Computational Mathematics, 12:273-288
+type SmolyakInterp struct {
+	Ndim  int             // space dimension
+	Level int             // level w
+	Xmin  la.Vector       // [ndim] min coordinates of box
+	Xmax  la.Vector       // [ndim] max coordinates of box
+	X     []la.Vector     // [npts] nodes of sparse grid
+	U     la.Vector       // [npts] function evaluated @ nodes
+	Terms []*TensorInterp // [nterms] tensor-product interpolators
+	Coefs []float64       // [nterms] combination coefficients
+	maps  [][]int         // [nterms][size of term] maps nodes of terms to nodes of sparse grid
+	g     la.Vector       // workspace for gradients
+}
+
+// NewSmolyakInterp returns a new Smolyak sparse-grid interpolator
+//  xmin, xmax -- [ndim] limits of box
+//  level      -- level w ≥ 0 (w = 0 yields a constant interpolant)
+func NewSmolyakInterp(xmin, xmax []float64, level int) (o *SmolyakInterp) {
+
+	// check
+	ndim := len(xmin)
+	if ndim < 1 || len(xmax) != ndim || level < 0 {
+		chk.Panic("xmin and xmax must have the same length ≥ 1 and level must be non-negative. level = %d is invalid\n", level)
+	}
+
+	// allocate
+	o = new(SmolyakInterp)
+	o.Ndim = ndim
+	o.Level = level
+	o.Xmin = la.NewVector(ndim)
+	o.Xmax = la.NewVector(ndim)
+	copy(o.Xmin, xmin)
+	copy(o.Xmax, xmax)
+	o.g = la.NewVector(ndim)
+
+	// combination technique
+	gridTypes := make([]string, ndim)
+	for k := 0; k < ndim; k++ {
+		gridTypes[k] = "cgl"
+	}
+	index := make(map[string]int)
+	idx := make([]int, ndim)
+	var recurse func(k, sum int)
+	recurse = func(k, sum int) {
+		if k == ndim {
+			if sum < level-ndim+1 {
+				return
+			}
+			c := NegOnePowN(level-sum) * Binomial(ndim-1, level-sum)
+			degrees := make([]int, ndim)
+			for j := 0; j < ndim; j++ {
+				if idx[j] > 0 {
+					degrees[j] = 1 << uint(idx[j])
+				}
+			}
+			term := NewTensorInterp(xmin, xmax, degrees, gridTypes)
+			mp := make([]int, term.Size())
+			for I := 0; I < term.Size(); I++ {
+				x := term.Node(I)
+				key := io.Sf("%v", x)
+				if i, ok := index[key]; ok {
+					mp[I] = i
+				} else {
+					index[key] = len(o.X)
+					mp[I] = len(o.X)
+					o.X = append(o.X, x)
+				}
+			}
+			o.Terms = append(o.Terms, term)
+			o.Coefs = append(o.Coefs, c)
+			o.maps = append(o.maps, mp)
+			return
+		}
+		for l := 0; sum+l <= level; l++ {
+			idx[k] = l
+			recurse(k+1, sum+l)
+		}
+	}
+	recurse(0, 0)
+	o.U = la.NewVector(len(o.X))
+	return
+}
+
+// Size returns the number of nodes of the sparse grid
+func (o *SmolyakInterp) Size() int {
+	return len(o.X)
+}
+
+// CalcU computes f(x_I); i.e. function f(x) @ all nodes of the sparse grid
+func (o *SmolyakInterp) CalcU(f Sv) {
+	for I, x := range o.X {
+		o.U[I] = f(x)
+	}
+	o.SetU(o.U)
+}
+
+// SetU sets the values at the nodes of the sparse grid (e.g. computed externally)
+func (o *SmolyakInterp) SetU(U la.Vector) {
+	if len(U) != len(o.X) {
+		chk.Panic("len(U) must be equal to the number of nodes = %d. %d is invalid\n", len(o.X), len(U))
+	}
+	copy(o.U, U)
+	for t, term := range o.Terms {
+		for I, i := range o.maps[t] {
+			term.U[I] = o.U[i]
+		}
+	}
+}
+
+// I computes the interpolation A{f}(x) @ x
+//  NOTE: U must be calculated with CalcU or SetU first
+func (o *SmolyakInterp) I(x la.Vector) (res float64) {
+	for t, term := range o.Terms {
+		res += o.Coefs[t] * term.I(x)
+	}
+	return
+}
+
+// Grad computes the gradient of the interpolant @ x and returns the interpolation A{f}(x)
+//  Output:
+//    g -- [ndim] gradient dA{f}/dx @ x
+//  NOTE: U must be calculated with CalcU or SetU first
+func (o *SmolyakInterp) Grad(g, x la.Vector) (res float64) {
+	g.Fill(0)
+	for t, term := range o.Terms {
+		res += o.Coefs[t] * term.Grad(o.g, x)
+		la.VecAdd(g, 1, g, o.Coefs[t], o.g)
+	}
+	return
+}
+
+// EstimateMaxErr estimates the maximum error using a tensor grid of stations
+//
+//   maxerr = max(|f - A{f}|)
+//
+//  nsta -- number of stations along each direction [use ≤ 1 for default = 11]
+//
+//  NOTE: U must be calculated with CalcU or SetU first
+func (o *SmolyakInterp) EstimateMaxErr(f Sv, nsta int) (maxerr float64, xloc la.Vector) {
+	return tensorMaxErr(o.Xmin, o.Xmax, nsta, f, o.I)
+}
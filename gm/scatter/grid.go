@@ -0,0 +1,23 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scatter
+
+import (
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/la"
+)
+
+// EvalGrid evaluates f (e.g. Rbf.F, Sibson.F or Kriging.F) at all nodes of a grid
+//  Output:
+//    v -- [g.Size()] values at nodes: v[I] = f(g.Node(I)). Use g.MapMeshgrid2d(v) or
+//         g.MapMeshgrid3d(v) to obtain the values in meshgrid format
+func EvalGrid(g *gm.Grid, f fun.Sv) (v la.Vector) {
+	v = la.NewVector(g.Size())
+	for I := 0; I < g.Size(); I++ {
+		v[I] = f(g.Node(I))
+	}
+	return
+}
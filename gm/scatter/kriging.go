@@ -0,0 +1,265 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scatter
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/num"
+)
+
+// Kriging implements ordinary kriging of scattered data
+//          npts-1
+//   f(x) =   Σ    λᵢ(x) ⋅ yᵢ      with  Σλᵢ = 1
+//           i=0
+//
+//  where the weights λ minimise the estimation variance for the (semi-)variogram γ(h):
+//
+//   ┌        ┐ ┌   ┐   ┌    ┐
+//   │ Γ    1 │ │ λ │   │ γ₀ │
+//   │        │ │   │ = │    │    with  Γᵢⱼ = γ(‖xᵢ - xⱼ‖)  and  γ₀ᵢ = γ(‖x - xᵢ‖)
+//   │ 1ᵀ   0 │ │ μ │   │ 1  │
+//   └        ┘ └   ┘   └    ┘
+//
+//  The kriging variance is σ²(x) = λᵀγ₀ + μ. The variogram models are (h > 0, r = h / Range):
+//    "sph" -- spherical:   γ = Nugget + Sill ⋅ (1.5 r - 0.5 r³) if r < 1; Nugget + Sill otherwise
+//    "exp" -- exponential: γ = Nugget + Sill ⋅ (1 - exp(-3 r))
+//    "gau" -- Gaussian:    γ = Nugget + Sill ⋅ (1 - exp(-3 r²))
+//  with γ(0) = 0. Thus, the total sill is Nugget + Sill and Range is the (practical) range.
+//
+//  Note: F and Variance can be used as fun.Sv functions
+//
+//   Reference:
+//   [1] Isaaks EH and Srivastava RM (1989) An Introduction to Applied Geostatistics. Oxford
+//       University Press, New York. 561p
+type Kriging struct {
+	Model  string      // variogram model: "sph", "exp" or "gau"
+	Nugget float64     // nugget effect
+	Sill   float64     // partial sill (total sill = Nugget + Sill)
+	Range  float64     // range
+	Ndim   int         // space dimension
+	Npts   int         // number of data points
+	X      [][]float64 // [npts][ndim] data points
+	Y      []float64   // [npts] data values
+	ai     *la.Matrix  // [npts+1][npts+1] inverse of kriging matrix
+	ay     la.Vector   // [npts+1] ai ⋅ {y, 0}
+}
+
+// NewKriging returns a new ordinary kriging interpolator
+//  Input:
+//    model -- variogram model: "sph", "exp" or "gau"
+//    X     -- [npts][ndim] data points
+//    y     -- [npts] data values
+//    vprms -- variogram parameters {nugget, sill, range}; may be nil => fitted to the empirical
+//             variogram computed with 15 bins up to half of the largest distance between points
+func NewKriging(model string, X [][]float64, y []float64, vprms []float64) (o *Kriging) {
+
+	// check
+	o = new(Kriging)
+	o.Npts, o.Ndim = checkData(X, y)
+	if o.Npts < 2 {
+		chk.Panic("at least 2 points are required. npts = %d is invalid", o.Npts)
+	}
+	o.Model = model
+	o.X = X
+	o.Y = y
+
+	// variogram
+	if vprms == nil {
+		h, γ, count := EmpiricalVariogram(X, y, 15, 0)
+		o.Nugget, o.Sill, o.Range = FitVariogram(model, h, γ, count)
+	} else {
+		if len(vprms) != 3 {
+			chk.Panic("variogram parameters must be {nugget, sill, range}. len(vprms) = %d is invalid", len(vprms))
+		}
+		o.Nugget, o.Sill, o.Range = vprms[0], vprms[1], vprms[2]
+	}
+	if o.Nugget < 0 || o.Sill <= 0 || o.Range <= 0 {
+		chk.Panic("variogram parameters must satisfy nugget ≥ 0, sill > 0 and range > 0. {%g, %g, %g} is invalid", o.Nugget, o.Sill, o.Range)
+	}
+	o.Variogram(0) // check model name
+
+	// kriging matrix
+	n := o.Npts + 1
+	A := la.NewMatrix(n, n)
+	for i := 0; i < o.Npts; i++ {
+		for j := i + 1; j < o.Npts; j++ {
+			γ := o.Variogram(dist(X[i], X[j]))
+			A.Set(i, j, γ)
+			A.Set(j, i, γ)
+		}
+		A.Set(i, o.Npts, 1)
+		A.Set(o.Npts, i, 1)
+	}
+
+	// inverse and weights of data
+	o.ai = la.NewMatrix(n, n)
+	la.MatInv(o.ai, A, false)
+	yy := la.NewVector(n)
+	copy(yy, y)
+	o.ay = la.NewVector(n)
+	la.MatVecMul(o.ay, 1, o.ai, yy)
+	return
+}
+
+// Variogram computes the model variogram γ(h)
+func (o *Kriging) Variogram(h float64) float64 {
+	return variogram(o.Model, h, o.Nugget, o.Sill, o.Range)
+}
+
+// F evaluates the kriging estimate at x
+func (o *Kriging) F(x la.Vector) (res float64) {
+	for i := 0; i < o.Npts; i++ {
+		res += o.ay[i] * o.Variogram(dist(x, o.X[i]))
+	}
+	return res + o.ay[o.Npts]
+}
+
+// Variance evaluates the kriging variance σ² at x
+func (o *Kriging) Variance(x la.Vector) float64 {
+	n := o.Npts + 1
+	b := la.NewVector(n)
+	for i := 0; i < o.Npts; i++ {
+		b[i] = o.Variogram(dist(x, o.X[i]))
+	}
+	b[o.Npts] = 1
+	λ := la.NewVector(n)
+	la.MatVecMul(λ, 1, o.ai, b)
+	return math.Max(la.VecDot(λ, b), 0)
+}
+
+// EmpiricalVariogram computes the empirical (semi-)variogram of scattered data
+//              1     N(h)
+//   γ(h) =  ──────    Σ   (yᵢ - yⱼ)²     for pairs with ‖xᵢ - xⱼ‖ in the bin of h
+//           2 N(h)   i,j
+//
+//  Input:
+//    X      -- [npts][ndim] data points
+//    y      -- [npts] data values
+//    nbins  -- number of bins (lag classes)
+//    maxLag -- maximum lag distance; use ≤0 for default = half of the largest distance between points
+//  Output:
+//    h     -- [nbins] mean lag distance in each bin (centre of bin if empty)
+//    γ     -- [nbins] semivariance in each bin (zero if empty)
+//    count -- [nbins] number of pairs N(h) in each bin
+func EmpiricalVariogram(X [][]float64, y []float64, nbins int, maxLag float64) (h, γ []float64, count []int) {
+
+	// check
+	npts, _ := checkData(X, y)
+	if nbins < 1 {
+		chk.Panic("number of bins must be at least 1. nbins = %d is invalid", nbins)
+	}
+	if maxLag <= 0 {
+		for i := 0; i < npts; i++ {
+			for j := i + 1; j < npts; j++ {
+				maxLag = math.Max(maxLag, dist(X[i], X[j]))
+			}
+		}
+		maxLag /= 2.0
+	}
+	if maxLag <= 0 {
+		chk.Panic("cannot compute variogram of coincident points")
+	}
+
+	// accumulate pairs
+	h = make([]float64, nbins)
+	γ = make([]float64, nbins)
+	count = make([]int, nbins)
+	Δ := maxLag / float64(nbins)
+	for i := 0; i < npts; i++ {
+		for j := i + 1; j < npts; j++ {
+			d := dist(X[i], X[j])
+			if d > maxLag {
+				continue
+			}
+			k := int(d / Δ)
+			if k == nbins {
+				k--
+			}
+			h[k] += d
+			γ[k] += (y[i] - y[j]) * (y[i] - y[j])
+			count[k]++
+		}
+	}
+
+	// averages
+	for k := 0; k < nbins; k++ {
+		if count[k] == 0 {
+			h[k] = (float64(k) + 0.5) * Δ
+			continue
+		}
+		h[k] /= float64(count[k])
+		γ[k] /= 2.0 * float64(count[k])
+	}
+	return
+}
+
+// FitVariogram fits a variogram model to the empirical variogram by weighted least-squares
+//  Input:
+//    model -- variogram model: "sph", "exp" or "gau"
+//    h     -- [nbins] lag distances
+//    γ     -- [nbins] semivariances
+//    count -- [nbins] number of pairs in each bin; bins with zero pairs are ignored. The residuals
+//             are weighted by √count [may be nil => all bins with equal weights]
+//  Output:
+//    nugget, sill, rng -- variogram parameters (see Kriging)
+func FitVariogram(model string, h, γ []float64, count []int) (nugget, sill, rng float64) {
+
+	// data
+	var xx, yy, σ []float64
+	γmax, hmax := 0.0, 0.0
+	for k := 0; k < len(h); k++ {
+		if count != nil && count[k] == 0 {
+			continue
+		}
+		xx = append(xx, h[k])
+		yy = append(yy, γ[k])
+		if count != nil {
+			σ = append(σ, 1.0/math.Sqrt(float64(count[k])))
+		}
+		γmax, hmax = math.Max(γmax, γ[k]), math.Max(hmax, h[k])
+	}
+	if len(xx) < 3 {
+		chk.Panic("at least 3 non-empty bins are required to fit the variogram")
+	}
+	if γmax == 0 {
+		chk.Panic("cannot fit variogram of constant data")
+	}
+
+	// fit
+	var fit num.LsqFit
+	fit.Init(3, func(x float64, p la.Vector) float64 {
+		return variogram(model, x, p[0], p[1], p[2])
+	}, nil, nil)
+	fit.SetBounds([]float64{0, 1e-8 * γmax, 1e-3 * hmax}, []float64{γmax, 10 * γmax, 10 * hmax})
+	fit.Fit(xx, yy, σ, []float64{0.1 * γmax, 0.9 * γmax, hmax / 2.0}, false)
+	return fit.P[0], fit.P[1], fit.P[2]
+}
+
+// variogram computes the model variogram
+func variogram(model string, h, nugget, sill, rng float64) float64 {
+	if h <= 0 {
+		if model != "sph" && model != "exp" && model != "gau" {
+			chk.Panic("cannot find variogram model named %q. options are \"sph\", \"exp\" and \"gau\"", model)
+		}
+		return 0
+	}
+	r := h / rng
+	switch model {
+	case "sph":
+		if r < 1 {
+			return nugget + sill*(1.5*r-0.5*r*r*r)
+		}
+		return nugget + sill
+	case "exp":
+		return nugget + sill*(1.0-math.Exp(-3.0*r))
+	case "gau":
+		return nugget + sill*(1.0-math.Exp(-3.0*r*r))
+	}
+	chk.Panic("cannot find variogram model named %q. options are \"sph\", \"exp\" and \"gau\"", model)
+	return 0
+}
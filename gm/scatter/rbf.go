@@ -0,0 +1,210 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scatter implements the interpolation of scattered data in 2D and 3D
+package scatter
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
+
+// Rbf implements radial basis function (RBF) interpolation of scattered data
+//          npts-1                        nmono-1
+//   f(x) =   Σ    wᵢ ⋅ φ(‖x - xᵢ‖)   +     Σ     cⱼ ⋅ pⱼ(x)
+//           i=0                           j=0
+//
+//  where pⱼ are the monomials of degree ≤ Deg and the coefficients are obtained from
+//
+//   ┌       ┐ ┌   ┐   ┌   ┐
+//   │ Φ   P │ │ w │   │ y │
+//   │       │ │   │ = │   │    with  Φᵢⱼ = φ(‖xᵢ - xⱼ‖)  and  Pᵢⱼ = pⱼ(xᵢ)
+//   │ Pᵀ  0 │ │ c │   │ 0 │
+//   └       ┘ └   ┘   └   ┘
+//
+//  The following kernels are available (r = distance):
+//    "tps"   -- thin-plate spline: φ = r² log(r). Requires Deg ≥ 1
+//    "mq"    -- multiquadric: φ = √(1 + (ε r)²)
+//    "gauss" -- Gaussian: φ = exp(-(ε r)²)
+//
+//  Note: F can be used as a fun.Sv function
+type Rbf struct {
+	Kind string                  // kernel: "tps", "mq" or "gauss"
+	Eps  float64                 // shape parameter ε of "mq" and "gauss"
+	Deg  int                     // degree of polynomial augmentation: -1 (none), 0 (constant) or 1 (linear)
+	Ndim int                     // space dimension
+	Npts int                     // number of data points
+	X    [][]float64             // [npts][ndim] data points
+	W    la.Vector               // [npts] weights of radial functions
+	C    la.Vector               // [nmono] coefficients of polynomial: {c0, cx, cy, cz} (according to Deg and Ndim)
+	phi  func(r float64) float64 // kernel
+}
+
+// NewRbf returns a new RBF interpolator
+//  Input:
+//    kind -- kernel: "tps", "mq" or "gauss"
+//    X    -- [npts][ndim] data points
+//    y    -- [npts] data values
+//    eps  -- shape parameter ε (ignored by "tps"); use ≤0 for default = 1/(mean distance to nearest neighbour)
+//    deg  -- degree of polynomial augmentation: -1 (none), 0 (constant) or 1 (linear)
+func NewRbf(kind string, X [][]float64, y []float64, eps float64, deg int) (o *Rbf) {
+
+	// check
+	o = new(Rbf)
+	o.Npts, o.Ndim = checkData(X, y)
+	if deg < -1 || deg > 1 {
+		chk.Panic("degree of polynomial augmentation must be -1, 0 or 1. deg = %d is invalid", deg)
+	}
+	if kind == "tps" && deg < 1 {
+		chk.Panic("thin-plate spline kernel requires linear polynomial augmentation (deg = 1)")
+	}
+	o.Kind = kind
+	o.Deg = deg
+	o.X = X
+
+	// shape parameter
+	if eps <= 0 {
+		eps = 1.0 / meanNearestDist(X)
+	}
+	o.Eps = eps
+
+	// kernel
+	switch kind {
+	case "tps":
+		o.phi = func(r float64) float64 {
+			if r == 0 {
+				return 0
+			}
+			return r * r * math.Log(r)
+		}
+	case "mq":
+		o.phi = func(r float64) float64 { return math.Sqrt(1.0 + eps*eps*r*r) }
+	case "gauss":
+		o.phi = func(r float64) float64 { return math.Exp(-eps * eps * r * r) }
+	default:
+		chk.Panic("cannot find RBF kernel named %q. options are \"tps\", \"mq\" and \"gauss\"", kind)
+	}
+
+	// system matrix
+	nmono := o.nmono()
+	n := o.Npts + nmono
+	if o.Npts < nmono {
+		chk.Panic("number of points (%d) must be at least equal to the number of monomials (%d)", o.Npts, nmono)
+	}
+	A := la.NewMatrix(n, n)
+	b := la.NewVector(n)
+	pj := la.NewVector(nmono)
+	for i := 0; i < o.Npts; i++ {
+		for j := 0; j < o.Npts; j++ {
+			A.Set(i, j, o.phi(dist(X[i], X[j])))
+		}
+		o.monomials(pj, X[i])
+		for j := 0; j < nmono; j++ {
+			A.Set(i, o.Npts+j, pj[j])
+			A.Set(o.Npts+j, i, pj[j])
+		}
+		b[i] = y[i]
+	}
+
+	// solve
+	wc := la.NewVector(n)
+	la.DenSolve(wc, A, b, false)
+	o.W = wc[:o.Npts]
+	o.C = wc[o.Npts:]
+	return
+}
+
+// F evaluates the interpolant at x
+func (o *Rbf) F(x la.Vector) (res float64) {
+	for i := 0; i < o.Npts; i++ {
+		res += o.W[i] * o.phi(dist(x, o.X[i]))
+	}
+	if o.Deg >= 0 {
+		res += o.C[0]
+	}
+	if o.Deg >= 1 {
+		for k := 0; k < o.Ndim; k++ {
+			res += o.C[1+k] * x[k]
+		}
+	}
+	return
+}
+
+// nmono returns the number of monomials
+func (o *Rbf) nmono() int {
+	switch o.Deg {
+	case 0:
+		return 1
+	case 1:
+		return 1 + o.Ndim
+	}
+	return 0
+}
+
+// monomials computes the monomials at x
+func (o *Rbf) monomials(p la.Vector, x []float64) {
+	if o.Deg >= 0 {
+		p[0] = 1
+	}
+	if o.Deg >= 1 {
+		for k := 0; k < o.Ndim; k++ {
+			p[1+k] = x[k]
+		}
+	}
+}
+
+// auxiliary /////////////////////////////////////////////////////////////////////////////////////
+
+// checkData checks the data points and returns their number and the space dimension
+func checkData(X [][]float64, y []float64) (npts, ndim int) {
+	npts = len(X)
+	if npts < 1 {
+		chk.Panic("at least one data point is required")
+	}
+	if len(y) != npts {
+		chk.Panic("the number of values (%d) must be equal to the number of points (%d)", len(y), npts)
+	}
+	ndim = len(X[0])
+	if ndim < 1 {
+		chk.Panic("the space dimension must be at least 1")
+	}
+	for i := 1; i < npts; i++ {
+		if len(X[i]) != ndim {
+			chk.Panic("all points must have the same dimension. len(X[%d]) = %d is invalid", i, len(X[i]))
+		}
+	}
+	return
+}
+
+// dist returns the Euclidean distance between two points
+func dist(a, b []float64) float64 {
+	sum := 0.0
+	for k := 0; k < len(b); k++ {
+		sum += (a[k] - b[k]) * (a[k] - b[k])
+	}
+	return math.Sqrt(sum)
+}
+
+// meanNearestDist returns the mean distance of each point to its nearest neighbour
+func meanNearestDist(X [][]float64) float64 {
+	if len(X) < 2 {
+		return 1
+	}
+	sum := 0.0
+	for i := 0; i < len(X); i++ {
+		dmin := math.MaxFloat64
+		for j := 0; j < len(X); j++ {
+			if j != i {
+				dmin = math.Min(dmin, dist(X[i], X[j]))
+			}
+		}
+		sum += dmin
+	}
+	if sum == 0 {
+		return 1
+	}
+	return sum / float64(len(X))
+}
@@ -0,0 +1,237 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scatter
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/gm/tri"
+	"github.com/cpmech/gosl/la"
+)
+
+// Sibson implements Sibson's natural neighbour interpolation of scattered data in 2D
+//          nnei-1                   Aᵢ(x)
+//   f(x) =   Σ    λᵢ(x) ⋅ yᵢ   λᵢ = ─────
+//           i=0                      A(x)
+//
+//  where A(x) is the area of the Voronoi cell of x inserted into the Voronoi diagram of the data
+//  points and Aᵢ(x) is the area "stolen" from the cell of the natural neighbour i. The stolen areas
+//  are computed from the circumcentres of the triangles of the Delaunay triangulation (computed by
+//  gm/tri.Delaunay) whose circumcircles contain x (the Bowyer-Watson cavity).
+//
+//  The interpolant is C⁰ at the data points, C¹ elsewhere, reproduces linear functions exactly
+//  and reduces to linear interpolation along the convex hull. F returns NaN outside the convex hull.
+//
+//  Note: F can be used as a fun.Sv function
+//
+//   Reference:
+//   [1] Sibson R (1981) A brief description of natural neighbor interpolation. In: Barnett V (ed)
+//       Interpreting Multivariate Data. Wiley, Chichester, pp 21-36
+type Sibson struct {
+	V   [][]float64 // [nverts][2] vertices of Delaunay triangulation (= data points)
+	C   [][]int     // [ncells][3] triangles of Delaunay triangulation (counter-clockwise)
+	Y   []float64   // [nverts] data values
+	cc  [][]float64 // [ncells][2] circumcentres
+	r2  []float64   // [ncells] squared radii of circumcircles
+	ed  map[[2]int]int
+	tol float64
+}
+
+// NewSibson returns a new natural neighbour interpolator
+//  Input:
+//    X -- [npts][2] data points
+//    y -- [npts] data values
+func NewSibson(X [][]float64, y []float64) (o *Sibson) {
+
+	// check
+	npts, ndim := checkData(X, y)
+	if ndim != 2 {
+		chk.Panic("natural neighbour interpolation requires 2D points. ndim = %d is invalid", ndim)
+	}
+	if npts < 3 {
+		chk.Panic("at least 3 points are required. npts = %d is invalid", npts)
+	}
+
+	// triangulation
+	xx := make([]float64, npts)
+	yy := make([]float64, npts)
+	for i := 0; i < npts; i++ {
+		xx[i], yy[i] = X[i][0], X[i][1]
+	}
+	o = new(Sibson)
+	o.V, o.C = tri.Delaunay(xx, yy, false)
+	if len(o.V) != npts {
+		chk.Panic("Delaunay triangulation changed the number of points from %d to %d (duplicated points?)", npts, len(o.V))
+	}
+	o.Y = y
+
+	// tolerance
+	xmin, xmax := xx[0], xx[0]
+	ymin, ymax := yy[0], yy[0]
+	for i := 1; i < npts; i++ {
+		xmin, xmax = math.Min(xmin, xx[i]), math.Max(xmax, xx[i])
+		ymin, ymax = math.Min(ymin, yy[i]), math.Max(ymax, yy[i])
+	}
+	o.tol = 1e-12 * math.Max(xmax-xmin, ymax-ymin)
+
+	// circumcircles and directed edges
+	ncells := len(o.C)
+	o.cc = make([][]float64, ncells)
+	o.r2 = make([]float64, ncells)
+	o.ed = make(map[[2]int]int)
+	for t, c := range o.C {
+		a, b, d := o.V[c[0]], o.V[c[1]], o.V[c[2]]
+		if cross(a, b, d) < 0 {
+			c[1], c[2] = c[2], c[1]
+		}
+		o.cc[t] = circumcentre(o.V[c[0]], o.V[c[1]], o.V[c[2]])
+		o.r2[t] = dist2(o.cc[t], o.V[c[0]])
+		for k := 0; k < 3; k++ {
+			o.ed[[2]int{c[k], c[(k+1)%3]}] = t
+		}
+	}
+	return
+}
+
+// F evaluates the interpolant at x. Returns NaN if x is outside the convex hull
+func (o *Sibson) F(x la.Vector) float64 {
+	ids, λ := o.Weights(x)
+	if ids == nil {
+		return math.NaN()
+	}
+	res := 0.0
+	for k, i := range ids {
+		res += λ[k] * o.Y[i]
+	}
+	return res
+}
+
+// Weights computes the natural neighbours of x and the Sibson coordinates
+//  Output:
+//    ids -- indices of the natural neighbours (vertices); nil if x is outside the convex hull
+//    λ   -- [len(ids)] Sibson coordinates (∑λ = 1 and ∑λ⋅xᵢ = x)
+func (o *Sibson) Weights(x la.Vector) (ids []int, λ []float64) {
+
+	// Bowyer-Watson cavity: triangles whose circumcircles contain x
+	p := []float64{x[0], x[1]}
+	cavity := make(map[int]bool)
+	inside := false
+	for t, c := range o.C {
+		if dist2(p, o.cc[t]) > o.r2[t]*(1.0+1e-10) {
+			continue
+		}
+		cavity[t] = true
+		if !inside && o.contains(c, p) {
+			inside = true
+		}
+	}
+	if !inside {
+		return
+	}
+
+	// coincident vertex
+	for t := range cavity {
+		for _, i := range o.C[t] {
+			if dist2(p, o.V[i]) <= o.tol*o.tol {
+				return []int{i}, []float64{1}
+			}
+		}
+	}
+
+	// boundary of cavity (counter-clockwise): next[a] = b for boundary edge a → b
+	next := make(map[int]int)
+	prev := make(map[int]int)
+	for t := range cavity {
+		c := o.C[t]
+		for k := 0; k < 3; k++ {
+			a, b := c[k], c[(k+1)%3]
+			if u, ok := o.ed[[2]int{b, a}]; ok && cavity[u] {
+				continue // interior edge
+			}
+			// x on boundary edge (convex hull) ⇒ linear interpolation
+			if math.Abs(cross(o.V[a], o.V[b], p)) <= o.tol*math.Sqrt(dist2(o.V[a], o.V[b])) {
+				s := math.Sqrt(dist2(o.V[a], p) / dist2(o.V[a], o.V[b]))
+				return []int{a, b}, []float64{1 - s, s}
+			}
+			next[a] = b
+			prev[b] = a
+		}
+	}
+
+	// stolen areas: polygon with vertices at the circumcentres of (x, v, v⁺), the cavity triangles
+	// around v (counter-clockwise), and (x, v⁻, v)
+	ids = make([]int, 0, len(next))
+	λ = make([]float64, 0, len(next))
+	total := 0.0
+	for v, vnext := range next {
+		poly := [][]float64{circumcentre(p, o.V[v], o.V[vnext])}
+		w := vnext
+		for {
+			t := o.ed[[2]int{v, w}]
+			poly = append(poly, o.cc[t])
+			c := o.C[t]
+			k := 0
+			for c[k] != v {
+				k++
+			}
+			w = c[(k+2)%3]
+			if w == prev[v] {
+				break
+			}
+		}
+		poly = append(poly, circumcentre(p, o.V[prev[v]], o.V[v]))
+		area := polyArea(poly)
+		ids = append(ids, v)
+		λ = append(λ, area)
+		total += area
+	}
+	for k := 0; k < len(λ); k++ {
+		λ[k] /= total
+	}
+	return
+}
+
+// contains checks whether triangle c contains p (with tolerance)
+func (o *Sibson) contains(c []int, p []float64) bool {
+	for k := 0; k < 3; k++ {
+		a, b := o.V[c[k]], o.V[c[(k+1)%3]]
+		if cross(a, b, p) < -o.tol*math.Sqrt(dist2(a, b)) {
+			return false
+		}
+	}
+	return true
+}
+
+// auxiliary /////////////////////////////////////////////////////////////////////////////////////
+
+// cross returns (b - a) × (p - a); positive if p is to the left of a → b
+func cross(a, b, p []float64) float64 {
+	return (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+}
+
+// dist2 returns the squared distance between 2D points
+func dist2(a, b []float64) float64 {
+	return (a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1])
+}
+
+// circumcentre returns the centre of the circle through a, b and c
+func circumcentre(a, b, c []float64) []float64 {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2.0 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	return []float64{a[0] + (cy*b2-by*c2)/d, a[1] + (bx*c2-cx*b2)/d}
+}
+
+// polyArea returns the area of a polygon (positive if counter-clockwise)
+func polyArea(P [][]float64) (area float64) {
+	n := len(P)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		area += P[i][0]*P[j][1] - P[j][0]*P[i][1]
+	}
+	return area / 2.0
+}
@@ -0,0 +1,85 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scatter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestKriging01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Kriging01. exact interpolation and variance")
+
+	X := scattered(25, 2, false)
+	y := make([]float64, len(X))
+	for i, x := range X {
+		y[i] = math.Sin(3*x[0]) + x[1]
+	}
+	for _, model := range []string{"sph", "exp", "gau"} {
+		o := NewKriging(model, X, y, []float64{0, 1, 0.8})
+		for i, x := range X {
+			chk.Float64(tst, io.Sf("%s: f(x%d)", model, i), 1e-8, o.F(x), y[i])
+			chk.Float64(tst, io.Sf("%s: σ²(x%d)", model, i), 1e-8, o.Variance(x), 0)
+		}
+		σ2 := o.Variance(la.Vector{2, 2})
+		io.Pf("%s: σ² far away = %g\n", model, σ2)
+		if σ2 <= o.Variance(la.Vector{0.5, 0.5}) {
+			tst.Errorf("%s: variance far from data must be larger\n", model)
+		}
+	}
+
+	// constant data
+	o := NewKriging("exp", X[:5], []float64{3, 3, 3, 3, 3}, []float64{0.1, 1, 0.5})
+	chk.Float64(tst, "constant", 1e-12, o.F(la.Vector{0.4, 0.1}), 3)
+}
+
+func TestKriging02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Kriging02. variogram fitting")
+
+	// fit to exact model values
+	h := make([]float64, 12)
+	γ := make([]float64, 12)
+	for k := 0; k < len(h); k++ {
+		h[k] = 0.1 * float64(k+1)
+		γ[k] = variogram("sph", h[k], 0.2, 1.5, 0.7)
+	}
+	nugget, sill, rng := FitVariogram("sph", h, γ, nil)
+	io.Pf("nugget = %g  sill = %g  range = %g\n", nugget, sill, rng)
+	chk.Float64(tst, "nugget", 1e-6, nugget, 0.2)
+	chk.Float64(tst, "sill", 1e-6, sill, 1.5)
+	chk.Float64(tst, "range", 1e-6, rng, 0.7)
+
+	// empirical variogram of smooth field and automatic fitting
+	f := func(x la.Vector) float64 { return math.Sin(4*x[0]) * math.Cos(3*x[1]) }
+	X := scattered(120, 2, false)
+	y := make([]float64, len(X))
+	for i, x := range X {
+		y[i] = f(x)
+	}
+	hh, γγ, count := EmpiricalVariogram(X, y, 10, 0)
+	io.Pf("h = %.3f\nγ = %.3f\ncount = %v\n", hh, γγ, count)
+	for k := 1; k < 6; k++ {
+		if γγ[k] < γγ[k-1] {
+			tst.Errorf("empirical variogram of smooth field should increase for small lags\n")
+		}
+	}
+	o := NewKriging("gau", X, y, nil)
+	io.Pf("nugget = %g  sill = %g  range = %g\n", o.Nugget, o.Sill, o.Range)
+	var g gm.Grid
+	g.RectGenUniform([]float64{0.2, 0.2}, []float64{0.8, 0.8}, []int{4, 4})
+	v := EvalGrid(&g, o.F)
+	for I := 0; I < g.Size(); I++ {
+		chk.Float64(tst, "f", 0.05, v[I], f(g.Node(I)))
+	}
+}
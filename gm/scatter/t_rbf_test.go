@@ -0,0 +1,99 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scatter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// scattered returns n quasi-random points in [0,1]ⁿᵈⁱᵐ (additive recurrence) plus the corners
+// of the unit square if corners == true
+func scattered(n, ndim int, corners bool) (X [][]float64) {
+	α := []float64{0.7548776662466927, 0.5698402909980532}
+	if ndim == 3 {
+		α = []float64{0.8191725133961645, 0.6710436067037893, 0.5497004779019703}
+	}
+	if corners {
+		X = [][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	}
+	for i := 1; i <= n; i++ {
+		x := make([]float64, ndim)
+		for k := 0; k < ndim; k++ {
+			x[k] = math.Mod(0.5+float64(i)*α[k], 1)
+		}
+		X = append(X, x)
+	}
+	return
+}
+
+func TestRbf01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rbf01. reproduction of data and linear functions")
+
+	lin := func(x []float64) float64 { return 1 + 2*x[0] - 3*x[1] }
+	X := scattered(30, 2, false)
+	y := make([]float64, len(X))
+	for i, x := range X {
+		y[i] = lin(x)
+	}
+	xt := la.Vector{0.33, 0.71}
+	for _, kind := range []string{"tps", "mq", "gauss"} {
+		o := NewRbf(kind, X, y, 3, 1)
+		for i, x := range X {
+			chk.Float64(tst, io.Sf("%s: f(x%d)", kind, i), 1e-9, o.F(x), y[i])
+		}
+		chk.Float64(tst, kind+": linear", 1e-9, o.F(xt), lin(xt))
+		io.Pf("%s: W = %.3e  C = %v\n", kind, o.W.Norm(), o.C)
+	}
+}
+
+func TestRbf02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Rbf02. approximation in 2D and 3D and grid evaluation")
+
+	f2 := func(x la.Vector) float64 { return math.Sin(2*x[0]) * math.Cos(x[1]) }
+	f3 := func(x la.Vector) float64 { return math.Exp(-x[0]) + x[1]*x[2] }
+	for _, ndim := range []int{2, 3} {
+		f, tol := f2, 1e-3
+		if ndim == 3 {
+			f, tol = f3, 2e-2 // sparser data
+		}
+		X := scattered(150, ndim, false)
+		y := make([]float64, len(X))
+		for i, x := range X {
+			y[i] = f(x)
+		}
+		var g gm.Grid
+		xmin, xmax, npts := []float64{0.2, 0.2}, []float64{0.8, 0.8}, []int{5, 4}
+		if ndim == 3 {
+			xmin, xmax, npts = append(xmin, 0.2), append(xmax, 0.8), append(npts, 3)
+		}
+		g.RectGenUniform(xmin, xmax, npts)
+		for _, kind := range []string{"tps", "mq", "gauss"} {
+			eps, deg := 0.0, 1
+			if kind == "gauss" {
+				eps, deg = 3, -1
+			}
+			o := NewRbf(kind, X, y, eps, deg)
+			v := EvalGrid(&g, o.F)
+			emax := 0.0
+			for I := 0; I < g.Size(); I++ {
+				emax = math.Max(emax, math.Abs(v[I]-f(g.Node(I))))
+			}
+			io.Pf("%dD %5s: ε = %6.3f  max error = %.3e\n", ndim, kind, o.Eps, emax)
+			if emax > tol {
+				tst.Errorf("%dD %s: max error = %g is too large\n", ndim, kind, emax)
+			}
+		}
+	}
+}
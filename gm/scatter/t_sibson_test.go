@@ -0,0 +1,87 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scatter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestSibson01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Sibson01. simple cases")
+
+	// square with centre point
+	X := [][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0.5, 0.5}}
+	y := []float64{1, 2, 3, 4, 5}
+	o := NewSibson(X, y)
+
+	// at vertices
+	for i, x := range X {
+		chk.Float64(tst, io.Sf("f(x%d)", i), 1e-15, o.F(x), y[i])
+	}
+
+	// on convex hull
+	chk.Float64(tst, "f(0.25,0)", 1e-15, o.F(la.Vector{0.25, 0}), 1.25)
+	chk.Float64(tst, "f(1,0.5)", 1e-15, o.F(la.Vector{1, 0.5}), 2.5)
+
+	// outside
+	if !math.IsNaN(o.F(la.Vector{1.1, 0.5})) {
+		tst.Errorf("f outside convex hull should be NaN\n")
+	}
+
+	// four corners of a square: symmetric weights
+	o = NewSibson(X[:4], y[:4])
+	ids, λ := o.Weights(la.Vector{0.5, 0.5})
+	io.Pf("ids = %v  λ = %v\n", ids, λ)
+	chk.Int(tst, "number of neighbours", len(ids), 4)
+	chk.Array(tst, "λ", 1e-14, λ, []float64{0.25, 0.25, 0.25, 0.25})
+}
+
+func TestSibson02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Sibson02. linear precision and grid evaluation")
+
+	lin := func(x la.Vector) float64 { return 1 + 2*x[0] - 3*x[1] }
+	X := scattered(60, 2, true)
+	y := make([]float64, len(X))
+	for i, x := range X {
+		y[i] = lin(x)
+	}
+	o := NewSibson(X, y)
+
+	// weights: positive, partition of unity and linear precision
+	var g gm.Grid
+	g.RectGenUniform([]float64{0, 0}, []float64{1, 1}, []int{11, 11})
+	for I := 0; I < g.Size(); I++ {
+		x := g.Node(I)
+		ids, λ := o.Weights(x)
+		sum, xr := 0.0, []float64{0, 0}
+		for k, i := range ids {
+			if λ[k] < 0 {
+				tst.Errorf("λ must be non-negative. λ = %g\n", λ[k])
+				return
+			}
+			sum += λ[k]
+			xr[0] += λ[k] * X[i][0]
+			xr[1] += λ[k] * X[i][1]
+		}
+		chk.Float64(tst, "Σλ", 1e-13, sum, 1)
+		chk.Array(tst, "Σλx", 1e-13, xr, x)
+	}
+
+	// grid evaluation
+	v := EvalGrid(&g, o.F)
+	for I := 0; I < g.Size(); I++ {
+		chk.Float64(tst, "f", 1e-13, v[I], lin(g.Node(I)))
+	}
+}
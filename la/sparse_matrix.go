@@ -192,6 +192,12 @@ func (o *CCMatrix) ToDense() (res *Matrix) {
 	return
 }
 
+// Get returns the internal data of column-compressed matrix
+//  NOTE: the slices are not copied; thus, they must not be modified
+func (o *CCMatrix) Get() (m, n int, Ap, Ai []int, Ax []float64) {
+	return o.m, o.n, o.p, o.i, o.x
+}
+
 // Set sets column-compressed matrix directly
 func (o *CCMatrix) Set(m, n int, Ap, Ai []int, Ax []float64) {
 	if len(Ap)-1 != n {
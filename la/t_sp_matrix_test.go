@@ -116,6 +116,13 @@ func TestSpMatrix01(tst *testing.T) {
 		{0, 0, 1, 0, 0},
 		{0, 4, 2, 0, 1},
 	})
+
+	m, n, Bp, Bi, Bx := A.Get()
+	chk.Int(tst, "m", m, 5)
+	chk.Int(tst, "n", n, 5)
+	chk.Ints(tst, "Ap", Bp, Ap)
+	chk.Ints(tst, "Ai", Bi, Ai)
+	chk.Array(tst, "Ax", 1e-17, Bx, Ax)
 }
 
 func TestSpMatrix02(tst *testing.T) {
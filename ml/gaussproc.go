@@ -0,0 +1,537 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ml
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+)
+
+// GaussProc implements Gaussian process regression
+//  The model is:
+//
+//       y(x) = f(x) + ε   with   f ~ GP(0, k(x, x'))   and   ε ~ N(0, σn²)
+//
+//  where the y-data is normalised by its mean and standard deviation. The available covariance
+//  functions (kernels) are, with r² = Σ (x_i - x'_i)² / ℓ_i²:
+//
+//       "rbf":       k = σf² exp(-r²/2)
+//       "matern32":  k = σf² (1 + √3 r) exp(-√3 r)
+//       "matern52":  k = σf² (1 + √5 r + 5 r²/3) exp(-√5 r)
+//
+//  The hyperparameters σf, ℓ and σn can be computed by maximising the log marginal likelihood:
+//
+//       log p(y|X) = -½ yᵀ K⁻¹ y - ½ log|K| - n/2 log(2π)    with    K = k(X, X) + σn² I
+//
+//  Reference:
+//   Rasmussen CE, Williams CKI (2006) Gaussian processes for machine learning. MIT Press
+type GaussProc struct {
+
+	// constants
+	Kernel    string // "rbf", "matern32" or "matern52"
+	Ard       bool   // one length scale per dimension (automatic relevance determination)
+	FitNoise  bool   // fit σn as well; otherwise σn is fixed
+	Nrestarts int    // number of random restarts of the maximisation of the likelihood
+	NmaxIt    int    // max number of iterations of the maximisation of the likelihood
+
+	// hyperparameters (with respect to normalised y-data)
+	SigF float64   // σf: signal standard deviation
+	Ell  []float64 // [nell] ℓ: length scales; nell = Ndim if Ard, otherwise nell = 1
+	SigN float64   // σn: noise standard deviation
+
+	// results
+	Ndim  int     // dimension of x
+	Ndata int     // number of data points
+	LogML float64 // log marginal likelihood
+
+	// auxiliary
+	x     []la.Vector // [Ndata][Ndim] x-data
+	y     la.Vector   // [Ndata] normalised y-data
+	ymean float64     // mean of y-data
+	ystd  float64     // standard deviation of y-data
+	chol  *la.Matrix  // [Ndata][Ndata] Cholesky factor of K (lower)
+	α     la.Vector   // [Ndata] α = K⁻¹ y
+	kx    la.Vector   // [Ndata] k(x, X)
+	v     la.Vector   // [Ndata] v = L⁻¹ k(x, X)
+}
+
+// NewGaussProc returns a new Gaussian process regression model
+//  ndim   -- dimension of x
+//  kernel -- "rbf", "matern32" or "matern52"
+func NewGaussProc(ndim int, kernel string) (o *GaussProc) {
+	o = new(GaussProc)
+	o.Ndim = ndim
+	o.Kernel = kernel
+	o.Ard = true
+	o.FitNoise = false
+	o.Nrestarts = 5
+	o.NmaxIt = 200
+	o.SigF = 1
+	o.Ell = make([]float64, ndim)
+	for i := 0; i < ndim; i++ {
+		o.Ell[i] = 1
+	}
+	o.SigN = 1e-4
+	o.checkKernel()
+	return
+}
+
+// Fit sets the data and computes the factorisation of K
+//  X        -- [ndata][ndim] x-data
+//  y        -- [ndata] y-data
+//  optimize -- compute the hyperparameters by maximising the log marginal likelihood
+func (o *GaussProc) Fit(X []la.Vector, y la.Vector, optimize bool) {
+
+	// check
+	o.checkKernel()
+	if len(X) != len(y) || len(X) < 1 {
+		chk.Panic("the number of x and y data points must be equal and greater than zero. %d != %d\n", len(X), len(y))
+	}
+	o.Ndata = len(X)
+	o.x = make([]la.Vector, o.Ndata)
+	for k := 0; k < o.Ndata; k++ {
+		if len(X[k]) != o.Ndim {
+			chk.Panic("x-data point %d has wrong dimension. %d != %d\n", k, len(X[k]), o.Ndim)
+		}
+		o.x[k] = X[k].GetCopy()
+	}
+
+	// normalise y-data
+	o.ymean, o.ystd = 0, 0
+	for _, v := range y {
+		o.ymean += v
+	}
+	o.ymean /= float64(o.Ndata)
+	for _, v := range y {
+		o.ystd += (v - o.ymean) * (v - o.ymean)
+	}
+	o.ystd = math.Sqrt(o.ystd / float64(o.Ndata))
+	if o.ystd < 1e-14 {
+		o.ystd = 1
+	}
+	o.y = la.NewVector(o.Ndata)
+	for k, v := range y {
+		o.y[k] = (v - o.ymean) / o.ystd
+	}
+
+	// length scales
+	nell := 1
+	if o.Ard {
+		nell = o.Ndim
+	}
+	if len(o.Ell) != nell {
+		ell := o.Ell
+		o.Ell = make([]float64, nell)
+		for i := 0; i < nell; i++ {
+			o.Ell[i] = 1
+			if len(ell) > 0 {
+				o.Ell[i] = ell[0]
+			}
+		}
+	}
+
+	// allocate
+	o.chol = la.NewMatrix(o.Ndata, o.Ndata)
+	o.α = la.NewVector(o.Ndata)
+	o.kx = la.NewVector(o.Ndata)
+	o.v = la.NewVector(o.Ndata)
+
+	// hyperparameters
+	if optimize {
+		o.optimize()
+	}
+
+	// factorisation
+	θ := o.getParams()
+	if val, ok := o.negLogML(nil, θ); ok {
+		o.LogML = -val
+	} else {
+		chk.Panic("covariance matrix is not positive-definite; try increasing SigN\n")
+	}
+}
+
+// Predict computes the mean and standard deviation of the prediction at x
+//  Note: σ does not include the noise term; i.e. σ is the standard deviation of f(x)
+func (o *GaussProc) Predict(x la.Vector) (μ, σ float64) {
+	for k := 0; k < o.Ndata; k++ {
+		o.kx[k] = o.kernel(x, o.x[k], nil)
+	}
+	μ = la.VecDot(o.kx, o.α)
+	for i := 0; i < o.Ndata; i++ {
+		s := o.kx[i]
+		for k := 0; k < i; k++ {
+			s -= o.chol.Get(i, k) * o.v[k]
+		}
+		o.v[i] = s / o.chol.Get(i, i)
+	}
+	variance := o.SigF*o.SigF - la.VecDot(o.v, o.v)
+	σ = math.Sqrt(math.Max(variance, 0)) * o.ystd
+	μ = o.ymean + o.ystd*μ
+	return
+}
+
+// auxiliary /////////////////////////////////////////////////////////////////////////////////////
+
+// checkKernel checks the name of the kernel
+func (o *GaussProc) checkKernel() {
+	switch o.Kernel {
+	case "rbf", "matern32", "matern52":
+	default:
+		chk.Panic("kernel %q is not available. options are \"rbf\", \"matern32\" and \"matern52\"\n", o.Kernel)
+	}
+}
+
+// kernel computes k(a, b) and, if dkdl != nil, the derivatives ∂k/∂(log ℓ_i)
+func (o *GaussProc) kernel(a, b la.Vector, dkdl []float64) (k float64) {
+	r2 := 0.0
+	for i := 0; i < o.Ndim; i++ {
+		ℓ := o.Ell[0]
+		if o.Ard {
+			ℓ = o.Ell[i]
+		}
+		d := (a[i] - b[i]) / ℓ
+		r2 += d * d
+	}
+	σ2 := o.SigF * o.SigF
+	var c float64 // ∂k/∂(log ℓ_i) = c ⋅ s_i with s_i = (a_i - b_i)² / ℓ_i²
+	switch o.Kernel {
+	case "rbf":
+		k = σ2 * math.Exp(-r2/2)
+		c = k
+	case "matern32":
+		s := math.Sqrt(3 * r2)
+		e := math.Exp(-s)
+		k = σ2 * (1 + s) * e
+		c = 3 * σ2 * e
+	case "matern52":
+		s := math.Sqrt(5 * r2)
+		e := math.Exp(-s)
+		k = σ2 * (1 + s + s*s/3) * e
+		c = 5 * σ2 * (1 + s) * e / 3
+	}
+	if dkdl != nil {
+		for j := range dkdl {
+			dkdl[j] = 0
+		}
+		for i := 0; i < o.Ndim; i++ {
+			j, ℓ := 0, o.Ell[0]
+			if o.Ard {
+				j, ℓ = i, o.Ell[i]
+			}
+			d := (a[i] - b[i]) / ℓ
+			dkdl[j] += c * d * d
+		}
+	}
+	return
+}
+
+// getParams returns θ = {log σf, log ℓ_0, log ℓ_1, ..., log σn}
+func (o *GaussProc) getParams() (θ []float64) {
+	θ = make([]float64, len(o.Ell)+2)
+	θ[0] = math.Log(o.SigF)
+	for j, ℓ := range o.Ell {
+		θ[1+j] = math.Log(ℓ)
+	}
+	θ[len(θ)-1] = math.Log(o.SigN)
+	return
+}
+
+// setParams sets the hyperparameters from θ = {log σf, log ℓ_0, log ℓ_1, ..., log σn}
+func (o *GaussProc) setParams(θ []float64) {
+	o.SigF = math.Exp(θ[0])
+	for j := range o.Ell {
+		o.Ell[j] = math.Exp(θ[1+j])
+	}
+	o.SigN = math.Exp(θ[len(θ)-1])
+}
+
+// paramBounds returns the bounds of θ
+func (o *GaussProc) paramBounds() (lo, hi []float64) {
+	nθ := len(o.Ell) + 2
+	lo, hi = make([]float64, nθ), make([]float64, nθ)
+	lo[0], hi[0] = math.Log(1e-2), math.Log(1e2)
+	for j := range o.Ell {
+		span := 0.0
+		for i := 0; i < o.Ndim; i++ {
+			if o.Ard && i != j {
+				continue
+			}
+			xmin, xmax := o.x[0][i], o.x[0][i]
+			for k := 1; k < o.Ndata; k++ {
+				xmin = math.Min(xmin, o.x[k][i])
+				xmax = math.Max(xmax, o.x[k][i])
+			}
+			span = math.Max(span, xmax-xmin)
+		}
+		if span < 1e-14 {
+			span = 1
+		}
+		lo[1+j], hi[1+j] = math.Log(1e-2*span), math.Log(1e2*span)
+	}
+	lo[nθ-1], hi[nθ-1] = math.Log(1e-6), math.Log(1)
+	if !o.FitNoise {
+		lo[nθ-1], hi[nθ-1] = math.Log(o.SigN), math.Log(o.SigN)
+	}
+	return
+}
+
+// negLogML sets the hyperparameters, factorises K and computes -log p(y|X) and its gradient
+// with respect to θ (if grad != nil)
+//  ok -- false if K is not positive-definite
+func (o *GaussProc) negLogML(grad, θ []float64) (val float64, ok bool) {
+
+	// covariance matrix
+	o.setParams(θ)
+	n := o.Ndata
+	K := la.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			kij := o.kernel(o.x[i], o.x[j], nil)
+			if i == j {
+				kij += o.SigN * o.SigN
+			}
+			K.Set(i, j, kij)
+			K.Set(j, i, kij)
+		}
+	}
+
+	// Cholesky factorisation
+	for j := 0; j < n; j++ {
+		for i := j; i < n; i++ {
+			s := K.Get(i, j)
+			for k := 0; k < j; k++ {
+				s -= o.chol.Get(i, k) * o.chol.Get(j, k)
+			}
+			if i == j {
+				if s <= 0 {
+					return 0, false
+				}
+				o.chol.Set(i, i, math.Sqrt(s))
+			} else {
+				o.chol.Set(i, j, s/o.chol.Get(j, j))
+			}
+		}
+	}
+
+	// α = K⁻¹ y
+	o.cholSolve(o.α, o.y)
+
+	// -log p(y|X)
+	val = 0.5*la.VecDot(o.y, o.α) + 0.5*float64(n)*math.Log(2*math.Pi)
+	for i := 0; i < n; i++ {
+		val += math.Log(o.chol.Get(i, i))
+	}
+	if grad == nil {
+		return val, true
+	}
+
+	// W = K⁻¹ - α αᵀ
+	W := la.NewMatrix(n, n)
+	e := la.NewVector(n)
+	col := la.NewVector(n)
+	for j := 0; j < n; j++ {
+		e.Fill(0)
+		e[j] = 1
+		o.cholSolve(col, e)
+		for i := 0; i < n; i++ {
+			W.Set(i, j, col[i]-o.α[i]*o.α[j])
+		}
+	}
+
+	// gradient: ∂(-log p)/∂θ_j = ½ tr(W ∂K/∂θ_j)
+	for j := range grad {
+		grad[j] = 0
+	}
+	nell := len(o.Ell)
+	dkdl := make([]float64, nell)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			f := 1.0
+			if i != j {
+				f = 2.0 // symmetry
+			}
+			kij := o.kernel(o.x[i], o.x[j], dkdl)
+			wij := 0.5 * f * W.Get(i, j)
+			grad[0] += wij * 2 * kij
+			for l := 0; l < nell; l++ {
+				grad[1+l] += wij * dkdl[l]
+			}
+			if i == j {
+				grad[nell+1] += wij * 2 * o.SigN * o.SigN
+			}
+		}
+	}
+	return val, true
+}
+
+// cholSolve solves L Lᵀ x = b where L is the Cholesky factor of K
+func (o *GaussProc) cholSolve(x, b la.Vector) {
+	n := o.Ndata
+	for i := 0; i < n; i++ {
+		s := b[i]
+		for k := 0; k < i; k++ {
+			s -= o.chol.Get(i, k) * x[k]
+		}
+		x[i] = s / o.chol.Get(i, i)
+	}
+	for i := n - 1; i >= 0; i-- {
+		s := x[i]
+		for k := i + 1; k < n; k++ {
+			s -= o.chol.Get(k, i) * x[k]
+		}
+		x[i] = s / o.chol.Get(i, i)
+	}
+}
+
+// optimize maximises the log marginal likelihood using the projected BFGS method with restarts
+func (o *GaussProc) optimize() {
+	lo, hi := o.paramBounds()
+	nθ := len(lo)
+	θbest := o.getParams()
+	for j := 0; j < nθ; j++ {
+		θbest[j] = math.Max(lo[j], math.Min(hi[j], θbest[j]))
+	}
+	fbest, ok := o.negLogML(nil, θbest)
+	if !ok {
+		fbest = math.Inf(+1)
+	}
+	θ := make([]float64, nθ)
+	for r := 0; r <= o.Nrestarts; r++ {
+		if r == 0 {
+			copy(θ, θbest)
+		} else {
+			for j := 0; j < nθ; j++ {
+				θ[j] = rnd.Float64(lo[j], hi[j])
+			}
+		}
+		f := o.bfgs(θ, lo, hi)
+		if f < fbest {
+			fbest = f
+			copy(θbest, θ)
+		}
+	}
+	o.setParams(θbest)
+}
+
+// bfgs minimises -log p(y|X) with the projected BFGS method and backtracking line search
+//  Input:
+//   θ -- initial point
+//  Output:
+//   θ -- local minimum
+//   f -- value at θ
+func (o *GaussProc) bfgs(θ, lo, hi []float64) (f float64) {
+
+	// initial point
+	n := len(θ)
+	g := make([]float64, n)
+	f, ok := o.negLogML(g, θ)
+	if !ok {
+		return math.Inf(+1)
+	}
+
+	// inverse Hessian approximation
+	H := la.NewMatrix(n, n)
+	H.SetDiag(1)
+	d := la.NewVector(n)
+	θnew := la.NewVector(n)
+	gnew := la.NewVector(n)
+	s := la.NewVector(n)
+	y := la.NewVector(n)
+	Hy := la.NewVector(n)
+	for it := 0; it < o.NmaxIt; it++ {
+
+		// projected gradient; variables at bounds with gradient pointing outwards are fixed
+		free := make([]bool, n)
+		pgnorm := 0.0
+		for j := 0; j < n; j++ {
+			free[j] = !((θ[j] <= lo[j] && g[j] > 0) || (θ[j] >= hi[j] && g[j] < 0))
+			if free[j] {
+				pgnorm = math.Max(pgnorm, math.Abs(g[j]))
+			}
+		}
+		if pgnorm < 1e-6 {
+			break
+		}
+
+		// search direction
+		for i := 0; i < n; i++ {
+			d[i] = 0
+			if !free[i] {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if free[j] {
+					d[i] -= H.Get(i, j) * g[j]
+				}
+			}
+		}
+		slope := 0.0
+		for j := 0; j < n; j++ {
+			slope += g[j] * d[j]
+		}
+		if slope >= 0 { // reset
+			H.SetDiag(1)
+			for j := 0; j < n; j++ {
+				d[j] = 0
+				if free[j] {
+					d[j] = -g[j]
+				}
+			}
+		}
+
+		// backtracking line search with projection
+		step := 1.0
+		accepted := false
+		var fnew float64
+		for k := 0; k < 30; k++ {
+			for j := 0; j < n; j++ {
+				θnew[j] = math.Max(lo[j], math.Min(hi[j], θ[j]+step*d[j]))
+			}
+			dec := 0.0
+			for j := 0; j < n; j++ {
+				dec += g[j] * (θnew[j] - θ[j])
+			}
+			fnew, ok = o.negLogML(gnew, θnew)
+			if ok && fnew <= f+1e-4*dec {
+				accepted = true
+				break
+			}
+			step /= 2
+		}
+		if !accepted {
+			break
+		}
+
+		// BFGS update of inverse Hessian
+		for j := 0; j < n; j++ {
+			s[j] = θnew[j] - θ[j]
+			y[j] = gnew[j] - g[j]
+		}
+		sy := la.VecDot(s, y)
+		if sy > 1e-12 {
+			la.MatVecMul(Hy, 1, H, y)
+			yHy := la.VecDot(y, Hy)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					hij := H.Get(i, j) + (sy+yHy)*s[i]*s[j]/(sy*sy) - (Hy[i]*s[j]+s[i]*Hy[j])/sy
+					H.Set(i, j, hij)
+				}
+			}
+		}
+
+		// update
+		converged := math.Abs(f-fnew) < 1e-10*(1+math.Abs(f))
+		copy(θ, θnew)
+		copy(g, gnew)
+		f = fnew
+		if converged {
+			break
+		}
+	}
+	return
+}
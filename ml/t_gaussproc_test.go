@@ -0,0 +1,140 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ml
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+)
+
+func TestGaussProc01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussProc01. gradient of log marginal likelihood")
+
+	// data
+	rnd.Init(1234)
+	X := make([]la.Vector, 12)
+	y := la.NewVector(12)
+	for k := 0; k < len(X); k++ {
+		X[k] = []float64{rnd.Float64(0, 2), rnd.Float64(-1, 1)}
+		y[k] = math.Sin(3*X[k][0]) + X[k][1]*X[k][1]
+	}
+
+	// check derivatives
+	for _, kernel := range []string{"rbf", "matern32", "matern52"} {
+		for _, ard := range []bool{true, false} {
+			gp := NewGaussProc(2, kernel)
+			gp.Ard = ard
+			gp.FitNoise = true
+			gp.SigN = 0.1
+			gp.Fit(X, y, false)
+			θ := gp.getParams()
+			for j := range θ {
+				θ[j] += 0.1 * float64(j+1)
+			}
+			grad := make([]float64, len(θ))
+			gp.negLogML(grad, θ)
+			for j := range θ {
+				h := 1e-6
+				θj := θ[j]
+				θ[j] = θj + h
+				fp, _ := gp.negLogML(nil, θ)
+				θ[j] = θj - h
+				fm, _ := gp.negLogML(nil, θ)
+				θ[j] = θj
+				chk.AnaNum(tst, io.Sf("%s(ard=%v): ∂/∂θ%d", kernel, ard, j), 1e-7, grad[j], (fp-fm)/(2*h), chk.Verbose)
+			}
+		}
+	}
+}
+
+func TestGaussProc02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussProc02. interpolation and hyperparameters")
+
+	// data
+	f := func(x float64) float64 { return math.Sin(2*x) + 0.5*x }
+	n := 10
+	X := make([]la.Vector, n)
+	y := la.NewVector(n)
+	for k := 0; k < n; k++ {
+		X[k] = []float64{3 * float64(k) / float64(n-1)}
+		y[k] = f(X[k][0])
+	}
+
+	// fit model
+	for _, kernel := range []string{"rbf", "matern32", "matern52"} {
+		rnd.Init(1234)
+		gp := NewGaussProc(1, kernel)
+		gp.Fit(X, y, false)
+		lml0 := gp.LogML
+		gp.Fit(X, y, true)
+		io.Pforan("%s: σf = %g  ℓ = %v  σn = %g  log(ML) = %g (before = %g)\n", kernel, gp.SigF, gp.Ell, gp.SigN, gp.LogML, lml0)
+		if gp.LogML < lml0 {
+			tst.Errorf("log marginal likelihood must increase after fitting\n")
+		}
+
+		// data points are interpolated
+		for k := 0; k < n; k++ {
+			μ, σ := gp.Predict(X[k])
+			chk.Float64(tst, io.Sf("%s: μ(x%d)", kernel, k), 1e-3, μ, y[k])
+			if σ > 1e-2 {
+				tst.Errorf("%s: σ(x%d) = %g is too large\n", kernel, k, σ)
+			}
+		}
+
+		// points in between
+		tol := map[string]float64{"rbf": 1e-3, "matern32": 5e-2, "matern52": 1e-2}
+		for _, x := range []float64{0.1, 0.75, 1.4, 2.2, 2.9} {
+			μ, σ := gp.Predict([]float64{x})
+			io.Pf("x = %4.2f  μ = %10.6f  f = %10.6f  σ = %g\n", x, μ, f(x), σ)
+			chk.Float64(tst, io.Sf("%s: μ(%g)", kernel, x), tol[kernel], μ, f(x))
+		}
+
+		// uncertainty grows away from data
+		_, σ1 := gp.Predict([]float64{3.5})
+		_, σ2 := gp.Predict([]float64{5.0})
+		if σ2 <= σ1 {
+			tst.Errorf("%s: σ should increase away from data: %g ≤ %g\n", kernel, σ2, σ1)
+		}
+	}
+}
+
+func TestGaussProc03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("GaussProc03. noisy data and automatic relevance determination")
+
+	// data: y depends on x0 only
+	rnd.Init(1234)
+	n := 40
+	X := make([]la.Vector, n)
+	y := la.NewVector(n)
+	for k := 0; k < n; k++ {
+		X[k] = []float64{rnd.Float64(0, 1), rnd.Float64(0, 1)}
+		y[k] = math.Sin(4*X[k][0]) + rnd.Normal(0, 0.05)
+	}
+
+	// fit
+	gp := NewGaussProc(2, "rbf")
+	gp.FitNoise = true
+	gp.Fit(X, y, true)
+	io.Pforan("σf = %g  ℓ = %v  σn = %g (σn⋅std(y) = %g)\n", gp.SigF, gp.Ell, gp.SigN, gp.SigN*gp.ystd)
+
+	// x1 is irrelevant ⇒ large length scale
+	if gp.Ell[1] < 5*gp.Ell[0] {
+		tst.Errorf("length scale of irrelevant feature should be large: ℓ = %v\n", gp.Ell)
+	}
+
+	// noise level
+	chk.Float64(tst, "σn", 0.03, gp.SigN*gp.ystd, 0.05)
+}
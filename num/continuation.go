@@ -0,0 +1,478 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/utl"
+)
+
+// ContPoint holds a special point found along a solution branch
+type ContPoint struct {
+	Kind   string    // "turning" (limit point w.r.t λ) or "bifurcation"
+	Step   int       // index of the branch point (row of Table) just after the special point
+	S      float64   // arclength at the special point
+	Lambda float64   // parameter λ at the special point
+	X      la.Vector // state x at the special point
+}
+
+// Continuation implements the pseudo-arclength continuation method to trace solution branches of
+// parameterised nonlinear systems
+//
+//   F(x, λ) = 0    with   x ∈ Rⁿ  and  λ ∈ R
+//
+//  Each step consists of an Euler predictor along the unit tangent t of the branch followed by
+//  Newton's corrector on the extended system
+//
+//   F(x, λ) = 0
+//   tᵀ ⋅ (y - yPred) = 0     with   y = {x, λ}
+//
+//  The step size Δs is adapted according to the number of corrector iterations. Turning points
+//  (folds) are detected by sign changes of the λ-component of the tangent and bifurcation points
+//  are detected by sign changes of the determinant of the extended Jacobian [Fx Fλ; tᵀ]. The
+//  special points are then located by the regula falsi method on the arclength.
+//   References:
+//    [1] Allgower EL and Georg K (2003) Introduction to Numerical Continuation Methods. SIAM
+//    [2] Seydel R (2010) Practical Bifurcation and Stability Analysis. 3rd Edition, Springer
+type Continuation struct {
+
+	// constants
+	Ds       float64 // initial step size Δs
+	DsMin    float64 // minimum step size
+	DsMax    float64 // maximum step size
+	MaxSteps int     // maximum number of continuation steps
+	MaxIt    int     // maximum number of corrector iterations
+	Nopt     int     // desired number of corrector iterations (step size control)
+	Tol      float64 // tolerance for the corrector: max|F| < Tol and max|δy| < Tol
+	LamMin   float64 // stop if λ < LamMin
+	LamMax   float64 // stop if λ > LamMax
+	Dir      float64 // initial direction of λ: +1 (increasing) or -1 (decreasing)
+
+	// callbacks
+	Ffcn  fun.Vvs // F(x,λ)
+	JxFcn fun.Mvs // Fx = dF/dx (optional; computed by finite differences if nil)
+	JlFcn fun.Vvs // Fλ = dF/dλ (optional; computed by finite differences if nil)
+
+	// results
+	Nsteps int                  // number of computed steps
+	Keys   []string             // keys of table: "s", "lambda", "x0", "x1", ..., "tlam", "det", "nit"
+	Table  map[string][]float64 // traced branch. one row per point (see Keys)
+	Points []*ContPoint         // special points (turning and bifurcation points)
+
+	// auxiliary
+	neq int        // number of equations n
+	A   *la.Matrix // [n+1][n+1] extended Jacobian
+	Fx  *la.Matrix // [n][n] Jacobian dF/dx
+	Fl  la.Vector  // [n] dF/dλ
+	f   la.Vector  // [n] F(x,λ)
+	w   la.Vector  // [n] workspace
+	rhs la.Vector  // [n+1] right-hand side
+	dy  la.Vector  // [n+1] correction
+}
+
+// Init initialises Continuation
+//  Input:
+//   neq   -- number of equations n (length of x)
+//   Ffcn  -- F(x,λ)
+//   JxFcn -- dF/dx; may be nil (numerical)
+//   JlFcn -- dF/dλ; may be nil (numerical)
+//   prms  -- ds, dsMin, dsMax, maxSteps, maxIt, nopt, tol, lamMin, lamMax, dir
+func (o *Continuation) Init(neq int, Ffcn fun.Vvs, JxFcn fun.Mvs, JlFcn fun.Vvs, prms map[string]float64) {
+
+	// check
+	if neq < 1 || Ffcn == nil {
+		chk.Panic("number of equations must be positive and Ffcn must be given. neq = %d is invalid", neq)
+	}
+
+	// set default values
+	o.Ds = 0.1
+	o.DsMin = 1e-6
+	o.DsMax = 0.5
+	o.MaxSteps = 200
+	o.MaxIt = 10
+	o.Nopt = 4
+	o.Tol = 1e-10
+	o.LamMin = math.Inf(-1)
+	o.LamMax = math.Inf(+1)
+	o.Dir = +1
+
+	// read parameters
+	for k, v := range prms {
+		switch k {
+		case "ds":
+			o.Ds = v
+		case "dsMin":
+			o.DsMin = v
+		case "dsMax":
+			o.DsMax = v
+		case "maxSteps":
+			o.MaxSteps = int(v)
+		case "maxIt":
+			o.MaxIt = int(v)
+		case "nopt":
+			o.Nopt = int(v)
+		case "tol":
+			o.Tol = v
+		case "lamMin":
+			o.LamMin = v
+		case "lamMax":
+			o.LamMax = v
+		case "dir":
+			o.Dir = v
+		}
+	}
+
+	// callbacks
+	o.Ffcn, o.JxFcn, o.JlFcn = Ffcn, JxFcn, JlFcn
+
+	// auxiliary
+	o.neq = neq
+	o.A = la.NewMatrix(neq+1, neq+1)
+	o.Fx = la.NewMatrix(neq, neq)
+	o.Fl = la.NewVector(neq)
+	o.f = la.NewVector(neq)
+	o.w = la.NewVector(neq)
+	o.rhs = la.NewVector(neq + 1)
+	o.dy = la.NewVector(neq + 1)
+
+	// table keys
+	o.Keys = []string{"s", "lambda"}
+	for i := 0; i < neq; i++ {
+		o.Keys = append(o.Keys, io.Sf("x%d", i))
+	}
+	o.Keys = append(o.Keys, "tlam", "det", "nit")
+}
+
+// Trace traces the solution branch starting at (x0, λ0)
+//  Input:
+//   x0     -- [neq] initial state; it is corrected with λ = λ0 fixed before the continuation starts
+//   lam0   -- initial parameter λ0
+//   silent -- do not show messages
+func (o *Continuation) Trace(x0 la.Vector, lam0 float64, silent bool) {
+
+	// check
+	if o.DsMin <= 0 || o.Ds < o.DsMin || o.DsMax < o.Ds {
+		chk.Panic("step sizes must satisfy 0 < DsMin ≤ Ds ≤ DsMax. DsMin = %g, Ds = %g and DsMax = %g are invalid", o.DsMin, o.Ds, o.DsMax)
+	}
+	if o.Dir != 1 && o.Dir != -1 {
+		chk.Panic("direction of λ must be +1 or -1. Dir = %g is invalid", o.Dir)
+	}
+
+	// initial point
+	n := o.neq
+	y := la.NewVector(n + 1)
+	copy(y, x0)
+	y[n] = lam0
+	o.correctFixedLambda(y)
+
+	// initial tangent
+	t := la.NewVector(n + 1)
+	t[n] = o.Dir
+	det := o.tangent(t, y, t)
+
+	// results
+	o.Table = make(map[string][]float64)
+	o.Points = nil
+	s, nit := 0.0, 0
+	o.addRow(s, y, t, det, nit)
+
+	// message
+	if !silent {
+		io.Pf("%5s%23s%23s%23s%5s\n", "step", "s", "λ", "Δs", "nit")
+		io.Pf("%5d%23.15e%23.15e%23.15e%5d\n", 0, s, y[n], o.Ds, nit)
+	}
+
+	// continuation
+	ds := o.Ds
+	ynew := la.NewVector(n + 1)
+	tnew := la.NewVector(n + 1)
+	for o.Nsteps = 0; o.Nsteps < o.MaxSteps; {
+
+		// predictor and corrector
+		for i := 0; i <= n; i++ {
+			ynew[i] = y[i] + ds*t[i]
+		}
+		var ok bool
+		nit, ok = o.correct(ynew, t)
+		if !ok {
+			ds /= 2
+			if ds < o.DsMin {
+				chk.Panic("step size became smaller than DsMin = %g at λ = %g", o.DsMin, y[n])
+			}
+			continue
+		}
+		o.Nsteps++
+
+		// new tangent and determinant
+		detNew := o.tangent(tnew, ynew, t)
+
+		// special points
+		if t[n]*tnew[n] < 0 {
+			o.locate("turning", y, t, ynew, s, ds, t[n], tnew[n])
+		}
+		if det*detNew < 0 {
+			o.locate("bifurcation", y, t, ynew, s, ds, det, detNew)
+		}
+
+		// update
+		s += ds
+		y.Apply(1, ynew)
+		t.Apply(1, tnew)
+		det = detNew
+		o.addRow(s, y, t, det, nit)
+
+		// message
+		if !silent {
+			io.Pf("%5d%23.15e%23.15e%23.15e%5d\n", o.Nsteps, s, y[n], ds, nit)
+		}
+
+		// check limits of λ
+		if y[n] < o.LamMin || y[n] > o.LamMax {
+			break
+		}
+
+		// step size control
+		α := float64(o.Nopt) / float64(utl.Imax(nit, 1))
+		ds = math.Min(o.DsMax, ds*math.Max(0.5, math.Min(2.0, α)))
+	}
+
+	// message
+	if !silent {
+		for _, p := range o.Points {
+			io.Pfyel("%s point at step %d: λ = %g  x = %v\n", p.Kind, p.Step, p.Lambda, p.X)
+		}
+	}
+}
+
+// WriteTable writes the traced branch to a file that can be read with io.ReadTable
+func (o *Continuation) WriteTable(dirout, fn string) {
+	var b bytes.Buffer
+	for _, key := range o.Keys {
+		io.Ff(&b, "%23s", key)
+	}
+	io.Ff(&b, "\n")
+	for i := range o.Table["s"] {
+		for _, key := range o.Keys {
+			io.Ff(&b, "%23.15e", o.Table[key][i])
+		}
+		io.Ff(&b, "\n")
+	}
+	io.WriteFileD(dirout, fn, &b)
+}
+
+// addRow adds a point to the table
+func (o *Continuation) addRow(s float64, y, t la.Vector, det float64, nit int) {
+	n := o.neq
+	o.Table["s"] = append(o.Table["s"], s)
+	o.Table["lambda"] = append(o.Table["lambda"], y[n])
+	for i := 0; i < n; i++ {
+		key := o.Keys[2+i]
+		o.Table[key] = append(o.Table[key], y[i])
+	}
+	o.Table["tlam"] = append(o.Table["tlam"], t[n])
+	o.Table["det"] = append(o.Table["det"], det)
+	o.Table["nit"] = append(o.Table["nit"], float64(nit))
+}
+
+// locate locates a special point between y (at arclength s) and ynew (at s + ds) with the
+// Illinois variant of the regula falsi method applied to the indicator q(σ), σ ∈ [0, ds]
+func (o *Continuation) locate(kind string, y, t, ynew la.Vector, s, ds, q0, q1 float64) {
+
+	// linear interpolation (fallback)
+	n := o.neq
+	θ := q0 / (q0 - q1)
+	σ := θ * ds
+	ysp := la.NewVector(n + 1)
+	for i := 0; i <= n; i++ {
+		ysp[i] = y[i] + θ*(ynew[i]-y[i])
+	}
+
+	// regula falsi
+	yσ := la.NewVector(n + 1)
+	tσ := la.NewVector(n + 1)
+	σa, qa, σb, qb := 0.0, q0, ds, q1
+	side := 0
+	for it := 0; it < 2*o.MaxIt; it++ {
+		σnew := (σa*qb - σb*qa) / (qb - qa)
+		for i := 0; i <= n; i++ {
+			yσ[i] = y[i] + σnew*t[i]
+		}
+		if _, ok := o.correct(yσ, t); !ok {
+			break
+		}
+		ysp.Apply(1, yσ)
+		q := o.tangent(tσ, yσ, t)
+		if kind == "turning" {
+			q = tσ[n]
+		}
+		converged := it > 0 && math.Abs(σnew-σ) < o.Tol*(1+math.Abs(ds))
+		σ = σnew
+		if q == 0 || converged {
+			break
+		}
+		if q*qb > 0 {
+			σb, qb = σ, q
+			if side == -1 {
+				qa /= 2
+			}
+			side = -1
+		} else {
+			σa, qa = σ, q
+			if side == +1 {
+				qb /= 2
+			}
+			side = +1
+		}
+	}
+
+	// results
+	o.Points = append(o.Points, &ContPoint{
+		Kind:   kind,
+		Step:   o.Nsteps,
+		S:      s + σ,
+		Lambda: ysp[n],
+		X:      ysp[:n].GetCopy(),
+	})
+}
+
+// correct applies Newton's method to the extended system with the hyperplane tᵀ⋅(y - yPred) = 0
+//  Input:
+//   y -- predicted point yPred
+//   t -- unit tangent
+//  Output:
+//   y   -- corrected point
+//   nit -- number of iterations
+//   ok  -- converged
+func (o *Continuation) correct(y, t la.Vector) (nit int, ok bool) {
+	n := o.neq
+	for nit = 1; nit <= o.MaxIt; nit++ {
+		o.Ffcn(o.f, y[:n], y[n])
+		fMax := o.f.Largest(1.0)
+		if math.IsNaN(fMax) {
+			return
+		}
+		if fMax < o.Tol && nit > 1 {
+			return nit - 1, true
+		}
+		o.jacobians(y)
+		for j := 0; j <= n; j++ {
+			o.A.Set(n, j, t[j])
+		}
+		for i := 0; i < n; i++ {
+			o.rhs[i] = -o.f[i]
+		}
+		o.rhs[n] = 0 // the correction is orthogonal to t
+		if denSolveGauss(o.dy, o.A, o.rhs) == 0 {
+			return
+		}
+		dyMax := 0.0
+		for i := 0; i <= n; i++ {
+			y[i] += o.dy[i]
+			dyMax = math.Max(dyMax, math.Abs(o.dy[i]))
+		}
+		if dyMax < o.Tol {
+			return nit, true
+		}
+	}
+	return
+}
+
+// correctFixedLambda applies Newton's method to F(x, λ) = 0 with fixed λ
+func (o *Continuation) correctFixedLambda(y la.Vector) {
+	n := o.neq
+	dx := la.NewVector(n)
+	for it := 0; it < o.MaxIt; it++ {
+		o.Ffcn(o.f, y[:n], y[n])
+		if o.f.Largest(1.0) < o.Tol {
+			return
+		}
+		o.jacobians(y)
+		for i := 0; i < n; i++ {
+			o.w[i] = -o.f[i]
+		}
+		if denSolveGauss(dx, o.Fx, o.w) == 0 {
+			chk.Panic("Jacobian dF/dx is singular at the initial point λ = %g", y[n])
+		}
+		for i := 0; i < n; i++ {
+			y[i] += dx[i]
+		}
+	}
+	o.Ffcn(o.f, y[:n], y[n])
+	if o.f.Largest(1.0) > math.Sqrt(o.Tol) {
+		chk.Panic("cannot find initial point with λ = %g. max|F| = %g", y[n], o.f.Largest(1.0))
+	}
+}
+
+// tangent computes the unit tangent t at y with orientation given by the previous tangent tPrev
+//  [Fx Fλ; tPrevᵀ] ⋅ t = {0, 1}  followed by normalisation
+//  Output:
+//   t   -- unit tangent; equal to tPrev if the extended Jacobian is singular
+//   det -- determinant of the extended Jacobian [Fx Fλ; tᵀ]; zero if singular
+func (o *Continuation) tangent(t, y, tPrev la.Vector) (det float64) {
+	n := o.neq
+	o.jacobians(y)
+	for j := 0; j <= n; j++ {
+		o.A.Set(n, j, tPrev[j])
+	}
+	o.rhs.Fill(0)
+	o.rhs[n] = 1
+	if denSolveGauss(o.dy, o.A, o.rhs) == 0 { // singular point: keep the previous tangent
+		t.Apply(1, tPrev)
+		return 0
+	}
+	nrm := o.dy.Norm()
+	for i := 0; i <= n; i++ {
+		t[i] = o.dy[i] / nrm
+	}
+	for j := 0; j <= n; j++ {
+		o.A.Set(n, j, t[j])
+	}
+	return denSolveGauss(o.dy, o.A, o.rhs)
+}
+
+// jacobians computes Fx and Fλ at y and sets the first n rows of the extended Jacobian
+//  The last row of A is set to t by the caller
+func (o *Continuation) jacobians(y la.Vector) {
+	n := o.neq
+	x, λ := y[:n], y[n]
+	if o.JxFcn == nil || o.JlFcn == nil {
+		o.Ffcn(o.f, x, λ)
+	}
+	if o.JxFcn != nil {
+		o.JxFcn(o.Fx, x, λ)
+	} else {
+		for j := 0; j < n; j++ {
+			xj := x[j]
+			h := math.Sqrt(MACHEPS) * math.Max(1.0, math.Abs(xj))
+			x[j] = xj + h
+			o.Ffcn(o.w, x, λ)
+			x[j] = xj
+			for i := 0; i < n; i++ {
+				o.Fx.Set(i, j, (o.w[i]-o.f[i])/h)
+			}
+		}
+	}
+	if o.JlFcn != nil {
+		o.JlFcn(o.Fl, x, λ)
+	} else {
+		h := math.Sqrt(MACHEPS) * math.Max(1.0, math.Abs(λ))
+		o.Ffcn(o.w, x, λ+h)
+		for i := 0; i < n; i++ {
+			o.Fl[i] = (o.w[i] - o.f[i]) / h
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			o.A.Set(i, j, o.Fx.Get(i, j))
+		}
+		o.A.Set(i, n, o.Fl[i])
+	}
+}
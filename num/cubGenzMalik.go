@@ -0,0 +1,377 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/la"
+)
+
+// CubGenzMalik computes the integral of f over the hyperrectangle [a, b] by the adaptive cubature
+// method of Genz and Malik [1,2]
+//
+//               b[0]   b[1]       b[ndim-1]
+//   res ≈  ∫     ∫    ...   ∫           f(x) dx
+//         a[0]   a[1]       a[ndim-1]
+//
+//  Each subregion is integrated by a degree-7 rule with an embedded degree-5 rule that provides
+//  the error estimate. The subregion with the largest error is bisected along the direction with
+//  the largest fourth divided difference until err ≤ max(atol, rtol⋅|res|) or the maximum number
+//  of function evaluations is reached.
+//
+//  Infinite limits (±math.Inf) are handled by the transformations
+//
+//   x = a + t/(1-t)          t ∈ [0, 1)    for [a, ∞)
+//   x = b - (1-t)/t          t ∈ (0, 1]    for (-∞, b]
+//   x = t/(1-t²)             t ∈ (-1, 1)   for (-∞, ∞)
+//
+//   Input:
+//     f       -- integrand
+//     a, b    -- [ndim] lower and upper limits
+//     atol    -- absolute tolerance
+//     rtol    -- relative tolerance
+//     maxEval -- maximum number of function evaluations
+//
+//   Output:
+//     res    -- integral
+//     err    -- estimated absolute error
+//     nfeval -- number of function evaluations
+//
+//   References:
+//   [1] Genz AC and Malik AA (1980) An adaptive algorithm for numerical integration over an
+//       n-dimensional rectangular region. Journal of Computational and Applied Mathematics,
+//       6(4):295-302
+//   [2] Berntsen J, Espelid TO and Genz A (1991) An adaptive algorithm for the approximate
+//       calculation of multiple integrals. ACM Transactions on Mathematical Software, 17(4):437-451
+func CubGenzMalik(f fun.Sv, a, b []float64, atol, rtol float64, maxEval int) (res, err float64, nfeval int) {
+	var tr cubTransform
+	tr.init(f, a, b)
+	return genzMalik(tr.g, tr.ta, tr.tb, atol, rtol, maxEval)
+}
+
+// CubSimplex computes the integral of f over the simplex (e.g. triangle or tetrahedron) with the
+// given vertices by the adaptive Genz-Malik cubature after mapping the unit hypercube onto the
+// simplex with the Duffy (collapsed coordinates) transformation
+//  Input:
+//    f       -- integrand
+//    verts   -- [ndim+1][ndim] vertices of the simplex
+//    atol    -- absolute tolerance
+//    rtol    -- relative tolerance
+//    maxEval -- maximum number of function evaluations
+//
+//  Output:
+//    res    -- integral
+//    err    -- estimated absolute error
+//    nfeval -- number of function evaluations
+func CubSimplex(f fun.Sv, verts [][]float64, atol, rtol float64, maxEval int) (res, err float64, nfeval int) {
+
+	// check
+	ndim := len(verts) - 1
+	if ndim < 1 {
+		chk.Panic("at least two vertices are required")
+	}
+	for _, v := range verts {
+		if len(v) != ndim {
+			chk.Panic("number of vertices must be equal to ndim+1 and vertices must have length ndim. %d vertices with length %d are invalid", len(verts), len(v))
+		}
+	}
+
+	// edge vectors and volume factor |det(V)|
+	V := la.NewMatrix(ndim, ndim)
+	for j := 1; j <= ndim; j++ {
+		for i := 0; i < ndim; i++ {
+			V.Set(i, j-1, verts[j][i]-verts[0][i])
+		}
+	}
+	det := math.Abs(la.MatInv(la.NewMatrix(ndim, ndim), V, true))
+	if det == 0 {
+		chk.Panic("simplex is degenerated")
+	}
+
+	// integrand on the unit hypercube
+	x := la.NewVector(ndim)
+	g := func(u la.Vector) float64 {
+		jac, rem := det, 1.0 // rem = Π (1 - u[i]) for i < k
+		copy(x, verts[0])
+		for k := 0; k < ndim; k++ {
+			s := u[k] * rem
+			for i := 0; i < ndim; i++ {
+				x[i] += s * V.Get(i, k)
+			}
+			if k < ndim-1 {
+				jac *= math.Pow(1-u[k], float64(ndim-1-k))
+			}
+			rem *= 1 - u[k]
+		}
+		return f(x) * jac
+	}
+	zero, one := make([]float64, ndim), make([]float64, ndim)
+	for i := 0; i < ndim; i++ {
+		one[i] = 1
+	}
+	return genzMalik(g, zero, one, atol, rtol, maxEval)
+}
+
+// genzMalik implements the adaptive Genz-Malik cubature over a finite hyperrectangle
+func genzMalik(f fun.Sv, a, b []float64, atol, rtol float64, maxEval int) (res, err float64, nfeval int) {
+
+	// check
+	ndim := len(a)
+	if ndim < 1 || len(b) != ndim {
+		chk.Panic("limits must have the same length ≥ 1. len(a) = %d and len(b) = %d are invalid", len(a), len(b))
+	}
+
+	// rule
+	var rule gmRule
+	rule.init(ndim)
+
+	// initial region
+	var regions gmRegions
+	r := &gmRegion{c: la.NewVector(ndim), h: la.NewVector(ndim)}
+	for i := 0; i < ndim; i++ {
+		if b[i] < a[i] {
+			chk.Panic("upper limit must not be smaller than lower limit. b[%d] = %g < a[%d] = %g", i, b[i], i, a[i])
+		}
+		r.c[i] = (a[i] + b[i]) / 2
+		r.h[i] = (b[i] - a[i]) / 2
+	}
+	rule.eval(f, r)
+	heap.Push(&regions, r)
+	res, err = r.res, r.err
+
+	// refinement
+	for nfeval = rule.npts; err > math.Max(atol, rtol*math.Abs(res)) && nfeval+2*rule.npts <= maxEval; nfeval += 2 * rule.npts {
+
+		// bisect region with largest error
+		r := heap.Pop(&regions).(*gmRegion)
+		res -= r.res
+		err -= r.err
+		d := r.dir
+		r.h[d] /= 2
+		s := &gmRegion{c: r.c.GetCopy(), h: r.h.GetCopy()}
+		r.c[d] -= r.h[d]
+		s.c[d] += r.h[d]
+		for _, q := range []*gmRegion{r, s} {
+			rule.eval(f, q)
+			heap.Push(&regions, q)
+			res += q.res
+			err += q.err
+		}
+	}
+
+	// recompute sums to reduce round-off errors
+	res, err = 0, 0
+	for _, q := range regions {
+		res += q.res
+		err += q.err
+	}
+	return
+}
+
+// gmRule holds the constants of the degree-7 Genz-Malik rule (and the embedded degree-5 rule)
+type gmRule struct {
+	ndim               int       // dimension
+	npts               int       // number of points: 1 + 4n + 2n(n-1) + 2ⁿ
+	λ2, λ3, λ4, λ5     float64   // generators
+	w1, w2, w3, w4, w5 float64   // weights of degree-7 rule
+	v1, v2, v3, v4     float64   // weights of degree-5 rule
+	x                  la.Vector // workspace
+	f2, f3             la.Vector // [ndim] workspace: f(c+λ2eᵢ)+f(c-λ2eᵢ) and f(c+λ3eᵢ)+f(c-λ3eᵢ)
+	ratio, twoN        float64   // (λ2/λ3)² and 2ⁿ
+}
+
+// init initialises the rule
+func (o *gmRule) init(ndim int) {
+	if ndim > 20 {
+		chk.Panic("Genz-Malik rule is limited to 20 dimensions. ndim = %d is invalid", ndim)
+	}
+	n := float64(ndim)
+	o.ndim = ndim
+	o.twoN = math.Pow(2, n)
+	o.npts = 1 + 4*ndim + 2*ndim*(ndim-1) + (1 << uint(ndim))
+	o.λ2 = math.Sqrt(9.0 / 70.0)
+	o.λ3 = math.Sqrt(9.0 / 10.0)
+	o.λ4 = math.Sqrt(9.0 / 10.0)
+	o.λ5 = math.Sqrt(9.0 / 19.0)
+	o.w1 = (12824.0 - 9120.0*n + 400.0*n*n) / 19683.0
+	o.w2 = 980.0 / 6561.0
+	o.w3 = (1820.0 - 400.0*n) / 19683.0
+	o.w4 = 200.0 / 19683.0
+	o.w5 = 6859.0 / 19683.0 / o.twoN
+	o.v1 = (729.0 - 950.0*n + 50.0*n*n) / 729.0
+	o.v2 = 245.0 / 486.0
+	o.v3 = (265.0 - 100.0*n) / 1458.0
+	o.v4 = 25.0 / 729.0
+	o.ratio = (o.λ2 / o.λ3) * (o.λ2 / o.λ3)
+	o.x = la.NewVector(ndim)
+	o.f2 = la.NewVector(ndim)
+	o.f3 = la.NewVector(ndim)
+}
+
+// eval evaluates the rule over region r and sets r.res, r.err and r.dir
+func (o *gmRule) eval(f fun.Sv, r *gmRegion) {
+
+	// volume
+	n := o.ndim
+	vol := 1.0
+	for i := 0; i < n; i++ {
+		vol *= 2 * r.h[i]
+	}
+
+	// center
+	x := o.x
+	copy(x, r.c)
+	f1 := f(x)
+
+	// points along axes
+	var s2, s3 float64
+	for i := 0; i < n; i++ {
+		o.f2[i], o.f3[i] = 0, 0
+		for _, sg := range []float64{-1, 1} {
+			x[i] = r.c[i] + sg*o.λ2*r.h[i]
+			o.f2[i] += f(x)
+			x[i] = r.c[i] + sg*o.λ3*r.h[i]
+			o.f3[i] += f(x)
+		}
+		x[i] = r.c[i]
+		s2 += o.f2[i]
+		s3 += o.f3[i]
+	}
+
+	// points on planes (i,j)
+	var s4 float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			for _, si := range []float64{-1, 1} {
+				for _, sj := range []float64{-1, 1} {
+					x[i] = r.c[i] + si*o.λ4*r.h[i]
+					x[j] = r.c[j] + sj*o.λ4*r.h[j]
+					s4 += f(x)
+				}
+			}
+			x[i], x[j] = r.c[i], r.c[j]
+		}
+	}
+
+	// corner points
+	var s5 float64
+	for k := 0; k < 1<<uint(n); k++ {
+		for i := 0; i < n; i++ {
+			if k&(1<<uint(i)) != 0 {
+				x[i] = r.c[i] + o.λ5*r.h[i]
+			} else {
+				x[i] = r.c[i] - o.λ5*r.h[i]
+			}
+		}
+		s5 += f(x)
+	}
+
+	// results
+	r.res = vol * (o.w1*f1 + o.w2*s2 + o.w3*s3 + o.w4*s4 + o.w5*s5)
+	r.err = math.Abs(r.res - vol*(o.v1*f1+o.v2*s2+o.v3*s3+o.v4*s4))
+
+	// direction with largest fourth divided difference
+	dmax := -1.0
+	for i := 0; i < n; i++ {
+		d := math.Abs(o.f2[i] - 2*f1 - o.ratio*(o.f3[i]-2*f1))
+		if d > dmax*(1+1e-12) { // ties: select the longest edge
+			dmax, r.dir = d, i
+		} else if d >= dmax*(1-1e-12) && r.h[i] > r.h[r.dir] {
+			r.dir = i
+		}
+	}
+}
+
+// gmRegion holds a subregion of the adaptive cubature
+type gmRegion struct {
+	c   la.Vector // center
+	h   la.Vector // half-widths
+	res float64   // integral over region
+	err float64   // estimated error
+	dir int       // direction for bisection
+}
+
+// gmRegions implements a max-heap of regions (with respect to the error)
+type gmRegions []*gmRegion
+
+func (o gmRegions) Len() int            { return len(o) }
+func (o gmRegions) Less(i, j int) bool  { return o[i].err > o[j].err }
+func (o gmRegions) Swap(i, j int)       { o[i], o[j] = o[j], o[i] }
+func (o *gmRegions) Push(x interface{}) { *o = append(*o, x.(*gmRegion)) }
+func (o *gmRegions) Pop() interface{} {
+	old := *o
+	n := len(old)
+	r := old[n-1]
+	*o = old[:n-1]
+	return r
+}
+
+// cubTransform maps a hyperrectangle with (possibly) infinite limits onto a finite one
+type cubTransform struct {
+	f      fun.Sv    // original integrand
+	a, b   []float64 // original limits
+	ta, tb []float64 // limits of transformed variables
+	x      la.Vector // workspace
+}
+
+// init initialises the transformation
+func (o *cubTransform) init(f fun.Sv, a, b []float64) {
+	if len(a) != len(b) {
+		chk.Panic("limits must have the same length. len(a) = %d and len(b) = %d are invalid", len(a), len(b))
+	}
+	o.f, o.a, o.b = f, a, b
+	n := len(a)
+	o.ta, o.tb = make([]float64, n), make([]float64, n)
+	o.x = la.NewVector(n)
+	for i := 0; i < n; i++ {
+		switch {
+		case math.IsInf(a[i], -1) && math.IsInf(b[i], +1):
+			o.ta[i], o.tb[i] = -1, 1
+		case math.IsInf(b[i], +1), math.IsInf(a[i], -1):
+			o.ta[i], o.tb[i] = 0, 1
+		case math.IsInf(a[i], 0) || math.IsInf(b[i], 0):
+			chk.Panic("limits [%g, %g] are invalid", a[i], b[i])
+		default:
+			o.ta[i], o.tb[i] = a[i], b[i]
+		}
+	}
+}
+
+// g computes the transformed integrand g(t) = f(x(t)) ⋅ |dx/dt|
+//  Note: g = 0 at infinite points
+func (o *cubTransform) g(t la.Vector) float64 {
+	jac := 1.0
+	for i, ti := range t {
+		a, b := o.a[i], o.b[i]
+		switch {
+		case math.IsInf(a, -1) && math.IsInf(b, +1):
+			d := 1 - ti*ti
+			if d <= 0 {
+				return 0
+			}
+			o.x[i] = ti / d
+			jac *= (1 + ti*ti) / (d * d)
+		case math.IsInf(b, +1):
+			if ti >= 1 {
+				return 0
+			}
+			o.x[i] = a + ti/(1-ti)
+			jac /= (1 - ti) * (1 - ti)
+		case math.IsInf(a, -1):
+			if ti <= 0 {
+				return 0
+			}
+			o.x[i] = b - (1-ti)/ti
+			jac /= ti * ti
+		default:
+			o.x[i] = ti
+		}
+	}
+	return o.f(o.x) * jac
+}
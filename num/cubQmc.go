@@ -0,0 +1,86 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+)
+
+// CubQmc computes the integral of f over the hyperrectangle [a, b] by randomised quasi-Monte Carlo
+// integration with Halton points (see rnd.HaltonPoints)
+//
+//  The Halton sequence is randomised by nshifts random shifts modulo 1 (Cranley-Patterson
+//  rotations) and the integral is the mean of the nshifts estimates. The error is estimated by the
+//  standard error of the mean. Infinite limits are handled as in CubGenzMalik.
+//
+//  NOTE: the shifts are generated with rnd.Float64; call rnd.Init(seed) for reproducible results
+//
+//   Input:
+//     f       -- integrand
+//     a, b    -- [ndim] lower and upper limits
+//     npts    -- number of Halton points (per shift)
+//     nshifts -- number of random shifts ≥ 2
+//
+//   Output:
+//     res    -- integral
+//     err    -- estimated error (standard error of the mean)
+//     nfeval -- number of function evaluations: npts ⋅ nshifts
+//
+//   Reference:
+//   [1] L'Ecuyer P and Lemieux C (2002) Recent advances in randomized quasi-Monte Carlo methods.
+//       In: Modeling Uncertainty, Springer, pp 419-474
+func CubQmc(f fun.Sv, a, b []float64, npts, nshifts int) (res, err float64, nfeval int) {
+
+	// check
+	if npts < 1 || nshifts < 2 {
+		chk.Panic("number of points must be positive and number of shifts must be at least 2. npts = %d and nshifts = %d are invalid", npts, nshifts)
+	}
+
+	// transformation and volume
+	var tr cubTransform
+	tr.init(f, a, b)
+	n := len(a)
+	vol := 1.0
+	for k := 0; k < n; k++ {
+		vol *= tr.tb[k] - tr.ta[k]
+	}
+
+	// Halton points (the first point is skipped because it is the origin)
+	H := rnd.HaltonPoints(n, npts+1)
+
+	// estimates
+	t := la.NewVector(n)
+	shift := make([]float64, n)
+	var sum, sum2 float64
+	for s := 0; s < nshifts; s++ {
+		for k := 0; k < n; k++ {
+			shift[k] = rnd.Float64(0, 1)
+		}
+		est := 0.0
+		for i := 1; i <= npts; i++ {
+			for k := 0; k < n; k++ {
+				u := H[k][i] + shift[k]
+				u -= math.Floor(u)
+				t[k] = tr.ta[k] + u*(tr.tb[k]-tr.ta[k])
+			}
+			est += tr.g(t)
+		}
+		est *= vol / float64(npts)
+		sum += est
+		sum2 += est * est
+	}
+
+	// results
+	N := float64(nshifts)
+	res = sum / N
+	err = math.Sqrt(math.Max(0, sum2/N-res*res) / (N - 1))
+	nfeval = npts * nshifts
+	return
+}
@@ -0,0 +1,149 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// SparseGridXW computes the points and weights of the Smolyak sparse grid over the hyperrectangle
+// [a, b] built on Gauss-Legendre rules (see GaussLegendreXW)
+//
+//                  q                    ⎛ n - 1 ⎞
+//   Q  =    Σ         (-1)^(q - |i|) ⋅ ⎜       ⎟ ⋅ Q(i[0]) ⊗ Q(i[1]) ⊗ ... ⊗ Q(i[n-1])
+//       |i|=q-n+1                       ⎝ q-|i| ⎠
+//
+//  where n is the dimension, q = n + level, i[k] ≥ 1 and Q(m) is the 1D Gauss-Legendre rule with
+//  2m-1 points. Polynomials of total degree 2⋅level+1 are integrated exactly. Repeated points are
+//  merged.
+//
+//   Input:
+//     a, b  -- [ndim] lower and upper limits (finite)
+//     level -- level of the sparse grid ≥ 0
+//
+//   Output:
+//     X -- [npts][ndim] points
+//     W -- [npts] weights
+//
+//   Reference:
+//   [1] Gerstner T and Griebel M (1998) Numerical integration using sparse grids. Numerical
+//       Algorithms, 18:209-232
+func SparseGridXW(a, b []float64, level int) (X [][]float64, W []float64) {
+
+	// check
+	n := len(a)
+	if n < 1 || len(b) != n || level < 0 {
+		chk.Panic("limits must have the same length ≥ 1 and level must be non-negative. len(a) = %d, len(b) = %d and level = %d are invalid", len(a), len(b), level)
+	}
+
+	// 1D rules
+	xs := make([][][]float64, n) // [ndim][level+1][2m-1]
+	ws := make([][][]float64, n)
+	for k := 0; k < n; k++ {
+		if math.IsInf(a[k], 0) || math.IsInf(b[k], 0) {
+			chk.Panic("limits must be finite. a[%d] = %g and b[%d] = %g are invalid", k, a[k], k, b[k])
+		}
+		xs[k] = make([][]float64, level+1)
+		ws[k] = make([][]float64, level+1)
+		for m := 1; m <= level+1; m++ {
+			x, w := GaussLegendreXW(a[k], b[k], 2*m-1)
+			x[m-1] = (a[k] + b[k]) / 2 // exact midpoint (merged with other rules)
+			xs[k][m-1], ws[k][m-1] = x, w
+		}
+	}
+
+	// combination technique
+	q := n + level
+	index := make(map[string]int)
+	idx := make([]int, n)
+	var recurse func(k, sum int)
+	recurse = func(k, sum int) {
+		if k == n {
+			if sum < q-n+1 {
+				return
+			}
+			c := fun.Binomial(n-1, q-sum)
+			if (q-sum)%2 == 1 {
+				c = -c
+			}
+			tensorProduct(xs, ws, idx, c, index, &X, &W)
+			return
+		}
+		for i := 1; sum+i+(n-k-1) <= q; i++ {
+			idx[k] = i
+			recurse(k+1, sum+i)
+		}
+	}
+	recurse(0, 0)
+	return
+}
+
+// CubSparseGrid computes the integral of f over the hyperrectangle [a, b] with the Smolyak sparse
+// grid of given level (see SparseGridXW). Infinite limits are handled as in CubGenzMalik
+//  Output:
+//    res    -- integral
+//    err    -- estimated error: |res - Q(level-1)|; |res| if level == 0
+//    nfeval -- number of function evaluations (including those of Q(level-1))
+func CubSparseGrid(f fun.Sv, a, b []float64, level int) (res, err float64, nfeval int) {
+	var tr cubTransform
+	tr.init(f, a, b)
+	res, nfeval = sparseGridSum(tr.g, tr.ta, tr.tb, level)
+	if level == 0 {
+		return res, math.Abs(res), nfeval
+	}
+	prev, nf := sparseGridSum(tr.g, tr.ta, tr.tb, level-1)
+	return res, math.Abs(res - prev), nfeval + nf
+}
+
+// sparseGridSum computes the sparse grid sum
+func sparseGridSum(f fun.Sv, a, b []float64, level int) (res float64, nfeval int) {
+	X, W := SparseGridXW(a, b, level)
+	for k, x := range X {
+		res += W[k] * f(x)
+	}
+	return res, len(X)
+}
+
+// tensorProduct adds the points and weights of the tensor product of 1D rules to X and W
+func tensorProduct(xs, ws [][][]float64, idx []int, c float64, index map[string]int, X *[][]float64, W *[]float64) {
+	n := len(idx)
+	pos := make([]int, n)
+	for {
+
+		// add point
+		x := la.NewVector(n)
+		w := c
+		for k := 0; k < n; k++ {
+			x[k] = xs[k][idx[k]-1][pos[k]]
+			w *= ws[k][idx[k]-1][pos[k]]
+		}
+		key := io.Sf("%v", x)
+		if i, ok := index[key]; ok {
+			(*W)[i] += w
+		} else {
+			index[key] = len(*X)
+			*X = append(*X, x)
+			*W = append(*W, w)
+		}
+
+		// next point
+		k := 0
+		for ; k < n; k++ {
+			pos[k]++
+			if pos[k] < 2*idx[k]-1 {
+				break
+			}
+			pos[k] = 0
+		}
+		if k == n {
+			return
+		}
+	}
+}
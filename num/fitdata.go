@@ -4,7 +4,12 @@
 
 package num
 
-import "math"
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
 
 // LinFit computes linear fitting parameters. Errors on y-direction only
 //
@@ -95,3 +100,143 @@ func LinFitSigma(x, y []float64) (a, b, σa, σb, χ2 float64) {
 	σb *= σdat
 	return
 }
+
+// PolyFit computes the coefficients of the least-squares polynomial of degree deg
+//
+//   y(x) = c[0] + c[1]⋅x + c[2]⋅x² + ... + c[deg]⋅x^deg
+func PolyFit(x, y []float64, deg int) (c []float64) {
+	c, _, _ = PolyFitSigma(x, y, nil, deg)
+	return
+}
+
+// PolyFitSigma computes the coefficients of the least-squares polynomial of degree deg and the
+// standard errors σc of the coefficients. Errors on y-direction only
+//
+//   y(x) = c[0] + c[1]⋅x + c[2]⋅x² + ... + c[deg]⋅x^deg
+//
+//   σ -- [ndata] standard deviations of y; may be nil (unweighted fit). In this case, σc is
+//        estimated with the typical σ computed from χ² as in LinFitSigma
+//
+//   The problem is solved by QR decomposition of the (weighted) Vandermonde matrix [1]
+//   Reference:
+//   [1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//       Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+func PolyFitSigma(x, y, σ []float64, deg int) (c, σc []float64, χ2 float64) {
+
+	// check
+	ndata := len(x)
+	if deg < 0 || ndata < deg+1 {
+		chk.Panic("number of data points must be greater than the degree of the polynomial. ndata = %d and deg = %d are invalid", ndata, deg)
+	}
+
+	// weighted Vandermonde matrix
+	A := la.NewMatrix(ndata, deg+1)
+	b := la.NewVector(ndata)
+	for i := 0; i < ndata; i++ {
+		w := 1.0
+		if σ != nil {
+			w = 1.0 / σ[i]
+		}
+		xp := 1.0
+		for j := 0; j <= deg; j++ {
+			A.Set(i, j, w*xp)
+			xp *= x[i]
+		}
+		b[i] = w * y[i]
+	}
+
+	// solve
+	var cov *la.Matrix
+	c, cov, χ2 = lsqSolveQR(A, b)
+	σc = make([]float64, deg+1)
+	σdat := 1.0
+	if σ == nil {
+		σdat = 0.0
+		if ndata > deg+1 {
+			σdat = math.Sqrt(χ2 / float64(ndata-deg-1))
+		}
+	}
+	for j := 0; j <= deg; j++ {
+		σc[j] = σdat * math.Sqrt(cov.Get(j, j))
+	}
+	return
+}
+
+// lsqSolveQR solves the linear least-squares problem min |A⋅c - b|² by Householder QR decomposition
+//  Input:
+//   A -- [m][n] matrix with m ≥ n and full rank (modified)
+//   b -- [m] right-hand side (modified)
+//  Output:
+//   c   -- [n] solution
+//   cov -- [n][n] (Aᵀ⋅A)⁻¹ = R⁻¹⋅R⁻ᵀ
+//   χ2  -- |A⋅c - b|²
+func lsqSolveQR(A *la.Matrix, b la.Vector) (c la.Vector, cov *la.Matrix, χ2 float64) {
+
+	// Householder reflections
+	m, n := A.M, A.N
+	for k := 0; k < n; k++ {
+		nrm := 0.0
+		for i := k; i < m; i++ {
+			nrm = math.Hypot(nrm, A.Get(i, k))
+		}
+		if nrm == 0 {
+			chk.Panic("least-squares matrix is rank deficient (column %d)", k)
+		}
+		if A.Get(k, k) > 0 {
+			nrm = -nrm
+		}
+		for i := k; i < m; i++ {
+			A.Set(i, k, A.Get(i, k)/nrm)
+		}
+		A.Add(k, k, -1) // v = x/α - e_k  (stored in column k)
+		vk := A.Get(k, k)
+		for j := k + 1; j < n; j++ {
+			s := 0.0
+			for i := k; i < m; i++ {
+				s += A.Get(i, k) * A.Get(i, j)
+			}
+			s /= vk
+			for i := k; i < m; i++ {
+				A.Add(i, j, s*A.Get(i, k))
+			}
+		}
+		s := 0.0
+		for i := k; i < m; i++ {
+			s += A.Get(i, k) * b[i]
+		}
+		s /= vk
+		for i := k; i < m; i++ {
+			b[i] += s * A.Get(i, k)
+		}
+		A.Set(k, k, nrm) // diagonal of R
+	}
+
+	// back substitution: R⋅c = Qᵀ⋅b
+	c = la.NewVector(n)
+	for i := n - 1; i >= 0; i-- {
+		s := b[i]
+		for j := i + 1; j < n; j++ {
+			s -= A.Get(i, j) * c[j]
+		}
+		c[i] = s / A.Get(i, i)
+	}
+	for i := n; i < m; i++ {
+		χ2 += b[i] * b[i]
+	}
+
+	// covariance: R⁻¹⋅R⁻ᵀ
+	Ri := la.NewMatrix(n, n)
+	for k := 0; k < n; k++ {
+		Ri.Set(k, k, 1.0/A.Get(k, k))
+		for i := k - 1; i >= 0; i-- {
+			s := 0.0
+			for j := i + 1; j <= k; j++ {
+				s += A.Get(i, j) * Ri.Get(j, k)
+			}
+			Ri.Set(i, k, -s/A.Get(i, i))
+		}
+	}
+	cov = la.NewMatrix(n, n)
+	la.MatMatTrMul(cov, 1, Ri, Ri)
+	return
+}
@@ -0,0 +1,434 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// LsqFit implements nonlinear least-squares fitting of models y = f(x; p) to data
+//
+//               ndata-1   ⎛ yᵢ - f(xᵢ; p) ⎞²
+//   min  χ²  =    Σ       ⎜ ───────────── ⎟      s.t.   pmin ≤ p ≤ pmax
+//    p           i=0      ⎝      σᵢ       ⎠
+//
+//  where σᵢ are the standard deviations of the data (σᵢ = 1 if not given). The problem is solved
+//  by the Levenberg-Marquardt method (Method = "lm") or by the Gauss-Newton method with
+//  backtracking line search (Method = "gn"). Bounds are handled by projection onto the box and by
+//  fixing the parameters at active bounds.
+//
+//  After fitting, the covariance matrix of the parameters is estimated by Cov = (Jᵀ⋅J)⁻¹ where
+//  J is the Jacobian of the weighted residuals. If σ is not given, Cov is scaled by χ²/(ndata-np)
+//  (as in LinFitSigma).
+//
+//   References:
+//   [1] Press WH, Teukolsky SA, Vetterling WT, Fnannery BP (2007) Numerical Recipes: The Art of
+//       Scientific Computing. Third Edition. Cambridge University Press. 1235p.
+//   [2] Madsen K, Nielsen HB and Tingleff O (2004) Methods for non-linear least squares problems.
+//       2nd Edition, Informatics and Mathematical Modelling, Technical University of Denmark
+type LsqFit struct {
+
+	// constants
+	Method string  // "lm" (Levenberg-Marquardt) or "gn" (Gauss-Newton)
+	MaxIt  int     // maximum number of iterations
+	Ftol   float64 // tolerance on the relative decrease of χ²
+	Ptol   float64 // tolerance on the relative change of parameters
+	Gtol   float64 // tolerance on the (projected) gradient of χ²
+	Mu0    float64 // Levenberg-Marquardt: initial damping μ₀ = Mu0 ⋅ max diag(JᵀJ) added to diag(JᵀJ)
+
+	// model
+	Np    int                                          // number of parameters
+	Model func(x float64, p la.Vector) float64         // model f(x; p)
+	Deriv func(dfdp la.Vector, x float64, p la.Vector) // df/dp (may be nil => finite differences)
+	Pmin  la.Vector                                    // [Np] lower bounds (may be nil)
+	Pmax  la.Vector                                    // [Np] upper bounds (may be nil)
+
+	// results
+	P       la.Vector  // [Np] parameters
+	Sigma   la.Vector  // [Np] standard errors of parameters
+	Cov     *la.Matrix // [Np][Np] covariance matrix of parameters
+	Chi2    float64    // χ²
+	Dof     int        // number of degrees of freedom: ndata - Np
+	RedChi2 float64    // reduced χ²: χ² / Dof
+	It      int        // number of iterations
+	NFeval  int        // number of evaluations of the model (at all data points)
+
+	// auxiliary
+	x, y, σ la.Vector  // data
+	r       la.Vector  // [ndata] weighted residuals (yᵢ - fᵢ) / σᵢ
+	f       la.Vector  // [ndata] model values (workspace for finite differences)
+	J       *la.Matrix // [ndata][Np] Jacobian of weighted model: dfᵢ/dpⱼ / σᵢ
+	dfdp    la.Vector  // [Np] derivatives of model
+}
+
+// Init initialises LsqFit
+//  Input:
+//   np    -- number of parameters
+//   model -- model f(x; p)
+//   deriv -- derivatives df/dp; may be nil (finite differences)
+//   prms  -- maxIt, ftol, ptol, gtol, mu0
+func (o *LsqFit) Init(np int, model func(x float64, p la.Vector) float64, deriv func(dfdp la.Vector, x float64, p la.Vector), prms map[string]float64) {
+
+	// check
+	if np < 1 || model == nil {
+		chk.Panic("number of parameters must be positive and model must be given. np = %d is invalid", np)
+	}
+
+	// set default values
+	o.Method = "lm"
+	o.MaxIt = 200
+	o.Ftol = 1e-12
+	o.Ptol = 1e-10
+	o.Gtol = 1e-12
+	o.Mu0 = 1e-3
+
+	// read parameters
+	for k, v := range prms {
+		switch k {
+		case "maxIt":
+			o.MaxIt = int(v)
+		case "ftol":
+			o.Ftol = v
+		case "ptol":
+			o.Ptol = v
+		case "gtol":
+			o.Gtol = v
+		case "mu0":
+			o.Mu0 = v
+		}
+	}
+
+	// model
+	o.Np = np
+	o.Model = model
+	o.Deriv = deriv
+	o.P = la.NewVector(np)
+	o.Sigma = la.NewVector(np)
+	o.Cov = la.NewMatrix(np, np)
+	o.dfdp = la.NewVector(np)
+}
+
+// SetBounds sets the lower and upper bounds of parameters
+//  Note: use ±math.Inf for unbounded parameters
+func (o *LsqFit) SetBounds(pmin, pmax la.Vector) {
+	if len(pmin) != o.Np || len(pmax) != o.Np {
+		chk.Panic("bounds must have length equal to Np = %d. %d and %d are invalid", o.Np, len(pmin), len(pmax))
+	}
+	for j := 0; j < o.Np; j++ {
+		if pmin[j] >= pmax[j] {
+			chk.Panic("lower bound must be smaller than upper bound. pmin[%d] = %g ≥ pmax[%d] = %g", j, pmin[j], j, pmax[j])
+		}
+	}
+	o.Pmin = pmin.GetCopy()
+	o.Pmax = pmax.GetCopy()
+}
+
+// Fit fits the model to data
+//  Input:
+//   x, y    -- [ndata] data points
+//   σ       -- [ndata] standard deviations of y; may be nil (unweighted fit)
+//   p0      -- [Np] initial values of parameters (projected onto the bounds)
+//   verbose -- show messages
+//  Output: results are stored in P, Sigma, Cov, Chi2, RedChi2, ...
+func (o *LsqFit) Fit(x, y, σ []float64, p0 la.Vector, verbose bool) {
+
+	// check
+	ndata, np := len(x), o.Np
+	if len(y) != ndata || (σ != nil && len(σ) != ndata) {
+		chk.Panic("x, y and σ must have the same length. %d, %d and %d are invalid", len(x), len(y), len(σ))
+	}
+	if ndata < np {
+		chk.Panic("number of data points must not be smaller than the number of parameters. %d < %d is invalid", ndata, np)
+	}
+	if len(p0) != np {
+		chk.Panic("p0 must have length equal to Np = %d. %d is invalid", np, len(p0))
+	}
+	if o.Method != "lm" && o.Method != "gn" {
+		chk.Panic("method %q is not available. options are \"lm\" and \"gn\"", o.Method)
+	}
+
+	// data
+	o.x, o.y = x, y
+	o.σ = la.NewVector(ndata)
+	o.σ.Fill(1)
+	if σ != nil {
+		for i := 0; i < ndata; i++ {
+			if σ[i] <= 0 {
+				chk.Panic("standard deviations must be positive. σ[%d] = %g is invalid", i, σ[i])
+			}
+			o.σ[i] = σ[i]
+		}
+	}
+	o.r = la.NewVector(ndata)
+	o.f = la.NewVector(ndata)
+	o.J = la.NewMatrix(ndata, np)
+	o.NFeval = 0
+
+	// initial values
+	p := o.P
+	p.Apply(1, p0)
+	o.project(p)
+	o.Chi2 = o.residuals(o.r, p)
+
+	// workspace
+	A := la.NewMatrix(np, np) // Jᵀ⋅J (+ μ⋅diag(Jᵀ⋅J))
+	g := la.NewVector(np)     // Jᵀ⋅r = -½ dχ²/dp
+	b := la.NewVector(np)     // right-hand side
+	δ := la.NewVector(np)     // step
+	pnew := la.NewVector(np)  // trial parameters
+	rnew := la.NewVector(ndata)
+	free := make([]bool, np)
+	μ, ν := -1.0, 2.0
+
+	// message
+	if verbose {
+		io.Pf("%5s%23s%23s%23s\n", "it", "χ²", "max|g|", "μ")
+		io.Pf("%5d%23.15e\n", 0, o.Chi2)
+	}
+
+	// iterations
+	newJ := true
+	var gmax float64
+	for o.It = 1; o.It <= o.MaxIt; o.It++ {
+
+		// Jacobian, gradient and active set
+		if newJ {
+			o.jacobian(p)
+			la.MatTrVecMul(g, 1, o.J, o.r)
+			la.MatTrMatMul(A, 1, o.J, o.J)
+			gmax = 0.0
+			for j := 0; j < np; j++ {
+				free[j] = true
+				if o.Pmin != nil && p[j] <= o.Pmin[j] && g[j] < 0 {
+					free[j] = false
+				}
+				if o.Pmax != nil && p[j] >= o.Pmax[j] && g[j] > 0 {
+					free[j] = false
+				}
+				if free[j] {
+					gmax = math.Max(gmax, math.Abs(g[j]))
+				}
+			}
+			if gmax < o.Gtol {
+				break
+			}
+		}
+
+		// system of equations for the step
+		if μ < 0 {
+			μ = 0.0
+			for j := 0; j < np; j++ {
+				μ = math.Max(μ, A.Get(j, j))
+			}
+			μ *= o.Mu0
+		}
+		M := A.GetCopy()
+		for j := 0; j < np; j++ {
+			b[j] = g[j]
+			if o.Method == "lm" {
+				M.Add(j, j, μ) // [A + μ I] ⋅ δ = g  (see [2])
+			}
+			if !free[j] {
+				for k := 0; k < np; k++ {
+					M.Set(j, k, 0)
+					M.Set(k, j, 0)
+				}
+				M.Set(j, j, 1)
+				b[j] = 0
+			}
+		}
+		if denSolveGauss(δ, M, b) == 0 {
+			chk.Panic("cannot compute step: Jacobian is rank deficient at p = %v", p)
+		}
+
+		// trial point
+		var χ2new, pred float64
+		α := 1.0
+		for k := 0; k < 30; k++ {
+			for j := 0; j < np; j++ {
+				pnew[j] = p[j] + α*δ[j]
+			}
+			o.project(pnew)
+			χ2new = o.residuals(rnew, pnew)
+			pred = o.predicted(pnew, p, g, A)
+			if o.Method == "lm" || χ2new < o.Chi2 {
+				break
+			}
+			α /= 2 // Gauss-Newton: backtracking
+		}
+
+		// ratio between actual and predicted reductions
+		ρ := -1.0
+		if pred > 0 && !math.IsNaN(χ2new) {
+			ρ = (o.Chi2 - χ2new) / pred
+		}
+		roundoff := pred <= 10*MACHEPS*o.Chi2 && χ2new <= (1+10*MACHEPS)*o.Chi2 // reduction at round-off level
+		if roundoff {
+			ρ = 1
+		}
+
+		// update damping factor
+		if o.Method == "lm" {
+			if ρ > 0 {
+				μ *= math.Max(1.0/3.0, 1.0-math.Pow(2.0*ρ-1.0, 3))
+				ν = 2.0
+			} else {
+				μ *= ν
+				ν *= 2.0
+			}
+		}
+
+		// reject step
+		newJ = ρ > 0
+		if !newJ {
+			if o.Method == "gn" || μ > 1.0/MACHEPS {
+				break // cannot decrease χ² any further
+			}
+			continue
+		}
+
+		// accept step
+		dpmax := 0.0
+		for j := 0; j < np; j++ {
+			dpmax = math.Max(dpmax, math.Abs(pnew[j]-p[j])/(math.Abs(p[j])+o.Ptol))
+		}
+		dχ2 := o.Chi2 - χ2new
+		p.Apply(1, pnew)
+		o.r.Apply(1, rnew)
+		o.Chi2 = χ2new
+
+		// message
+		if verbose {
+			io.Pf("%5d%23.15e%23.15e%23.15e\n", o.It, o.Chi2, gmax, μ)
+		}
+
+		// check convergence
+		if roundoff || dχ2 <= o.Ftol*o.Chi2 || dpmax < o.Ptol {
+			break
+		}
+	}
+
+	// check convergence
+	if o.It > o.MaxIt {
+		chk.Panic("cannot converge after %d iterations. χ² = %g", o.MaxIt, o.Chi2)
+	}
+
+	// statistics
+	o.Dof = ndata - np
+	o.RedChi2 = 0
+	if o.Dof > 0 {
+		o.RedChi2 = o.Chi2 / float64(o.Dof)
+	}
+	o.covariance(σ == nil)
+}
+
+// Eval evaluates the fitted model at x
+func (o *LsqFit) Eval(x float64) float64 {
+	return o.Model(x, o.P)
+}
+
+// project projects p onto the bounds
+func (o *LsqFit) project(p la.Vector) {
+	for j := 0; j < o.Np; j++ {
+		if o.Pmin != nil && p[j] < o.Pmin[j] {
+			p[j] = o.Pmin[j]
+		}
+		if o.Pmax != nil && p[j] > o.Pmax[j] {
+			p[j] = o.Pmax[j]
+		}
+	}
+}
+
+// residuals computes the weighted residuals r and returns χ² = rᵀ⋅r
+func (o *LsqFit) residuals(r, p la.Vector) (χ2 float64) {
+	for i, x := range o.x {
+		r[i] = (o.y[i] - o.Model(x, p)) / o.σ[i]
+		χ2 += r[i] * r[i]
+	}
+	o.NFeval++
+	return
+}
+
+// predicted computes the reduction of χ² predicted by the linear model for the step pnew - p
+//  pred = χ² - |r - J⋅δ|² = 2 δᵀ⋅g - δᵀ⋅A⋅δ   with  g = Jᵀ⋅r  and  A = Jᵀ⋅J
+func (o *LsqFit) predicted(pnew, p, g la.Vector, A *la.Matrix) (pred float64) {
+	for j := 0; j < o.Np; j++ {
+		δj := pnew[j] - p[j]
+		pred += 2 * δj * g[j]
+		for k := 0; k < o.Np; k++ {
+			pred -= δj * A.Get(j, k) * (pnew[k] - p[k])
+		}
+	}
+	return
+}
+
+// jacobian computes the Jacobian of the weighted model J = dfᵢ/dpⱼ / σᵢ
+func (o *LsqFit) jacobian(p la.Vector) {
+	if o.Deriv != nil {
+		for i, x := range o.x {
+			o.Deriv(o.dfdp, x, p)
+			for j := 0; j < o.Np; j++ {
+				o.J.Set(i, j, o.dfdp[j]/o.σ[i])
+			}
+		}
+		return
+	}
+	h0 := math.Cbrt(MACHEPS)
+	for j := 0; j < o.Np; j++ {
+
+		// central differences (one-sided differences at bounds)
+		pj := p[j]
+		h := h0 * math.Max(math.Abs(pj), 1)
+		a, b := pj-h, pj+h
+		if o.Pmin != nil && a < o.Pmin[j] {
+			a = pj
+		}
+		if o.Pmax != nil && b > o.Pmax[j] {
+			b = pj
+		}
+		p[j] = a
+		for i, x := range o.x {
+			o.f[i] = o.Model(x, p)
+		}
+		p[j] = b
+		for i, x := range o.x {
+			o.J.Set(i, j, (o.Model(x, p)-o.f[i])/((b-a)*o.σ[i]))
+		}
+		p[j] = pj
+	}
+	o.NFeval += 2 * o.Np
+}
+
+// covariance computes the covariance matrix and standard errors of parameters
+func (o *LsqFit) covariance(scale bool) {
+	np := o.Np
+	o.jacobian(o.P)
+	A := la.NewMatrix(np, np)
+	la.MatTrMatMul(A, 1, o.J, o.J)
+	e := la.NewVector(np)
+	c := la.NewVector(np)
+	for k := 0; k < np; k++ {
+		e.Fill(0)
+		e[k] = 1
+		if denSolveGauss(c, A, e) == 0 {
+			chk.Panic("covariance matrix cannot be computed: Jacobian is rank deficient at p = %v", o.P)
+		}
+		for j := 0; j < np; j++ {
+			o.Cov.Set(j, k, c[j])
+		}
+	}
+	if scale {
+		for k := range o.Cov.Data {
+			o.Cov.Data[k] *= o.RedChi2
+		}
+	}
+	for j := 0; j < np; j++ {
+		o.Sigma[j] = math.Sqrt(o.Cov.Get(j, j))
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
+
+// LsqSpline implements the least-squares fitting of B-splines (e.g. cubic splines) to data
+//
+//           nb-1
+//   s(x) =   Σ  cⱼ⋅Nⱼ,ₚ(x)
+//           j=0
+//
+//  where Nⱼ,ₚ are the B-spline basis functions of degree p defined on the clamped knot vector
+//  with the given breakpoints (including the ends of the interval). The number of basis functions
+//  is nb = nbreaks + p - 1. The fitted spline is C^(p-1) continuous.
+type LsqSpline struct {
+	Deg   int       // degree p of the spline (e.g. 3 for cubic splines)
+	Knots []float64 // clamped knot vector
+	C     la.Vector // [nb] coefficients
+	Sigma la.Vector // [nb] standard errors of the coefficients
+	Chi2  float64   // χ²
+	nb    int       // number of basis functions
+	bf    []float64 // [nknots-1] workspace for basis functions
+}
+
+// NewLsqSpline returns a new object for least-squares fitting of splines
+//  Input:
+//   deg    -- degree of the spline (e.g. 3 for cubic splines)
+//   breaks -- [nbreaks] increasing breakpoints (including both ends of the interval)
+func NewLsqSpline(deg int, breaks []float64) (o *LsqSpline) {
+	if deg < 1 || len(breaks) < 2 {
+		chk.Panic("degree must be at least 1 and at least 2 breakpoints must be given. deg = %d and nbreaks = %d are invalid", deg, len(breaks))
+	}
+	for i := 1; i < len(breaks); i++ {
+		if breaks[i] <= breaks[i-1] {
+			chk.Panic("breakpoints must be strictly increasing")
+		}
+	}
+	o = new(LsqSpline)
+	o.Deg = deg
+	for k := 0; k < deg; k++ {
+		o.Knots = append(o.Knots, breaks[0])
+	}
+	o.Knots = append(o.Knots, breaks...)
+	for k := 0; k < deg; k++ {
+		o.Knots = append(o.Knots, breaks[len(breaks)-1])
+	}
+	o.nb = len(o.Knots) - deg - 1
+	o.bf = make([]float64, len(o.Knots)-1)
+	return
+}
+
+// Fit fits the spline to data
+//  Input:
+//   x, y -- [ndata] data points; x must be within the range of the breakpoints
+//   σ    -- [ndata] standard deviations of y; may be nil (unweighted fit)
+//  Output: results are stored in C, Sigma and Chi2
+func (o *LsqSpline) Fit(x, y, σ []float64) {
+
+	// check
+	ndata := len(x)
+	if ndata < o.nb {
+		chk.Panic("number of data points must not be smaller than the number of basis functions. %d < %d is invalid", ndata, o.nb)
+	}
+
+	// weighted collocation matrix
+	A := la.NewMatrix(ndata, o.nb)
+	b := la.NewVector(ndata)
+	for i := 0; i < ndata; i++ {
+		w := 1.0
+		if σ != nil {
+			w = 1.0 / σ[i]
+		}
+		o.basis(x[i])
+		for j := 0; j < o.nb; j++ {
+			A.Set(i, j, w*o.bf[j])
+		}
+		b[i] = w * y[i]
+	}
+
+	// solve
+	var cov *la.Matrix
+	o.C, cov, o.Chi2 = lsqSolveQR(A, b)
+	σdat := 1.0
+	if σ == nil {
+		σdat = 0.0
+		if ndata > o.nb {
+			σdat = math.Sqrt(o.Chi2 / float64(ndata-o.nb))
+		}
+	}
+	o.Sigma = la.NewVector(o.nb)
+	for j := 0; j < o.nb; j++ {
+		o.Sigma[j] = σdat * math.Sqrt(cov.Get(j, j))
+	}
+}
+
+// F computes the fitted spline s(x)
+func (o *LsqSpline) F(x float64) (res float64) {
+	o.basis(x)
+	for j := 0; j < o.nb; j++ {
+		res += o.C[j] * o.bf[j]
+	}
+	return
+}
+
+// G computes the derivative of the fitted spline ds/dx
+//  ds/dx = Σ p (cⱼ - cⱼ₋₁) / (tⱼ₊ₚ - tⱼ) Nⱼ,ₚ₋₁(x)
+func (o *LsqSpline) G(x float64) (res float64) {
+	p := o.Deg
+	t := o.Knots
+	o.basisDeg(x, p-1)
+	for j := 1; j < o.nb; j++ {
+		den := t[j+p] - t[j]
+		if den > 0 {
+			res += float64(p) * (o.C[j] - o.C[j-1]) / den * o.bf[j]
+		}
+	}
+	return
+}
+
+// basis computes all basis functions of degree Deg at x (stored in bf)
+func (o *LsqSpline) basis(x float64) {
+	o.basisDeg(x, o.Deg)
+}
+
+// basisDeg computes all basis functions of degree p at x (stored in bf) by the Cox-de Boor recursion
+func (o *LsqSpline) basisDeg(x float64, p int) {
+	t := o.Knots
+	nk := len(t)
+	if x < t[0] || x > t[nk-1] {
+		chk.Panic("x = %g is outside the range [%g, %g]", x, t[0], t[nk-1])
+	}
+
+	// degree 0: find span (the last non-empty span includes the right end)
+	for i := 0; i < nk-1; i++ {
+		o.bf[i] = 0
+		if t[i] <= x && x < t[i+1] {
+			o.bf[i] = 1
+		}
+	}
+	if x == t[nk-1] {
+		for i := nk - 2; i >= 0; i-- {
+			if t[i] < t[i+1] {
+				o.bf[i] = 1
+				break
+			}
+		}
+	}
+
+	// higher degrees
+	for k := 1; k <= p; k++ {
+		for i := 0; i < nk-1-k; i++ {
+			var a, b float64
+			if d := t[i+k] - t[i]; d > 0 {
+				a = (x - t[i]) / d * o.bf[i]
+			}
+			if d := t[i+k+1] - t[i+1]; d > 0 {
+				b = (t[i+k+1] - x) / d * o.bf[i+1]
+			}
+			o.bf[i] = a + b
+		}
+	}
+}
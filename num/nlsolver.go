@@ -26,6 +26,9 @@ type NlSolver struct {
 	LsMaxIt int     // linear solver maximum iterations
 	MaxIt   int     // Newton's method maximum iterations
 	ChkConv bool    // check convergence
+	Dogleg  bool    // use the trust-region dogleg method instead of Newton's method
+	LevMar  bool    // use the Levenberg-Marquardt method instead of Newton's method
+	Delta0  float64 // initial trust-region radius (dogleg)
 	atol    float64 // absolute tolerance
 	rtol    float64 // relative tolerance
 	ftol    float64 // minimum value of fx
@@ -73,7 +76,7 @@ type NlSolver struct {
 //   useSp -- Use sparse solver with JfcnSp
 //   useDn -- Use dense solver (matrix inversion) with JfcnDn
 //   numJ  -- Use numeric Jacobian (sparse version only)
-//   prms  -- atol, rtol, ftol, lSearch, lsMaxIt, maxIt
+//   prms  -- atol, rtol, ftol, lSearch, lsMaxIt, maxIt, dogleg, levMar, delta0
 func (o *NlSolver) Init(neq int, Ffcn fun.Vv, JfcnSp fun.Tv, JfcnDn fun.Mv, useDn, numJ bool, prms map[string]float64) {
 
 	// set default values
@@ -81,6 +84,7 @@ func (o *NlSolver) Init(neq int, Ffcn fun.Vv, JfcnSp fun.Tv, JfcnDn fun.Mv, useD
 	o.LsMaxIt = 20
 	o.MaxIt = 20
 	o.ChkConv = true
+	o.Delta0 = 1.0
 
 	// read parameters
 	for k, v := range prms {
@@ -97,6 +101,12 @@ func (o *NlSolver) Init(neq int, Ffcn fun.Vv, JfcnSp fun.Tv, JfcnDn fun.Mv, useD
 			o.LsMaxIt = int(v)
 		case "maxIt":
 			o.MaxIt = int(v)
+		case "dogleg":
+			o.Dogleg = v > 0.0
+		case "levMar":
+			o.LevMar = v > 0.0
+		case "delta0":
+			o.Delta0 = v
 		}
 	}
 
@@ -152,6 +162,12 @@ func (o *NlSolver) SetTols(Atol, Rtol, Ftol, ϵ float64) {
 // Solve solves non-linear problem f(x) == 0
 func (o *NlSolver) Solve(x []float64, silent bool) {
 
+	// globalised methods
+	if o.Dogleg || o.LevMar {
+		o.solveGlobal(x, silent)
+		return
+	}
+
 	// compute scaling vector
 	la.VecScaleAbs(o.scal, o.atol, o.rtol, x) // scal = Atol + Rtol*abs(x)
 
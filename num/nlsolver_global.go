@@ -0,0 +1,316 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+)
+
+// solveGlobal solves f(x) == 0 with the trust-region dogleg method or the Levenberg-Marquardt method
+//  Both methods minimise φ(x) = ½ fᵀf and reduce to Newton's method near the solution. The
+//  Jacobian is handled as a dense matrix (computed from the sparse one if useDn == false)
+//   References:
+//    [1] Nocedal J and Wright SJ (2006) Numerical Optimization. 2nd Edition, Springer. Chapters 4 and 10
+//    [2] Madsen K, Nielsen HB and Tingleff O (2004) Methods for non-linear least squares problems.
+//        2nd Edition, Informatics and Mathematical Modelling, Technical University of Denmark
+func (o *NlSolver) solveGlobal(x []float64, silent bool) {
+
+	// check
+	if o.Dogleg && o.LevMar {
+		chk.Panic("dogleg and Levenberg-Marquardt methods cannot be used at the same time")
+	}
+
+	// compute scaling vector
+	la.VecScaleAbs(o.scal, o.atol, o.rtol, x) // scal = Atol + Rtol*abs(x)
+
+	// evaluate function @ x
+	o.Ffcn(o.fx, x) // fx := f(x)
+	o.NFeval, o.NJeval = 1, 0
+	o.φ = 0.5 * la.VecDot(o.fx, o.fx)
+
+	// workspace
+	n := o.neq
+	if o.J == nil {
+		o.J = la.NewMatrix(n, n)
+	}
+	g := la.NewVector(n)    // g = Jᵀ f = dφdx
+	p := la.NewVector(n)    // step
+	Jp := la.NewVector(n)   // J p
+	fnew := la.NewVector(n) // f(x + p)
+	A := la.NewMatrix(n, n) // Jᵀ J + μ I
+	Δ := o.Delta0           // trust-region radius
+	μ, ν := -1.0, 2.0       // damping parameter and its growth factor
+
+	// show message
+	if !silent {
+		o.msg("", 0, 0, 0, true, false)
+	}
+
+	// iterations
+	var Ldx, fxMax float64
+	newJ := true
+	for o.It = 0; o.It < o.MaxIt; o.It++ {
+
+		// check convergence on f(x)
+		fxMax = o.fx.Largest(1.0) // den = 1.0
+		if fxMax < o.ftol {
+			if !silent {
+				o.msg("fxMax(ini)", o.It, Ldx, fxMax, false, true)
+			}
+			break
+		}
+
+		// show message
+		if !silent {
+			o.msg("", o.It, Ldx, fxMax, false, false)
+		}
+
+		// output
+		if o.Out != nil {
+			o.Out(x)
+		}
+
+		// evaluate Jacobian @ x and gradient of φ
+		if newJ {
+			o.denseJacobian(x)
+			la.MatTrVecMul(g, 1, o.J, o.fx) // g := tra(J) * fx
+			if g.Largest(1.0) < MACHEPS*(1.0+o.φ) {
+				chk.Panic("stationary point of ½|f|² found with fxMax = %g. f(x) == 0 has no solution near x = %v", fxMax, x)
+			}
+		}
+
+		// dogleg step
+		if o.Dogleg {
+			o.doglegStep(p, g, Δ)
+
+			// Levenberg-Marquardt step: (Jᵀ J + μ I) p = -g
+		} else {
+			la.MatTrMatMul(A, 1, o.J, o.J)
+			if μ < 0 {
+				μ = 0.0
+				for i := 0; i < n; i++ {
+					μ = math.Max(μ, A.Get(i, i))
+				}
+				μ *= 1e-3
+			}
+			for i := 0; i < n; i++ {
+				A.Add(i, i, μ)
+				Jp[i] = -g[i]
+			}
+			if denSolveGauss(p, A, Jp) == 0 {
+				chk.Panic("Levenberg-Marquardt system is singular with μ = %g", μ)
+			}
+		}
+
+		// trial point
+		for i := 0; i < n; i++ {
+			o.x0[i] = x[i] + p[i]
+		}
+		o.Ffcn(fnew, o.x0)
+		o.NFeval++
+		φnew := 0.5 * la.VecDot(fnew, fnew)
+
+		// ratio between actual and predicted reductions
+		la.MatVecMul(Jp, 1, o.J, p) // Jp := J * p
+		pred := 0.0
+		for i := 0; i < n; i++ {
+			pred -= 0.5*Jp[i]*Jp[i] + o.fx[i]*Jp[i] // pred = φ - ½|f + J p|²
+		}
+		ρ := -1.0
+		if pred > 0 {
+			ρ = (o.φ - φnew) / pred
+		}
+		if math.IsNaN(φnew) {
+			ρ = -1.0
+		}
+
+		// update trust-region radius or damping parameter
+		pnorm := p.Norm()
+		if o.Dogleg {
+			if ρ < 0.25 {
+				Δ = 0.25 * pnorm
+			} else if ρ > 0.75 && pnorm > 0.99*Δ {
+				Δ = 2.0 * Δ
+			}
+		} else {
+			if ρ > 0 {
+				μ *= math.Max(1.0/3.0, 1.0-math.Pow(2.0*ρ-1.0, 3))
+				ν = 2.0
+			} else {
+				μ *= ν
+				ν *= 2.0
+			}
+		}
+
+		// reject step
+		newJ = ρ > 1e-4
+		if !newJ {
+			if Δ < MACHEPS*(1.0+la.Vector(x).Rms()) || μ > 1.0/MACHEPS {
+				chk.Panic("cannot decrease ½|f|² any further with fxMax = %g (Δ = %g, μ = %g)", fxMax, Δ, μ)
+			}
+			continue
+		}
+
+		// accept step
+		Ldx = 0.0
+		for i := 0; i < n; i++ {
+			x[i] = o.x0[i]
+			o.fx[i] = fnew[i]
+			Ldx += (p[i] / o.scal[i]) * (p[i] / o.scal[i])
+		}
+		Ldx = math.Sqrt(Ldx / float64(n))
+		o.φ = φnew
+
+		// check convergence on f(x)
+		fxMax = o.fx.Largest(1.0) // den = 1.0
+		if fxMax < o.ftol {
+			if !silent {
+				o.msg("fxMax", o.It, Ldx, fxMax, false, true)
+			}
+			break
+		}
+
+		// check convergence on Ldx
+		if Ldx < o.fnewt {
+			if !silent {
+				o.msg("Ldx", o.It, Ldx, fxMax, false, true)
+			}
+			break
+		}
+	}
+
+	// output
+	if o.Out != nil {
+		o.Out(x)
+	}
+
+	// check convergence
+	if o.It == o.MaxIt {
+		chk.Panic("cannot converge after %d iterations", o.It)
+	}
+}
+
+// denseJacobian computes the dense Jacobian matrix J @ x
+func (o *NlSolver) denseJacobian(x []float64) {
+	if o.useDn {
+		o.JfcnDn(o.J, x)
+	} else {
+		if o.numJ {
+			Jacobian(&o.Jtri, o.Ffcn, x, o.fx, o.w)
+			o.NFeval += o.neq
+		} else {
+			o.JfcnSp(&o.Jtri, x)
+		}
+		o.J = o.Jtri.ToDense()
+	}
+	o.NJeval++
+}
+
+// doglegStep computes the dogleg step p within the trust region of radius Δ
+//  The step is a combination of the Cauchy point  pc = -(gᵀg / |J g|²) g  and the Gauss-Newton
+//  point  pgn = -J⁻¹ f, where g = Jᵀ f
+func (o *NlSolver) doglegStep(p, g la.Vector, Δ float64) {
+
+	// Cauchy point
+	n := o.neq
+	Jg := la.NewVector(n)
+	la.MatVecMul(Jg, 1, o.J, g)
+	gg, JgJg := la.VecDot(g, g), la.VecDot(Jg, Jg)
+	pc := la.NewVector(n)
+	for i := 0; i < n; i++ {
+		pc[i] = -(gg / JgJg) * g[i]
+	}
+	pcNorm := pc.Norm()
+
+	// Gauss-Newton point
+	mf := la.NewVector(n)
+	for i := 0; i < n; i++ {
+		mf[i] = -o.fx[i]
+	}
+	singular := denSolveGauss(p, o.J, mf) == 0
+	if !singular && p.Norm() <= Δ {
+		return // p = pgn
+	}
+
+	// steepest descent direction only
+	if pcNorm >= Δ || singular {
+		s := math.Min(1, Δ/pcNorm)
+		for i := 0; i < n; i++ {
+			p[i] = s * pc[i]
+		}
+		return
+	}
+
+	// intersection of pc + τ (pgn - pc) with the boundary
+	d := la.NewVector(n)
+	for i := 0; i < n; i++ {
+		d[i] = p[i] - pc[i]
+	}
+	a, b, c := la.VecDot(d, d), 2.0*la.VecDot(pc, d), pcNorm*pcNorm-Δ*Δ
+	τ := (-b + math.Sqrt(b*b-4.0*a*c)) / (2.0 * a)
+	for i := 0; i < n; i++ {
+		p[i] = pc[i] + τ*d[i]
+	}
+}
+
+// denSolveGauss solves the dense linear system A x = b by Gaussian elimination with partial pivoting
+//  Note: A is not modified
+//  Note: la.DenSolve and la.MatInv are not used because LAPACK panics with singular matrices,
+//        whereas the callers must detect singular systems and proceed; e.g. the dogleg step falls
+//        back to the steepest descent direction and the continuation keeps the previous tangent
+//  Output:
+//   x   -- solution; unchanged if A is singular
+//   det -- determinant of A; zero if A is singular
+func denSolveGauss(x la.Vector, A *la.Matrix, b la.Vector) (det float64) {
+
+	// augmented matrix
+	n := A.M
+	a := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		a[i] = make([]float64, n+1)
+		for j := 0; j < n; j++ {
+			a[i][j] = A.Get(i, j)
+		}
+		a[i][n] = b[i]
+	}
+
+	// elimination
+	det = 1.0
+	for k := 0; k < n; k++ {
+		piv := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i][k]) > math.Abs(a[piv][k]) {
+				piv = i
+			}
+		}
+		if a[piv][k] == 0 {
+			return 0
+		}
+		if piv != k {
+			a[k], a[piv] = a[piv], a[k]
+			det = -det
+		}
+		det *= a[k][k]
+		for i := k + 1; i < n; i++ {
+			m := a[i][k] / a[k][k]
+			for j := k; j <= n; j++ {
+				a[i][j] -= m * a[k][j]
+			}
+		}
+	}
+
+	// back substitution
+	for i := n - 1; i >= 0; i-- {
+		s := a[i][n]
+		for j := i + 1; j < n; j++ {
+			s -= a[i][j] * x[j]
+		}
+		x[i] = s / a[i][i]
+	}
+	return
+}
@@ -33,6 +33,24 @@ void dqawoe_(fType f, double* a, double* b, double* omega, int* integr,
              double* alist, double* blist, double* rlist, double* elist,
              int* iord, int* nnlog, int* momcom, double* chebmo, int* fid);
 
+void dqawce_(fType f, double* a, double* b, double* c, double* epsabs, double* epsrel, int* limit,
+             double* result, double* abserr, int* neval, int* ier,
+             double* alist, double* blist, double* rlist, double* elist,
+             int* iord, int* last, int* fid);
+
+void dqawse_(fType f, double* a, double* b, double* alfa, double* beta, int* integr,
+             double* epsabs, double* epsrel, int* limit,
+             double* result, double* abserr, int* neval, int* ier,
+             double* alist, double* blist, double* rlist, double* elist,
+             int* iord, int* last, int* fid);
+
+void dqawfe_(fType f, double* a, double* omega, int* integr, double* epsabs,
+             int* limlst, int* limit, int* maxp1,
+             double* result, double* abserr, int* neval, int* ier,
+             double* rslst, double* erlst, int* ierlst, int* lst,
+             double* alist, double* blist, double* rlist, double* elist,
+             int* iord, int* nnlog, double* chebmo, int* fid);
+
 #include "connect.h"
 */
 import "C"
@@ -444,6 +462,311 @@ func Awoe(fid int32, f fType, a, b, omega float64, integr int32, epsabs, epsrel
 	return
 }
 
+// Awce computes the Cauchy principal value of the integral of f(x)/(x-c) over (a,b)
+//
+// AW: Automatic with weight, C: Cauchy principal value
+//
+//   INPUT:
+//     fid    -- id of function to avoid goroutine problems
+//     f      -- function defining the integrand
+//     a      -- lower limit of integration
+//     b      -- upper limit of integration
+//     c      -- parameter in the weight function w(x) = 1/(x-c); c must be different from a and b
+//     epsabs -- absolute accuracy requested [use ≤0 for default]
+//     epsrel -- relative accuracy requested [use ≤0 for default]
+//
+//   INPUT/OUTPUT:
+//     NOTE: (1) the length of the 5 vectors below is equal to the "limit" variable in the original
+//               code which is an upperbound on the number of subintervals in the partition of (a,b)
+//           (2) the 5 vectors below may be <nil>, thus memory is allocated here
+//
+//     alist, blist, rlist, elist, iord -- see Agse
+//
+//   OUTPUT:
+//     result -- approximation to the integral
+//     abserr -- estimate of the modulus of the absolute error, which should equal or exceed abs(i-result)
+//     neval  -- number of integrand evaluations
+//     last   -- number of subintervals actually produced in the subdivision process
+//
+func Awce(fid int32, f fType, a, b, c, epsabs, epsrel float64, alist, blist, rlist, elist []float64, iord []int32) (result, abserr float64, neval, last int32) {
+
+	// set function in database
+	if fid >= int32(len(functions)) {
+		chk.Panic("functions database capacity exceeded. max number of functions = %d\n", len(functions))
+	}
+	functions[fid] = f
+
+	// default values
+	if epsabs <= 0 {
+		epsabs = 1.49e-8
+	}
+	if epsrel <= 0 {
+		epsrel = 1.49e-8
+	}
+
+	// allocate vectors
+	limit := len(alist)
+	if limit < 1 {
+		limit = 50
+		alist = make([]float64, limit)
+		blist = make([]float64, limit)
+		rlist = make([]float64, limit)
+		elist = make([]float64, limit)
+		iord = make([]int32, limit)
+	}
+
+	// call quadpack
+	var ier int32
+	C.dqawce_(
+		C.fType(C.fcn),
+		(*C.double)(unsafe.Pointer(&a)),
+		(*C.double)(unsafe.Pointer(&b)),
+		(*C.double)(unsafe.Pointer(&c)),
+		(*C.double)(unsafe.Pointer(&epsabs)),
+		(*C.double)(unsafe.Pointer(&epsrel)),
+		(*C.int)(unsafe.Pointer(&limit)),
+		(*C.double)(unsafe.Pointer(&result)),
+		(*C.double)(unsafe.Pointer(&abserr)),
+		(*C.int)(unsafe.Pointer(&neval)),
+		(*C.int)(unsafe.Pointer(&ier)),
+		(*C.double)(unsafe.Pointer(&alist[0])),
+		(*C.double)(unsafe.Pointer(&blist[0])),
+		(*C.double)(unsafe.Pointer(&rlist[0])),
+		(*C.double)(unsafe.Pointer(&elist[0])),
+		(*C.int)(unsafe.Pointer(&iord[0])),
+		(*C.int)(unsafe.Pointer(&last)),
+		(*C.int)(unsafe.Pointer(&fid)),
+	)
+
+	// check
+	checkIer(ier)
+	return
+}
+
+// Awse approximates the definite integral ∫ f(x)⋅w(x) dx over (a,b) where w(x) has
+// algebraico-logarithmic singularities at the end points
+//
+// AW: Automatic with weight, S: end-point singularities
+//
+//   INPUT:
+//     fid    -- id of function to avoid goroutine problems
+//     f      -- function defining the integrand
+//     a      -- lower limit of integration
+//     b      -- upper limit of integration (b > a)
+//     alfa   -- parameter in the weight function; alfa > -1
+//     beta   -- parameter in the weight function; beta > -1
+//
+//     integr -- indicates which of the weight functions is to be used:
+//                 integr = 1  ⇒  w(x) = (x-a)^alfa ⋅ (b-x)^beta
+//                 integr = 2  ⇒  w(x) = (x-a)^alfa ⋅ (b-x)^beta ⋅ log(x-a)
+//                 integr = 3  ⇒  w(x) = (x-a)^alfa ⋅ (b-x)^beta ⋅ log(b-x)
+//                 integr = 4  ⇒  w(x) = (x-a)^alfa ⋅ (b-x)^beta ⋅ log(x-a) ⋅ log(b-x)
+//               [default = 1]
+//
+//     epsabs -- absolute accuracy requested [use ≤0 for default]
+//     epsrel -- relative accuracy requested [use ≤0 for default]
+//
+//   INPUT/OUTPUT:
+//     NOTE: (1) the length of the 5 vectors below is equal to the "limit" variable in the original
+//               code which is an upperbound on the number of subintervals in the partition of (a,b)
+//           (2) the 5 vectors below may be <nil>, thus memory is allocated here
+//
+//     alist, blist, rlist, elist, iord -- see Agse
+//
+//   OUTPUT:
+//     result -- approximation to the integral
+//     abserr -- estimate of the modulus of the absolute error, which should equal or exceed abs(i-result)
+//     neval  -- number of integrand evaluations
+//     last   -- number of subintervals actually produced in the subdivision process
+//
+func Awse(fid int32, f fType, a, b, alfa, beta float64, integr int32, epsabs, epsrel float64, alist, blist, rlist, elist []float64, iord []int32) (result, abserr float64, neval, last int32) {
+
+	// set function in database
+	if fid >= int32(len(functions)) {
+		chk.Panic("functions database capacity exceeded. max number of functions = %d\n", len(functions))
+	}
+	functions[fid] = f
+
+	// default values
+	if epsabs <= 0 {
+		epsabs = 1.49e-8
+	}
+	if epsrel <= 0 {
+		epsrel = 1.49e-8
+	}
+
+	// default flags
+	if integr < 1 || integr > 4 {
+		integr = 1
+	}
+
+	// allocate vectors
+	limit := len(alist)
+	if limit < 2 {
+		limit = 50
+		alist = make([]float64, limit)
+		blist = make([]float64, limit)
+		rlist = make([]float64, limit)
+		elist = make([]float64, limit)
+		iord = make([]int32, limit)
+	}
+
+	// call quadpack
+	var ier int32
+	C.dqawse_(
+		C.fType(C.fcn),
+		(*C.double)(unsafe.Pointer(&a)),
+		(*C.double)(unsafe.Pointer(&b)),
+		(*C.double)(unsafe.Pointer(&alfa)),
+		(*C.double)(unsafe.Pointer(&beta)),
+		(*C.int)(unsafe.Pointer(&integr)),
+		(*C.double)(unsafe.Pointer(&epsabs)),
+		(*C.double)(unsafe.Pointer(&epsrel)),
+		(*C.int)(unsafe.Pointer(&limit)),
+		(*C.double)(unsafe.Pointer(&result)),
+		(*C.double)(unsafe.Pointer(&abserr)),
+		(*C.int)(unsafe.Pointer(&neval)),
+		(*C.int)(unsafe.Pointer(&ier)),
+		(*C.double)(unsafe.Pointer(&alist[0])),
+		(*C.double)(unsafe.Pointer(&blist[0])),
+		(*C.double)(unsafe.Pointer(&rlist[0])),
+		(*C.double)(unsafe.Pointer(&elist[0])),
+		(*C.int)(unsafe.Pointer(&iord[0])),
+		(*C.int)(unsafe.Pointer(&last)),
+		(*C.int)(unsafe.Pointer(&fid)),
+	)
+
+	// check
+	checkIer(ier)
+	return
+}
+
+// Awfe computes Fourier integrals ∫ f(x)⋅w(x) dx over (a,+infinity) where
+// w(x) = cos(omega*x) or w(x)=sin(omega*x)
+//
+// AW: Automatic with weight, F: Fourier
+//
+//   INPUT:
+//     fid    -- id of function to avoid goroutine problems
+//     f      -- function defining the integrand
+//     a      -- lower limit of integration
+//     omega  -- parameter in the weight function
+//
+//     integr -- indicates which of the weight functions is to be used:
+//                 integr = 1  ⇒   w(x) = cos(omega*x)
+//                 integr = 2  ⇒   w(x) = sin(omega*x)
+//               [default = 1]
+//
+//     epsabs -- absolute accuracy requested [use ≤0 for default]
+//
+//     maxp1 -- upper bound on the number of Chebyshev moments which can be stored within each
+//              cycle; see Awoe [default = 50,  maxp1 ≥ 1]
+//
+//   INPUT/OUTPUT:
+//     NOTE: (1) the length of the 3 vectors below is equal to the "limlst" variable in the original
+//               code which is an upperbound on the number of cycles (limlst ≥ 3)
+//           (2) the 3 vectors below may be <nil>, thus memory is allocated here
+//
+//     rslst  -- the first lst elements of which are the integral contributions over the cycles
+//               (a+(k-1)c, a+kc), with c = (2*int(abs(omega))+1)*pi/abs(omega)
+//     erlst  -- the first lst elements of which are the error estimates corresponding to rslst
+//     ierlst -- the first lst elements of which are the error flags corresponding to rslst
+//
+//     NOTE: (1) the length of the 6 vectors below is equal to the "limit" variable in the original
+//               code which is an upperbound on the number of subintervals in each cycle
+//           (2) the 6 vectors below may be <nil>, thus memory is allocated here
+//
+//     alist, blist, rlist, elist, iord -- see Agse
+//     nnlog -- see Awoe
+//
+//     chebmo -- A rank-2 array of shape (maxp1, 25) with space for the Chebyshev moments
+//               [may be nil]
+//
+//   OUTPUT:
+//     result -- approximation to the integral
+//     abserr -- estimate of the modulus of the absolute error, which should equal or exceed abs(i-result)
+//     neval  -- number of integrand evaluations
+//     lst    -- number of cycles needed for the integration
+//
+func Awfe(fid int32, f fType, a, omega float64, integr int32, epsabs float64, maxp1 int32, rslst, erlst []float64, ierlst []int32, alist, blist, rlist, elist []float64, iord, nnlog []int32, chebmo []float64) (result, abserr float64, neval, lst int32) {
+
+	// set function in database
+	if fid >= int32(len(functions)) {
+		chk.Panic("functions database capacity exceeded. max number of functions = %d\n", len(functions))
+	}
+	functions[fid] = f
+
+	// default values
+	if epsabs <= 0 {
+		epsabs = 1.49e-8
+	}
+
+	// default flags
+	if integr < 1 || integr > 2 {
+		integr = 1
+	}
+	if maxp1 < 1 {
+		maxp1 = 50
+	}
+
+	// allocate vectors
+	limlst := len(rslst)
+	if limlst < 3 {
+		limlst = 50
+		rslst = make([]float64, limlst)
+		erlst = make([]float64, limlst)
+		ierlst = make([]int32, limlst)
+	}
+	limit := len(alist)
+	if limit < 1 {
+		limit = 50
+		alist = make([]float64, limit)
+		blist = make([]float64, limit)
+		rlist = make([]float64, limit)
+		elist = make([]float64, limit)
+		iord = make([]int32, limit)
+		nnlog = make([]int32, limit)
+	}
+
+	// chebmo
+	if int32(len(chebmo)) < 25*maxp1 {
+		chebmo = make([]float64, 25*maxp1)
+	}
+
+	// call quadpack
+	var ier int32
+	C.dqawfe_(
+		C.fType(C.fcn),
+		(*C.double)(unsafe.Pointer(&a)),
+		(*C.double)(unsafe.Pointer(&omega)),
+		(*C.int)(unsafe.Pointer(&integr)),
+		(*C.double)(unsafe.Pointer(&epsabs)),
+		(*C.int)(unsafe.Pointer(&limlst)),
+		(*C.int)(unsafe.Pointer(&limit)),
+		(*C.int)(unsafe.Pointer(&maxp1)),
+		(*C.double)(unsafe.Pointer(&result)),
+		(*C.double)(unsafe.Pointer(&abserr)),
+		(*C.int)(unsafe.Pointer(&neval)),
+		(*C.int)(unsafe.Pointer(&ier)),
+		(*C.double)(unsafe.Pointer(&rslst[0])),
+		(*C.double)(unsafe.Pointer(&erlst[0])),
+		(*C.int)(unsafe.Pointer(&ierlst[0])),
+		(*C.int)(unsafe.Pointer(&lst)),
+		(*C.double)(unsafe.Pointer(&alist[0])),
+		(*C.double)(unsafe.Pointer(&blist[0])),
+		(*C.double)(unsafe.Pointer(&rlist[0])),
+		(*C.double)(unsafe.Pointer(&elist[0])),
+		(*C.int)(unsafe.Pointer(&iord[0])),
+		(*C.int)(unsafe.Pointer(&nnlog[0])),
+		(*C.double)(unsafe.Pointer(&chebmo[0])),
+		(*C.int)(unsafe.Pointer(&fid)),
+	)
+
+	// check
+	checkIer(ier)
+	return
+}
+
 // status checks ier code
 func checkIer(ier int32) {
 	if ier == 0 {
@@ -462,6 +785,8 @@ func checkIer(ier int32) {
 		chk.Panic("error # 5: the integral is probably divergent, or slowly convergent\n")
 	case 6:
 		chk.Panic("error # 6: the input is invalid\n")
+	case 7:
+		chk.Panic("error # 7: bad integrand behaviour occurs within one or more of the cycles\n")
 	}
 	chk.Panic("unknown error\n")
 }
@@ -87,6 +87,28 @@ func runO(tst *testing.T, name string, y fType, a, b, omega, correct, tol float6
 	chk.AnaNum(tst, name, tol, res, correct, chk.Verbose)
 }
 
+// auxiliary function to run test (Cauchy principal value)
+func runC(tst *testing.T, name string, y fType, a, b, c, correct, tol float64) {
+	res, _, _, _ := Awce(0, y, a, b, c, 0, 0, nil, nil, nil, nil, nil)
+	chk.AnaNum(tst, name, tol, res, correct, chk.Verbose)
+}
+
+// auxiliary function to run test (with algebraico-logarithmic end-point singularities)
+func runS(tst *testing.T, name string, y fType, a, b, alfa, beta float64, integr int32, correct, tol float64) {
+	res, _, _, _ := Awse(0, y, a, b, alfa, beta, integr, 0, 0, nil, nil, nil, nil, nil)
+	chk.AnaNum(tst, name, tol, res, correct, chk.Verbose)
+}
+
+// auxiliary function to run test (Fourier integrals)
+func runF(tst *testing.T, name string, y fType, a, omega, correct, tol float64, isSin bool) {
+	var integr int32 = 1 // cos(omega*x)
+	if isSin {
+		integr = 2 // sin(omega*x)
+	}
+	res, _, _, _ := Awfe(0, y, a, omega, integr, 0, 0, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	chk.AnaNum(tst, name, tol, res, correct, chk.Verbose)
+}
+
 func TestAgs02(tst *testing.T) {
 
 	//verbose()
@@ -128,3 +150,56 @@ func TestAgs02(tst *testing.T) {
 		return Exp(a * (x - 1))
 	}, 0, 1, ome, Aref, 1e-16, true) // true => sin
 }
+
+func TestAws03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Aws03. weighted functions")
+
+	// 1. Cauchy principal value: PV ∫ x²/(x-1) dx over (0,3)
+	runC(tst, "function # 1", func(x float64) float64 {
+		return x * x
+	}, 0, 3, 1, 7.5+Log(2.0), 1e-12)
+
+	// 2. algebraic end-point singularities: ∫ x⋅x^(-½)⋅(1-x)^(-½) dx over (0,1) = B(3/2,½)
+	runS(tst, "function # 2", func(x float64) float64 {
+		return x
+	}, 0, 1, -0.5, -0.5, 1, Pi/2.0, 1e-12)
+
+	// 3. logarithmic end-point singularity: ∫ x^(-½)⋅log(x) dx over (0,1)
+	runS(tst, "function # 3", func(x float64) float64 {
+		return 1
+	}, 0, 1, -0.5, 0, 2, -4.0, 1e-12)
+
+	// 4. Fourier cosine integral over (0,∞)
+	runF(tst, "function # 4", func(x float64) float64 {
+		return Exp(-x)
+	}, 0, 1, 0.5, 1e-10, false) // false => cos
+}
+
+func TestAws04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Aws04. goroutines")
+
+	// channels
+	nch := 3
+	done := make(chan int, nch)
+
+	// run all
+	for ich := 0; ich < nch; ich++ {
+		go func(fid int) {
+			c := 1.0 + float64(fid)/2.0 // different singular points
+			y := func(x float64) float64 { return x * x }
+			A, _, _, _ := Awce(int32(fid), y, 0, 3, c, 0, 0, nil, nil, nil, nil, nil)
+			Aref := 4.5 + 3*c + c*c*Log((3-c)/c)
+			chk.Float64(tst, "A", 1e-12, A, Aref)
+			done <- 1
+		}(ich)
+	}
+
+	// wait
+	for i := 0; i < nch; i++ {
+		<-done
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/fun"
+)
+
+// QuadTanhSinh computes the integral of f over [a, b] by the double exponential (tanh-sinh) rule
+// of Takahasi and Mori [1,2]
+//
+//          b          ∞
+//   res = ∫ f(x) dx = ∫ f(x(t)) x'(t) dt  ≈  h Σ f(x(kh)) x'(kh)
+//         a          -∞                      k
+//
+//  The integral is transformed to the real line by one of the following maps and then computed by
+//  the trapezoidal rule, halving h until two successive results agree to max(atol, rtol⋅|res|)
+//
+//   x = c + r⋅tanh(π/2⋅sinh t)   c = (a+b)/2, r = (b-a)/2   for [a, b]   (tanh-sinh)
+//   x = a + exp(π/2⋅sinh t)                                for [a, ∞)   (exp-sinh)
+//   x = b - exp(π/2⋅sinh t)                                for (-∞, b]  (exp-sinh)
+//   x = sinh(π/2⋅sinh t)                                   for (-∞, ∞)  (sinh-sinh)
+//
+//  Because the nodes cluster double exponentially near the ends of the interval and the integrand is
+//  never evaluated at the ends, end-point singularities such as 1/√x or log(x) are handled without
+//  special treatment. Nevertheless, f only receives the rounded x; thus, singularities at a non-zero
+//  end (e.g. 1/√(1-x) at x = 1) limit the accuracy to about √ε and should be shifted to the origin.
+//  This rule is implemented in pure Go and may be used instead of QUADPACK.
+//
+//   Input:
+//     f        -- integrand
+//     a, b     -- limits of integration; may be infinite (±math.Inf)
+//     atol     -- absolute tolerance
+//     rtol     -- relative tolerance
+//     maxLevel -- maximum number of halvings of h [use ≤0 for default = 10]
+//
+//   Output:
+//     res    -- integral
+//     err    -- estimated absolute error (difference between the last two levels; usually pessimistic)
+//     nfeval -- number of function evaluations
+//
+//   References:
+//   [1] Takahasi H and Mori M (1974) Double exponential formulas for numerical integration.
+//       Publications of the Research Institute for Mathematical Sciences, 9(3):721-741
+//   [2] Bailey DH, Jeyabalan K and Li XS (2005) A comparison of three high-precision quadrature
+//       schemes. Experimental Mathematics, 14(3):317-329
+func QuadTanhSinh(f fun.Ss, a, b, atol, rtol float64, maxLevel int) (res, err float64, nfeval int) {
+
+	// trivial and reversed intervals
+	if a == b {
+		return
+	}
+	if a > b {
+		res, err, nfeval = QuadTanhSinh(f, b, a, atol, rtol, maxLevel)
+		res = -res
+		return
+	}
+	if maxLevel < 1 {
+		maxLevel = 10
+	}
+
+	// term of the trapezoidal sum at t
+	const tmax = 6.5 // nodes beyond underflow or overflow are skipped by tanhSinhNode
+	term := func(t float64) float64 {
+		x, w, ok := tanhSinhNode(a, b, t)
+		if !ok {
+			return 0
+		}
+		nfeval++
+		return w * f(x)
+	}
+
+	// level 0
+	h := 0.5
+	sum := term(0)
+	for t := h; t <= tmax; t += h {
+		sum += term(t) + term(-t)
+	}
+	res = h * sum
+
+	// refinement: add the odd nodes of the next level
+	for level := 1; level <= maxLevel; level++ {
+		h /= 2.0
+		for t := h; t <= tmax; t += 2.0 * h {
+			sum += term(t) + term(-t)
+		}
+		prev := res
+		res = h * sum
+		err = math.Abs(res - prev)
+		if err <= math.Max(atol, rtol*math.Abs(res)) {
+			break
+		}
+	}
+	return
+}
+
+// tanhSinhNode computes the node x(t) and weight x'(t) of the double exponential rule over [a, b]
+//  Note: ok is false if x coincides with a finite end of the interval or overflows
+func tanhSinhNode(a, b, t float64) (x, w float64, ok bool) {
+	u := math.Pi / 2.0 * math.Sinh(t)
+	du := math.Pi / 2.0 * math.Cosh(t)
+	lowInf, uppInf := math.IsInf(a, -1), math.IsInf(b, +1)
+	switch {
+
+	// sinh-sinh
+	case lowInf && uppInf:
+		x, w = math.Sinh(u), du*math.Cosh(u)
+
+	// exp-sinh
+	case uppInf:
+		e := math.Exp(u)
+		x, w = a+e, du*e
+		if x == a {
+			return
+		}
+	case lowInf:
+		e := math.Exp(u)
+		x, w = b-e, du*e
+		if x == b {
+			return
+		}
+
+	// tanh-sinh: distance to the nearest end d = r⋅(1 - tanh|u|) computed without cancellation
+	default:
+		r := (b - a) / 2.0
+		e := math.Exp(-2.0 * math.Abs(u))
+		d := r * 2.0 * e / (1.0 + e)
+		w = r * du * 4.0 * e / ((1.0 + e) * (1.0 + e))
+		if t < 0 {
+			x = a + d
+		} else {
+			x = b - d
+		}
+		if x <= a || x >= b {
+			return
+		}
+	}
+	ok = !math.IsInf(x, 0) && !math.IsInf(w, 0)
+	return
+}
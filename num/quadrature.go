@@ -4,7 +4,12 @@
 
 package num
 
-import "github.com/cpmech/gosl/num/qpck"
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/num/qpck"
+)
 
 // QuadGen performs automatic integration (quadrature) using the general-purpose
 // QUADPACK routine AGSE (Automatic, general-purpose, end-points singularities).
@@ -95,3 +100,118 @@ func QuadExpIx(a, b, m float64, fid int, f func(x float64) float64) (res complex
 	res = complex(Icos, Isin)
 	return
 }
+
+// QuadInf performs automatic integration (quadrature) over infinite intervals using the
+// QUADPACK routine AGIE (Automatic, general-purpose, infinite intervals)
+//
+//   INPUT:
+//     a      -- lower limit of integration; may be -∞ (math.Inf(-1))
+//     b      -- upper limit of integration; may be +∞ (math.Inf(+1))
+//     fid    -- index of goroutine (to avoid race problems)
+//     f      -- function defining the integrand
+//
+//   OUTPUT:          b
+//             res = ∫  f(x) dx
+//                   a
+//
+//   NOTE: QuadGen is called if both a and b are finite
+//
+func QuadInf(a, b float64, fid int, f func(x float64) float64) (res float64) {
+	id := int32(fid)
+	negInf, posInf := math.IsInf(a, -1), math.IsInf(b, +1)
+	switch {
+	case negInf && posInf:
+		res, _, _, _ = qpck.Agie(id, f, 0, 2, 0, 0, nil, nil, nil, nil, nil)
+	case posInf:
+		res, _, _, _ = qpck.Agie(id, f, a, 1, 0, 0, nil, nil, nil, nil, nil)
+	case negInf:
+		res, _, _, _ = qpck.Agie(id, f, b, -1, 0, 0, nil, nil, nil, nil, nil)
+	default:
+		res = QuadGen(a, b, fid, f)
+	}
+	return
+}
+
+// QuadCauchy computes the Cauchy principal value of the integral of f(x)/(x-c) using the
+// QUADPACK routine AWCE (Automatic with weight, Cauchy principal value)
+//
+//   INPUT:
+//     a      -- lower limit of integration
+//     b      -- upper limit of integration
+//     c      -- singular point; c ≠ a and c ≠ b
+//     fid    -- index of goroutine (to avoid race problems)
+//     f      -- function defining the integrand
+//
+//   OUTPUT:             b
+//             res = PV ∫  f(x) / (x - c) dx
+//                      a
+//
+func QuadCauchy(a, b, c float64, fid int, f func(x float64) float64) (res float64) {
+	if c == a || c == b {
+		chk.Panic("singular point c = %g must not coincide with the limits of integration a = %g and b = %g", c, a, b)
+	}
+	id := int32(fid)
+	res, _, _, _ = qpck.Awce(id, f, a, b, c, 0, 0, nil, nil, nil, nil, nil)
+	return
+}
+
+// QuadAlgLog performs automatic integration (quadrature) of functions with algebraico-logarithmic
+// end-point singularities using the QUADPACK routine AWSE (Automatic with weight, end-point singularities)
+//
+//   INPUT:
+//     a      -- lower limit of integration
+//     b      -- upper limit of integration; b > a
+//     α      -- exponent of (x-a); α > -1
+//     β      -- exponent of (b-x); β > -1
+//     logA   -- multiply the weight by log(x-a)
+//     logB   -- multiply the weight by log(b-x)
+//     fid    -- index of goroutine (to avoid race problems)
+//     f      -- function defining the integrand
+//
+//   OUTPUT:          b
+//             res = ∫  f(x) ⋅ (x-a)^α ⋅ (b-x)^β ⋅ v(x) dx
+//                   a
+//
+//          where v(x) = 1, log(x-a), log(b-x) or log(x-a)⋅log(b-x) depending on logA and logB
+//
+func QuadAlgLog(a, b, α, β float64, logA, logB bool, fid int, f func(x float64) float64) (res float64) {
+	if b <= a || α <= -1 || β <= -1 {
+		chk.Panic("QuadAlgLog requires b > a, α > -1 and β > -1. a = %g, b = %g, α = %g and β = %g are invalid", a, b, α, β)
+	}
+	id := int32(fid)
+	integr := int32(1) // (x-a)^α ⋅ (b-x)^β
+	switch {
+	case logA && logB:
+		integr = 4
+	case logA:
+		integr = 2
+	case logB:
+		integr = 3
+	}
+	res, _, _, _ = qpck.Awse(id, f, a, b, α, β, integr, 0, 0, nil, nil, nil, nil, nil)
+	return
+}
+
+// QuadFourier computes the Fourier cosine or sine integral over a semi-infinite interval using the
+// QUADPACK routine AWFE (Automatic with weight, Fourier)
+//
+//   INPUT:
+//     a      -- lower limit of integration
+//     ω      -- omega
+//     useSin -- use sin(ω⋅x) instead of cos(ω⋅x)
+//     fid    -- index of goroutine (to avoid race problems)
+//     f      -- function defining the integrand
+//
+//   OUTPUT:          ∞                                     ∞
+//             res = ∫  f(x) ⋅ cos(ω⋅x) dx     or    res = ∫ f(x) ⋅ sin(ω⋅x) dx
+//                   a                                     a
+//
+func QuadFourier(a, ω float64, useSin bool, fid int, f func(x float64) float64) (res float64) {
+	id := int32(fid)
+	cs := int32(1) // cos
+	if useSin {
+		cs = 2 // sin
+	}
+	res, _, _, _ = qpck.Awfe(id, f, a, ω, cs, 0, 0, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	return
+}
@@ -0,0 +1,121 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestContinuation01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation01. fold: x - λ exp(x) = 0")
+
+	ffcn := func(f, x la.Vector, λ float64) {
+		f[0] = x[0] - λ*math.Exp(x[0])
+	}
+	JxFcn := func(J *la.Matrix, x la.Vector, λ float64) {
+		J.Set(0, 0, 1-λ*math.Exp(x[0]))
+	}
+	JlFcn := func(Fl, x la.Vector, λ float64) {
+		Fl[0] = -math.Exp(x[0])
+	}
+
+	prms := map[string]float64{"ds": 0.1, "dsMax": 0.4, "lamMin": 0.05, "maxSteps": 100}
+	for _, analytical := range []bool{true, false} {
+		var cont Continuation
+		if analytical {
+			cont.Init(1, ffcn, JxFcn, JlFcn, prms)
+		} else {
+			cont.Init(1, ffcn, nil, nil, prms)
+		}
+		cont.Trace([]float64{0.1}, 0.1, !chk.Verbose)
+
+		// all points are on the branch λ = x exp(-x)
+		io.Pforan("analytical = %v: nsteps = %d  λfinal = %g\n", analytical, cont.Nsteps, cont.Table["lambda"][cont.Nsteps])
+		for i, λ := range cont.Table["lambda"] {
+			x := cont.Table["x0"][i]
+			chk.Float64(tst, "λ", 1e-10, λ, x*math.Exp(-x))
+		}
+		if cont.Table["x0"][cont.Nsteps] < 2 {
+			tst.Errorf("branch must be traced beyond the turning point\n")
+		}
+
+		// turning point at λ = 1/e, x = 1
+		chk.Int(tst, "number of special points", len(cont.Points), 1)
+		p := cont.Points[0]
+		io.Pforan("%s point: λ = %v  x = %v\n", p.Kind, p.Lambda, p.X)
+		chk.String(tst, p.Kind, "turning")
+		chk.Float64(tst, "λ @ turning point", 1e-10, p.Lambda, 1/math.E)
+		chk.Float64(tst, "x @ turning point", 1e-6, p.X[0], 1)
+		if cont.Table["tlam"][p.Step-1]*cont.Table["tlam"][p.Step] >= 0 {
+			tst.Errorf("tλ must change sign across the turning point\n")
+		}
+	}
+}
+
+func TestContinuation02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation02. pitchfork: x³ - λ x = 0")
+
+	ffcn := func(f, x la.Vector, λ float64) {
+		f[0] = x[0]*x[0]*x[0] - λ*x[0]
+	}
+
+	var cont Continuation
+	cont.Init(1, ffcn, nil, nil, map[string]float64{"ds": 0.15, "lamMax": 1})
+	cont.Trace([]float64{0}, -1, !chk.Verbose)
+
+	// trivial branch
+	chk.Array(tst, "x", 1e-15, cont.Table["x0"], nil)
+	chk.Float64(tst, "λ final", 1e-15, math.Max(cont.Table["lambda"][cont.Nsteps], 1), cont.Table["lambda"][cont.Nsteps])
+
+	// bifurcation at λ = 0, x = 0
+	chk.Int(tst, "number of special points", len(cont.Points), 1)
+	p := cont.Points[0]
+	io.Pforan("%s point: λ = %v  x = %v\n", p.Kind, p.Lambda, p.X)
+	chk.String(tst, p.Kind, "bifurcation")
+	chk.Float64(tst, "λ @ bifurcation point", 1e-8, p.Lambda, 0)
+	chk.Float64(tst, "x @ bifurcation point", 1e-15, p.X[0], 0)
+
+	// table
+	cont.WriteTable("/tmp/gosl/num", "continuation02.res")
+	keys, T := io.ReadTable("/tmp/gosl/num/continuation02.res")
+	chk.Strings(tst, "keys", keys, []string{"s", "lambda", "x0", "tlam", "det", "nit"})
+	chk.Array(tst, "λ (file)", 1e-14, T["lambda"], cont.Table["lambda"])
+	chk.Array(tst, "det (file)", 1e-14, T["det"], cont.Table["det"])
+}
+
+func TestContinuation03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Continuation03. two equations with two turning points")
+
+	// x0 = x1 and x1³ - 3 x1 - λ = 0 ⇒ turning points at (x,λ) = (±1, ∓2)
+	ffcn := func(f, x la.Vector, λ float64) {
+		f[0] = x[0] - x[1]
+		f[1] = x[1]*x[1]*x[1] - 3*x[1] - λ
+	}
+
+	var cont Continuation
+	cont.Init(2, ffcn, nil, nil, map[string]float64{"ds": 0.2, "lamMin": -4, "lamMax": 4})
+	cont.Trace([]float64{-2, -2}, -2, !chk.Verbose)
+
+	chk.Int(tst, "number of special points", len(cont.Points), 2)
+	xcor := []float64{-1, 1}
+	lcor := []float64{2, -2}
+	for k, p := range cont.Points {
+		io.Pforan("%s point: λ = %v  x = %v\n", p.Kind, p.Lambda, p.X)
+		chk.String(tst, p.Kind, "turning")
+		chk.Float64(tst, "λ @ turning point", 1e-10, p.Lambda, lcor[k])
+		chk.Array(tst, "x @ turning point", 1e-5, p.X, []float64{xcor[k], xcor[k]})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestCubGenzMalik01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("CubGenzMalik01. polynomials and smooth functions")
+
+	// degree-7 polynomial: exact with one region
+	f := func(x la.Vector) float64 { return 1 + x[0]*x[0]*x[0]*x[1]*x[1]*x[2]*x[2] }
+	res, err, nfeval := CubGenzMalik(f, []float64{0, 0, 0}, []float64{1, 1, 1}, 1e-10, 1e-10, 10000)
+	io.Pforan("polynomial: res = %v  err = %v  nfeval = %d\n", res, err, nfeval)
+	chk.Float64(tst, "∫ 1 + x³y²z²", 1e-14, res, 1+1.0/36.0)
+
+	// 1D
+	res, err, nfeval = CubGenzMalik(func(x la.Vector) float64 { return math.Sin(x[0]) }, []float64{0}, []float64{math.Pi}, 1e-12, 1e-12, 10000)
+	io.Pforan("1D: res = %v  err = %v  nfeval = %d\n", res, err, nfeval)
+	chk.Float64(tst, "∫ sin", 1e-12, res, 2)
+
+	// Genz oscillatory function in 4D (the error estimate is conservative)
+	c := []float64{0.9, 1.3, 0.7, 1.1}
+	u := 0.3
+	g := func(x la.Vector) float64 {
+		s := 2 * math.Pi * u
+		for i := range x {
+			s += c[i] * x[i]
+		}
+		return math.Cos(s)
+	}
+	z := cmplx.Exp(complex(0, 2*math.Pi*u))
+	for _, ci := range c {
+		z *= (cmplx.Exp(complex(0, ci)) - 1) / complex(0, ci)
+	}
+	res, err, nfeval = CubGenzMalik(g, []float64{0, 0, 0, 0}, []float64{1, 1, 1, 1}, 1e-7, 1e-7, 200000)
+	io.Pforan("oscillatory: res = %v  err = %v  nfeval = %d  (correct = %v)\n", res, err, nfeval, real(z))
+	chk.Float64(tst, "oscillatory", 1e-9, res, real(z))
+	if err > 1e-7 {
+		tst.Errorf("error estimate is too large: %g\n", err)
+	}
+
+	// maximum number of evaluations
+	_, err, nfeval = CubGenzMalik(g, []float64{0, 0, 0, 0}, []float64{1, 1, 1, 1}, 1e-15, 1e-15, 1000)
+	if nfeval > 1000 || err < 1e-15 {
+		tst.Errorf("maxEval must be respected: nfeval = %d  err = %g\n", nfeval, err)
+	}
+}
+
+func TestCubGenzMalik02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("CubGenzMalik02. infinite domains")
+
+	gauss := func(x la.Vector) float64 { return math.Exp(-la.VecDot(x, x)) }
+	inf := math.Inf(1)
+
+	res, err, nfeval := CubGenzMalik(gauss, []float64{-inf, -inf}, []float64{inf, inf}, 1e-10, 1e-10, 200000)
+	io.Pforan("R²: res = %v  err = %v  nfeval = %d\n", res, err, nfeval)
+	chk.Float64(tst, "∫ exp(-|x|²) over R²", 1e-9, res, math.Pi)
+
+	res, err, nfeval = CubGenzMalik(gauss, []float64{0, -inf, -1}, []float64{inf, 0, 1}, 1e-10, 1e-10, 500000)
+	cor := math.Pow(math.Sqrt(math.Pi)/2, 2) * math.Sqrt(math.Pi) * math.Erf(1)
+	io.Pforan("[0,∞)×(-∞,0]×[-1,1]: res = %v  err = %v  nfeval = %d\n", res, err, nfeval)
+	chk.Float64(tst, "∫ exp(-|x|²) over [0,∞)×(-∞,0]×[-1,1]", 1e-9, res, cor)
+}
+
+func TestCubSimplex01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("CubSimplex01. triangles and tetrahedra")
+
+	one := func(x la.Vector) float64 { return 1 }
+	xy := func(x la.Vector) float64 { return x[0] * x[1] }
+	xx := func(x la.Vector) float64 { return x[0] * x[0] }
+
+	tri := [][]float64{{0, 0}, {1, 0}, {0, 1}}
+	res, _, _ := CubSimplex(one, tri, 1e-12, 1e-12, 10000)
+	chk.Float64(tst, "area", 1e-15, res, 0.5)
+	res, _, _ = CubSimplex(xy, tri, 1e-12, 1e-12, 10000)
+	chk.Float64(tst, "∫ x y over triangle", 1e-15, res, 1.0/24.0)
+
+	tri = [][]float64{{1, 1}, {4, 2}, {2, 5}}
+	res, _, _ = CubSimplex(one, tri, 1e-12, 1e-12, 10000)
+	chk.Float64(tst, "area (general)", 1e-14, res, 5.5)
+
+	tet := [][]float64{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	res, _, _ = CubSimplex(one, tet, 1e-12, 1e-12, 10000)
+	chk.Float64(tst, "volume", 1e-15, res, 1.0/6.0)
+	res, _, _ = CubSimplex(xx, tet, 1e-12, 1e-12, 10000)
+	chk.Float64(tst, "∫ x² over tetrahedron", 1e-15, res, 1.0/60.0)
+
+	res, err, nfeval := CubSimplex(func(x la.Vector) float64 { return math.Exp(x[0] + x[1]) }, [][]float64{{0, 0}, {1, 0}, {0, 1}}, 1e-12, 1e-12, 100000)
+	io.Pforan("∫ exp(x+y): res = %v  err = %v  nfeval = %d\n", res, err, nfeval)
+	chk.Float64(tst, "∫ exp(x+y) over triangle", 1e-12, res, 1)
+}
@@ -0,0 +1,62 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+)
+
+func TestCubQmc01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("CubQmc01. quasi-Monte Carlo integration")
+
+	// product function in 8D
+	n := 8
+	a, b := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		a[i], b[i] = -1, 2
+	}
+	f := func(x la.Vector) float64 {
+		res := 1.0
+		for _, xi := range x {
+			res *= 1 + 0.5*xi*xi
+		}
+		return res
+	}
+	cor := math.Pow(3+0.5*3, float64(n))
+	rnd.Init(1234)
+	var errPrev float64
+	for _, npts := range []int{100, 1000, 10000} {
+		res, err, nfeval := CubQmc(f, a, b, npts, 10)
+		io.Pforan("npts = %5d  res = %v  err = %.3e  (true error = %.3e)  nfeval = %d\n", npts, res, err, math.Abs(res-cor), nfeval)
+		if math.Abs(res-cor) > 5*err {
+			tst.Errorf("true error must be within 5 standard errors\n")
+		}
+		if npts > 100 && err > errPrev {
+			tst.Errorf("error estimate must decrease\n")
+		}
+		errPrev = err
+	}
+
+	// same seed ⇒ same result
+	rnd.Init(7)
+	r1, e1, _ := CubQmc(f, a, b, 100, 4)
+	rnd.Init(7)
+	r2, e2, _ := CubQmc(f, a, b, 100, 4)
+	chk.Array(tst, "same seed", 1e-17, []float64{r1, e1}, []float64{r2, e2})
+
+	// infinite domain
+	inf := math.Inf(1)
+	res, err, _ := CubQmc(func(x la.Vector) float64 { return math.Exp(-la.VecDot(x, x)) }, []float64{-inf, -inf, -inf}, []float64{inf, inf, inf}, 20000, 10)
+	io.Pforan("∫ exp(-|x|²) over R³: res = %v  err = %v\n", res, err)
+	chk.Float64(tst, "∫ exp(-|x|²) over R³", 5*err, res, math.Pow(math.Pi, 1.5))
+}
@@ -0,0 +1,81 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestSparseGrid01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("SparseGrid01. points, weights and exactness")
+
+	// level 1 in 2D: 3-point rules along each axis with merged center
+	X, W := SparseGridXW([]float64{-1, -1}, []float64{1, 1}, 1)
+	io.Pforan("X = %v\nW = %v\n", X, W)
+	chk.Int(tst, "npts", len(X), 5)
+	chk.Float64(tst, "Σw", 1e-14, la.Vector(W).Accum(), 4)
+
+	// exactness for total degree 2⋅level+1
+	a, b := []float64{0, -1, 0.5}, []float64{1, 2, 1.5}
+	for level := 0; level <= 4; level++ {
+		X, W = SparseGridXW(a, b, level)
+		deg := 2*level + 1
+		for _, p := range [][]int{{deg, 0, 0}, {0, deg, 0}, {1, deg - 1, 0}, {0, level, level + 1}, {level, 1, level}} {
+			if p[0]+p[1]+p[2] > deg {
+				continue
+			}
+			res := 0.0
+			for k, x := range X {
+				res += W[k] * math.Pow(x[0], float64(p[0])) * math.Pow(x[1], float64(p[1])) * math.Pow(x[2], float64(p[2]))
+			}
+			cor := 1.0
+			for i := 0; i < 3; i++ {
+				q := float64(p[i] + 1)
+				cor *= (math.Pow(b[i], q) - math.Pow(a[i], q)) / q
+			}
+			chk.Float64(tst, io.Sf("level %d: ∫ x^%d y^%d z^%d", level, p[0], p[1], p[2]), 1e-14*math.Max(1, math.Abs(cor)), res, cor)
+		}
+		io.Pforan("level = %d  npts = %d\n", level, len(X))
+	}
+}
+
+func TestCubSparseGrid01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("CubSparseGrid01. smooth functions")
+
+	// product of exponentials in 6D
+	n := 6
+	a, b := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		b[i] = 1
+	}
+	f := func(x la.Vector) float64 { return math.Exp(x.Accum() / float64(n)) }
+	cor := math.Pow(float64(n)*(math.Exp(1/float64(n))-1), float64(n))
+	var errPrev float64
+	for level := 1; level <= 4; level++ {
+		res, err, nfeval := CubSparseGrid(f, a, b, level)
+		io.Pforan("level = %d  res = %v  err = %.2e  (true error = %.2e)  nfeval = %d\n", level, res, err, math.Abs(res-cor), nfeval)
+		if level > 1 && err > errPrev {
+			tst.Errorf("error estimate must decrease\n")
+		}
+		errPrev = err
+	}
+	res, _, _ := CubSparseGrid(f, a, b, 4)
+	chk.Float64(tst, "∫ exp(Σx/n)", 1e-13, res, cor)
+
+	// infinite domain
+	inf := math.Inf(1)
+	res, err, _ := CubSparseGrid(func(x la.Vector) float64 { return math.Exp(-x[0] - 2*x[1]) }, []float64{0, 0}, []float64{inf, inf}, 8)
+	io.Pforan("∫ exp(-x-2y) over quadrant: res = %v  err = %v\n", res, err)
+	chk.Float64(tst, "∫ exp(-x-2y) over quadrant", 1e-5, res, 0.5)
+}
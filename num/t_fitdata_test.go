@@ -5,6 +5,7 @@
 package num
 
 import (
+	"math"
 	"testing"
 
 	"github.com/cpmech/gosl/chk"
@@ -69,3 +70,31 @@ func TestLinFit02(tst *testing.T) {
 		plt.Save("/tmp/gosl/num", "linfit02")
 	}
 }
+
+func TestPolyFit01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("PolyFit01")
+
+	// exact cubic
+	x := utl.LinSpace(-1, 3, 9)
+	y := utl.GetMapped(x, func(x float64) float64 { return 1 - 2*x + 0.5*x*x*x })
+	c := PolyFit(x, y, 3)
+	io.Pforan("c = %v\n", c)
+	chk.Array(tst, "c", 1e-14, c, []float64{1, -2, 0, 0.5})
+
+	// straight line: compare with LinFitSigma
+	x = []float64{1, 2, 3, 4, 5, 6}
+	y = []float64{6, 5, 7, 10, 11, 13}
+	a, b, σa, σb, χ2 := LinFitSigma(x, y)
+	c, σc, χ2p := PolyFitSigma(x, y, nil, 1)
+	io.Pforan("c = %v  σc = %v  χ2 = %v\n", c, σc, χ2p)
+	chk.Array(tst, "c (line)", 1e-14, c, []float64{a, b})
+	chk.Array(tst, "σc (line)", 1e-14, σc, []float64{σa, σb})
+	chk.Float64(tst, "χ2", 1e-13, χ2p, χ2)
+
+	// weighted fit: σ = 1 ⇒ unscaled standard errors
+	σ := []float64{1, 1, 1, 1, 1, 1}
+	_, σc1, _ := PolyFitSigma(x, y, σ, 1)
+	chk.Array(tst, "σc (σ=1)", 1e-14, σc1, []float64{σa / math.Sqrt(χ2/4), σb / math.Sqrt(χ2/4)})
+}
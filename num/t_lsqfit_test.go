@@ -0,0 +1,123 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+	"github.com/cpmech/gosl/utl"
+)
+
+func TestLsqFit01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LsqFit01. straight line (compare with LinFitSigma)")
+
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := []float64{6, 5, 7, 10, 11, 13}
+	a, b, σa, σb, χ2 := LinFitSigma(x, y)
+
+	model := func(x float64, p la.Vector) float64 { return p[0] + p[1]*x }
+	for _, method := range []string{"lm", "gn"} {
+		var fit LsqFit
+		fit.Init(2, model, nil, nil)
+		fit.Method = method
+		fit.Fit(x, y, nil, []float64{0, 0}, chk.Verbose)
+		io.Pforan("%s: p = %v  σ = %v  χ² = %v  it = %d\n", method, fit.P, fit.Sigma, fit.Chi2, fit.It)
+		chk.Array(tst, "p", 1e-9, fit.P, []float64{a, b})
+		chk.Array(tst, "σ", 1e-8, fit.Sigma, []float64{σa, σb})
+		chk.Float64(tst, "χ²", 1e-9, fit.Chi2, χ2)
+		chk.Int(tst, "dof", fit.Dof, 4)
+		chk.Float64(tst, "reduced χ²", 1e-9, fit.RedChi2, χ2/4)
+	}
+}
+
+func TestLsqFit02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LsqFit02. exponential decay with noise and weights")
+
+	// data: y = a exp(-b x) + c
+	pcor := []float64{5, 1.3, 0.5}
+	model := func(x float64, p la.Vector) float64 { return p[0]*math.Exp(-p[1]*x) + p[2] }
+	deriv := func(dfdp la.Vector, x float64, p la.Vector) {
+		dfdp[0] = math.Exp(-p[1] * x)
+		dfdp[1] = -p[0] * x * math.Exp(-p[1]*x)
+		dfdp[2] = 1
+	}
+	rnd.Init(1234)
+	x := utl.LinSpace(0, 4, 41)
+	y := make([]float64, len(x))
+	σ := make([]float64, len(x))
+	for i := range x {
+		σ[i] = 0.02 + 0.01*x[i]
+		y[i] = model(x[i], pcor) + rnd.Normal(0, σ[i])
+	}
+
+	// fit with analytical and numerical derivatives and both methods
+	var results []la.Vector
+	for _, method := range []string{"lm", "gn"} {
+		for _, d := range []func(la.Vector, float64, la.Vector){deriv, nil} {
+			var fit LsqFit
+			fit.Init(3, model, d, nil)
+			fit.Method = method
+			fit.Fit(x, y, σ, []float64{1, 0.5, 0}, chk.Verbose)
+			io.Pforan("%s (analytical = %v): p = %v  σ = %v  χ²/dof = %v  it = %d  nfeval = %d\n", method, d != nil, fit.P, fit.Sigma, fit.RedChi2, fit.It, fit.NFeval)
+			for j := 0; j < 3; j++ {
+				if math.Abs(fit.P[j]-pcor[j]) > 3*fit.Sigma[j] {
+					tst.Errorf("p[%d] = %g is not within 3σ = %g of %g\n", j, fit.P[j], 3*fit.Sigma[j], pcor[j])
+				}
+			}
+			if fit.RedChi2 < 0.5 || fit.RedChi2 > 1.5 {
+				tst.Errorf("reduced χ² = %g should be close to 1\n", fit.RedChi2)
+			}
+			chk.Float64(tst, "cov symmetric", 1e-15, fit.Cov.Get(0, 1), fit.Cov.Get(1, 0))
+			results = append(results, fit.P.GetCopy())
+		}
+	}
+	for k := 1; k < len(results); k++ {
+		chk.Array(tst, "same solution", 1e-6, results[k], results[0])
+	}
+}
+
+func TestLsqFit03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LsqFit03. bounds")
+
+	// data: y = a x^b with b = 0.5 ⇒ fit with b ≤ 0.4
+	model := func(x float64, p la.Vector) float64 { return p[0] * math.Pow(x, p[1]) }
+	x := utl.LinSpace(0.1, 2, 20)
+	y := utl.GetMapped(x, func(x float64) float64 { return 2 * math.Sqrt(x) })
+
+	// unconstrained
+	var fit LsqFit
+	fit.Init(2, model, nil, nil)
+	fit.Fit(x, y, nil, []float64{1, 1}, chk.Verbose)
+	chk.Array(tst, "p (unconstrained)", 1e-8, fit.P, []float64{2, 0.5})
+	chk.Float64(tst, "χ² (unconstrained)", 1e-15, fit.Chi2, 0)
+
+	// constrained
+	for _, method := range []string{"lm", "gn"} {
+		fit.Method = method
+		fit.SetBounds([]float64{0, 0}, []float64{math.Inf(1), 0.4})
+		fit.Fit(x, y, nil, []float64{1, 0.1}, chk.Verbose)
+		io.Pforan("%s: p = %v  χ² = %v  it = %d\n", method, fit.P, fit.Chi2, fit.It)
+		chk.Float64(tst, "b at upper bound", 1e-15, fit.P[1], 0.4)
+
+		// a is optimal for fixed b: a = Σ y x^b / Σ x^2b
+		num, den := 0.0, 0.0
+		for i := range x {
+			num += y[i] * math.Pow(x[i], 0.4)
+			den += math.Pow(x[i], 0.8)
+		}
+		chk.Float64(tst, "a", 1e-8, fit.P[0], num/den)
+	}
+}
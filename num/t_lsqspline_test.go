@@ -0,0 +1,74 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/utl"
+)
+
+func TestLsqSpline01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LsqSpline01. cubic polynomial is reproduced")
+
+	f := func(x float64) float64 { return 1 - 2*x + 0.5*x*x*x }
+	g := func(x float64) float64 { return -2 + 1.5*x*x }
+	x := utl.LinSpace(-1, 3, 21)
+	y := utl.GetMapped(x, f)
+	sp := NewLsqSpline(3, []float64{-1, 0, 0.5, 2, 3})
+	sp.Fit(x, y, nil)
+	io.Pforan("knots = %v\n", sp.Knots)
+	io.Pforan("c = %v  χ² = %v\n", sp.C, sp.Chi2)
+	chk.Int(tst, "number of coefficients", len(sp.C), 7)
+	chk.Float64(tst, "χ²", 1e-20, sp.Chi2, 0)
+	for _, xx := range utl.LinSpace(-1, 3, 17) {
+		chk.Float64(tst, io.Sf("s(%g)", xx), 1e-13, sp.F(xx), f(xx))
+		chk.Float64(tst, io.Sf("s'(%g)", xx), 1e-12, sp.G(xx), g(xx))
+	}
+}
+
+func TestLsqSpline02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LsqSpline02. smoothing of data")
+
+	// data with pseudo-noise
+	x := utl.LinSpace(0, 2*math.Pi, 101)
+	y := make([]float64, len(x))
+	for i := range x {
+		y[i] = math.Sin(x[i]) + 0.01*math.Sin(37*float64(i))
+	}
+
+	// fit
+	sp := NewLsqSpline(3, utl.LinSpace(0, 2*math.Pi, 9))
+	sp.Fit(x, y, nil)
+	io.Pforan("χ² = %v  σ = %v\n", sp.Chi2, sp.Sigma)
+	maxerr := 0.0
+	for _, xx := range utl.LinSpace(0, 2*math.Pi, 50) {
+		maxerr = math.Max(maxerr, math.Abs(sp.F(xx)-math.Sin(xx)))
+	}
+	io.Pforan("max error = %v\n", maxerr)
+	if maxerr > 1e-2 {
+		tst.Errorf("max error is too large: %g\n", maxerr)
+	}
+	for j, s := range sp.Sigma {
+		if s <= 0 || s > 0.01 {
+			tst.Errorf("σ[%d] = %g is incorrect\n", j, s)
+		}
+	}
+
+	// linear spline interpolates data at the breakpoints
+	sp = NewLsqSpline(1, x)
+	sp.Fit(x, y, nil)
+	chk.Float64(tst, "χ² (linear)", 1e-20, sp.Chi2, 0)
+	for i := range x {
+		chk.Float64(tst, "s(xi)", 1e-14, sp.F(x[i]), y[i])
+	}
+}
@@ -273,3 +273,44 @@ func Test_nls03(tst *testing.T) {
 	io.Pf("f(x) = %v << converges to a different solution\n", fx)
 	chk.Array(tst, "f(x) = 0? ", 1e-8, fx, nil)
 }
+
+func Test_nls04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("nls04. dogleg and Levenberg-Marquardt methods")
+
+	// Powell's badly scaled function
+	ffcn := func(fx, x la.Vector) {
+		fx[0] = 1e4*x[0]*x[1] - 1.0
+		fx[1] = math.Exp(-x[0]) + math.Exp(-x[1]) - 1.0001
+	}
+	JfcnD := func(dfdx *la.Matrix, x la.Vector) {
+		dfdx.Set(0, 0, 1e4*x[1])
+		dfdx.Set(0, 1, 1e4*x[0])
+		dfdx.Set(1, 0, -math.Exp(-x[0]))
+		dfdx.Set(1, 1, -math.Exp(-x[1]))
+	}
+	xcor := []float64{1.09815932969975976e-05, 9.10614673986652401}
+
+	fx := make([]float64, 2)
+	for _, method := range []string{"dogleg", "levMar"} {
+		for _, useDn := range []bool{true, false} {
+			prms := map[string]float64{
+				"atol":  1e-12,
+				"rtol":  1e-12,
+				"ftol":  1e-12,
+				"maxIt": 200,
+				method:  1,
+			}
+			var nls NlSolver
+			nls.Init(2, ffcn, nil, JfcnD, useDn, !useDn, prms)
+			x := []float64{0, 1}
+			nls.Solve(x, !chk.Verbose)
+			nls.Free()
+			ffcn(fx, x)
+			io.Pforan("%s (useDn=%v): x = %v  nit = %d  nFeval = %d  nJeval = %d\n", method, useDn, x, nls.It, nls.NFeval, nls.NJeval)
+			chk.Array(tst, "f(x) = 0? ", 1e-12, fx, nil)
+			chk.Array(tst, "x", 1e-9, x, xcor)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package num
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+)
+
+func TestQuadTanhSinh01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadTanhSinh01. finite intervals with end-point singularities")
+
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a, b float64
+		ref  float64
+	}{
+		{"x²", func(x float64) float64 { return x * x }, 0, 1, 1.0 / 3.0},
+		{"x² (reversed)", func(x float64) float64 { return x * x }, 1, 0, -1.0 / 3.0},
+		{"1/√x", func(x float64) float64 { return 1.0 / math.Sqrt(x) }, 0, 1, 2.0},
+		{"log(x)", func(x float64) float64 { return math.Log(x) }, 0, 1, -1.0},
+		{"x^(-0.9)", func(x float64) float64 { return math.Pow(x, -0.9) }, 0, 1, 10.0},
+		{"1/√(-x)", func(x float64) float64 { return 1.0 / math.Sqrt(-x) }, -4, 0, 4.0},
+		{"√(1+sin³(x))", func(x float64) float64 { return math.Sqrt(1.0 + math.Pow(math.Sin(x), 3.0)) }, 0, 1, 1.08268158558},
+	}
+	for _, t := range tests {
+		res, err, nfeval := QuadTanhSinh(t.f, t.a, t.b, 1e-14, 1e-14, 0)
+		io.Pforan("%-14s: res = %23.16e  err = %8.2e  nfeval = %d\n", t.name, res, err, nfeval)
+		tol := 1e-14
+		if t.name == "√(1+sin³(x))" {
+			tol = 1e-11 // reference has 12 digits only
+		}
+		chk.Float64(tst, t.name, tol, res, t.ref)
+	}
+}
+
+func TestQuadTanhSinh02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadTanhSinh02. infinite intervals")
+
+	inf := math.Inf(1)
+	tests := []struct {
+		name string
+		f    func(x float64) float64
+		a, b float64
+		ref  float64
+		tol  float64
+	}{
+		{"exp(-x)", func(x float64) float64 { return math.Exp(-x) }, 0, inf, 1.0, 1e-14},
+		{"exp(x)", func(x float64) float64 { return math.Exp(x) }, -inf, 0, 1.0, 1e-14},
+		{"exp(-x²)", func(x float64) float64 { return math.Exp(-x * x) }, -inf, inf, math.Sqrt(math.Pi), 1e-14},
+		{"1/(1+x²)", func(x float64) float64 { return 1.0 / (1.0 + x*x) }, 0, inf, math.Pi / 2.0, 1e-12},
+		{"-exp(-x)⋅log(x)", func(x float64) float64 { return -math.Exp(-x) * math.Log(x) }, 0, inf, 0.577215664901532860606512, 1e-13},
+	}
+	for _, t := range tests {
+		res, err, nfeval := QuadTanhSinh(t.f, t.a, t.b, 1e-14, 1e-14, 0)
+		io.Pforan("%-16s: res = %23.16e  err = %8.2e  nfeval = %d\n", t.name, res, err, nfeval)
+		chk.Float64(tst, t.name, t.tol, res, t.ref)
+	}
+}
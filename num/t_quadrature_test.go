@@ -83,3 +83,85 @@ func TestQuadExpIx02(tst *testing.T) {
 
 	chk.AnaNumC(tst, "I", 1e-15, I, Iana, chk.Verbose)
 }
+
+func TestQuadInf01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadInf01. infinite intervals using QUADPACK")
+
+	inf := math.Inf(1)
+	A := QuadInf(0, inf, 0, func(x float64) float64 { return 1.0 / (1.0 + x*x) })
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫_0^∞ 1/(1+x²)", 1e-12, A, math.Pi/2.0)
+
+	A = QuadInf(-inf, 0, 0, func(x float64) float64 { return math.Exp(x) })
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫_-∞^0 exp(x)", 1e-12, A, 1.0)
+
+	A = QuadInf(-inf, inf, 0, func(x float64) float64 { return math.Exp(-x * x) })
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫_-∞^∞ exp(-x²)", 1e-12, A, math.Sqrt(math.Pi))
+}
+
+func TestQuadCauchy01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadCauchy01. Cauchy principal value using QUADPACK")
+
+	// PV ∫ x²/(x-1) dx = ∫ (x + 1) dx + PV ∫ 1/(x-1) dx
+	A := QuadCauchy(0, 3, 1, 0, func(x float64) float64 { return x * x })
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "PV ∫_0^3 x²/(x-1)", 1e-12, A, 7.5+math.Log(2.0))
+
+	A = QuadCauchy(0, 2, 1, 0, func(x float64) float64 { return 1.0 })
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "PV ∫_0^2 1/(x-1)", 1e-12, A, 0.0)
+}
+
+func TestQuadAlgLog01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadAlgLog01. algebraico-logarithmic singularities using QUADPACK")
+
+	one := func(x float64) float64 { return 1.0 }
+
+	// beta function B(½, ½) = π
+	A := QuadAlgLog(0, 1, -0.5, -0.5, false, false, 0, one)
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫ x^(-½)⋅(1-x)^(-½)", 1e-12, A, math.Pi)
+
+	// B(3/2, ½) = π/2
+	A = QuadAlgLog(0, 1, -0.5, -0.5, false, false, 0, func(x float64) float64 { return x })
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫ x⋅x^(-½)⋅(1-x)^(-½)", 1e-12, A, math.Pi/2.0)
+
+	// ∫ x^(-½)⋅log(x) dx = -4
+	A = QuadAlgLog(0, 1, -0.5, 0, true, false, 0, one)
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫ x^(-½)⋅log(x)", 1e-12, A, -4.0)
+
+	// ∫ log(1-x) dx = -1
+	A = QuadAlgLog(0, 1, 0, 0, false, true, 0, one)
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫ log(1-x)", 1e-12, A, -1.0)
+
+	// ∫ log(x)⋅log(1-x) dx = 2 - π²/6
+	A = QuadAlgLog(0, 1, 0, 0, true, true, 0, one)
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫ log(x)⋅log(1-x)", 1e-12, A, 2.0-math.Pi*math.Pi/6.0)
+}
+
+func TestQuadFourier01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("QuadFourier01. Fourier integrals using QUADPACK")
+
+	f := func(x float64) float64 { return math.Exp(-x) }
+	A := QuadFourier(0, 1, false, 0, f)
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫_0^∞ exp(-x)⋅cos(x)", 1e-10, A, 0.5)
+
+	A = QuadFourier(0, 2, true, 0, f)
+	io.Pforan("A  = %v\n", A)
+	chk.Float64(tst, "∫_0^∞ exp(-x)⋅sin(2x)", 1e-10, A, 0.4)
+}
@@ -115,7 +115,7 @@ func (o *ExplicitRK) Init(ndim int, conf *Config, work *rkwork, stat *Stat, fcn
 	o.ndf = float64(ndim)
 
 	// dense output
-	if o.conf.denseOut {
+	if o.conf.denseOut || o.conf.denseF != nil {
 		if o.do == nil {
 			chk.Panic("dense output is not available for %q\n", o.conf.method)
 		}
@@ -135,7 +135,7 @@ func (o *ExplicitRK) Init(ndim int, conf *Config, work *rkwork, stat *Stat, fcn
 func (o *ExplicitRK) Accept(y0 la.Vector, x0 float64) (dxnew float64) {
 
 	// store data for future dense output
-	if o.conf.denseOut {
+	if o.conf.denseOut || o.conf.denseF != nil {
 		if o.dfunA != nil {
 			o.dfunA(y0, x0)
 		}
@@ -132,9 +132,10 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 	// first scaling variable
 	la.VecScaleAbs(o.work.scal, o.conf.atol, o.conf.rtol, y) // scal = atol + rtol * abs(y)
 
-	// make sure that final x is equal to xf in the end
+	// make sure that final x is equal to xf in the end (unless stopped by the output function)
+	var stopped bool
 	defer func() {
-		if math.Abs(x-xf) > 1e-15 {
+		if !stopped && math.Abs(x-xf) > 1e-15 {
 			chk.Panic("internal error: x must be equal to xf in the end. x-xf=%v\n", x-xf)
 		}
 	}()
@@ -157,8 +158,8 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 			x = float64(n+1) * o.work.h
 			o.rkm.Accept(y, x)
 			if o.Out != nil {
-				stop := o.Out.execute(istep, false, o.work.rs, o.work.h, x, y)
-				if stop {
+				stopped = o.Out.execute(istep, false, o.work.rs, o.work.h, x, y)
+				if stopped {
 					return
 				}
 			}
@@ -259,8 +260,8 @@ func (o *Solver) Solve(y la.Vector, x, xf float64) {
 
 				// output
 				if o.Out != nil {
-					stop := o.Out.execute(o.Stat.Naccepted, last, o.work.rs, o.work.h, x, y)
-					if stop {
+					stopped = o.Out.execute(o.Stat.Naccepted, last, o.work.rs, o.work.h, x, y)
+					if stopped {
 						return
 					}
 				}
@@ -5,11 +5,13 @@
 package ode
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/cpmech/gosl/chk"
 	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
 	"github.com/cpmech/gosl/plt"
 )
 
@@ -275,3 +277,61 @@ func TestOde04(tst *testing.T) {
 		plt.Save("/tmp/gosl/ode", "ode4")
 	}
 }
+
+func TestOde05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ode05: stop simulation using output functions")
+
+	// problem: dy/dx = -y with y(0) = 1 ⇒ y = exp(-x)
+	fcn := func(f la.Vector, h, x float64, y la.Vector) {
+		f[0] = -y[0]
+	}
+
+	// step output
+	for _, method := range []string{"rk4", "dopri5", "radau5"} {
+		conf := NewConfig(method, "", nil)
+		if method == "rk4" {
+			conf.SetFixedH(0.1, 1)
+		}
+		var xstop float64
+		var ystop la.Vector
+		conf.SetStepOut(true, func(istep int, h, x float64, y la.Vector) (stop bool) {
+			if x >= 0.3 {
+				xstop, ystop = x, y.GetCopy()
+				return true
+			}
+			return
+		})
+		sol := NewSolver(1, conf, fcn, nil, nil)
+		y := la.NewVector(1)
+		y[0] = 1
+		sol.Solve(y, 0, 1)
+		sol.Free()
+		io.Pforan("%-7s xstop = %v\n", method, xstop)
+		if xstop < 0.3 || xstop >= 1 {
+			tst.Errorf("simulation should have stopped in [0.3,1). xstop = %v\n", xstop)
+			return
+		}
+		chk.Array(tst, "y(xstop)", 1e-15, y, ystop)
+		chk.Float64(tst, "y(xstop)", 1e-3, y[0], math.Exp(-xstop))
+	}
+
+	// dense output with explicit Runge-Kutta methods (without saving results)
+	for _, method := range []string{"dopri5", "dopri8"} {
+		conf := NewConfig(method, "", nil)
+		var xout []float64
+		conf.SetDenseOut(false, 0.1, 10, func(istep int, h, x float64, y la.Vector, xo float64, yo la.Vector) (stop bool) {
+			xout = append(xout, xo)
+			chk.Float64(tst, "yout", 1e-3, yo[0], math.Exp(-xo))
+			return xo >= 0.5-1e-12
+		})
+		sol := NewSolver(1, conf, fcn, nil, nil)
+		y := la.NewVector(1)
+		y[0] = 1
+		sol.Solve(y, 0, 10)
+		sol.Free()
+		io.Pforan("%-7s xout = %v\n", method, xout)
+		chk.Array(tst, "xout", 1e-12, xout, []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5})
+	}
+}
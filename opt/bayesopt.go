@@ -0,0 +1,211 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/ml"
+	"github.com/cpmech/gosl/rnd"
+	"github.com/cpmech/gosl/utl"
+)
+
+// BayesOpt implements the Bayesian optimisation method with Gaussian process surrogates
+//  Solve:
+//          min f(x)   s.t.   xmin ≤ x ≤ xmax
+//           x
+//
+//  The method is intended for expensive objective functions. After evaluating f at Ninit points
+//  generated by Latin hypercube sampling (or Halton points), a Gaussian process model (ml.GaussProc)
+//  of f is fitted and the next point is selected by maximising an acquisition function:
+//
+//       "ei":   expected improvement   EI(x) = (fbest - μ - ξ) Φ(z) + σ φ(z),  z = (fbest - μ - ξ) / σ
+//       "ucb":  lower confidence bound  -LCB(x) = -(μ - κ σ)
+//
+//  where μ(x) and σ(x) are the mean and standard deviation predicted by the model. The acquisition
+//  function is maximised by differential evolution. The hyperparameters of the model are
+//  recomputed after each evaluation.
+type BayesOpt struct {
+
+	// problem
+	Ndim int       // dimension of x
+	Xmin la.Vector // [Ndim] lower limits
+	Xmax la.Vector // [Ndim] upper limits
+
+	// constants
+	Ninit       int     // number of initial samples
+	NmaxEval    int     // max number of function evaluations (including initial samples)
+	Acquisition string  // acquisition function: "ei" (expected improvement) or "ucb" (confidence bound)
+	Xi          float64 // ξ: exploration parameter of "ei"
+	Kappa       float64 // κ: exploration parameter of "ucb"
+	Kernel      string  // kernel of the Gaussian process: "rbf", "matern32" or "matern52"
+	Sampling    string  // initial samples: "lhs", "halton" or "uniform"
+	Seed        int     // seed for the random numbers generator; use Seed ≤ 0 to use current time
+
+	// results
+	Xbest   la.Vector     // [Ndim] best solution
+	Fbest   float64       // best objective value
+	Nfeval  int           // number of function evaluations
+	X       []la.Vector   // [Nfeval] all evaluated points
+	F       []float64     // [Nfeval] all objective values
+	History []float64     // [Nfeval] best objective value after each evaluation (convergence history)
+	Gp      *ml.GaussProc // Gaussian process model (with x normalised to the unit hypercube)
+
+	// auxiliary
+	ffcn fun.Sv // objective function
+}
+
+// Init initialises BayesOpt
+//  Input:
+//   xmin -- [ndim] lower limits
+//   xmax -- [ndim] upper limits
+//   ffcn -- objective function f(x)
+//   prms -- parameters: "ninit", "nmaxeval", "xi", "kappa", "seed"
+func (o *BayesOpt) Init(xmin, xmax la.Vector, ffcn fun.Sv, prms dbf.Params) {
+
+	// problem
+	evoCheckBounds(xmin, xmax)
+	if ffcn == nil {
+		chk.Panic("objective function must be given")
+	}
+	o.Ndim = len(xmin)
+	o.Xmin = xmin.GetCopy()
+	o.Xmax = xmax.GetCopy()
+	o.ffcn = ffcn
+
+	// constants
+	o.Ninit = utl.Imax(5, 2*o.Ndim+1)
+	o.NmaxEval = o.Ninit + 20*o.Ndim
+	o.Acquisition = "ei"
+	o.Xi = 0.01
+	o.Kappa = 2
+	o.Kernel = "matern52"
+	o.Sampling = "lhs"
+	o.Seed = 0
+	for _, p := range prms {
+		switch p.N {
+		case "ninit":
+			o.Ninit = int(p.V)
+		case "nmaxeval":
+			o.NmaxEval = int(p.V)
+		case "xi":
+			o.Xi = p.V
+		case "kappa":
+			o.Kappa = p.V
+		case "seed":
+			o.Seed = int(p.V)
+		}
+	}
+
+	// results
+	o.Xbest = la.NewVector(o.Ndim)
+}
+
+// Solve solves the optimisation problem
+func (o *BayesOpt) Solve(verbose bool) {
+
+	// check
+	switch o.Acquisition {
+	case "ei", "ucb":
+	default:
+		chk.Panic("acquisition function %q is not available. options are \"ei\" and \"ucb\"", o.Acquisition)
+	}
+	if o.Ninit < 2 || o.NmaxEval < o.Ninit {
+		chk.Panic("Ninit must be at least 2 and NmaxEval ≥ Ninit. Ninit = %d and NmaxEval = %d are invalid", o.Ninit, o.NmaxEval)
+	}
+
+	// initial samples
+	rnd.Init(o.Seed)
+	o.Nfeval = 0
+	o.Fbest = math.Inf(+1)
+	o.X, o.F, o.History = nil, nil, nil
+	Xinit := make([]la.Vector, o.Ninit)
+	for k := 0; k < o.Ninit; k++ {
+		Xinit[k] = la.NewVector(o.Ndim)
+	}
+	evoSample(Xinit, o.Xmin, o.Xmax, o.Sampling)
+	if verbose {
+		io.Pf("%5s%23s%23s\n", "eval", "f", "fbest")
+	}
+	for _, x := range Xinit {
+		o.evaluate(x, verbose)
+	}
+
+	// model
+	o.Gp = ml.NewGaussProc(o.Ndim, o.Kernel)
+	o.Gp.Nrestarts = 2
+	Xn := make([]la.Vector, 0, o.NmaxEval) // normalised x-values
+
+	// acquisition function (to be minimised)
+	zero, one := la.NewVector(o.Ndim), la.NewVector(o.Ndim)
+	one.Fill(1)
+	acq := func(xn la.Vector) float64 {
+		μ, σ := o.Gp.Predict(xn)
+		if o.Acquisition == "ucb" {
+			return μ - o.Kappa*σ
+		}
+		imp := o.Fbest - μ - o.Xi
+		if σ < 1e-14 {
+			return -math.Max(imp, 0)
+		}
+		z := imp / σ
+		return -(imp*rnd.StdPhi(z) + σ*rnd.Stdphi(z))
+	}
+
+	// iterations
+	x := la.NewVector(o.Ndim)
+	for it := 0; o.Nfeval < o.NmaxEval; it++ {
+
+		// fit model
+		Xn = Xn[:0]
+		for _, xk := range o.X {
+			xn := la.NewVector(o.Ndim)
+			for j := 0; j < o.Ndim; j++ {
+				xn[j] = (xk[j] - o.Xmin[j]) / (o.Xmax[j] - o.Xmin[j])
+			}
+			Xn = append(Xn, xn)
+		}
+		o.Gp.Fit(Xn, o.F, true)
+
+		// maximise acquisition function
+		var de DiffEvol
+		de.Init(zero, one, acq, 0, nil, dbf.Params{
+			&dbf.P{N: "npop", V: float64(utl.Imax(20, 10*o.Ndim))},
+			&dbf.P{N: "nmaxgen", V: 100},
+			&dbf.P{N: "nstall", V: 20},
+		})
+		if o.Seed > 0 {
+			de.Seed = o.Seed + it + 1
+		}
+		de.Solve(false)
+
+		// evaluate
+		for j := 0; j < o.Ndim; j++ {
+			x[j] = o.Xmin[j] + de.Xbest[j]*(o.Xmax[j]-o.Xmin[j])
+		}
+		o.evaluate(x, verbose)
+	}
+}
+
+// evaluate evaluates the objective function and updates the results
+func (o *BayesOpt) evaluate(x la.Vector, verbose bool) {
+	f := o.ffcn(x)
+	o.Nfeval++
+	o.X = append(o.X, x.GetCopy())
+	o.F = append(o.F, f)
+	if f < o.Fbest {
+		o.Fbest = f
+		o.Xbest.Apply(1, x)
+	}
+	o.History = append(o.History, o.Fbest)
+	if verbose {
+		io.Pf("%5d%23.15e%23.15e\n", o.Nfeval, f, o.Fbest)
+	}
+}
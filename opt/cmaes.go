@@ -0,0 +1,297 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+	"github.com/cpmech/gosl/utl"
+)
+
+// CmaEs implements the covariance matrix adaptation evolution strategy (Hansen 2016)
+//  Solve:
+//          min f(x)   s.t.   g(x) ≤ 0,  xmin ≤ x ≤ xmax
+//           x
+//
+//  The (μ/μ_w, λ)-CMA-ES with cumulative step-size adaptation is implemented. Candidates outside
+//  the box are evaluated at the nearest point inside the box and the distance to the box is added
+//  to the constraint violation. The candidates are ranked by Deb's feasibility rules.
+//
+//  Reference:
+//   Hansen N (2016) The CMA evolution strategy: a tutorial. arXiv:1604.00772
+type CmaEs struct {
+
+	// problem
+	Ndim int       // dimension of x
+	Xmin la.Vector // [Ndim] lower limits
+	Xmax la.Vector // [Ndim] upper limits
+
+	// constants
+	Lambda  int       // population size (number of offspring)
+	Mu      int       // number of parents
+	Sigma0  float64   // initial step size
+	X0      la.Vector // [Ndim] initial mean; may be nil ⇒ a random point is selected
+	NmaxGen int       // max number of generations
+	Nstall  int       // stop if the best value does not improve by more than Ftol during Nstall generations
+	Ftol    float64   // tolerance for the stagnation criterion
+	Xtol    float64   // stop if σ ⋅ max(sqrt(eig(C))) < Xtol
+	Seed    int       // seed for the random numbers generator; use Seed ≤ 0 to use current time
+
+	// results
+	Xbest   la.Vector // [Ndim] best solution
+	Fbest   float64   // best objective value
+	Viol    float64   // constraint violation of best solution (zero if feasible)
+	Sigma   float64   // final step size
+	Ngen    int       // number of generations
+	Nfeval  int       // number of function evaluations
+	History []float64 // [Ngen+1] best objective value at each generation (convergence history)
+
+	// auxiliary
+	obj evoObjective // objective and constraints
+}
+
+// Init initialises CmaEs
+//  Input:
+//   xmin -- [ndim] lower limits
+//   xmax -- [ndim] upper limits
+//   ffcn -- objective function f(x)
+//   ng   -- number of inequality constraints
+//   gfcn -- inequality constraints g(x) ≤ 0; may be nil if ng == 0
+//   prms -- parameters: "lambda", "mu", "sigma0", "nmaxgen", "nstall", "ftol", "xtol", "seed"
+func (o *CmaEs) Init(xmin, xmax la.Vector, ffcn fun.Sv, ng int, gfcn fun.Vv, prms dbf.Params) {
+
+	// problem
+	evoCheckBounds(xmin, xmax)
+	o.Ndim = len(xmin)
+	o.Xmin = xmin.GetCopy()
+	o.Xmax = xmax.GetCopy()
+	o.obj.init(ffcn, ng, gfcn)
+
+	// constants
+	o.Lambda = 4 + int(3*math.Log(float64(o.Ndim)))
+	o.Mu = 0
+	o.Sigma0 = 0
+	for i := 0; i < o.Ndim; i++ {
+		o.Sigma0 = math.Max(o.Sigma0, 0.3*(o.Xmax[i]-o.Xmin[i]))
+	}
+	o.NmaxGen = 10000
+	o.Nstall = 100 + int(100*math.Pow(float64(o.Ndim), 1.5)/float64(o.Lambda))
+	o.Ftol = 1e-12
+	o.Xtol = 1e-12
+	o.Seed = 0
+	for _, p := range prms {
+		switch p.N {
+		case "lambda":
+			o.Lambda = int(p.V)
+		case "mu":
+			o.Mu = int(p.V)
+		case "sigma0":
+			o.Sigma0 = p.V
+		case "nmaxgen":
+			o.NmaxGen = int(p.V)
+		case "nstall":
+			o.Nstall = int(p.V)
+		case "ftol":
+			o.Ftol = p.V
+		case "xtol":
+			o.Xtol = p.V
+		case "seed":
+			o.Seed = int(p.V)
+		}
+	}
+	if o.Mu < 1 {
+		o.Mu = o.Lambda / 2
+	}
+
+	// results
+	o.Xbest = la.NewVector(o.Ndim)
+}
+
+// Solve solves the optimisation problem
+func (o *CmaEs) Solve(verbose bool) {
+
+	// strategy parameters
+	n, λ := o.Ndim, o.Lambda
+	μ := utl.Imin(o.Mu, λ)
+	nf := float64(n)
+	w := la.NewVector(μ)
+	for i := 0; i < μ; i++ {
+		w[i] = math.Log(float64(μ)+0.5) - math.Log(float64(i+1))
+	}
+	w.Apply(1.0/w.Accum(), w)
+	μeff := 1.0 / la.VecDot(w, w)
+	cc := (4 + μeff/nf) / (nf + 4 + 2*μeff/nf)
+	cs := (μeff + 2) / (nf + μeff + 5)
+	c1 := 2 / ((nf+1.3)*(nf+1.3) + μeff)
+	cμ := math.Min(1-c1, 2*(μeff-2+1/μeff)/((nf+2)*(nf+2)+μeff))
+	damps := 1 + 2*math.Max(0, math.Sqrt((μeff-1)/(nf+1))-1) + cs
+	chiN := math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+	neig := utl.Imax(1, int(1/((c1+cμ)*nf*10)))
+
+	// initial mean
+	rnd.Init(o.Seed)
+	o.obj.nfeval = 0
+	m := la.NewVector(n)
+	if o.X0 != nil {
+		m.Apply(1, o.X0)
+	} else {
+		for i := 0; i < n; i++ {
+			m[i] = rnd.Float64(o.Xmin[i], o.Xmax[i])
+		}
+	}
+
+	// state
+	σ := o.Sigma0
+	pc := la.NewVector(n)
+	ps := la.NewVector(n)
+	B := la.NewMatrix(n, n) // eigenvectors of C
+	D := la.NewVector(n)    // square root of eigenvalues of C
+	C := la.NewMatrix(n, n) // covariance matrix
+	Ctmp := la.NewMatrix(n, n)
+	for i := 0; i < n; i++ {
+		B.Set(i, i, 1)
+		C.Set(i, i, 1)
+		D[i] = 1
+	}
+
+	// workspace
+	X := make([]la.Vector, λ)  // candidates
+	Xc := make([]la.Vector, λ) // candidates clipped to box
+	Y := make([]la.Vector, λ)  // steps (x - m) / σ
+	F := make([]float64, λ)
+	V := make([]float64, λ)
+	for k := 0; k < λ; k++ {
+		X[k] = la.NewVector(n)
+		Xc[k] = la.NewVector(n)
+		Y[k] = la.NewVector(n)
+	}
+	idx := make([]int, λ)
+	z := la.NewVector(n)
+	mold := la.NewVector(n)
+	yw := la.NewVector(n)
+	tmp := la.NewVector(n)
+
+	// best solution
+	o.Fbest, o.Viol = math.Inf(+1), math.Inf(+1)
+	o.History = nil
+
+	// message
+	if verbose {
+		io.Pf("%5s%23s%23s%23s%10s\n", "gen", "fbest", "viol", "sigma", "nfeval")
+	}
+
+	// generations
+	for o.Ngen = 0; o.Ngen < o.NmaxGen; o.Ngen++ {
+
+		// sample and evaluate candidates
+		for k := 0; k < λ; k++ {
+			for i := 0; i < n; i++ {
+				z[i] = rnd.Normal(0, 1) * D[i]
+			}
+			la.MatVecMul(Y[k], 1, B, z)
+			for i := 0; i < n; i++ {
+				X[k][i] = m[i] + σ*Y[k][i]
+			}
+			Xc[k].Apply(1, X[k])
+			evoClip(Xc[k], o.Xmin, o.Xmax)
+			F[k], V[k] = o.obj.eval(Xc[k])
+			if evoBetter(F[k], V[k], o.Fbest, o.Viol) {
+				o.Xbest.Apply(1, Xc[k])
+				o.Fbest, o.Viol = F[k], V[k]
+			}
+			V[k] += X[k].NormDiff(Xc[k])
+		}
+		if o.Ngen == 0 {
+			o.History = append(o.History, o.Fbest)
+			if verbose {
+				io.Pf("%5d%23.15e%23.15e%23.15e%10d\n", 0, o.Fbest, o.Viol, σ, o.obj.nfeval)
+			}
+		}
+
+		// rank candidates
+		for k := 0; k < λ; k++ {
+			idx[k] = k
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return evoBetter(F[idx[a]], V[idx[a]], F[idx[b]], V[idx[b]]) })
+
+		// update mean
+		mold.Apply(1, m)
+		yw.Fill(0)
+		for i := 0; i < μ; i++ {
+			la.VecAdd(yw, 1, yw, w[i], Y[idx[i]])
+		}
+		la.VecAdd(m, 1, mold, σ, yw)
+
+		// cumulation for σ: ps = (1-cs) ps + sqrt(cs (2-cs) μeff) C^(-1/2) yw
+		la.MatTrVecMul(tmp, 1, B, yw)
+		for i := 0; i < n; i++ {
+			tmp[i] /= D[i]
+		}
+		la.MatVecMul(z, 1, B, tmp)
+		la.VecAdd(ps, 1-cs, ps, math.Sqrt(cs*(2-cs)*μeff), z)
+		psn := ps.Norm()
+		hsig := 0.0
+		if psn/math.Sqrt(1-math.Pow(1-cs, 2*float64(o.Ngen+1)))/chiN < 1.4+2/(nf+1) {
+			hsig = 1.0
+		}
+
+		// cumulation for C
+		la.VecAdd(pc, 1-cc, pc, hsig*math.Sqrt(cc*(2-cc)*μeff), yw)
+
+		// update C
+		δ := (1 - hsig) * cc * (2 - cc)
+		for i := 0; i < n; i++ {
+			for j := 0; j <= i; j++ {
+				cij := (1-c1-cμ)*C.Get(i, j) + c1*(pc[i]*pc[j]+δ*C.Get(i, j))
+				for k := 0; k < μ; k++ {
+					cij += cμ * w[k] * Y[idx[k]][i] * Y[idx[k]][j]
+				}
+				C.Set(i, j, cij)
+				C.Set(j, i, cij)
+			}
+		}
+
+		// update σ
+		σ *= math.Exp((cs / damps) * (psn/chiN - 1))
+
+		// eigendecomposition of C
+		if (o.Ngen+1)%neig == 0 {
+			C.CopyInto(Ctmp, 1)
+			la.Jacobi(B, D, Ctmp)
+			for i := 0; i < n; i++ {
+				D[i] = math.Sqrt(math.Max(D[i], 1e-300))
+			}
+		}
+
+		// history
+		o.History = append(o.History, o.Fbest)
+		if verbose {
+			io.Pf("%5d%23.15e%23.15e%23.15e%10d\n", o.Ngen+1, o.Fbest, o.Viol, σ, o.obj.nfeval)
+		}
+
+		// check convergence
+		if o.Viol == 0 && evoStalled(o.History, o.Nstall, o.Ftol) {
+			o.Ngen++
+			break
+		}
+		if σ*D.Max() < o.Xtol {
+			o.Ngen++
+			break
+		}
+	}
+
+	// results
+	o.Sigma = σ
+	o.Nfeval = o.obj.nfeval
+	if verbose && o.Ngen == o.NmaxGen {
+		io.Pfred("max number of generations reached\n")
+	}
+}
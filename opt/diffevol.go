@@ -0,0 +1,217 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math/rand"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+	"github.com/cpmech/gosl/utl"
+)
+
+// DiffEvol implements the differential evolution method (Storn and Price 1997)
+//  Solve:
+//          min f(x)   s.t.   g(x) ≤ 0,  xmin ≤ x ≤ xmax
+//           x
+//
+//  The mutant vectors are computed with the "rand/1" or "best/1" strategies and combined with
+//  the target vectors by means of binomial crossover. Constraints are handled with Deb's
+//  feasibility rules: feasible solutions are always better than infeasible ones and infeasible
+//  solutions are compared by the sum of constraint violations.
+type DiffEvol struct {
+
+	// problem
+	Ndim int       // dimension of x
+	Xmin la.Vector // [Ndim] lower limits
+	Xmax la.Vector // [Ndim] upper limits
+
+	// constants
+	Npop     int     // population size
+	NmaxGen  int     // max number of generations
+	Nstall   int     // stop if the best value does not improve by more than Ftol during Nstall generations
+	Ftol     float64 // tolerance for the stagnation criterion
+	F        float64 // differential weight ∈ (0, 2]
+	CR       float64 // crossover probability ∈ [0, 1]
+	Strategy string  // mutation strategy: "rand1" or "best1"
+	Sampling string  // initial population: "lhs", "halton" or "uniform"
+	Seed     int     // seed for the random numbers generator; use Seed ≤ 0 to use current time
+
+	// results
+	Xbest   la.Vector // [Ndim] best solution
+	Fbest   float64   // best objective value
+	Viol    float64   // constraint violation of best solution (zero if feasible)
+	Ngen    int       // number of generations
+	Nfeval  int       // number of function evaluations
+	History []float64 // [Ngen+1] best objective value at each generation (convergence history)
+
+	// auxiliary
+	obj  evoObjective // objective and constraints
+	pop  []la.Vector  // [Npop] population
+	fval []float64    // [Npop] objective values
+	vval []float64    // [Npop] constraint violations
+}
+
+// Init initialises DiffEvol
+//  Input:
+//   xmin -- [ndim] lower limits
+//   xmax -- [ndim] upper limits
+//   ffcn -- objective function f(x)
+//   ng   -- number of inequality constraints
+//   gfcn -- inequality constraints g(x) ≤ 0; may be nil if ng == 0
+//   prms -- parameters: "npop", "nmaxgen", "nstall", "ftol", "F", "CR", "seed"
+func (o *DiffEvol) Init(xmin, xmax la.Vector, ffcn fun.Sv, ng int, gfcn fun.Vv, prms dbf.Params) {
+
+	// problem
+	evoCheckBounds(xmin, xmax)
+	o.Ndim = len(xmin)
+	o.Xmin = xmin.GetCopy()
+	o.Xmax = xmax.GetCopy()
+	o.obj.init(ffcn, ng, gfcn)
+
+	// constants
+	o.Npop = utl.Imax(10*o.Ndim, 20)
+	o.NmaxGen = 1000
+	o.Nstall = 100
+	o.Ftol = 1e-12
+	o.F = 0.7
+	o.CR = 0.9
+	o.Strategy = "rand1"
+	o.Sampling = "lhs"
+	o.Seed = 0
+	for _, p := range prms {
+		switch p.N {
+		case "npop":
+			o.Npop = int(p.V)
+		case "nmaxgen":
+			o.NmaxGen = int(p.V)
+		case "nstall":
+			o.Nstall = int(p.V)
+		case "ftol":
+			o.Ftol = p.V
+		case "F":
+			o.F = p.V
+		case "CR":
+			o.CR = p.V
+		case "seed":
+			o.Seed = int(p.V)
+		}
+	}
+
+	// results
+	o.Xbest = la.NewVector(o.Ndim)
+}
+
+// Solve solves the optimisation problem
+func (o *DiffEvol) Solve(verbose bool) {
+
+	// check
+	if o.Npop < 4 {
+		chk.Panic("population size must be at least 4. Npop = %d is invalid", o.Npop)
+	}
+	switch o.Strategy {
+	case "rand1", "best1":
+	default:
+		chk.Panic("strategy %q is not available. options are \"rand1\" and \"best1\"", o.Strategy)
+	}
+
+	// initial population
+	rnd.Init(o.Seed)
+	o.obj.nfeval = 0
+	o.pop = make([]la.Vector, o.Npop)
+	o.fval = make([]float64, o.Npop)
+	o.vval = make([]float64, o.Npop)
+	for i := 0; i < o.Npop; i++ {
+		o.pop[i] = la.NewVector(o.Ndim)
+	}
+	evoSample(o.pop, o.Xmin, o.Xmax, o.Sampling)
+	ibest := 0
+	for i := 0; i < o.Npop; i++ {
+		o.fval[i], o.vval[i] = o.obj.eval(o.pop[i])
+		if evoBetter(o.fval[i], o.vval[i], o.fval[ibest], o.vval[ibest]) {
+			ibest = i
+		}
+	}
+	o.History = []float64{o.fval[ibest]}
+
+	// message
+	if verbose {
+		io.Pf("%5s%23s%23s%10s\n", "gen", "fbest", "viol", "nfeval")
+		io.Pf("%5d%23.15e%23.15e%10d\n", 0, o.fval[ibest], o.vval[ibest], o.obj.nfeval)
+	}
+
+	// generations
+	trial := la.NewVector(o.Ndim)
+	for o.Ngen = 0; o.Ngen < o.NmaxGen; {
+		o.Ngen++
+		for i := 0; i < o.Npop; i++ {
+
+			// select distinct individuals
+			r := rnd.IntGetUniqueN(0, o.Npop, 4)
+			rnd.IntShuffle(r)
+			k := 0
+			for k < 3 && r[k] != i {
+				k++
+			}
+			if k < 3 {
+				r[k] = r[3]
+			}
+			base := o.pop[r[0]]
+			if o.Strategy == "best1" {
+				base = o.pop[ibest]
+			}
+
+			// mutation and binomial crossover
+			jrand := rand.Intn(o.Ndim)
+			for j := 0; j < o.Ndim; j++ {
+				if j == jrand || rand.Float64() < o.CR {
+					trial[j] = base[j] + o.F*(o.pop[r[1]][j]-o.pop[r[2]][j])
+					if trial[j] < o.Xmin[j] {
+						trial[j] = (o.Xmin[j] + o.pop[i][j]) / 2.0
+					}
+					if trial[j] > o.Xmax[j] {
+						trial[j] = (o.Xmax[j] + o.pop[i][j]) / 2.0
+					}
+				} else {
+					trial[j] = o.pop[i][j]
+				}
+			}
+
+			// selection
+			f, v := o.obj.eval(trial)
+			if !evoBetter(o.fval[i], o.vval[i], f, v) {
+				o.pop[i].Apply(1, trial)
+				o.fval[i], o.vval[i] = f, v
+				if evoBetter(f, v, o.fval[ibest], o.vval[ibest]) {
+					ibest = i
+				}
+			}
+		}
+		o.History = append(o.History, o.fval[ibest])
+
+		// message
+		if verbose {
+			io.Pf("%5d%23.15e%23.15e%10d\n", o.Ngen, o.fval[ibest], o.vval[ibest], o.obj.nfeval)
+		}
+
+		// check convergence
+		if o.vval[ibest] == 0 && evoStalled(o.History, o.Nstall, o.Ftol) {
+			break
+		}
+	}
+
+	// results
+	o.Xbest.Apply(1, o.pop[ibest])
+	o.Fbest = o.fval[ibest]
+	o.Viol = o.vval[ibest]
+	o.Nfeval = o.obj.nfeval
+	if verbose && o.Ngen == o.NmaxGen {
+		io.Pfred("max number of generations reached\n")
+	}
+}
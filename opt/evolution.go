@@ -0,0 +1,137 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+)
+
+// evoObjective evaluates the objective function and the constraints of single-objective problems
+//  Solve:
+//          min f(x)   s.t.   g(x) ≤ 0,  xmin ≤ x ≤ xmax
+//           x
+type evoObjective struct {
+	ffcn   fun.Sv    // objective function
+	ng     int       // number of inequality constraints
+	gfcn   fun.Vv    // inequality constraints g(x) ≤ 0; may be nil if ng == 0
+	gval   la.Vector // [ng] constraint values
+	nfeval int       // number of function evaluations
+}
+
+// init initialises evoObjective
+func (o *evoObjective) init(ffcn fun.Sv, ng int, gfcn fun.Vv) {
+	if ffcn == nil {
+		chk.Panic("objective function must be given")
+	}
+	if ng > 0 && gfcn == nil {
+		chk.Panic("constraints function must be given when ng = %d > 0", ng)
+	}
+	o.ffcn = ffcn
+	o.ng = ng
+	o.gfcn = gfcn
+	o.gval = la.NewVector(ng)
+	o.nfeval = 0
+}
+
+// eval evaluates the objective function and the constraint violation
+func (o *evoObjective) eval(x la.Vector) (f, viol float64) {
+	o.nfeval++
+	f = o.ffcn(x)
+	if o.ng > 0 {
+		o.gfcn(o.gval, x)
+		viol = evoViolation(o.gval)
+	}
+	return
+}
+
+// evoViolation computes the constraint violation Σ max(0, g_i)
+func evoViolation(g la.Vector) (viol float64) {
+	for _, v := range g {
+		if v > 0 {
+			viol += v
+		}
+	}
+	return
+}
+
+// evoBetter implements Deb's feasibility rules: returns true if "a" is better than "b"
+//  1. a feasible solution is better than an infeasible one
+//  2. of two infeasible solutions, the one with the smaller violation is better
+//  3. of two feasible solutions, the one with the smaller objective value is better
+func evoBetter(fa, va, fb, vb float64) bool {
+	if va > 0 || vb > 0 {
+		return va < vb
+	}
+	return fa < fb
+}
+
+// evoCheckBounds checks the limits of the search space
+func evoCheckBounds(xmin, xmax la.Vector) {
+	if len(xmin) != len(xmax) || len(xmin) < 1 {
+		chk.Panic("xmin and xmax must have the same length > 0. %d != %d", len(xmin), len(xmax))
+	}
+	for i := 0; i < len(xmin); i++ {
+		if xmax[i] <= xmin[i] {
+			chk.Panic("xmax must be greater than xmin. xmax[%d] = %g is invalid", i, xmax[i])
+		}
+	}
+}
+
+// evoClip clips x to [xmin, xmax]
+func evoClip(x, xmin, xmax la.Vector) {
+	for i := 0; i < len(x); i++ {
+		x[i] = math.Max(xmin[i], math.Min(xmax[i], x[i]))
+	}
+}
+
+// evoSample generates the initial population
+//  Input:
+//   sampling -- "lhs" (Latin hypercube), "halton" (Halton points) or "uniform" (random)
+//  Output:
+//   X -- [npop] population with len(X[i]) = len(xmin)
+func evoSample(X []la.Vector, xmin, xmax la.Vector, sampling string) {
+	npop, ndim := len(X), len(xmin)
+	switch sampling {
+	case "lhs":
+		L := rnd.LatinIHS(ndim, npop, 5)
+		for i := 0; i < npop; i++ {
+			for j := 0; j < ndim; j++ {
+				u := (float64(L[j][i]-1) + rand.Float64()) / float64(npop)
+				X[i][j] = xmin[j] + u*(xmax[j]-xmin[j])
+			}
+		}
+	case "halton":
+		H := rnd.HaltonPoints(ndim, npop+1)
+		for i := 0; i < npop; i++ {
+			for j := 0; j < ndim; j++ {
+				X[i][j] = xmin[j] + H[j][i+1]*(xmax[j]-xmin[j])
+			}
+		}
+	case "uniform":
+		for i := 0; i < npop; i++ {
+			for j := 0; j < ndim; j++ {
+				X[i][j] = rnd.Float64(xmin[j], xmax[j])
+			}
+		}
+	default:
+		chk.Panic("sampling method %q is not available. options are \"lhs\", \"halton\" and \"uniform\"", sampling)
+	}
+}
+
+// evoStalled checks whether the best objective value has not improved by more than ftol during
+// the last nstall generations
+func evoStalled(history []float64, nstall int, ftol float64) bool {
+	n := len(history)
+	if nstall < 1 || n <= nstall {
+		return false
+	}
+	return math.Abs(history[n-1-nstall]-history[n-1]) <= ftol*(1+math.Abs(history[n-1]))
+}
@@ -0,0 +1,431 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// LinMilp implements the branch-and-bound method for mixed-integer linear programming problems
+//  Solve:
+//          min cᵀx   s.t.   A x = b,  l ≤ x ≤ u,  x[j] ∈ ℤ for all j with Integer[j] == true
+//           x
+//
+//  The LP relaxations are solved with LinSimplex: each node is reoptimised with the dual simplex
+//  method starting from the optimal basis of its parent. Gomory mixed-integer cuts can be added
+//  at the root node.
+type LinMilp struct {
+
+	// problem
+	Lp      LinSimplex // solver of LP relaxations
+	Integer []bool     // [Nx] integer variables
+
+	// constants
+	NodeSel    string  // node selection: "depth" (depth-first), "best" (best-bound) or "hybrid" (depth-first until a solution is found)
+	GapTol     float64 // relative gap tolerance: stop when F - Bound ≤ GapTol ⋅ max(1, |F|)
+	IntTol     float64 // integrality tolerance
+	NmaxNodes  int     // max number of nodes
+	NcutRounds int     // number of rounds of Gomory mixed-integer cuts at the root node
+
+	// results
+	Status LpStatus  // LpOptimal, LpInfeasible, LpUnbounded (relaxation is unbounded) or LpMaxIt (max number of nodes reached)
+	Found  bool      // an integer solution has been found
+	X      la.Vector // [Nx] best integer solution (incumbent)
+	F      float64   // objective value of best integer solution
+	Bound  float64   // lower bound of objective value
+	Gap    float64   // relative gap: (F - Bound) / max(1, |F|)
+	Nnodes int       // number of nodes processed
+	Ncuts  int       // number of cuts added at the root node
+
+	// auxiliary
+	nodes []*milpNode // open nodes
+	cuts  []milpCut   // cuts added to Lp (removed at the end of Solve)
+	lo0   []float64   // [Nx] original lower bounds
+	up0   []float64   // [Nx] original upper bounds
+}
+
+// milpNode holds a node of the branch-and-bound tree
+type milpNode struct {
+	lo, up []float64     // [Nx] bounds of variables
+	bound  float64       // lower bound of objective value (from parent)
+	basis  *SimplexBasis // optimal basis of parent
+	depth  int           // depth in tree
+}
+
+// milpCut holds a cut  Σ a[k] x[idx[k]] ≤ β  and its slack variable
+type milpCut struct {
+	idx   []int     // indices of variables
+	a     []float64 // coefficients
+	β     float64   // right-hand side
+	slack int       // index of slack variable in Lp
+}
+
+// Init initialises LinMilp
+//  Input:
+//   A -- [nl][nx] matrix of constraints
+//   b -- [nl] right-hand side
+//   c -- [nx] cost vector
+//   l -- [nx] lower bounds; may be nil ⇒ l = 0
+//   u -- [nx] upper bounds; may be nil ⇒ u = +∞
+//   integer -- [nx] integer variables; may be nil ⇒ all variables are integer
+//   prms -- parameters: "nmaxnodes", "gaptol", "inttol", "ncutrounds" and the parameters of LinSimplex
+func (o *LinMilp) Init(A *la.CCMatrix, b, c, l, u la.Vector, integer []bool, prms dbf.Params) {
+
+	// LP relaxation
+	o.Lp.Init(A, b, c, l, u, prms)
+	o.Integer = integer
+	if o.Integer == nil {
+		o.Integer = make([]bool, o.Lp.Nx)
+		for j := 0; j < o.Lp.Nx; j++ {
+			o.Integer[j] = true
+		}
+	}
+	if len(o.Integer) != o.Lp.Nx {
+		chk.Panic("len(integer) must be equal to the number of variables. %d != %d", len(o.Integer), o.Lp.Nx)
+	}
+
+	// constants
+	o.NodeSel = "hybrid"
+	o.GapTol = 1e-6
+	o.IntTol = 1e-6
+	o.NmaxNodes = 100000
+	o.NcutRounds = 0
+	for _, p := range prms {
+		switch p.N {
+		case "nmaxnodes":
+			o.NmaxNodes = int(p.V)
+		case "gaptol":
+			o.GapTol = p.V
+		case "inttol":
+			o.IntTol = p.V
+		case "ncutrounds":
+			o.NcutRounds = int(p.V)
+		}
+	}
+
+	// results
+	o.Status = LpUnsolved
+	o.X = la.NewVector(o.Lp.Nx)
+	o.cuts = nil
+	o.lo0 = o.Lp.L.GetCopy()
+	o.up0 = o.Lp.U.GetCopy()
+}
+
+// Solve solves the mixed-integer linear programming problem
+func (o *LinMilp) Solve(verbose bool) {
+
+	// check
+	switch o.NodeSel {
+	case "depth", "best", "hybrid":
+	default:
+		chk.Panic("node selection strategy %q is not available. options are \"depth\", \"best\" and \"hybrid\"", o.NodeSel)
+	}
+
+	// root node (the bounds of Lp are modified by branch-and-bound and restored at the end;
+	// the cuts are added to Lp and removed at the end)
+	nx, nl, nv := o.Lp.Nx, o.Lp.Nl, o.Lp.nv
+	o.restoreBounds()
+	o.cuts = nil
+	defer func() {
+		o.Lp.removeConstraints(nl, nv)
+		o.cuts = nil
+		o.restoreBounds()
+	}()
+	o.Found = false
+	o.F = math.Inf(+1)
+	o.Nnodes, o.Ncuts = 0, 0
+	o.Lp.Solve(false)
+	if o.Lp.Status != LpOptimal {
+		o.Status = o.Lp.Status
+		return
+	}
+
+	// cutting planes
+	for round := 0; round < o.NcutRounds; round++ {
+		ncuts := o.addGomoryCuts()
+		if ncuts == 0 {
+			break
+		}
+		o.Ncuts += ncuts
+		o.Lp.Resolve(false)
+		if o.Lp.Status != LpOptimal {
+			o.Status = o.Lp.Status
+			return
+		}
+	}
+	if verbose {
+		io.Pf("root: f(x) = %g (number of cuts = %d)\n", o.Lp.F, o.Ncuts)
+		io.Pf("%8s%8s%23s%23s%13s\n", "node", "open", "incumbent", "bound", "gap")
+	}
+
+	// first node
+	root := &milpNode{
+		lo:    append([]float64{}, o.lo0...),
+		up:    append([]float64{}, o.up0...),
+		bound: o.Lp.F,
+		basis: o.Lp.GetBasis(),
+	}
+	o.nodes = []*milpNode{root}
+
+	// branch-and-bound
+	o.Status = LpOptimal
+	for len(o.nodes) > 0 {
+
+		// check gap
+		o.updateBound()
+		if o.Found && o.Gap <= o.GapTol {
+			break
+		}
+		if o.Nnodes >= o.NmaxNodes {
+			o.Status = LpMaxIt
+			break
+		}
+
+		// solve relaxation
+		node := o.selectNode()
+		o.Nnodes++
+		if o.Found && o.prune(node.bound) {
+			continue
+		}
+		for j := 0; j < nx; j++ {
+			if node.lo[j] != o.Lp.L[j] || node.up[j] != o.Lp.U[j] {
+				o.Lp.SetBounds(j, node.lo[j], node.up[j])
+			}
+		}
+		o.Lp.SetBasis(node.basis)
+		o.Lp.Resolve(false)
+		if verbose {
+			io.Pf("%8d%8d%23.15e%23.15e%13.4e\n", o.Nnodes, len(o.nodes), o.F, o.Bound, o.Gap)
+		}
+		if o.Lp.Status == LpUnbounded {
+			o.Status = LpUnbounded
+			return
+		}
+		if o.Lp.Status != LpOptimal || (o.Found && o.prune(o.Lp.F)) {
+			continue
+		}
+
+		// select branching variable (most fractional)
+		k, fmax := -1, 0.0
+		for j := 0; j < nx; j++ {
+			if o.Integer[j] {
+				f := o.Lp.X[j] - math.Floor(o.Lp.X[j])
+				dist := math.Min(f, 1-f)
+				if dist > o.IntTol && dist > fmax {
+					k, fmax = j, dist
+				}
+			}
+		}
+
+		// integer solution
+		if k < 0 {
+			if o.Lp.F < o.F {
+				o.Found = true
+				o.F = o.Lp.F
+				for j := 0; j < nx; j++ {
+					o.X[j] = o.Lp.X[j]
+					if o.Integer[j] {
+						o.X[j] = math.Floor(o.Lp.X[j] + 0.5)
+					}
+				}
+			}
+			continue
+		}
+
+		// branch: the child closest to the LP solution is processed first in depth-first search
+		v := o.Lp.X[k]
+		basis := o.Lp.GetBasis()
+		down := &milpNode{append([]float64{}, node.lo...), append([]float64{}, node.up...), o.Lp.F, basis, node.depth + 1}
+		up := &milpNode{append([]float64{}, node.lo...), append([]float64{}, node.up...), o.Lp.F, basis, node.depth + 1}
+		down.up[k] = math.Floor(v)
+		up.lo[k] = math.Ceil(v)
+		if v-math.Floor(v) > 0.5 {
+			o.nodes = append(o.nodes, down, up)
+		} else {
+			o.nodes = append(o.nodes, up, down)
+		}
+	}
+
+	// results
+	o.updateBound()
+	if !o.Found && o.Status == LpOptimal {
+		o.Status = LpInfeasible
+	}
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// prune checks whether a node with given lower bound can be discarded
+func (o *LinMilp) prune(bound float64) bool {
+	return o.F-bound <= o.GapTol*math.Max(1, math.Abs(o.F))
+}
+
+// restoreBounds sets the original bounds in Lp
+func (o *LinMilp) restoreBounds() {
+	for j := 0; j < o.Lp.Nx; j++ {
+		if o.Lp.L[j] != o.lo0[j] || o.Lp.U[j] != o.up0[j] {
+			o.Lp.SetBounds(j, o.lo0[j], o.up0[j])
+		}
+	}
+}
+
+// updateBound updates the lower bound and the gap
+func (o *LinMilp) updateBound() {
+	o.Bound = o.F
+	for _, node := range o.nodes {
+		o.Bound = math.Min(o.Bound, node.bound)
+	}
+	o.Gap = math.Inf(+1)
+	if o.Found {
+		o.Gap = (o.F - o.Bound) / math.Max(1, math.Abs(o.F))
+	}
+}
+
+// selectNode selects and removes a node from the list of open nodes
+func (o *LinMilp) selectNode() (node *milpNode) {
+	k := len(o.nodes) - 1
+	if o.NodeSel == "best" || (o.NodeSel == "hybrid" && o.Found) {
+		for i, n := range o.nodes {
+			if n.bound < o.nodes[k].bound {
+				k = i
+			}
+		}
+	}
+	node = o.nodes[k]
+	o.nodes = append(o.nodes[:k], o.nodes[k+1:]...)
+	return
+}
+
+// addGomoryCuts adds Gomory mixed-integer cuts derived from the rows of the optimal tableau
+// corresponding to fractional basic integer variables. Returns the number of added cuts.
+//  With nonbasic variables shifted to their bounds (x'ⱼ = xⱼ - lⱼ or x'ⱼ = uⱼ - xⱼ),
+//  the tableau row is xᵢ + Σ a'ⱼ x'ⱼ = β and, with f₀ = β - ⌊β⌋ and fⱼ = a'ⱼ - ⌊a'ⱼ⌋,
+//  the cut reads:
+//
+//     Σ  fⱼ/f₀ x'ⱼ  +  Σ  (1-fⱼ)/(1-f₀) x'ⱼ  +  Σ  a'ⱼ/f₀ x'ⱼ  -  Σ  a'ⱼ/(1-f₀) x'ⱼ  ≥  1
+//    fⱼ≤f₀          fⱼ>f₀                   a'ⱼ≥0            a'ⱼ<0
+//    (integer)      (integer)               (continuous)     (continuous)
+func (o *LinMilp) addGomoryCuts() (ncuts int) {
+
+	// auxiliary
+	lp := &o.Lp
+	nx := lp.Nx
+	αr := make([]float64, lp.nv)
+	type cut struct {
+		π []float64
+		β float64
+	}
+	var newCuts []cut
+
+	// slack variables of previous cuts
+	cutOf := make(map[int]int)
+	for k, c := range o.cuts {
+		cutOf[c.slack] = k
+	}
+
+	// loop over fractional basic integer variables
+	for r, i := range lp.basis {
+		if i >= nx || !o.Integer[i] {
+			continue
+		}
+		β := lp.xv[i]
+		f0 := β - math.Floor(β)
+		if f0 < 0.01 || f0 > 0.99 {
+			continue
+		}
+
+		// tableau row and cut in shifted variables
+		lp.tableauRow(αr, r)
+		π := make([]float64, nx)
+		rhs := 1.0
+		valid := true
+		for j := 0; j < lp.nv; j++ {
+			if lp.stat[j] == sxBasic || lp.art[j] || math.Abs(αr[j]) < 1e-12 {
+				continue
+			}
+			if lp.stat[j] == sxFree {
+				valid = false
+				break
+			}
+			a := αr[j]
+			if lp.stat[j] == sxUpper {
+				a = -a
+			}
+			var g float64
+			isInt := j < nx && o.Integer[j] && lp.xv[j] == math.Floor(lp.xv[j])
+			if isInt {
+				fj := a - math.Floor(a)
+				if fj <= f0 {
+					g = fj / f0
+				} else {
+					g = (1 - fj) / (1 - f0)
+				}
+			} else {
+				if a >= 0 {
+					g = a / f0
+				} else {
+					g = -a / (1 - f0)
+				}
+			}
+			if g == 0 {
+				continue
+			}
+
+			// back to original variables
+			if lp.stat[j] == sxUpper {
+				g = -g
+			}
+			if j < nx {
+				π[j] += g
+				rhs += g * lp.xv[j]
+				continue
+			}
+			k, ok := cutOf[j]
+			if !ok {
+				valid = false
+				break
+			}
+			c := o.cuts[k] // s = β - aᵀx ≥ 0
+			rhs -= g * c.β
+			for p, jj := range c.idx {
+				π[jj] -= g * c.a[p]
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		// check violation
+		viol := rhs
+		for j := 0; j < nx; j++ {
+			viol -= π[j] * lp.xv[j]
+		}
+		if viol < 1e-6 {
+			continue
+		}
+		newCuts = append(newCuts, cut{π, rhs})
+	}
+
+	// add cuts:  Σ π x ≥ rhs  ⇒  Σ -π x ≤ -rhs
+	for _, c := range newCuts {
+		var idx []int
+		var a []float64
+		for j := 0; j < nx; j++ {
+			if math.Abs(c.π[j]) > 1e-12 {
+				idx = append(idx, j)
+				a = append(a, -c.π[j])
+			}
+		}
+		lp.AddConstraint(idx, a, -c.β)
+		o.cuts = append(o.cuts, milpCut{idx, a, -c.β, lp.nv - 2})
+		ncuts++
+	}
+	return
+}
@@ -0,0 +1,820 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// LpStatus defines the status of the solution of a linear program
+type LpStatus int
+
+const (
+
+	// LpUnsolved indicates that the problem has not been solved yet
+	LpUnsolved LpStatus = iota
+
+	// LpOptimal indicates that an optimal solution has been found
+	LpOptimal
+
+	// LpInfeasible indicates that the problem has no feasible solution
+	LpInfeasible
+
+	// LpUnbounded indicates that the objective function is unbounded from below
+	LpUnbounded
+
+	// LpMaxIt indicates that the maximum number of iterations has been reached
+	LpMaxIt
+)
+
+// String returns a description of the status
+func (o LpStatus) String() string {
+	switch o {
+	case LpOptimal:
+		return "optimal"
+	case LpInfeasible:
+		return "infeasible"
+	case LpUnbounded:
+		return "unbounded"
+	case LpMaxIt:
+		return "max number of iterations reached"
+	}
+	return "unsolved"
+}
+
+// status of variables in the simplex method
+const (
+	sxBasic = iota // basic variable
+	sxLower        // nonbasic at lower bound
+	sxUpper        // nonbasic at upper bound
+	sxFree         // nonbasic free variable (at zero)
+)
+
+// SimplexBasis holds a snapshot of the basis in LinSimplex; it can be used for warm starts
+type SimplexBasis struct {
+	basis []int     // basic variables
+	stat  []int     // status of all variables
+	xv    []float64 // values of all variables
+}
+
+// LinSimplex implements the (bounded) revised simplex method for linear programming problems
+//  Solve:
+//          min cᵀx   s.t.   A x = b,  l ≤ x ≤ u
+//           x
+//
+//  The primal simplex method (with phase I based on artificial variables) is used to solve the
+//  problem from scratch and the dual simplex method is used for reoptimisation, e.g. after
+//  changing the bounds or adding constraints. The basis matrix is factorised with a sparse LU
+//  decomposition which is updated with the product-form of the inverse after each iteration.
+type LinSimplex struct {
+
+	// problem
+	A *la.CCMatrix // [Nl][Nx] matrix of constraints
+	B la.Vector    // [Nl] right-hand side
+	C la.Vector    // [Nx] cost vector
+	L la.Vector    // [Nx] lower bounds (may be -∞)
+	U la.Vector    // [Nx] upper bounds (may be +∞)
+
+	// constants
+	NmaxIt  int     // max number of iterations
+	Tol     float64 // tolerance for primal and dual feasibility
+	Nrefact int     // number of basis updates before refactorisation
+
+	// dimensions
+	Nx int // number of x
+	Nl int // number of constraints (including added ones)
+
+	// results
+	Status LpStatus  // status of solution
+	X      la.Vector // [Nx] solution
+	Y      la.Vector // [Nl] dual variables (simplex multipliers)
+	D      la.Vector // [Nx] reduced costs
+	F      float64   // objective value cᵀx
+	Nit    int       // number of iterations performed by the last call to Solve or Resolve
+
+	// variables
+	nv    int       // total number of variables (x, slacks and artificials)
+	cols  []spCol   // [nv] columns of the (augmented) matrix of constraints
+	rhs   []float64 // [Nl] right-hand side (including added constraints)
+	cost  []float64 // [nv] cost of current phase
+	lo    []float64 // [nv] lower bounds
+	up    []float64 // [nv] upper bounds
+	xv    []float64 // [nv] values of variables
+	stat  []int     // [nv] status of variables
+	art   []bool    // [nv] artificial variables
+	artOf []int     // [Nl] artificial variable of each row
+
+	// basis
+	basis    []int   // [Nl] basic variable of each position
+	lu       basisLU // factorisation of basis matrix
+	hasBasis bool    // a valid basis is available
+	bland    bool    // use Bland's rule to avoid cycling
+
+	// workspace
+	wa []float64 // [Nl] workspace
+	wb []float64 // [Nl] workspace
+	wc []float64 // [Nl] workspace
+	wd []float64 // [nv] workspace
+}
+
+// Init initialises LinSimplex
+//  Input:
+//   A -- [nl][nx] matrix of constraints
+//   b -- [nl] right-hand side
+//   c -- [nx] cost vector
+//   l -- [nx] lower bounds; may be nil ⇒ l = 0
+//   u -- [nx] upper bounds; may be nil ⇒ u = +∞
+//   prms -- parameters: "nmaxit", "tol" and "nrefact"
+func (o *LinSimplex) Init(A *la.CCMatrix, b, c, l, u la.Vector, prms dbf.Params) {
+
+	// problem
+	o.A, o.B, o.C = A, b, c
+	m, n, Ap, Ai, Ax := A.Get()
+	if len(b) != m || len(c) != n {
+		chk.Panic("dimensions are incorrect: len(b)=%d (should be %d) and len(c)=%d (should be %d)", len(b), m, len(c), n)
+	}
+	if l == nil {
+		o.L = la.NewVector(n)
+	} else {
+		o.L = l.GetCopy()
+	}
+	if u == nil {
+		o.U = la.NewVector(n)
+		o.U.Fill(math.Inf(+1))
+	} else {
+		o.U = u.GetCopy()
+	}
+
+	// constants
+	o.NmaxIt = 10000
+	o.Tol = 1e-9
+	o.Nrefact = 50
+	for _, p := range prms {
+		switch p.N {
+		case "nmaxit":
+			o.NmaxIt = int(p.V)
+		case "tol":
+			o.Tol = p.V
+		case "nrefact":
+			o.Nrefact = int(p.V)
+		}
+	}
+
+	// dimensions
+	o.Nx, o.Nl = n, m
+
+	// structural variables
+	o.nv = 0
+	o.cols, o.lo, o.up, o.art = nil, nil, nil, nil
+	for j := 0; j < n; j++ {
+		var col spCol
+		for p := Ap[j]; p < Ap[j+1]; p++ {
+			col.i = append(col.i, Ai[p])
+			col.x = append(col.x, Ax[p])
+		}
+		o.addVariable(col, o.L[j], o.U[j], false)
+	}
+
+	// artificial variables
+	o.rhs = make([]float64, m)
+	o.artOf = make([]int, m)
+	for i := 0; i < m; i++ {
+		o.rhs[i] = b[i]
+		o.artOf[i] = o.addVariable(spCol{[]int{i}, []float64{1}}, 0, 0, true)
+	}
+
+	// results
+	o.Status = LpUnsolved
+	o.X = la.NewVector(n)
+	o.D = la.NewVector(n)
+	o.hasBasis = false
+}
+
+// SetBounds sets the bounds of variable x[j]
+func (o *LinSimplex) SetBounds(j int, l, u float64) {
+	if l > u {
+		chk.Panic("lower bound must not be greater than upper bound. %g > %g", l, u)
+	}
+	o.L[j], o.U[j] = l, u
+	o.lo[j], o.up[j] = l, u
+	if o.hasBasis && o.stat[j] != sxBasic {
+		o.placeNonbasic(j)
+	}
+}
+
+// AddConstraint adds the inequality constraint Σ a[k] x[idx[k]] ≤ β
+//  NOTE: a slack variable is added to the problem; it becomes basic in the current basis,
+//        thus Resolve can be called afterwards to reoptimise with the dual simplex method
+func (o *LinSimplex) AddConstraint(idx []int, a []float64, β float64) {
+
+	// new row
+	i := o.Nl
+	o.Nl++
+	o.rhs = append(o.rhs, β)
+	for k, j := range idx {
+		o.cols[j].i = append(o.cols[j].i, i)
+		o.cols[j].x = append(o.cols[j].x, a[k])
+	}
+
+	// slack and artificial variables
+	s := o.addVariable(spCol{[]int{i}, []float64{1}}, 0, math.Inf(+1), false)
+	o.artOf = append(o.artOf, o.addVariable(spCol{[]int{i}, []float64{1}}, 0, 0, true))
+
+	// extend basis with slack variable
+	if o.hasBasis {
+		o.basis = append(o.basis, s)
+		o.stat[s] = sxBasic
+		o.allocWork()
+		o.refactor()
+		o.computeXB()
+	}
+}
+
+// GetBasis returns a snapshot of the current basis (nil if not available)
+func (o *LinSimplex) GetBasis() (b *SimplexBasis) {
+	if !o.hasBasis {
+		return nil
+	}
+	b = new(SimplexBasis)
+	b.basis = append([]int{}, o.basis...)
+	b.stat = append([]int{}, o.stat...)
+	b.xv = append([]float64{}, o.xv...)
+	return
+}
+
+// SetBasis sets the current basis from a snapshot obtained with GetBasis
+func (o *LinSimplex) SetBasis(b *SimplexBasis) {
+	if len(b.basis) != o.Nl || len(b.stat) != o.nv {
+		chk.Panic("basis is not compatible with the current problem. Nl=%d (basis: %d) and nv=%d (basis: %d)", o.Nl, len(b.basis), o.nv, len(b.stat))
+	}
+	o.basis = append(o.basis[:0], b.basis...)
+	copy(o.stat, b.stat)
+	copy(o.xv, b.xv)
+	o.hasBasis = true
+}
+
+// Solve solves the linear programming problem from scratch (phase I and phase II)
+func (o *LinSimplex) Solve(verbose bool) {
+
+	// initial nonbasic variables
+	o.allocWork()
+	for j := 0; j < o.nv; j++ {
+		if o.art[j] {
+			o.lo[j], o.up[j] = 0, 0
+		}
+		o.stat[j] = sxLower
+		o.placeNonbasic(j)
+	}
+
+	// residual r = b - N xN
+	r := o.wa
+	copy(r, o.rhs)
+	for j := 0; j < o.nv; j++ {
+		if o.xv[j] != 0 {
+			for p, i := range o.cols[j].i {
+				r[i] -= o.cols[j].x[p] * o.xv[j]
+			}
+		}
+	}
+
+	// artificial basis
+	o.basis = make([]int, o.Nl)
+	for i := 0; i < o.Nl; i++ {
+		k := o.artOf[i]
+		o.cols[k].x[0] = 1
+		if r[i] < 0 {
+			o.cols[k].x[0] = -1
+		}
+		o.lo[k], o.up[k] = 0, math.Inf(+1)
+		o.xv[k] = math.Abs(r[i])
+		o.stat[k] = sxBasic
+		o.basis[i] = k
+	}
+	o.refactor()
+	o.hasBasis = true
+
+	// phase I
+	for j := 0; j < o.nv; j++ {
+		o.cost[j] = 0
+		if o.art[j] {
+			o.cost[j] = 1
+		}
+	}
+	if verbose {
+		io.Pf("phase I\n")
+	}
+	o.Nit = 0
+	o.Status = o.primal(verbose)
+	if o.Status != LpOptimal {
+		return
+	}
+	infeas := 0.0
+	for i := 0; i < o.Nl; i++ {
+		infeas += o.xv[o.artOf[i]]
+	}
+	if infeas > o.Tol*(1.0+la.Vector(o.rhs).Norm()) {
+		o.Status = LpInfeasible
+		return
+	}
+
+	// phase II
+	for i := 0; i < o.Nl; i++ {
+		o.up[o.artOf[i]] = 0
+	}
+	o.setCost()
+	if verbose {
+		io.Pf("phase II\n")
+	}
+	o.Status = o.primal(verbose)
+	o.results()
+}
+
+// Resolve reoptimises the problem after changes in bounds or added constraints, starting from
+// the current basis. The dual simplex method is used if the basis is dual feasible; otherwise,
+// the primal simplex method is used. Solve is called if there is no basis available.
+func (o *LinSimplex) Resolve(verbose bool) {
+
+	// check
+	if !o.hasBasis {
+		o.Solve(verbose)
+		return
+	}
+
+	// restart factorisation and nonbasic variables
+	o.allocWork()
+	for i := 0; i < o.Nl; i++ {
+		o.up[o.artOf[i]] = 0
+	}
+	for j := 0; j < o.nv; j++ {
+		if o.stat[j] != sxBasic {
+			o.placeNonbasic(j)
+		}
+	}
+	if o.refactor() {
+		o.Solve(verbose)
+		return
+	}
+	o.computeXB()
+	o.setCost()
+	o.Nit = 0
+
+	// dual simplex
+	if o.makeDualFeasible() {
+		if verbose {
+			io.Pf("dual simplex\n")
+		}
+		o.Status = o.dual(verbose)
+		if o.Status == LpOptimal || o.Status == LpInfeasible {
+			o.results()
+			return
+		}
+	}
+
+	// primal simplex
+	if o.primalFeasible() {
+		if verbose {
+			io.Pf("primal simplex\n")
+		}
+		o.Status = o.primal(verbose)
+		o.results()
+		return
+	}
+	o.Solve(verbose)
+}
+
+// auxiliary ///////////////////////////////////////////////////////////////////////////////////////
+
+// addVariable adds a new variable and returns its index
+func (o *LinSimplex) addVariable(col spCol, lo, up float64, art bool) (j int) {
+	j = o.nv
+	o.nv++
+	o.cols = append(o.cols, col)
+	o.lo = append(o.lo, lo)
+	o.up = append(o.up, up)
+	o.art = append(o.art, art)
+	o.cost = append(o.cost, 0)
+	o.xv = append(o.xv, 0)
+	o.stat = append(o.stat, sxLower)
+	return
+}
+
+// removeConstraints removes the constraints (rows) added after the first nl ones together with
+// the variables added after the first nv ones (i.e. the slack and artificial variables of the
+// removed constraints). The basis becomes unavailable
+func (o *LinSimplex) removeConstraints(nl, nv int) {
+	if nl == o.Nl && nv == o.nv {
+		return
+	}
+	for j := 0; j < nv; j++ {
+		col := &o.cols[j]
+		n := 0
+		for p, i := range col.i {
+			if i < nl {
+				col.i[n], col.x[n] = i, col.x[p]
+				n++
+			}
+		}
+		col.i, col.x = col.i[:n], col.x[:n]
+	}
+	o.Nl, o.nv = nl, nv
+	o.rhs, o.artOf = o.rhs[:nl], o.artOf[:nl]
+	o.cols, o.lo, o.up, o.art = o.cols[:nv], o.lo[:nv], o.up[:nv], o.art[:nv]
+	o.cost, o.xv, o.stat = o.cost[:nv], o.xv[:nv], o.stat[:nv]
+	o.hasBasis = false
+}
+
+// allocWork allocates workspace
+func (o *LinSimplex) allocWork() {
+	if len(o.wa) != o.Nl {
+		o.wa = make([]float64, o.Nl)
+		o.wb = make([]float64, o.Nl)
+		o.wc = make([]float64, o.Nl)
+	}
+	if len(o.wd) != o.nv {
+		o.wd = make([]float64, o.nv)
+	}
+}
+
+// setCost sets the cost vector of phase II
+func (o *LinSimplex) setCost() {
+	for j := 0; j < o.nv; j++ {
+		o.cost[j] = 0
+		if j < o.Nx {
+			o.cost[j] = o.C[j]
+		}
+	}
+}
+
+// placeNonbasic sets the value of a nonbasic variable according to its status and bounds
+func (o *LinSimplex) placeNonbasic(j int) {
+	lInf, uInf := math.IsInf(o.lo[j], -1), math.IsInf(o.up[j], +1)
+	switch {
+	case o.stat[j] == sxUpper && !uInf:
+		o.xv[j] = o.up[j]
+	case !lInf:
+		o.stat[j], o.xv[j] = sxLower, o.lo[j]
+	case !uInf:
+		o.stat[j], o.xv[j] = sxUpper, o.up[j]
+	default:
+		o.stat[j], o.xv[j] = sxFree, 0
+	}
+}
+
+// refactor factorises the basis matrix
+func (o *LinSimplex) refactor() (singular bool) {
+	cols := make([]spCol, o.Nl)
+	for k, j := range o.basis {
+		cols[k] = o.cols[j]
+	}
+	return o.lu.factor(o.Nl, cols)
+}
+
+// computeXB computes the basic variables: xB = B⁻¹ (b - N xN)
+func (o *LinSimplex) computeXB() {
+	r := o.wa
+	copy(r, o.rhs)
+	for j := 0; j < o.nv; j++ {
+		if o.stat[j] != sxBasic && o.xv[j] != 0 {
+			for p, i := range o.cols[j].i {
+				r[i] -= o.cols[j].x[p] * o.xv[j]
+			}
+		}
+	}
+	o.lu.ftran(o.wb, r)
+	for k, j := range o.basis {
+		o.xv[j] = o.wb[k]
+	}
+}
+
+// computeDuals computes y = B⁻ᵀ cB and the reduced costs d = c - Aᵀ y (of nonbasic variables)
+func (o *LinSimplex) computeDuals(y, d []float64) {
+	cB := o.wc
+	for k, j := range o.basis {
+		cB[k] = o.cost[j]
+	}
+	o.lu.btran(y, cB)
+	for j := 0; j < o.nv; j++ {
+		d[j] = 0
+		if o.stat[j] != sxBasic {
+			d[j] = o.cost[j] - o.dot(y, j)
+		}
+	}
+}
+
+// dot computes yᵀ a_j
+func (o *LinSimplex) dot(y []float64, j int) (res float64) {
+	for p, i := range o.cols[j].i {
+		res += o.cols[j].x[p] * y[i]
+	}
+	return
+}
+
+// fixed returns whether variable j is fixed
+func (o *LinSimplex) fixed(j int) bool {
+	return o.up[j]-o.lo[j] <= o.Tol
+}
+
+// scatter scatters the column of variable j into dense vector a
+func (o *LinSimplex) scatter(a []float64, j int) {
+	for i := range a {
+		a[i] = 0
+	}
+	for p, i := range o.cols[j].i {
+		a[i] += o.cols[j].x[p]
+	}
+}
+
+// pivot replaces the basic variable at position r by variable q. α = B⁻¹ a_q
+func (o *LinSimplex) pivot(r, q int, α []float64) {
+	o.basis[r] = q
+	o.stat[q] = sxBasic
+	o.lu.update(r, α)
+	if o.lu.neta() >= o.Nrefact {
+		if o.refactor() {
+			chk.Panic("basis matrix became singular")
+		}
+		o.computeXB()
+	}
+}
+
+// primalFeasible checks whether the basic variables are within bounds
+func (o *LinSimplex) primalFeasible() bool {
+	for _, j := range o.basis {
+		if o.xv[j] < o.lo[j]-o.Tol || o.xv[j] > o.up[j]+o.Tol {
+			return false
+		}
+	}
+	return true
+}
+
+// makeDualFeasible moves nonbasic variables between bounds to obtain a dual feasible basis
+//  Output:
+//   ok -- a dual feasible basis has been obtained
+func (o *LinSimplex) makeDualFeasible() (ok bool) {
+	o.computeDuals(o.wa, o.wd)
+	moved := false
+	for j := 0; j < o.nv; j++ {
+		if o.stat[j] == sxBasic || o.fixed(j) {
+			continue
+		}
+		d := o.wd[j]
+		switch {
+		case o.stat[j] == sxLower && d < -o.Tol:
+			if math.IsInf(o.up[j], +1) {
+				return false
+			}
+			o.stat[j], o.xv[j], moved = sxUpper, o.up[j], true
+		case o.stat[j] == sxUpper && d > o.Tol:
+			if math.IsInf(o.lo[j], -1) {
+				return false
+			}
+			o.stat[j], o.xv[j], moved = sxLower, o.lo[j], true
+		case o.stat[j] == sxFree && math.Abs(d) > o.Tol:
+			return false
+		}
+	}
+	if moved {
+		o.computeXB()
+	}
+	return true
+}
+
+// primal runs the primal simplex method
+func (o *LinSimplex) primal(verbose bool) LpStatus {
+	y, d, a, α := o.wa, o.wd, o.wb, o.wc
+	ndegen := 0
+	o.bland = false
+	if verbose {
+		io.Pf("%6s%23s%8s%8s\n", "it", "f(x)", "enter", "leave")
+	}
+	for ; o.Nit < o.NmaxIt; o.Nit++ {
+
+		// pricing
+		o.computeDuals(y, d)
+		q, dmax := -1, 0.0
+		for j := 0; j < o.nv; j++ {
+			if o.stat[j] == sxBasic || o.fixed(j) {
+				continue
+			}
+			eligible := (o.stat[j] == sxLower && d[j] < -o.Tol) ||
+				(o.stat[j] == sxUpper && d[j] > o.Tol) ||
+				(o.stat[j] == sxFree && math.Abs(d[j]) > o.Tol)
+			if eligible {
+				if o.bland {
+					q = j
+					break
+				}
+				if math.Abs(d[j]) > dmax {
+					q, dmax = j, math.Abs(d[j])
+				}
+			}
+		}
+		if q < 0 {
+			return LpOptimal
+		}
+		dir := 1.0
+		if d[q] > 0 {
+			dir = -1.0
+		}
+
+		// ratio test
+		o.scatter(a, q)
+		o.lu.ftran(α, a)
+		r, t := -1, o.up[q]-o.lo[q]
+		toLower := false
+		for k, j := range o.basis {
+			s := dir * α[k]
+			var tk float64
+			var lower bool
+			switch {
+			case s > 1e-9:
+				if math.IsInf(o.lo[j], -1) {
+					continue
+				}
+				tk, lower = (o.xv[j]-o.lo[j])/s, true
+			case s < -1e-9:
+				if math.IsInf(o.up[j], +1) {
+					continue
+				}
+				tk, lower = (o.up[j]-o.xv[j])/(-s), false
+			default:
+				continue
+			}
+			if tk < 0 {
+				tk = 0
+			}
+			if tk < t || (tk == t && r >= 0 && o.bland && j < o.basis[r]) {
+				r, t, toLower = k, tk, lower
+			}
+		}
+		if math.IsInf(t, +1) {
+			return LpUnbounded
+		}
+
+		// update variables
+		o.xv[q] += dir * t
+		for k, j := range o.basis {
+			o.xv[j] -= dir * t * α[k]
+		}
+		if t < o.Tol {
+			ndegen++
+			o.bland = ndegen > 50
+		} else {
+			ndegen = 0
+			o.bland = false
+		}
+		if verbose {
+			io.Pf("%6d%23.15e%8d%8d\n", o.Nit, o.objective(), q, r)
+		}
+
+		// bound flip
+		if r < 0 {
+			if dir > 0 {
+				o.stat[q], o.xv[q] = sxUpper, o.up[q]
+			} else {
+				o.stat[q], o.xv[q] = sxLower, o.lo[q]
+			}
+			continue
+		}
+
+		// change basis
+		l := o.basis[r]
+		if toLower {
+			o.stat[l], o.xv[l] = sxLower, o.lo[l]
+		} else {
+			o.stat[l], o.xv[l] = sxUpper, o.up[l]
+		}
+		o.pivot(r, q, α)
+	}
+	return LpMaxIt
+}
+
+// dual runs the dual simplex method
+func (o *LinSimplex) dual(verbose bool) LpStatus {
+	y, d, ρ, α := o.wa, o.wd, o.wb, o.wc
+	e := make([]float64, o.Nl)
+	a := make([]float64, o.Nl)
+	if verbose {
+		io.Pf("%6s%23s%8s%8s\n", "it", "f(x)", "enter", "leave")
+	}
+	for ; o.Nit < o.NmaxIt; o.Nit++ {
+
+		// select leaving variable
+		r, δmax, δ := -1, 0.0, 0.0
+		for k, j := range o.basis {
+			var dk float64
+			if o.xv[j] < o.lo[j]-o.Tol {
+				dk = o.xv[j] - o.lo[j]
+			} else if o.xv[j] > o.up[j]+o.Tol {
+				dk = o.xv[j] - o.up[j]
+			}
+			if math.Abs(dk) > δmax {
+				r, δmax, δ = k, math.Abs(dk), dk
+			}
+		}
+		if r < 0 {
+			return LpOptimal
+		}
+
+		// row of tableau
+		o.computeDuals(y, d)
+		for i := range e {
+			e[i] = 0
+		}
+		e[r] = 1
+		o.lu.btran(ρ, e)
+
+		// ratio test
+		q, θ, αrq := -1, math.Inf(+1), 0.0
+		for j := 0; j < o.nv; j++ {
+			if o.stat[j] == sxBasic || o.fixed(j) {
+				continue
+			}
+			αrj := o.dot(ρ, j)
+			if math.Abs(αrj) < 1e-9 {
+				continue
+			}
+			s := αrj
+			if δ > 0 {
+				s = -αrj
+			}
+			eligible := (o.stat[j] == sxLower && s < 0) ||
+				(o.stat[j] == sxUpper && s > 0) ||
+				o.stat[j] == sxFree
+			if !eligible {
+				continue
+			}
+			ratio := math.Abs(d[j] / αrj)
+			if ratio < θ || (ratio == θ && math.Abs(αrj) > math.Abs(αrq)) {
+				q, θ, αrq = j, ratio, αrj
+			}
+		}
+		if q < 0 {
+			return LpInfeasible
+		}
+
+		// update variables
+		o.scatter(a, q)
+		o.lu.ftran(α, a)
+		Δ := δ / α[r]
+		o.xv[q] += Δ
+		for k, j := range o.basis {
+			o.xv[j] -= Δ * α[k]
+		}
+		if verbose {
+			io.Pf("%6d%23.15e%8d%8d\n", o.Nit, o.objective(), q, r)
+		}
+
+		// change basis
+		l := o.basis[r]
+		if δ < 0 {
+			o.stat[l], o.xv[l] = sxLower, o.lo[l]
+		} else {
+			o.stat[l], o.xv[l] = sxUpper, o.up[l]
+		}
+		o.pivot(r, q, α)
+	}
+	return LpMaxIt
+}
+
+// objective computes the objective function of the current phase
+func (o *LinSimplex) objective() (res float64) {
+	for j := 0; j < o.nv; j++ {
+		res += o.cost[j] * o.xv[j]
+	}
+	return
+}
+
+// results sets results
+func (o *LinSimplex) results() {
+	if o.Status != LpOptimal {
+		return
+	}
+	o.Y = la.NewVector(o.Nl)
+	o.computeDuals(o.Y, o.wd)
+	copy(o.X, o.xv[:o.Nx])
+	copy(o.D, o.wd[:o.Nx])
+	o.F = la.VecDot(o.C, o.X)
+}
+
+// tableauRow computes the row of the simplex tableau corresponding to basis position r;
+// i.e. αr = e_rᵀ B⁻¹ A (for nonbasic variables; zero for basic ones)
+func (o *LinSimplex) tableauRow(αr []float64, r int) {
+	e := make([]float64, o.Nl)
+	ρ := make([]float64, o.Nl)
+	e[r] = 1
+	o.lu.btran(ρ, e)
+	for j := 0; j < o.nv; j++ {
+		αr[j] = 0
+		if o.stat[j] != sxBasic {
+			αr[j] = o.dot(ρ, j)
+		}
+	}
+}
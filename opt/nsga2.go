@@ -0,0 +1,392 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+	"github.com/cpmech/gosl/utl"
+)
+
+// Nsga2 implements the non-dominated sorting genetic algorithm II (Deb et al. 2002)
+//  Solve:
+//          min {f_0(x), f_1(x), ...}   s.t.   g(x) ≤ 0,  xmin ≤ x ≤ xmax
+//           x
+//
+//  The offspring is generated by binary tournament selection, simulated binary crossover (SBX)
+//  and polynomial mutation. Parents and offspring are ranked by the fast non-dominated sorting
+//  with constrained domination and the crowding distance (see utl.ParetoMin and
+//  utl.ParetoCrowding). A solution "a" constrained-dominates "b" if:
+//   1) a is feasible and b is not; or
+//   2) both are infeasible and a has the smaller constraint violation; or
+//   3) both are feasible and a dominates b in the Pareto sense
+//
+//  Reference:
+//   Deb K, Pratap A, Agarwal S, Meyarivan T (2002) A fast and elitist multiobjective genetic
+//   algorithm: NSGA-II. IEEE Transactions on Evolutionary Computation, 6(2):182-197
+type Nsga2 struct {
+
+	// problem
+	Ndim int       // dimension of x
+	Nf   int       // number of objectives
+	Xmin la.Vector // [Ndim] lower limits
+	Xmax la.Vector // [Ndim] upper limits
+
+	// constants
+	Npop     int       // population size (even)
+	NmaxGen  int       // number of generations
+	Pc       float64   // crossover probability
+	EtaC     float64   // distribution index of SBX crossover
+	Pm       float64   // mutation probability (of each variable); default = 1/Ndim
+	EtaM     float64   // distribution index of polynomial mutation
+	Ref      []float64 // [Nf] reference point to compute hypervolumes; may be nil ⇒ no history
+	Sampling string    // initial population: "lhs", "halton" or "uniform"
+	Seed     int       // seed for the random numbers generator; use Seed ≤ 0 to use current time
+
+	// results
+	X       []la.Vector // [nfront] solutions in the first (non-dominated) front
+	F       [][]float64 // [nfront][Nf] objective values of solutions in the first front
+	Viol    []float64   // [nfront] constraint violations of solutions in the first front (zero if feasible)
+	Ngen    int         // number of generations
+	Nfeval  int         // number of function evaluations
+	History []float64   // [Ngen+1] hypervolume of the first front at each generation (if Ref != nil)
+
+	// auxiliary
+	ffcn fun.Vv      // objective functions
+	ng   int         // number of inequality constraints
+	gfcn fun.Vv      // inequality constraints
+	gval la.Vector   // [ng] constraint values
+	x    []la.Vector // [2⋅Npop] parents and offspring
+	f    [][]float64 // [2⋅Npop][Nf] objective values
+	v    []float64   // [2⋅Npop] constraint violations
+	rank []int       // [2⋅Npop] ranks (index of front)
+	dist []float64   // [2⋅Npop] crowding distances
+}
+
+// Init initialises Nsga2
+//  Input:
+//   xmin -- [ndim] lower limits
+//   xmax -- [ndim] upper limits
+//   nf   -- number of objectives
+//   ffcn -- objective functions f(x) with len(f) = nf
+//   ng   -- number of inequality constraints
+//   gfcn -- inequality constraints g(x) ≤ 0; may be nil if ng == 0
+//   prms -- parameters: "npop", "nmaxgen", "pc", "etac", "pm", "etam", "seed"
+func (o *Nsga2) Init(xmin, xmax la.Vector, nf int, ffcn fun.Vv, ng int, gfcn fun.Vv, prms dbf.Params) {
+
+	// problem
+	evoCheckBounds(xmin, xmax)
+	if nf < 1 || ffcn == nil {
+		chk.Panic("number of objectives must be at least 1 and the objective function must be given. nf = %d", nf)
+	}
+	if ng > 0 && gfcn == nil {
+		chk.Panic("constraints function must be given when ng = %d > 0", ng)
+	}
+	o.Ndim = len(xmin)
+	o.Nf = nf
+	o.Xmin = xmin.GetCopy()
+	o.Xmax = xmax.GetCopy()
+	o.ffcn = ffcn
+	o.ng = ng
+	o.gfcn = gfcn
+	o.gval = la.NewVector(ng)
+
+	// constants
+	o.Npop = 100
+	o.NmaxGen = 250
+	o.Pc = 0.9
+	o.EtaC = 15
+	o.Pm = 1.0 / float64(o.Ndim)
+	o.EtaM = 20
+	o.Ref = nil
+	o.Sampling = "lhs"
+	o.Seed = 0
+	for _, p := range prms {
+		switch p.N {
+		case "npop":
+			o.Npop = int(p.V)
+		case "nmaxgen":
+			o.NmaxGen = int(p.V)
+		case "pc":
+			o.Pc = p.V
+		case "etac":
+			o.EtaC = p.V
+		case "pm":
+			o.Pm = p.V
+		case "etam":
+			o.EtaM = p.V
+		case "seed":
+			o.Seed = int(p.V)
+		}
+	}
+}
+
+// Solve solves the multi-objective optimisation problem
+func (o *Nsga2) Solve(verbose bool) {
+
+	// check
+	if o.Npop < 4 || o.Npop%2 != 0 {
+		chk.Panic("population size must be even and at least 4. Npop = %d is invalid", o.Npop)
+	}
+	if o.Ref != nil && len(o.Ref) != o.Nf {
+		chk.Panic("reference point must have length equal to the number of objectives. %d != %d", len(o.Ref), o.Nf)
+	}
+
+	// allocate parents and offspring
+	rnd.Init(o.Seed)
+	o.Nfeval = 0
+	N := o.Npop
+	o.x = make([]la.Vector, 2*N)
+	o.f = utl.Alloc(2*N, o.Nf)
+	o.v = make([]float64, 2*N)
+	o.rank = make([]int, 2*N)
+	o.dist = make([]float64, 2*N)
+	for i := 0; i < 2*N; i++ {
+		o.x[i] = la.NewVector(o.Ndim)
+	}
+
+	// initial population
+	evoSample(o.x[:N], o.Xmin, o.Xmax, o.Sampling)
+	for i := 0; i < N; i++ {
+		o.eval(i)
+	}
+	o.rankAndCrowd(N)
+	o.Ngen = 0
+	o.History = nil
+	o.recordHistory(N)
+
+	// message
+	if verbose {
+		io.Pf("%5s%10s%23s%10s\n", "gen", "nfront", "hypervolume", "nfeval")
+		o.message(N)
+	}
+
+	// generations
+	order := make([]int, 2*N)
+	for o.Ngen = 0; o.Ngen < o.NmaxGen; {
+		o.Ngen++
+
+		// offspring
+		for i := N; i < 2*N; i += 2 {
+			a, b := o.tournament(N), o.tournament(N)
+			o.crossover(o.x[i], o.x[i+1], o.x[a], o.x[b])
+			o.mutation(o.x[i])
+			o.mutation(o.x[i+1])
+			o.eval(i)
+			o.eval(i + 1)
+		}
+
+		// select next population: sort by rank and crowding distance and move the best to the top
+		o.rankAndCrowd(2 * N)
+		for i := 0; i < 2*N; i++ {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return o.crowdedLess(order[a], order[b]) })
+		o.permute(order)
+
+		// ranks and crowding distances of the new population
+		o.rankAndCrowd(N)
+		o.recordHistory(N)
+		if verbose {
+			o.message(N)
+		}
+	}
+
+	// results
+	o.X, o.F, o.Viol = nil, nil, nil
+	for i := 0; i < N; i++ {
+		if o.rank[i] == 0 {
+			o.X = append(o.X, o.x[i].GetCopy())
+			o.F = append(o.F, utl.GetCopy(o.f[i]))
+			o.Viol = append(o.Viol, o.v[i])
+		}
+	}
+}
+
+// auxiliary /////////////////////////////////////////////////////////////////////////////////////
+
+// eval evaluates the objectives and constraints of individual i
+func (o *Nsga2) eval(i int) {
+	o.Nfeval++
+	o.ffcn(o.f[i], o.x[i])
+	o.v[i] = 0
+	if o.ng > 0 {
+		o.gfcn(o.gval, o.x[i])
+		o.v[i] = evoViolation(o.gval)
+	}
+}
+
+// dominates implements the constrained domination
+func (o *Nsga2) dominates(a, b int) bool {
+	if o.v[a] > 0 || o.v[b] > 0 {
+		return o.v[a] < o.v[b]
+	}
+	aDominates, _ := utl.ParetoMin(o.f[a], o.f[b])
+	return aDominates
+}
+
+// crowdedLess implements the crowded-comparison operator: a is better than b
+func (o *Nsga2) crowdedLess(a, b int) bool {
+	if o.rank[a] != o.rank[b] {
+		return o.rank[a] < o.rank[b]
+	}
+	return o.dist[a] > o.dist[b]
+}
+
+// rankAndCrowd computes the ranks (fast non-dominated sorting) and crowding distances of the
+// first n individuals
+func (o *Nsga2) rankAndCrowd(n int) {
+
+	// domination counts and dominated sets
+	count := make([]int, n)
+	dominated := make([][]int, n)
+	var front []int
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			if o.dominates(a, b) {
+				dominated[a] = append(dominated[a], b)
+				count[b]++
+			} else if o.dominates(b, a) {
+				dominated[b] = append(dominated[b], a)
+				count[a]++
+			}
+		}
+	}
+	for a := 0; a < n; a++ {
+		if count[a] == 0 {
+			front = append(front, a)
+		}
+	}
+
+	// fronts
+	for r := 0; len(front) > 0; r++ {
+		dist := utl.ParetoCrowding(o.f, front)
+		var next []int
+		for k, a := range front {
+			o.rank[a] = r
+			o.dist[a] = dist[k]
+			for _, b := range dominated[a] {
+				count[b]--
+				if count[b] == 0 {
+					next = append(next, b)
+				}
+			}
+		}
+		front = next
+	}
+}
+
+// permute reorders the individuals
+func (o *Nsga2) permute(order []int) {
+	x := make([]la.Vector, len(order))
+	f := make([][]float64, len(order))
+	v := make([]float64, len(order))
+	for k, i := range order {
+		x[k], f[k], v[k] = o.x[i], o.f[i], o.v[i]
+	}
+	o.x, o.f, o.v = x, f, v
+}
+
+// tournament performs the binary tournament selection among the first n individuals
+func (o *Nsga2) tournament(n int) int {
+	a, b := rand.Intn(n), rand.Intn(n)
+	if o.crowdedLess(b, a) {
+		return b
+	}
+	return a
+}
+
+// crossover performs the simulated binary crossover (SBX) with bounds
+func (o *Nsga2) crossover(c1, c2, p1, p2 la.Vector) {
+	c1.Apply(1, p1)
+	c2.Apply(1, p2)
+	if rand.Float64() > o.Pc {
+		return
+	}
+	η := o.EtaC
+	betaq := func(β float64) float64 {
+		u := rand.Float64()
+		α := 2 - math.Pow(β, -(η+1))
+		if u <= 1/α {
+			return math.Pow(u*α, 1/(η+1))
+		}
+		return math.Pow(1/(2-u*α), 1/(η+1))
+	}
+	for j := 0; j < o.Ndim; j++ {
+		if rand.Float64() > 0.5 || math.Abs(p1[j]-p2[j]) < 1e-14 {
+			continue
+		}
+		y1, y2 := math.Min(p1[j], p2[j]), math.Max(p1[j], p2[j])
+		yl, yu := o.Xmin[j], o.Xmax[j]
+		a := 0.5 * ((y1 + y2) - betaq(1+2*(y1-yl)/(y2-y1))*(y2-y1))
+		b := 0.5 * ((y1 + y2) + betaq(1+2*(yu-y2)/(y2-y1))*(y2-y1))
+		a = math.Max(yl, math.Min(yu, a))
+		b = math.Max(yl, math.Min(yu, b))
+		if rand.Float64() <= 0.5 {
+			a, b = b, a
+		}
+		c1[j], c2[j] = a, b
+	}
+}
+
+// mutation performs the polynomial mutation with bounds
+func (o *Nsga2) mutation(x la.Vector) {
+	η := o.EtaM
+	for j := 0; j < o.Ndim; j++ {
+		if rand.Float64() > o.Pm {
+			continue
+		}
+		yl, yu := o.Xmin[j], o.Xmax[j]
+		δ1 := (x[j] - yl) / (yu - yl)
+		δ2 := (yu - x[j]) / (yu - yl)
+		r := rand.Float64()
+		var δq float64
+		if r < 0.5 {
+			val := 2*r + (1-2*r)*math.Pow(1-δ1, η+1)
+			δq = math.Pow(val, 1/(η+1)) - 1
+		} else {
+			val := 2*(1-r) + 2*(r-0.5)*math.Pow(1-δ2, η+1)
+			δq = 1 - math.Pow(val, 1/(η+1))
+		}
+		x[j] = math.Max(yl, math.Min(yu, x[j]+δq*(yu-yl)))
+	}
+}
+
+// recordHistory computes the hypervolume of the feasible solutions in the first front
+func (o *Nsga2) recordHistory(n int) {
+	if o.Ref == nil {
+		return
+	}
+	var front [][]float64
+	for i := 0; i < n; i++ {
+		if o.rank[i] == 0 && o.v[i] == 0 {
+			front = append(front, o.f[i])
+		}
+	}
+	o.History = append(o.History, utl.ParetoHypervolume(front, o.Ref))
+}
+
+// message prints information about the current generation
+func (o *Nsga2) message(n int) {
+	nfront := 0
+	for i := 0; i < n; i++ {
+		if o.rank[i] == 0 {
+			nfront++
+		}
+	}
+	hv := 0.0
+	if len(o.History) > 0 {
+		hv = o.History[len(o.History)-1]
+	}
+	io.Pf("%5d%10d%23.15e%10d\n", o.Ngen, nfront, hv, o.Nfeval)
+}
@@ -0,0 +1,219 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math/rand"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/rnd"
+)
+
+// ParticleSwarm implements the particle swarm optimisation method with constriction coefficients
+//  Solve:
+//          min f(x)   s.t.   g(x) ≤ 0,  xmin ≤ x ≤ xmax
+//           x
+//
+//  The velocities are updated with (Clerc and Kennedy 2002):
+//
+//      v ← W ⋅ v + C1 ⋅ r1 ⋅ (p - x) + C2 ⋅ r2 ⋅ (g - x)
+//
+//  where p is the best position of the particle and g is the best position of the swarm. The
+//  velocities are clamped to Vmax ⋅ (xmax - xmin) and particles hitting the box are stopped at
+//  the boundary. The best positions are selected by Deb's feasibility rules.
+type ParticleSwarm struct {
+
+	// problem
+	Ndim int       // dimension of x
+	Xmin la.Vector // [Ndim] lower limits
+	Xmax la.Vector // [Ndim] upper limits
+
+	// constants
+	Npop     int     // number of particles
+	NmaxGen  int     // max number of iterations (generations)
+	Nstall   int     // stop if the best value does not improve by more than Ftol during Nstall generations
+	Ftol     float64 // tolerance for the stagnation criterion
+	W        float64 // inertia weight
+	C1       float64 // cognitive coefficient
+	C2       float64 // social coefficient
+	Vmax     float64 // max velocity as a fraction of (xmax - xmin)
+	Sampling string  // initial positions: "lhs", "halton" or "uniform"
+	Seed     int     // seed for the random numbers generator; use Seed ≤ 0 to use current time
+
+	// results
+	Xbest   la.Vector // [Ndim] best solution
+	Fbest   float64   // best objective value
+	Viol    float64   // constraint violation of best solution (zero if feasible)
+	Ngen    int       // number of generations
+	Nfeval  int       // number of function evaluations
+	History []float64 // [Ngen+1] best objective value at each generation (convergence history)
+
+	// auxiliary
+	obj evoObjective // objective and constraints
+}
+
+// Init initialises ParticleSwarm
+//  Input:
+//   xmin -- [ndim] lower limits
+//   xmax -- [ndim] upper limits
+//   ffcn -- objective function f(x)
+//   ng   -- number of inequality constraints
+//   gfcn -- inequality constraints g(x) ≤ 0; may be nil if ng == 0
+//   prms -- parameters: "npop", "nmaxgen", "nstall", "ftol", "W", "C1", "C2", "vmax", "seed"
+func (o *ParticleSwarm) Init(xmin, xmax la.Vector, ffcn fun.Sv, ng int, gfcn fun.Vv, prms dbf.Params) {
+
+	// problem
+	evoCheckBounds(xmin, xmax)
+	o.Ndim = len(xmin)
+	o.Xmin = xmin.GetCopy()
+	o.Xmax = xmax.GetCopy()
+	o.obj.init(ffcn, ng, gfcn)
+
+	// constants
+	o.Npop = 40
+	o.NmaxGen = 1000
+	o.Nstall = 100
+	o.Ftol = 1e-12
+	o.W = 0.7298
+	o.C1 = 1.49618
+	o.C2 = 1.49618
+	o.Vmax = 0.2
+	o.Sampling = "lhs"
+	o.Seed = 0
+	for _, p := range prms {
+		switch p.N {
+		case "npop":
+			o.Npop = int(p.V)
+		case "nmaxgen":
+			o.NmaxGen = int(p.V)
+		case "nstall":
+			o.Nstall = int(p.V)
+		case "ftol":
+			o.Ftol = p.V
+		case "W":
+			o.W = p.V
+		case "C1":
+			o.C1 = p.V
+		case "C2":
+			o.C2 = p.V
+		case "vmax":
+			o.Vmax = p.V
+		case "seed":
+			o.Seed = int(p.V)
+		}
+	}
+
+	// results
+	o.Xbest = la.NewVector(o.Ndim)
+}
+
+// Solve solves the optimisation problem
+func (o *ParticleSwarm) Solve(verbose bool) {
+
+	// check
+	if o.Npop < 2 {
+		chk.Panic("number of particles must be at least 2. Npop = %d is invalid", o.Npop)
+	}
+
+	// initial positions and velocities
+	rnd.Init(o.Seed)
+	o.obj.nfeval = 0
+	n := o.Ndim
+	X := make([]la.Vector, o.Npop) // positions
+	V := make([]la.Vector, o.Npop) // velocities
+	P := make([]la.Vector, o.Npop) // best positions of particles
+	fp := make([]float64, o.Npop)  // objective values at best positions
+	vp := make([]float64, o.Npop)  // constraint violations at best positions
+	vmax := la.NewVector(n)
+	for j := 0; j < n; j++ {
+		vmax[j] = o.Vmax * (o.Xmax[j] - o.Xmin[j])
+	}
+	for i := 0; i < o.Npop; i++ {
+		X[i] = la.NewVector(n)
+		V[i] = la.NewVector(n)
+		for j := 0; j < n; j++ {
+			V[i][j] = rnd.Float64(-vmax[j], vmax[j])
+		}
+	}
+	evoSample(X, o.Xmin, o.Xmax, o.Sampling)
+	ibest := 0
+	for i := 0; i < o.Npop; i++ {
+		P[i] = X[i].GetCopy()
+		fp[i], vp[i] = o.obj.eval(X[i])
+		if evoBetter(fp[i], vp[i], fp[ibest], vp[ibest]) {
+			ibest = i
+		}
+	}
+	o.History = []float64{fp[ibest]}
+
+	// message
+	if verbose {
+		io.Pf("%5s%23s%23s%10s\n", "gen", "fbest", "viol", "nfeval")
+		io.Pf("%5d%23.15e%23.15e%10d\n", 0, fp[ibest], vp[ibest], o.obj.nfeval)
+	}
+
+	// iterations
+	for o.Ngen = 0; o.Ngen < o.NmaxGen; {
+		o.Ngen++
+		g := P[ibest]
+		for i := 0; i < o.Npop; i++ {
+
+			// update velocity and position
+			for j := 0; j < n; j++ {
+				v := o.W*V[i][j] + o.C1*rand.Float64()*(P[i][j]-X[i][j]) + o.C2*rand.Float64()*(g[j]-X[i][j])
+				if v > vmax[j] {
+					v = vmax[j]
+				}
+				if v < -vmax[j] {
+					v = -vmax[j]
+				}
+				x := X[i][j] + v
+				if x < o.Xmin[j] {
+					x, v = o.Xmin[j], 0
+				}
+				if x > o.Xmax[j] {
+					x, v = o.Xmax[j], 0
+				}
+				X[i][j], V[i][j] = x, v
+			}
+
+			// update best positions
+			f, viol := o.obj.eval(X[i])
+			if !evoBetter(fp[i], vp[i], f, viol) {
+				P[i].Apply(1, X[i])
+				fp[i], vp[i] = f, viol
+			}
+		}
+		for i := 0; i < o.Npop; i++ {
+			if evoBetter(fp[i], vp[i], fp[ibest], vp[ibest]) {
+				ibest = i
+			}
+		}
+		o.History = append(o.History, fp[ibest])
+
+		// message
+		if verbose {
+			io.Pf("%5d%23.15e%23.15e%10d\n", o.Ngen, fp[ibest], vp[ibest], o.obj.nfeval)
+		}
+
+		// check convergence
+		if vp[ibest] == 0 && evoStalled(o.History, o.Nstall, o.Ftol) {
+			break
+		}
+	}
+
+	// results
+	o.Xbest.Apply(1, P[ibest])
+	o.Fbest = fp[ibest]
+	o.Viol = vp[ibest]
+	o.Nfeval = o.obj.nfeval
+	if verbose && o.Ngen == o.NmaxGen {
+		io.Pfred("max number of generations reached\n")
+	}
+}
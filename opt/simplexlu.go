@@ -0,0 +1,231 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+)
+
+// spCol holds a sparse column: row indices and values
+type spCol struct {
+	i []int     // row indices
+	x []float64 // values
+}
+
+// basisLU implements the sparse LU factorisation of a basis matrix B with product-form updates
+//  The factorisation is computed column-by-column (left-looking) with partial
+//  pivoting, after ordering the columns by number of non-zeros:
+//
+//       B ⋅ Q = L ⋅ U
+//
+//  where the columns of L are stored with the original row indices and the pivot rows are
+//  kept in piv. After each change of basis B̄ = B ⋅ E, where E is an identity matrix with one
+//  column replaced by d = B⁻¹ a, an "eta" column is stored such that B̄⁻¹ = E⁻¹ ⋅ B⁻¹.
+type basisLU struct {
+	m    int       // dimension of basis
+	q    []int     // [m] column ordering: k-th factorised column corresponds to basis position q[k]
+	piv  []int     // [m] pivot rows
+	lcol []spCol   // [m] columns of L (without unit diagonal; original row indices)
+	ucol []spCol   // [m] columns of U above the diagonal (indices refer to k-ordering)
+	udia []float64 // [m] diagonal of U
+	eta  []spCol   // eta columns (indices refer to basis positions)
+	epos []int     // basis positions of eta columns
+	epiv []float64 // pivots of eta columns
+	work []float64 // [m] workspace
+}
+
+// factor computes the LU factorisation of the basis matrix with columns cols
+//  Output:
+//   singular -- the basis matrix is (numerically) singular
+func (o *basisLU) factor(m int, cols []spCol) (singular bool) {
+
+	// allocate
+	o.m = m
+	o.q = make([]int, m)
+	o.piv = make([]int, m)
+	o.lcol = make([]spCol, m)
+	o.ucol = make([]spCol, m)
+	o.udia = make([]float64, m)
+	o.eta = o.eta[:0]
+	o.epos = o.epos[:0]
+	o.epiv = o.epiv[:0]
+	o.work = make([]float64, m)
+
+	// order columns by number of non-zeros
+	for k := 0; k < m; k++ {
+		o.q[k] = k
+	}
+	sort.SliceStable(o.q, func(a, b int) bool { return len(cols[o.q[a]].i) < len(cols[o.q[b]].i) })
+
+	// eliminate column by column
+	pivoted := make([]bool, m)
+	w := o.work
+	for k := 0; k < m; k++ {
+
+		// scatter column
+		for i := 0; i < m; i++ {
+			w[i] = 0
+		}
+		c := cols[o.q[k]]
+		for p, i := range c.i {
+			w[i] += c.x[p]
+		}
+
+		// solve with previous columns of L
+		for kk := 0; kk < k; kk++ {
+			v := w[o.piv[kk]]
+			if v == 0 {
+				continue
+			}
+			o.ucol[k].i = append(o.ucol[k].i, kk)
+			o.ucol[k].x = append(o.ucol[k].x, v)
+			for p, i := range o.lcol[kk].i {
+				w[i] -= o.lcol[kk].x[p] * v
+			}
+		}
+
+		// find pivot
+		r, vmax := -1, 0.0
+		for i := 0; i < m; i++ {
+			if !pivoted[i] && math.Abs(w[i]) > vmax {
+				r, vmax = i, math.Abs(w[i])
+			}
+		}
+		if r < 0 || vmax < 1e-11 {
+			return true
+		}
+
+		// set L and U
+		pivoted[r] = true
+		o.piv[k] = r
+		o.udia[k] = w[r]
+		for i := 0; i < m; i++ {
+			if !pivoted[i] && w[i] != 0 {
+				o.lcol[k].i = append(o.lcol[k].i, i)
+				o.lcol[k].x = append(o.lcol[k].x, w[i]/w[r])
+			}
+		}
+	}
+	return false
+}
+
+// ftran solves B ⋅ x = a (forward transformation)
+//  Input:
+//   a -- right-hand side (indexed by rows)
+//  Output:
+//   x -- solution (indexed by basis positions). x and a may not be the same
+func (o *basisLU) ftran(x, a []float64) {
+
+	// solve L ⋅ z = a
+	w := o.work
+	copy(w, a)
+	for k := 0; k < o.m; k++ {
+		v := w[o.piv[k]]
+		if v == 0 {
+			continue
+		}
+		for p, i := range o.lcol[k].i {
+			w[i] -= o.lcol[k].x[p] * v
+		}
+	}
+	z := x
+	for k := 0; k < o.m; k++ {
+		z[k] = w[o.piv[k]]
+	}
+
+	// solve U ⋅ y = z
+	for k := o.m - 1; k >= 0; k-- {
+		z[k] /= o.udia[k]
+		for p, kk := range o.ucol[k].i {
+			z[kk] -= o.ucol[k].x[p] * z[k]
+		}
+	}
+
+	// unpermute columns
+	copy(w, z)
+	for k := 0; k < o.m; k++ {
+		x[o.q[k]] = w[k]
+	}
+
+	// apply eta columns
+	for e := 0; e < len(o.eta); e++ {
+		r := o.epos[e]
+		x[r] /= o.epiv[e]
+		if x[r] == 0 {
+			continue
+		}
+		for p, i := range o.eta[e].i {
+			x[i] -= o.eta[e].x[p] * x[r]
+		}
+	}
+}
+
+// btran solves Bᵀ ⋅ y = c (backward transformation)
+//  Input:
+//   c -- right-hand side (indexed by basis positions)
+//  Output:
+//   y -- solution (indexed by rows). y and c may not be the same
+func (o *basisLU) btran(y, c []float64) {
+
+	// apply eta columns (transposed) in reverse order
+	w := o.work
+	copy(w, c)
+	for e := len(o.eta) - 1; e >= 0; e-- {
+		r := o.epos[e]
+		s := w[r]
+		for p, i := range o.eta[e].i {
+			s -= o.eta[e].x[p] * w[i]
+		}
+		w[r] = s / o.epiv[e]
+	}
+
+	// solve Uᵀ ⋅ z = Qᵀ ⋅ w
+	z := y
+	for k := 0; k < o.m; k++ {
+		s := w[o.q[k]]
+		for p, kk := range o.ucol[k].i {
+			s -= o.ucol[k].x[p] * z[kk]
+		}
+		z[k] = s / o.udia[k]
+	}
+
+	// solve Lᵀ ⋅ y = z
+	copy(w, z)
+	for k := o.m - 1; k >= 0; k-- {
+		s := w[k]
+		for p, i := range o.lcol[k].i {
+			s -= o.lcol[k].x[p] * y[i]
+		}
+		y[o.piv[k]] = s
+	}
+}
+
+// update updates the factorisation after the column at basis position r has been replaced
+//  Input:
+//   r -- basis position of replaced column
+//   d -- d = B⁻¹ ⋅ a where a is the new column (computed with ftran before the update)
+func (o *basisLU) update(r int, d []float64) {
+	if math.Abs(d[r]) < 1e-14 {
+		chk.Panic("cannot update basis factorisation: pivot is zero (%g)", d[r])
+	}
+	var e spCol
+	for i, v := range d {
+		if i != r && v != 0 {
+			e.i = append(e.i, i)
+			e.x = append(e.x, v)
+		}
+	}
+	o.eta = append(o.eta, e)
+	o.epos = append(o.epos, r)
+	o.epiv = append(o.epiv, d[r])
+}
+
+// neta returns the number of eta columns
+func (o *basisLU) neta() int {
+	return len(o.eta)
+}
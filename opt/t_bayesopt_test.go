@@ -0,0 +1,70 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestBayesOpt01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BayesOpt01. one-dimensional multimodal function")
+
+	// f(x) = sin(x) + sin(10 x / 3) with global minimum at x ≈ 5.14574834
+	ffcn := func(x la.Vector) float64 { return math.Sin(x[0]) + math.Sin(10*x[0]/3) }
+	for _, acq := range []string{"ei", "ucb"} {
+		var bo BayesOpt
+		bo.Init([]float64{2.7}, []float64{7.5}, ffcn, dbf.Params{&dbf.P{N: "seed", V: 1234}, &dbf.P{N: "nmaxeval", V: 20}})
+		bo.Acquisition = acq
+		bo.Solve(chk.Verbose)
+		io.Pforan("%s: x = %v  f = %v  nfeval = %d\n", acq, bo.Xbest, bo.Fbest, bo.Nfeval)
+		chk.Int(tst, "nfeval", bo.Nfeval, 20)
+		chk.Int(tst, "len(History)", len(bo.History), 20)
+		chk.Float64(tst, acq+": f", 1e-4, bo.Fbest, ffcn([]float64{5.14574834}))
+		chk.Float64(tst, acq+": x", 1e-2, bo.Xbest[0], 5.14574834)
+	}
+}
+
+func TestBayesOpt02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BayesOpt02. Branin function")
+
+	// Branin function with three global minima f = 0.397887
+	ffcn := func(x la.Vector) float64 {
+		a, b, c := 1.0, 5.1/(4*math.Pi*math.Pi), 5/math.Pi
+		r, s, t := 6.0, 10.0, 1/(8*math.Pi)
+		return a*math.Pow(x[1]-b*x[0]*x[0]+c*x[0]-r, 2) + s*(1-t)*math.Cos(x[0]) + s
+	}
+
+	// solve
+	var bo BayesOpt
+	bo.Init([]float64{-5, 0}, []float64{10, 15}, ffcn, dbf.Params{&dbf.P{N: "seed", V: 1234}, &dbf.P{N: "nmaxeval", V: 40}})
+	bo.Sampling = "halton"
+	bo.Solve(chk.Verbose)
+	io.Pforan("x = %v  f = %v  nfeval = %d\n", bo.Xbest, bo.Fbest, bo.Nfeval)
+	io.Pforan("σf = %g  ℓ = %v\n", bo.Gp.SigF, bo.Gp.Ell)
+	chk.Float64(tst, "f", 1e-2, bo.Fbest, 0.397887)
+	for k := 1; k < len(bo.History); k++ {
+		if bo.History[k] > bo.History[k-1] {
+			tst.Errorf("history must be non-increasing\n")
+			return
+		}
+	}
+
+	// deterministic seeding
+	var bo2 BayesOpt
+	bo2.Init([]float64{-5, 0}, []float64{10, 15}, ffcn, dbf.Params{&dbf.P{N: "seed", V: 1234}, &dbf.P{N: "nmaxeval", V: 15}})
+	bo2.Sampling = "halton"
+	bo2.Solve(false)
+	chk.Array(tst, "same seed ⇒ same samples", 1e-17, bo2.F, bo.F[:15])
+}
@@ -0,0 +1,154 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// evoTestProblem holds a test problem for the single-objective population-based methods
+type evoTestProblem struct {
+	name       string
+	xmin, xmax la.Vector
+	ffcn       func(x la.Vector) float64
+	ng         int
+	gfcn       func(g, x la.Vector)
+	xcor       []float64
+	fcor       float64
+}
+
+// evoTestProblems returns a set of test problems
+func evoTestProblems() []*evoTestProblem {
+	return []*evoTestProblem{
+		{
+			name: "sphere",
+			xmin: []float64{-5, -5, -5, -5}, xmax: []float64{5, 5, 5, 5},
+			ffcn: func(x la.Vector) float64 { return la.VecDot(x, x) },
+			xcor: []float64{0, 0, 0, 0}, fcor: 0,
+		},
+		{
+			name: "rosenbrock",
+			xmin: []float64{-2, -2}, xmax: []float64{2, 2},
+			ffcn: func(x la.Vector) float64 {
+				return 100*math.Pow(x[1]-x[0]*x[0], 2) + math.Pow(1-x[0], 2)
+			},
+			xcor: []float64{1, 1}, fcor: 0,
+		},
+		{
+			name: "constrained",
+			xmin: []float64{-3, -3}, xmax: []float64{3, 3},
+			ffcn: func(x la.Vector) float64 {
+				return math.Pow(x[0]-2, 2) + math.Pow(x[1]-1, 2)
+			},
+			ng: 2,
+			gfcn: func(g, x la.Vector) {
+				g[0] = x[0]*x[0] - x[1]
+				g[1] = x[0] + x[1] - 2
+			},
+			xcor: []float64{1, 1}, fcor: 1,
+		},
+	}
+}
+
+func TestDiffEvol01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("DiffEvol01. sphere, Rosenbrock and constrained problems")
+
+	for _, p := range evoTestProblems() {
+		for _, strategy := range []string{"rand1", "best1"} {
+			var de DiffEvol
+			de.Init(p.xmin, p.xmax, p.ffcn, p.ng, p.gfcn, dbf.Params{&dbf.P{N: "seed", V: 1234}})
+			de.Strategy = strategy
+			de.Solve(chk.Verbose)
+			io.Pforan("%s (%s): x = %v  f = %g  viol = %g  ngen = %d  nfeval = %d\n", p.name, strategy, de.Xbest, de.Fbest, de.Viol, de.Ngen, de.Nfeval)
+			chk.Float64(tst, p.name+": viol", 1e-15, de.Viol, 0)
+			chk.Float64(tst, p.name+": f", 1e-6, de.Fbest, p.fcor)
+			chk.Array(tst, p.name+": x", 1e-4, de.Xbest, p.xcor)
+			chk.Int(tst, "len(History)", len(de.History), de.Ngen+1)
+			for k := 1; k < len(de.History); k++ {
+				if de.History[k] > de.History[k-1] {
+					tst.Errorf("history must be non-increasing\n")
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestDiffEvol02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("DiffEvol02. deterministic seeding and sampling methods")
+
+	p := evoTestProblems()[1]
+	var results [][]float64
+	for _, sampling := range []string{"lhs", "lhs", "halton", "uniform"} {
+		var de DiffEvol
+		de.Init(p.xmin, p.xmax, p.ffcn, p.ng, p.gfcn, dbf.Params{&dbf.P{N: "seed", V: 7}, &dbf.P{N: "nmaxgen", V: 20}})
+		de.Sampling = sampling
+		de.Solve(false)
+		io.Pforan("%8s: x = %v  f = %g\n", sampling, de.Xbest, de.Fbest)
+		results = append(results, []float64{de.Xbest[0], de.Xbest[1], de.Fbest})
+	}
+	chk.Array(tst, "same seed ⇒ same result", 1e-17, results[0], results[1])
+}
+
+func TestCmaEs01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("CmaEs01. sphere, Rosenbrock and constrained problems")
+
+	for _, p := range evoTestProblems() {
+		var es CmaEs
+		es.Init(p.xmin, p.xmax, p.ffcn, p.ng, p.gfcn, dbf.Params{&dbf.P{N: "seed", V: 1234}})
+		es.Solve(chk.Verbose)
+		io.Pforan("%s: x = %v  f = %g  viol = %g  σ = %g  ngen = %d  nfeval = %d\n", p.name, es.Xbest, es.Fbest, es.Viol, es.Sigma, es.Ngen, es.Nfeval)
+		chk.Float64(tst, p.name+": viol", 1e-15, es.Viol, 0)
+		chk.Float64(tst, p.name+": f", 1e-6, es.Fbest, p.fcor)
+		chk.Array(tst, p.name+": x", 1e-4, es.Xbest, p.xcor)
+		chk.Int(tst, "len(History)", len(es.History), es.Ngen+1)
+	}
+
+	// deterministic seeding
+	p := evoTestProblems()[1]
+	var es1, es2 CmaEs
+	es1.Init(p.xmin, p.xmax, p.ffcn, 0, nil, dbf.Params{&dbf.P{N: "seed", V: 3}, &dbf.P{N: "nmaxgen", V: 10}})
+	es2.Init(p.xmin, p.xmax, p.ffcn, 0, nil, dbf.Params{&dbf.P{N: "seed", V: 3}, &dbf.P{N: "nmaxgen", V: 10}})
+	es1.Solve(false)
+	es2.Solve(false)
+	chk.Array(tst, "same seed ⇒ same result", 1e-17, es1.Xbest, es2.Xbest)
+
+	// initial mean and step size
+	var es CmaEs
+	es.Init(p.xmin, p.xmax, p.ffcn, 0, nil, dbf.Params{&dbf.P{N: "sigma0", V: 0.1}, &dbf.P{N: "seed", V: 3}})
+	es.X0 = []float64{-1, 1}
+	es.Solve(false)
+	io.Pforan("x0 = %v: x = %v  f = %g  nfeval = %d\n", es.X0, es.Xbest, es.Fbest, es.Nfeval)
+	chk.Array(tst, "x (with x0)", 1e-4, es.Xbest, p.xcor)
+}
+
+func TestParticleSwarm01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("ParticleSwarm01. sphere, Rosenbrock and constrained problems")
+
+	for _, p := range evoTestProblems() {
+		var ps ParticleSwarm
+		ps.Init(p.xmin, p.xmax, p.ffcn, p.ng, p.gfcn, dbf.Params{&dbf.P{N: "seed", V: 1234}, &dbf.P{N: "nmaxgen", V: 2000}})
+		ps.Solve(chk.Verbose)
+		io.Pforan("%s: x = %v  f = %g  viol = %g  ngen = %d  nfeval = %d\n", p.name, ps.Xbest, ps.Fbest, ps.Viol, ps.Ngen, ps.Nfeval)
+		chk.Float64(tst, p.name+": viol", 1e-15, ps.Viol, 0)
+		chk.Float64(tst, p.name+": f", 1e-6, ps.Fbest, p.fcor)
+		chk.Array(tst, p.name+": x", 1e-3, ps.Xbest, p.xcor)
+		chk.Int(tst, "len(History)", len(ps.History), ps.Ngen+1)
+	}
+}
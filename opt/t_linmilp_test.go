@@ -0,0 +1,219 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestLinMilp01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinMilp01. integer program with two variables")
+
+	// integer program
+	//   min  -x0 - x1
+	//   s.t.  -2*x0 +  2*x1 ≥ 1
+	//         -8*x0 + 10*x1 ≤ 13
+	//         x0, x1 ≥ 0 and integer
+	// with slack variables:
+	//         -2*x0 +  2*x1 - x2      = 1
+	//         -8*x0 + 10*x1      + x3 = 13
+	var T la.Triplet
+	T.Init(2, 4, 6)
+	T.Put(0, 0, -2)
+	T.Put(0, 1, 2)
+	T.Put(0, 2, -1)
+	T.Put(1, 0, -8)
+	T.Put(1, 1, 10)
+	T.Put(1, 3, 1)
+	A := T.ToMatrix(nil)
+	b := []float64{1, 13}
+	c := []float64{-1, -1, 0, 0}
+	integer := []bool{true, true, false, false}
+
+	// solve with all node selection strategies, with and without cuts
+	for _, ncuts := range []float64{0, 3} {
+		for _, nodesel := range []string{"depth", "best", "hybrid"} {
+			var milp LinMilp
+			milp.Init(A, b, c, nil, nil, integer, dbf.Params{&dbf.P{N: "ncutrounds", V: ncuts}})
+			milp.NodeSel = nodesel
+			milp.Solve(chk.Verbose)
+			io.Pforan("%s (ncutrounds=%g): x = %v  f = %g  nodes = %d  cuts = %d\n", nodesel, ncuts, milp.X, milp.F, milp.Nnodes, milp.Ncuts)
+			chk.String(tst, milp.Status.String(), "optimal")
+			chk.Array(tst, "x", 1e-10, milp.X[:2], []float64{1, 2})
+			chk.Float64(tst, "f", 1e-10, milp.F, -3)
+			chk.Float64(tst, "gap", 1e-10, milp.Gap, 0)
+		}
+	}
+}
+
+func TestLinMilp02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinMilp02. knapsack problems against brute force")
+
+	// problem
+	//   max  Σ v_j x_j   s.t.   Σ w_ij x_j ≤ W_i,  0 ≤ x_j ≤ 3 and integer
+	v := []float64{5, 4, 3, 7, 2}
+	w := [][]float64{
+		{2, 3, 1, 4, 2},
+		{4, 1, 2, 3, 1},
+		{3, 4, 2, 1, 3},
+	}
+	W := []float64{9, 11, 10}
+	n, m := len(v), len(W)
+
+	// brute force
+	fbest := 0.0
+	x := make([]int, n)
+	var enumerate func(k int)
+	enumerate = func(k int) {
+		if k == n {
+			for i := 0; i < m; i++ {
+				s := 0.0
+				for j := 0; j < n; j++ {
+					s += w[i][j] * float64(x[j])
+				}
+				if s > W[i] {
+					return
+				}
+			}
+			f := 0.0
+			for j := 0; j < n; j++ {
+				f -= v[j] * float64(x[j])
+			}
+			fbest = math.Min(fbest, f)
+			return
+		}
+		for x[k] = 0; x[k] <= 3; x[k]++ {
+			enumerate(k + 1)
+		}
+	}
+	enumerate(0)
+	io.Pforan("brute force: f = %g\n", fbest)
+
+	// standard form with slack variables
+	var T la.Triplet
+	T.Init(m, n+m, m*n+m)
+	c := make([]float64, n+m)
+	l := make([]float64, n+m)
+	u := make([]float64, n+m)
+	integer := make([]bool, n+m)
+	for j := 0; j < n; j++ {
+		c[j] = -v[j]
+		u[j] = 3
+		integer[j] = true
+		for i := 0; i < m; i++ {
+			T.Put(i, j, w[i][j])
+		}
+	}
+	for i := 0; i < m; i++ {
+		T.Put(i, n+i, 1)
+		u[n+i] = math.Inf(+1)
+	}
+	A := T.ToMatrix(nil)
+
+	// solve
+	for _, ncuts := range []float64{0, 2} {
+		var milp LinMilp
+		milp.Init(A, W, c, l, u, integer, dbf.Params{&dbf.P{N: "ncutrounds", V: ncuts}})
+		milp.Solve(chk.Verbose)
+		io.Pforan("x = %v  f = %g  nodes = %d  cuts = %d\n", milp.X[:n], milp.F, milp.Nnodes, milp.Ncuts)
+		chk.String(tst, milp.Status.String(), "optimal")
+		chk.Float64(tst, "f", 1e-10, milp.F, fbest)
+		r := make([]float64, m)
+		la.SpMatVecMul(r, 1, A, milp.X)
+		chk.Array(tst, "A*x=b", 1e-10, r, W)
+	}
+
+	// gap tolerance
+	var milp LinMilp
+	milp.Init(A, W, c, l, u, integer, dbf.Params{&dbf.P{N: "gaptol", V: 0.5}})
+	milp.Solve(false)
+	io.Pforan("gaptol=0.5: f = %g  bound = %g  gap = %g\n", milp.F, milp.Bound, milp.Gap)
+	if milp.Gap > 0.5 || milp.Bound > fbest+1e-10 || milp.F < fbest-1e-10 {
+		tst.Errorf("gap or bounds are incorrect\n")
+	}
+}
+
+func TestLinMilp03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinMilp03. infeasible integer program")
+
+	// 2*x0 + 2*x1 = 3 with integer x
+	var T la.Triplet
+	T.Init(1, 2, 2)
+	T.Put(0, 0, 2)
+	T.Put(0, 1, 2)
+	A := T.ToMatrix(nil)
+	var milp LinMilp
+	milp.Init(A, []float64{3}, []float64{1, 1}, nil, nil, nil, nil)
+	milp.Solve(chk.Verbose)
+	chk.String(tst, milp.Status.String(), "infeasible")
+	if milp.Found {
+		tst.Errorf("solution should not have been found\n")
+	}
+}
+
+func TestLinMilp04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinMilp04. solving twice does not modify the bounds and constraints")
+
+	// same problem as in LinMilp01 with explicit bounds
+	var T la.Triplet
+	T.Init(2, 4, 6)
+	T.Put(0, 0, -2)
+	T.Put(0, 1, 2)
+	T.Put(0, 2, -1)
+	T.Put(1, 0, -8)
+	T.Put(1, 1, 10)
+	T.Put(1, 3, 1)
+	A := T.ToMatrix(nil)
+	b := []float64{1, 13}
+	c := []float64{-1, -1, 0, 0}
+	l := la.Vector([]float64{0, 0, 0, 0})
+	u := la.Vector([]float64{10, 10, 100, 100})
+	integer := []bool{true, true, false, false}
+	var relax LinSimplex
+	relax.Init(A, b, c, l, u, nil)
+	relax.Solve(false)
+	var milp LinMilp
+	milp.Init(A, b, c, l, u, integer, dbf.Params{&dbf.P{N: "ncutrounds", V: 3}})
+	ncuts := -1
+	for k := 0; k < 2; k++ {
+		milp.Solve(chk.Verbose)
+		io.Pforan("solve %d: x = %v  f = %g  nodes = %d  cuts = %d\n", k, milp.X, milp.F, milp.Nnodes, milp.Ncuts)
+		if k == 0 {
+			ncuts = milp.Ncuts
+			if ncuts == 0 {
+				tst.Errorf("cuts should have been added\n")
+			}
+		}
+		chk.Int(tst, "Ncuts", milp.Ncuts, ncuts)
+		chk.Int(tst, "Lp.Nl", milp.Lp.Nl, 2)
+		chk.String(tst, milp.Status.String(), "optimal")
+		chk.Array(tst, "x", 1e-10, milp.X[:2], []float64{1, 2})
+		chk.Float64(tst, "f", 1e-10, milp.F, -3)
+		chk.Array(tst, "l", 1e-17, l, []float64{0, 0, 0, 0})
+		chk.Array(tst, "u", 1e-17, u, []float64{10, 10, 100, 100})
+		chk.Array(tst, "Lp.L", 1e-17, milp.Lp.L, []float64{0, 0, 0, 0})
+		chk.Array(tst, "Lp.U", 1e-17, milp.Lp.U, []float64{10, 10, 100, 100})
+	}
+
+	// the LP relaxation is recovered
+	milp.Lp.Solve(false)
+	chk.String(tst, milp.Lp.Status.String(), "optimal")
+	chk.Float64(tst, "relaxation: f", 1e-10, milp.Lp.F, relax.F)
+	chk.Array(tst, "relaxation: x", 1e-10, milp.Lp.X, relax.X)
+}
@@ -0,0 +1,305 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestBasisLU01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("BasisLU01. factorisation, ftran, btran and updates")
+
+	// matrix
+	A := [][]float64{
+		{2, 0, 0, 1, 0},
+		{0, 0, 3, 0, 1},
+		{1, 4, 0, 0, 0},
+		{0, 1, 0, 5, 0},
+		{0, 0, 1, 0, 2},
+	}
+	m := len(A)
+	cols := make([]spCol, m)
+	for j := 0; j < m; j++ {
+		for i := 0; i < m; i++ {
+			if A[i][j] != 0 {
+				cols[j].i = append(cols[j].i, i)
+				cols[j].x = append(cols[j].x, A[i][j])
+			}
+		}
+	}
+
+	// factorisation
+	var lu basisLU
+	if lu.factor(m, cols) {
+		tst.Errorf("matrix should not be singular\n")
+		return
+	}
+
+	// check solutions against dense solver
+	a := []float64{1, 2, 3, 4, 5}
+	x := make([]float64, m)
+	y := make([]float64, m)
+	check := func(msg string) {
+		B := la.NewMatrix(m, m)
+		for j := 0; j < m; j++ {
+			for p, i := range cols[j].i {
+				B.Set(i, j, cols[j].x[p])
+			}
+		}
+		xcor := make([]float64, m)
+		ycor := make([]float64, m)
+		la.DenSolve(xcor, B, a, true)
+		la.DenSolve(ycor, B.GetTranspose(), a, true)
+		lu.ftran(x, a)
+		lu.btran(y, a)
+		io.Pforan("%s: x = %v\n", msg, x)
+		io.Pforan("%s: y = %v\n", msg, y)
+		chk.Array(tst, msg+": x", 1e-14, x, xcor)
+		chk.Array(tst, msg+": y", 1e-14, y, ycor)
+	}
+	check("original")
+
+	// replace columns
+	news := []spCol{
+		{[]int{0, 2, 4}, []float64{1, 1, 1}},
+		{[]int{1, 3}, []float64{-2, 7}},
+	}
+	d := make([]float64, m)
+	for k, r := range []int{1, 3} {
+		for i := 0; i < m; i++ {
+			x[i] = 0
+		}
+		for p, i := range news[k].i {
+			x[i] = news[k].x[p]
+		}
+		lu.ftran(d, x)
+		lu.update(r, d)
+		cols[r] = news[k]
+		check(io.Sf("update %d", k))
+	}
+	chk.Int(tst, "number of etas", lu.neta(), 2)
+
+	// singular matrix
+	cols[4] = cols[0]
+	if !lu.factor(m, cols) {
+		tst.Errorf("matrix should be singular\n")
+	}
+}
+
+func TestLinSimplex01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinSimplex01. same problem as linipm01")
+
+	// linear programming problem
+	//   min  -4*x0 - 5*x1
+	//   s.t.  2*x0 +   x1 + x2      = 3
+	//           x0 + 2*x1      + x3 = 3
+	//         x0,x1,x2,x3 ≥ 0
+	var T la.Triplet
+	T.Init(2, 4, 6)
+	T.Put(0, 0, 2.0)
+	T.Put(0, 1, 1.0)
+	T.Put(0, 2, 1.0)
+	T.Put(1, 0, 1.0)
+	T.Put(1, 1, 2.0)
+	T.Put(1, 3, 1.0)
+	Am := T.ToMatrix(nil)
+	c := []float64{-4, -5, 0, 0}
+	b := []float64{3, 3}
+
+	// solve LP
+	var lp LinSimplex
+	lp.Init(Am, b, c, nil, nil, nil)
+	lp.Solve(chk.Verbose)
+
+	// check
+	io.Pforan("x = %v\n", lp.X)
+	io.Pfcyan("y = %v\n", lp.Y)
+	io.Pforan("d = %v\n", lp.D)
+	chk.String(tst, lp.Status.String(), "optimal")
+	chk.Array(tst, "x", 1e-14, lp.X, []float64{1, 1, 0, 0})
+	chk.Float64(tst, "f", 1e-14, lp.F, -9)
+
+	// check duals: cᵀx = bᵀy and d = c - Aᵀy
+	chk.Float64(tst, "bᵀy", 1e-14, la.VecDot(b, lp.Y), -9)
+	d := la.NewVector(4)
+	d.Apply(1, c)
+	la.SpMatTrVecMulAdd(d, -1, Am, lp.Y)
+	chk.Array(tst, "d", 1e-14, lp.D, d)
+}
+
+func TestLinSimplex02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinSimplex02. free variable and bounds")
+
+	// linear program
+	//   min   2*x0 +   x1
+	//   s.t.   -x0 +   x1 ≤ 1
+	//           x0 +   x1 ≥ 2
+	//           x0 - 2*x1 ≤ 4
+	//         x0 free and x1 ≥ 0
+	// with slack variables:
+	//   s.t.   -x0 +   x1 + x2           = 1
+	//           x0 +   x1      - x3      = 2
+	//           x0 - 2*x1           + x4 = 4
+	var T la.Triplet
+	T.Init(3, 5, 9)
+	T.Put(0, 0, -1)
+	T.Put(0, 1, 1)
+	T.Put(0, 2, 1)
+	T.Put(1, 0, 1)
+	T.Put(1, 1, 1)
+	T.Put(1, 3, -1)
+	T.Put(2, 0, 1)
+	T.Put(2, 1, -2)
+	T.Put(2, 4, 1)
+	Am := T.ToMatrix(nil)
+	c := []float64{2, 1, 0, 0, 0}
+	b := []float64{1, 2, 4}
+	inf := math.Inf(+1)
+	l := []float64{-inf, 0, 0, 0, 0}
+	u := []float64{inf, inf, inf, inf, inf}
+
+	// solve LP
+	var lp LinSimplex
+	lp.Init(Am, b, c, l, u, nil)
+	lp.Solve(chk.Verbose)
+
+	// check
+	io.Pforan("x = %v\n", lp.X)
+	chk.String(tst, lp.Status.String(), "optimal")
+	chk.Array(tst, "x", 1e-14, lp.X[:2], []float64{0.5, 1.5})
+
+	// bounded variables: 0 ≤ x1 ≤ 1  ⇒  x = {1, 1}
+	lp.SetBounds(1, 0, 1)
+	lp.Resolve(chk.Verbose)
+	io.Pforan("x = %v\n", lp.X)
+	chk.String(tst, lp.Status.String(), "optimal")
+	chk.Array(tst, "x (x1 ≤ 1)", 1e-14, lp.X[:2], []float64{1, 1})
+	chk.Float64(tst, "f (x1 ≤ 1)", 1e-14, lp.F, 3)
+
+	// compare with solution from scratch (Init copies the bounds; thus u is unchanged)
+	if u[1] != inf {
+		tst.Errorf("the bounds given to Init must not be modified\n")
+	}
+	var cold LinSimplex
+	cold.Init(Am, b, c, l, u, nil)
+	cold.SetBounds(1, 0, 1)
+	cold.Solve(false)
+	chk.Array(tst, "x (cold start)", 1e-14, cold.X, lp.X)
+
+	// infeasible
+	lp.SetBounds(0, -inf, -1)
+	lp.Resolve(chk.Verbose)
+	chk.String(tst, lp.Status.String(), "infeasible")
+	lp.Solve(false)
+	chk.String(tst, lp.Status.String(), "infeasible")
+
+	// unbounded: min -x0  with x0 free
+	lp.SetBounds(0, -inf, inf)
+	lp.SetBounds(1, 0, inf)
+	lp.C[0], lp.C[1] = -1, 0
+	lp.Solve(chk.Verbose)
+	chk.String(tst, lp.Status.String(), "unbounded")
+}
+
+func TestLinSimplex03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinSimplex03. added constraints and warm start")
+
+	// linear program
+	//   max  x0 + x1   s.t.   x0 + 2*x1 ≤ 4,  3*x0 + x1 ≤ 6,  x ≥ 0
+	var T la.Triplet
+	T.Init(2, 4, 6)
+	T.Put(0, 0, 1)
+	T.Put(0, 1, 2)
+	T.Put(0, 2, 1)
+	T.Put(1, 0, 3)
+	T.Put(1, 1, 1)
+	T.Put(1, 3, 1)
+	Am := T.ToMatrix(nil)
+	c := []float64{-1, -1, 0, 0}
+	b := []float64{4, 6}
+
+	// solve LP
+	var lp LinSimplex
+	lp.Init(Am, b, c, nil, nil, nil)
+	lp.Solve(chk.Verbose)
+	io.Pforan("x = %v\n", lp.X)
+	chk.Array(tst, "x", 1e-14, lp.X[:2], []float64{1.6, 1.2})
+
+	// basis snapshot
+	basis := lp.GetBasis()
+
+	// add constraint: x0 + x1 ≤ 2.5
+	lp.AddConstraint([]int{0, 1}, []float64{1, 1}, 2.5)
+	lp.Resolve(chk.Verbose)
+	io.Pforan("x = %v (f = %g)\n", lp.X, lp.F)
+	chk.String(tst, lp.Status.String(), "optimal")
+	chk.Float64(tst, "f", 1e-14, lp.F, -2.5)
+	chk.Int(tst, "Nl", lp.Nl, 3)
+	chk.Int(tst, "len(y)", len(lp.Y), 3)
+	chk.Float64(tst, "x0 + x1", 1e-14, lp.X[0]+lp.X[1], 2.5)
+	if lp.Nit > 2 {
+		tst.Errorf("dual simplex should take at most 2 iterations. Nit = %d\n", lp.Nit)
+	}
+
+	// old basis is not compatible anymore
+	defer func() {
+		if err := recover(); err == nil {
+			tst.Errorf("SetBasis should have failed\n")
+		}
+	}()
+	lp.SetBasis(basis)
+}
+
+func TestLinSimplex04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("LinSimplex04. afiro")
+
+	// read LP
+	A, b, c, _, _ := ReadLPfortran("data/afiro.dat")
+
+	// solve LP
+	var lp LinSimplex
+	lp.Init(A, b, c, nil, nil, nil)
+	lp.Solve(chk.Verbose)
+
+	// check
+	io.Pforan("f = %v (Nit = %d)\n", lp.F, lp.Nit)
+	chk.String(tst, lp.Status.String(), "optimal")
+	chk.Float64(tst, "f", 1e-8, lp.F, -4.6475314286e+02)
+	bres := make([]float64, len(b))
+	la.SpMatVecMul(bres, 1, A, lp.X)
+	chk.Array(tst, "A*x=b", 1e-12, bres, b)
+	if lp.X.Min() < 0 {
+		tst.Errorf("x must be non-negative\n")
+	}
+
+	// compare with interior-point method
+	var ipm LinIpm
+	defer ipm.Free()
+	ipm.Init(A, b, c, nil)
+	ipm.Solve(false)
+	chk.Float64(tst, "f(ipm)", 1e-7, la.VecDot(c, ipm.X), lp.F)
+
+	// refactorisation after every iteration yields the same solution
+	var lp2 LinSimplex
+	lp2.Init(A, b, c, nil, nil, nil)
+	lp2.Nrefact = 1
+	lp2.Solve(false)
+	chk.Float64(tst, "f (Nrefact=1)", 1e-10, lp2.F, lp.F)
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/utl"
+)
+
+func TestNsga2a(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Nsga2a. ZDT1 problem")
+
+	// ZDT1 problem: f1 = x0, f2 = g ⋅ (1 - sqrt(f1/g)) with g = 1 + 9 Σ x_i / (n-1)
+	// Pareto front: f2 = 1 - sqrt(f1) with x_i = 0 for i > 0
+	n := 10
+	xmin := la.NewVector(n)
+	xmax := la.NewVector(n)
+	xmax.Fill(1)
+	ffcn := func(f, x la.Vector) {
+		g := 1 + 9*(x.Accum()-x[0])/float64(n-1)
+		f[0] = x[0]
+		f[1] = g * (1 - math.Sqrt(x[0]/g))
+	}
+
+	// solve
+	var nsga Nsga2
+	nsga.Init(xmin, xmax, 2, ffcn, 0, nil, dbf.Params{&dbf.P{N: "seed", V: 1234}})
+	nsga.Ref = []float64{1.1, 1.1}
+	nsga.Solve(chk.Verbose)
+
+	// check front
+	io.Pforan("nfront = %d  nfeval = %d\n", len(nsga.F), nsga.Nfeval)
+	chk.Int(tst, "nfront", len(nsga.F), nsga.Npop)
+	chk.Int(tst, "nfeval", nsga.Nfeval, nsga.Npop*(nsga.NmaxGen+1))
+	chk.Int(tst, "len(History)", len(nsga.History), nsga.NmaxGen+1)
+	for k, f := range nsga.F {
+		if math.Abs(f[1]-(1-math.Sqrt(f[0]))) > 0.02 {
+			tst.Errorf("solution %d is not on the Pareto front: f = %v\n", k, f)
+			return
+		}
+	}
+	front := utl.ParetoFront(nsga.F)
+	chk.Int(tst, "all solutions are non-dominated", len(front), len(nsga.F))
+
+	// check hypervolume: exact value = 1.1² - ∫₀¹ (1 - sqrt(f1)) df1 = 1.21 - 1/3
+	hv := nsga.History[len(nsga.History)-1]
+	io.Pforan("hypervolume = %v (exact = %v)\n", hv, 1.21-1.0/3.0)
+	chk.Float64(tst, "hypervolume", 0.01, hv, 1.21-1.0/3.0)
+	for k := 1; k < len(nsga.History); k++ {
+		if nsga.History[k] < nsga.History[k-1]-1e-3 {
+			tst.Errorf("hypervolume must not decrease significantly: %g < %g\n", nsga.History[k], nsga.History[k-1])
+			return
+		}
+	}
+
+	// deterministic seeding
+	var nsga2 Nsga2
+	nsga2.Init(xmin, xmax, 2, ffcn, 0, nil, dbf.Params{&dbf.P{N: "seed", V: 1234}})
+	nsga2.Solve(false)
+	chk.Deep2(tst, "same seed ⇒ same front", 1e-17, nsga2.F, nsga.F)
+}
+
+func TestNsga2b(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Nsga2b. constrained problem (CONSTR)")
+
+	// CONSTR problem: f1 = x0, f2 = (1 + x1) / x0
+	//                 g1 = 6 - (x1 + 9 x0) ≤ 0,  g2 = 1 - (9 x0 - x1) ≤ 0
+	xmin := []float64{0.1, 0}
+	xmax := []float64{1, 5}
+	ffcn := func(f, x la.Vector) {
+		f[0] = x[0]
+		f[1] = (1 + x[1]) / x[0]
+	}
+	gfcn := func(g, x la.Vector) {
+		g[0] = 6 - (x[1] + 9*x[0])
+		g[1] = 1 - (9*x[0] - x[1])
+	}
+
+	// solve
+	var nsga Nsga2
+	nsga.Init(xmin, xmax, 2, ffcn, 2, gfcn, dbf.Params{&dbf.P{N: "seed", V: 1234}, &dbf.P{N: "nmaxgen", V: 200}})
+	nsga.Ref = []float64{1, 10}
+	nsga.Solve(chk.Verbose)
+
+	// check: all solutions are feasible and on the Pareto front
+	//   for 7/18 ≤ x0 ≤ 2/3:  f2 = (7 - 9 f1) / f1  (g1 active)
+	//   for 2/3 ≤ x0 ≤ 1:     f2 = 1 / f1           (x1 = 0)
+	io.Pforan("nfront = %d  hypervolume = %v\n", len(nsga.F), nsga.History[len(nsga.History)-1])
+	emean := 0.0
+	for k, f := range nsga.F {
+		chk.Float64(tst, "viol", 1e-15, nsga.Viol[k], 0)
+		fcor := 1 / f[0]
+		if f[0] < 2.0/3.0 {
+			fcor = (7 - 9*f[0]) / f[0]
+		}
+		e := math.Abs(f[1]-fcor) / fcor
+		if e > 0.1 {
+			tst.Errorf("solution %d is not on the Pareto front: f = %v (f2 should be %g)\n", k, f, fcor)
+			return
+		}
+		emean += e / float64(len(nsga.F))
+	}
+	io.Pforan("mean relative distance to Pareto front = %v\n", emean)
+	if emean > 0.02 {
+		tst.Errorf("mean relative distance to Pareto front is too large: %g\n", emean)
+	}
+	f1min, f1max := 1.0, 0.0
+	for _, f := range nsga.F {
+		f1min = math.Min(f1min, f[0])
+		f1max = math.Max(f1max, f[0])
+	}
+	chk.Float64(tst, "min(f1)", 0.01, f1min, 7.0/18.0)
+	chk.Float64(tst, "max(f1)", 0.01, f1max, 1)
+}
@@ -15,11 +15,12 @@ import (
 
 // EssentialBcs holds data for prescribing a SET of essential (Dirichlet) boundary conditions
 type EssentialBcs struct {
-	all     [][]dbf.T    // [node][dof] function to compute BCs
-	grid    *gm.Grid     // using grid
-	mesh    *msh.Mesh    // using mesh
-	maxNdof int          // max number of "degrees-of-freedom" per node
-	nodes   map[int]bool // list of nodes with prescribed boundary conditions
+	all     [][]dbf.T      // [node][dof] function to compute BCs
+	grid    *gm.Grid       // using grid
+	mesh    *msh.Mesh      // using mesh
+	patch   *gm.NurbsPatch // using control points of NURBS patch
+	maxNdof int            // max number of "degrees-of-freedom" per node
+	nodes   map[int]bool   // list of nodes with prescribed boundary conditions
 }
 
 // NewEssentialBcsGrid returns a new EssentialBcs structure using Grid
@@ -46,7 +47,20 @@ func NewEssentialBcsMesh(mesh *msh.Mesh, maxNdof int) (o *EssentialBcs) {
 	return
 }
 
-// AddUsingTag sets boundary condition using edge or face tag from grid or mesh
+// NewEssentialBcsNurbs returns a new EssentialBcs structure using the control points of a NURBS patch
+//  patch   -- NURBS patch; the nodes are the control points and the tags are the control points' tags
+//  maxNdof -- max number of "degrees-of-freedom" per node
+func NewEssentialBcsNurbs(patch *gm.NurbsPatch, maxNdof int) (o *EssentialBcs) {
+	o = new(EssentialBcs)
+	o.all = make([][]dbf.T, len(patch.ControlPoints))
+	o.patch = patch
+	o.maxNdof = maxNdof
+	o.nodes = make(map[int]bool)
+	return
+}
+
+// AddUsingTag sets boundary condition using edge or face tag from grid or mesh (or the tag of
+// control points of NURBS patch)
 //   tag    -- edge or face tag
 //   dof    -- index of "degree-of-freedom"; e.g. 0⇒horizontal displacement, 1⇒vertical displacement
 //   cvalue -- constant value [optional]; or
@@ -68,6 +82,14 @@ func (o *EssentialBcs) AddUsingTag(tag, dof int, cvalue float64, fvalue dbf.T) {
 	var nodes []int
 	if o.grid != nil {
 		nodes = o.grid.Boundary(tag)
+	} else if o.patch != nil {
+
+		// using NURBS patch
+		for _, p := range o.patch.ControlPoints {
+			if p.Tag == tag {
+				nodes = append(nodes, p.ID)
+			}
+		}
 	} else {
 
 		// using mesh
@@ -114,10 +136,41 @@ func (o *EssentialBcs) Value(node, dof int, t float64) (val float64, available b
 		return bc[dof].F(t, o.grid.Node(node)), true
 	}
 
+	// using NURBS patch
+	if o.patch != nil {
+		return bc[dof].F(t, o.patch.ControlPoints[node].X[:3]), true
+	}
+
 	// using mesh
 	return bc[dof].F(t, o.mesh.Verts[node].X), true
 }
 
+// Rate returns the time derivative of the prescribed boundary condition @ {node,dof,time}
+func (o *EssentialBcs) Rate(node, dof int, t float64) (val float64, available bool) {
+
+	// check if available
+	bc := o.all[node]
+	if bc == nil {
+		return
+	}
+	if bc[dof] == nil {
+		return
+	}
+
+	// using grid
+	if o.grid != nil {
+		return bc[dof].G(t, o.grid.Node(node)), true
+	}
+
+	// using NURBS patch
+	if o.patch != nil {
+		return bc[dof].G(t, o.patch.ControlPoints[node].X[:3]), true
+	}
+
+	// using mesh
+	return bc[dof].G(t, o.mesh.Verts[node].X), true
+}
+
 // Print prints boundary conditions
 func (o *EssentialBcs) Print() (l string) {
 	var strNid string
@@ -127,6 +180,9 @@ func (o *EssentialBcs) Print() (l string) {
 	if o.mesh != nil {
 		_, strNid = utl.Digits(len(o.mesh.Verts))
 	}
+	if o.patch != nil {
+		_, strNid = utl.Digits(len(o.patch.ControlPoints))
+	}
 	_, strDof := utl.Digits(o.maxNdof)
 	for _, n := range o.Nodes() {
 		list := ""
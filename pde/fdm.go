@@ -5,6 +5,8 @@
 package pde
 
 import (
+	"math"
+
 	"github.com/cpmech/gosl/chk"
 	"github.com/cpmech/gosl/fun"
 	"github.com/cpmech/gosl/fun/dbf"
@@ -14,19 +16,52 @@ import (
 
 // FdmLaplacian implements the Finite Difference (FDM) Laplacian operator (2D or 3D)
 //
+//    L{u} = ∇ ⋅ (k K ∇u)      with      K = diag(kx, ky, kz)
+//
+//  where k = k({x}) is an optional variable coefficient (Kvar). With k = 1, the operator is
+//
 //              ∂²u        ∂²u        ∂²u
 //    L{u} = kx ———  +  ky ———  +  kz ———
 //              ∂x²        ∂y²        ∂z²
 //
+//  The operator is discretised in the non-conservative form L{u} = k K:∇∇u + ∇k ⋅ K ∇u. On
+//  rectangular grids (e.g. from RectGenUniform or RectSet2d), the derivatives are computed along
+//  the physical (possibly non-uniformly spaced) coordinates. On curvilinear grids (e.g. from
+//  SetTransfinite2d), the derivatives are computed w.r.t the reference coordinates uⁱ using the
+//  metric terms of the grid:
+//
+//                  ∂²u          ∂u
+//    K:∇∇u = Gⁱʲ (————————— - Γᵏᵢⱼ ———)      with      Gⁱʲ = gⁱ ⋅ K ⋅ gʲ
+//                 ∂uⁱ ∂uʲ       ∂uᵏ
+//
+//  where gⁱ are the contravariant basis vectors and Γᵏᵢⱼ are the Christoffel symbols of second
+//  kind (see gm.Grid.GammaS). Note that Gⁱʲ Γᵏᵢⱼ are the L-coefficients (gm.Grid.Lcoeff) if K = I.
+//
+//  Natural boundary conditions are given on edges (2D) or faces (3D) as follows
+//
+//    n ⋅ (k K ∇u) + β u = q
+//
+//  where n is the unit outward normal; β = 0 corresponds to Neumann (flux) conditions whereas
+//  β ≠ 0 corresponds to Robin (e.g. convection) conditions. Boundaries without prescribed
+//  conditions are insulated (q = 0). The natural conditions are imposed by eliminating the ghost
+//  points outside the grid; thus the number of points along each direction must be at least 3.
+//
+//  NOTE: the system is [A]⋅{u} = {b} where {b} collects the source term s({x},t) and the
+//        contributions of the natural boundary conditions
 type FdmLaplacian struct {
 	Kx       float64       // isotropic coefficient x
 	Ky       float64       // isotropic coefficient y
 	Kz       float64       // isotropic coefficient z
+	Kvar     dbf.T         // variable coefficient k({x}) [optional; evaluated at t=0]
 	Grid     *gm.Grid      // grid
 	Source   fun.Svs       // source term function s({x},t)
 	EssenBcs *EssentialBcs // essential boundary conditions
 	Eqs      *la.Equations // equations
 	bcsReady bool          // boundary conditions are set
+
+	// natural boundary conditions
+	natBcs map[int]*fdmNaturalBc // tag ⇒ natural boundary condition
+	rhsBcs [][]fdmBcTerm         // [node] contributions of natural boundary conditions to {b}
 }
 
 // NewFdmLaplacian creates a new FDM Laplacian operator with given parameters
@@ -45,11 +80,12 @@ func NewFdmLaplacian(params dbf.Params, grid *gm.Grid, source fun.Svs) (o *FdmLa
 	o.Source = source
 	o.EssenBcs = NewEssentialBcsGrid(grid, 1) // 1:maxNdof
 	o.bcsReady = false
+	o.natBcs = make(map[int]*fdmNaturalBc)
 	return
 }
 
 // AddBc adds essential or natural boundary condition
-//   essential -- essential BC; otherwise natural boundary condition
+//   essential -- essential BC; otherwise natural (Neumann) boundary condition: n ⋅ (k K ∇u) = q
 //   tag       -- edge or face tag in grid
 //   cvalue    -- constant value [optional]; or
 //   fvalue    -- function value [optional]
@@ -59,57 +95,55 @@ func (o *FdmLaplacian) AddBc(essential bool, tag int, cvalue float64, fvalue dbf
 		o.EssenBcs.AddUsingTag(tag, 0, cvalue, fvalue)
 		return
 	}
-	chk.Panic("TODO: Implement natural boundary condition\n")
+	o.AddRobinBc(tag, 0, cvalue, fvalue)
+}
+
+// AddRobinBc adds natural boundary condition of Robin type: n ⋅ (k K ∇u) + β u = q
+//   tag    -- edge or face tag in grid
+//   beta   -- coefficient β; e.g. heat transfer coefficient of convection conditions
+//   cvalue -- constant value of q [optional]; or
+//   fvalue -- function q(t,{x}) [optional]
+//  NOTE: for convection conditions -k ∂u/∂n = h (u - u∞), use β = h and q = h u∞
+func (o *FdmLaplacian) AddRobinBc(tag int, beta, cvalue float64, fvalue dbf.T) {
+	if o.Grid.Boundary(tag) == nil {
+		chk.Panic("cannot find nodes with tag=%d\n", tag)
+	}
+	o.bcsReady = false
+	o.natBcs[tag] = &fdmNaturalBc{beta, cvalue, fvalue}
 }
 
 // Assemble assembles operator into A matrix from [A] ⋅ {u} = {b}
 //  reactions -- prepare for computation of RHS
 func (o *FdmLaplacian) Assemble(reactions bool) {
+
+	// equations of all nodes
+	nnodes := o.Grid.Size()
+	rows := make([]*fdmForm, nnodes)
+	o.rhsBcs = make([][]fdmBcTerm, nnodes)
+	stencil := newFdmStencil(o)
+	for I := 0; I < nnodes; I++ {
+		rows[I] = stencil.equation(I)
+		o.rhsBcs[I] = rows[I].bcs
+	}
+
+	// allocate equations
 	if !o.bcsReady {
-		o.Eqs = la.NewEquations(o.Grid.Size(), o.EssenBcs.Nodes())
-		o.Eqs.Alloc([]int{5 * o.Eqs.Nu, 5 * o.Eqs.Nu, 5 * o.Eqs.Nk, 5 * o.Eqs.Nk}, reactions, true)
+		o.Eqs = la.NewEquations(nnodes, o.EssenBcs.Nodes())
+		cols := make([][]int, nnodes)
+		for I, row := range rows {
+			cols[I] = row.cols
+		}
+		allocEqs(o.Eqs, cols, reactions)
 		o.bcsReady = true
 	}
+
+	// assemble
 	o.Eqs.Start()
-	if o.Grid.Ndim() == 2 {
-		nx := o.Grid.Npts(0)
-		ny := o.Grid.Npts(1)
-		dx := o.Grid.Xlen(0) / float64(nx-1)
-		dy := o.Grid.Xlen(1) / float64(ny-1)
-		dx2 := dx * dx
-		dy2 := dy * dy
-		α := -2.0 * (o.Kx/dx2 + o.Ky/dy2)
-		β := o.Kx / dx2
-		γ := o.Ky / dy2
-		mol := []float64{α, β, β, γ, γ}
-		jays := make([]int, 5)
-		for I := 0; I < o.Eqs.N; I++ { // loop over all Nx*Ny equations
-			col := I % nx    // grid column number
-			row := I / nx    // grid row number
-			jays[0] = I      // current node
-			jays[1] = I - 1  // left node
-			jays[2] = I + 1  // right node
-			jays[3] = I - nx // bottom node
-			jays[4] = I + nx // top node
-			if col == 0 {
-				jays[1] = jays[2]
-			}
-			if col == nx-1 {
-				jays[2] = jays[1]
-			}
-			if row == 0 {
-				jays[3] = jays[4]
-			}
-			if row == ny-1 {
-				jays[4] = jays[3]
-			}
-			for k, J := range jays { // loop over non-zero columns
-				o.Eqs.Put(I, J, mol[k])
-			}
+	for I, row := range rows {
+		for k, J := range row.cols {
+			o.Eqs.Put(I, J, row.vals[k])
 		}
-		return
 	}
-	chk.Panic("TODO: Implement Assemble() in 3D\n")
 }
 
 // SolveSteady solves steady problem
@@ -146,9 +180,406 @@ func (o *FdmLaplacian) calcXk(I int, t float64) float64 {
 // calcBu calculates RHS vector (e.g. source) corresponding to known values of {u} (CalcBu in la.Equations)
 //  I -- node number
 //  t -- time
-func (o *FdmLaplacian) calcBu(I int, t float64) float64 {
+func (o *FdmLaplacian) calcBu(I int, t float64) (res float64) {
+	x := o.Grid.Node(I)
 	if o.Source != nil {
-		return o.Source(o.Grid.Node(I), t)
+		res = o.Source(x, t)
 	}
-	return 0
+	if o.rhsBcs != nil {
+		for _, term := range o.rhsBcs[I] {
+			res -= term.w * term.bc.value(t, x)
+		}
+	}
+	return
+}
+
+// allocEqs allocates the uu, uk, ku and kk parts of the sparse matrix of the equations
+//  cols -- [neq] column indices J of the non-zero entries of each (full) row I. Repeated
+//          indices are allowed; e.g. when the same entry receives many contributions
+func allocEqs(eqs *la.Equations, cols [][]int, reactions bool) {
+	nnz := make([]int, 4) // uu, uk, ku, kk
+	for I, row := range cols {
+		for _, J := range row {
+			switch {
+			case eqs.FtoU[I] >= 0 && eqs.FtoU[J] >= 0:
+				nnz[0]++
+			case eqs.FtoU[I] >= 0:
+				nnz[1]++
+			case eqs.FtoU[J] >= 0:
+				nnz[2]++
+			default:
+				nnz[3]++
+			}
+		}
+	}
+	eqs.Alloc(nnz, reactions, true)
+}
+
+// fdmNaturalBc holds the data of a natural boundary condition: n ⋅ (k K ∇u) + β u = q
+type fdmNaturalBc struct {
+	beta   float64 // coefficient β
+	cvalue float64 // constant value of q
+	fvalue dbf.T   // function q(t,{x}) [may be nil]
+}
+
+// value returns q(t,{x})
+func (o *fdmNaturalBc) value(t float64, x la.Vector) float64 {
+	if o.fvalue != nil {
+		return o.fvalue.F(t, x)
+	}
+	return o.cvalue
+}
+
+// fdmBcTerm holds the contribution w ⋅ q(t,{x}) of a natural boundary condition
+type fdmBcTerm struct {
+	bc *fdmNaturalBc // boundary condition
+	w  float64       // weight
+}
+
+// fdmForm holds the linear combination Σ vals[k] ⋅ u[cols[k]] + Σ bcs[k].w ⋅ bcs[k].q
+type fdmForm struct {
+	cols []int       // node numbers
+	vals []float64   // coefficients
+	bcs  []fdmBcTerm // contributions of natural boundary conditions
+}
+
+// add adds v ⋅ u[J] to form
+func (o *fdmForm) add(J int, v float64) {
+	if v == 0 {
+		return
+	}
+	for k, col := range o.cols {
+		if col == J {
+			o.vals[k] += v
+			return
+		}
+	}
+	o.cols = append(o.cols, J)
+	o.vals = append(o.vals, v)
+}
+
+// addForm adds α ⋅ b to form
+func (o *fdmForm) addForm(α float64, b *fdmForm) {
+	if α == 0 {
+		return
+	}
+	for k, J := range b.cols {
+		o.add(J, α*b.vals[k])
+	}
+	for _, term := range b.bcs {
+		o.addBc(term.bc, α*term.w)
+	}
+}
+
+// addBc adds w ⋅ q to form
+func (o *fdmForm) addBc(bc *fdmNaturalBc, w float64) {
+	if w == 0 {
+		return
+	}
+	for k, term := range o.bcs {
+		if term.bc == bc {
+			o.bcs[k].w += w
+			return
+		}
+	}
+	o.bcs = append(o.bcs, fdmBcTerm{bc, w})
+}
+
+// fdmStencil computes the finite difference equations at the nodes of a grid
+type fdmStencil struct {
+	lap    *FdmLaplacian // operator
+	ndim   int           // space dimension
+	npts   []int         // number of points along each direction
+	rect   bool          // rectangular grid: use physical coordinates
+	h      [][]float64   // [ndim][npts-1] spacing along each direction
+	kk     []float64     // diagonal of K
+	stride []int         // increment of node number along each direction
+}
+
+// newFdmStencil returns a new stencil calculator
+func newFdmStencil(lap *FdmLaplacian) (o *fdmStencil) {
+	o = new(fdmStencil)
+	o.lap = lap
+	g := lap.Grid
+	o.ndim = g.Ndim()
+	o.npts = make([]int, o.ndim)
+	o.stride = make([]int, o.ndim)
+	o.kk = []float64{lap.Kx, lap.Ky, lap.Kz}
+	for d := 0; d < o.ndim; d++ {
+		o.npts[d] = g.Npts(d)
+		if o.npts[d] < 3 {
+			chk.Panic("FdmLaplacian requires at least 3 points along each direction. npts[%d]=%d is invalid\n", d, o.npts[d])
+		}
+	}
+	o.stride[0] = 1
+	for d := 1; d < o.ndim; d++ {
+		o.stride[d] = o.stride[d-1] * o.npts[d-1]
+	}
+
+	// check if the grid lines are aligned with the axes
+	o.rect = true
+	for I := 0; I < g.Size() && o.rect; I++ {
+		m, n, p := g.IndexItoMNP(I)
+		for d := 0; d < o.ndim && o.rect; d++ {
+			gd := g.CovarBasis(m, n, p, d)
+			for j := 0; j < o.ndim; j++ {
+				if j != d && math.Abs(gd[j]) > 1e-14*gd.Norm() {
+					o.rect = false
+				}
+			}
+		}
+	}
+
+	// spacing: snap to uniform spacing if possible to obtain the classical stencils
+	o.h = make([][]float64, o.ndim)
+	for d := 0; d < o.ndim; d++ {
+		n := o.npts[d]
+		c := make([]float64, n)
+		idx := []int{0, 0, 0}
+		for i := 0; i < n; i++ {
+			idx[d] = i
+			if o.rect {
+				c[i] = g.X(idx[0], idx[1], idx[2])[d]
+			} else {
+				c[i] = g.U(idx[0], idx[1], idx[2])[d]
+			}
+		}
+		o.h[d] = make([]float64, n-1)
+		huni := (c[n-1] - c[0]) / float64(n-1)
+		uniform := true
+		for i := 0; i < n-1; i++ {
+			o.h[d][i] = c[i+1] - c[i]
+			if o.h[d][i] <= 0 {
+				chk.Panic("coordinates along direction %d must be strictly increasing\n", d)
+			}
+			if math.Abs(o.h[d][i]-huni) > 1e-10*huni {
+				uniform = false
+			}
+		}
+		if uniform {
+			for i := 0; i < n-1; i++ {
+				o.h[d][i] = huni
+			}
+		}
+	}
+	return
+}
+
+// equation computes the finite difference equation at node I
+func (o *fdmStencil) equation(I int) (row *fdmForm) {
+
+	// node
+	g := o.lap.Grid
+	m, n, p := g.IndexItoMNP(I)
+	idx := []int{m, n, p}
+	x := g.Node(I)
+	nd := o.ndim
+
+	// contravariant basis vectors
+	gc := make([]la.Vector, nd)
+	for i := 0; i < nd; i++ {
+		gc[i] = la.NewVector(nd)
+		if o.rect {
+			gc[i][i] = 1
+			continue
+		}
+		for j := 0; j < nd; j++ {
+			gj := g.CovarBasis(m, n, p, j)
+			gij := g.ContraMatrix(m, n, p).Get(i, j)
+			for a := 0; a < nd; a++ {
+				gc[i][a] += gij * gj[a]
+			}
+		}
+	}
+
+	// coefficients: Gⁱʲ = k gⁱ⋅K⋅gʲ and cʲ = ∇k⋅K⋅gʲ - Γʲₐᵦ Gᵃᵇ
+	k := 1.0
+	dk := la.NewVector(nd)
+	if o.lap.Kvar != nil {
+		k = o.lap.Kvar.F(0, x)
+		o.lap.Kvar.Grad(dk, 0, x)
+	}
+	Kg := make([]la.Vector, nd) // K⋅gʲ
+	for j := 0; j < nd; j++ {
+		Kg[j] = la.NewVector(nd)
+		for a := 0; a < nd; a++ {
+			Kg[j][a] = o.kk[a] * gc[j][a]
+		}
+	}
+	G := la.NewMatrix(nd, nd)
+	c := la.NewVector(nd)
+	for i := 0; i < nd; i++ {
+		for j := 0; j < nd; j++ {
+			G.Set(i, j, k*la.VecDot(gc[i], Kg[j]))
+		}
+	}
+	for j := 0; j < nd; j++ {
+		c[j] = la.VecDot(dk, Kg[j])
+		if !o.rect {
+			for a := 0; a < nd; a++ {
+				for b := 0; b < nd; b++ {
+					c[j] -= g.GammaS(m, n, p, j, a, b) * G.Get(a, b)
+				}
+			}
+		}
+	}
+
+	// boundary directions: side = -1 (min), +1 (max) or 0 (interior)
+	side := make([]int, nd)
+	var bry []int
+	for d := 0; d < nd; d++ {
+		if idx[d] == 0 {
+			side[d] = -1
+		} else if idx[d] == o.npts[d]-1 {
+			side[d] = +1
+		}
+		if side[d] != 0 {
+			bry = append(bry, d)
+		}
+	}
+
+	// first derivatives
+	d1 := make([]*fdmForm, nd)
+	for d := 0; d < nd; d++ {
+		if side[d] == 0 {
+			d1[d] = o.firstDeriv(I, idx, d)
+		}
+	}
+	if len(bry) > 0 {
+		o.naturalBcs(I, idx, side, bry, gc, Kg, k, d1)
+	}
+
+	// equation: Gⁱʲ ∂²u/∂uⁱ∂uʲ + cʲ ∂u/∂uʲ
+	row = new(fdmForm)
+	for d := 0; d < nd; d++ {
+		row.addForm(G.Get(d, d), o.secondDeriv(I, idx, side, d, d1[d]))
+	}
+	for i := 0; i < nd; i++ {
+		for j := i + 1; j < nd; j++ {
+			if G.Get(i, j) != 0 {
+				row.addForm(2*G.Get(i, j), o.mixedDeriv(I, idx, i, j))
+			}
+		}
+	}
+	for j := 0; j < nd; j++ {
+		row.addForm(c[j], d1[j])
+	}
+	return
+}
+
+// naturalBcs computes the first derivatives along the boundary directions from the natural
+// boundary conditions n ⋅ (k K ∇u) + β u = q, with ∇u = Σ gʲ ∂u/∂uʲ
+func (o *fdmStencil) naturalBcs(I int, idx, side, bry []int, gc, Kg []la.Vector, k float64, d1 []*fdmForm) {
+
+	// boundary conditions (without prescribed conditions ⇒ insulated: q = 0)
+	nb := len(bry)
+	M := la.NewMatrix(nb, nb)
+	rhs := make([]*fdmForm, nb)
+	for a, e := range bry {
+		tag := (e + 1) * 10
+		if o.ndim == 3 {
+			tag *= 10
+		}
+		if side[e] > 0 {
+			tag++
+		}
+		rhs[a] = new(fdmForm)
+		if bc, ok := o.lap.natBcs[tag]; ok {
+			rhs[a].addBc(bc, 1)
+			rhs[a].add(I, -bc.beta)
+		}
+
+		// normal and coefficients of ∂u/∂uʲ
+		nrm := la.NewVector(o.ndim)
+		nrm.Apply(float64(side[e])/gc[e].Norm(), gc[e])
+		for j := 0; j < o.ndim; j++ {
+			coef := k * la.VecDot(nrm, Kg[j])
+			if side[j] == 0 {
+				rhs[a].addForm(-coef, d1[j])
+				continue
+			}
+			for b, f := range bry {
+				if f == j {
+					M.Set(a, b, coef)
+				}
+			}
+		}
+	}
+
+	// solve for the derivatives
+	Mi := la.NewMatrix(nb, nb)
+	det := la.MatInvSmall(Mi, M, 1e-13)
+	if math.Abs(det) < 1e-13 {
+		chk.Panic("cannot impose natural boundary conditions at node %d\n", I)
+	}
+	for b, j := range bry {
+		d1[j] = new(fdmForm)
+		for a := 0; a < nb; a++ {
+			d1[j].addForm(Mi.Get(b, a), rhs[a])
+		}
+	}
+}
+
+// firstDeriv returns the second-order approximation of ∂u/∂uᵈ (central or one-sided)
+func (o *fdmStencil) firstDeriv(I int, idx []int, d int) (f *fdmForm) {
+	f = new(fdmForm)
+	offsets, weights := o.firstDerivWeights(idx[d], d)
+	for k, off := range offsets {
+		f.add(I+off*o.stride[d], weights[k])
+	}
+	return
+}
+
+// firstDerivWeights returns the offsets and weights of the three-point approximation of the
+// first derivative at point i along direction d (central or one-sided at the boundaries)
+func (o *fdmStencil) firstDerivWeights(i, d int) (offsets []int, weights []float64) {
+	h := o.h[d]
+	switch i {
+	case 0:
+		h1, h2 := h[0], h[1]
+		return []int{0, 1, 2}, []float64{-(2*h1 + h2) / (h1 * (h1 + h2)), (h1 + h2) / (h1 * h2), -h1 / (h2 * (h1 + h2))}
+	case o.npts[d] - 1:
+		h1, h2 := h[i-1], h[i-2]
+		return []int{0, -1, -2}, []float64{(2*h1 + h2) / (h1 * (h1 + h2)), -(h1 + h2) / (h1 * h2), h1 / (h2 * (h1 + h2))}
+	}
+	hm, hp := h[i-1], h[i]
+	return []int{-1, 0, 1}, []float64{-hp / (hm * (hm + hp)), (hp - hm) / (hm * hp), hm / (hp * (hm + hp))}
+}
+
+// secondDeriv returns the approximation of ∂²u/∂uᵈ∂uᵈ. At boundaries, the ghost point is
+// eliminated using the first derivative d1 (computed from the natural boundary conditions)
+func (o *fdmStencil) secondDeriv(I int, idx, side []int, d int, d1 *fdmForm) (f *fdmForm) {
+	f = new(fdmForm)
+	i, s := idx[d], o.stride[d]
+	switch side[d] {
+	case -1: // u[-1] = u[1] - 2 h u'
+		h := o.h[d][0]
+		f.add(I+s, 2/(h*h))
+		f.add(I, -2/(h*h))
+		f.addForm(-2/h, d1)
+	case +1: // u[n] = u[n-2] + 2 h u'
+		h := o.h[d][i-1]
+		f.add(I-s, 2/(h*h))
+		f.add(I, -2/(h*h))
+		f.addForm(2/h, d1)
+	default:
+		hm, hp := o.h[d][i-1], o.h[d][i]
+		f.add(I-s, 2/(hm*(hm+hp)))
+		f.add(I, -2/(hm*hp))
+		f.add(I+s, 2/(hp*(hm+hp)))
+	}
+	return
+}
+
+// mixedDeriv returns the approximation of ∂²u/∂uⁱ∂uʲ (i ≠ j) by the product of first derivatives
+func (o *fdmStencil) mixedDeriv(I int, idx []int, i, j int) (f *fdmForm) {
+	f = new(fdmForm)
+	offi, wi := o.firstDerivWeights(idx[i], i)
+	offj, wj := o.firstDerivWeights(idx[j], j)
+	for a := range offi {
+		for b := range offj {
+			f.add(I+offi[a]*o.stride[i]+offj[b]*o.stride[j], wi[a]*wj[b])
+		}
+	}
+	return
 }
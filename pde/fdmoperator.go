@@ -0,0 +1,468 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/utl"
+)
+
+// FdmOperator implements a generic Finite Difference (FDM) operator for linear differential
+// operators with constant or variable coefficients on rectangular grids (2D or 3D)
+//
+//             ∂²u              ∂u
+//    L{u} = aᵢⱼ ———————  +  bᵢ ———  +  c u
+//             ∂xᵢ ∂xⱼ          ∂xᵢ
+//
+//  where the terms are given by AddSecondDeriv, AddFirstDeriv and AddReaction. The derivatives
+//  are approximated by stencils with 2nd, 4th or 6th order of accuracy (Order) whose weights are
+//  computed by Fornberg's algorithm; thus, non-uniformly spaced grids are allowed (with reduced
+//  accuracy of the central stencils). Near the boundaries, the stencils are shifted inwards
+//  (one-sided) keeping the order of accuracy. Cross derivatives are computed by the product of
+//  first derivatives. First derivatives may use upwind-biased stencils (e.g. for convection).
+//
+//  Natural boundary conditions are given on edges (2D) or faces (3D) as follows
+//
+//    n ⋅ ∇u + β u = q
+//
+//  where n is the unit outward normal. These conditions replace the equations at the boundary
+//  nodes and use one-sided stencils. At nodes shared by more than one edge (face) with natural
+//  conditions, the condition with the smallest tag is used. Boundary nodes without prescribed
+//  conditions use the operator itself (with one-sided stencils).
+//
+//  NOTE: the system is [A]⋅{u} = {b} where {b} collects the source term s({x},t) and the values
+//        q of the natural boundary conditions
+//
+//  Reference:
+//    [1] Fornberg B (1988) Generation of finite difference formulas on arbitrarily spaced grids.
+//        Mathematics of Computation, 51(184):699-706
+type FdmOperator struct {
+	Order    int           // order of accuracy of stencils: 2, 4 or 6 [default = 2]
+	Grid     *gm.Grid      // grid
+	Source   fun.Svs       // source term function s({x},t) [may be nil]
+	EssenBcs *EssentialBcs // essential boundary conditions
+	Eqs      *la.Equations // equations
+	bcsReady bool          // boundary conditions are set
+	order    int           // order of stencils when equations were allocated
+
+	// terms and natural boundary conditions
+	terms  []*fdmTerm            // terms of operator
+	natBcs map[int]*fdmNaturalBc // tag ⇒ natural boundary condition
+	bcRows []*fdmNaturalBc       // [node] natural boundary condition replacing the equation at node
+
+	// grid data
+	ndim   int         // space dimension
+	coords [][]float64 // [ndim][npts] coordinates along each direction
+	stride []int       // increment of node number along each direction
+}
+
+// fdmTerm holds a term a({x}) ⋅ D{u} of a linear differential operator where D is a derivative
+type fdmTerm struct {
+	i, j   int     // directions: ∂²/∂xᵢ∂xⱼ if j ≥ 0; ∂/∂xᵢ if i ≥ 0 and j < 0; identity if i < 0
+	upwind int     // order of upwind-biased stencil of first derivative (0 ⇒ central)
+	cvalue float64 // constant coefficient
+	fvalue dbf.T   // variable coefficient a({x}) [may be nil]
+}
+
+// coef returns the coefficient @ x
+func (o *fdmTerm) coef(x la.Vector) float64 {
+	if o.fvalue != nil {
+		return o.fvalue.F(0, x)
+	}
+	return o.cvalue
+}
+
+// NewFdmOperator creates a new (empty) FDM operator
+//  grid   -- rectangular grid (e.g. from RectGenUniform or RectSet2d)
+//  source -- source term s({x},t) [may be nil]
+func NewFdmOperator(grid *gm.Grid, source fun.Svs) (o *FdmOperator) {
+	o = new(FdmOperator)
+	o.Order = 2
+	o.Grid = grid
+	o.Source = source
+	o.EssenBcs = NewEssentialBcsGrid(grid, 1) // 1:maxNdof
+	o.natBcs = make(map[int]*fdmNaturalBc)
+	o.ndim = grid.Ndim()
+	o.coords = make([][]float64, o.ndim)
+	o.stride = make([]int, o.ndim)
+	for d := 0; d < o.ndim; d++ {
+		n := grid.Npts(d)
+		o.coords[d] = make([]float64, n)
+		idx := []int{0, 0, 0}
+		for i := 0; i < n; i++ {
+			idx[d] = i
+			o.coords[d][i] = grid.X(idx[0], idx[1], idx[2])[d]
+			if i > 0 && o.coords[d][i] <= o.coords[d][i-1] {
+				chk.Panic("coordinates along direction %d must be strictly increasing\n", d)
+			}
+		}
+		c := o.coords[d]
+		huni := (c[n-1] - c[0]) / float64(n-1)
+		uniform := true
+		for i := 1; i < n && uniform; i++ {
+			uniform = math.Abs(c[i]-c[i-1]-huni) <= 1e-10*huni
+		}
+		if uniform { // snap to uniform spacing to obtain the classical stencils
+			for i := 1; i < n; i++ {
+				c[i] = c[0] + float64(i)*huni
+			}
+		}
+		o.stride[d] = 1
+		if d > 0 {
+			o.stride[d] = o.stride[d-1] * grid.Npts(d-1)
+		}
+	}
+	for I := 0; I < grid.Size(); I++ {
+		m, n, p := grid.IndexItoMNP(I)
+		for d := 0; d < o.ndim; d++ {
+			gd := grid.CovarBasis(m, n, p, d)
+			for j := 0; j < o.ndim; j++ {
+				if j != d && math.Abs(gd[j]) > 1e-14*gd.Norm() {
+					chk.Panic("FdmOperator requires a rectangular grid\n")
+				}
+			}
+		}
+	}
+	return
+}
+
+// AddSecondDeriv adds the term a({x}) ∂²u/∂xᵢ∂xⱼ to the operator
+//   i, j   -- directions; e.g. i = j = 0 ⇒ ∂²u/∂x²; i = 0 and j = 1 ⇒ ∂²u/∂x∂y
+//   cvalue -- constant coefficient [optional]; or
+//   fvalue -- variable coefficient a({x}) [optional]
+//  NOTE: the mixed derivative is added once; e.g. ∇ ⋅ (A ∇u) with a symmetric A requires 2 a₀₁
+func (o *FdmOperator) AddSecondDeriv(i, j int, cvalue float64, fvalue dbf.T) {
+	o.bcsReady = false
+	o.checkDir(i)
+	o.checkDir(j)
+	if j < i {
+		i, j = j, i
+	}
+	o.terms = append(o.terms, &fdmTerm{i, j, 0, cvalue, fvalue})
+}
+
+// AddFirstDeriv adds the term b({x}) ∂u/∂xᵢ to the operator
+//  i      -- direction
+//  upwind -- 0 ⇒ central stencil with Order accuracy; otherwise, upwind-biased stencil with
+//            given order of accuracy (1, 2 or 3). The stencil is biased towards +xᵢ if b > 0
+//            and towards -xᵢ if b < 0; i.e. the information is taken from upstream when L{u}
+//            represents convection with velocity vᵢ = -bᵢ; e.g. ∂u/∂t = L{u} or L{u} = s
+//  cvalue -- constant coefficient [optional]; or
+//  fvalue -- variable coefficient b({x}) [optional]
+func (o *FdmOperator) AddFirstDeriv(i, upwind int, cvalue float64, fvalue dbf.T) {
+	o.bcsReady = false
+	o.checkDir(i)
+	if upwind < 0 || upwind > 3 {
+		chk.Panic("upwind=%d is invalid; it must be 0 (central), 1, 2 or 3\n", upwind)
+	}
+	o.terms = append(o.terms, &fdmTerm{i, -1, upwind, cvalue, fvalue})
+}
+
+// AddReaction adds the term c({x}) u to the operator
+//  cvalue -- constant coefficient [optional]; or
+//  fvalue -- variable coefficient c({x}) [optional]
+func (o *FdmOperator) AddReaction(cvalue float64, fvalue dbf.T) {
+	o.bcsReady = false
+	o.terms = append(o.terms, &fdmTerm{-1, -1, 0, cvalue, fvalue})
+}
+
+// AddBc adds essential or natural boundary condition
+//  essential -- essential BC; otherwise natural (Neumann) boundary condition: n ⋅ ∇u = q
+//  tag       -- edge or face tag in grid
+//  cvalue    -- constant value [optional]; or
+//  fvalue    -- function value [optional]
+func (o *FdmOperator) AddBc(essential bool, tag int, cvalue float64, fvalue dbf.T) {
+	o.bcsReady = false
+	if essential {
+		o.EssenBcs.AddUsingTag(tag, 0, cvalue, fvalue)
+		return
+	}
+	o.AddRobinBc(tag, 0, cvalue, fvalue)
+}
+
+// AddRobinBc adds natural boundary condition of Robin type: n ⋅ ∇u + β u = q
+//  tag    -- edge or face tag in grid
+//  beta   -- coefficient β
+//  cvalue -- constant value of q [optional]; or
+//  fvalue -- function q(t,{x}) [optional]
+func (o *FdmOperator) AddRobinBc(tag int, beta, cvalue float64, fvalue dbf.T) {
+	if len(o.Grid.Boundary(tag)) == 0 {
+		chk.Panic("cannot find nodes with tag=%d\n", tag)
+	}
+	o.bcsReady = false
+	o.natBcs[tag] = &fdmNaturalBc{beta, cvalue, fvalue}
+}
+
+// Assemble assembles operator into A matrix from [A] ⋅ {u} = {b}
+//  reactions -- prepare for computation of RHS
+func (o *FdmOperator) Assemble(reactions bool) {
+
+	// check
+	if o.Order != 2 && o.Order != 4 && o.Order != 6 {
+		chk.Panic("Order=%d is invalid; it must be 2, 4 or 6\n", o.Order)
+	}
+	if len(o.terms) == 0 {
+		chk.Panic("operator has no terms\n")
+	}
+
+	// natural boundary conditions replacing equations
+	nnodes := o.Grid.Size()
+	o.bcRows = make([]*fdmNaturalBc, nnodes)
+	tags := make([]int, 0, len(o.natBcs))
+	for tag := range o.natBcs {
+		tags = append(tags, tag)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(tags))) // the smallest tag is set last
+	for _, tag := range tags {
+		for _, I := range o.Grid.Boundary(tag) {
+			o.bcRows[I] = o.natBcs[tag]
+		}
+	}
+
+	// equations of all nodes
+	rows := make([]*fdmForm, nnodes)
+	for I := 0; I < nnodes; I++ {
+		rows[I] = o.equation(I)
+	}
+
+	// allocate equations
+	if !o.bcsReady || o.order != o.Order {
+		o.Eqs = la.NewEquations(nnodes, o.EssenBcs.Nodes())
+		cols := make([][]int, nnodes)
+		for I, row := range rows {
+			cols[I] = row.cols
+		}
+		allocEqs(o.Eqs, cols, reactions)
+		o.bcsReady = true
+		o.order = o.Order
+	}
+
+	// assemble
+	o.Eqs.Start()
+	for I, row := range rows {
+		for k, J := range row.cols {
+			o.Eqs.Put(I, J, row.vals[k])
+		}
+	}
+}
+
+// SolveSteady solves steady problem
+//  Solves: [K]⋅{u} = {f} represented by [A]⋅{x} = {b}
+func (o *FdmOperator) SolveSteady(reactions bool) (u, f []float64) {
+	o.Eqs.SolveOnce(o.calcXk, o.calcBu)
+	u = make([]float64, o.Grid.Size())
+	o.Eqs.JoinVector(u, o.Eqs.Xu, o.Eqs.Xk)
+	if reactions {
+		f = make([]float64, o.Grid.Size())
+		if o.Eqs.Nk > 0 { // need to calc Bu again because it was modified
+			for i, I := range o.Eqs.UtoF {
+				o.Eqs.Bu[i] = o.calcBu(I, 0)
+			}
+		}
+		o.Eqs.JoinVector(f, o.Eqs.Bu, o.Eqs.Bk)
+	}
+	return
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// checkDir checks direction
+func (o *FdmOperator) checkDir(i int) {
+	if i < 0 || i >= o.ndim {
+		chk.Panic("direction %d is invalid; it must be in [0,%d]\n", i, o.ndim-1)
+	}
+}
+
+// calcXk calculates know {u} values (CalcXk in la.Equations)
+//  I -- node number
+//  t -- time
+func (o *FdmOperator) calcXk(I int, t float64) float64 {
+	val, available := o.EssenBcs.Value(I, 0, t)
+	if available {
+		return val
+	}
+	return 0
+}
+
+// calcBu calculates RHS vector corresponding to known values of {u} (CalcBu in la.Equations)
+//  I -- node number
+//  t -- time
+func (o *FdmOperator) calcBu(I int, t float64) float64 {
+	x := o.Grid.Node(I)
+	if o.bcRows[I] != nil {
+		return o.bcRows[I].value(t, x)
+	}
+	if o.Source != nil {
+		return o.Source(x, t)
+	}
+	return 0
+}
+
+// equation computes the finite difference equation at node I
+func (o *FdmOperator) equation(I int) (row *fdmForm) {
+	m, n, p := o.Grid.IndexItoMNP(I)
+	idx := []int{m, n, p}
+	row = new(fdmForm)
+
+	// natural boundary condition: Σ nᵈ ∂u/∂xᵈ + β u
+	if bc := o.bcRows[I]; bc != nil {
+		for d := 0; d < o.ndim; d++ {
+			side := 0.0
+			if idx[d] == 0 {
+				side = -1
+			} else if idx[d] == len(o.coords[d])-1 {
+				side = +1
+			}
+			if side == 0 || !o.onSide(I, bc, d) {
+				continue
+			}
+			row.addForm(side, o.deriv(I, idx, d, 1, 0, 0))
+		}
+		row.add(I, bc.beta)
+		return
+	}
+
+	// operator
+	x := o.Grid.Node(I)
+	for _, term := range o.terms {
+		a := term.coef(x)
+		switch {
+		case term.i < 0:
+			row.add(I, a)
+		case term.j < 0:
+			row.addForm(a, o.deriv(I, idx, term.i, 1, term.upwind, a))
+		case term.i == term.j:
+			row.addForm(a, o.deriv(I, idx, term.i, 2, 0, 0))
+		default:
+			row.addForm(a, o.mixedDeriv(I, idx, term.i, term.j))
+		}
+	}
+	return
+}
+
+// onSide returns whether the boundary condition bc is given on the side normal to direction d
+// containing node I
+func (o *FdmOperator) onSide(I int, bc *fdmNaturalBc, d int) bool {
+	for tag, b := range o.natBcs {
+		if b != bc {
+			continue
+		}
+		dir := tag/10 - 1
+		if o.ndim == 3 {
+			dir = tag/100 - 1
+		}
+		if dir == d && utl.IntIndexSmall(o.Grid.Boundary(tag), I) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// deriv returns the approximation of the m-th derivative ∂ᵐu/∂xᵈ at node I
+//  upwind -- order of upwind-biased stencil (0 ⇒ central)
+//  b      -- coefficient defining the direction of the upwind-biased stencil
+func (o *FdmOperator) deriv(I int, idx []int, d, m, upwind int, b float64) (f *fdmForm) {
+	f = new(fdmForm)
+	offsets, weights := o.stencil(idx[d], d, m, upwind, b)
+	for k, off := range offsets {
+		f.add(I+off*o.stride[d], weights[k])
+	}
+	return
+}
+
+// mixedDeriv returns the approximation of ∂²u/∂xⁱ∂xʲ (i ≠ j) by the product of first derivatives
+func (o *FdmOperator) mixedDeriv(I int, idx []int, i, j int) (f *fdmForm) {
+	f = new(fdmForm)
+	offi, wi := o.stencil(idx[i], i, 1, 0, 0)
+	offj, wj := o.stencil(idx[j], j, 1, 0, 0)
+	for a := range offi {
+		for b := range offj {
+			f.add(I+offi[a]*o.stride[i]+offj[b]*o.stride[j], wi[a]*wj[b])
+		}
+	}
+	return
+}
+
+// stencil returns the offsets and weights of the approximation of the m-th derivative at point i
+// along direction d
+//
+//  The central stencils have Order + 1 points. Near the boundaries, the stencils are shifted
+//  inwards and have m + Order points. The upwind-biased stencils of order q have q + 1 points
+//  with ⌊(q-1)/2⌋ points downstream.
+func (o *FdmOperator) stencil(i, d, m, upwind int, b float64) (offsets []int, weights []float64) {
+
+	// window of points [lo, hi]
+	n := len(o.coords[d])
+	var lo, size int
+	if upwind > 0 {
+		size = upwind + 1
+		down := (upwind - 1) / 2
+		lo = i - down
+		if b < 0 {
+			lo = i + down - upwind
+		}
+	} else {
+		size = o.Order + 1
+		lo = i - o.Order/2
+		if lo < 0 || lo+size > n {
+			size = m + o.Order
+			lo = 0
+			if i >= n/2 {
+				lo = n - size
+			}
+		}
+	}
+	if size > n {
+		chk.Panic("the stencil requires %d points along direction %d but there are %d\n", size, d, n)
+	}
+	lo = utl.Imax(0, utl.Imin(lo, n-size))
+
+	// weights
+	c := fdmWeights(o.coords[d][i], o.coords[d][lo:lo+size], m)
+	offsets = make([]int, size)
+	for k := 0; k < size; k++ {
+		offsets[k] = lo + k - i
+	}
+	return offsets, c[m]
+}
+
+// fdmWeights computes the weights of finite difference formulae for the derivatives of order 0 to
+// m at z using the points x (Fornberg's algorithm; see [1])
+//  Output:
+//    c -- [m+1][len(x)] weights; e.g. dᵐu/dxᵐ(z) ≈ Σ c[m][j] u(x[j])
+func fdmWeights(z float64, x []float64, m int) (c [][]float64) {
+	n := len(x)
+	c = utl.Alloc(m+1, n)
+	c1, c4 := 1.0, x[0]-z
+	c[0][0] = 1
+	for i := 1; i < n; i++ {
+		mn := utl.Imin(i, m)
+		c2, c5 := 1.0, c4
+		c4 = x[i] - z
+		for j := 0; j < i; j++ {
+			c3 := x[i] - x[j]
+			c2 *= c3
+			if j == i-1 {
+				for k := mn; k > 0; k-- {
+					c[k][i] = c1 * (float64(k)*c[k-1][i-1] - c5*c[k][i-1]) / c2
+				}
+				c[0][i] = -c1 * c5 * c[0][i-1] / c2
+			}
+			for k := mn; k > 0; k-- {
+				c[k][j] = (c4*c[k][j] - float64(k)*c[k-1][j]) / c3
+			}
+			c[0][j] = c4 * c[0][j] / c3
+		}
+		c1 = c2
+	}
+	return
+}
@@ -0,0 +1,432 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/la"
+)
+
+// femBase implements the data structures and algorithms shared by the FEM solvers; e.g. the
+// computation of element matrices over the cells of each partition of the mesh (concurrently),
+// the integration over edges (2D) or faces (3D) of cells and the assembly of global matrices
+//
+//  The equation numbers are I = v ⋅ ndof + d where v is the vertex id and d is the local index
+//  of the degree-of-freedom (dof)
+type femBase struct {
+	mesh   *msh.Mesh           // mesh
+	ndim   int                 // space dimension
+	ndof   int                 // number of degrees-of-freedom per vertex
+	neq    int                 // total number of equations
+	parts  [][]*msh.Cell       // [npart] active cells of each partition
+	integs [][]*msh.Integrator // [npart][TypeNumMax] integrators of each partition (allocated on demand)
+	ebcs   *EssentialBcs       // essential boundary conditions
+	nbcs   []*femNaturalBc     // natural boundary conditions
+	kmat   []*la.Matrix        // [ncells] element "stiffness" matrices
+	mmat   []*la.Matrix        // [ncells] element "mass" matrices
+}
+
+// femNaturalBc holds the data of a natural boundary condition
+//
+//  The flux (traction) q and the Robin coefficient β result in the following terms
+//
+//    ⌠                         ⌠
+//    │ Nᵃ q(t,{x}) dΓ   and    │ β Nᵃ Nᵇ dΓ
+//    ⌡                         ⌡
+//     Γ                         Γ
+type femNaturalBc struct {
+	dof    int                 // index of degree-of-freedom
+	beta   float64             // Robin coefficient β
+	cvalue float64             // constant value of q
+	fvalue dbf.T               // function q(t,{x}) [may be nil]
+	bry    msh.BoundaryDataSet // cells and local ids of edges (2D) or faces (3D)
+}
+
+// value returns q(t,{x})
+func (o *femNaturalBc) value(t float64, x la.Vector) float64 {
+	if o.fvalue != nil {
+		return o.fvalue.F(t, x)
+	}
+	return o.cvalue
+}
+
+// newFemBase returns a new femBase structure
+func newFemBase(mesh *msh.Mesh, ndof int) (o *femBase) {
+	o = new(femBase)
+	o.mesh = mesh
+	o.ndim = mesh.Ndim
+	o.ndof = ndof
+	o.neq = len(mesh.Verts) * ndof
+	o.ebcs = NewEssentialBcsMesh(mesh, ndof)
+
+	// partitions
+	var ids []int
+	for part := range mesh.Tmaps.CellPart2cells {
+		ids = append(ids, part)
+	}
+	sort.Ints(ids)
+	for _, part := range ids {
+		var cells []*msh.Cell
+		for _, cell := range mesh.Tmaps.CellPart2cells[part] {
+			if cell.Disabled {
+				continue
+			}
+			if cell.Gndim != o.ndim {
+				chk.Panic("FEM solver requires cells with the same dimension as the mesh. cell %d has gndim=%d != %d\n", cell.ID, cell.Gndim, o.ndim)
+			}
+			cells = append(cells, cell)
+		}
+		if len(cells) > 0 {
+			o.parts = append(o.parts, cells)
+		}
+	}
+	if len(o.parts) == 0 {
+		chk.Panic("there are no active cells in mesh\n")
+	}
+	o.integs = make([][]*msh.Integrator, len(o.parts))
+	for i := range o.parts {
+		o.integs[i] = make([]*msh.Integrator, msh.TypeNumMax)
+	}
+	return
+}
+
+// integrator returns the integrator of cell type in given partition
+func (o *femBase) integrator(part, ctype int) *msh.Integrator {
+	if o.integs[part][ctype] == nil {
+		o.integs[part][ctype] = msh.NewIntegrator(ctype, nil, "")
+	}
+	return o.integs[part][ctype]
+}
+
+// addNaturalBc adds natural boundary condition to edges (2D) or faces (3D) with given tag
+func (o *femBase) addNaturalBc(tag, dof int, beta, cvalue float64, fvalue dbf.T) {
+	if dof < 0 || dof >= o.ndof {
+		chk.Panic("dof=%d is invalid; it must be in [0,%d]\n", dof, o.ndof-1)
+	}
+	bry := o.mesh.Tmaps.EdgeTag2cells[tag]
+	if o.ndim == 3 {
+		bry = o.mesh.Tmaps.FaceTag2cells[tag]
+	}
+	if len(bry) == 0 {
+		chk.Panic("cannot find cells with boundary tag=%d\n", tag)
+	}
+	o.nbcs = append(o.nbcs, &femNaturalBc{dof, beta, cvalue, fvalue, bry})
+}
+
+// cellEqs returns the equation numbers of cell
+func (o *femBase) cellEqs(cell *msh.Cell) (eqs []int) {
+	eqs = make([]int, len(cell.V)*o.ndof)
+	for m, v := range cell.V {
+		for d := 0; d < o.ndof; d++ {
+			eqs[m*o.ndof+d] = v*o.ndof + d
+		}
+	}
+	return
+}
+
+// knownEqs returns the equation numbers with essential boundary conditions
+func (o *femBase) knownEqs() (eqs []int) {
+	for _, v := range o.ebcs.Nodes() {
+		for d := 0; d < o.ndof; d++ {
+			if _, available := o.ebcs.Value(v, d, 0); available {
+				eqs = append(eqs, v*o.ndof+d)
+			}
+		}
+	}
+	return
+}
+
+// calcXk returns the prescribed value at equation I (CalcXk in la.Equations)
+func (o *femBase) calcXk(I int, t float64) float64 {
+	val, _ := o.ebcs.Value(I/o.ndof, I%o.ndof, t)
+	return val
+}
+
+// runParts runs function concurrently for each partition
+func (o *femBase) runParts(fcn func(part int, cells []*msh.Cell)) {
+	if len(o.parts) == 1 {
+		fcn(0, o.parts[0])
+		return
+	}
+	wg := new(sync.WaitGroup)
+	for part, cells := range o.parts {
+		wg.Add(1)
+		go func(part int, cells []*msh.Cell) {
+			defer wg.Done()
+			fcn(part, cells)
+		}(part, cells)
+	}
+	wg.Wait()
+}
+
+// pointValues evaluates a user function at the integration points of all cells serially; thus,
+// the user function does not need to be goroutine-safe
+//  ncomp -- number of components of each value
+//  fcn   -- computes v({x}) where len(v) == ncomp
+//  Output:
+//    vals -- values at all integration points [ncells][npts][ncomp]
+func (o *femBase) pointValues(ncomp int, fcn func(v, x la.Vector)) (vals [][]la.Vector) {
+	vals = make([][]la.Vector, len(o.mesh.Cells))
+	x := la.NewVector(o.ndim)
+	for _, cells := range o.parts {
+		for _, cell := range cells {
+			ig := o.integrator(0, cell.TypeIndex)
+			G := la.NewMatrix(len(cell.V), o.ndim)
+			vals[cell.ID] = make([]la.Vector, ig.Npts)
+			for ip := 0; ip < ig.Npts; ip++ {
+				o.shapeGrads(G, x, ig, cell, ip)
+				vals[cell.ID][ip] = la.NewVector(ncomp)
+				fcn(vals[cell.ID][ip], x)
+			}
+		}
+	}
+	return
+}
+
+// computeMatrices computes the element matrices of all cells concurrently
+//  kernel -- computes the element stiffness K and mass M matrices (set to zero on input). kernel is
+//            called concurrently; thus, user functions must be evaluated before with pointValues
+//  NOTE: the Robin terms of the natural boundary conditions are added to K
+func (o *femBase) computeMatrices(kernel func(ig *msh.Integrator, cell *msh.Cell, K, M *la.Matrix)) {
+
+	// Robin conditions attached to cells
+	robin := make(map[int][]*femNaturalBc)
+	robinID := make(map[int][]int)
+	for _, bc := range o.nbcs {
+		if bc.beta == 0 {
+			continue
+		}
+		for _, b := range bc.bry {
+			robin[b.Cell.ID] = append(robin[b.Cell.ID], bc)
+			robinID[b.Cell.ID] = append(robinID[b.Cell.ID], b.LocalID)
+		}
+	}
+
+	// element matrices
+	ncells := len(o.mesh.Cells)
+	o.kmat = make([]*la.Matrix, ncells)
+	o.mmat = make([]*la.Matrix, ncells)
+	o.runParts(func(part int, cells []*msh.Cell) {
+		for _, cell := range cells {
+			n := len(cell.V) * o.ndof
+			K, M := la.NewMatrix(n, n), la.NewMatrix(n, n)
+			kernel(o.integrator(part, cell.TypeIndex), cell, K, M)
+			for k, bc := range robin[cell.ID] {
+				o.integrateBry(cell, robinID[cell.ID][k], func(S la.Vector, lverts []int, x la.Vector, w float64) {
+					for a, ma := range lverts {
+						for b, mb := range lverts {
+							K.Add(ma*o.ndof+bc.dof, mb*o.ndof+bc.dof, w*bc.beta*S[a]*S[b])
+						}
+					}
+				})
+			}
+			o.kmat[cell.ID], o.mmat[cell.ID] = K, M
+		}
+	})
+}
+
+// computeRhs computes the global right-hand-side vector F(t)
+//  kernel -- computes the element vector Fe (set to zero on input) [may be nil]. kernel is called
+//            concurrently; thus, user functions must be evaluated before with pointValues
+func (o *femBase) computeRhs(F la.Vector, t float64, kernel func(ig *msh.Integrator, cell *msh.Cell, Fe la.Vector, t float64)) {
+
+	// element contributions
+	F.Fill(0)
+	if kernel != nil {
+		fparts := make([]la.Vector, len(o.parts))
+		o.runParts(func(part int, cells []*msh.Cell) {
+			fparts[part] = la.NewVector(o.neq)
+			for _, cell := range cells {
+				Fe := la.NewVector(len(cell.V) * o.ndof)
+				kernel(o.integrator(part, cell.TypeIndex), cell, Fe, t)
+				for i, I := range o.cellEqs(cell) {
+					fparts[part][I] += Fe[i]
+				}
+			}
+		})
+		for _, fp := range fparts {
+			for I, v := range fp {
+				F[I] += v
+			}
+		}
+	}
+
+	// natural boundary conditions
+	for _, bc := range o.nbcs {
+		for _, b := range bc.bry {
+			o.integrateBry(b.Cell, b.LocalID, func(S la.Vector, lverts []int, x la.Vector, w float64) {
+				q := bc.value(t, x)
+				for a, m := range lverts {
+					F[b.Cell.V[m]*o.ndof+bc.dof] += w * q * S[a]
+				}
+			})
+		}
+	}
+}
+
+// integrateBry integrates over edge (2D) or face (3D) of cell
+//  fcn -- integrand: S are the shape functions of the edge/face with local vertices lverts
+//         (w.r.t the cell), x are the coordinates of the integration point and w = dΓ ⋅ weight
+func (o *femBase) integrateBry(cell *msh.Cell, localID int, fcn func(S la.Vector, lverts []int, x la.Vector, w float64)) {
+
+	// shape of edge or face
+	var lverts []int
+	var btype int
+	if o.ndim == 2 {
+		lverts = msh.EdgeLocalVerts[cell.TypeIndex][localID]
+		switch len(lverts) {
+		case 2:
+			btype = msh.TypeLin2
+		case 3:
+			btype = msh.TypeLin3
+		case 4:
+			btype = msh.TypeLin4
+		case 5:
+			btype = msh.TypeLin5
+		}
+	} else {
+		lverts = msh.FaceLocalVerts[cell.TypeIndex][localID]
+		switch {
+		case len(lverts) == 3:
+			btype = msh.TypeTri3
+		case len(lverts) == 6:
+			btype = msh.TypeTri6
+		case len(lverts) == 4:
+			btype = msh.TypeQua4
+		case len(lverts) == 8:
+			btype = msh.TypeQua8
+		default:
+			chk.Panic("cannot integrate over face with %d vertices\n", len(lverts))
+		}
+	}
+
+	// integration
+	nv := len(lverts)
+	gnd := msh.GeomNdim[btype]
+	S := la.NewVector(nv)
+	dSdR := la.NewMatrix(nv, gnd)
+	x := la.NewVector(o.ndim)
+	dxdr := make([]la.Vector, gnd)
+	for k := 0; k < gnd; k++ {
+		dxdr[k] = la.NewVector(o.ndim)
+	}
+	for _, p := range msh.DefaultIntPoints[btype] {
+		msh.Functions[btype](S, dSdR, p, true)
+		x.Fill(0)
+		for k := 0; k < gnd; k++ {
+			dxdr[k].Fill(0)
+		}
+		for a, m := range lverts {
+			for i := 0; i < o.ndim; i++ {
+				xi := cell.X.Get(m, i)
+				x[i] += S[a] * xi
+				for k := 0; k < gnd; k++ {
+					dxdr[k][i] += dSdR.Get(a, k) * xi
+				}
+			}
+		}
+		var dΓ float64
+		if gnd == 1 {
+			dΓ = dxdr[0].Norm()
+		} else {
+			a, b := dxdr[0], dxdr[1]
+			dΓ = math.Sqrt(math.Pow(a[1]*b[2]-a[2]*b[1], 2) + math.Pow(a[2]*b[0]-a[0]*b[2], 2) + math.Pow(a[0]*b[1]-a[1]*b[0], 2))
+		}
+		fcn(S, lverts, x, dΓ*p[3])
+	}
+}
+
+// allocEquations allocates the equations structure
+func (o *femBase) allocEquations(reactions bool) (eqs *la.Equations) {
+	eqs = la.NewEquations(o.neq, o.knownEqs())
+	cols := make([][]int, o.neq)
+	for _, cells := range o.parts {
+		for _, cell := range cells {
+			ids := o.cellEqs(cell)
+			for _, I := range ids {
+				cols[I] = append(cols[I], ids...)
+			}
+		}
+	}
+	allocEqs(eqs, cols, reactions)
+	return
+}
+
+// putMatrices puts α [K] + β [M] into equations
+func (o *femBase) putMatrices(eqs *la.Equations, α, β float64) {
+	eqs.Start()
+	for _, cells := range o.parts {
+		for _, cell := range cells {
+			K, M := o.kmat[cell.ID], o.mmat[cell.ID]
+			ids := o.cellEqs(cell)
+			for i, I := range ids {
+				for j, J := range ids {
+					eqs.Put(I, J, α*K.Get(i, j)+β*M.Get(i, j))
+				}
+			}
+		}
+	}
+}
+
+// globalMatrix returns α [K] + β [M] (full system) in triplet format
+func (o *femBase) globalMatrix(α, β float64) (A *la.Triplet) {
+	nnz := 0
+	for _, cells := range o.parts {
+		for _, cell := range cells {
+			n := len(cell.V) * o.ndof
+			nnz += n * n
+		}
+	}
+	A = la.NewTriplet(o.neq, o.neq, nnz)
+	for _, cells := range o.parts {
+		for _, cell := range cells {
+			K, M := o.kmat[cell.ID], o.mmat[cell.ID]
+			ids := o.cellEqs(cell)
+			for i, I := range ids {
+				for j, J := range ids {
+					A.Put(I, J, α*K.Get(i, j)+β*M.Get(i, j))
+				}
+			}
+		}
+	}
+	return
+}
+
+// shapeGrads computes the shape functions, their gradients G = dS/dx and the integration weight
+// w = det(J) ⋅ weight at integration point ip
+func (o *femBase) shapeGrads(G *la.Matrix, x la.Vector, ig *msh.Integrator, cell *msh.Cell, ip int) (S la.Vector, w float64) {
+	ig.EvalJacobian(cell.X, ip)
+	if ig.DetJacobian <= 0 {
+		chk.Panic("determinant of Jacobian of cell %d is non-positive (%g). check the ordering of vertices\n", cell.ID, ig.DetJacobian)
+	}
+	S = ig.ShapeFcns[ip]
+	la.MatMatMul(G, 1, ig.RefGrads[ip], ig.InvJacobMat) // G := dSdR ⋅ dRdX
+	la.MatTrVecMul(x, 1, cell.X, S)                     // x := Xᵀ ⋅ S
+	w = ig.DetJacobian * ig.P[ip][3]
+	return
+}
+
+// femSnapshots returns a function to decide whether a snapshot of a transient solution must be
+// taken at time t after a time step dt (output times are multiples of dtOut; dtOut ≤ 0 means all
+// steps)
+func femSnapshots(tf, dtOut float64) func(t, dt float64) bool {
+	tout := 0.0
+	return func(t, dt float64) bool {
+		tol := 1e-10 * dt
+		if dtOut > 0 && t < tout-tol && math.Abs(t-tf) > tol {
+			return false
+		}
+		for dtOut > 0 && tout <= t+tol {
+			tout += dtOut
+		}
+		return true
+	}
+}
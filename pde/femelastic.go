@@ -0,0 +1,297 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/la"
+)
+
+// FemElastic implements the Finite Element Method (FEM) for linear elasticity (small strains)
+// with isotropic materials (2D plane-strain, 2D plane-stress or 3D)
+//
+//    ∇ ⋅ σ + {b} = 0      with      σ = λ tr(ε) I + 2 μ ε   and   ε = (∇u + ∇uᵀ) / 2
+//
+//  where {b}({x},t) is the body force. The natural boundary conditions are the components of the
+//  traction vector t = σ ⋅ n where n is the unit outward normal. After discretisation, the system
+//  of equations is
+//
+//    [K]⋅{u} = {F}
+//
+//           ⌠                        ⌠                        ⌠
+//    Kᵃᵇ =  │ [B]ᵃᵀ ⋅ [D] ⋅ [B]ᵇ dΩ    Fᵃᵢ =  │ Nᵃ bᵢ dΩ  +  ⌠ Nᵃ tᵢ dΓ    Mᵃᵇᵢⱼ = │ ρ Nᵃ Nᵇ δᵢⱼ dΩ
+//           ⌡                        ⌡        ⌡               ⌡
+//            Ω                        Ω        Γ               Ω
+//
+//  where [D] is the elastic modulus in Voigt notation with engineering shear strains; i.e. the
+//  strain components are ordered as {εxx, εyy, γxy} in 2D and {εxx, εyy, εzz, γxy, γyz, γzx}
+//  in 3D. The stress components are ordered similarly. The degrees-of-freedom of each vertex are
+//  the displacements (ux, uy[, uz]). The element matrices are computed concurrently for each
+//  partition (Cell.Part) of the mesh. The user functions (Body and boundary conditions) are
+//  evaluated serially, outside the concurrent sections; thus, they do not need to be goroutine-safe.
+type FemElastic struct {
+	E           float64       // Young's modulus
+	Nu          float64       // Poisson's coefficient
+	Rho         float64       // density [default = 1]
+	PlaneStress bool          // 2D plane-stress; otherwise plane-strain
+	Mesh        *msh.Mesh     // mesh
+	Body        fun.Vvs       // body force function {b}({x},t) [may be nil]
+	EssenBcs    *EssentialBcs // essential boundary conditions
+	Eqs         *la.Equations // equations
+	F           la.Vector     // global right-hand-side vector {F} [full system]
+	fem         *femBase      // FEM data and algorithms
+	ready       bool          // element matrices and equations are ready
+}
+
+// NewFemElastic creates a new FEM solver for linear elasticity
+//  params -- "E" and "nu"; "rho" [optional]
+//  mesh   -- mesh
+//  body   -- body force {b}({x},t) [may be nil]
+func NewFemElastic(params dbf.Params, mesh *msh.Mesh, body fun.Vvs) (o *FemElastic) {
+	o = new(FemElastic)
+	o.Rho = 1
+	err := params.ConnectSetOpt(
+		[]*float64{&o.E, &o.Nu, &o.Rho},
+		[]string{"E", "nu", "rho"},
+		[]bool{false, false, true},
+		"FemElastic",
+	)
+	if err != "" {
+		chk.Panic(err)
+	}
+	if o.E <= 0 || o.Nu <= -1 || o.Nu >= 0.5 {
+		chk.Panic("E=%g and nu=%g are invalid. E must be positive and nu must be in (-1,0.5)\n", o.E, o.Nu)
+	}
+	o.Mesh = mesh
+	o.Body = body
+	o.fem = newFemBase(mesh, mesh.Ndim)
+	o.EssenBcs = o.fem.ebcs
+	o.F = la.NewVector(o.fem.neq)
+	return
+}
+
+// AddBc adds essential or natural boundary condition
+//  essential -- essential BC (displacement); otherwise natural BC (traction)
+//  tag       -- edge (2D) or face (3D) tag in mesh
+//  dof       -- index of component: 0 ⇒ x, 1 ⇒ y, 2 ⇒ z
+//  cvalue    -- constant value [optional]; or
+//  fvalue    -- function value [optional]
+func (o *FemElastic) AddBc(essential bool, tag, dof int, cvalue float64, fvalue dbf.T) {
+	o.ready = false
+	if essential {
+		if dof < 0 || dof >= o.fem.ndof {
+			chk.Panic("dof=%d is invalid; it must be in [0,%d]\n", dof, o.fem.ndof-1)
+		}
+		o.EssenBcs.AddUsingTag(tag, dof, cvalue, fvalue)
+		return
+	}
+	o.fem.addNaturalBc(tag, dof, 0, cvalue, fvalue)
+}
+
+// Assemble computes the element matrices and assembles [K] into the A matrix of [A]⋅{u} = {b}
+//  reactions -- prepare for computation of RHS
+//  NOTE: no user function is called during the concurrent computation of the element matrices
+func (o *FemElastic) Assemble(reactions bool) {
+	D := o.ElasticModulus()
+	nd := o.fem.ndim
+	nsig := D.M
+	o.fem.computeMatrices(func(ig *msh.Integrator, cell *msh.Cell, K, M *la.Matrix) {
+		nv := len(cell.V)
+		nu := nv * nd
+		G := la.NewMatrix(nv, nd)
+		B := la.NewMatrix(nsig, nu)
+		DB := la.NewMatrix(nsig, nu)
+		x := la.NewVector(nd)
+		for ip := 0; ip < ig.Npts; ip++ {
+			S, w := o.fem.shapeGrads(G, x, ig, cell, ip)
+			o.calcB(B, G)
+			la.MatMatMul(DB, 1, D, B)
+			la.MatTrMatMulAdd(K, w, B, DB) // K += w ⋅ Bᵀ ⋅ D ⋅ B
+			for a := 0; a < nv; a++ {
+				for b := 0; b < nv; b++ {
+					for i := 0; i < nd; i++ {
+						M.Add(a*nd+i, b*nd+i, w*o.Rho*S[a]*S[b])
+					}
+				}
+			}
+		}
+	})
+	o.Eqs = o.fem.allocEquations(reactions)
+	o.fem.putMatrices(o.Eqs, 1, 0)
+	o.ready = true
+}
+
+// SolveSteady solves the static problem
+//  Solves: [K]⋅{u} = {F} represented by [A]⋅{x} = {b}
+//  Output:
+//    u -- displacements at all vertices; u[v⋅ndim+i] is the i-component at vertex v
+//    f -- [K]⋅{u} at all vertices (i.e. {F} plus the reactions at the prescribed vertices)
+//         [only if reactions == true; the equations must be assembled with reactions == true]
+func (o *FemElastic) SolveSteady(reactions bool) (u, f []float64) {
+	if !o.ready {
+		o.Assemble(reactions)
+	}
+	o.computeRhs(0)
+	o.Eqs.SolveOnce(o.fem.calcXk, o.calcBu)
+	u = make([]float64, o.fem.neq)
+	o.Eqs.JoinVector(u, o.Eqs.Xu, o.Eqs.Xk)
+	if reactions {
+		f = make([]float64, o.fem.neq)
+		for i, I := range o.Eqs.UtoF {
+			o.Eqs.Bu[i] = o.F[I]
+		}
+		o.Eqs.JoinVector(f, o.Eqs.Bu, o.Eqs.Bk)
+	}
+	return
+}
+
+// CellStresses computes the stresses at the integration points of cell
+//  Input:
+//    u      -- displacements at all vertices
+//    cellID -- index of cell
+//  Output:
+//    X   -- [nip][ndim] coordinates of integration points
+//    Sig -- [nip][nsig] stresses at integration points; nsig = 3 (2D) or 6 (3D)
+func (o *FemElastic) CellStresses(u []float64, cellID int) (X, Sig [][]float64) {
+	cell := o.Mesh.Cells[cellID]
+	if cell.Disabled {
+		chk.Panic("cell %d is disabled\n", cellID)
+	}
+	D := o.ElasticModulus()
+	nd := o.fem.ndim
+	nsig := D.M
+	ig := msh.NewIntegrator(cell.TypeIndex, nil, "")
+	ids := o.fem.cellEqs(cell)
+	ue := la.NewVector(len(ids))
+	for i, I := range ids {
+		ue[i] = u[I]
+	}
+	G := la.NewMatrix(len(cell.V), nd)
+	B := la.NewMatrix(nsig, len(ids))
+	eps := la.NewVector(nsig)
+	X = make([][]float64, ig.Npts)
+	Sig = make([][]float64, ig.Npts)
+	for ip := 0; ip < ig.Npts; ip++ {
+		x := la.NewVector(nd)
+		o.fem.shapeGrads(G, x, ig, cell, ip)
+		o.calcB(B, G)
+		la.MatVecMul(eps, 1, B, ue)
+		sig := la.NewVector(nsig)
+		la.MatVecMul(sig, 1, D, eps)
+		X[ip], Sig[ip] = x, sig
+	}
+	return
+}
+
+// GlobalMatrices returns the global stiffness [K] and mass [M] matrices (full system)
+func (o *FemElastic) GlobalMatrices() (K, M *la.Triplet) {
+	if !o.ready {
+		o.Assemble(false)
+	}
+	return o.fem.globalMatrix(1, 0), o.fem.globalMatrix(0, 1)
+}
+
+// ElasticModulus returns the elastic modulus [D] in Voigt notation (engineering shear strains)
+func (o *FemElastic) ElasticModulus() (D *la.Matrix) {
+	return elasticModulus(o.E, o.Nu, o.fem.ndim, o.PlaneStress)
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// calcB computes the strain-displacement matrix [B] from the gradients of shape functions G
+func (o *FemElastic) calcB(B, G *la.Matrix) {
+	elasticB(B, G, o.fem.ndim)
+}
+
+// computeRhs computes {F}(t)
+func (o *FemElastic) computeRhs(t float64) {
+	if o.Body == nil {
+		o.fem.computeRhs(o.F, t, nil)
+		return
+	}
+	nd := o.fem.ndim
+	bvals := o.fem.pointValues(nd, func(v, x la.Vector) { o.Body(v, x, t) })
+	o.fem.computeRhs(o.F, t, func(ig *msh.Integrator, cell *msh.Cell, Fe la.Vector, t float64) {
+		G := la.NewMatrix(len(cell.V), nd)
+		x := la.NewVector(nd)
+		for ip := 0; ip < ig.Npts; ip++ {
+			S, w := o.fem.shapeGrads(G, x, ig, cell, ip)
+			b := bvals[cell.ID][ip]
+			for a := range cell.V {
+				for i := 0; i < nd; i++ {
+					Fe[a*nd+i] += w * b[i] * S[a]
+				}
+			}
+		}
+	})
+}
+
+// calcBu returns the RHS value at equation I (CalcBu in la.Equations)
+func (o *FemElastic) calcBu(I int, t float64) float64 {
+	return o.F[I]
+}
+
+// elasticModulus returns the elastic modulus [D] of isotropic materials in Voigt notation
+// (engineering shear strains)
+func elasticModulus(E, nu float64, ndim int, planeStress bool) (D *la.Matrix) {
+	if ndim == 2 {
+		D = la.NewMatrix(3, 3)
+		if planeStress {
+			c := E / (1 - nu*nu)
+			D.Set(0, 0, c)
+			D.Set(0, 1, c*nu)
+			D.Set(1, 0, c*nu)
+			D.Set(1, 1, c)
+			D.Set(2, 2, c*(1-nu)/2)
+			return
+		}
+		c := E / ((1 + nu) * (1 - 2*nu))
+		D.Set(0, 0, c*(1-nu))
+		D.Set(0, 1, c*nu)
+		D.Set(1, 0, c*nu)
+		D.Set(1, 1, c*(1-nu))
+		D.Set(2, 2, c*(1-2*nu)/2)
+		return
+	}
+	λ := E * nu / ((1 + nu) * (1 - 2*nu))
+	μ := E / (2 * (1 + nu))
+	D = la.NewMatrix(6, 6)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			D.Set(i, j, λ)
+		}
+		D.Set(i, i, λ+2*μ)
+		D.Set(3+i, 3+i, μ)
+	}
+	return
+}
+
+// elasticB computes the strain-displacement matrix [B] from the gradients of shape functions G
+func elasticB(B, G *la.Matrix, ndim int) {
+	B.Fill(0)
+	for a := 0; a < G.M; a++ {
+		if ndim == 2 {
+			c := a * 2
+			B.Set(0, c+0, G.Get(a, 0))
+			B.Set(1, c+1, G.Get(a, 1))
+			B.Set(2, c+0, G.Get(a, 1))
+			B.Set(2, c+1, G.Get(a, 0))
+			continue
+		}
+		c := a * 3
+		B.Set(0, c+0, G.Get(a, 0))
+		B.Set(1, c+1, G.Get(a, 1))
+		B.Set(2, c+2, G.Get(a, 2))
+		B.Set(3, c+0, G.Get(a, 1))
+		B.Set(3, c+1, G.Get(a, 0))
+		B.Set(4, c+1, G.Get(a, 2))
+		B.Set(4, c+2, G.Get(a, 1))
+		B.Set(5, c+0, G.Get(a, 2))
+		B.Set(5, c+2, G.Get(a, 0))
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/la"
+)
+
+// FemPoisson implements the Finite Element Method (FEM) for the scalar Poisson (steady) and
+// diffusion/heat (transient) equations (2D or 3D)
+//
+//      ∂u
+//    ρ ——— = ∇ ⋅ (k K ∇u) - s({x},t)      with      K = diag(kx, ky, kz)
+//      ∂t
+//
+//  where k = k({x}) is an optional variable coefficient (Kvar) and ρ is the capacity coefficient
+//  (e.g. ρ cp). The steady problem is ∇ ⋅ (k K ∇u) = s as in FdmLaplacian. The natural boundary
+//  conditions are
+//
+//    n ⋅ (k K ∇u) + β u = q
+//
+//  where n is the unit outward normal. Boundaries without prescribed conditions are insulated.
+//  After discretisation, the system of equations is
+//
+//    [M]⋅d{u}/dt + [K]⋅{u} = {F}(t)
+//
+//           ⌠                                    ⌠                 ⌠
+//    Kᵃᵇ =  │ ∇Nᵃ ⋅ k K ⋅ ∇Nᵇ dΩ  +  ⌠ β Nᵃ Nᵇ dΓ     Mᵃᵇ =  │ ρ Nᵃ Nᵇ dΩ      Fᵃ = -│ Nᵃ s dΩ + ⌠ Nᵃ q dΓ
+//           ⌡                     ⌡                   ⌡                 ⌡          ⌡
+//            Ω                     Γ                   Ω                 Ω          Γ
+//
+//  The element matrices are computed concurrently for each partition (Cell.Part) of the mesh. The
+//  user functions (Kvar, Source and boundary conditions) are evaluated serially, outside the
+//  concurrent sections; thus, they do not need to be goroutine-safe.
+type FemPoisson struct {
+	Kx       float64       // isotropic coefficient x
+	Ky       float64       // isotropic coefficient y
+	Kz       float64       // isotropic coefficient z
+	Rho      float64       // capacity coefficient ρ for transient analyses [default = 1]
+	Kvar     dbf.T         // variable coefficient k({x}) [optional; evaluated at t=0]
+	Mesh     *msh.Mesh     // mesh
+	Source   fun.Svs       // source term function s({x},t) [may be nil]
+	EssenBcs *EssentialBcs // essential boundary conditions
+	Eqs      *la.Equations // equations
+	F        la.Vector     // global right-hand-side vector {F}(t) [full system]
+	fem      *femBase      // FEM data and algorithms
+	ready    bool          // element matrices and equations are ready
+}
+
+// NewFemPoisson creates a new FEM solver for the Poisson/heat equations
+//  params -- "kx", "ky" and "kz" (3D); "rho" [optional]
+//  mesh   -- mesh
+//  source -- source term s({x},t) [may be nil]
+func NewFemPoisson(params dbf.Params, mesh *msh.Mesh, source fun.Svs) (o *FemPoisson) {
+	o = new(FemPoisson)
+	o.Rho = 1
+	err := params.ConnectSetOpt(
+		[]*float64{&o.Kx, &o.Ky, &o.Kz, &o.Rho},
+		[]string{"kx", "ky", "kz", "rho"},
+		[]bool{false, false, mesh.Ndim == 2, true},
+		"FemPoisson",
+	)
+	if err != "" {
+		chk.Panic(err)
+	}
+	o.Mesh = mesh
+	o.Source = source
+	o.fem = newFemBase(mesh, 1)
+	o.EssenBcs = o.fem.ebcs
+	o.F = la.NewVector(o.fem.neq)
+	return
+}
+
+// AddBc adds essential or natural boundary condition
+//  essential -- essential BC; otherwise natural (flux) boundary condition: n ⋅ (k K ∇u) = q
+//  tag       -- edge (2D) or face (3D) tag in mesh
+//  cvalue    -- constant value [optional]; or
+//  fvalue    -- function value [optional]
+func (o *FemPoisson) AddBc(essential bool, tag int, cvalue float64, fvalue dbf.T) {
+	o.ready = false
+	if essential {
+		o.EssenBcs.AddUsingTag(tag, 0, cvalue, fvalue)
+		return
+	}
+	o.fem.addNaturalBc(tag, 0, 0, cvalue, fvalue)
+}
+
+// AddRobinBc adds natural boundary condition of Robin type: n ⋅ (k K ∇u) + β u = q
+//  tag    -- edge (2D) or face (3D) tag in mesh
+//  beta   -- coefficient β; e.g. heat transfer coefficient of convection conditions
+//  cvalue -- constant value of q [optional]; or
+//  fvalue -- function q(t,{x}) [optional]
+func (o *FemPoisson) AddRobinBc(tag int, beta, cvalue float64, fvalue dbf.T) {
+	o.ready = false
+	o.fem.addNaturalBc(tag, 0, beta, cvalue, fvalue)
+}
+
+// Assemble computes the element matrices and assembles [K] into the A matrix of [A]⋅{u} = {b}
+//  reactions -- prepare for computation of RHS
+//  NOTE: Kvar is evaluated serially at all integration points before the concurrent computation
+//        of the element matrices
+func (o *FemPoisson) Assemble(reactions bool) {
+	kk := []float64{o.Kx, o.Ky, o.Kz}
+	nd := o.fem.ndim
+	var kvals [][]la.Vector
+	if o.Kvar != nil {
+		kvals = o.fem.pointValues(1, func(v, x la.Vector) { v[0] = o.Kvar.F(0, x) })
+	}
+	o.fem.computeMatrices(func(ig *msh.Integrator, cell *msh.Cell, K, M *la.Matrix) {
+		nv := len(cell.V)
+		G := la.NewMatrix(nv, nd)
+		x := la.NewVector(nd)
+		for ip := 0; ip < ig.Npts; ip++ {
+			S, w := o.fem.shapeGrads(G, x, ig, cell, ip)
+			k := 1.0
+			if kvals != nil {
+				k = kvals[cell.ID][ip][0]
+			}
+			for a := 0; a < nv; a++ {
+				for b := 0; b < nv; b++ {
+					var gkg float64
+					for i := 0; i < nd; i++ {
+						gkg += G.Get(a, i) * kk[i] * G.Get(b, i)
+					}
+					K.Add(a, b, w*k*gkg)
+					M.Add(a, b, w*o.Rho*S[a]*S[b])
+				}
+			}
+		}
+	})
+	o.Eqs = o.fem.allocEquations(reactions)
+	o.fem.putMatrices(o.Eqs, 1, 0)
+	o.ready = true
+}
+
+// SolveSteady solves steady problem
+//  Solves: [K]⋅{u} = {F} represented by [A]⋅{x} = {b}
+//  Output:
+//    u -- values of u at all vertices
+//    f -- [K]⋅{u} at all vertices (i.e. {F} plus the "reactions" at the prescribed vertices)
+//         [only if reactions == true; the equations must be assembled with reactions == true]
+func (o *FemPoisson) SolveSteady(reactions bool) (u, f []float64) {
+	if !o.ready {
+		o.Assemble(reactions)
+	}
+	o.computeRhs(0)
+	o.Eqs.SolveOnce(o.fem.calcXk, o.calcBu)
+	u = make([]float64, o.fem.neq)
+	o.Eqs.JoinVector(u, o.Eqs.Xu, o.Eqs.Xk)
+	if reactions {
+		f = make([]float64, o.fem.neq)
+		for i, I := range o.Eqs.UtoF {
+			o.Eqs.Bu[i] = o.F[I]
+		}
+		o.Eqs.JoinVector(f, o.Eqs.Bu, o.Eqs.Bk)
+	}
+	return
+}
+
+// SolveTransient solves the transient problem from t = 0 to tf using the θ-method
+//
+//    ([M]/Δt + θ [K])⋅{u}ⁿ⁺¹ = ([M]/Δt - (1-θ) [K])⋅{u}ⁿ + θ {F}ⁿ⁺¹ + (1-θ) {F}ⁿ
+//
+//  INPUT:
+//    u     -- initial values of u at all vertices (the values at the vertices with essential
+//             conditions are replaced by the prescribed values)
+//    tf    -- final time
+//    dt    -- time step
+//    theta -- θ ∈ (0,1]; e.g. 1 ⇒ backward Euler; 0.5 ⇒ Crank-Nicolson
+//    dtOut -- time increment for snapshots [0 ⇒ snapshots at all steps]
+//    out   -- callback function to process snapshots [may be nil]. v is nil
+//
+//  OUTPUT:
+//    u -- final values of u at all vertices
+//
+//  NOTE: the A matrix in Eqs holds [M]/Δt + θ [K] afterwards; thus, SolveSteady re-assembles [K]
+func (o *FemPoisson) SolveTransient(u []float64, tf, dt, theta, dtOut float64, out MolOutF) {
+
+	// check
+	if tf <= 0 || dt <= 0 {
+		chk.Panic("final time and time step must be positive. tf=%g and dt=%g are invalid\n", tf, dt)
+	}
+	if theta <= 0 || theta > 1 {
+		chk.Panic("θ must be in (0,1]. θ=%g is invalid\n", theta)
+	}
+	if !o.ready {
+		o.Assemble(false)
+	}
+
+	// matrices
+	nsteps := int(tf/dt + 0.5)
+	if nsteps < 1 {
+		nsteps = 1
+	}
+	dt = tf / float64(nsteps)
+	kmat := o.fem.globalMatrix(1, 0).ToMatrix(nil)
+	mmat := o.fem.globalMatrix(0, 1).ToMatrix(nil)
+	o.fem.putMatrices(o.Eqs, theta, 1/dt)
+	o.ready = false // Eqs no longer holds [K]
+	solver := la.NewSparseSolver("umfpack")
+	defer solver.Free()
+	solver.Init(o.Eqs.Auu, false, false, "", "", nil)
+	solver.Fact()
+
+	// initial state
+	t := 0.0
+	for _, I := range o.Eqs.KtoF {
+		u[I] = o.fem.calcXk(I, t)
+	}
+	snap := femSnapshots(tf, dtOut)
+	if out != nil {
+		snap(t, dt)
+		if out(0, t, u, nil) {
+			return
+		}
+	}
+
+	// time loop
+	o.computeRhs(t)
+	b := la.NewVector(o.fem.neq)
+	fold := la.NewVector(o.fem.neq)
+	idx := 1
+	for n := 0; n < nsteps; n++ {
+		copy(fold, o.F)
+		la.SpMatVecMul(b, 1/dt, mmat, u)
+		la.SpMatVecMulAdd(b, theta-1, kmat, u)
+		t = float64(n+1) * dt
+		o.computeRhs(t)
+		for I := range b {
+			b[I] += theta*o.F[I] + (1-theta)*fold[I]
+		}
+		o.Eqs.Solve(solver, t, o.fem.calcXk, func(I int, t float64) float64 { return b[I] })
+		o.Eqs.JoinVector(u, o.Eqs.Xu, o.Eqs.Xk)
+		if out != nil && snap(t, dt) {
+			if out(idx, t, u, nil) {
+				return
+			}
+			idx++
+		}
+	}
+}
+
+// GlobalMatrices returns the global "stiffness" [K] and "mass" [M] matrices (full system)
+func (o *FemPoisson) GlobalMatrices() (K, M *la.Triplet) {
+	if !o.ready {
+		o.Assemble(false)
+	}
+	return o.fem.globalMatrix(1, 0), o.fem.globalMatrix(0, 1)
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// computeRhs computes {F}(t)
+func (o *FemPoisson) computeRhs(t float64) {
+	if o.Source == nil {
+		o.fem.computeRhs(o.F, t, nil)
+		return
+	}
+	nd := o.fem.ndim
+	svals := o.fem.pointValues(1, func(v, x la.Vector) { v[0] = o.Source(x, t) })
+	o.fem.computeRhs(o.F, t, func(ig *msh.Integrator, cell *msh.Cell, Fe la.Vector, t float64) {
+		G := la.NewMatrix(len(cell.V), nd)
+		x := la.NewVector(nd)
+		for ip := 0; ip < ig.Npts; ip++ {
+			S, w := o.fem.shapeGrads(G, x, ig, cell, ip)
+			s := svals[cell.ID][ip][0]
+			for a := range cell.V {
+				Fe[a] -= w * s * S[a]
+			}
+		}
+	})
+}
+
+// calcBu returns the RHS value at equation I (CalcBu in la.Equations)
+func (o *FemPoisson) calcBu(I int, t float64) float64 {
+	return o.F[I]
+}
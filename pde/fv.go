@@ -0,0 +1,700 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/utl"
+)
+
+// FvModel defines the physics of (2D) hyperbolic systems of conservation laws
+//
+//    ∂u
+//    ——— + ∇ ⋅ F(u) = 0
+//    ∂t
+//
+//  where u is the vector of conserved variables and F = [Fx Fy] holds the physical fluxes
+type FvModel interface {
+	Nvars() int                                 // number of conserved variables
+	Flux(f, u, n la.Vector)                     // normal flux f = F(u) ⋅ n
+	Speeds(u, n la.Vector) (smin, smax float64) // min and max wave speeds along the unit normal n
+	RoeDissipation(d, ul, ur, n la.Vector)      // d = |A(ũ)| ⋅ (ur - ul) where A(ũ) is the Roe matrix along n
+	Wall(ug, u, n la.Vector)                    // ghost state ug at a slip (reflective) wall with unit normal n
+}
+
+// FvOutF defines a callback function to process snapshots of finite volume solutions
+//  INPUT:
+//    idx -- index of snapshot (0 corresponds to the initial state)
+//    t   -- time
+//    u   -- [ncells][nvars] cell averages of the conserved variables
+//
+//  OUTPUT:
+//    stop -- stop simulation (nicely)
+//
+//  NOTE: u is overwritten after this function returns; thus, copy it if needed
+type FvOutF func(idx int, t float64, u [][]float64) (stop bool)
+
+// FiniteVolume implements a cell-centred finite volume solver for (2D) hyperbolic systems of
+// conservation laws on structured (gm.Grid) or unstructured (msh.Mesh) meshes of polygons
+//
+//  The semi-discrete equations of cell c are
+//
+//    d{uc}       1
+//    ————— = - ————— Σ  F̂(uL, uR, n) |f|
+//     dt       |Ωc|  f
+//
+//  where the numerical flux F̂ is given by an approximate Riemann solver (Rusanov, HLL or Roe)
+//  and uL and uR are the states on both sides of face f with unit normal n and length |f|. The
+//  second-order accuracy in space is achieved with the MUSCL reconstruction: the gradients are
+//  computed by least squares and the upwind-extrapolated differences are limited (minmod, van
+//  Leer or superbee). The time integration is carried out with the strong stability preserving
+//  Runge-Kutta (SSP-RK) methods and the time step is given by the CFL condition.
+//
+//  The boundary conditions are imposed via ghost states. The kinds are:
+//    "outflow" -- transmissive (zero gradient) boundary [default]
+//    "wall"    -- slip (reflective) wall
+//    "inflow"  -- prescribed state
+//
+//  The boundary tags of grids are 10 (x=xmin), 11 (x=xmax), 20 (y=ymin) and 21 (y=ymax); the
+//  boundary tags of meshes are the edge tags of cells.
+type FiniteVolume struct {
+	Model   FvModel // physics
+	Limiter string  // slope limiter: "none" (first-order), "minmod", "vanleer" or "superbee" [default = "minmod"]
+	Riemann string  // Riemann solver: "rusanov", "hll" or "roe" [default = "hll"]
+	Stages  int     // number of stages of the SSP-RK method: 1, 2 or 3 [default = 3]
+	Cfl     float64 // Courant number [default = 0.4]
+	Nsteps  int     // number of time steps of the last run
+
+	// derived
+	nvars int                        // number of conserved variables
+	cells []*fvCell                  // cells
+	faces []*fvFace                  // faces
+	bcs   map[int]*fvBc              // boundary conditions
+	psi   func(a, b float64) float64 // limiter function [nil ⇒ first-order]
+
+	// workspaces
+	gx    [][]float64 // [ncells][nvars] x-component of gradients
+	gy    [][]float64 // [ncells][nvars] y-component of gradients
+	ghost [][]float64 // [nfaces][nvars] ghost states computed from cell averages (boundary faces only)
+	res   [][]float64 // [ncells][nvars] rates
+	u1    [][]float64 // [ncells][nvars] first stage
+	u2    [][]float64 // [ncells][nvars] second stage
+	ul    la.Vector   // [nvars] state on the left of face
+	ur    la.Vector   // [nvars] state on the right of face
+	fl    la.Vector   // [nvars] flux of ul
+	fr    la.Vector   // [nvars] flux of ur
+	fd    la.Vector   // [nvars] Roe dissipation
+	fn    la.Vector   // [nvars] numerical flux
+}
+
+// fvCell holds the geometry of a cell
+type fvCell struct {
+	x   la.Vector  // centroid
+	vol float64    // area
+	gi  [3]float64 // inverse of the least squares matrix {Gxx, Gxy, Gyy}
+}
+
+// fvFace holds the geometry of a face
+type fvFace struct {
+	l    int       // cell on the left
+	r    int       // cell on the right (the normal points from l to r) [-1 ⇒ boundary]
+	tag  int       // boundary tag
+	area float64   // length
+	n    la.Vector // unit normal
+	d    la.Vector // distance vector from centroid of l to centroid of r (mirrored centroid of l at boundaries)
+	al   float64   // extrapolation factor of l: (xf - xl) ⋅ d / d ⋅ d
+	ar   float64   // extrapolation factor of r: (xr - xf) ⋅ d / d ⋅ d
+}
+
+// fvBc holds the data of a boundary condition
+type fvBc struct {
+	kind  string    // "outflow", "wall" or "inflow"
+	state la.Vector // prescribed state
+}
+
+// NewFvGrid returns a new finite volume solver on a 2D structured grid
+//  model    -- physics
+//  grid     -- 2D grid; the cells are the quadrilaterals between grid lines
+//  periodic -- [2] periodic directions [may be nil]
+//
+//  NOTE: the cells are numbered with c = i + j ⋅ (nx-1) where nx is the number of points along x
+func NewFvGrid(model FvModel, grid *gm.Grid, periodic []bool) (o *FiniteVolume) {
+	if grid.Ndim() != 2 {
+		chk.Panic("FiniteVolume works with 2D grids only. ndim=%d is invalid\n", grid.Ndim())
+	}
+	o = newFiniteVolume(model)
+	nx, ny := grid.Npts(0)-1, grid.Npts(1)-1
+	pt := func(m, n int) la.Vector { return grid.X(m, n, 0) }
+	cid := func(i, j int) int { return i + j*nx }
+	o.cells = make([]*fvCell, nx*ny)
+	for j := 0; j < ny; j++ {
+		for i := 0; i < nx; i++ {
+			o.cells[cid(i, j)] = newFvCell([]la.Vector{pt(i, j), pt(i+1, j), pt(i+1, j+1), pt(i, j+1)})
+		}
+	}
+	perx := len(periodic) > 0 && periodic[0]
+	pery := len(periodic) > 1 && periodic[1]
+
+	// faces normal to x
+	for j := 0; j < ny; j++ {
+		for i := 0; i <= nx; i++ {
+			a, b := pt(i, j), pt(i, j+1)
+			switch {
+			case i == 0:
+				if !perx {
+					o.addFace(cid(0, j), -1, 10, a, b, nil)
+				}
+			case i == nx:
+				if perx {
+					o.addFace(cid(nx-1, j), cid(0, j), 0, a, b, fvMidPoint(pt(0, j), pt(0, j+1)))
+				} else {
+					o.addFace(cid(nx-1, j), -1, 11, a, b, nil)
+				}
+			default:
+				o.addFace(cid(i-1, j), cid(i, j), 0, a, b, nil)
+			}
+		}
+	}
+
+	// faces normal to y
+	for i := 0; i < nx; i++ {
+		for j := 0; j <= ny; j++ {
+			a, b := pt(i, j), pt(i+1, j)
+			switch {
+			case j == 0:
+				if !pery {
+					o.addFace(cid(i, 0), -1, 20, a, b, nil)
+				}
+			case j == ny:
+				if pery {
+					o.addFace(cid(i, ny-1), cid(i, 0), 0, a, b, fvMidPoint(pt(i, 0), pt(i+1, 0)))
+				} else {
+					o.addFace(cid(i, ny-1), -1, 21, a, b, nil)
+				}
+			default:
+				o.addFace(cid(i, j-1), cid(i, j), 0, a, b, nil)
+			}
+		}
+	}
+	o.init()
+	return
+}
+
+// NewFvMesh returns a new finite volume solver on a 2D unstructured mesh
+//  model -- physics
+//  mesh  -- 2D mesh; the cells are the polygons defined by the corners of (any) 2D shape
+//
+//  NOTE: the faces are the edges found by msh.ExtractEdges
+func NewFvMesh(model FvModel, mesh *msh.Mesh) (o *FiniteVolume) {
+	if mesh.Ndim != 2 {
+		chk.Panic("FiniteVolume works with 2D meshes only. ndim=%d is invalid\n", mesh.Ndim)
+	}
+	o = newFiniteVolume(model)
+	o.cells = make([]*fvCell, len(mesh.Cells))
+	for _, cell := range mesh.Cells {
+		if cell.Gndim != 2 {
+			chk.Panic("cell %d of type %q is not 2D\n", cell.ID, cell.TypeKey)
+		}
+		lverts := msh.EdgeLocalVerts[cell.TypeIndex]
+		corners := make([]la.Vector, len(lverts))
+		for i, lv := range lverts {
+			corners[i] = mesh.Verts[cell.V[lv[0]]].X
+		}
+		o.cells[cell.ID] = newFvCell(corners)
+	}
+
+	// faces (sorted to make results reproducible)
+	edges := mesh.ExtractEdges()
+	keys := make([]msh.EdgeKey, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.A != b.A {
+			return a.A < b.A
+		}
+		if a.B != b.B {
+			return a.B < b.B
+		}
+		return a.C < b.C
+	})
+	for _, key := range keys {
+		edge := edges[key]
+		a, b := edge.Verts[0].X, edge.Verts[1].X
+		bd := edge.Bdata[0]
+		if len(edge.Bdata) > 1 {
+			o.addFace(bd.Cell.ID, edge.Bdata[1].Cell.ID, 0, a, b, nil)
+			continue
+		}
+		tag := 0
+		if bd.LocalID < len(bd.Cell.EdgeTags) {
+			tag = bd.Cell.EdgeTags[bd.LocalID]
+		}
+		o.addFace(bd.Cell.ID, -1, tag, a, b, nil)
+	}
+	o.init()
+	return
+}
+
+// AddBc adds boundary condition
+//  tag   -- boundary tag
+//  kind  -- "outflow", "wall" or "inflow"
+//  state -- [nvars] prescribed state ("inflow" only) [may be nil otherwise]
+func (o *FiniteVolume) AddBc(tag int, kind string, state []float64) {
+	switch kind {
+	case "outflow", "wall":
+	case "inflow":
+		if len(state) != o.nvars {
+			chk.Panic("inflow condition requires a state with %d values. %d is invalid\n", o.nvars, len(state))
+		}
+	default:
+		chk.Panic("cannot find boundary condition kind %q. options are \"outflow\", \"wall\" and \"inflow\"\n", kind)
+	}
+	o.bcs[tag] = &fvBc{kind, la.Vector(state).GetCopy()}
+}
+
+// Ncells returns the number of cells
+func (o *FiniteVolume) Ncells() int {
+	return len(o.cells)
+}
+
+// Center returns the centroid of cell c
+func (o *FiniteVolume) Center(c int) la.Vector {
+	return o.cells[c].x
+}
+
+// Volume returns the area of cell c
+func (o *FiniteVolume) Volume(c int) float64 {
+	return o.cells[c].vol
+}
+
+// Alloc allocates the array of cell averages
+//  u -- [ncells][nvars]
+func (o *FiniteVolume) Alloc() (u [][]float64) {
+	return utl.Alloc(len(o.cells), o.nvars)
+}
+
+// Integral computes the integral of the conserved variables over the domain: Σ uc |Ωc|
+//  u -- [ncells][nvars] cell averages
+func (o *FiniteVolume) Integral(u [][]float64) (res la.Vector) {
+	res = la.NewVector(o.nvars)
+	for c, cell := range o.cells {
+		for k := 0; k < o.nvars; k++ {
+			res[k] += u[c][k] * cell.vol
+		}
+	}
+	return
+}
+
+// StableDt returns the time step given by the CFL condition
+//
+//                           2 |Ωc|
+//    Δt = Cfl ⋅ min  ———————————————————
+//                c    Σ  max(|s|) |f|
+//                     f
+//
+//  where s are the wave speeds of the cell averages on both sides of face f
+func (o *FiniteVolume) StableDt(u [][]float64) (dt float64) {
+	sig := make([]float64, len(o.cells))
+	for _, f := range o.faces {
+		s := o.maxSpeed(u[f.l], f.n)
+		if f.r >= 0 {
+			s = math.Max(s, o.maxSpeed(u[f.r], f.n))
+			sig[f.r] += s * f.area
+		}
+		sig[f.l] += s * f.area
+	}
+	dt = math.Inf(1)
+	for c, cell := range o.cells {
+		if sig[c] > 0 {
+			dt = math.Min(dt, 2*cell.vol/sig[c])
+		}
+	}
+	return o.Cfl * dt
+}
+
+// Rates computes the rates of change of the cell averages (right-hand side of the semi-discrete
+// equations)
+//  r -- [ncells][nvars] d{uc}/dt
+//  u -- [ncells][nvars] cell averages
+func (o *FiniteVolume) Rates(r, u [][]float64) {
+
+	// check
+	o.check()
+
+	// ghost states
+	for i, f := range o.faces {
+		if f.r < 0 {
+			o.bry(o.ghost[i], u[f.l], f)
+		}
+	}
+
+	// gradients
+	if o.psi != nil {
+		o.gradients(u)
+	}
+
+	// fluxes
+	for c := range r {
+		for k := 0; k < o.nvars; k++ {
+			r[c][k] = 0
+		}
+	}
+	for i, f := range o.faces {
+		ua := u[f.l]
+		ub := o.ghost[i]
+		if f.r >= 0 {
+			ub = u[f.r]
+		}
+		for k := 0; k < o.nvars; k++ {
+			o.ul[k], o.ur[k] = ua[k], ub[k]
+			if o.psi != nil {
+				δ := ub[k] - ua[k]
+				o.ul[k] += f.al * o.psi(2*(o.gx[f.l][k]*f.d[0]+o.gy[f.l][k]*f.d[1])-δ, δ)
+				if f.r >= 0 {
+					o.ur[k] -= f.ar * o.psi(2*(o.gx[f.r][k]*f.d[0]+o.gy[f.r][k]*f.d[1])-δ, δ)
+				}
+			}
+		}
+		if f.r < 0 && o.psi != nil {
+			o.bry(o.ur, o.ul, f)
+		}
+		o.numFlux(o.ul, o.ur, f.n)
+		for k := 0; k < o.nvars; k++ {
+			r[f.l][k] -= o.fn[k] * f.area / o.cells[f.l].vol
+			if f.r >= 0 {
+				r[f.r][k] += o.fn[k] * f.area / o.cells[f.r].vol
+			}
+		}
+	}
+}
+
+// Solve solves the transient problem from t = 0 to tf
+//  u     -- [ncells][nvars] initial cell averages; will hold the final values
+//  tf    -- final time
+//  dt    -- fixed time step [use ≤ 0 for the time step given by the CFL condition; see StableDt]
+//  dtOut -- time step for output [use ≤ 0 to output all steps]
+//  out   -- callback to process snapshots [may be nil]
+func (o *FiniteVolume) Solve(u [][]float64, tf, dt, dtOut float64, out FvOutF) {
+
+	// check
+	if tf <= 0 {
+		chk.Panic("final time must be positive. tf = %g is invalid\n", tf)
+	}
+	if len(u) != len(o.cells) {
+		chk.Panic("the array of cell averages must have %d rows. %d is invalid\n", len(o.cells), len(u))
+	}
+	o.check()
+
+	// initial state
+	o.Nsteps = 0
+	idx := 0
+	if out != nil {
+		if out(idx, 0, u) {
+			return
+		}
+		idx++
+	}
+
+	// time loop
+	t, tout := 0.0, dtOut
+	for t < tf {
+		h := dt
+		if h <= 0 {
+			h = o.StableDt(u)
+		}
+		tnext := tf
+		if dtOut > 0 && tout < tf {
+			tnext = tout
+		}
+		landed := false
+		if t+h >= tnext-1e-10*h {
+			h, landed = tnext-t, true
+		}
+		o.step(u, h)
+		o.Nsteps++
+		if landed {
+			t = tnext
+			if tnext == tout {
+				tout += dtOut
+			}
+		} else {
+			t += h
+		}
+		if out != nil && (dtOut <= 0 || landed) {
+			if out(idx, t, u) {
+				return
+			}
+			idx++
+		}
+	}
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// newFiniteVolume allocates a new solver with default configuration
+func newFiniteVolume(model FvModel) (o *FiniteVolume) {
+	o = new(FiniteVolume)
+	o.Model = model
+	o.Limiter = "minmod"
+	o.Riemann = "hll"
+	o.Stages = 3
+	o.Cfl = 0.4
+	o.nvars = model.Nvars()
+	o.bcs = make(map[int]*fvBc)
+	return
+}
+
+// newFvCell computes the area and centroid of a polygon
+func newFvCell(corners []la.Vector) (o *fvCell) {
+	o = &fvCell{x: la.NewVector(2)}
+	n := len(corners)
+	area := 0.0
+	for i := 0; i < n; i++ {
+		a, b := corners[i], corners[(i+1)%n]
+		cross := a[0]*b[1] - b[0]*a[1]
+		area += cross / 2
+		o.x[0] += (a[0] + b[0]) * cross
+		o.x[1] += (a[1] + b[1]) * cross
+	}
+	if math.Abs(area) < 1e-15 {
+		chk.Panic("area of cell is zero\n")
+	}
+	o.x[0] /= 6 * area
+	o.x[1] /= 6 * area
+	o.vol = math.Abs(area)
+	return
+}
+
+// fvMidPoint returns the mid point of a segment
+func fvMidPoint(a, b la.Vector) la.Vector {
+	return []float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
+
+// addFace adds a new face with vertices a and b
+//  xfr -- mid point of face as seen from r (periodic boundaries) [may be nil]
+func (o *FiniteVolume) addFace(l, r, tag int, a, b, xfr la.Vector) {
+	f := &fvFace{l: l, r: r, tag: tag, n: la.NewVector(2), d: la.NewVector(2)}
+	xf := fvMidPoint(a, b)
+	f.area = math.Sqrt((b[0]-a[0])*(b[0]-a[0]) + (b[1]-a[1])*(b[1]-a[1]))
+	f.n[0], f.n[1] = (b[1]-a[1])/f.area, (a[0]-b[0])/f.area
+	xl := o.cells[l].x
+	rl := []float64{xf[0] - xl[0], xf[1] - xl[1]}
+	if f.n[0]*rl[0]+f.n[1]*rl[1] < 0 {
+		f.n[0], f.n[1] = -f.n[0], -f.n[1]
+	}
+	rr := []float64{0, 0}
+	if r < 0 {
+		dn := 2 * (f.n[0]*rl[0] + f.n[1]*rl[1])
+		f.d[0], f.d[1] = dn*f.n[0], dn*f.n[1]
+	} else {
+		if xfr == nil {
+			xfr = xf
+		}
+		xr := o.cells[r].x
+		rr[0], rr[1] = xr[0]-xfr[0], xr[1]-xfr[1]
+		f.d[0], f.d[1] = rl[0]+rr[0], rl[1]+rr[1]
+	}
+	dd := f.d[0]*f.d[0] + f.d[1]*f.d[1]
+	f.al = (rl[0]*f.d[0] + rl[1]*f.d[1]) / dd
+	f.ar = (rr[0]*f.d[0] + rr[1]*f.d[1]) / dd
+	o.faces = append(o.faces, f)
+}
+
+// init computes the least squares matrices and allocates workspaces
+func (o *FiniteVolume) init() {
+	for _, f := range o.faces {
+		cells := []*fvCell{o.cells[f.l]}
+		if f.r >= 0 {
+			cells = append(cells, o.cells[f.r])
+		}
+		for _, cell := range cells {
+			cell.gi[0] += f.d[0] * f.d[0]
+			cell.gi[1] += f.d[0] * f.d[1]
+			cell.gi[2] += f.d[1] * f.d[1]
+		}
+	}
+	for c, cell := range o.cells {
+		det := cell.gi[0]*cell.gi[2] - cell.gi[1]*cell.gi[1]
+		if det < 1e-14*(cell.gi[0]+cell.gi[2])*(cell.gi[0]+cell.gi[2]) {
+			chk.Panic("least squares matrix of cell %d is singular\n", c)
+		}
+		cell.gi[0], cell.gi[1], cell.gi[2] = cell.gi[2]/det, -cell.gi[1]/det, cell.gi[0]/det
+	}
+	nc := len(o.cells)
+	o.gx = utl.Alloc(nc, o.nvars)
+	o.gy = utl.Alloc(nc, o.nvars)
+	o.ghost = utl.Alloc(len(o.faces), o.nvars)
+	o.res = utl.Alloc(nc, o.nvars)
+	o.u1 = utl.Alloc(nc, o.nvars)
+	o.u2 = utl.Alloc(nc, o.nvars)
+	o.ul = la.NewVector(o.nvars)
+	o.ur = la.NewVector(o.nvars)
+	o.fl = la.NewVector(o.nvars)
+	o.fr = la.NewVector(o.nvars)
+	o.fd = la.NewVector(o.nvars)
+	o.fn = la.NewVector(o.nvars)
+}
+
+// check checks the configuration and sets the limiter function
+func (o *FiniteVolume) check() {
+	switch o.Limiter {
+	case "none":
+		o.psi = nil
+	case "minmod":
+		o.psi = func(a, b float64) float64 {
+			if a*b <= 0 {
+				return 0
+			}
+			if math.Abs(a) < math.Abs(b) {
+				return a
+			}
+			return b
+		}
+	case "vanleer":
+		o.psi = func(a, b float64) float64 {
+			if a*b <= 0 {
+				return 0
+			}
+			return 2 * a * b / (a + b)
+		}
+	case "superbee":
+		o.psi = func(a, b float64) float64 {
+			if a*b <= 0 {
+				return 0
+			}
+			s := math.Copysign(1, a)
+			a, b = math.Abs(a), math.Abs(b)
+			return s * math.Max(math.Min(2*a, b), math.Min(a, 2*b))
+		}
+	default:
+		chk.Panic("cannot find limiter %q. options are \"none\", \"minmod\", \"vanleer\" and \"superbee\"\n", o.Limiter)
+	}
+	switch o.Riemann {
+	case "rusanov", "hll", "roe":
+	default:
+		chk.Panic("cannot find Riemann solver %q. options are \"rusanov\", \"hll\" and \"roe\"\n", o.Riemann)
+	}
+	if o.Stages < 1 || o.Stages > 3 {
+		chk.Panic("number of stages of SSP-RK must be 1, 2 or 3. %d is invalid\n", o.Stages)
+	}
+}
+
+// bry computes the ghost state ug corresponding to the state u at boundary face f
+func (o *FiniteVolume) bry(ug, u la.Vector, f *fvFace) {
+	bc, ok := o.bcs[f.tag]
+	if !ok || bc.kind == "outflow" {
+		copy(ug, u)
+		return
+	}
+	if bc.kind == "wall" {
+		o.Model.Wall(ug, u, f.n)
+		return
+	}
+	copy(ug, bc.state)
+}
+
+// gradients computes the gradients of the cell averages by least squares
+func (o *FiniteVolume) gradients(u [][]float64) {
+	for c := range o.cells {
+		for k := 0; k < o.nvars; k++ {
+			o.gx[c][k], o.gy[c][k] = 0, 0
+		}
+	}
+	for i, f := range o.faces {
+		ub := o.ghost[i]
+		if f.r >= 0 {
+			ub = u[f.r]
+		}
+		for k := 0; k < o.nvars; k++ {
+			δ := ub[k] - u[f.l][k]
+			o.gx[f.l][k] += f.d[0] * δ
+			o.gy[f.l][k] += f.d[1] * δ
+			if f.r >= 0 {
+				o.gx[f.r][k] += f.d[0] * δ
+				o.gy[f.r][k] += f.d[1] * δ
+			}
+		}
+	}
+	for c, cell := range o.cells {
+		for k := 0; k < o.nvars; k++ {
+			bx, by := o.gx[c][k], o.gy[c][k]
+			o.gx[c][k] = cell.gi[0]*bx + cell.gi[1]*by
+			o.gy[c][k] = cell.gi[1]*bx + cell.gi[2]*by
+		}
+	}
+}
+
+// numFlux computes the numerical flux fn across a face with unit normal n
+func (o *FiniteVolume) numFlux(ul, ur, n la.Vector) {
+	o.Model.Flux(o.fl, ul, n)
+	o.Model.Flux(o.fr, ur, n)
+	switch o.Riemann {
+	case "rusanov":
+		s := math.Max(o.maxSpeed(ul, n), o.maxSpeed(ur, n))
+		for k := 0; k < o.nvars; k++ {
+			o.fn[k] = (o.fl[k]+o.fr[k])/2 - s*(ur[k]-ul[k])/2
+		}
+	case "hll":
+		sminL, smaxL := o.Model.Speeds(ul, n)
+		sminR, smaxR := o.Model.Speeds(ur, n)
+		sl, sr := math.Min(sminL, sminR), math.Max(smaxL, smaxR)
+		switch {
+		case sl >= 0:
+			copy(o.fn, o.fl)
+		case sr <= 0:
+			copy(o.fn, o.fr)
+		default:
+			for k := 0; k < o.nvars; k++ {
+				o.fn[k] = (sr*o.fl[k] - sl*o.fr[k] + sl*sr*(ur[k]-ul[k])) / (sr - sl)
+			}
+		}
+	case "roe":
+		o.Model.RoeDissipation(o.fd, ul, ur, n)
+		for k := 0; k < o.nvars; k++ {
+			o.fn[k] = (o.fl[k]+o.fr[k])/2 - o.fd[k]/2
+		}
+	}
+}
+
+// maxSpeed returns the max absolute wave speed along n
+func (o *FiniteVolume) maxSpeed(u, n la.Vector) float64 {
+	smin, smax := o.Model.Speeds(u, n)
+	return math.Max(math.Abs(smin), math.Abs(smax))
+}
+
+// step advances the cell averages by one time step h using the SSP-RK method in Shu-Osher form
+func (o *FiniteVolume) step(u [][]float64, h float64) {
+	o.Rates(o.res, u)
+	if o.Stages == 1 {
+		o.stage(u, 0, u, 1, u, h)
+		return
+	}
+	o.stage(o.u1, 0, u, 1, u, h)
+	o.Rates(o.res, o.u1)
+	if o.Stages == 2 {
+		o.stage(u, 0.5, u, 0.5, o.u1, h)
+		return
+	}
+	o.stage(o.u2, 0.75, u, 0.25, o.u1, h)
+	o.Rates(o.res, o.u2)
+	o.stage(u, 1.0/3.0, u, 2.0/3.0, o.u2, h)
+}
+
+// stage computes w := α a + β (b + h res)
+func (o *FiniteVolume) stage(w [][]float64, α float64, a [][]float64, β float64, b [][]float64, h float64) {
+	for c := range w {
+		for k := 0; k < o.nvars; k++ {
+			w[c][k] = α*a[c][k] + β*(b[c][k]+h*o.res[c][k])
+		}
+	}
+}
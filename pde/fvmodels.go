@@ -0,0 +1,253 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/la"
+)
+
+// fvEntropyFix is the fraction of the speed of sound used by Harten's entropy fix of Roe solvers
+const fvEntropyFix = 0.1
+
+// FvAdvection implements the linear advection equation
+//
+//    ∂u
+//    ——— + ∇ ⋅ (v u) = 0      with      v = [vx vy] (constant)
+//    ∂t
+type FvAdvection struct {
+	Vx float64 // velocity x
+	Vy float64 // velocity y
+}
+
+// NewFvAdvection returns a new linear advection model
+//  params -- "vx" and "vy" [optional]
+func NewFvAdvection(params dbf.Params) (o *FvAdvection) {
+	o = new(FvAdvection)
+	err := params.ConnectSetOpt([]*float64{&o.Vx, &o.Vy}, []string{"vx", "vy"}, []bool{true, true}, "FvAdvection")
+	if err != "" {
+		chk.Panic(err)
+	}
+	return
+}
+
+// Nvars returns the number of conserved variables
+func (o *FvAdvection) Nvars() int { return 1 }
+
+// Flux computes the normal flux
+func (o *FvAdvection) Flux(f, u, n la.Vector) {
+	f[0] = (o.Vx*n[0] + o.Vy*n[1]) * u[0]
+}
+
+// Speeds returns the min and max wave speeds along n
+func (o *FvAdvection) Speeds(u, n la.Vector) (smin, smax float64) {
+	s := o.Vx*n[0] + o.Vy*n[1]
+	return s, s
+}
+
+// RoeDissipation computes |A| ⋅ (ur - ul)
+func (o *FvAdvection) RoeDissipation(d, ul, ur, n la.Vector) {
+	d[0] = math.Abs(o.Vx*n[0]+o.Vy*n[1]) * (ur[0] - ul[0])
+}
+
+// Wall computes the ghost state at a wall (zero gradient)
+func (o *FvAdvection) Wall(ug, u, n la.Vector) {
+	ug[0] = u[0]
+}
+
+// FvShallowWater implements the shallow water equations
+//
+//       ┌    ┐       ┌               ┐       ┌               ┐
+//       │ h  │       │  h vx         │       │  h vy         │
+//    u =│h vx│   Fx =│h vx² + g h²/2 │   Fy =│  h vx vy      │
+//       │h vy│       │  h vx vy      │       │h vy² + g h²/2 │
+//       └    ┘       └               ┘       └               ┘
+//
+//  where h is the depth of water, [vx vy] is the velocity and g is the gravity acceleration
+type FvShallowWater struct {
+	G float64 // gravity acceleration
+}
+
+// NewFvShallowWater returns a new shallow water model
+//  params -- "g" [optional; default = 9.81]
+func NewFvShallowWater(params dbf.Params) (o *FvShallowWater) {
+	o = &FvShallowWater{G: 9.81}
+	err := params.ConnectSetOpt([]*float64{&o.G}, []string{"g"}, []bool{true}, "FvShallowWater")
+	if err != "" {
+		chk.Panic(err)
+	}
+	return
+}
+
+// Nvars returns the number of conserved variables
+func (o *FvShallowWater) Nvars() int { return 3 }
+
+// Flux computes the normal flux
+func (o *FvShallowWater) Flux(f, u, n la.Vector) {
+	vn := (u[1]*n[0] + u[2]*n[1]) / u[0]
+	p := o.G * u[0] * u[0] / 2
+	f[0] = u[0] * vn
+	f[1] = u[1]*vn + p*n[0]
+	f[2] = u[2]*vn + p*n[1]
+}
+
+// Speeds returns the min and max wave speeds along n
+func (o *FvShallowWater) Speeds(u, n la.Vector) (smin, smax float64) {
+	vn := (u[1]*n[0] + u[2]*n[1]) / u[0]
+	c := math.Sqrt(o.G * u[0])
+	return vn - c, vn + c
+}
+
+// RoeDissipation computes |A(ũ)| ⋅ (ur - ul) with the Roe-averaged state ũ
+func (o *FvShallowWater) RoeDissipation(d, ul, ur, n la.Vector) {
+
+	// Roe averages
+	sl, sr := math.Sqrt(ul[0]), math.Sqrt(ur[0])
+	vx := (ul[1]/sl + ur[1]/sr) / (sl + sr)
+	vy := (ul[2]/sl + ur[2]/sr) / (sl + sr)
+	c := math.Sqrt(o.G * (ul[0] + ur[0]) / 2)
+	vn, vt := vx*n[0]+vy*n[1], -vx*n[1]+vy*n[0]
+
+	// wave strengths
+	dh := ur[0] - ul[0]
+	dmn := (ur[1]-ul[1])*n[0] + (ur[2]-ul[2])*n[1]
+	dmt := -(ur[1]-ul[1])*n[1] + (ur[2]-ul[2])*n[0]
+	a1 := ((vn+c)*dh - dmn) / (2 * c)
+	a2 := dmt - vt*dh
+	a3 := (dmn - (vn-c)*dh) / (2 * c)
+
+	// |λ| α r
+	δ := fvEntropyFix * c
+	a1 *= fvAbsEig(vn-c, δ)
+	a2 *= math.Abs(vn)
+	a3 *= fvAbsEig(vn+c, δ)
+	d[0] = a1 + a3
+	d[1] = a1*(vx-c*n[0]) - a2*n[1] + a3*(vx+c*n[0])
+	d[2] = a1*(vy-c*n[1]) + a2*n[0] + a3*(vy+c*n[1])
+}
+
+// Wall computes the ghost state at a slip wall (normal velocity is reversed)
+func (o *FvShallowWater) Wall(ug, u, n la.Vector) {
+	mn := u[1]*n[0] + u[2]*n[1]
+	ug[0] = u[0]
+	ug[1] = u[1] - 2*mn*n[0]
+	ug[2] = u[2] - 2*mn*n[1]
+}
+
+// FvEuler implements the Euler equations of gas dynamics
+//
+//       ┌    ┐       ┌            ┐       ┌            ┐
+//       │ ρ  │       │   ρ vx     │       │   ρ vy     │
+//    u =│ρ vx│   Fx =│ρ vx² + p   │   Fy =│  ρ vx vy   │
+//       │ρ vy│       │  ρ vx vy   │       │ρ vy² + p   │
+//       │ E  │       │ (E + p) vx │       │ (E + p) vy │
+//       └    ┘       └            ┘       └            ┘
+//
+//  where ρ is the density, [vx vy] is the velocity and E is the total energy per unit volume. The
+//  pressure of the ideal gas is p = (γ - 1) (E - ρ |v|² / 2)
+type FvEuler struct {
+	Gamma float64 // ratio of specific heats γ
+}
+
+// NewFvEuler returns a new model of the Euler equations
+//  params -- "gamma" [optional; default = 1.4]
+func NewFvEuler(params dbf.Params) (o *FvEuler) {
+	o = &FvEuler{Gamma: 1.4}
+	err := params.ConnectSetOpt([]*float64{&o.Gamma}, []string{"gamma"}, []bool{true}, "FvEuler")
+	if err != "" {
+		chk.Panic(err)
+	}
+	return
+}
+
+// Conserved returns the conserved variables given the primitive ones
+func (o *FvEuler) Conserved(ρ, vx, vy, p float64) la.Vector {
+	return []float64{ρ, ρ * vx, ρ * vy, p/(o.Gamma-1) + ρ*(vx*vx+vy*vy)/2}
+}
+
+// Pressure returns the pressure
+func (o *FvEuler) Pressure(u la.Vector) float64 {
+	return (o.Gamma - 1) * (u[3] - (u[1]*u[1]+u[2]*u[2])/(2*u[0]))
+}
+
+// Nvars returns the number of conserved variables
+func (o *FvEuler) Nvars() int { return 4 }
+
+// Flux computes the normal flux
+func (o *FvEuler) Flux(f, u, n la.Vector) {
+	vn := (u[1]*n[0] + u[2]*n[1]) / u[0]
+	p := o.Pressure(u)
+	f[0] = u[0] * vn
+	f[1] = u[1]*vn + p*n[0]
+	f[2] = u[2]*vn + p*n[1]
+	f[3] = (u[3] + p) * vn
+}
+
+// Speeds returns the min and max wave speeds along n
+func (o *FvEuler) Speeds(u, n la.Vector) (smin, smax float64) {
+	vn := (u[1]*n[0] + u[2]*n[1]) / u[0]
+	c := math.Sqrt(o.Gamma * o.Pressure(u) / u[0])
+	return vn - c, vn + c
+}
+
+// RoeDissipation computes |A(ũ)| ⋅ (ur - ul) with the Roe-averaged state ũ
+func (o *FvEuler) RoeDissipation(d, ul, ur, n la.Vector) {
+
+	// primitive variables
+	pl, pr := o.Pressure(ul), o.Pressure(ur)
+	vxl, vyl := ul[1]/ul[0], ul[2]/ul[0]
+	vxr, vyr := ur[1]/ur[0], ur[2]/ur[0]
+	hl, hr := (ul[3]+pl)/ul[0], (ur[3]+pr)/ur[0]
+
+	// Roe averages
+	sl, sr := math.Sqrt(ul[0]), math.Sqrt(ur[0])
+	ρ := sl * sr
+	vx := (sl*vxl + sr*vxr) / (sl + sr)
+	vy := (sl*vyl + sr*vyr) / (sl + sr)
+	h := (sl*hl + sr*hr) / (sl + sr)
+	q2 := vx*vx + vy*vy
+	c := math.Sqrt((o.Gamma - 1) * (h - q2/2))
+	vn, vt := vx*n[0]+vy*n[1], -vx*n[1]+vy*n[0]
+
+	// wave strengths
+	dρ, dp := ur[0]-ul[0], pr-pl
+	dvn := (vxr-vxl)*n[0] + (vyr-vyl)*n[1]
+	dvt := -(vxr-vxl)*n[1] + (vyr-vyl)*n[0]
+	a1 := (dp - ρ*c*dvn) / (2 * c * c)
+	a2 := dρ - dp/(c*c)
+	a3 := ρ * dvt
+	a4 := (dp + ρ*c*dvn) / (2 * c * c)
+
+	// |λ| α r
+	δ := fvEntropyFix * c
+	a1 *= fvAbsEig(vn-c, δ)
+	a2 *= math.Abs(vn)
+	a3 *= math.Abs(vn)
+	a4 *= fvAbsEig(vn+c, δ)
+	d[0] = a1 + a2 + a4
+	d[1] = a1*(vx-c*n[0]) + a2*vx - a3*n[1] + a4*(vx+c*n[0])
+	d[2] = a1*(vy-c*n[1]) + a2*vy + a3*n[0] + a4*(vy+c*n[1])
+	d[3] = a1*(h-vn*c) + a2*q2/2 + a3*vt + a4*(h+vn*c)
+}
+
+// Wall computes the ghost state at a slip wall (normal velocity is reversed)
+func (o *FvEuler) Wall(ug, u, n la.Vector) {
+	mn := u[1]*n[0] + u[2]*n[1]
+	ug[0] = u[0]
+	ug[1] = u[1] - 2*mn*n[0]
+	ug[2] = u[2] - 2*mn*n[1]
+	ug[3] = u[3]
+}
+
+// fvAbsEig returns |λ| modified by Harten's entropy fix with threshold δ
+func fvAbsEig(λ, δ float64) float64 {
+	if math.Abs(λ) < δ {
+		return (λ*λ + δ*δ) / (2 * δ)
+	}
+	return math.Abs(λ)
+}
@@ -0,0 +1,270 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/num"
+)
+
+// igaBase implements the data structures and algorithms shared by the isogeometric analysis (IGA)
+// solvers; i.e. the loop over the elements (non-zero knot spans) of all NURBS of a patch, the
+// Gauss quadrature within each element and the assembly of global matrices
+//
+//  The NURBS basis functions are associated with the control points. The control points of
+//  different NURBS (entities) located at the same position are merged by gm.NurbsPatch; thus,
+//  entities sharing a side are coupled through the global ids in the exchange data (conforming
+//  multi-patch). The equation numbers are I = c ⋅ ndof + d where c is the global id of the
+//  control point and d is the local index of the degree-of-freedom (dof)
+type igaBase struct {
+	patch *gm.NurbsPatch // patch of NURBS surfaces
+	ndim  int            // space dimension
+	ndof  int            // number of degrees-of-freedom per control point
+	neq   int            // total number of equations
+	elems []*igaElem     // all elements of all entities
+	ebcs  *EssentialBcs  // essential boundary conditions
+	kmat  []*la.Matrix   // [nelems] element "stiffness" matrices
+}
+
+// igaElem holds the data of an element (non-zero knot span) of a NURBS entity
+type igaElem struct {
+	entity int       // index of NURBS in patch
+	nurbs  *gm.Nurbs // NURBS entity
+	span   []int     // indices of knots delimiting the element {u0, u1, v0, v1}
+	lctrls []int     // local indices of control points (basis functions) in NURBS
+	ctrls  []int     // global ids of control points
+	gu, wu []float64 // knot values and weights of Gauss points along u
+	gv, wv []float64 // knot values and weights of Gauss points along v
+}
+
+// newIgaBase returns a new igaBase structure
+//  NOTE: the number of Gauss points along each direction is p + 1 where p is the order of the
+//        B-spline along that direction
+func newIgaBase(patch *gm.NurbsPatch, ndof int) (o *igaBase) {
+	o = new(igaBase)
+	o.patch = patch
+	o.ndim = 2
+	o.ndof = ndof
+	o.neq = len(patch.ControlPoints) * ndof
+	for e, nurbs := range patch.Entities {
+		if nurbs.Gnd() != 2 {
+			chk.Panic("IGA solvers require NURBS surfaces. gnd=%d of entity %d is invalid\n", nurbs.Gnd(), e)
+		}
+		for _, span := range nurbs.Elements() {
+			el := &igaElem{entity: e, nurbs: nurbs, span: span, lctrls: nurbs.IndBasis(span)}
+			el.ctrls = make([]int, len(el.lctrls))
+			for a, l := range el.lctrls {
+				el.ctrls[a] = patch.ExchangeData[e].Ctrls[l]
+			}
+			el.gu, el.wu = num.GaussLegendreXW(nurbs.U(0, span[0]), nurbs.U(0, span[1]), nurbs.Ord(0)+1)
+			el.gv, el.wv = num.GaussLegendreXW(nurbs.U(1, span[2]), nurbs.U(1, span[3]), nurbs.Ord(1)+1)
+			o.elems = append(o.elems, el)
+		}
+	}
+	o.ebcs = NewEssentialBcsNurbs(patch, ndof)
+	o.kmat = make([]*la.Matrix, len(o.elems))
+	return
+}
+
+// elemEqs returns the equation numbers of element
+func (o *igaBase) elemEqs(el *igaElem) (eqs []int) {
+	eqs = make([]int, len(el.ctrls)*o.ndof)
+	for a, c := range el.ctrls {
+		for d := 0; d < o.ndof; d++ {
+			eqs[a*o.ndof+d] = c*o.ndof + d
+		}
+	}
+	return
+}
+
+// knownEqs returns the equation numbers with essential boundary conditions
+func (o *igaBase) knownEqs() (eqs []int) {
+	for _, c := range o.ebcs.Nodes() {
+		for d := 0; d < o.ndof; d++ {
+			if _, available := o.ebcs.Value(c, d, 0); available {
+				eqs = append(eqs, c*o.ndof+d)
+			}
+		}
+	}
+	return
+}
+
+// calcXk returns the prescribed value at equation I (CalcXk in la.Equations)
+func (o *igaBase) calcXk(I int, t float64) float64 {
+	val, _ := o.ebcs.Value(I/o.ndof, I%o.ndof, t)
+	return val
+}
+
+// shapeGrads computes the basis functions S, their gradients G = dS/dx, the coordinates x and
+// the determinant of the Jacobian dx/du at knot values u within element el
+func (o *igaBase) shapeGrads(G *la.Matrix, x la.Vector, el *igaElem, u []float64) (S la.Vector, detJ float64) {
+	J := la.NewMatrix(o.ndim, o.ndim)
+	Ji := la.NewMatrix(o.ndim, o.ndim)
+	el.nurbs.PointAndFirstDerivs(J, x, u, o.ndim) // J := dx/du
+	detJ = la.MatInvSmall(Ji, J, 1e-14)
+	if detJ <= 0 {
+		chk.Panic("determinant of Jacobian of element %v of entity %d is non-positive (%g)\n", el.span, el.entity, detJ)
+	}
+	el.nurbs.CalcBasisAndDerivs(u)
+	S = la.NewVector(len(el.lctrls))
+	dSdu := la.NewVector(o.ndim)
+	for a, l := range el.lctrls {
+		S[a] = el.nurbs.GetBasisL(l)
+		el.nurbs.GetDerivL(dSdu, l)
+		for i := 0; i < o.ndim; i++ {
+			G.Set(a, i, 0)
+			for k := 0; k < o.ndim; k++ {
+				G.Add(a, i, dSdu[k]*Ji.Get(k, i)) // G := dSdu ⋅ dudx
+			}
+		}
+	}
+	return
+}
+
+// integrate runs fcn at all Gauss points of element el
+//  fcn -- integrand: S are the basis functions, G = dS/dx, x are the coordinates of the Gauss
+//         point and w = det(J) ⋅ weight
+func (o *igaBase) integrate(el *igaElem, fcn func(S la.Vector, G *la.Matrix, x la.Vector, w float64)) {
+	G := la.NewMatrix(len(el.lctrls), o.ndim)
+	x := la.NewVector(o.ndim)
+	u := make([]float64, 2)
+	for j, v := range el.gv {
+		for i, r := range el.gu {
+			u[0], u[1] = r, v
+			S, detJ := o.shapeGrads(G, x, el, u)
+			fcn(S, G, x, detJ*el.wu[i]*el.wv[j])
+		}
+	}
+}
+
+// computeMatrices computes the element matrices of all elements
+//  kernel -- computes the element stiffness matrix K (set to zero on input)
+func (o *igaBase) computeMatrices(kernel func(el *igaElem, K *la.Matrix)) {
+	for e, el := range o.elems {
+		n := len(el.ctrls) * o.ndof
+		o.kmat[e] = la.NewMatrix(n, n)
+		kernel(el, o.kmat[e])
+	}
+}
+
+// computeRhs computes the global right-hand-side vector F(t)
+//  kernel -- computes the element vector Fe (set to zero on input) [may be nil]
+func (o *igaBase) computeRhs(F la.Vector, t float64, kernel func(el *igaElem, Fe la.Vector, t float64)) {
+	F.Fill(0)
+	if kernel == nil {
+		return
+	}
+	for _, el := range o.elems {
+		Fe := la.NewVector(len(el.ctrls) * o.ndof)
+		kernel(el, Fe, t)
+		for i, I := range o.elemEqs(el) {
+			F[I] += Fe[i]
+		}
+	}
+}
+
+// allocEquations allocates the equations structure
+func (o *igaBase) allocEquations(reactions bool) (eqs *la.Equations) {
+	eqs = la.NewEquations(o.neq, o.knownEqs())
+	cols := make([][]int, o.neq)
+	for _, el := range o.elems {
+		ids := o.elemEqs(el)
+		for _, I := range ids {
+			cols[I] = append(cols[I], ids...)
+		}
+	}
+	allocEqs(eqs, cols, reactions)
+	return
+}
+
+// putMatrices puts [K] into equations
+func (o *igaBase) putMatrices(eqs *la.Equations) {
+	eqs.Start()
+	for e, el := range o.elems {
+		K := o.kmat[e]
+		ids := o.elemEqs(el)
+		for i, I := range ids {
+			for j, J := range ids {
+				eqs.Put(I, J, K.Get(i, j))
+			}
+		}
+	}
+}
+
+// globalMatrix returns [K] (full system) in triplet format
+func (o *igaBase) globalMatrix() (A *la.Triplet) {
+	nnz := 0
+	for e := range o.elems {
+		nnz += o.kmat[e].M * o.kmat[e].M
+	}
+	A = la.NewTriplet(o.neq, o.neq, nnz)
+	for e, el := range o.elems {
+		K := o.kmat[e]
+		ids := o.elemEqs(el)
+		for i, I := range ids {
+			for j, J := range ids {
+				A.Put(I, J, K.Get(i, j))
+			}
+		}
+	}
+	return
+}
+
+// locate returns the element of entity containing the point with knot values u
+//  NOTE: as in the evaluation of basis functions, the spans are [uᵢ, uᵢ₊₁) except the last one
+func (o *igaBase) locate(entity int, u []float64) *igaElem {
+	inside := func(el *igaElem, dir int) bool {
+		a, b := el.nurbs.U(dir, el.span[2*dir]), el.nurbs.U(dir, el.span[2*dir+1])
+		knots := el.nurbs.GetU(dir)
+		if b == knots[len(knots)-1] {
+			return u[dir] >= a && u[dir] <= b
+		}
+		return u[dir] >= a && u[dir] < b
+	}
+	for _, el := range o.elems {
+		if el.entity == entity && inside(el, 0) && inside(el, 1) {
+			return el
+		}
+	}
+	chk.Panic("cannot find element of entity %d containing u=%v\n", entity, u)
+	return nil
+}
+
+// IgaTagSides sets the tags of the control points on the sides of a NURBS surface in patch; e.g.
+// to prescribe essential boundary conditions
+//  entity -- index of NURBS surface in patch
+//  tags   -- [4] tags of sides u=umin, u=umax, v=vmin and v=vmax. Zero tags are ignored. The
+//            tags of the control points at corners are set by the last side; thus, IgaTagSides
+//            may be called again with the sides that must take precedence at corners
+func IgaTagSides(patch *gm.NurbsPatch, entity int, tags []int) {
+	nurbs := patch.Entities[entity]
+	n0, n1 := nurbs.NumBasis(0), nurbs.NumBasis(1)
+	ctrls := patch.ExchangeData[entity].Ctrls
+	for side, tag := range tags {
+		if tag == 0 {
+			continue
+		}
+		switch side {
+		case 0, 1:
+			i := 0
+			if side == 1 {
+				i = n0 - 1
+			}
+			for j := 0; j < n1; j++ {
+				patch.ControlPoints[ctrls[i+j*n0]].Tag = tag
+			}
+		case 2, 3:
+			j := 0
+			if side == 3 {
+				j = n1 - 1
+			}
+			for i := 0; i < n0; i++ {
+				patch.ControlPoints[ctrls[i+j*n0]].Tag = tag
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/la"
+)
+
+// IgaElastic implements the isogeometric analysis (IGA) of linear elasticity (small strains) with
+// isotropic materials (2D plane-strain or plane-stress) on a patch of NURBS surfaces
+//
+//    ∇ ⋅ σ + {b} = 0      with      σ = λ tr(ε) I + 2 μ ε   and   ε = (∇u + ∇uᵀ) / 2
+//
+//  where {b}({x}) is the body force. After discretisation with the NURBS basis functions Rᵃ, the
+//  system of equations is
+//
+//    [K]⋅{u} = {F}
+//
+//           ⌠                             ⌠
+//    Kᵃᵇ =  │ [B]ᵃᵀ ⋅ [D] ⋅ [B]ᵇ dΩ   Fᵃᵢ = │ Rᵃ bᵢ dΩ
+//           ⌡                             ⌡
+//            Ω                             Ω
+//
+//  where [D] is the elastic modulus in Voigt notation with engineering shear strains as in
+//  FemElastic. The degrees-of-freedom of each control point are the displacements (ux, uy). The
+//  essential boundary conditions are prescribed at the control points (with given tag).
+//  Boundaries without prescribed conditions are traction free.
+type IgaElastic struct {
+	E           float64        // Young's modulus
+	Nu          float64        // Poisson's coefficient
+	PlaneStress bool           // plane-stress; otherwise plane-strain
+	Patch       *gm.NurbsPatch // patch of NURBS surfaces
+	Body        fun.Vvs        // body force function {b}({x},t) [may be nil; evaluated at t=0]
+	EssenBcs    *EssentialBcs  // essential boundary conditions
+	Eqs         *la.Equations  // equations
+	F           la.Vector      // global right-hand-side vector {F} [full system]
+	iga         *igaBase       // IGA data and algorithms
+	ready       bool           // element matrices and equations are ready
+}
+
+// NewIgaElastic creates a new IGA solver for linear elasticity
+//  params -- "E" and "nu"
+//  patch  -- patch of NURBS surfaces
+//  body   -- body force {b}({x},t) [may be nil]
+func NewIgaElastic(params dbf.Params, patch *gm.NurbsPatch, body fun.Vvs) (o *IgaElastic) {
+	o = new(IgaElastic)
+	err := params.ConnectSet([]*float64{&o.E, &o.Nu}, []string{"E", "nu"}, "IgaElastic")
+	if err != "" {
+		chk.Panic(err)
+	}
+	if o.E <= 0 || o.Nu <= -1 || o.Nu >= 0.5 {
+		chk.Panic("E=%g and nu=%g are invalid. E must be positive and nu must be in (-1,0.5)\n", o.E, o.Nu)
+	}
+	o.Patch = patch
+	o.Body = body
+	o.iga = newIgaBase(patch, 2)
+	o.EssenBcs = o.iga.ebcs
+	o.F = la.NewVector(o.iga.neq)
+	return
+}
+
+// AddBc adds essential boundary condition (displacement) at the control points with given tag
+// (see IgaTagSides)
+//  tag    -- tag of control points
+//  dof    -- index of component: 0 ⇒ x, 1 ⇒ y
+//  cvalue -- constant value [optional]; or
+//  fvalue -- function value [optional]
+func (o *IgaElastic) AddBc(tag, dof int, cvalue float64, fvalue dbf.T) {
+	if dof < 0 || dof > 1 {
+		chk.Panic("dof=%d is invalid; it must be in [0,1]\n", dof)
+	}
+	o.ready = false
+	o.EssenBcs.AddUsingTag(tag, dof, cvalue, fvalue)
+}
+
+// Assemble computes the element matrices and assembles [K] into the A matrix of [A]⋅{u} = {b}
+//  reactions -- prepare for computation of RHS
+func (o *IgaElastic) Assemble(reactions bool) {
+	D := o.ElasticModulus()
+	o.iga.computeMatrices(func(el *igaElem, K *la.Matrix) {
+		nu := len(el.ctrls) * 2
+		B := la.NewMatrix(3, nu)
+		DB := la.NewMatrix(3, nu)
+		o.iga.integrate(el, func(S la.Vector, G *la.Matrix, x la.Vector, w float64) {
+			elasticB(B, G, 2)
+			la.MatMatMul(DB, 1, D, B)
+			la.MatTrMatMulAdd(K, w, B, DB) // K += w ⋅ Bᵀ ⋅ D ⋅ B
+		})
+	})
+	o.Eqs = o.iga.allocEquations(reactions)
+	o.iga.putMatrices(o.Eqs)
+	o.ready = true
+}
+
+// SolveSteady solves the static problem
+//  Solves: [K]⋅{u} = {F} represented by [A]⋅{x} = {b}
+//  Output:
+//    u -- displacements at all control points; u[c⋅2+i] is the i-component at control point c
+//    f -- [K]⋅{u} at all control points (i.e. {F} plus the reactions at the prescribed points)
+//         [only if reactions == true; the equations must be assembled with reactions == true]
+func (o *IgaElastic) SolveSteady(reactions bool) (u, f []float64) {
+	if !o.ready {
+		o.Assemble(reactions)
+	}
+	o.computeRhs()
+	o.Eqs.SolveOnce(o.iga.calcXk, o.calcBu)
+	u = make([]float64, o.iga.neq)
+	o.Eqs.JoinVector(u, o.Eqs.Xu, o.Eqs.Xk)
+	if reactions {
+		f = make([]float64, o.iga.neq)
+		for i, I := range o.Eqs.UtoF {
+			o.Eqs.Bu[i] = o.F[I]
+		}
+		o.Eqs.JoinVector(f, o.Eqs.Bu, o.Eqs.Bk)
+	}
+	return
+}
+
+// Eval evaluates the displacements and stresses at a point of a NURBS surface
+//  Input:
+//    u      -- displacements at all control points
+//    entity -- index of NURBS in patch
+//    knots  -- [2] knot values of point
+//  Output:
+//    x   -- [2] coordinates of point
+//    d   -- [2] displacements at point
+//    sig -- [3] stresses at point {σxx, σyy, σxy}
+func (o *IgaElastic) Eval(u []float64, entity int, knots []float64) (x, d, sig la.Vector) {
+	el := o.iga.locate(entity, knots)
+	ids := o.iga.elemEqs(el)
+	ue := la.NewVector(len(ids))
+	for i, I := range ids {
+		ue[i] = u[I]
+	}
+	G := la.NewMatrix(len(el.ctrls), 2)
+	B := la.NewMatrix(3, len(ids))
+	eps := la.NewVector(3)
+	x = la.NewVector(2)
+	d = la.NewVector(2)
+	sig = la.NewVector(3)
+	S, _ := o.iga.shapeGrads(G, x, el, knots)
+	for a := range el.ctrls {
+		d[0] += S[a] * ue[a*2+0]
+		d[1] += S[a] * ue[a*2+1]
+	}
+	elasticB(B, G, 2)
+	la.MatVecMul(eps, 1, B, ue)
+	la.MatVecMul(sig, 1, o.ElasticModulus(), eps)
+	return
+}
+
+// GlobalMatrix returns the global stiffness matrix [K] (full system)
+func (o *IgaElastic) GlobalMatrix() (K *la.Triplet) {
+	if !o.ready {
+		o.Assemble(false)
+	}
+	return o.iga.globalMatrix()
+}
+
+// ElasticModulus returns the elastic modulus [D] in Voigt notation (engineering shear strains)
+func (o *IgaElastic) ElasticModulus() (D *la.Matrix) {
+	return elasticModulus(o.E, o.Nu, 2, o.PlaneStress)
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// computeRhs computes {F}
+func (o *IgaElastic) computeRhs() {
+	if o.Body == nil {
+		o.iga.computeRhs(o.F, 0, nil)
+		return
+	}
+	o.iga.computeRhs(o.F, 0, func(el *igaElem, Fe la.Vector, t float64) {
+		b := la.NewVector(2)
+		o.iga.integrate(el, func(S la.Vector, G *la.Matrix, x la.Vector, w float64) {
+			o.Body(b, x, t)
+			for a := range S {
+				Fe[a*2+0] += w * b[0] * S[a]
+				Fe[a*2+1] += w * b[1] * S[a]
+			}
+		})
+	})
+}
+
+// calcBu returns the RHS value at equation I (CalcBu in la.Equations)
+func (o *IgaElastic) calcBu(I int, t float64) float64 {
+	return o.F[I]
+}
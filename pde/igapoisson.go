@@ -0,0 +1,168 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/la"
+)
+
+// IgaPoisson implements the isogeometric analysis (IGA) of the Poisson equation (2D) on a patch of
+// NURBS surfaces
+//
+//    ∇ ⋅ (K ∇u) = s({x})      with      K = diag(kx, ky)
+//
+//  The geometry and the solution are represented by the same NURBS basis functions Rᵃ; i.e. the
+//  unknowns are the values of u at the control points (not interpolatory in general). After
+//  discretisation, the system of equations is
+//
+//    [K]⋅{u} = {F}
+//
+//           ⌠                          ⌠
+//    Kᵃᵇ =  │ ∇Rᵃ ⋅ K ⋅ ∇Rᵇ dΩ   Fᵃ = -│ Rᵃ s dΩ
+//           ⌡                          ⌡
+//            Ω                          Ω
+//
+//  The integrals are computed with Gauss points in each element (non-zero knot span) of each
+//  NURBS. The essential boundary conditions are prescribed at the control points (with given tag)
+//  using the coordinates of the control points; this is exact for linear functions only.
+//  Boundaries without prescribed conditions are insulated.
+type IgaPoisson struct {
+	Kx       float64        // isotropic coefficient x
+	Ky       float64        // isotropic coefficient y
+	Patch    *gm.NurbsPatch // patch of NURBS surfaces
+	Source   fun.Svs        // source term function s({x},t) [may be nil; evaluated at t=0]
+	EssenBcs *EssentialBcs  // essential boundary conditions
+	Eqs      *la.Equations  // equations
+	F        la.Vector      // global right-hand-side vector {F} [full system]
+	iga      *igaBase       // IGA data and algorithms
+	ready    bool           // element matrices and equations are ready
+}
+
+// NewIgaPoisson creates a new IGA solver for the Poisson equation
+//  params -- "kx" and "ky"
+//  patch  -- patch of NURBS surfaces
+//  source -- source term s({x},t) [may be nil]
+func NewIgaPoisson(params dbf.Params, patch *gm.NurbsPatch, source fun.Svs) (o *IgaPoisson) {
+	o = new(IgaPoisson)
+	err := params.ConnectSet([]*float64{&o.Kx, &o.Ky}, []string{"kx", "ky"}, "IgaPoisson")
+	if err != "" {
+		chk.Panic(err)
+	}
+	o.Patch = patch
+	o.Source = source
+	o.iga = newIgaBase(patch, 1)
+	o.EssenBcs = o.iga.ebcs
+	o.F = la.NewVector(o.iga.neq)
+	return
+}
+
+// AddBc adds essential boundary condition at the control points with given tag (see IgaTagSides)
+//  tag    -- tag of control points
+//  cvalue -- constant value [optional]; or
+//  fvalue -- function value [optional]
+func (o *IgaPoisson) AddBc(tag int, cvalue float64, fvalue dbf.T) {
+	o.ready = false
+	o.EssenBcs.AddUsingTag(tag, 0, cvalue, fvalue)
+}
+
+// Assemble computes the element matrices and assembles [K] into the A matrix of [A]⋅{u} = {b}
+//  reactions -- prepare for computation of RHS
+func (o *IgaPoisson) Assemble(reactions bool) {
+	kk := []float64{o.Kx, o.Ky}
+	o.iga.computeMatrices(func(el *igaElem, K *la.Matrix) {
+		o.iga.integrate(el, func(S la.Vector, G *la.Matrix, x la.Vector, w float64) {
+			for a := range S {
+				for b := range S {
+					K.Add(a, b, w*(G.Get(a, 0)*kk[0]*G.Get(b, 0)+G.Get(a, 1)*kk[1]*G.Get(b, 1)))
+				}
+			}
+		})
+	})
+	o.Eqs = o.iga.allocEquations(reactions)
+	o.iga.putMatrices(o.Eqs)
+	o.ready = true
+}
+
+// SolveSteady solves the problem
+//  Solves: [K]⋅{u} = {F} represented by [A]⋅{x} = {b}
+//  Output:
+//    u -- values of u at all control points
+//    f -- [K]⋅{u} at all control points (i.e. {F} plus the "reactions" at the prescribed points)
+//         [only if reactions == true; the equations must be assembled with reactions == true]
+func (o *IgaPoisson) SolveSteady(reactions bool) (u, f []float64) {
+	if !o.ready {
+		o.Assemble(reactions)
+	}
+	o.computeRhs()
+	o.Eqs.SolveOnce(o.iga.calcXk, o.calcBu)
+	u = make([]float64, o.iga.neq)
+	o.Eqs.JoinVector(u, o.Eqs.Xu, o.Eqs.Xk)
+	if reactions {
+		f = make([]float64, o.iga.neq)
+		for i, I := range o.Eqs.UtoF {
+			o.Eqs.Bu[i] = o.F[I]
+		}
+		o.Eqs.JoinVector(f, o.Eqs.Bu, o.Eqs.Bk)
+	}
+	return
+}
+
+// Eval evaluates the solution at a point of a NURBS surface
+//  Input:
+//    u      -- values of u at all control points
+//    entity -- index of NURBS in patch
+//    knots  -- [2] knot values of point
+//  Output:
+//    x    -- [2] coordinates of point
+//    val  -- value of u at point
+//    grad -- [2] gradient of u at point
+func (o *IgaPoisson) Eval(u []float64, entity int, knots []float64) (x la.Vector, val float64, grad la.Vector) {
+	el := o.iga.locate(entity, knots)
+	G := la.NewMatrix(len(el.ctrls), 2)
+	x = la.NewVector(2)
+	grad = la.NewVector(2)
+	S, _ := o.iga.shapeGrads(G, x, el, knots)
+	for a, c := range el.ctrls {
+		val += S[a] * u[c]
+		grad[0] += G.Get(a, 0) * u[c]
+		grad[1] += G.Get(a, 1) * u[c]
+	}
+	return
+}
+
+// GlobalMatrix returns the global matrix [K] (full system)
+func (o *IgaPoisson) GlobalMatrix() (K *la.Triplet) {
+	if !o.ready {
+		o.Assemble(false)
+	}
+	return o.iga.globalMatrix()
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// computeRhs computes {F}
+func (o *IgaPoisson) computeRhs() {
+	if o.Source == nil {
+		o.iga.computeRhs(o.F, 0, nil)
+		return
+	}
+	o.iga.computeRhs(o.F, 0, func(el *igaElem, Fe la.Vector, t float64) {
+		o.iga.integrate(el, func(S la.Vector, G *la.Matrix, x la.Vector, w float64) {
+			s := o.Source(x, t)
+			for a := range S {
+				Fe[a] -= w * S[a] * s
+			}
+		})
+	})
+}
+
+// calcBu returns the RHS value at equation I (CalcBu in la.Equations)
+func (o *IgaPoisson) calcBu(I int, t float64) float64 {
+	return o.F[I]
+}
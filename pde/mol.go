@@ -0,0 +1,380 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/ode"
+)
+
+// MolOutF defines a callback function to process snapshots of transient solutions
+//  INPUT:
+//    idx -- index of snapshot (0 corresponds to the initial state)
+//    t   -- time
+//    u   -- values of u at all nodes of the grid (including nodes with essential conditions)
+//    v   -- values of ∂u/∂t at all nodes [wave equation only; nil otherwise]
+//
+//  OUTPUT:
+//    stop -- stop simulation (nicely)
+//
+//  NOTE: u and v are overwritten after this function returns; thus, copy them if needed
+type MolOutF func(idx int, t float64, u, v []float64) (stop bool)
+
+// FdmHeat solves the transient diffusion (heat) equation using the method of lines (MOL)
+//      ∂u
+//    c ——— = L{u} - s({x},t)      with      L{u} = ∇ ⋅ (k K ∇u)
+//      ∂t
+//
+//  where L is the FDM operator with its boundary conditions (see FdmLaplacian). Thus, the steady
+//  state corresponds to the solution given by FdmLaplacian.SolveSteady. After discretisation in
+//  space, the system of ODEs for the nodes without essential conditions is
+//
+//    d{uu}
+//    ————— = {f}(t,{uu}) = ([Auu]⋅{uu} + [Auk]⋅{uk}(t) - {bu}(t)) / c
+//     dt
+//
+//  where {uk}(t) are the (possibly time-dependent) prescribed values from EssentialBcs. The
+//  Jacobian d{f}/d{uu} = [Auu] / c is sparse and constant.
+type FdmHeat struct {
+	Op     *FdmLaplacian // operator with boundary conditions
+	C      float64       // capacity coefficient c > 0 (e.g. ρ cp)
+	Method string        // ODE method; e.g. "radau5", "bweuler", "fweuler", "rk4", "dopri5"
+	Atol   float64       // absolute tolerance for methods with variable steps [default = 1e-6]
+	Rtol   float64       // relative tolerance for methods with variable steps [default = 1e-6]
+	Stat   *ode.Stat     // statistics of the last run
+	mol    *fdmMol       // method of lines data
+}
+
+// NewFdmHeat returns a new transient diffusion (heat) solver
+//  op     -- operator with boundary conditions
+//  c      -- capacity coefficient c > 0
+//  method -- ODE method; e.g. "radau5", "bweuler", "fweuler", "rk4", "dopri5"
+func NewFdmHeat(op *FdmLaplacian, c float64, method string) (o *FdmHeat) {
+	if c <= 0 {
+		chk.Panic("capacity coefficient must be positive. c = %g is invalid\n", c)
+	}
+	o = new(FdmHeat)
+	o.Op = op
+	o.C = c
+	o.Method = method
+	o.Atol, o.Rtol = 1e-6, 1e-6
+	return
+}
+
+// Fcn computes d{uu}/dt = {f}(t,{uu}) (see ode.Func)
+func (o *FdmHeat) Fcn(f la.Vector, h, t float64, y la.Vector) {
+	o.mol.residual(f, t, y)
+	f.Apply(1.0/o.C, f)
+}
+
+// Jac computes the sparse Jacobian d{f}/d{uu} = [Auu] / c (see ode.JacF)
+func (o *FdmHeat) Jac(dfdy *la.Triplet, h, t float64, y la.Vector) {
+	nu := o.mol.eqs.Nu
+	if dfdy.Max() == 0 {
+		dfdy.Init(nu, nu, o.mol.nnz)
+	}
+	dfdy.Start()
+	o.mol.putAuu(dfdy, 0, 0, 1.0/o.C)
+}
+
+// Solve solves the transient problem from t = 0 to tf
+//  INPUT:
+//    u     -- initial values of u at all nodes of the grid (the values at the nodes with
+//             essential conditions are replaced by the prescribed values)
+//    tf    -- final time
+//    dt    -- time step for methods with fixed steps (e.g. "bweuler" or "rk4") [0 ⇒ variable steps]
+//    dtOut -- time increment for snapshots [0 ⇒ snapshots at all steps]
+//    out   -- callback function to process snapshots [may be nil]
+//
+//  OUTPUT:
+//    u -- final values of u at all nodes
+//
+//  NOTE: with variable steps and dtOut > 0, the snapshots are computed by dense output, which
+//        is available with "radau5", "dopri5" and "dopri8" only. With fixed steps, the snapshots
+//        are taken at the first steps reaching the output times
+func (o *FdmHeat) Solve(u []float64, tf, dt, dtOut float64, out MolOutF) {
+	o.mol = newFdmMol(o.Op)
+	y := la.NewVector(o.mol.eqs.Nu)
+	o.mol.split(y, u)
+	var snap func(t float64, y la.Vector) bool
+	if out != nil {
+		idx := 0
+		snap = func(t float64, y la.Vector) (stop bool) {
+			o.mol.join(u, nil, t, y)
+			stop = out(idx, t, u, nil)
+			idx++
+			return
+		}
+	}
+	var t float64
+	o.Stat, t = molRun(o.Method, o.Atol, o.Rtol, o.Fcn, o.Jac, y, tf, dt, dtOut, snap)
+	o.mol.join(u, nil, t, y)
+}
+
+// FdmWave solves the (damped) wave equation using the method of lines (MOL)
+//      ∂²u     ∂u
+//    m ———— + d ——— = L{u} - s({x},t)      with      L{u} = ∇ ⋅ (k K ∇u)
+//      ∂t²     ∂t
+//
+//  where L is the FDM operator with its boundary conditions (see FdmLaplacian). With v = ∂u/∂t,
+//  the system of ODEs for the nodes without essential conditions is
+//
+//    d{uu}/dt = {vu}
+//    d{vu}/dt = ([Auu]⋅{uu} + [Auk]⋅{uk}(t) - {bu}(t) - d {vu}) / m
+//
+//  where {uk}(t) are the (possibly time-dependent) prescribed values from EssentialBcs. The
+//  Jacobian is sparse and constant:
+//
+//    d{f}     [    0          I    ]
+//    ———— =   [                    ]
+//    d{y}     [ [Auu]/m    -d/m I  ]
+type FdmWave struct {
+	Op     *FdmLaplacian // operator with boundary conditions
+	M      float64       // mass coefficient m > 0 (e.g. 1/c² where c is the wave speed)
+	D      float64       // damping coefficient d ≥ 0
+	Method string        // ODE method; e.g. "radau5", "bweuler", "rk4", "dopri5", "dopri8"
+	Atol   float64       // absolute tolerance for methods with variable steps [default = 1e-6]
+	Rtol   float64       // relative tolerance for methods with variable steps [default = 1e-6]
+	Stat   *ode.Stat     // statistics of the last run
+	mol    *fdmMol       // method of lines data
+}
+
+// NewFdmWave returns a new wave equation solver
+//  op     -- operator with boundary conditions
+//  m      -- mass coefficient m > 0
+//  d      -- damping coefficient d ≥ 0
+//  method -- ODE method; e.g. "radau5", "bweuler", "rk4", "dopri5", "dopri8"
+func NewFdmWave(op *FdmLaplacian, m, d float64, method string) (o *FdmWave) {
+	if m <= 0 {
+		chk.Panic("mass coefficient must be positive. m = %g is invalid\n", m)
+	}
+	if d < 0 {
+		chk.Panic("damping coefficient must be non-negative. d = %g is invalid\n", d)
+	}
+	o = new(FdmWave)
+	o.Op = op
+	o.M = m
+	o.D = d
+	o.Method = method
+	o.Atol, o.Rtol = 1e-6, 1e-6
+	return
+}
+
+// Fcn computes d{y}/dt = {f}(t,{y}) with {y} = {uu, vu} (see ode.Func)
+func (o *FdmWave) Fcn(f la.Vector, h, t float64, y la.Vector) {
+	nu := o.mol.eqs.Nu
+	uu, vu := y[:nu], y[nu:]
+	fu, fv := f[:nu], f[nu:]
+	o.mol.residual(fv, t, uu)
+	for i := 0; i < nu; i++ {
+		fu[i] = vu[i]
+		fv[i] = (fv[i] - o.D*vu[i]) / o.M
+	}
+}
+
+// Jac computes the sparse Jacobian d{f}/d{y} (see ode.JacF)
+func (o *FdmWave) Jac(dfdy *la.Triplet, h, t float64, y la.Vector) {
+	nu := o.mol.eqs.Nu
+	if dfdy.Max() == 0 {
+		dfdy.Init(2*nu, 2*nu, o.mol.nnz+2*nu)
+	}
+	dfdy.Start()
+	for i := 0; i < nu; i++ {
+		dfdy.Put(i, nu+i, 1)
+		dfdy.Put(nu+i, nu+i, -o.D/o.M)
+	}
+	o.mol.putAuu(dfdy, nu, 0, 1.0/o.M)
+}
+
+// Solve solves the transient problem from t = 0 to tf
+//  INPUT:
+//    u     -- initial values of u at all nodes of the grid (the values at the nodes with
+//             essential conditions are replaced by the prescribed values)
+//    v     -- initial values of ∂u/∂t at all nodes of the grid [may be nil ⇒ zero]
+//    tf    -- final time
+//    dt    -- time step for methods with fixed steps (e.g. "bweuler" or "rk4") [0 ⇒ variable steps]
+//    dtOut -- time increment for snapshots [0 ⇒ snapshots at all steps]
+//    out   -- callback function to process snapshots [may be nil]
+//
+//  OUTPUT:
+//    u -- final values of u at all nodes
+//    v -- final values of ∂u/∂t at all nodes [if not nil]
+//
+//  NOTE: see FdmHeat.Solve regarding the snapshots
+func (o *FdmWave) Solve(u, v []float64, tf, dt, dtOut float64, out MolOutF) {
+	o.mol = newFdmMol(o.Op)
+	nu := o.mol.eqs.Nu
+	y := la.NewVector(2 * nu)
+	o.mol.split(y[:nu], u)
+	if v != nil {
+		o.mol.split(y[nu:], v)
+	}
+	vv := v
+	if vv == nil {
+		vv = make([]float64, len(u))
+	}
+	var snap func(t float64, y la.Vector) bool
+	if out != nil {
+		idx := 0
+		snap = func(t float64, y la.Vector) (stop bool) {
+			o.mol.join(u, vv, t, y)
+			stop = out(idx, t, u, vv)
+			idx++
+			return
+		}
+	}
+	var t float64
+	o.Stat, t = molRun(o.Method, o.Atol, o.Rtol, o.Fcn, o.Jac, y, tf, dt, dtOut, snap)
+	o.mol.join(u, vv, t, y)
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// fdmMol holds the data of the semi-discrete system of the method of lines
+type fdmMol struct {
+	op  *FdmLaplacian // operator
+	eqs *la.Equations // equations (from operator)
+	auu *la.CCMatrix  // [Auu]
+	auk *la.CCMatrix  // [Auk] [may be nil]
+	xk  la.Vector     // prescribed values {uk}(t)
+	nnz int           // number of non-zeros in [Auu]
+}
+
+// newFdmMol assembles the operator (if needed) and returns the semi-discrete system
+func newFdmMol(op *FdmLaplacian) (o *fdmMol) {
+	if op.Eqs == nil || !op.bcsReady {
+		op.Assemble(false)
+	}
+	o = new(fdmMol)
+	o.op = op
+	o.eqs = op.Eqs
+	if o.eqs.Nu == 0 {
+		chk.Panic("all nodes have essential boundary conditions; there is nothing to solve\n")
+	}
+	o.auu = o.eqs.Auu.ToMatrix(nil)
+	_, _, _, _, ax := o.auu.Get()
+	o.nnz = len(ax)
+	if o.eqs.Nk > 0 {
+		o.auk = o.eqs.Auk.ToMatrix(nil)
+	}
+	o.xk = la.NewVector(o.eqs.Nk)
+	return
+}
+
+// residual computes r = [Auu]⋅{uu} + [Auk]⋅{uk}(t) - {bu}(t)
+func (o *fdmMol) residual(r la.Vector, t float64, uu la.Vector) {
+	for i, I := range o.eqs.UtoF {
+		r[i] = -o.op.calcBu(I, t)
+	}
+	la.SpMatVecMulAdd(r, 1, o.auu, uu)
+	if o.auk != nil {
+		for i, I := range o.eqs.KtoF {
+			o.xk[i] = o.op.calcXk(I, t)
+		}
+		la.SpMatVecMulAdd(r, 1, o.auk, o.xk)
+	}
+}
+
+// putAuu puts α [Auu] into triplet starting at (i0,j0)
+func (o *fdmMol) putAuu(a *la.Triplet, i0, j0 int, α float64) {
+	_, n, ap, ai, ax := o.auu.Get()
+	for j := 0; j < n; j++ {
+		for k := ap[j]; k < ap[j+1]; k++ {
+			a.Put(i0+ai[k], j0+j, α*ax[k])
+		}
+	}
+}
+
+// split collects the values of the nodes without essential conditions
+func (o *fdmMol) split(yu la.Vector, u []float64) {
+	for i, I := range o.eqs.UtoF {
+		yu[i] = u[I]
+	}
+}
+
+// join sets the values at all nodes, including the prescribed values at time t
+func (o *fdmMol) join(u, v []float64, t float64, y la.Vector) {
+	nu := o.eqs.Nu
+	for i, I := range o.eqs.UtoF {
+		u[I] = y[i]
+		if v != nil {
+			v[I] = y[nu+i]
+		}
+	}
+	for _, I := range o.eqs.KtoF {
+		u[I] = o.op.calcXk(I, t)
+		if v != nil {
+			v[I], _ = o.op.EssenBcs.Rate(I, 0, t)
+		}
+	}
+}
+
+// molRun runs the ODE solver from t = 0 to tf
+//  snap -- takes snapshots of y at time t (see the Solve methods) [may be nil]
+//  Output:
+//    stat -- statistics of the ODE solver
+//    t    -- final time reached; i.e. tf or the time of the snapshot stopping the simulation.
+//            In the latter case, y holds the values at this snapshot
+func molRun(method string, atol, rtol float64, fcn ode.Func, jac ode.JacF, y la.Vector,
+	tf, dt, dtOut float64, snap func(t float64, y la.Vector) bool) (stat *ode.Stat, t float64) {
+
+	// configuration
+	if tf <= 0 {
+		chk.Panic("final time must be positive. tf = %g is invalid\n", tf)
+	}
+	conf := ode.NewConfig(method, "", nil)
+	conf.SetTols(atol, rtol)
+	if dt > 0 {
+		conf.SetFixedH(dt, tf)
+	}
+
+	// snapshots
+	t = tf
+	var ystop la.Vector
+	if snap != nil {
+		output := snap
+		snap = func(tout float64, yout la.Vector) (stop bool) {
+			stop = output(tout, yout)
+			if stop {
+				t, ystop = tout, yout.GetCopy()
+			}
+			return
+		}
+		switch {
+		case dtOut <= 0:
+			conf.SetStepOut(false, func(istep int, h, t float64, y la.Vector) (stop bool) {
+				return snap(t, y)
+			})
+		case dt > 0:
+			tout := 0.0
+			conf.SetStepOut(false, func(istep int, h, t float64, y la.Vector) (stop bool) {
+				tol := 1e-10 * h
+				if t < tout-tol && math.Abs(t-tf) > tol {
+					return
+				}
+				for tout <= t+tol {
+					tout += dtOut
+				}
+				return snap(t, y)
+			})
+		default:
+			conf.SetDenseOut(false, dtOut, tf, func(istep int, h, t float64, y la.Vector, tout float64, yout la.Vector) (stop bool) {
+				return snap(tout, yout)
+			})
+		}
+	}
+
+	// solve
+	sol := ode.NewSolver(len(y), conf, fcn, jac, nil)
+	defer sol.Free()
+	sol.Solve(y, 0, tf)
+	if ystop != nil {
+		y.Apply(1, ystop)
+	}
+	return sol.Stat, t
+}
@@ -0,0 +1,588 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/ode"
+)
+
+// SpcBasis holds the collocation points and the differentiation matrices along one direction
+//  "cheb"    -- Chebyshev-Gauss-Lobatto points on the bounded interval [xmin, xmax] (including
+//               the end points) computed with fun.ChebyInterp
+//  "fourier" -- equally spaced points on the periodic interval [xmin, xmax) computed with
+//               fun.FourierInterp
+//
+//  NOTE: the points are sorted in ascending order
+type SpcBasis struct {
+	Periodic bool       // Fourier (periodic) basis; otherwise Chebyshev
+	Xmin     float64    // min x
+	Xmax     float64    // max x (excluded if periodic)
+	X        la.Vector  // collocation points
+	D1       *la.Matrix // first derivative matrix: (dℓj/dx)(xi)
+	D2       *la.Matrix // second derivative matrix: (d²ℓj/dx²)(xi)
+}
+
+// NewSpcBasis returns a new basis for spectral collocation along one direction
+//  kind -- "cheb" or "fourier"
+//  N    -- degree of polynomial ("cheb" ⇒ N+1 points) or number of points ("fourier"; N even)
+//  xmin -- min x
+//  xmax -- max x (period = xmax - xmin with "fourier")
+func NewSpcBasis(kind string, N int, xmin, xmax float64) (o *SpcBasis) {
+	if xmax <= xmin {
+		chk.Panic("xmax must be greater than xmin. xmin=%g and xmax=%g are invalid\n", xmin, xmax)
+	}
+	o = new(SpcBasis)
+	o.Xmin, o.Xmax = xmin, xmax
+	L := xmax - xmin
+	switch kind {
+
+	// Chebyshev: x = xmin + (1 - X) L / 2 since ChebyInterp.X goes from +1 to -1
+	case "cheb":
+		if N < 2 {
+			chk.Panic("degree of polynomial must be at least 2. N=%d is invalid\n", N)
+		}
+		ci := fun.NewChebyInterp(N, false)
+		ci.CalcD2()
+		o.X = la.NewVector(N + 1)
+		o.D1 = la.NewMatrix(N+1, N+1)
+		o.D2 = la.NewMatrix(N+1, N+1)
+		c := -2.0 / L
+		for i := 0; i < N+1; i++ {
+			o.X[i] = xmin + (1-ci.X[i])*L/2
+			for j := 0; j < N+1; j++ {
+				o.D1.Set(i, j, c*ci.D1.Get(i, j))
+				o.D2.Set(i, j, c*c*ci.D2.Get(i, j))
+			}
+		}
+
+	// Fourier: x = xmin + X L / (2 π). The columns of D1 and D2 are the derivatives of the
+	// interpolants of the unit vectors
+	case "fourier":
+		o.Periodic = true
+		fi := fun.NewFourierInterp(N, "")
+		defer fi.Free()
+		fi.U = la.NewVector(N)
+		o.X = la.NewVector(N)
+		o.D1 = la.NewMatrix(N, N)
+		o.D2 = la.NewMatrix(N, N)
+		c := 2.0 * math.Pi / L
+		for j := 0; j < N; j++ {
+			o.X[j] = xmin + fi.X[j]/c
+			fi.U.Fill(0)
+			fi.U[j] = 1
+			fi.CalcA()
+			fi.CalcD1()
+			fi.CalcD2()
+			for i := 0; i < N; i++ {
+				o.D1.Set(i, j, c*fi.Du1[i])
+				o.D2.Set(i, j, c*c*fi.Du2[i])
+			}
+		}
+
+	default:
+		chk.Panic("cannot find basis kind %q. options are \"cheb\" and \"fourier\"\n", kind)
+	}
+	return
+}
+
+// Spectral implements spectral collocation solvers on tensor-product domains (1D or 2D)
+//
+//  The linear operator is
+//
+//              ∂²u        ∂²u        ∂u        ∂u
+//    L{u} = kx ———  +  ky ———  -  vx ——  -  vy ——  +  a u
+//              ∂x²        ∂y²        ∂x        ∂y
+//
+//  The steady problem (e.g. Poisson, Helmholtz, advection-diffusion) is
+//
+//                  ∂u     ∂u
+//    L{u} - b u ⋅ (——  +  ——) = s({x})
+//                  ∂x     ∂y
+//
+//  and the transient problem (e.g. advection-diffusion or Burgers with b = 1) is
+//
+//    ∂u                    ∂u     ∂u
+//    ——— = L{u} - b u ⋅ (——  +  ——) - s({x},t)
+//    ∂t                    ∂x     ∂y
+//
+//  The bounded (Chebyshev) directions require boundary conditions; these are imposed by
+//  replacing the collocation equations of the boundary nodes by the boundary equations (boundary
+//  bordering). The natural boundary conditions are
+//
+//    n ⋅ (K ∇u) = q      with      K = diag(kx, ky)
+//
+//  where n is the unit outward normal. Boundaries without prescribed conditions are insulated
+//  (q = 0). The boundary tags are 10 (x=xmin), 11 (x=xmax), 20 (y=ymin) and 21 (y=ymax). At
+//  corners, essential conditions have priority; otherwise, the first added condition is used.
+//
+//  The nodes are numbered with I = i + j ⋅ nx where i and j are the indices along x and y.
+//
+//  The transient problem is solved with the method of lines after the values at boundary nodes
+//  are condensed out. The dense Jacobian is computed analytically.
+type Spectral struct {
+	Kx     float64     // diffusion coefficient x
+	Ky     float64     // diffusion coefficient y
+	Vx     float64     // advection velocity x
+	Vy     float64     // advection velocity y
+	A      float64     // reaction (Helmholtz) coefficient
+	B      float64     // coefficient of nonlinear convective (Burgers) term
+	Basis  []*SpcBasis // [ndim] basis along each direction
+	Source fun.Svs     // source term function s({x},t) [may be nil]
+
+	// transient analyses
+	Method string    // ODE method; e.g. "radau5", "bweuler", "rk4", "dopri5" [default = "radau5"]
+	Atol   float64   // absolute tolerance for methods with variable steps [default = 1e-6]
+	Rtol   float64   // relative tolerance for methods with variable steps [default = 1e-6]
+	Stat   *ode.Stat // statistics of the last run
+
+	// Newton's method (nonlinear steady problems)
+	NewtonTol    float64 // tolerance on the norm of corrections [default = 1e-10]
+	NewtonMaxIts int     // max number of iterations [default = 20]
+
+	// derived
+	ndim  int        // space dimension
+	npts  int        // total number of nodes
+	dx    *la.Matrix // [npts][npts] ∂/∂x
+	dy    *la.Matrix // [npts][npts] ∂/∂y [nil in 1D]
+	sdu   *la.Matrix // [npts][npts] ∂/∂x + ∂/∂y
+	lop   *la.Matrix // [npts][npts] linear operator L
+	bcs   []*spcBc   // boundary conditions
+	nbry  []*spcBc   // [npts] condition applied at node [nil ⇒ interior or periodic]
+	ready bool       // operator and boundary equations are ready
+
+	// condensation of boundary nodes (transient analyses)
+	utoF []int      // [nu] unknown ⇒ full
+	ktoF []int      // [nk] boundary ⇒ full
+	pmat *la.Matrix // [nk][nk] inverse of boundary equations matrix [Ckk]⁻¹
+	qmat *la.Matrix // [nk][nu] -[Ckk]⁻¹⋅[Cku]
+	uful la.Vector  // [npts] workspace: all values
+	gk   la.Vector  // [nk] workspace: boundary values
+	wful la.Vector  // [npts] workspace
+}
+
+// spcBc holds the data of a boundary condition
+type spcBc struct {
+	essential bool    // essential condition; otherwise natural
+	tag       int     // boundary tag
+	cvalue    float64 // constant value
+	fvalue    dbf.T   // function value [may be nil]
+}
+
+// value returns the prescribed value
+func (o *spcBc) value(t float64, x la.Vector) float64 {
+	if o.fvalue != nil {
+		return o.fvalue.F(t, x)
+	}
+	return o.cvalue
+}
+
+// NewSpectral returns a new spectral collocation solver
+//  params -- "kx" and "ky" (2D); "vx", "vy", "a" and "b" [optional]
+//  basis  -- [ndim] basis along each direction
+//  source -- source term s({x},t) [may be nil]
+func NewSpectral(params dbf.Params, basis []*SpcBasis, source fun.Svs) (o *Spectral) {
+	if len(basis) < 1 || len(basis) > 2 {
+		chk.Panic("number of basis (space dimension) must be 1 or 2. %d is invalid\n", len(basis))
+	}
+	o = new(Spectral)
+	o.Basis = basis
+	o.Source = source
+	o.ndim = len(basis)
+	err := params.ConnectSetOpt(
+		[]*float64{&o.Kx, &o.Ky, &o.Vx, &o.Vy, &o.A, &o.B},
+		[]string{"kx", "ky", "vx", "vy", "a", "b"},
+		[]bool{false, o.ndim == 1, true, true, true, true},
+		"Spectral",
+	)
+	if err != "" {
+		chk.Panic(err)
+	}
+	o.Method = "radau5"
+	o.Atol, o.Rtol = 1e-6, 1e-6
+	o.NewtonTol, o.NewtonMaxIts = 1e-10, 20
+	o.npts = 1
+	for _, b := range basis {
+		o.npts *= len(b.X)
+	}
+	return
+}
+
+// Npts returns the number of points along direction idim
+func (o *Spectral) Npts(idim int) int {
+	return len(o.Basis[idim].X)
+}
+
+// Size returns the total number of nodes
+func (o *Spectral) Size() int {
+	return o.npts
+}
+
+// Node returns the coordinates of node I
+func (o *Spectral) Node(I int) (x la.Vector) {
+	nx := len(o.Basis[0].X)
+	x = la.NewVector(o.ndim)
+	x[0] = o.Basis[0].X[I%nx]
+	if o.ndim == 2 {
+		x[1] = o.Basis[1].X[I/nx]
+	}
+	return
+}
+
+// AddBc adds essential or natural boundary condition
+//  essential -- essential BC; otherwise natural (Neumann) boundary condition: n ⋅ (K ∇u) = q
+//  tag       -- 10 (x=xmin), 11 (x=xmax), 20 (y=ymin) or 21 (y=ymax)
+//  cvalue    -- constant value [optional]; or
+//  fvalue    -- function value [optional]
+func (o *Spectral) AddBc(essential bool, tag int, cvalue float64, fvalue dbf.T) {
+	idim := tag/10 - 1
+	if idim < 0 || idim >= o.ndim || tag%10 > 1 {
+		chk.Panic("boundary tag %d is invalid\n", tag)
+	}
+	if o.Basis[idim].Periodic {
+		chk.Panic("cannot set boundary condition with tag %d along periodic direction\n", tag)
+	}
+	o.bcs = append(o.bcs, &spcBc{essential, tag, cvalue, fvalue})
+	o.ready = false
+}
+
+// Assemble computes the differentiation matrices, the operator and the boundary equations
+func (o *Spectral) Assemble() {
+
+	// differentiation matrices
+	nx := len(o.Basis[0].X)
+	o.dx = la.NewMatrix(o.npts, o.npts)
+	dxx := la.NewMatrix(o.npts, o.npts)
+	var dyy *la.Matrix
+	if o.ndim == 1 {
+		o.Basis[0].D1.CopyInto(o.dx, 1)
+		o.Basis[0].D2.CopyInto(dxx, 1)
+	} else {
+		ny := len(o.Basis[1].X)
+		o.dy = la.NewMatrix(o.npts, o.npts)
+		dyy = la.NewMatrix(o.npts, o.npts)
+		for j := 0; j < ny; j++ {
+			for i := 0; i < nx; i++ {
+				I := i + j*nx
+				for k := 0; k < nx; k++ {
+					J := k + j*nx
+					o.dx.Set(I, J, o.Basis[0].D1.Get(i, k))
+					dxx.Set(I, J, o.Basis[0].D2.Get(i, k))
+				}
+				for l := 0; l < ny; l++ {
+					J := i + l*nx
+					o.dy.Set(I, J, o.Basis[1].D1.Get(j, l))
+					dyy.Set(I, J, o.Basis[1].D2.Get(j, l))
+				}
+			}
+		}
+	}
+
+	// operator
+	o.lop = la.NewMatrix(o.npts, o.npts)
+	o.sdu = la.NewMatrix(o.npts, o.npts)
+	for I := 0; I < o.npts; I++ {
+		for J := 0; J < o.npts; J++ {
+			v := o.Kx*dxx.Get(I, J) - o.Vx*o.dx.Get(I, J)
+			s := o.dx.Get(I, J)
+			if o.ndim == 2 {
+				v += o.Ky*dyy.Get(I, J) - o.Vy*o.dy.Get(I, J)
+				s += o.dy.Get(I, J)
+			}
+			o.lop.Set(I, J, v)
+			o.sdu.Set(I, J, s)
+		}
+		o.lop.Add(I, I, o.A)
+	}
+
+	// workspace
+	o.uful = la.NewVector(o.npts)
+	o.wful = la.NewVector(o.npts)
+
+	// boundary conditions at nodes
+	o.nbry = make([]*spcBc, o.npts)
+	insulated := make(map[int]*spcBc)
+	for I := 0; I < o.npts; I++ {
+		tags := o.nodeTags(I)
+		for _, essential := range []bool{true, false} {
+			for _, bc := range o.bcs {
+				if o.nbry[I] == nil && bc.essential == essential && (tags[0] == bc.tag || tags[1] == bc.tag) {
+					o.nbry[I] = bc
+				}
+			}
+		}
+		if o.nbry[I] == nil && tags[0]+tags[1] > 0 {
+			tag := tags[0]
+			if tag == 0 {
+				tag = tags[1]
+			}
+			if insulated[tag] == nil {
+				insulated[tag] = &spcBc{false, tag, 0, nil}
+			}
+			o.nbry[I] = insulated[tag]
+		}
+	}
+	o.ready = true
+}
+
+// SolveSteady solves the steady problem
+//  INPUT:
+//    u -- initial values for the iterations of the nonlinear problem (b ≠ 0) [may be nil ⇒ zero]
+//  OUTPUT:
+//    u -- values of u at all nodes (newly allocated if nil on input)
+func (o *Spectral) SolveSteady(u []float64) []float64 {
+	if !o.ready {
+		o.Assemble()
+	}
+	if u == nil {
+		u = make([]float64, o.npts)
+	}
+	r := la.NewVector(o.npts)
+	du := la.NewVector(o.npts)
+	jac := la.NewMatrix(o.npts, o.npts)
+	for it := 0; it < o.NewtonMaxIts; it++ {
+		o.residual(r, 0, u)
+		o.jacobian(jac, u)
+		la.DenSolve(du, jac, r, false)
+		for I := range u {
+			u[I] -= du[I]
+		}
+		if o.B == 0 || du.Norm() <= o.NewtonTol*(1+la.Vector(u).Norm()) {
+			return u
+		}
+	}
+	chk.Panic("Newton's method did not converge after %d iterations\n", o.NewtonMaxIts)
+	return nil
+}
+
+// Fcn computes d{uu}/dt = {f}(t,{uu}) where {uu} are the values at the nodes without boundary
+// conditions (see ode.Func)
+func (o *Spectral) Fcn(f la.Vector, h, t float64, y la.Vector) {
+	o.join(o.uful, t, y)
+	o.residual(o.wful, t, o.uful)
+	for i, I := range o.utoF {
+		f[i] = o.wful[I]
+	}
+}
+
+// Jac computes the dense Jacobian d{f}/d{uu} (see ode.JacF)
+//
+//    d{f}
+//    ————— = [Juu] + [Juk]⋅[Q]      with      [Q] = -[Ckk]⁻¹⋅[Cku]
+//    d{uu}
+//
+//  where [J] is the Jacobian of the collocation equations and [C] is the matrix of boundary
+//  equations
+func (o *Spectral) Jac(dfdy *la.Triplet, h, t float64, y la.Vector) {
+	nu := len(o.utoF)
+	if dfdy.Max() == 0 {
+		dfdy.Init(nu, nu, nu*nu)
+	}
+	dfdy.Start()
+	o.join(o.uful, t, y)
+	o.wful.Fill(0)
+	la.MatVecMul(o.wful, 1, o.sdu, o.uful)
+	for i, I := range o.utoF {
+		for j, J := range o.utoF {
+			v := o.jacobianEntry(I, J)
+			for k, K := range o.ktoF {
+				v += o.jacobianEntry(I, K) * o.qmat.Get(k, j)
+			}
+			dfdy.Put(i, j, v)
+		}
+	}
+}
+
+// Solve solves the transient problem from t = 0 to tf
+//  INPUT:
+//    u     -- initial values of u at all nodes (the values at the boundary nodes are replaced
+//             by the values satisfying the boundary conditions)
+//    tf    -- final time
+//    dt    -- time step for methods with fixed steps (e.g. "bweuler" or "rk4") [0 ⇒ variable steps]
+//    dtOut -- time increment for snapshots [0 ⇒ snapshots at all steps]
+//    out   -- callback function to process snapshots [may be nil]. v is nil
+//
+//  OUTPUT:
+//    u -- final values of u at all nodes
+//
+//  NOTE: see FdmHeat.Solve regarding the snapshots
+func (o *Spectral) Solve(u []float64, tf, dt, dtOut float64, out MolOutF) {
+	o.condense()
+	if len(o.utoF) == 0 {
+		chk.Panic("all nodes are on boundaries; there is nothing to solve\n")
+	}
+	y := la.NewVector(len(o.utoF))
+	for i, I := range o.utoF {
+		y[i] = u[I]
+	}
+	var snap func(t float64, y la.Vector) bool
+	if out != nil {
+		idx := 0
+		snap = func(t float64, y la.Vector) (stop bool) {
+			o.join(u, t, y)
+			stop = out(idx, t, u, nil)
+			idx++
+			return
+		}
+	}
+	var t float64
+	o.Stat, t = molRun(o.Method, o.Atol, o.Rtol, o.Fcn, o.Jac, y, tf, dt, dtOut, snap)
+	o.join(u, t, y)
+}
+
+// auxiliary //////////////////////////////////////////////////////////////////////////////////////
+
+// nodeTags returns the boundary tags of node I (zero if not on boundary or periodic)
+func (o *Spectral) nodeTags(I int) (tags [2]int) {
+	nx := len(o.Basis[0].X)
+	ij := []int{I % nx, I / nx}
+	for idim := 0; idim < o.ndim; idim++ {
+		if o.Basis[idim].Periodic {
+			continue
+		}
+		switch ij[idim] {
+		case 0:
+			tags[idim] = 10 * (idim + 1)
+		case len(o.Basis[idim].X) - 1:
+			tags[idim] = 10*(idim+1) + 1
+		}
+	}
+	return
+}
+
+// bryEntry returns the coefficient of u[J] in the boundary equation of node I
+func (o *Spectral) bryEntry(I, J int) float64 {
+	bc := o.nbry[I]
+	if bc.essential {
+		if I == J {
+			return 1
+		}
+		return 0
+	}
+	switch bc.tag {
+	case 10:
+		return -o.Kx * o.dx.Get(I, J)
+	case 11:
+		return o.Kx * o.dx.Get(I, J)
+	case 20:
+		return -o.Ky * o.dy.Get(I, J)
+	}
+	return o.Ky * o.dy.Get(I, J)
+}
+
+// residual computes the residual of the collocation and boundary equations
+//  rᵢ = L{u}ᵢ - b uᵢ wᵢ - sᵢ      with      w = ∂u/∂x + ∂u/∂y   (interior)
+//  rᵢ = Σ Cᵢⱼ uⱼ - gᵢ                                            (boundary)
+func (o *Spectral) residual(r la.Vector, t float64, u la.Vector) {
+	r.Fill(0)
+	la.MatVecMul(r, 1, o.lop, u)
+	if o.B != 0 {
+		w := la.NewVector(o.npts)
+		la.MatVecMul(w, 1, o.sdu, u)
+		for I := range r {
+			r[I] -= o.B * u[I] * w[I]
+		}
+	}
+	for I := range r {
+		if o.nbry[I] != nil {
+			r[I] = -o.nbry[I].value(t, o.Node(I))
+			for J := 0; J < o.npts; J++ {
+				r[I] += o.bryEntry(I, J) * u[J]
+			}
+			continue
+		}
+		if o.Source != nil {
+			r[I] -= o.Source(o.Node(I), t)
+		}
+	}
+}
+
+// jacobian computes the Jacobian of the collocation and boundary equations
+func (o *Spectral) jacobian(jac *la.Matrix, u la.Vector) {
+	copy(o.uful, u)
+	o.wful.Fill(0)
+	la.MatVecMul(o.wful, 1, o.sdu, o.uful)
+	for I := 0; I < o.npts; I++ {
+		for J := 0; J < o.npts; J++ {
+			if o.nbry[I] != nil {
+				jac.Set(I, J, o.bryEntry(I, J))
+			} else {
+				jac.Set(I, J, o.jacobianEntry(I, J))
+			}
+		}
+	}
+}
+
+// jacobianEntry returns ∂rᵢ/∂uⱼ of the collocation equation of node I with u and w = ∂u/∂x + ∂u/∂y
+// given in uful and wful
+func (o *Spectral) jacobianEntry(I, J int) float64 {
+	v := o.lop.Get(I, J)
+	if o.B != 0 {
+		v -= o.B * o.uful[I] * o.sdu.Get(I, J)
+		if I == J {
+			v -= o.B * o.wful[I]
+		}
+	}
+	return v
+}
+
+// condense computes the matrices to condense out the values at boundary nodes
+func (o *Spectral) condense() {
+	if !o.ready {
+		o.Assemble()
+	}
+	o.utoF, o.ktoF = nil, nil
+	for I := 0; I < o.npts; I++ {
+		if o.nbry[I] == nil {
+			o.utoF = append(o.utoF, I)
+		} else {
+			o.ktoF = append(o.ktoF, I)
+		}
+	}
+	nu, nk := len(o.utoF), len(o.ktoF)
+	o.gk = la.NewVector(nk)
+	if nk == 0 {
+		return
+	}
+	ckk := la.NewMatrix(nk, nk)
+	cku := la.NewMatrix(nk, nu)
+	for k, K := range o.ktoF {
+		for l, L := range o.ktoF {
+			ckk.Set(k, l, o.bryEntry(K, L))
+		}
+		for j, J := range o.utoF {
+			cku.Set(k, j, o.bryEntry(K, J))
+		}
+	}
+	o.pmat = la.NewMatrix(nk, nk)
+	o.qmat = la.NewMatrix(nk, nu)
+	la.MatInv(o.pmat, ckk, false)
+	la.MatMatMul(o.qmat, -1, o.pmat, cku)
+}
+
+// join sets the values at all nodes, including the values at boundary nodes at time t
+//  {uk} = [Ckk]⁻¹⋅{g}(t) + [Q]⋅{uu}
+func (o *Spectral) join(u []float64, t float64, y la.Vector) {
+	for i, I := range o.utoF {
+		u[I] = y[i]
+	}
+	if len(o.ktoF) == 0 {
+		return
+	}
+	for k, K := range o.ktoF {
+		o.gk[k] = o.nbry[K].value(t, o.Node(K))
+	}
+	uk := la.NewVector(len(o.ktoF))
+	la.MatVecMul(uk, 1, o.pmat, o.gk)
+	la.MatVecMulAdd(uk, 1, o.qmat, y)
+	for k, K := range o.ktoF {
+		u[K] = uk[k]
+	}
+}
@@ -5,6 +5,7 @@
 package pde
 
 import (
+	"math"
 	"testing"
 
 	"github.com/cpmech/gosl/chk"
@@ -13,6 +14,7 @@ import (
 	"github.com/cpmech/gosl/io"
 	"github.com/cpmech/gosl/la"
 	"github.com/cpmech/gosl/plt"
+	"github.com/cpmech/gosl/utl"
 )
 
 func TestFdm01(tst *testing.T) {
@@ -136,3 +138,140 @@ func TestFdm02(tst *testing.T) {
 		plt.Save("/tmp/gosl/pde", "fdm02")
 	}
 }
+
+func TestFdm03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Fdm03. variable k with Neumann and Robin conditions")
+
+	// solve problem
+	//    ∇⋅(k ∇u) = s    with   k = 1 + x y   and   u = exp(x) sin(y)  (∇²u = 0)
+	//    s = ∇k ⋅ ∇u = y exp(x) sin(y) + x exp(x) cos(y)
+	kvar := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "1 + x[0]*x[1]"}})
+	uana := func(x []float64) float64 { return math.Exp(x[0]) * math.Sin(x[1]) }
+	source := func(x la.Vector, t float64) float64 {
+		return x[1]*math.Exp(x[0])*math.Sin(x[1]) + x[0]*math.Exp(x[0])*math.Cos(x[1])
+	}
+	fluxX := "(1+x[0]*x[1])*exp(x[0])*sin(x[1])" // k ∂u/∂x
+	fluxY := "(1+x[0]*x[1])*exp(x[0])*cos(x[1])" // k ∂u/∂y
+	β := 2.0
+
+	// run with increasing number of points
+	var errs []float64
+	for _, npts := range []int{9, 17, 33} {
+		g := new(gm.Grid)
+		g.RectGenUniform([]float64{0, 0}, []float64{1, 1}, []int{npts, npts})
+		s := NewFdmLaplacian(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, g, source)
+		s.Kvar = kvar
+		s.AddBc(true, 10, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "exp(x[0])*sin(x[1])"}}))
+		s.AddBc(false, 11, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: fluxX}}))
+		s.AddBc(false, 20, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "-" + fluxY}}))
+		s.AddRobinBc(21, β, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: fluxY + "+b*exp(x[0])*sin(x[1])"}, {N: "b", V: β}}))
+		s.Assemble(false)
+		u, _ := s.SolveSteady(false)
+		maxerr := 0.0
+		for I := 0; I < g.Size(); I++ {
+			maxerr = math.Max(maxerr, math.Abs(u[I]-uana(g.Node(I))))
+		}
+		io.Pf("npts = %3d  maxerr = %.6e\n", npts, maxerr)
+		errs = append(errs, maxerr)
+	}
+	for i := 1; i < len(errs); i++ {
+		rate := math.Log2(errs[i-1] / errs[i])
+		io.Pforan("rate = %.4f\n", rate)
+		if rate < 1.9 {
+			tst.Errorf("convergence rate must be approximately 2. %g is invalid\n", rate)
+		}
+	}
+	if errs[len(errs)-1] > 5e-4 {
+		tst.Errorf("error is too large: %g\n", errs[len(errs)-1])
+	}
+}
+
+func TestFdm04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Fdm04. curvilinear grid: quarter ring")
+
+	// solve problem
+	//    ∇⋅(k ∇u) = 0    with   k = 1 + r²   ⇒   u = ln(r) - ln(1+r²)/2 + ln(2)/2
+	//    u = 0 @ r = a = 1    and   k ∂u/∂r + β u = q @ r = b = 2
+	//    the straight edges are insulated
+	a, b := 1.0, 2.0
+	uana := func(r float64) float64 { return math.Log(r) - math.Log(1+r*r)/2 + math.Log(2)/2 }
+	β := 1.0
+	q := 1.0/b + β*uana(b)
+	trf := gm.FactoryTfinite.Surf2dQuarterRing(a, b)
+
+	// run with increasing number of points
+	var errs []float64
+	var g *gm.Grid
+	var u []float64
+	for _, npts := range []int{9, 17, 33} {
+		g = new(gm.Grid)
+		g.SetTransfinite2d(trf, utl.LinSpace(-1, 1, npts), utl.LinSpace(-1, 1, npts))
+		s := NewFdmLaplacian(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, g, nil)
+		s.Kvar = dbf.New("expr", []*dbf.P{{N: "expr", Extra: "1 + x[0]^2 + x[1]^2"}})
+		s.AddBc(true, 10, 0, nil)
+		s.AddRobinBc(11, β, q, nil)
+		s.Assemble(false)
+		u, _ = s.SolveSteady(false)
+		maxerr := 0.0
+		for I := 0; I < g.Size(); I++ {
+			maxerr = math.Max(maxerr, math.Abs(u[I]-uana(g.Node(I).Norm())))
+		}
+		io.Pf("npts = %3d  maxerr = %.6e\n", npts, maxerr)
+		errs = append(errs, maxerr)
+	}
+	for i := 1; i < len(errs); i++ {
+		rate := math.Log2(errs[i-1] / errs[i])
+		io.Pforan("rate = %.4f\n", rate)
+		if rate < 1.8 {
+			tst.Errorf("convergence rate must be approximately 2. %g is invalid\n", rate)
+		}
+	}
+	if errs[len(errs)-1] > 1e-4 {
+		tst.Errorf("error is too large: %g\n", errs[len(errs)-1])
+	}
+
+	// plot
+	if chk.Verbose {
+		gp := gm.GridPlotter{G: g}
+		plt.Reset(true, &plt.A{WidthPt: 400, Dpi: 150})
+		gp.Draw()
+		plt.ContourF(gp.X2d, gp.Y2d, g.MapMeshgrid2d(u), nil)
+		plt.Gll("$x$", "$y$", nil)
+		plt.Equal()
+		plt.HideAllBorders()
+		plt.Save("/tmp/gosl/pde", "fdm04")
+	}
+}
+
+func TestFdm05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Fdm05. 3D with natural boundary conditions")
+
+	// solve problem
+	//    ∇²u = 0    with   u = x² + y² - 2z² + x y   (reproduced exactly)
+	g := new(gm.Grid)
+	g.RectGenUniform([]float64{0, 0, 0}, []float64{1, 2, 1}, []int{5, 6, 4})
+	s := NewFdmLaplacian(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}, {N: "kz", V: 1}}, g, nil)
+	expr := func(str string) dbf.T {
+		return dbf.New("expr", []*dbf.P{{N: "expr", Extra: str}})
+	}
+	s.AddBc(true, 300, 0, expr("x[0]^2 + x[1]^2 + x[0]*x[1]"))
+	s.AddBc(false, 100, 0, expr("-(2*x[0] + x[1])"))
+	s.AddRobinBc(101, 3, 0, expr("2*x[0] + x[1] + 3*(x[0]^2 + x[1]^2 - 2*x[2]^2 + x[0]*x[1])"))
+	s.AddBc(false, 200, 0, expr("-(2*x[1] + x[0])"))
+	s.AddBc(false, 201, 0, expr("2*x[1] + x[0]"))
+	s.AddBc(false, 301, 0, expr("-4*x[2]"))
+	s.Assemble(false)
+	u, _ := s.SolveSteady(false)
+	uana := make([]float64, g.Size())
+	for I := 0; I < g.Size(); I++ {
+		x := g.Node(I)
+		uana[I] = x[0]*x[0] + x[1]*x[1] - 2*x[2]*x[2] + x[0]*x[1]
+	}
+	chk.Array(tst, "u", 1e-12, u, uana)
+}
@@ -0,0 +1,270 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestFdmOperator01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FdmOperator01. Fornberg weights and stencils")
+
+	// central stencils (4th order)
+	c := fdmWeights(0, []float64{-2, -1, 0, 1, 2}, 2)
+	chk.Array(tst, "c0", 1e-15, c[0], []float64{0, 0, 1, 0, 0})
+	chk.Array(tst, "c1", 1e-15, c[1], []float64{1.0 / 12, -2.0 / 3, 0, 2.0 / 3, -1.0 / 12})
+	chk.Array(tst, "c2", 1e-14, c[2], []float64{-1.0 / 12, 4.0 / 3, -5.0 / 2, 4.0 / 3, -1.0 / 12})
+
+	// one-sided stencils (2nd order)
+	c = fdmWeights(0, []float64{0, 0.5, 1}, 2)
+	chk.Array(tst, "one-sided c1", 1e-15, c[1], []float64{-3, 4, -1})
+	chk.Array(tst, "one-sided c2", 1e-14, c[2], []float64{4, -8, 4})
+
+	// non-uniform points: exact for polynomials of degree len(x)-1
+	x := []float64{-0.3, 0.1, 0.25, 0.7, 1.2}
+	z := 0.4
+	c = fdmWeights(z, x, 2)
+	d1, d2 := 0.0, 0.0
+	for j, xj := range x {
+		d1 += c[1][j] * math.Pow(xj, 4)
+		d2 += c[2][j] * math.Pow(xj, 4)
+	}
+	chk.Float64(tst, "d(x⁴)/dx", 1e-13, d1, 4*math.Pow(z, 3))
+	chk.Float64(tst, "d²(x⁴)/dx²", 1e-12, d2, 12*z*z)
+
+	// upwind-biased stencils of first derivative (h = 1)
+	g := new(gm.Grid)
+	g.RectGenUniform([]float64{0, 0}, []float64{8, 2}, []int{9, 3})
+	o := NewFdmOperator(g, nil)
+	for _, c := range []struct {
+		upwind  int
+		b       float64
+		offsets []int
+		weights []float64
+	}{
+		{1, +1, []int{0, 1}, []float64{-1, 1}},
+		{1, -1, []int{-1, 0}, []float64{-1, 1}},
+		{2, +1, []int{0, 1, 2}, []float64{-1.5, 2, -0.5}},
+		{2, -1, []int{-2, -1, 0}, []float64{0.5, -2, 1.5}},
+		{3, +1, []int{-1, 0, 1, 2}, []float64{-1.0 / 3, -0.5, 1, -1.0 / 6}},
+		{3, -1, []int{-2, -1, 0, 1}, []float64{1.0 / 6, -1, 0.5, 1.0 / 3}},
+	} {
+		offsets, weights := o.stencil(4, 0, 1, c.upwind, c.b)
+		chk.Ints(tst, "offsets", offsets, c.offsets)
+		chk.Array(tst, "weights", 1e-15, weights, c.weights)
+	}
+
+	// shifted stencils near boundaries
+	o.Order = 4
+	offsets, weights := o.stencil(1, 0, 2, 0, 0)
+	chk.Ints(tst, "offsets", offsets, []int{-1, 0, 1, 2, 3, 4})
+	chk.Array(tst, "weights", 1e-14, weights, []float64{5.0 / 6, -5.0 / 4, -1.0 / 3, 7.0 / 6, -1.0 / 2, 1.0 / 12})
+	offsets, _ = o.stencil(8, 0, 1, 1, -1)
+	chk.Ints(tst, "offsets", offsets, []int{-1, 0})
+	offsets, _ = o.stencil(8, 0, 1, 1, +1) // clamped
+	chk.Ints(tst, "offsets", offsets, []int{-1, 0})
+}
+
+func TestFdmOperator02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FdmOperator02. same matrix as FdmLaplacian")
+
+	// 2nd order stencils of the Laplacian on a non-uniform grid
+	g := new(gm.Grid)
+	g.RectSet2d([]float64{0, 0.2, 0.5, 0.7, 1}, []float64{0, 0.3, 0.4, 1})
+	lap := NewFdmLaplacian(dbf.Params{{N: "kx", V: 2}, {N: "ky", V: 3}}, g, nil)
+	op := NewFdmOperator(g, nil)
+	op.AddSecondDeriv(0, 0, 2, nil)
+	op.AddSecondDeriv(1, 1, 3, nil)
+	for _, tag := range []int{10, 11, 20, 21} {
+		lap.AddBc(true, tag, 0, nil)
+		op.AddBc(true, tag, 0, nil)
+	}
+	lap.Assemble(false)
+	op.Assemble(false)
+	chk.Deep2(tst, "Auu", 1e-12, op.Eqs.Auu.ToDense().GetDeep2(), lap.Eqs.Auu.ToDense().GetDeep2())
+	chk.Deep2(tst, "Auk", 1e-12, op.Eqs.Auk.ToDense().GetDeep2(), lap.Eqs.Auk.ToDense().GetDeep2())
+}
+
+func TestFdmOperator03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FdmOperator03. exact for polynomials (non-uniform grid)")
+
+	// solve problem
+	//            ∂²u        ∂²u         ∂²u    ∂u     ∂u
+	//    L{u} =  ———  + 2 ————— + 0.5 ———  +  ——— - ——— + u = s    with    u = wᵖ + x³ y²
+	//            ∂x²      ∂x ∂y       ∂y²    ∂x     ∂y
+	//
+	//  where w = 0.5 + x - 0.7 y and p is the order of the stencils. The solution is prescribed at
+	//  x = 0 and y = 0; the Neumann condition is used at y = 1 and the Robin condition at x = 1
+	for _, order := range []int{2, 4, 6} {
+		p := float64(order)
+		ana := func(x la.Vector) (u, ux, uy, uxx, uxy, uyy float64) {
+			w := 0.5 + x[0] - 0.7*x[1]
+			a, b := p*math.Pow(w, p-1), p*(p-1)*math.Pow(w, p-2)
+			u = math.Pow(w, p) + math.Pow(x[0], 3)*x[1]*x[1]
+			ux = a + 3*x[0]*x[0]*x[1]*x[1]
+			uy = -0.7*a + 2*math.Pow(x[0], 3)*x[1]
+			uxx = b + 6*x[0]*x[1]*x[1]
+			uxy = -0.7*b + 6*x[0]*x[0]*x[1]
+			uyy = 0.49*b + 2*math.Pow(x[0], 3)
+			if order == 2 { // the cubic terms are not reproduced by 2nd order stencils
+				u, ux, uy, uxx, uxy, uyy = math.Pow(w, p), a, -0.7*a, b, -0.7*b, 0.49*b
+			}
+			return
+		}
+		g := new(gm.Grid)
+		g.RectSet2d([]float64{0, 0.1, 0.25, 0.3, 0.5, 0.6, 0.8, 0.85, 1}, []float64{0, 0.15, 0.2, 0.4, 0.55, 0.7, 0.75, 0.9, 1})
+		o := NewFdmOperator(g, func(x la.Vector, t float64) float64 {
+			u, ux, uy, uxx, uxy, uyy := ana(x)
+			return uxx + 2*uxy + 0.5*uyy + ux - uy + u
+		})
+		o.Order = order
+		o.AddSecondDeriv(0, 0, 1, nil)
+		o.AddSecondDeriv(1, 0, 2, nil)
+		o.AddSecondDeriv(1, 1, 0.5, nil)
+		o.AddFirstDeriv(0, 0, 1, nil)
+		o.AddFirstDeriv(1, 0, -1, nil)
+		o.AddReaction(1, nil)
+		w, cub, cubx, cuby := "(0.5 + x[0] - 0.7*x[1])", "x[0]^3*x[1]^2", "3*x[0]^2*x[1]^2", "2*x[0]^3*x[1]"
+		if order == 2 {
+			cub, cubx, cuby = "0", "0", "0"
+		}
+		uexpr := io.Sf("%s^%d + %s", w, order, cub)
+		uxexpr := io.Sf("%d*%s^%d + %s", order, w, order-1, cubx)
+		uyexpr := io.Sf("-0.7*%d*%s^%d + %s", order, w, order-1, cuby)
+		ufcn := dbf.New("expr", []*dbf.P{{N: "expr", Extra: uexpr}})
+		o.AddBc(true, 10, 0, ufcn)
+		o.AddBc(true, 20, 0, ufcn)
+		o.AddBc(false, 21, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: uyexpr}}))
+		o.AddRobinBc(11, 2, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: uxexpr + " + 2*(" + uexpr + ")"}}))
+		o.Assemble(false)
+		u, _ := o.SolveSteady(false)
+		maxerr := 0.0
+		for I := range u {
+			ua, _, _, _, _, _ := ana(g.Node(I))
+			maxerr = math.Max(maxerr, math.Abs(u[I]-ua))
+		}
+		io.Pf("order = %d  max error = %.3e\n", order, maxerr)
+		if maxerr > 1e-9 {
+			tst.Errorf("order %d: error must be zero. %g is invalid\n", order, maxerr)
+		}
+	}
+}
+
+func TestFdmOperator04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FdmOperator04. convergence rates of 2nd, 4th and 6th order stencils")
+
+	// solve problem
+	//    ∂²u       ∂²u      ∂²u      ∂u
+	//    ———  +  ————— +  ———  +  2 ——— = s    with    u = sin(π x) sin(π y)    on [0,1] × [0,1]
+	//    ∂x²     ∂x ∂y     ∂y²       ∂x
+	//
+	//  with u = 0 on the boundary
+	π := math.Pi
+	source := func(x la.Vector, t float64) float64 {
+		sx, cx, sy, cy := math.Sin(π*x[0]), math.Cos(π*x[0]), math.Sin(π*x[1]), math.Cos(π*x[1])
+		return -2*π*π*sx*sy + π*π*cx*cy + 2*π*cx*sy
+	}
+	for _, order := range []int{2, 4, 6} {
+		var errs []float64
+		for _, n := range []int{9, 17, 33} {
+			g := new(gm.Grid)
+			g.RectGenUniform([]float64{0, 0}, []float64{1, 1}, []int{n, n})
+			o := NewFdmOperator(g, source)
+			o.Order = order
+			o.AddSecondDeriv(0, 0, 1, nil)
+			o.AddSecondDeriv(0, 1, 1, nil)
+			o.AddSecondDeriv(1, 1, 1, nil)
+			o.AddFirstDeriv(0, 0, 2, nil)
+			for _, tag := range []int{10, 11, 20, 21} {
+				o.AddBc(true, tag, 0, nil)
+			}
+			o.Assemble(false)
+			u, _ := o.SolveSteady(false)
+			maxerr := 0.0
+			for I := range u {
+				x := g.Node(I)
+				maxerr = math.Max(maxerr, math.Abs(u[I]-math.Sin(π*x[0])*math.Sin(π*x[1])))
+			}
+			errs = append(errs, maxerr)
+		}
+		for i := 1; i < len(errs); i++ {
+			rate := math.Log2(errs[i-1] / errs[i])
+			io.Pf("order = %d  error = %.3e  rate = %.4f\n", order, errs[i], rate)
+			if rate < float64(order)-0.3 {
+				tst.Errorf("order %d: convergence rate must be greater than %g. %g is invalid\n", order, float64(order)-0.3, rate)
+			}
+		}
+	}
+}
+
+func TestFdmOperator05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FdmOperator05. upwinding with high Péclet number")
+
+	// solve problem
+	//      ∂²u    ∂u
+	//    ε ——— + ——— = 0    with    u(0) = 0   and   u(1) = 1
+	//      ∂x²    ∂x
+	//
+	//    u = (1 - exp(-x/ε)) / (1 - exp(-1/ε))
+	//
+	//  with a boundary layer at x = 0 and zero derivatives along y
+	ε := 0.01
+	solve := func(upwind int) (u []float64, g *gm.Grid) {
+		g = new(gm.Grid)
+		g.RectGenUniform([]float64{0, 0}, []float64{1, 1}, []int{11, 3})
+		o := NewFdmOperator(g, nil)
+		o.AddSecondDeriv(0, 0, ε, nil)
+		o.AddFirstDeriv(0, upwind, 1, nil)
+		o.AddBc(true, 10, 0, nil)
+		o.AddBc(true, 11, 1, nil)
+		o.AddBc(false, 20, 0, nil)
+		o.AddBc(false, 21, 0, nil)
+		o.Assemble(false)
+		u, _ = o.SolveSteady(false)
+		return
+	}
+
+	// central differences oscillate (cell Péclet number = 5)
+	u, _ := solve(0)
+	umax := 0.0
+	for _, v := range u {
+		umax = math.Max(umax, v)
+	}
+	io.Pf("central: max(u) = %g\n", umax)
+	if umax < 1.5 {
+		tst.Errorf("central differences should overshoot\n")
+	}
+
+	// first order upwinding yields monotonic solution
+	u, g := solve(1)
+	for I := range u {
+		m, n, _ := g.IndexItoMNP(I)
+		chk.Float64(tst, "u(y)", 1e-12, u[I], u[m]) // same values along y
+		if m > 0 && n == 0 && u[I] < u[I-1] {
+			tst.Errorf("solution must increase monotonically\n")
+		}
+		if m > 3 { // outside boundary layer
+			x := g.Node(I)[0]
+			chk.Float64(tst, "u", 1e-4, u[I], (1-math.Exp(-x/ε))/(1-math.Exp(-1/ε)))
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// femHexMesh returns a mesh of hex8 on [0,1]×[0,1]×[0,1] with the inner vertices shifted
+//  The faces are tagged as follows: 100 ⇒ x=0, 101 ⇒ x=1, 200 ⇒ y=0, 201 ⇒ y=1, 300 ⇒ z=0 and
+//  301 ⇒ z=1
+func femHexMesh(ndiv int) (o *msh.Mesh) {
+	o = new(msh.Mesh)
+	n := ndiv + 1
+	h := 1.0 / float64(ndiv)
+	vid := func(i, j, k int) int { return i + j*n + k*n*n }
+	for k := 0; k < n; k++ {
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				x := []float64{float64(i) * h, float64(j) * h, float64(k) * h}
+				if i > 0 && i < ndiv && j > 0 && j < ndiv && k > 0 && k < ndiv {
+					x[0] += 0.2 * h
+					x[1] -= 0.1 * h
+					x[2] += 0.15 * h
+				}
+				o.Verts = append(o.Verts, &msh.Vertex{ID: len(o.Verts), X: x})
+			}
+		}
+	}
+	tag := func(m, bry, val int) int {
+		if m == bry {
+			return val
+		}
+		return 0
+	}
+	for k := 0; k < ndiv; k++ {
+		for j := 0; j < ndiv; j++ {
+			for i := 0; i < ndiv; i++ {
+				o.Cells = append(o.Cells, &msh.Cell{ID: len(o.Cells), Tag: -1, TypeKey: "hex8",
+					V: []int{
+						vid(i, j, k), vid(i+1, j, k), vid(i+1, j+1, k), vid(i, j+1, k),
+						vid(i, j, k+1), vid(i+1, j, k+1), vid(i+1, j+1, k+1), vid(i, j+1, k+1),
+					},
+					FaceTags: []int{
+						tag(i, 0, 100), tag(i, ndiv-1, 101),
+						tag(j, 0, 200), tag(j, ndiv-1, 201),
+						tag(k, 0, 300), tag(k, ndiv-1, 301),
+					},
+				})
+			}
+		}
+	}
+	o.CheckAndCalcDerivedVars()
+	return
+}
+
+func TestFemElastic01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemElastic01. patch test: uniaxial traction in 2D")
+
+	// solve problem
+	//    unit square with rollers @ left and bottom and traction tx = σ @ right
+	//    plane-strain: εxx = (1-ν²) σ / E  and  εyy = -ν (1+ν) σ / E
+	//    plane-stress: εxx = σ / E         and  εyy = -ν σ / E
+	E, ν, σ := 1000.0, 0.25, 3.0
+	quads := femDistortedMesh(4)
+	for _, mesh := range []*msh.Mesh{quads, femTriMesh(quads)} {
+		for _, pstress := range []bool{false, true} {
+			εxx, εyy := (1-ν*ν)*σ/E, -ν*(1+ν)*σ/E
+			if pstress {
+				εxx, εyy = σ/E, -ν*σ/E
+			}
+			s := NewFemElastic(dbf.Params{{N: "E", V: E}, {N: "nu", V: ν}}, mesh, nil)
+			s.PlaneStress = pstress
+			s.AddBc(true, 40, 0, 0, nil)
+			s.AddBc(true, 10, 1, 0, nil)
+			s.AddBc(false, 20, 0, σ, nil)
+			s.Assemble(true)
+			u, f := s.SolveSteady(true)
+			key := io.Sf("%s(pstress=%v)", mesh.Cells[0].TypeKey, pstress)
+			for _, v := range mesh.Verts {
+				chk.Float64(tst, key+": ux", 1e-14, u[v.ID*2+0], εxx*v.X[0])
+				chk.Float64(tst, key+": uy", 1e-14, u[v.ID*2+1], εyy*v.X[1])
+			}
+
+			// reactions
+			rx := 0.0
+			for _, v := range mesh.Tmaps.EdgeTag2verts[40] {
+				rx += f[v.ID*2+0]
+			}
+			chk.Float64(tst, key+": Σ rx", 1e-12, rx, -σ)
+
+			// stresses
+			for _, cell := range mesh.Cells {
+				_, sig := s.CellStresses(u, cell.ID)
+				for _, sg := range sig {
+					chk.Array(tst, key+": σ", 1e-12, sg, []float64{σ, 0, 0})
+				}
+			}
+		}
+	}
+}
+
+func TestFemElastic02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemElastic02. convergence with body forces")
+
+	// solve problem (plane-strain)
+	//    ∇⋅σ + b = 0    with   ux = uy = φ = sin(π x) sin(π y)   and   u = 0 @ boundaries
+	//    bᵢ = 2 π² μ φ - (λ + μ) π² (cos(π x) cos(π y) - φ)
+	E, ν := 1.0, 0.3
+	λ := E * ν / ((1 + ν) * (1 - 2*ν))
+	μ := E / (2 * (1 + ν))
+	φ := func(x []float64) float64 { return math.Sin(math.Pi*x[0]) * math.Sin(math.Pi*x[1]) }
+	body := func(b, x la.Vector, t float64) {
+		cc := math.Cos(math.Pi*x[0]) * math.Cos(math.Pi*x[1])
+		b[0] = 2*math.Pi*math.Pi*μ*φ(x) - (λ+μ)*math.Pi*math.Pi*(cc-φ(x))
+		b[1] = b[0]
+	}
+	for _, c := range []struct {
+		ctype int
+		ndivs []int
+		rate  float64
+	}{
+		{msh.TypeQua4, []int{4, 8, 16}, 1.8},
+		{msh.TypeQua9, []int{2, 4, 8}, 2.8},
+	} {
+		var errs []float64
+		for _, ndiv := range c.ndivs {
+			mesh := msh.GenQuadRegionHL(c.ctype, ndiv, ndiv, 0, 1, 0, 1)
+			s := NewFemElastic(dbf.Params{{N: "E", V: E}, {N: "nu", V: ν}}, mesh, body)
+			for _, tag := range []int{10, 20, 30, 40} {
+				s.AddBc(true, tag, 0, 0, nil)
+				s.AddBc(true, tag, 1, 0, nil)
+			}
+			u, _ := s.SolveSteady(false)
+			maxerr := 0.0
+			for _, v := range mesh.Verts {
+				maxerr = math.Max(maxerr, math.Abs(u[v.ID*2+0]-φ(v.X)))
+				maxerr = math.Max(maxerr, math.Abs(u[v.ID*2+1]-φ(v.X)))
+			}
+			io.Pf("%s: ndiv = %2d  maxerr = %.6e\n", msh.TypeIndexToKey[c.ctype], ndiv, maxerr)
+			errs = append(errs, maxerr)
+		}
+		for i := 1; i < len(errs); i++ {
+			rate := math.Log2(errs[i-1] / errs[i])
+			io.Pforan("rate = %.4f\n", rate)
+			if rate < c.rate {
+				tst.Errorf("convergence rate of %s must be greater than %g. %g is invalid\n", msh.TypeIndexToKey[c.ctype], c.rate, rate)
+			}
+		}
+	}
+}
+
+func TestFemElastic03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemElastic03. patch test: uniaxial traction in 3D")
+
+	// solve problem
+	//    unit cube with rollers @ x=0, y=0 and z=0 and traction tx = σ @ x=1
+	//    εxx = σ / E  and  εyy = εzz = -ν σ / E
+	E, ν, σ := 1000.0, 0.25, 3.0
+	mesh := femHexMesh(2)
+	s := NewFemElastic(dbf.Params{{N: "E", V: E}, {N: "nu", V: ν}}, mesh, nil)
+	s.AddBc(true, 100, 0, 0, nil)
+	s.AddBc(true, 200, 1, 0, nil)
+	s.AddBc(true, 300, 2, 0, nil)
+	s.AddBc(false, 101, 0, σ, nil)
+	u, _ := s.SolveSteady(false)
+	for _, v := range mesh.Verts {
+		chk.Float64(tst, "ux", 1e-14, u[v.ID*3+0], σ/E*v.X[0])
+		chk.Float64(tst, "uy", 1e-14, u[v.ID*3+1], -ν*σ/E*v.X[1])
+		chk.Float64(tst, "uz", 1e-14, u[v.ID*3+2], -ν*σ/E*v.X[2])
+	}
+	for _, cell := range mesh.Cells {
+		_, sig := s.CellStresses(u, cell.ID)
+		for _, sg := range sig {
+			chk.Array(tst, "σ", 1e-12, sg, []float64{σ, 0, 0, 0, 0, 0})
+		}
+	}
+
+	// rigid body modes are in the null space of the stiffness matrix
+	K, M := s.GlobalMatrices()
+	kk := K.ToMatrix(nil)
+	r := la.NewVector(len(u))
+	for _, mode := range [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		ur := la.NewVector(len(u))
+		for _, v := range mesh.Verts {
+			for i := 0; i < 3; i++ {
+				ur[v.ID*3+i] = mode[i]
+			}
+		}
+		la.SpMatVecMul(r, 1, kk, ur)
+		chk.Float64(tst, "|K⋅urigid|", 1e-12, r.Norm(), 0)
+		la.SpMatVecMul(r, 1, M.ToMatrix(nil), ur)
+		chk.Float64(tst, "urigidᵀ⋅M⋅urigid = mass", 1e-13, la.VecDot(ur, r), 1)
+	}
+	rot := la.NewVector(len(u)) // rotation about z
+	for _, v := range mesh.Verts {
+		rot[v.ID*3+0], rot[v.ID*3+1] = -v.X[1], v.X[0]
+	}
+	la.SpMatVecMul(r, 1, kk, rot)
+	chk.Float64(tst, "|K⋅urotation|", 1e-12, r.Norm(), 0)
+}
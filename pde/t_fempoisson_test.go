@@ -0,0 +1,237 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+// femDistortedMesh returns a mesh of qua4 on [0,1]×[0,1] with the inner vertices shifted
+func femDistortedMesh(ndiv int) *msh.Mesh {
+	return msh.GenQuadRegion(msh.TypeQua4, ndiv, ndiv, false, func(i, j, nr, ns int) (x, y float64) {
+		h := 1.0 / float64(nr-1)
+		x, y = float64(i)*h, float64(j)*h
+		if i > 0 && i < nr-1 && j > 0 && j < ns-1 {
+			x += 0.2 * h * float64((i+2*j)%3-1)
+			y += 0.2 * h * float64((2*i+j)%3-1)
+		}
+		return
+	})
+}
+
+// femTriMesh splits each qua4 of mesh into two tri3
+func femTriMesh(m *msh.Mesh) (o *msh.Mesh) {
+	o = new(msh.Mesh)
+	o.Verts = m.Verts
+	for _, c := range m.Cells {
+		v, e := c.V, c.EdgeTags
+		if len(e) == 0 {
+			e = make([]int, 4)
+		}
+		o.Cells = append(o.Cells,
+			&msh.Cell{ID: len(o.Cells), Tag: c.Tag, Part: c.Part, TypeKey: "tri3", V: []int{v[0], v[1], v[2]}, EdgeTags: []int{e[0], e[1], 0}},
+			&msh.Cell{ID: len(o.Cells) + 1, Tag: c.Tag, Part: c.Part, TypeKey: "tri3", V: []int{v[0], v[2], v[3]}, EdgeTags: []int{0, e[2], e[3]}},
+		)
+	}
+	o.CheckAndCalcDerivedVars()
+	return
+}
+
+func TestFemPoisson01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemPoisson01. patch test")
+
+	// solve problem
+	//    ∇⋅(K ∇u) = 0    with   K = diag(1, 2)   and   u = 1 + 2 x + 3 y
+	//    u is prescribed @ left and bottom; n⋅(K ∇u) = 2 @ right and n⋅(K ∇u) = 6 @ top
+	uana := func(x []float64) float64 { return 1 + 2*x[0] + 3*x[1] }
+	ebc := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "1 + 2*x[0] + 3*x[1]"}})
+	quads := femDistortedMesh(4)
+	for _, mesh := range []*msh.Mesh{quads, femTriMesh(quads)} {
+		s := NewFemPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 2}}, mesh, nil)
+		s.AddBc(true, 40, 0, ebc)
+		s.AddBc(true, 10, 0, ebc)
+		s.AddBc(false, 20, 2, nil)
+		s.AddBc(false, 30, 6, nil)
+		s.Assemble(true)
+		u, f := s.SolveSteady(true)
+		for _, v := range mesh.Verts {
+			chk.Float64(tst, io.Sf("%s: u%d", mesh.Cells[0].TypeKey, v.ID), 1e-12, u[v.ID], uana(v.X))
+		}
+
+		// the reactions balance the prescribed fluxes
+		sumF, sumf := 0.0, 0.0
+		for I := range f {
+			sumF += s.F[I]
+			sumf += f[I]
+		}
+		chk.Float64(tst, "Σ F", 1e-12, sumF, 2+6)
+		chk.Float64(tst, "Σ f", 1e-12, sumf, 0)
+
+		// global matrices
+		K, M := s.GlobalMatrices()
+		kk, mm := K.ToDense(), M.ToDense()
+		sumM := 0.0
+		for i := 0; i < kk.M; i++ {
+			rowK := 0.0
+			for j := 0; j < kk.N; j++ {
+				rowK += kk.Get(i, j)
+				sumM += mm.Get(i, j)
+				chk.Float64(tst, "Mᵀ-M", 1e-15, mm.Get(j, i), mm.Get(i, j))
+			}
+			chk.Float64(tst, "Σ K row", 1e-12, rowK, 0)
+		}
+		chk.Float64(tst, "Σ M = area", 1e-13, sumM, 1)
+	}
+}
+
+func TestFemPoisson02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemPoisson02. partitions and variable k")
+
+	// solve problem
+	//    ∇⋅(k ∇u) = s    with   k = 1 + x   and   u = x² + y
+	//    s = 2 (1 + x) + 2 x = 2 + 4 x
+	source := func(x la.Vector, t float64) float64 { return 2 + 4*x[0] }
+	solve := func(nparts int) []float64 {
+		mesh := msh.GenQuadRegionHL(msh.TypeQua8, 4, 4, 0, 1, 0, 1)
+		for _, c := range mesh.Cells {
+			c.Part = c.ID % nparts
+		}
+		mesh.CheckAndCalcDerivedVars()
+		s := NewFemPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, mesh, source)
+		s.Kvar = dbf.New("expr", []*dbf.P{{N: "expr", Extra: "1 + x[0]"}})
+		s.AddBc(true, 40, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "x[1]"}}))
+		s.AddBc(false, 20, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "4"}}))
+		s.AddBc(false, 10, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "-(1 + x[0])"}}))
+		s.AddRobinBc(30, 1, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "(1 + x[0]) + x[0]^2 + 1"}}))
+		u, _ := s.SolveSteady(false)
+		for _, v := range mesh.Verts {
+			chk.Float64(tst, io.Sf("u%d", v.ID), 1e-10, u[v.ID], v.X[0]*v.X[0]+v.X[1])
+		}
+		return u
+	}
+	u1 := solve(1)
+	u3 := solve(3)
+	chk.Array(tst, "u(3 parts)", 1e-13, u3, u1)
+}
+
+func TestFemPoisson03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemPoisson03. convergence")
+
+	// solve problem
+	//    ∇²u = s    with   u = sin(π x) exp(y)   ⇒   s = (1 - π²) u
+	//    u = 0 @ left, u = sin(π x) @ bottom, ∂u/∂x = -π exp(y) @ right and ∂u/∂y + u = 2 u @ top
+	uana := func(x []float64) float64 { return math.Sin(math.Pi*x[0]) * math.Exp(x[1]) }
+	source := func(x la.Vector, t float64) float64 { return (1 - math.Pi*math.Pi) * uana(x) }
+	expr := func(str string) dbf.T {
+		return dbf.New("expr", []*dbf.P{{N: "expr", Extra: str}})
+	}
+	for _, c := range []struct {
+		ctype int
+		ndivs []int
+		rate  float64
+	}{
+		{msh.TypeQua4, []int{4, 8, 16}, 1.8},
+		{msh.TypeQua9, []int{2, 4, 8}, 2.8},
+	} {
+		var errs []float64
+		for _, ndiv := range c.ndivs {
+			mesh := msh.GenQuadRegionHL(c.ctype, ndiv, ndiv, 0, 1, 0, 1)
+			s := NewFemPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, mesh, source)
+			s.AddBc(true, 40, 0, nil)
+			s.AddBc(true, 10, 0, expr("sin(pi*x[0])"))
+			s.AddBc(false, 20, 0, expr("-pi*exp(x[1])"))
+			s.AddRobinBc(30, 1, 0, expr("2*sin(pi*x[0])*exp(x[1])"))
+			u, _ := s.SolveSteady(false)
+			maxerr := 0.0
+			for _, v := range mesh.Verts {
+				maxerr = math.Max(maxerr, math.Abs(u[v.ID]-uana(v.X)))
+			}
+			io.Pf("%s: ndiv = %2d  maxerr = %.6e\n", msh.TypeIndexToKey[c.ctype], ndiv, maxerr)
+			errs = append(errs, maxerr)
+		}
+		for i := 1; i < len(errs); i++ {
+			rate := math.Log2(errs[i-1] / errs[i])
+			io.Pforan("rate = %.4f\n", rate)
+			if rate < c.rate {
+				tst.Errorf("convergence rate of %s must be greater than %g. %g is invalid\n", msh.TypeIndexToKey[c.ctype], c.rate, rate)
+			}
+		}
+	}
+}
+
+func TestFemPoisson04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FemPoisson04. transient: heat equation")
+
+	// solve problem
+	//    ∂u     ∂²u
+	//    ——— =  ———     with   u(0,t) = u(1,t) = 0   and   u(x,0) = sin(π x)  ⇒  u = exp(-π² t) sin(π x)
+	//    ∂t     ∂x²
+	uana := func(t float64, x []float64) float64 { return math.Exp(-math.Pi*math.Pi*t) * math.Sin(math.Pi*x[0]) }
+	mesh := msh.GenQuadRegionHL(msh.TypeQua8, 8, 1, 0, 1, 0, 0.2)
+	s := NewFemPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, mesh, nil)
+	s.AddBc(true, 40, 0, nil)
+	s.AddBc(true, 20, 0, nil)
+	u := make([]float64, len(mesh.Verts))
+	for _, v := range mesh.Verts {
+		u[v.ID] = uana(0, v.X)
+	}
+	var T []float64
+	s.SolveTransient(u, 0.1, 0.0025, 0.5, 0.025, func(idx int, t float64, u, v []float64) (stop bool) {
+		if idx != len(T) {
+			tst.Errorf("index of snapshot is incorrect\n")
+		}
+		T = append(T, t)
+		for _, v := range mesh.Verts {
+			chk.Float64(tst, "u", 2e-4, u[v.ID], uana(t, v.X))
+		}
+		return
+	})
+	chk.Array(tst, "T", 1e-12, T, []float64{0, 0.025, 0.05, 0.075, 0.1})
+
+	// solve problem
+	//    ∂u     ∂²u
+	//    ——— =  ———     with   u(0,t) = t   ∂u/∂x(1,t) = 1   and   u(x,0) = x²/2
+	//    ∂t     ∂x²
+	//
+	//  the solution u = t + x²/2 is reproduced exactly by qua8 elements and any θ
+	for _, theta := range []float64{1, 0.5} {
+		s = NewFemPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, mesh, nil)
+		s.AddBc(true, 40, 0, dbf.New("lin", []*dbf.P{{N: "m", V: 1}}))
+		s.AddBc(false, 20, 1, nil)
+		for _, v := range mesh.Verts {
+			u[v.ID] = v.X[0] * v.X[0] / 2
+		}
+		nsnap := 0
+		s.SolveTransient(u, 0.1, 0.01, theta, 0, func(idx int, t float64, u, v []float64) (stop bool) {
+			for _, v := range mesh.Verts {
+				chk.Float64(tst, "u", 1e-12, u[v.ID], t+v.X[0]*v.X[0]/2)
+			}
+			nsnap++
+			return
+		})
+		chk.Int(tst, "number of snapshots", nsnap, 11)
+
+		// steady solution after transient analysis
+		uss, _ := s.SolveSteady(false)
+		for _, v := range mesh.Verts {
+			chk.Float64(tst, "steady u", 1e-12, uss[v.ID], v.X[0])
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/gm/msh"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/utl"
+)
+
+// fvGrid1d returns a grid with one row of nx square cells on [0,1] × [0,1/nx]
+func fvGrid1d(nx int) (o *gm.Grid) {
+	o = new(gm.Grid)
+	o.RectGenUniform([]float64{0, 0}, []float64{1, 1.0 / float64(nx)}, []int{nx + 1, 2})
+	return
+}
+
+// fvSod returns the exact density of the Sod shock tube problem @ x ∈ [0,1] (diaphragm @ x=0.5)
+//  the star values are taken from Toro (2009) Table 4.3 (γ = 1.4)
+func fvSod(t, x float64) float64 {
+	γ, ρL, pL, ρR := 1.4, 1.0, 1.0, 0.125
+	pS, uS, ρSL, ρSR, sS := 0.30313, 0.92745, 0.42632, 0.26557, 1.75216
+	cL := math.Sqrt(γ * pL / ρL)
+	cSL := cL * math.Pow(pS/pL, (γ-1)/(2*γ))
+	ξ := (x - 0.5) / t
+	switch {
+	case ξ < -cL:
+		return ρL
+	case ξ < uS-cSL:
+		return ρL * math.Pow(2/(γ+1)-(γ-1)/((γ+1)*cL)*ξ, 2/(γ-1))
+	case ξ < uS:
+		return ρSL
+	case ξ < sS:
+		return ρSR
+	}
+	return ρR
+}
+
+// fvDamBreak returns the exact depth of the (wet bed) dam break problem @ x ∈ [0,1] (dam @ x=0.5)
+func fvDamBreak(g, hL, hR, t, x float64) float64 {
+	cL := math.Sqrt(g * hL)
+	res := func(h float64) float64 {
+		return 2*(cL-math.Sqrt(g*h)) - (h-hR)*math.Sqrt(g*(h+hR)/(2*h*hR))
+	}
+	a, b := hR, hL
+	for i := 0; i < 100; i++ {
+		hS := (a + b) / 2
+		if res(hS) > 0 {
+			a = hS
+		} else {
+			b = hS
+		}
+	}
+	hS := (a + b) / 2
+	cS := math.Sqrt(g * hS)
+	uS := 2 * (cL - cS)
+	sS := hS * uS / (hS - hR)
+	ξ := (x - 0.5) / t
+	switch {
+	case ξ < -cL:
+		return hL
+	case ξ < uS-cS:
+		c := (2*cL - ξ) / 3
+		return c * c / g
+	case ξ < sS:
+		return hS
+	}
+	return hR
+}
+
+func TestFiniteVolume01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FiniteVolume01. geometry and free stream preservation")
+
+	grid := new(gm.Grid)
+	grid.RectGenUniform([]float64{0, 0}, []float64{2, 1}, []int{5, 4})
+	quads := femDistortedMesh(5)
+	model := NewFvEuler(nil)
+	for _, c := range []struct {
+		key  string
+		o    *FiniteVolume
+		area float64
+	}{
+		{"grid", NewFvGrid(model, grid, nil), 2},
+		{"qua4", NewFvMesh(model, quads), 1},
+		{"tri3", NewFvMesh(model, femTriMesh(quads)), 1},
+	} {
+
+		// areas and closed cells
+		area := 0.0
+		for i := 0; i < c.o.Ncells(); i++ {
+			area += c.o.Volume(i)
+		}
+		chk.Float64(tst, c.key+": Σ|Ωc|", 1e-14, area, c.area)
+		sn := utl.Alloc(c.o.Ncells(), 2)
+		for _, f := range c.o.faces {
+			for i := 0; i < 2; i++ {
+				sn[f.l][i] += f.n[i] * f.area
+				if f.r >= 0 {
+					sn[f.r][i] -= f.n[i] * f.area
+				}
+			}
+		}
+		for i := 0; i < c.o.Ncells(); i++ {
+			chk.Array(tst, c.key+": Σ n |f|", 1e-15, sn[i], nil)
+		}
+
+		// uniform flow is preserved
+		u := c.o.Alloc()
+		r := c.o.Alloc()
+		for i := range u {
+			copy(u[i], model.Conserved(1.2, 0.3, -0.4, 1.5))
+		}
+		for _, limiter := range []string{"none", "minmod", "vanleer", "superbee"} {
+			for _, riemann := range []string{"rusanov", "hll", "roe"} {
+				c.o.Limiter, c.o.Riemann = limiter, riemann
+				c.o.Rates(r, u)
+				for i := range r {
+					chk.Array(tst, c.key+": du/dt", 1e-13, r[i], nil)
+				}
+			}
+		}
+	}
+
+	// linear fields are reconstructed exactly
+	o := NewFvMesh(NewFvAdvection(nil), femTriMesh(quads))
+	u := o.Alloc()
+	for i := range u {
+		x := o.Center(i)
+		u[i][0] = 1 + 2*x[0] - 3*x[1]
+	}
+	for i, f := range o.faces {
+		if f.r < 0 {
+			x := o.Center(f.l)
+			o.ghost[i][0] = 1 + 2*(x[0]+f.d[0]) - 3*(x[1]+f.d[1])
+		}
+	}
+	o.gradients(u)
+	for i := range u {
+		chk.Float64(tst, "∂u/∂x", 1e-13, o.gx[i][0], 2)
+		chk.Float64(tst, "∂u/∂y", 1e-13, o.gy[i][0], -3)
+	}
+}
+
+func TestFiniteVolume02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FiniteVolume02. linear advection: convergence")
+
+	// solve problem (periodic)
+	//    ∂u     ∂u
+	//    ——— +  —— = 0     with   u(x,0) = sin(2 π x)   ⇒   u = sin(2 π (x - t))
+	//    ∂t     ∂x
+	//
+	//  the error is computed with the exact cell averages
+	tf := 1.0
+	for _, c := range []struct {
+		limiter string
+		rate    float64
+	}{
+		{"none", 0.8},
+		{"minmod", 1.6},
+		{"vanleer", 1.8},
+		{"superbee", 0.5}, // superbee steepens smooth profiles
+	} {
+		var errs []float64
+		for _, nx := range []int{40, 80, 160} {
+			o := NewFvGrid(NewFvAdvection(dbf.Params{{N: "vx", V: 1}}), fvGrid1d(nx), []bool{true, false})
+			o.Limiter = c.limiter
+			h := 1.0 / float64(nx)
+			avg := func(t float64, i int) float64 {
+				a, b := float64(i)*h-t, float64(i+1)*h-t
+				return (math.Cos(2*math.Pi*a) - math.Cos(2*math.Pi*b)) / (2 * math.Pi * h)
+			}
+			u := o.Alloc()
+			for i := range u {
+				u[i][0] = avg(0, i)
+			}
+			o.Solve(u, tf, 0, 0, nil)
+			l1 := 0.0
+			for i := range u {
+				l1 += math.Abs(u[i][0]-avg(tf, i)) * h
+			}
+			io.Pf("%8s: nx = %3d  nsteps = %4d  L1 error = %.6e\n", c.limiter, nx, o.Nsteps, l1)
+			errs = append(errs, l1)
+		}
+		for i := 1; i < len(errs); i++ {
+			rate := math.Log2(errs[i-1] / errs[i])
+			io.Pforan("rate = %.4f\n", rate)
+			if rate < c.rate {
+				tst.Errorf("convergence rate of %q must be greater than %g. %g is invalid\n", c.limiter, c.rate, rate)
+			}
+		}
+	}
+}
+
+func TestFiniteVolume03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FiniteVolume03. Euler: Sod shock tube")
+
+	// initial state
+	nx, tf := 100, 0.2
+	model := NewFvEuler(nil)
+	setU := func(o *FiniteVolume) (u [][]float64) {
+		u = o.Alloc()
+		for i := range u {
+			if o.Center(i)[0] < 0.5 {
+				copy(u[i], model.Conserved(1, 0, 0, 1))
+			} else {
+				copy(u[i], model.Conserved(0.125, 0, 0, 0.1))
+			}
+		}
+		return
+	}
+
+	// solve with all Riemann solvers and limiters
+	grid := fvGrid1d(nx)
+	mesh := msh.GenQuadRegionHL(msh.TypeQua4, nx, 1, 0, 1, 0, 1.0/float64(nx))
+	for _, riemann := range []string{"rusanov", "hll", "roe"} {
+		for _, c := range []struct {
+			limiter string
+			tol     float64
+		}{
+			{"none", 0.03},
+			{"minmod", 0.012},
+			{"vanleer", 0.009},
+			{"superbee", 0.009},
+		} {
+			o := NewFvGrid(model, grid, nil)
+			o.Riemann, o.Limiter = riemann, c.limiter
+			u := setU(o)
+			nsnap := 0
+			o.Solve(u, tf, 0, 0.05, func(idx int, t float64, u [][]float64) (stop bool) {
+				nsnap++
+				return
+			})
+			chk.Int(tst, "number of snapshots", nsnap, 5)
+			l1 := 0.0
+			for i := range u {
+				l1 += math.Abs(u[i][0]-fvSod(tf, o.Center(i)[0])) / float64(nx)
+			}
+			io.Pf("%8s %8s: nsteps = %3d  L1 error of ρ = %.6f\n", riemann, c.limiter, o.Nsteps, l1)
+			if l1 > c.tol {
+				tst.Errorf("L1 error of %s/%s is too large: %g > %g\n", riemann, c.limiter, l1, c.tol)
+			}
+
+			// the same problem on a mesh gives the same results
+			m := NewFvMesh(model, mesh)
+			m.Riemann, m.Limiter = riemann, c.limiter
+			um := setU(m)
+			m.Solve(um, tf, 0, 0.05, nil)
+			chk.Int(tst, "nsteps", m.Nsteps, o.Nsteps)
+			for i := range um {
+				chk.Array(tst, "x", 1e-14, m.Center(i), o.Center(i))
+				chk.Array(tst, "u", 1e-12, um[i], u[i])
+			}
+		}
+	}
+}
+
+func TestFiniteVolume04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FiniteVolume04. shallow water: dam break")
+
+	// 1D dam break with SSP-RK2
+	g, hL, hR, tf := 9.81, 1.0, 0.5, 0.1
+	model := NewFvShallowWater(nil)
+	for _, riemann := range []string{"hll", "roe"} {
+		o := NewFvGrid(model, fvGrid1d(100), nil)
+		o.Riemann, o.Limiter, o.Stages = riemann, "vanleer", 2
+		u := o.Alloc()
+		for i := range u {
+			u[i][0] = hR
+			if o.Center(i)[0] < 0.5 {
+				u[i][0] = hL
+			}
+		}
+		o.Solve(u, tf, 0, 0, nil)
+		l1 := 0.0
+		for i := range u {
+			l1 += math.Abs(u[i][0]-fvDamBreak(g, hL, hR, tf, o.Center(i)[0])) / 100
+		}
+		io.Pf("%4s: nsteps = %3d  L1 error of h = %.6f\n", riemann, o.Nsteps, l1)
+		if l1 > 5e-3 {
+			tst.Errorf("L1 error of %s is too large: %g\n", riemann, l1)
+		}
+	}
+
+	// circular dam break in a closed box of triangles
+	o := NewFvMesh(model, femTriMesh(msh.GenQuadRegionHL(msh.TypeQua4, 11, 11, -1, 1, -1, 1)))
+	for _, tag := range []int{10, 20, 30, 40} {
+		o.AddBc(tag, "wall", nil)
+	}
+	o.Riemann = "roe"
+	u := o.Alloc()
+	for i := range u {
+		u[i][0] = 1
+		if o.Center(i).Norm() < 0.5 {
+			u[i][0] = 2
+		}
+	}
+	mass := o.Integral(u)[0]
+	o.Solve(u, 0.5, 0, 0.1, func(idx int, t float64, u [][]float64) (stop bool) {
+		tot := o.Integral(u)
+		io.Pf("t = %.2f  mass = %.15f  momentum = (%+.2e, %+.2e)\n", t, tot[0], tot[1], tot[2])
+		chk.Float64(tst, "mass", 1e-13, tot[0], mass)
+		for i := range u {
+			if u[i][0] < 0.5 {
+				tst.Errorf("depth is too small: %g\n", u[i][0])
+				return true
+			}
+		}
+		return
+	})
+}
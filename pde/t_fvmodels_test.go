@@ -0,0 +1,106 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/la"
+)
+
+// fvCheckRoe checks the Roe property: A(ũ) ⋅ (ur - ul) = F(ur) - F(ul). If all wave speeds are
+// positive (or negative), |A(ũ)| = A(ũ) (or -A(ũ)) and thus the dissipation equals ±(F(ur) - F(ul))
+func fvCheckRoe(tst *testing.T, key string, model FvModel, ul, ur, n la.Vector, sign float64) {
+	nv := model.Nvars()
+	d, fl, fr := la.NewVector(nv), la.NewVector(nv), la.NewVector(nv)
+	model.RoeDissipation(d, ul, ur, n)
+	model.Flux(fl, ul, n)
+	model.Flux(fr, ur, n)
+	for k := 0; k < nv; k++ {
+		chk.Float64(tst, key+": |A|⋅Δu", 1e-13, d[k], sign*(fr[k]-fl[k]))
+	}
+	model.RoeDissipation(d, ul, ul, n)
+	chk.Array(tst, key+": |A|⋅0", 1e-15, d, nil)
+}
+
+// fvCheckWall checks that the ghost state of a wall yields zero normal velocity on average
+func fvCheckWall(tst *testing.T, key string, model FvModel, u, n la.Vector) {
+	ug := la.NewVector(len(u))
+	model.Wall(ug, u, n)
+	chk.Float64(tst, key+": ρ", 1e-15, ug[0], u[0])
+	chk.Float64(tst, key+": Σ(ρ v)⋅n", 1e-15, (u[1]+ug[1])*n[0]+(u[2]+ug[2])*n[1], 0)
+	chk.Float64(tst, key+": Δ(ρ v)⋅t", 1e-15, (u[2]-ug[2])*n[0]-(u[1]-ug[1])*n[1], 0)
+}
+
+func TestFvModels01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FvModels01. advection")
+
+	m := NewFvAdvection(dbf.Params{{N: "vx", V: 2}, {N: "vy", V: -1}})
+	n := la.Vector{0.6, 0.8}
+	f := la.NewVector(1)
+	m.Flux(f, []float64{3}, n)
+	chk.Float64(tst, "f", 1e-15, f[0], 3*(1.2-0.8))
+	smin, smax := m.Speeds([]float64{3}, n)
+	chk.Float64(tst, "smin", 1e-15, smin, 0.4)
+	chk.Float64(tst, "smax", 1e-15, smax, 0.4)
+	fvCheckRoe(tst, "advection", m, []float64{1}, []float64{3}, n, 1)
+	fvCheckRoe(tst, "advection", m, []float64{1}, []float64{3}, la.Vector{-0.6, -0.8}, -1)
+}
+
+func TestFvModels02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FvModels02. shallow water")
+
+	m := NewFvShallowWater(dbf.Params{{N: "g", V: 10}})
+	n := la.Vector{math.Cos(0.3), math.Sin(0.3)}
+	u := la.Vector{2, 2 * 1.5, 2 * -0.5}
+	f := la.NewVector(3)
+	m.Flux(f, u, n)
+	vn := 1.5*n[0] - 0.5*n[1]
+	chk.Array(tst, "f", 1e-14, f, []float64{2 * vn, 2*1.5*vn + 20*n[0], 2*-0.5*vn + 20*n[1]})
+	smin, smax := m.Speeds(u, n)
+	chk.Float64(tst, "smin", 1e-14, smin, vn-math.Sqrt(20))
+	chk.Float64(tst, "smax", 1e-14, smax, vn+math.Sqrt(20))
+
+	// supercritical states (Froude > 1) along n and -n
+	ul := la.Vector{1.0, 6 * n[0], 6 * n[1]}
+	ur := la.Vector{1.2, 1.2 * (7*n[0] - 2*n[1]), 1.2 * (7*n[1] + 2*n[0])}
+	fvCheckRoe(tst, "sw", m, ul, ur, n, 1)
+	fvCheckRoe(tst, "sw", m, ul, ur, la.Vector{-n[0], -n[1]}, -1)
+	fvCheckWall(tst, "sw", m, u, n)
+}
+
+func TestFvModels03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("FvModels03. Euler")
+
+	m := NewFvEuler(nil)
+	chk.Float64(tst, "γ", 1e-15, m.Gamma, 1.4)
+	n := la.Vector{math.Cos(2.0), math.Sin(2.0)}
+	u := m.Conserved(1.2, 0.5, -0.3, 2.5)
+	chk.Float64(tst, "p", 1e-14, m.Pressure(u), 2.5)
+	f := la.NewVector(4)
+	m.Flux(f, u, n)
+	vn := 0.5*n[0] - 0.3*n[1]
+	chk.Array(tst, "f", 1e-14, f, []float64{1.2 * vn, 1.2*0.5*vn + 2.5*n[0], 1.2*-0.3*vn + 2.5*n[1], (u[3] + 2.5) * vn})
+	c := math.Sqrt(1.4 * 2.5 / 1.2)
+	smin, smax := m.Speeds(u, n)
+	chk.Float64(tst, "smin", 1e-14, smin, vn-c)
+	chk.Float64(tst, "smax", 1e-14, smax, vn+c)
+
+	// supersonic states along n and -n
+	ul := m.Conserved(1.0, 3*n[0]+0.5*n[1], 3*n[1]-0.5*n[0], 1.0)
+	ur := m.Conserved(0.8, 3.5*n[0]-0.2*n[1], 3.5*n[1]+0.2*n[0], 0.7)
+	fvCheckRoe(tst, "euler", m, ul, ur, n, 1)
+	fvCheckRoe(tst, "euler", m, ul, ur, la.Vector{-n[0], -n[1]}, -1)
+	fvCheckWall(tst, "euler", m, u, n)
+}
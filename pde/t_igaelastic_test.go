@@ -0,0 +1,143 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestIgaElastic01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("IgaElastic01. patch test and equilibrium (two patches)")
+
+	// linear displacements prescribed on the boundary:
+	//    ux = 0.1 + 0.2 x + 0.3 y    and    uy = -0.1 + 0.4 x - 0.2 y
+	newPatch := func() (patch *gm.NurbsPatch) {
+		patch = gm.NewNurbsPatch(5, 1e-10, igaSquare(0, 0.6, 0.45).KrefineN(2, false), igaSquare(1, 1.45, 0.55).KrefineN(2, false))
+		IgaTagSides(patch, 0, []int{10, 0, 20, 21})
+		IgaTagSides(patch, 1, []int{0, 11, 20, 21})
+		return
+	}
+	patch := newPatch()
+	o := NewIgaElastic(dbf.Params{{N: "E", V: 1000}, {N: "nu", V: 0.25}}, patch, nil)
+	fux := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "0.1 + 0.2*x[0] + 0.3*x[1]"}})
+	fuy := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "-0.1 + 0.4*x[0] - 0.2*x[1]"}})
+	for _, tag := range []int{10, 11, 20, 21} {
+		o.AddBc(tag, 0, 0, fux)
+		o.AddBc(tag, 1, 0, fuy)
+	}
+	u, _ := o.SolveSteady(false)
+	for i, p := range patch.ControlPoints {
+		chk.Float64(tst, "ux", 1e-13, u[i*2+0], 0.1+0.2*p.X[0]+0.3*p.X[1])
+		chk.Float64(tst, "uy", 1e-13, u[i*2+1], -0.1+0.4*p.X[0]-0.2*p.X[1])
+	}
+	sig := la.NewVector(3)
+	la.MatVecMul(sig, 1, o.ElasticModulus(), []float64{0.2, -0.2, 0.7})
+	for e := range patch.Entities {
+		for _, knots := range [][]float64{{0.2, 0.3}, {0.5, 0.5}, {0.9, 1}} {
+			x, d, s := o.Eval(u, e, knots)
+			chk.Array(tst, "u(x)", 1e-13, d, []float64{0.1 + 0.2*x[0] + 0.3*x[1], -0.1 + 0.4*x[0] - 0.2*x[1]})
+			chk.Array(tst, "σ(x)", 1e-10, s, sig)
+		}
+	}
+
+	// rigid body motions yield no forces
+	K := o.GlobalMatrix().ToDense()
+	r := la.NewVector(len(u))
+	for _, mode := range []func(x []float64) (float64, float64){
+		func(x []float64) (float64, float64) { return 1, 0 },
+		func(x []float64) (float64, float64) { return 0, 1 },
+		func(x []float64) (float64, float64) { return -x[1], x[0] },
+	} {
+		v := la.NewVector(len(u))
+		for i, p := range patch.ControlPoints {
+			v[i*2+0], v[i*2+1] = mode(p.X)
+		}
+		la.MatVecMul(r, 1, K, v)
+		chk.Array(tst, "K⋅rigid", 1e-12, r, nil)
+	}
+
+	// the reactions at the fixed bottom balance the body force: ∫ b dΩ = (0, -2 × 2)
+	patch = newPatch()
+	o = NewIgaElastic(dbf.Params{{N: "E", V: 1000}, {N: "nu", V: 0.25}}, patch, func(b, x la.Vector, t float64) {
+		b[0], b[1] = 0, -2
+	})
+	o.PlaneStress = true
+	o.AddBc(20, 0, 0, nil)
+	o.AddBc(20, 1, 0, nil)
+	_, f := o.SolveSteady(true)
+	var fy, ry float64
+	for i, p := range patch.ControlPoints {
+		fy += o.F[i*2+1]
+		if p.Tag == 20 {
+			ry += f[i*2+1] - o.F[i*2+1]
+		}
+	}
+	chk.Float64(tst, "Σ F_y", 1e-13, fy, -4)
+	chk.Float64(tst, "Σ reactions_y", 1e-11, ry, 4)
+}
+
+func TestIgaElastic02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("IgaElastic02. thick cylinder: convergence under k-refinement")
+
+	// Lamé's solution of a thick cylinder (plane-strain) using a quarter of ring with 1 ≤ r ≤ 2
+	//
+	//    ur = A r + B / r
+	//
+	//  the displacements are prescribed at the inner and outer surfaces (where they are linear
+	//  functions of x and y) and symmetry conditions are applied at x = 0 and y = 0
+	A, B := 1e-3, 2e-3
+	uana := func(x la.Vector, i int) float64 {
+		r2 := x[0]*x[0] + x[1]*x[1]
+		return (A + B/r2) * x[i]
+	}
+	var errs []float64
+	for _, ndiv := range []int{2, 4, 8} {
+		patch := gm.NewNurbsPatch(5, 1e-10, gm.FactoryNurbs.Surf2dQuarterRing(0, 0, 1, 2).KrefineN(ndiv, false))
+		IgaTagSides(patch, 0, []int{0, 0, 20, 21})
+		IgaTagSides(patch, 0, []int{10, 11, 0, 0}) // both components are prescribed at the corners
+		o := NewIgaElastic(dbf.Params{{N: "E", V: 1000}, {N: "nu", V: 0.3}}, patch, nil)
+		for _, c := range []struct {
+			tag int
+			r2  float64
+		}{{10, 1}, {11, 4}} {
+			for i, expr := range []string{"a*x[0]", "a*x[1]"} {
+				o.AddBc(c.tag, i, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: expr}, {N: "a", V: A + B/c.r2}}))
+			}
+		}
+		o.AddBc(20, 1, 0, nil)
+		o.AddBc(21, 0, 0, nil)
+		u, _ := o.SolveSteady(false)
+		l2 := igaErrorL2(o.iga, u, uana)
+		io.Pf("ndiv = %d  neq = %3d  L2 error = %.6e\n", ndiv, len(u), l2)
+		errs = append(errs, l2)
+
+		// radial stress σrr = 2 (λ + μ) A - 2 μ B / r² at the centre of the first element
+		if ndiv == 8 {
+			λ, μ := 1000*0.3/(1.3*0.4), 1000/2.6
+			x, _, s := o.Eval(u, 0, []float64{1.0 / 16, 0.5})
+			r2 := x[0]*x[0] + x[1]*x[1]
+			σrr := (s[0]*x[0]*x[0] + s[1]*x[1]*x[1] + 2*s[2]*x[0]*x[1]) / r2
+			chk.Float64(tst, "σrr", 5e-3, σrr, 2*(λ+μ)*A-2*μ*B/r2)
+		}
+	}
+	for i := 1; i < len(errs); i++ {
+		rate := math.Log2(errs[i-1] / errs[i])
+		io.Pforan("rate = %.4f\n", rate)
+		if rate < 1.9 {
+			tst.Errorf("convergence rate must be greater than 1.9. %g is invalid\n", rate)
+		}
+	}
+}
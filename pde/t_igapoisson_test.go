@@ -0,0 +1,186 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+	"github.com/cpmech/gosl/utl"
+)
+
+// igaSquare returns a quadratic B-spline surface (unit weights) on [x0,x0+1] × [0,1] with the
+// central control point moved to {xc,yc}
+func igaSquare(x0, xc, yc float64) (o *gm.Nurbs) {
+	verts := [][]float64{
+		{x0, 0, 0, 1}, {x0 + 0.5, 0, 0, 1}, {x0 + 1, 0, 0, 1},
+		{x0, 0.5, 0, 1}, {xc, yc, 0, 1}, {x0 + 1, 0.5, 0, 1},
+		{x0, 1, 0, 1}, {x0 + 0.5, 1, 0, 1}, {x0 + 1, 1, 0, 1},
+	}
+	o = gm.NewNurbs(2, []int{2, 2}, [][]float64{{0, 0, 0, 1, 1, 1}, {0, 0, 0, 1, 1, 1}})
+	o.SetControl(verts, utl.IntRange(len(verts)))
+	return
+}
+
+// igaErrorL2 returns the L2 norm of the error of a field with ndof components at control points
+func igaErrorL2(o *igaBase, u []float64, ana func(x la.Vector, i int) float64) float64 {
+	sum := 0.0
+	for _, el := range o.elems {
+		o.integrate(el, func(S la.Vector, G *la.Matrix, x la.Vector, w float64) {
+			for i := 0; i < o.ndof; i++ {
+				uh := 0.0
+				for a, c := range el.ctrls {
+					uh += S[a] * u[c*o.ndof+i]
+				}
+				sum += w * math.Pow(uh-ana(x, i), 2)
+			}
+		})
+	}
+	return math.Sqrt(sum)
+}
+
+func TestIgaPoisson01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("IgaPoisson01. patch test (single and multiple patches)")
+
+	// solve problem
+	//    ∇²u = 0    with   u = 1 + 2 x - 3 y   on the boundary
+	//
+	//  the solution is exact because NURBS reproduce linear fields
+	ebc := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "1 + 2*x[0] - 3*x[1]"}})
+	for _, c := range []struct {
+		key      string
+		entities []*gm.Nurbs
+		tags     [][]int
+		nctrls   int
+	}{
+		{"single", []*gm.Nurbs{igaSquare(0, 0.6, 0.45).KrefineN(3, false)}, [][]int{{10, 11, 20, 21}}, 25},
+		{"two", []*gm.Nurbs{igaSquare(0, 0.6, 0.45).KrefineN(3, false), igaSquare(1, 1.45, 0.55).KrefineN(3, false)}, [][]int{{10, 0, 20, 21}, {0, 11, 20, 21}}, 45},
+	} {
+		patch := gm.NewNurbsPatch(5, 1e-10, c.entities...)
+		chk.Int(tst, c.key+": number of control points", len(patch.ControlPoints), c.nctrls)
+		for e, tags := range c.tags {
+			IgaTagSides(patch, e, tags)
+		}
+		o := NewIgaPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, patch, nil)
+		for _, tag := range []int{10, 11, 20, 21} {
+			o.AddBc(tag, 0, ebc)
+		}
+		u, f := o.SolveSteady(true)
+		for i, p := range patch.ControlPoints {
+			chk.Float64(tst, c.key+": u", 1e-12, u[i], 1+2*p.X[0]-3*p.X[1])
+			if p.Tag == 0 {
+				chk.Float64(tst, c.key+": f", 1e-12, f[i], 0)
+			}
+		}
+		for e := range patch.Entities {
+			for _, knots := range [][]float64{{0.1, 0.2}, {0.5, 0.9}, {1, 1}} {
+				x, val, grad := o.Eval(u, e, knots)
+				chk.Float64(tst, c.key+": u(x)", 1e-12, val, 1+2*x[0]-3*x[1])
+				chk.Array(tst, c.key+": ∇u(x)", 1e-11, grad, []float64{2, -3})
+			}
+		}
+
+		// the global matrix is symmetric and constant fields yield zero fluxes
+		K := o.GlobalMatrix().ToDense()
+		ones := la.NewVector(len(u))
+		ones.Fill(1)
+		r := la.NewVector(len(u))
+		la.MatVecMul(r, 1, K, ones)
+		chk.Array(tst, c.key+": K⋅1", 1e-13, r, nil)
+		for i := 0; i < K.M; i++ {
+			for j := 0; j < i; j++ {
+				chk.Float64(tst, c.key+": Kij-Kji", 1e-15, K.Get(i, j), K.Get(j, i))
+			}
+		}
+	}
+}
+
+func TestIgaPoisson02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("IgaPoisson02. quarter of ring: convergence under k-refinement")
+
+	// solve problem
+	//    ∇²u = s    on the quarter of ring with 1 ≤ r ≤ 2    with   u = 0   on the boundary
+	//
+	//    u = x y (r² - 1) (4 - r²)   ⇒   s = 2 x y (30 - 16 r²)
+	//
+	//  the geometry is exact. The ring is linear along the radius
+	uana := func(x []float64) float64 {
+		r2 := x[0]*x[0] + x[1]*x[1]
+		return x[0] * x[1] * (r2 - 1) * (4 - r2)
+	}
+	source := func(x la.Vector, t float64) float64 {
+		return 2 * x[0] * x[1] * (30 - 16*(x[0]*x[0]+x[1]*x[1]))
+	}
+	var errs []float64
+	for _, ndiv := range []int{4, 8, 16} {
+		patch := gm.NewNurbsPatch(5, 1e-10, gm.FactoryNurbs.Surf2dQuarterRing(0, 0, 1, 2).KrefineN(ndiv, false))
+		IgaTagSides(patch, 0, []int{1, 1, 1, 1})
+		o := NewIgaPoisson(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, patch, source)
+		o.AddBc(1, 0, nil)
+		u, _ := o.SolveSteady(false)
+		l2 := igaErrorL2(o.iga, u, func(x la.Vector, i int) float64 { return uana(x) })
+		io.Pf("ndiv = %2d  neq = %4d  L2 error = %.6e\n", ndiv, len(u), l2)
+		errs = append(errs, l2)
+	}
+	for i := 1; i < len(errs); i++ {
+		rate := math.Log2(errs[i-1] / errs[i])
+		io.Pforan("rate = %.4f\n", rate)
+		if rate < 1.9 {
+			tst.Errorf("convergence rate must be greater than 1.9. %g is invalid\n", rate)
+		}
+	}
+}
+
+func TestIgaPoisson03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("IgaPoisson03. two patches: convergence under k-refinement")
+
+	// solve problem
+	//    ∇⋅(K ∇u) = s    on [0,2] × [0,1]    with   u = 0   on the boundary
+	//
+	//    u = sin(π x / 2) sin(π y)   ⇒   s = -(kx π²/4 + ky π²) u
+	//
+	//  using two quadratic B-spline patches coupled at x = 1
+	kx, ky := 2.0, 0.5
+	uana := func(x []float64) float64 { return math.Sin(math.Pi*x[0]/2) * math.Sin(math.Pi*x[1]) }
+	source := func(x la.Vector, t float64) float64 { return -(kx*math.Pi*math.Pi/4 + ky*math.Pi*math.Pi) * uana(x) }
+	var errs []float64
+	for _, ndiv := range []int{2, 4, 8} {
+		patch := gm.NewNurbsPatch(5, 1e-10, igaSquare(0, 0.5, 0.5).KrefineN(ndiv, false), igaSquare(1, 1.5, 0.5).KrefineN(ndiv, false))
+		IgaTagSides(patch, 0, []int{1, 0, 1, 1})
+		IgaTagSides(patch, 1, []int{0, 1, 1, 1})
+		o := NewIgaPoisson(dbf.Params{{N: "kx", V: kx}, {N: "ky", V: ky}}, patch, source)
+		o.AddBc(1, 0, nil)
+		u, _ := o.SolveSteady(false)
+		l2 := igaErrorL2(o.iga, u, func(x la.Vector, i int) float64 { return uana(x) })
+		io.Pf("ndiv = %d  neq = %3d  L2 error = %.6e\n", ndiv, len(u), l2)
+		errs = append(errs, l2)
+
+		// the solution is continuous across the patches
+		for _, v := range []float64{0.2, 0.5, 0.7} {
+			xa, ua, _ := o.Eval(u, 0, []float64{1, v})
+			xb, ub, _ := o.Eval(u, 1, []float64{0, v})
+			chk.Array(tst, "x @ interface", 1e-15, xa, xb)
+			chk.Float64(tst, "u @ interface", 1e-15, ua, ub)
+		}
+	}
+	for i := 1; i < len(errs); i++ {
+		rate := math.Log2(errs[i-1] / errs[i])
+		io.Pforan("rate = %.4f\n", rate)
+		if rate < 2.8 {
+			tst.Errorf("convergence rate must be greater than 2.8. %g is invalid\n", rate)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/gm"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/plt"
+)
+
+// molGrid1d returns a grid to solve 1D problems along x (the top and bottom edges are insulated)
+func molGrid1d(npts int) (g *gm.Grid, h float64) {
+	g = new(gm.Grid)
+	g.RectGenUniform([]float64{0, 0}, []float64{1, 0.2}, []int{npts, 3})
+	h = 1.0 / float64(npts-1)
+	return
+}
+
+func TestMol01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Mol01. heat equation: decay of sine mode")
+
+	// solve problem
+	//    ∂u     ∂²u
+	//    ——— =  ———     with   u(0,t) = u(1,t) = 0   and   u(x,0) = sin(π x)
+	//    ∂t     ∂x²
+	//
+	//  the semi-discrete solution is u = exp(-λ t) sin(π x) with λ = 4 sin²(π h / 2) / h²
+	g, h := molGrid1d(21)
+	λ := 4 * math.Pow(math.Sin(math.Pi*h/2), 2) / (h * h)
+	uana := func(t float64, x []float64) float64 { return math.Exp(-λ*t) * math.Sin(math.Pi*x[0]) }
+	op := NewFdmLaplacian(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, g, nil)
+	op.AddBc(true, 10, 0, nil)
+	op.AddBc(true, 11, 0, nil)
+
+	// run
+	tf, dtOut := 0.1, 0.025
+	for _, c := range []struct {
+		method string
+		dt     float64
+		tol    float64
+	}{
+		{"radau5", 0, 1e-7},
+		{"dopri5", 0, 1e-7},
+		{"bweuler", 1e-3, 2e-3},
+	} {
+		heat := NewFdmHeat(op, 1, c.method)
+		heat.Atol, heat.Rtol = 1e-9, 1e-9
+		u := make([]float64, g.Size())
+		for I := range u {
+			u[I] = uana(0, g.Node(I))
+		}
+		var T []float64
+		var X, U [][]float64
+		heat.Solve(u, tf, c.dt, dtOut, func(idx int, t float64, u, v []float64) (stop bool) {
+			if idx != len(T) {
+				tst.Errorf("index of snapshot is incorrect\n")
+			}
+			T = append(T, t)
+			x, y := make([]float64, g.Npts(0)), make([]float64, g.Npts(0))
+			for I := 0; I < g.Size(); I++ {
+				chk.Float64(tst, "u", c.tol, u[I], uana(t, g.Node(I)))
+			}
+			for m := 0; m < g.Npts(0); m++ {
+				x[m], y[m] = g.Node(m)[0], u[m]
+			}
+			X, U = append(X, x), append(U, y)
+			return
+		})
+		io.Pforan("%-8s nsteps = %d\n", c.method, heat.Stat.Nsteps)
+		chk.Array(tst, "T", 1e-12, T, []float64{0, 0.025, 0.05, 0.075, 0.1})
+		for I := 0; I < g.Size(); I++ {
+			chk.Float64(tst, "u(tf)", c.tol, u[I], uana(tf, g.Node(I)))
+		}
+
+		// plot
+		if chk.Verbose && c.method == "radau5" {
+			plt.Reset(true, nil)
+			for k, t := range T {
+				plt.Plot(X[k], U[k], &plt.A{C: plt.C(k, 0), M: ".", L: io.Sf("t=%g", t)})
+			}
+			plt.Gll("$x$", "$u$", nil)
+			plt.Save("/tmp/gosl/pde", "mol01")
+		}
+	}
+}
+
+func TestMol02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Mol02. heat equation: time-dependent and natural conditions")
+
+	// solve problem
+	//    ∂u     ∂²u
+	//    ——— =  ———     with   u(0,t) = t   ∂u/∂x(1,t) = 1   and   u(x,0) = x²/2
+	//    ∂t     ∂x²
+	//
+	//  the solution u = t + x²/2 is reproduced exactly by the FDM and by all methods
+	g, _ := molGrid1d(11)
+	op := NewFdmLaplacian(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, g, nil)
+	op.AddBc(true, 10, 0, dbf.New("lin", []*dbf.P{{N: "m", V: 1}}))
+	op.AddBc(false, 11, 1, nil)
+
+	// run
+	tf := 0.1
+	for _, c := range []struct {
+		method string
+		dt     float64
+	}{
+		{"radau5", 0},
+		{"bweuler", 0.01},
+		{"rk4", 0.002},
+	} {
+		heat := NewFdmHeat(op, 1, c.method)
+		u := make([]float64, g.Size())
+		for I := range u {
+			x := g.Node(I)
+			u[I] = x[0] * x[0] / 2
+		}
+		nsnap := 0
+		heat.Solve(u, tf, c.dt, 0, func(idx int, t float64, u, v []float64) (stop bool) {
+			for I := 0; I < g.Size(); I++ {
+				x := g.Node(I)
+				chk.Float64(tst, "u", 1e-9, u[I], t+x[0]*x[0]/2)
+			}
+			nsnap++
+			return
+		})
+		io.Pforan("%-8s nsteps = %d  nsnapshots = %d\n", c.method, heat.Stat.Nsteps, nsnap)
+		if c.dt > 0 {
+			chk.Int(tst, "number of snapshots", nsnap, int(math.Round(tf/c.dt))+1)
+		}
+		for I := 0; I < g.Size(); I++ {
+			x := g.Node(I)
+			chk.Float64(tst, "u(tf)", 1e-9, u[I], tf+x[0]*x[0]/2)
+		}
+	}
+
+	// stop simulation: the final values correspond to the last snapshot
+	for _, c := range []struct {
+		method string
+		dt     float64
+	}{
+		{"bweuler", 0.01},
+		{"radau5", 0},
+	} {
+		heat := NewFdmHeat(op, 1, c.method)
+		u := make([]float64, g.Size())
+		for I := range u {
+			x := g.Node(I)
+			u[I] = x[0] * x[0] / 2
+		}
+		var tlast float64
+		heat.Solve(u, tf, c.dt, 0.05, func(idx int, t float64, u, v []float64) (stop bool) {
+			tlast = t
+			return idx == 1
+		})
+		chk.Float64(tst, "tlast", 1e-15, tlast, 0.05)
+		for I := 0; I < g.Size(); I++ {
+			x := g.Node(I)
+			chk.Float64(tst, "u(tlast)", 1e-9, u[I], tlast+x[0]*x[0]/2)
+		}
+	}
+}
+
+func TestMol03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Mol03. wave equation: vibrating string")
+
+	// solve problem
+	//      ∂²u      ∂u     ∂²u
+	//    m ———— + d ——— =  ———     with   u(0,t) = u(1,t) = 0,  u(x,0) = sin(π x)  and  v(x,0) = 0
+	//      ∂t²      ∂t     ∂x²
+	//
+	//  the semi-discrete solution is u = A(t) sin(π x) with (ω² = λ/m - d²/4m², ζ = d/2m)
+	//    A(t) = exp(-ζ t) (cos(ω t) + ζ/ω sin(ω t))   and   λ = 4 sin²(π h / 2) / h²
+	g, h := molGrid1d(21)
+	λ := 4 * math.Pow(math.Sin(math.Pi*h/2), 2) / (h * h)
+	op := NewFdmLaplacian(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, g, nil)
+	op.AddBc(true, 10, 0, nil)
+	op.AddBc(true, 11, 0, nil)
+
+	// run
+	tf := 1.0
+	for _, c := range []struct {
+		method string
+		m, d   float64
+		tol    float64
+	}{
+		{"dopri8", 1, 0, 1e-7},
+		{"radau5", 1, 0, 1e-6},
+		{"radau5", 0.5, 0.8, 1e-6},
+	} {
+		ζ := c.d / (2 * c.m)
+		ω := math.Sqrt(λ/c.m - ζ*ζ)
+		amp := func(t float64) float64 { return math.Exp(-ζ*t) * (math.Cos(ω*t) + ζ/ω*math.Sin(ω*t)) }
+		vel := func(t float64) float64 { return -math.Exp(-ζ*t) * (ω + ζ*ζ/ω) * math.Sin(ω*t) }
+		wave := NewFdmWave(op, c.m, c.d, c.method)
+		wave.Atol, wave.Rtol = 1e-10, 1e-10
+		u := make([]float64, g.Size())
+		v := make([]float64, g.Size())
+		for I := range u {
+			u[I] = math.Sin(math.Pi * g.Node(I)[0])
+		}
+		nsnap := 0
+		wave.Solve(u, v, tf, 0, 0.25, func(idx int, t float64, u, v []float64) (stop bool) {
+			for I := 0; I < g.Size(); I++ {
+				s := math.Sin(math.Pi * g.Node(I)[0])
+				chk.Float64(tst, "u", c.tol, u[I], amp(t)*s)
+				chk.Float64(tst, "v", 10*c.tol, v[I], vel(t)*s)
+			}
+			nsnap++
+			return
+		})
+		io.Pforan("%-8s m = %g  d = %g  nsteps = %d\n", c.method, c.m, c.d, wave.Stat.Nsteps)
+		chk.Int(tst, "number of snapshots", nsnap, 5)
+		for I := 0; I < g.Size(); I++ {
+			s := math.Sin(math.Pi * g.Node(I)[0])
+			chk.Float64(tst, "u(tf)", c.tol, u[I], amp(tf)*s)
+			chk.Float64(tst, "v(tf)", 10*c.tol, v[I], vel(tf)*s)
+		}
+	}
+}
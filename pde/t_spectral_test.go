@@ -0,0 +1,235 @@
+// Copyright 2016 The Gosl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pde
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cpmech/gosl/chk"
+	"github.com/cpmech/gosl/fun/dbf"
+	"github.com/cpmech/gosl/io"
+	"github.com/cpmech/gosl/la"
+)
+
+func TestSpectral01(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral01. basis: differentiation matrices")
+
+	// Chebyshev: polynomial of degree 4 on [1,3]
+	b := NewSpcBasis("cheb", 6, 1, 3)
+	chk.Float64(tst, "x[0]", 1e-15, b.X[0], 1)
+	chk.Float64(tst, "x[N]", 1e-15, b.X[6], 3)
+	u := la.NewVector(7)
+	du := la.NewVector(7)
+	ddu := la.NewVector(7)
+	for i, x := range b.X {
+		u[i] = x*x*x*x - 2*x
+	}
+	la.MatVecMul(du, 1, b.D1, u)
+	la.MatVecMul(ddu, 1, b.D2, u)
+	for i, x := range b.X {
+		chk.Float64(tst, "du/dx", 1e-12, du[i], 4*x*x*x-2)
+		chk.Float64(tst, "d²u/dx²", 1e-11, ddu[i], 12*x*x)
+	}
+
+	// Fourier: periodic function on [-1,1)
+	b = NewSpcBasis("fourier", 16, -1, 1)
+	chk.Float64(tst, "x[0]", 1e-15, b.X[0], -1)
+	chk.Float64(tst, "x[1]", 1e-15, b.X[1], -1+2.0/16)
+	u = la.NewVector(16)
+	du = la.NewVector(16)
+	ddu = la.NewVector(16)
+	for i, x := range b.X {
+		u[i] = math.Sin(math.Pi*x) + math.Cos(3*math.Pi*x)
+	}
+	la.MatVecMul(du, 1, b.D1, u)
+	la.MatVecMul(ddu, 1, b.D2, u)
+	π := math.Pi
+	for i, x := range b.X {
+		chk.Float64(tst, "du/dx", 1e-12, du[i], π*math.Cos(π*x)-3*π*math.Sin(3*π*x))
+		chk.Float64(tst, "d²u/dx²", 1e-11, ddu[i], -π*π*math.Sin(π*x)-9*π*π*math.Cos(3*π*x))
+	}
+}
+
+func TestSpectral02(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral02. 1D Helmholtz with Dirichlet and Neumann conditions")
+
+	// solve problem
+	//    u'' + 4 u = s    with   u = exp(x) sin(3 x)  on [0,1]
+	//    s = exp(x) (-8 sin(3 x) + 6 cos(3 x)) + 4 u
+	//    u(0) = 0   and   u'(1) = exp(1) (sin(3) + 3 cos(3))
+	uana := func(x float64) float64 { return math.Exp(x) * math.Sin(3*x) }
+	source := func(x la.Vector, t float64) float64 {
+		return math.Exp(x[0])*(-8*math.Sin(3*x[0])+6*math.Cos(3*x[0])) + 4*uana(x[0])
+	}
+	var errs []float64
+	for _, N := range []int{8, 16, 24} {
+		s := NewSpectral(dbf.Params{{N: "kx", V: 1}, {N: "a", V: 4}}, []*SpcBasis{NewSpcBasis("cheb", N, 0, 1)}, source)
+		s.AddBc(true, 10, 0, nil)
+		s.AddBc(false, 11, math.E*(math.Sin(3)+3*math.Cos(3)), nil)
+		u := s.SolveSteady(nil)
+		maxerr := 0.0
+		for I := 0; I < s.Size(); I++ {
+			maxerr = math.Max(maxerr, math.Abs(u[I]-uana(s.Node(I)[0])))
+		}
+		io.Pf("N = %2d  maxerr = %.6e\n", N, maxerr)
+		errs = append(errs, maxerr)
+	}
+	if errs[1] > 1e-6 || errs[2] > 1e-11 {
+		tst.Errorf("spectral convergence failed: errors = %v\n", errs)
+	}
+}
+
+func TestSpectral03(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral03. 2D Poisson with periodic and bounded directions")
+
+	// solve problem
+	//    ∇²u = s    with   u = sin(x) (y³ + 1)  on [0,2π) × [-1,1]  (periodic along x)
+	//    s = sin(x) (6 y - y³ - 1)
+	//    u(x,-1) = 0   and   ∂u/∂y(x,1) = 3 sin(x)
+	uana := func(x []float64) float64 { return math.Sin(x[0]) * (x[1]*x[1]*x[1] + 1) }
+	source := func(x la.Vector, t float64) float64 {
+		return math.Sin(x[0]) * (6*x[1] - x[1]*x[1]*x[1] - 1)
+	}
+	basis := []*SpcBasis{NewSpcBasis("fourier", 8, 0, 2*math.Pi), NewSpcBasis("cheb", 4, -1, 1)}
+	s := NewSpectral(dbf.Params{{N: "kx", V: 1}, {N: "ky", V: 1}}, basis, source)
+	s.AddBc(true, 20, 0, nil)
+	s.AddBc(false, 21, 0, dbf.New("expr", []*dbf.P{{N: "expr", Extra: "3*sin(x[0])"}}))
+	u := s.SolveSteady(nil)
+	chk.Int(tst, "size", s.Size(), 8*5)
+	for I := 0; I < s.Size(); I++ {
+		chk.Float64(tst, "u", 1e-13, u[I], uana(s.Node(I)))
+	}
+
+	// solve problem
+	//    ∇⋅(K ∇u) + a u = s    with   u = cos(π x / 2) exp(y)  on [-1,1] × [0,1]
+	//    s = (-kx π²/4 + ky + a) u
+	//    u = 0 @ x = ±1, u(x,0) = cos(π x / 2) and u(x,1) = e cos(π x / 2)
+	kx, ky, a := 2.0, 0.5, -1.0
+	uana = func(x []float64) float64 { return math.Cos(math.Pi*x[0]/2) * math.Exp(x[1]) }
+	source = func(x la.Vector, t float64) float64 { return (-kx*math.Pi*math.Pi/4 + ky + a) * uana(x) }
+	ebc := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "cos(pi*x[0]/2)*exp(x[1])"}})
+	var errs []float64
+	for _, N := range []int{6, 12} {
+		basis = []*SpcBasis{NewSpcBasis("cheb", N, -1, 1), NewSpcBasis("cheb", N, 0, 1)}
+		s = NewSpectral(dbf.Params{{N: "kx", V: kx}, {N: "ky", V: ky}, {N: "a", V: a}}, basis, source)
+		for _, tag := range []int{10, 11, 20, 21} {
+			s.AddBc(true, tag, 0, ebc)
+		}
+		u = s.SolveSteady(nil)
+		maxerr := 0.0
+		for I := 0; I < s.Size(); I++ {
+			maxerr = math.Max(maxerr, math.Abs(u[I]-uana(s.Node(I))))
+		}
+		io.Pf("N = %2d  maxerr = %.6e\n", N, maxerr)
+		errs = append(errs, maxerr)
+	}
+	if errs[0] > 1e-4 || errs[1] > 1e-11 {
+		tst.Errorf("spectral convergence failed: errors = %v\n", errs)
+	}
+}
+
+func TestSpectral04(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral04. transient advection-diffusion")
+
+	// solve problem (periodic)
+	//    ∂u     ∂u     ∂²u
+	//    ——— + v —— = k ———     with   u(x,0) = sin(x)   ⇒   u = exp(-k t) sin(x - v t)
+	//    ∂t     ∂x     ∂x²
+	k, v := 0.1, 1.0
+	uana := func(t, x float64) float64 { return math.Exp(-k*t) * math.Sin(x-v*t) }
+	s := NewSpectral(dbf.Params{{N: "kx", V: k}, {N: "vx", V: v}}, []*SpcBasis{NewSpcBasis("fourier", 16, 0, 2*math.Pi)}, nil)
+	s.Method = "dopri5"
+	s.Atol, s.Rtol = 1e-10, 1e-10
+	u := make([]float64, s.Size())
+	for I := range u {
+		u[I] = uana(0, s.Node(I)[0])
+	}
+	nsnap := 0
+	s.Solve(u, 2, 0, 0.5, func(idx int, t float64, u, v []float64) (stop bool) {
+		for I := range u {
+			chk.Float64(tst, "u", 1e-8, u[I], uana(t, s.Node(I)[0]))
+		}
+		nsnap++
+		return
+	})
+	io.Pforan("dopri5: nsteps = %d\n", s.Stat.Nsteps)
+	chk.Int(tst, "number of snapshots", nsnap, 5)
+
+	// solve problem (bounded)
+	//    ∂u     ∂²u
+	//    ——— =  ———     with   u(0,t) = 0,  ∂u/∂x(1,t) = 0   and   u(x,0) = sin(π x / 2)
+	//    ∂t     ∂x²
+	//
+	//  the solution is u = exp(-π² t / 4) sin(π x / 2)
+	uana = func(t, x float64) float64 { return math.Exp(-math.Pi*math.Pi*t/4) * math.Sin(math.Pi*x/2) }
+	s = NewSpectral(dbf.Params{{N: "kx", V: 1}}, []*SpcBasis{NewSpcBasis("cheb", 16, 0, 1)}, nil)
+	s.AddBc(true, 10, 0, nil)
+	s.Atol, s.Rtol = 1e-10, 1e-10
+	u = make([]float64, s.Size())
+	for I := range u {
+		u[I] = uana(0, s.Node(I)[0])
+	}
+	s.Solve(u, 0.5, 0, 0, nil)
+	io.Pforan("radau5: nsteps = %d\n", s.Stat.Nsteps)
+	for I := range u {
+		chk.Float64(tst, "u(tf)", 1e-8, u[I], uana(0.5, s.Node(I)[0]))
+	}
+}
+
+func TestSpectral05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("Spectral05. Burgers equation")
+
+	// solve steady problem
+	//    ν u'' = u u'    with   u = -tanh(x / 2ν)   on [-1,1]
+	ν := 0.2
+	uana := func(x float64) float64 { return -math.Tanh(x / (2 * ν)) }
+	s := NewSpectral(dbf.Params{{N: "kx", V: ν}, {N: "b", V: 1}}, []*SpcBasis{NewSpcBasis("cheb", 32, -1, 1)}, nil)
+	s.AddBc(true, 10, uana(-1), nil)
+	s.AddBc(true, 11, uana(1), nil)
+	u := make([]float64, s.Size())
+	for I := range u {
+		u[I] = -s.Node(I)[0] // initial guess
+	}
+	u = s.SolveSteady(u)
+	for I := range u {
+		chk.Float64(tst, "steady u", 1e-9, u[I], uana(s.Node(I)[0]))
+	}
+
+	// solve transient problem (travelling wave)
+	//    ∂u     ∂u     ∂²u
+	//    ——— + u —— = ν ———     with   u = c - tanh((x - c t) / 2ν)
+	//    ∂t     ∂x     ∂x²
+	c := 0.5
+	wave := func(t, x float64) float64 { return c - math.Tanh((x-c*t)/(2*ν)) }
+	ebc := dbf.New("expr", []*dbf.P{{N: "expr", Extra: "c - tanh((x[0] - c*t)/(2*nu))"}, {N: "c", V: c}, {N: "nu", V: ν}})
+	s = NewSpectral(dbf.Params{{N: "kx", V: ν}, {N: "b", V: 1}}, []*SpcBasis{NewSpcBasis("cheb", 32, -1, 1)}, nil)
+	s.AddBc(true, 10, 0, ebc)
+	s.AddBc(true, 11, 0, ebc)
+	s.Atol, s.Rtol = 1e-9, 1e-9
+	for I := range u {
+		u[I] = wave(0, s.Node(I)[0])
+	}
+	nsnap := 0
+	s.Solve(u, 1, 0, 0.25, func(idx int, t float64, u, v []float64) (stop bool) {
+		for I := range u {
+			chk.Float64(tst, "u", 1e-7, u[I], wave(t, s.Node(I)[0]))
+		}
+		nsnap++
+		return
+	})
+	io.Pforan("radau5: nsteps = %d\n", s.Stat.Nsteps)
+	chk.Int(tst, "number of snapshots", nsnap, 5)
+}
@@ -7,6 +7,7 @@ package utl
 import (
 	"math"
 	"math/rand"
+	"sort"
 
 	"github.com/cpmech/gosl/chk"
 )
@@ -126,3 +127,94 @@ func ParetoFront(Ovs [][]float64) (front []int) {
 	}
 	return
 }
+
+// ParetoCrowding computes the crowding distances of the points in a front
+//  Input:
+//   Ovs   -- [nsamples][ndim] objective values
+//   front -- indices of points in the front (e.g. from ParetoFront)
+//  Output:
+//   dist -- [len(front)] crowding distances. The extreme points have dist = +∞
+//  Note: the distance along each objective is normalised by the range of values in the front
+func ParetoCrowding(Ovs [][]float64, front []int) (dist []float64) {
+	n := len(front)
+	dist = make([]float64, n)
+	if n < 3 {
+		for k := 0; k < n; k++ {
+			dist[k] = math.Inf(+1)
+		}
+		return
+	}
+	order := make([]int, n)
+	for m := 0; m < len(Ovs[front[0]]); m++ {
+		for k := 0; k < n; k++ {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool { return Ovs[front[order[a]]][m] < Ovs[front[order[b]]][m] })
+		fmin := Ovs[front[order[0]]][m]
+		fmax := Ovs[front[order[n-1]]][m]
+		dist[order[0]] = math.Inf(+1)
+		dist[order[n-1]] = math.Inf(+1)
+		if fmax-fmin < 1e-15 {
+			continue
+		}
+		for k := 1; k < n-1; k++ {
+			dist[order[k]] += (Ovs[front[order[k+1]]][m] - Ovs[front[order[k-1]]][m]) / (fmax - fmin)
+		}
+	}
+	return
+}
+
+// ParetoHypervolume computes the hypervolume (Lebesgue measure) of the region dominated by a set
+// of points and bounded by a reference point (minimisation)
+//  Input:
+//   Ovs -- [nsamples][ndim] objective values (e.g. the Pareto front)
+//   ref -- [ndim] reference point. Points that do not dominate ref are ignored
+//  Output:
+//   hv -- hypervolume
+//  Note: the "hypervolume by slicing objectives" algorithm is used; thus this function is
+//        only efficient for small sets and few objectives
+func ParetoHypervolume(Ovs [][]float64, ref []float64) (hv float64) {
+	var pts [][]float64
+	for _, v := range Ovs {
+		chk.IntAssert(len(v), len(ref))
+		inside := true
+		for m := 0; m < len(ref); m++ {
+			if v[m] >= ref[m] {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			pts = append(pts, v)
+		}
+	}
+	return hypervolumeSlice(pts, ref, len(ref))
+}
+
+// hypervolumeSlice computes the hypervolume using the first ndim objectives
+func hypervolumeSlice(pts [][]float64, ref []float64, ndim int) (hv float64) {
+	if len(pts) == 0 {
+		return 0
+	}
+	m := ndim - 1
+	if m == 0 {
+		xmin := ref[0]
+		for _, p := range pts {
+			xmin = math.Min(xmin, p[0])
+		}
+		return ref[0] - xmin
+	}
+	sorted := make([][]float64, len(pts))
+	copy(sorted, pts)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a][m] < sorted[b][m] })
+	for k := 0; k < len(sorted); k++ {
+		next := ref[m]
+		if k < len(sorted)-1 {
+			next = sorted[k+1][m]
+		}
+		if next > sorted[k][m] {
+			hv += hypervolumeSlice(sorted[:k+1], ref, m) * (next - sorted[k][m])
+		}
+	}
+	return
+}
@@ -6,6 +6,7 @@ package utl
 
 import (
 	"bytes"
+	"math"
 	"math/rand"
 	"testing"
 	"time"
@@ -303,3 +304,65 @@ func Test_pareto04(tst *testing.T) {
 		io.WriteFileVD("/tmp/gosl", "test_pareto04.py", &buf)
 	}
 }
+
+func Test_pareto05(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("pareto05. crowding distance")
+
+	ovs := [][]float64{
+		{0.0, 4.0}, {1.0, 2.0}, {3.0, 1.0}, {4.0, 0.0}, {5.0, 5.0},
+	}
+	front := []int{0, 1, 2, 3}
+	dist := ParetoCrowding(ovs, front)
+	io.Pforan("dist = %v\n", dist)
+	chk.Array(tst, "dist[1:3]", 1e-15, dist[1:3], []float64{3.0/4.0 + 3.0/4.0, 3.0/4.0 + 2.0/4.0})
+	if !math.IsInf(dist[0], +1) || !math.IsInf(dist[3], +1) {
+		tst.Errorf("extreme points must have infinite crowding distance\n")
+	}
+
+	dist = ParetoCrowding(ovs, []int{1, 2})
+	if !math.IsInf(dist[0], +1) || !math.IsInf(dist[1], +1) {
+		tst.Errorf("crowding distances of fronts with two points must be infinite\n")
+	}
+}
+
+func Test_pareto06(tst *testing.T) {
+
+	//verbose()
+	chk.PrintTitle("pareto06. hypervolume")
+
+	// 2D: staircase
+	ovs := [][]float64{{1, 3}, {2, 2}, {3, 1}, {3.5, 3.5}, {5, 0.5}}
+	hv := ParetoHypervolume(ovs, []float64{4, 4})
+	io.Pforan("hv2d = %v\n", hv)
+	chk.Float64(tst, "hv2d", 1e-15, hv, 3+2+1)
+
+	// 3D: single point and two points
+	hv = ParetoHypervolume([][]float64{{0, 0, 0}}, []float64{1, 2, 3})
+	chk.Float64(tst, "hv3d: single", 1e-15, hv, 6)
+	hv = ParetoHypervolume([][]float64{{0, 1, 0}, {1, 0, 1}}, []float64{2, 2, 2})
+	io.Pforan("hv3d = %v\n", hv)
+	chk.Float64(tst, "hv3d: two", 1e-15, hv, 4+2-1)
+
+	// 3D: compare with Monte Carlo
+	rand.Seed(1234)
+	pts := make([][]float64, 6)
+	for i := 0; i < len(pts); i++ {
+		pts[i] = []float64{rand.Float64(), rand.Float64(), rand.Float64()}
+	}
+	ref := []float64{1, 1, 1}
+	hv = ParetoHypervolume(pts, ref)
+	nmc, ndom := 200000, 0
+	for k := 0; k < nmc; k++ {
+		x := []float64{rand.Float64(), rand.Float64(), rand.Float64()}
+		for _, p := range pts {
+			if p[0] <= x[0] && p[1] <= x[1] && p[2] <= x[2] {
+				ndom++
+				break
+			}
+		}
+	}
+	io.Pforan("hv3d = %v  Monte Carlo = %v\n", hv, float64(ndom)/float64(nmc))
+	chk.Float64(tst, "hv3d: Monte Carlo", 5e-3, hv, float64(ndom)/float64(nmc))
+}